@@ -0,0 +1,20 @@
+package apierror
+
+import (
+	"net/http"
+)
+
+const (
+	// TypeArtistNameConflict indicates that the name or an alias of an artist is already used by another artist.
+	TypeArtistNameConflict = ProblemTypeDomain + "artist-name-conflict"
+)
+
+// ArtistNameConflict generates an error indicating that the name or an alias of an artist is already in use.
+func ArtistNameConflict() *ProblemDetails {
+	return &ProblemDetails{
+		Type:   TypeArtistNameConflict,
+		Title:  "Artist Name Conflict",
+		Status: http.StatusConflict,
+		Detail: "The name or an alias is already used by another artist. Merge the artists instead.",
+	}
+}
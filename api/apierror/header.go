@@ -0,0 +1,34 @@
+package apierror
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	// TypeHeaderNameConflict indicates that the name or an alias of a custom header is already used by another header.
+	TypeHeaderNameConflict = ProblemTypeDomain + "header-name-conflict"
+)
+
+// HeaderNameConflict generates an error indicating that the name or an alias of a custom header is already in use.
+func HeaderNameConflict() *ProblemDetails {
+	return &ProblemDetails{
+		Type:   TypeHeaderNameConflict,
+		Title:  "Header Name Conflict",
+		Status: http.StatusConflict,
+		Detail: "The name or an alias is already used by another header.",
+	}
+}
+
+// pointerEscaper escapes reference tokens of JSON pointers as defined in RFC 6901.
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// InvalidCustomTags generates a validation error for custom tags of a song that violate the registry of custom headers.
+// problems maps the keys of invalid custom tags to a description of the respective problem.
+func InvalidCustomTags(problems map[string]string) *ProblemDetails {
+	errs := make(map[string]string, len(problems))
+	for key, msg := range problems {
+		errs["/extra/"+pointerEscaper.Replace(key)] = msg
+	}
+	return ValidationError("Some custom tags do not conform to the registry of custom headers.", errs)
+}
@@ -15,6 +15,15 @@ const (
 
 	// TypeUnsupportedMediaType indicates that the Content-Type header was valid but the supplied media type is not allowed.
 	TypeUnsupportedMediaType = ProblemTypeDomain + "unsupported-media-type"
+
+	// TypePreconditionFailed indicates that the If-Match precondition of a request did not match the current resource.
+	TypePreconditionFailed = ProblemTypeDomain + "precondition-failed"
+
+	// TypePreconditionRequired indicates that a conditional request is required but no precondition was specified.
+	TypePreconditionRequired = ProblemTypeDomain + "precondition-required"
+
+	// TypePermissionDenied indicates that the user making the request is not allowed to perform the requested action.
+	TypePermissionDenied = ProblemTypeDomain + "permission-denied"
 )
 
 // These errors are ProblemDetails representations of common HTTP error codes.
@@ -25,6 +34,7 @@ var (
 	ErrNotFound             = HTTPStatus(http.StatusNotFound)
 	ErrMethodNotAllowed     = HTTPStatus(http.StatusMethodNotAllowed)
 	ErrNotAcceptable        = HTTPStatus(http.StatusNotAcceptable)
+	ErrContentTooLarge      = HTTPStatus(http.StatusRequestEntityTooLarge)
 	ErrUnprocessableEntity  = HTTPStatus(http.StatusUnprocessableEntity)
 	ErrUnsupportedMediaType = HTTPStatus(http.StatusUnsupportedMediaType)
 	ErrInternalServerError  = HTTPStatus(http.StatusInternalServerError)
@@ -37,6 +47,14 @@ var (
 		Status: http.StatusNotFound,
 		Detail: "This API endpoint does not exist.",
 	}
+
+	// ErrPreconditionRequired generates an error indicating that the request must include an If-Match header.
+	ErrPreconditionRequired = &ProblemDetails{
+		Type:   TypePreconditionRequired,
+		Title:  "Precondition Required",
+		Status: http.StatusPreconditionRequired,
+		Detail: "This request must be made conditional using the If-Match header.",
+	}
 )
 
 // MissingContentType generates an error indicating that no content type was specified in the request.
@@ -65,6 +83,22 @@ func UnsupportedMediaType(allowed ...mediatype.MediaType) *ProblemDetails {
 	}
 }
 
+// PreconditionFailed generates an error indicating that the If-Match header did not match the current resource.
+// The current entity tag of the resource is included as an extra field.
+// If the resource does not exist, etag should be empty.
+func PreconditionFailed(etag string) *ProblemDetails {
+	err := &ProblemDetails{
+		Type:   TypePreconditionFailed,
+		Title:  "Precondition Failed",
+		Status: http.StatusPreconditionFailed,
+		Detail: "The resource has been modified since it was last fetched.",
+	}
+	if etag != "" {
+		err.Fields = map[string]any{"etag": etag}
+	}
+	return err
+}
+
 // ValidationError generates an error indicating that the request payload did not conform to the expected schema.
 func ValidationError(message string, errors map[string]string) *ProblemDetails {
 	err := &ProblemDetails{
@@ -86,6 +120,16 @@ func ValidationError(message string, errors map[string]string) *ProblemDetails {
 	return err
 }
 
+// PermissionDenied generates a 403 Forbidden error with the specified message.
+func PermissionDenied(message string) *ProblemDetails {
+	return &ProblemDetails{
+		Type:   TypePermissionDenied,
+		Title:  "Permission Denied",
+		Status: http.StatusForbidden,
+		Detail: message,
+	}
+}
+
 // BadRequest generates an 400 Bad Request error with the specified message.
 func BadRequest(message string) *ProblemDetails {
 	p := HTTPStatus(http.StatusBadRequest)
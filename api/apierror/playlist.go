@@ -0,0 +1,20 @@
+package apierror
+
+import (
+	"net/http"
+)
+
+const (
+	// TypeInvalidUPL indicates that the UltraStar playlist data could not be parsed.
+	TypeInvalidUPL = ProblemTypeDomain + "invalid-upl"
+)
+
+// InvalidUPL generates an error indicating that the UltraStar playlist in the request could not be parsed.
+func InvalidUPL(err error) *ProblemDetails {
+	return &ProblemDetails{
+		Type:   TypeInvalidUPL,
+		Title:  "Invalid UltraStar playlist format",
+		Status: http.StatusBadRequest,
+		Detail: err.Error(),
+	}
+}
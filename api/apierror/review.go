@@ -0,0 +1,26 @@
+package apierror
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+const (
+	// TypeInvalidReviewTransition indicates that the review state of a song cannot be changed to the requested state.
+	TypeInvalidReviewTransition = ProblemTypeDomain + "invalid-review-transition"
+)
+
+// InvalidReviewTransition generates an error indicating that the review state of song cannot be changed to state.
+func InvalidReviewTransition(song model.Song, state model.ReviewState) *ProblemDetails {
+	return &ProblemDetails{
+		Type:   TypeInvalidReviewTransition,
+		Title:  "Invalid Review Transition",
+		Status: http.StatusConflict,
+		Detail: fmt.Sprintf("The review state cannot be changed from %q to %q.", song.ReviewState, state),
+		Fields: map[string]any{
+			"uuid": song.UUID.String(),
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package apierror
+
+import (
+	"net/http"
+)
+
+const (
+	// TypeInvalidUSDXDatabase indicates that the request did not contain a valid UltraStar Deluxe database.
+	TypeInvalidUSDXDatabase = ProblemTypeDomain + "invalid-usdx-database"
+)
+
+// InvalidUSDXDatabase generates an error indicating that the database in the request could not be read.
+func InvalidUSDXDatabase(err error) *ProblemDetails {
+	return &ProblemDetails{
+		Type:   TypeInvalidUSDXDatabase,
+		Title:  "Invalid UltraStar Deluxe database",
+		Status: http.StatusBadRequest,
+		Detail: err.Error(),
+	}
+}
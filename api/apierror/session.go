@@ -0,0 +1,58 @@
+package apierror
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// These constants identify known problem types related to party sessions.
+const (
+	// TypeSessionClosed indicates that a song request was rejected because the session does not accept requests.
+	TypeSessionClosed = ProblemTypeDomain + "session-closed"
+
+	// TypeRequestLimitExceeded indicates that a song request was rejected because the guest has too many queued requests.
+	TypeRequestLimitExceeded = ProblemTypeDomain + "request-limit-exceeded"
+
+	// TypeInvalidQueueOrder indicates that a new order of the queue does not contain exactly the queued requests.
+	TypeInvalidQueueOrder = ProblemTypeDomain + "invalid-queue-order"
+)
+
+// SessionClosed generates an error indicating that session does not accept new requests.
+func SessionClosed(session model.Session) *ProblemDetails {
+	return &ProblemDetails{
+		Type:   TypeSessionClosed,
+		Title:  "Session Closed",
+		Status: http.StatusConflict,
+		Detail: "The session does not accept new requests.",
+		Fields: map[string]any{
+			"uuid": session.UUID.String(),
+		},
+	}
+}
+
+// RequestLimitExceeded generates an error indicating that singer has reached the request limit of session.
+func RequestLimitExceeded(session model.Session, singer string) *ProblemDetails {
+	return &ProblemDetails{
+		Type:   TypeRequestLimitExceeded,
+		Title:  "Request Limit Exceeded",
+		Status: http.StatusTooManyRequests,
+		Detail: fmt.Sprintf("%s already has %d songs in the queue.", singer, session.RequestLimit),
+		Fields: map[string]any{
+			"uuid":   session.UUID.String(),
+			"singer": singer,
+			"limit":  session.RequestLimit,
+		},
+	}
+}
+
+// InvalidQueueOrder generates an error indicating that the new order of a queue does not match the queued requests.
+func InvalidQueueOrder() *ProblemDetails {
+	return &ProblemDetails{
+		Type:   TypeInvalidQueueOrder,
+		Title:  "Invalid Queue Order",
+		Status: http.StatusConflict,
+		Detail: "The order must contain every queued request exactly once.",
+	}
+}
@@ -2,6 +2,7 @@ package apierror
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"codello.dev/ultrastar/txt"
@@ -14,11 +15,20 @@ const (
 	// It is usually accompanied by a line number that caused the error.
 	TypeInvalidTXT = ProblemTypeDomain + "invalid-ultrastar-txt"
 
+	// TypeInvalidMIDI indicates that a MIDI file could not be converted into a song.
+	TypeInvalidMIDI = ProblemTypeDomain + "invalid-midi"
+
 	// TypeUploadSongReadonly indicates that the song cannot be modified because it belongs to an upload.
 	TypeUploadSongReadonly = ProblemTypeDomain + "upload-song-readonly"
 
 	// TypeMediaFileNotFound indicates that the requested media file was not found.
 	TypeMediaFileNotFound = ProblemTypeDomain + "song-media-not-found"
+
+	// TypeSongDeleted indicates that the requested song has been moved to the trash.
+	TypeSongDeleted = ProblemTypeDomain + "song-deleted"
+
+	// TypeNoMedleySuggestion indicates that no chorus could be found in the lyrics of a song.
+	TypeNoMedleySuggestion = ProblemTypeDomain + "no-medley-suggestion"
 )
 
 // InvalidUltraStarTXT generates an error indicating that the UltraStar data in the request could not be parsed.
@@ -39,6 +49,16 @@ func InvalidUltraStarTXT(err error) *ProblemDetails {
 	}
 }
 
+// InvalidMIDI generates an error indicating that the MIDI file in the request could not be converted into a song.
+func InvalidMIDI(err error) *ProblemDetails {
+	return &ProblemDetails{
+		Type:   TypeInvalidMIDI,
+		Title:  "Invalid MIDI file",
+		Status: http.StatusBadRequest,
+		Detail: err.Error(),
+	}
+}
+
 // UploadSongReadonly generates an error indicating that song cannot be modified because it belongs to an upload.
 func UploadSongReadonly(song model.Song) *ProblemDetails {
 	return &ProblemDetails{
@@ -67,3 +87,47 @@ func MediaFileNotFound(song model.Song, media string) *ProblemDetails {
 		},
 	}
 }
+
+// SongDeleted generates an error indicating that song has been moved to the trash.
+// The song can be restored until it is purged.
+func SongDeleted(song model.Song) *ProblemDetails {
+	return &ProblemDetails{
+		Type:   TypeSongDeleted,
+		Title:  "Song Deleted",
+		Status: http.StatusGone,
+		Detail: "The song has been moved to the trash.",
+		Fields: map[string]any{
+			"uuid": song.UUID.String(),
+		},
+	}
+}
+
+// InvalidNoteEdits generates an error indicating that the note edits of a request could not be applied.
+// pointer is a JSON pointer to the failing edit or, if the resulting notes are invalid,
+// to the invalid line or note in the notes schema.
+func InvalidNoteEdits(pointer string, message string) *ProblemDetails {
+	return ValidationError("The notes could not be edited.", map[string]string{
+		pointer: message,
+	})
+}
+
+// InvalidTransform generates an error indicating that the transform at index in the request could not be applied.
+func InvalidTransform(index int, message string) *ProblemDetails {
+	return ValidationError("The transforms could not be applied.", map[string]string{
+		fmt.Sprintf("/transforms/%d", index): message,
+	})
+}
+
+// NoMedleySuggestion generates an error indicating that no medley could be suggested for song
+// because its lyrics contain no repeated lines.
+func NoMedleySuggestion(song model.Song) *ProblemDetails {
+	return &ProblemDetails{
+		Type:   TypeNoMedleySuggestion,
+		Title:  "No Medley Suggestion",
+		Status: http.StatusNotFound,
+		Detail: "The lyrics of the song contain no repeated lines.",
+		Fields: map[string]any{
+			"uuid": song.UUID.String(),
+		},
+	}
+}
@@ -0,0 +1,20 @@
+package apierror
+
+import (
+	"net/http"
+)
+
+const (
+	// TypeTagNameConflict indicates that the name of a tag is already used by another tag.
+	TypeTagNameConflict = ProblemTypeDomain + "tag-name-conflict"
+)
+
+// TagNameConflict generates an error indicating that the name of a tag is already in use.
+func TagNameConflict() *ProblemDetails {
+	return &ProblemDetails{
+		Type:   TypeTagNameConflict,
+		Title:  "Tag Name Conflict",
+		Status: http.StatusConflict,
+		Detail: "The name is already used by another tag.",
+	}
+}
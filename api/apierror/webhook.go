@@ -0,0 +1,15 @@
+package apierror
+
+import (
+	"strconv"
+)
+
+// InvalidWebhookEvents generates a validation error for event filters of a webhook that do not match any known event.
+// indexes contains the positions of the invalid filters in the list of events.
+func InvalidWebhookEvents(indexes []int) *ProblemDetails {
+	errs := make(map[string]string, len(indexes))
+	for _, i := range indexes {
+		errs["/events/"+strconv.Itoa(i)] = "unknown event type or category"
+	}
+	return ValidationError("Some events do not match any known event type or category.", errs)
+}
@@ -11,10 +11,24 @@ import (
 	"github.com/Karaoke-Manager/karman/api/apierror"
 	"github.com/Karaoke-Manager/karman/api/middleware"
 	v1 "github.com/Karaoke-Manager/karman/api/v1"
+	"github.com/Karaoke-Manager/karman/core/artist"
+	"github.com/Karaoke-Manager/karman/core/batch"
+	"github.com/Karaoke-Manager/karman/core/duplicate"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/header"
 	"github.com/Karaoke-Manager/karman/core/media"
+	"github.com/Karaoke-Manager/karman/core/playlist"
+	"github.com/Karaoke-Manager/karman/core/review"
+	"github.com/Karaoke-Manager/karman/core/revision"
+	"github.com/Karaoke-Manager/karman/core/score"
+	"github.com/Karaoke-Manager/karman/core/session"
 	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/core/tag"
 	"github.com/Karaoke-Manager/karman/core/upload"
+	"github.com/Karaoke-Manager/karman/core/variant"
+	"github.com/Karaoke-Manager/karman/core/webhook"
 	"github.com/Karaoke-Manager/karman/pkg/render"
+	_ "github.com/Karaoke-Manager/karman/pkg/render/csv"  // CSV encoding for song sheets
 	_ "github.com/Karaoke-Manager/karman/pkg/render/json" // JSON encoding for responses
 )
 
@@ -35,6 +49,9 @@ type Handler struct {
 
 // NewHandler creates a new Handler instance using the specified dependencies.
 // The injected dependencies are passed along to the sub-handlers.
+// strictPreconditions indicates whether mutating requests must include an If-Match header.
+// davDialect is the TXT dialect of songs served via WebDAV at /v1/dav.
+// davMounts maps names to the dialects of additional WebDAV mounts at /v1/dav-<name>.
 // debug indicates whether additional debugging features should be enabled.
 func NewHandler(
 	logger *slog.Logger,
@@ -42,10 +59,28 @@ func NewHandler(
 	hc HealthChecker,
 	songRepo song.Repository,
 	songSvc song.Service,
+	revisionRepo revision.Repository,
+	artistRepo artist.Repository,
+	tagRepo tag.Repository,
+	headerRepo header.Repository,
+	variantRepo variant.Repository,
+	reviewRepo review.Repository,
+	playlistRepo playlist.Repository,
+	sessionRepo session.Repository,
+	scoreRepo score.Repository,
+	duplicateRepo duplicate.Repository,
 	mediaSvc media.Service,
 	mediaStore media.Store,
 	uploadRepo upload.Repository,
 	uploadStore upload.Store,
+	uploadSvc upload.Service,
+	webhookRepo webhook.Repository,
+	batchSvc batch.Service,
+	batchRepo batch.Repository,
+	eventBus event.Bus,
+	strictPreconditions bool,
+	davDialect song.Dialect,
+	davMounts map[string]song.Dialect,
 	debug bool,
 ) *Handler {
 	r := chi.NewRouter()
@@ -54,10 +89,28 @@ func NewHandler(
 		logger,
 		songRepo,
 		songSvc,
+		revisionRepo,
+		artistRepo,
+		tagRepo,
+		headerRepo,
+		variantRepo,
+		reviewRepo,
+		playlistRepo,
+		sessionRepo,
+		scoreRepo,
+		duplicateRepo,
 		mediaSvc,
 		mediaStore,
 		uploadRepo,
 		uploadStore,
+		uploadSvc,
+		webhookRepo,
+		batchSvc,
+		batchRepo,
+		eventBus,
+		strictPreconditions,
+		davDialect,
+		davMounts,
 	)
 	r.Use(middleware.Logger(requestLogger))
 	r.Use(middleware.Recoverer(logger, debug))
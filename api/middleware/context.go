@@ -10,4 +10,6 @@ const (
 	contextKeyPagination contextKey = iota
 	// contextKeyUUID is a context key that stores a UUID value.
 	contextKeyUUID
+	// contextKeyShutdown is a context key that stores a channel that is closed when the server shuts down.
+	contextKeyShutdown
 )
@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"hash/fnv"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ETag returns a strong entity tag for a resource that was last modified at t.
+// The returned value includes the surrounding double quotes and can be used as the value of an ETag header.
+// Two calls with the same time (in microsecond precision) produce the same entity tag.
+func ETag(t time.Time) string {
+	return `"` + strconv.FormatInt(t.UnixMicro(), 36) + `"`
+}
+
+// HasIfMatch reports whether r contains an If-Match header.
+func HasIfMatch(r *http.Request) bool {
+	return len(r.Header.Values("If-Match")) > 0
+}
+
+// VariantETag returns a strong entity tag for a representation of a resource that was last modified at t.
+// Representations of the same resource that are not byte-identical, such as different file formats,
+// must use different variants.
+// The empty variant produces the same entity tag as ETag.
+func VariantETag(t time.Time, variant string) string {
+	if variant == "" {
+		return ETag(t)
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(variant))
+	return `"` + strconv.FormatInt(t.UnixMicro(), 36) + "-" + strconv.FormatUint(uint64(h.Sum32()), 36) + `"`
+}
+
+// IfMatch evaluates the If-Match precondition of r against the current entity tag of the target resource
+// as defined in RFC 9110, Section 13.1.1.
+// An empty etag indicates that there is no current representation of the resource.
+// If r does not contain an If-Match header the precondition is considered to be met.
+//
+// The comparison uses the strong comparison function,
+// i.e. weak entity tags in the If-Match header never match.
+func IfMatch(r *http.Request, etag string) bool {
+	return ifMatch(r, etag != "", func(tag string) bool { return tag == etag })
+}
+
+// IfMatchVersion works like IfMatch for a resource that was last modified at t.
+// In addition to ETag(t), the entity tags of all variants of the resource (see VariantETag) match.
+// This allows clients to make changes conditional on the entity tag of any representation they have fetched.
+func IfMatchVersion(r *http.Request, t time.Time) bool {
+	etag := ETag(t)
+	prefix := strings.TrimSuffix(etag, `"`) + "-"
+	return ifMatch(r, true, func(tag string) bool { return tag == etag || strings.HasPrefix(tag, prefix) })
+}
+
+// ifMatch implements IfMatch and IfMatchVersion.
+// exists indicates whether the resource has a current representation.
+// match reports whether a strong entity tag of the If-Match header matches the resource.
+func ifMatch(r *http.Request, exists bool, match func(tag string) bool) bool {
+	values := r.Header.Values("If-Match")
+	if len(values) == 0 {
+		return true
+	}
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" && exists {
+				return true
+			}
+			if exists && !strings.HasPrefix(tag, "W/") && match(tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestETag(t *testing.T) {
+	now := time.Now()
+	if ETag(now) != ETag(now.Truncate(time.Microsecond)) {
+		t.Errorf("ETag(%s) differs from ETag with microsecond precision, expected equal", now)
+	}
+	if ETag(now) == ETag(now.Add(time.Second)) {
+		t.Errorf("ETag(%s) = ETag(%s), expected different values", now, now.Add(time.Second))
+	}
+	if etag := ETag(now); etag[0] != '"' || etag[len(etag)-1] != '"' {
+		t.Errorf("ETag(%s) = %s, expected a quoted value", now, etag)
+	}
+}
+
+func TestVariantETag(t *testing.T) {
+	now := time.Now()
+	if VariantETag(now, "") != ETag(now) {
+		t.Errorf("VariantETag(%s, \"\") = %s, expected %s", now, VariantETag(now, ""), ETag(now))
+	}
+	if VariantETag(now, "a") == VariantETag(now, "b") {
+		t.Errorf("VariantETag(%s, \"a\") = VariantETag(%s, \"b\"), expected different values", now, now)
+	}
+	if VariantETag(now, "a") == ETag(now) {
+		t.Errorf("VariantETag(%s, \"a\") = ETag(%s), expected different values", now, now)
+	}
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	now := time.Now()
+	cases := map[string]struct {
+		header string
+		match  bool
+	}{
+		"base":     {ETag(now), true},
+		"variant":  {VariantETag(now, "a"), true},
+		"weak":     {"W/" + VariantETag(now, "a"), false},
+		"modified": {VariantETag(now.Add(time.Second), "a"), false},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/", nil)
+			r.Header.Set("If-Match", c.header)
+			if match := IfMatchVersion(r, now); match != c.match {
+				t.Errorf("IfMatchVersion(%q, %s) = %t, expected %t", c.header, now, match, c.match)
+			}
+		})
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	etag := `"abc"`
+	cases := map[string]struct {
+		header []string
+		etag   string
+		match  bool
+	}{
+		"no header":          {nil, etag, true},
+		"exact":              {[]string{`"abc"`}, etag, true},
+		"mismatch":           {[]string{`"def"`}, etag, false},
+		"list":               {[]string{`"def", "abc"`}, etag, true},
+		"multiple headers":   {[]string{`"def"`, `"abc"`}, etag, true},
+		"weak":               {[]string{`W/"abc"`}, etag, false},
+		"wildcard":           {[]string{"*"}, etag, true},
+		"wildcard (missing)": {[]string{"*"}, "", false},
+		"missing":            {[]string{`"abc"`}, "", false},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPut, "/", nil)
+			for _, v := range c.header {
+				r.Header.Add("If-Match", v)
+			}
+			if HasIfMatch(r) != (c.header != nil) {
+				t.Errorf("HasIfMatch(r) = %t, expected %t", HasIfMatch(r), c.header != nil)
+			}
+			if match := IfMatch(r, c.etag); match != c.match {
+				t.Errorf("IfMatch(%q, %q) = %t, expected %t", c.header, c.etag, match, c.match)
+			}
+		})
+	}
+}
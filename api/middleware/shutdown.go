@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	"context"
+)
+
+// SetShutdown sets done in ctx.
+// done should be closed when the server starts to shut down.
+// The value is retrievable later via GetShutdown.
+//
+// The channel is usually set in the base context of an http.Server,
+// so that long-lived handlers such as event streams can end before the server waits for open requests.
+func SetShutdown(ctx context.Context, done <-chan struct{}) context.Context {
+	return context.WithValue(ctx, contextKeyShutdown, done)
+}
+
+// GetShutdown returns the channel that is closed when the server shuts down.
+// If ctx does not contain such a channel, nil is returned.
+// Receiving from a nil channel blocks forever, so the result can always be used in a select statement.
+func GetShutdown(ctx context.Context) <-chan struct{} {
+	done, _ := ctx.Value(contextKeyShutdown).(<-chan struct{})
+	return done
+}
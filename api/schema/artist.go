@@ -0,0 +1,75 @@
+package schema
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// ArtistRW is the main schema for working with artists.
+// All fields in ArtistRW are readable and writeable fields.
+type ArtistRW struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+// Artist extends ArtistRW with additional read-only fields used in API responses.
+type Artist struct {
+	render.NopRenderer
+	ArtistRW
+	UUID uuid.UUID `json:"uuid"`
+
+	// SongCount is the number of songs in the library that credit the artist.
+	SongCount int64 `json:"songCount"`
+}
+
+// FromArtist converts m into a schema instance representing the current state of m.
+func FromArtist(m model.Artist) Artist {
+	aliases := m.Aliases
+	if aliases == nil {
+		aliases = make([]string, 0)
+	}
+	return Artist{
+		UUID:      m.UUID,
+		SongCount: m.SongCount,
+		ArtistRW: ArtistRW{
+			Name:    m.Name,
+			Aliases: aliases,
+		},
+	}
+}
+
+// Apply stores the fields of s into the respective fields of m.
+func (s *ArtistRW) Apply(m *model.Artist) {
+	m.Name = s.Name
+	m.Aliases = s.Aliases
+}
+
+// Bind implements the render.Binder interface.
+// Bind makes sure that the artist has a name.
+func (s *ArtistRW) Bind(*http.Request) error {
+	if strings.TrimSpace(s.Name) == "" {
+		return errors.New("the artist name must not be empty")
+	}
+	return nil
+}
+
+// ArtistMerge is the request schema for merging artists.
+type ArtistMerge struct {
+	// Source is the UUID of the artist that is merged into another artist.
+	Source uuid.UUID `json:"source"`
+}
+
+// Bind implements the render.Binder interface.
+// Bind makes sure that a source artist is specified.
+func (s *ArtistMerge) Bind(*http.Request) error {
+	if s.Source == uuid.Nil {
+		return errors.New("the source artist must be specified")
+	}
+	return nil
+}
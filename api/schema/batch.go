@@ -0,0 +1,215 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// BatchFields is the schema for the song fields set by a batch operation.
+// Omitted fields are not changed, an empty string or a year of 0 clears a field.
+type BatchFields struct {
+	Genre    *string `json:"genre,omitempty"`
+	Edition  *string `json:"edition,omitempty"`
+	Language *string `json:"language,omitempty"`
+	Creator  *string `json:"creator,omitempty"`
+	Year     *int    `json:"year,omitempty"`
+}
+
+// BatchOperation is the schema for model.BatchOperation.
+// Which fields are used depends on the value of Op.
+type BatchOperation struct {
+	Op         model.BatchOp     `json:"op"`
+	Fields     *BatchFields      `json:"fields,omitempty"`
+	CustomTags map[string]string `json:"customTags,omitempty"`
+	TagNames   []string          `json:"tagNames,omitempty"`
+	Transforms []Transform       `json:"transforms,omitempty"`
+}
+
+// fromBatchOperation converts op into a schema instance.
+func fromBatchOperation(op model.BatchOperation) BatchOperation {
+	s := BatchOperation{Op: op.Op}
+	switch op.Op {
+	case model.BatchOpSet:
+		s.Fields = &BatchFields{
+			Genre:    op.Fields.Genre,
+			Edition:  op.Fields.Edition,
+			Language: op.Fields.Language,
+			Creator:  op.Fields.Creator,
+			Year:     op.Fields.Year,
+		}
+	case model.BatchOpAddCustomTags:
+		s.CustomTags = op.CustomTags
+	case model.BatchOpRemoveCustomTags, model.BatchOpAddTags, model.BatchOpRemoveTags:
+		s.TagNames = op.TagNames
+	case model.BatchOpTransform:
+		s.Transforms = make([]Transform, 0, len(op.Transforms))
+		for _, text := range op.Transforms {
+			if t, err := song.ParseTransform(text); err == nil {
+				s.Transforms = append(s.Transforms, fromTransform(t))
+			}
+		}
+	}
+	return s
+}
+
+// fromTransform converts t into a schema instance.
+func fromTransform(t song.Transform) Transform {
+	s := Transform{Op: t.Op}
+	switch t.Op {
+	case song.ShiftGap, song.SetVideoGap:
+		value := t.Duration.Milliseconds()
+		s.Value = &value
+	case song.ShiftNotes:
+		value := int64(t.Beats)
+		s.Value = &value
+	}
+	return s
+}
+
+// validate makes sure that s contains the fields required by s.Op.
+// The operation is converted into a model.BatchOperation.
+func (s *BatchOperation) validate() (model.BatchOperation, error) {
+	op := model.BatchOperation{Op: s.Op}
+	switch s.Op {
+	case model.BatchOpSet:
+		if s.Fields == nil || *s.Fields == (BatchFields{}) {
+			return op, errors.New("at least one field must be specified")
+		}
+		op.Fields = model.SongFields{
+			Genre:    s.Fields.Genre,
+			Edition:  s.Fields.Edition,
+			Language: s.Fields.Language,
+			Creator:  s.Fields.Creator,
+			Year:     s.Fields.Year,
+		}
+	case model.BatchOpAddCustomTags:
+		if len(s.CustomTags) == 0 {
+			return op, errors.New("at least one custom tag must be specified")
+		}
+		for name := range s.CustomTags {
+			if name == "" {
+				return op, errors.New("custom tag names must not be empty")
+			}
+		}
+		op.CustomTags = s.CustomTags
+	case model.BatchOpRemoveCustomTags:
+		if len(s.TagNames) == 0 {
+			return op, errors.New("at least one custom tag must be specified")
+		}
+		op.TagNames = s.TagNames
+	case model.BatchOpAddTags, model.BatchOpRemoveTags:
+		if len(s.TagNames) == 0 {
+			return op, errors.New("at least one tag must be specified")
+		}
+		for _, name := range s.TagNames {
+			if strings.TrimSpace(name) == "" {
+				return op, errors.New("tag names must not be empty")
+			}
+		}
+		op.TagNames = s.TagNames
+	case model.BatchOpTransform:
+		if len(s.Transforms) == 0 {
+			return op, errors.New("at least one transform must be specified")
+		}
+		op.Transforms = make([]string, len(s.Transforms))
+		for i, t := range s.Transforms {
+			transform, err := t.toTransform()
+			if err != nil {
+				return op, fmt.Errorf("transform %d: %w", i, err)
+			}
+			op.Transforms[i] = transform.String()
+		}
+	case model.BatchOpDelete, model.BatchOpFix:
+	default:
+		return op, fmt.Errorf("unknown operation %q", s.Op)
+	}
+	return op, nil
+}
+
+// SongBatchRequest is the request schema for creating a song batch.
+// Songs are selected either explicitly by their UUIDs or by a query.
+// The query uses the same syntax as the query string of the GET /v1/songs endpoint.
+type SongBatchRequest struct {
+	Songs     []uuid.UUID    `json:"songs,omitempty"`
+	Query     *string        `json:"query,omitempty"`
+	Operation BatchOperation `json:"operation"`
+	Atomic    bool           `json:"atomic"`
+
+	// operation contains the converted operation after binding.
+	operation model.BatchOperation
+}
+
+// Bind implements the render.Binder interface.
+// Bind makes sure that songs are selected in exactly one way and validates the operation.
+func (s *SongBatchRequest) Bind(*http.Request) error {
+	if s.Query != nil && len(s.Songs) > 0 {
+		return errors.New("songs and query cannot be specified together")
+	}
+	if s.Query == nil && len(s.Songs) == 0 {
+		return errors.New("either songs or query must be specified")
+	}
+	op, err := s.Operation.validate()
+	if err != nil {
+		return fmt.Errorf("operation: %w", err)
+	}
+	s.operation = op
+	return nil
+}
+
+// Batch returns a model.SongBatch for the request.
+// The author of the batch is set to author.
+// This method must only be called after s has been bound successfully.
+func (s *SongBatchRequest) Batch(author string) model.SongBatch {
+	return model.SongBatch{
+		Operation: s.operation,
+		Atomic:    s.Atomic,
+		Author:    author,
+	}
+}
+
+// SongBatch is the response schema for model.SongBatch.
+type SongBatch struct {
+	render.NopRenderer
+	UUID      uuid.UUID        `json:"uuid"`
+	Status    model.BatchState `json:"status"`
+	Operation BatchOperation   `json:"operation"`
+	Atomic    bool             `json:"atomic"`
+
+	SongsTotal     int `json:"songsTotal"`
+	SongsProcessed int `json:"songsProcessed"`
+	Errors         int `json:"errors"`
+}
+
+// FromSongBatch generates a response schema, describing m.
+func FromSongBatch(m model.SongBatch) SongBatch {
+	return SongBatch{
+		UUID:           m.UUID,
+		Status:         m.State,
+		Operation:      fromBatchOperation(m.Operation),
+		Atomic:         m.Atomic,
+		SongsTotal:     m.SongsTotal,
+		SongsProcessed: m.SongsProcessed,
+		Errors:         m.Errors,
+	}
+}
+
+// SongBatchResult is the schema for the result of a batch for a single song.
+type SongBatchResult struct {
+	render.NopRenderer
+	Song uuid.UUID `json:"song"`
+	// Error is omitted if the song was changed successfully.
+	Error string `json:"error,omitempty"`
+}
+
+// FromSongBatchResult converts m into a schema instance.
+func FromSongBatchResult(m model.SongBatchResult) SongBatchResult {
+	return SongBatchResult{Song: m.Song, Error: m.Error}
+}
@@ -0,0 +1,77 @@
+package schema
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// DuplicateCluster is the response schema for a group of duplicate songs.
+type DuplicateCluster struct {
+	render.NopRenderer
+	Songs   []Song                  `json:"songs"`
+	Reasons []model.DuplicateReason `json:"reasons"`
+}
+
+// FromDuplicateCluster converts m into a schema instance.
+// songs must contain the songs of m in the same order.
+func FromDuplicateCluster(m model.DuplicateCluster, songs []model.Song) DuplicateCluster {
+	c := DuplicateCluster{
+		Songs:   make([]Song, len(songs)),
+		Reasons: m.Reasons,
+	}
+	for i, s := range songs {
+		c.Songs[i] = FromSong(s)
+	}
+	return c
+}
+
+// MergeMode determines what happens to duplicates that are merged into another song.
+type MergeMode string
+
+const (
+	// MergeModeTrash moves duplicates to the trash.
+	MergeModeTrash MergeMode = "trash"
+	// MergeModeDelete deletes duplicates permanently.
+	MergeModeDelete MergeMode = "delete"
+)
+
+// DuplicateMerge is the request schema for merging duplicate songs.
+type DuplicateMerge struct {
+	// Keep is the UUID of the song whose TXT data is kept.
+	Keep uuid.UUID `json:"keep"`
+	// Duplicates are the UUIDs of the songs that are removed.
+	Duplicates []uuid.UUID `json:"duplicates"`
+
+	// Audio, Cover, Video and Background select the song whose media file is kept.
+	// If a value is nil, the media file of the kept song is used.
+	Audio      *uuid.UUID `json:"audio"`
+	Cover      *uuid.UUID `json:"cover"`
+	Video      *uuid.UUID `json:"video"`
+	Background *uuid.UUID `json:"background"`
+
+	Mode MergeMode `json:"mode"`
+}
+
+// Bind implements the render.Binder interface.
+// Bind makes sure that the merge includes at least one duplicate and sets the default mode.
+func (m *DuplicateMerge) Bind(*http.Request) error {
+	if m.Keep == uuid.Nil {
+		return errors.New("the song to keep is required")
+	}
+	if len(m.Duplicates) == 0 {
+		return errors.New("at least one duplicate is required")
+	}
+	switch m.Mode {
+	case "":
+		m.Mode = MergeModeTrash
+	case MergeModeTrash, MergeModeDelete:
+	default:
+		return errors.New("the mode must be trash or delete")
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core/header"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// CustomHeaderRW is the main schema for working with custom headers.
+// All fields in CustomHeaderRW are readable and writeable fields.
+type CustomHeaderRW struct {
+	Name    string           `json:"name"`
+	Type    model.HeaderType `json:"type,omitempty"`
+	Pattern string           `json:"pattern,omitempty"`
+	Aliases []string         `json:"aliases"`
+}
+
+// CustomHeader extends CustomHeaderRW with additional read-only fields used in API responses.
+type CustomHeader struct {
+	render.NopRenderer
+	CustomHeaderRW
+	UUID uuid.UUID `json:"uuid"`
+}
+
+// FromCustomHeader converts m into a schema instance representing the current state of m.
+func FromCustomHeader(m model.CustomHeader) CustomHeader {
+	aliases := m.Aliases
+	if aliases == nil {
+		aliases = make([]string, 0)
+	}
+	return CustomHeader{
+		UUID: m.UUID,
+		CustomHeaderRW: CustomHeaderRW{
+			Name:    m.Name,
+			Type:    m.Type,
+			Pattern: m.Pattern,
+			Aliases: aliases,
+		},
+	}
+}
+
+// Apply stores the fields of s into the respective fields of m.
+func (s *CustomHeaderRW) Apply(m *model.CustomHeader) {
+	m.Name = s.Name
+	m.Type = s.Type
+	m.Pattern = s.Pattern
+	m.Aliases = s.Aliases
+}
+
+// Bind implements the render.Binder interface.
+// Bind makes sure that the header has a name, a known type, and a valid pattern.
+func (s *CustomHeaderRW) Bind(*http.Request) error {
+	if strings.TrimPrefix(strings.TrimSpace(s.Name), "#") == "" {
+		return errors.New("the header name must not be empty")
+	}
+	switch s.Type {
+	case "", model.HeaderTypeString, model.HeaderTypeInt, model.HeaderTypeDuration, model.HeaderTypeURL:
+	default:
+		return fmt.Errorf("unknown header type %q", s.Type)
+	}
+	if _, err := regexp.Compile(s.Pattern); err != nil {
+		return fmt.Errorf("invalid pattern: %w", err)
+	}
+	return nil
+}
+
+// CustomTagReport describes a custom tag key used by songs that is unknown or not spelled canonically.
+type CustomTagReport struct {
+	Key   string `json:"key"`
+	Songs int64  `json:"songs"`
+	// Canonical is the canonical spelling of the key, if it refers to a known header.
+	Canonical string `json:"canonical,omitempty"`
+}
+
+// CustomTagsReport is the response of the custom tags report.
+type CustomTagsReport struct {
+	render.NopRenderer
+	Keys []CustomTagReport `json:"keys"`
+}
+
+// FromKeyReports converts reports into a schema instance.
+func FromKeyReports(reports []header.KeyReport) CustomTagsReport {
+	resp := CustomTagsReport{Keys: make([]CustomTagReport, len(reports))}
+	for i, r := range reports {
+		resp.Keys[i] = CustomTagReport{Key: r.Key, Songs: r.Songs, Canonical: r.Canonical}
+	}
+	return resp
+}
@@ -0,0 +1,53 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Syllable is the schema for a single timed syllable of the lyrics.
+type Syllable struct {
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+	Text  string        `json:"text"`
+}
+
+// LyricLine is the schema for a single timed line of the lyrics.
+type LyricLine struct {
+	Start     time.Duration `json:"start"`
+	End       time.Duration `json:"end"`
+	Text      string        `json:"text"`
+	Syllables []Syllable    `json:"syllables"`
+}
+
+// fromLyricLines converts lines into schema instances.
+func fromLyricLines(lines []song.LyricLine) []LyricLine {
+	result := make([]LyricLine, len(lines))
+	for i, l := range lines {
+		result[i] = LyricLine{Start: l.Start, End: l.End, Text: l.Text, Syllables: make([]Syllable, len(l.Syllables))}
+		for j, s := range l.Syllables {
+			result[i].Syllables[j] = Syllable(s)
+		}
+	}
+	return result
+}
+
+// SongLyrics is the schema for the timed lyrics of a song.
+// P2 is only present for duets.
+type SongLyrics struct {
+	render.NopRenderer
+	P1 []LyricLine `json:"p1"`
+	P2 []LyricLine `json:"p2,omitempty"`
+}
+
+// FromSongLyrics computes the lyrics of m and converts them into a schema instance.
+func FromSongLyrics(m model.Song) SongLyrics {
+	lyrics := SongLyrics{P1: fromLyricLines(song.Lyrics(m, m.NotesP1))}
+	if m.IsDuet() {
+		lyrics.P2 = fromLyricLines(song.Lyrics(m, m.NotesP2))
+	}
+	return lyrics
+}
@@ -0,0 +1,27 @@
+package schema
+
+import (
+	"time"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// MedleySuggestion is the schema for a proposed medley and preview of a song.
+type MedleySuggestion struct {
+	render.NopRenderer
+	MedleyStartBeat ultrastar.Beat `json:"medleyStartBeat"`
+	MedleyEndBeat   ultrastar.Beat `json:"medleyEndBeat"`
+	PreviewStart    time.Duration  `json:"previewStart,omitempty"`
+}
+
+// FromMedleySuggestion converts s into a schema instance.
+func FromMedleySuggestion(s song.MedleySuggestion) MedleySuggestion {
+	return MedleySuggestion{
+		MedleyStartBeat: s.MedleyStartBeat,
+		MedleyEndBeat:   s.MedleyEndBeat,
+		PreviewStart:    s.PreviewStart,
+	}
+}
@@ -0,0 +1,191 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// noteTypes maps the note types of the API to UltraStar note types.
+var noteTypes = map[string]ultrastar.NoteType{
+	"regular":    ultrastar.NoteTypeRegular,
+	"golden":     ultrastar.NoteTypeGolden,
+	"freestyle":  ultrastar.NoteTypeFreestyle,
+	"rap":        ultrastar.NoteTypeRap,
+	"golden-rap": ultrastar.NoteTypeGoldenRap,
+}
+
+// noteTypeName returns the API name of t.
+func noteTypeName(t ultrastar.NoteType) string {
+	for name, nt := range noteTypes {
+		if nt == t {
+			return name
+		}
+	}
+	return string(t)
+}
+
+// Note is the schema for a single note of a song.
+type Note struct {
+	Type     string          `json:"type"`
+	Start    ultrastar.Beat  `json:"start"`
+	Duration ultrastar.Beat  `json:"duration"`
+	Pitch    ultrastar.Pitch `json:"pitch"`
+	Text     string          `json:"text"`
+}
+
+// fromNote converts n into a schema instance.
+func fromNote(n ultrastar.Note) Note {
+	return Note{
+		Type:     noteTypeName(n.Type),
+		Start:    n.Start,
+		Duration: n.Duration,
+		Pitch:    n.Pitch,
+		Text:     n.Text,
+	}
+}
+
+// toNote converts n into an ultrastar.Note.
+// If n.Type is not a known note type, an error is returned.
+func (n Note) toNote() (ultrastar.Note, error) {
+	t, ok := noteTypes[n.Type]
+	if !ok {
+		return ultrastar.Note{}, fmt.Errorf("unknown note type %q", n.Type)
+	}
+	return ultrastar.Note{Type: t, Start: n.Start, Duration: n.Duration, Pitch: n.Pitch, Text: n.Text}, nil
+}
+
+// NotesLine is the schema for a single line of notes.
+type NotesLine struct {
+	// Start is the beat of the line break preceding the line.
+	Start ultrastar.Beat `json:"start"`
+	Notes []Note         `json:"notes"`
+}
+
+// fromLines converts lines into schema instances.
+func fromLines(lines []song.Line) []NotesLine {
+	result := make([]NotesLine, len(lines))
+	for i, line := range lines {
+		result[i] = NotesLine{Start: line.Start, Notes: make([]Note, len(line.Notes))}
+		for j, n := range line.Notes {
+			result[i].Notes[j] = fromNote(n)
+		}
+	}
+	return result
+}
+
+// SongNotes is the schema for the notes of a song, split into lines.
+// Lines and notes are identified by their indices in the P1 and P2 arrays.
+type SongNotes struct {
+	render.NopRenderer
+	P1 []NotesLine `json:"p1"`
+	P2 []NotesLine `json:"p2,omitempty"`
+}
+
+// FromSongNotes converts the notes of m into a schema instance.
+func FromSongNotes(m model.Song) SongNotes {
+	return SongNotes{
+		P1: fromLines(song.SplitLines(m.NotesP1)),
+		P2: fromLines(song.SplitLines(m.NotesP2)),
+	}
+}
+
+// NoteEdit is the schema for a single edit of a NotesPatch.
+// Which fields are required depends on the operation.
+type NoteEdit struct {
+	Op     song.NoteOp `json:"op"`
+	Player int         `json:"player,omitempty"` // defaults to 1
+	Line   int         `json:"line"`
+	Note   int         `json:"note"`
+
+	Value    *Note            `json:"value,omitempty"`
+	Start    *ultrastar.Beat  `json:"start,omitempty"`
+	Duration *ultrastar.Beat  `json:"duration,omitempty"`
+	Pitch    *ultrastar.Pitch `json:"pitch,omitempty"`
+	Text     *string          `json:"text,omitempty"`
+	Type     string           `json:"type,omitempty"`
+}
+
+// toEdit validates that e contains the fields required by e.Op and converts it into a song.NoteEdit.
+func (e NoteEdit) toEdit() (song.NoteEdit, error) {
+	edit := song.NoteEdit{Op: e.Op, Player: e.Player, Line: e.Line, Note: e.Note}
+	if edit.Player == 0 {
+		edit.Player = 1
+	}
+	var err error
+	switch e.Op {
+	case song.InsertNote:
+		if e.Value == nil {
+			return edit, errors.New("value is required")
+		}
+		edit.Value, err = e.Value.toNote()
+	case song.MoveNote:
+		if e.Start == nil {
+			return edit, errors.New("start is required")
+		}
+		edit.Start = *e.Start
+	case song.ResizeNote:
+		if e.Duration == nil {
+			return edit, errors.New("duration is required")
+		}
+		edit.Duration = *e.Duration
+	case song.SetPitch:
+		if e.Pitch == nil {
+			return edit, errors.New("pitch is required")
+		}
+		edit.Pitch = *e.Pitch
+	case song.SetText:
+		if e.Text == nil {
+			return edit, errors.New("text is required")
+		}
+		edit.Text = *e.Text
+	case song.SetType:
+		t, ok := noteTypes[e.Type]
+		if !ok {
+			return edit, fmt.Errorf("unknown note type %q", e.Type)
+		}
+		edit.Type = t
+	case song.SplitLine:
+		if e.Start != nil {
+			edit.Start = *e.Start
+		}
+	case song.DeleteNote, song.MergeLines:
+	default:
+		return edit, fmt.Errorf("unknown operation %q", e.Op)
+	}
+	return edit, err
+}
+
+// NotesPatch is the request schema for granular edits of the notes of a song.
+type NotesPatch struct {
+	Edits []NoteEdit `json:"edits"`
+
+	// edits contains the converted edits after binding.
+	edits []song.NoteEdit
+}
+
+// Bind implements the render.Binder interface.
+// Bind validates the fields of each edit.
+func (p *NotesPatch) Bind(*http.Request) error {
+	p.edits = make([]song.NoteEdit, len(p.Edits))
+	for i, e := range p.Edits {
+		edit, err := e.toEdit()
+		if err != nil {
+			return fmt.Errorf("edit %d: %w", i, err)
+		}
+		p.edits[i] = edit
+	}
+	return nil
+}
+
+// NoteEdits returns the edits of p.
+// This method must only be called after p has been bound successfully.
+func (p *NotesPatch) NoteEdits() []song.NoteEdit {
+	return p.edits
+}
@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// PlaylistRW is the main schema for working with playlists.
+// All fields in PlaylistRW are readable and writeable fields.
+type PlaylistRW struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Songs are the UUIDs of the songs in the playlist, in order.
+	Songs []uuid.UUID `json:"songs"`
+}
+
+// Playlist extends PlaylistRW with additional read-only fields used in API responses.
+type Playlist struct {
+	render.NopRenderer
+	PlaylistRW
+	UUID uuid.UUID `json:"uuid"`
+}
+
+// FromPlaylist converts m into a schema instance representing the current state of m.
+func FromPlaylist(m model.Playlist) Playlist {
+	songs := m.Songs
+	if songs == nil {
+		songs = make([]uuid.UUID, 0)
+	}
+	return Playlist{
+		UUID: m.UUID,
+		PlaylistRW: PlaylistRW{
+			Name:        m.Name,
+			Description: m.Description,
+			Songs:       songs,
+		},
+	}
+}
+
+// Apply stores the fields of s into the respective fields of m.
+func (s *PlaylistRW) Apply(m *model.Playlist) {
+	m.Name = s.Name
+	m.Description = s.Description
+	m.Songs = s.Songs
+}
+
+// Bind implements the render.Binder interface.
+// Bind makes sure that the playlist has a name.
+func (s *PlaylistRW) Bind(*http.Request) error {
+	if strings.TrimSpace(s.Name) == "" {
+		return errors.New("the playlist name must not be empty")
+	}
+	return nil
+}
+
+// PlaylistImport is the response schema for playlists imported from a UPL file.
+type PlaylistImport struct {
+	Playlist
+	// Missing contains the entries of the UPL file that do not match any song in the library.
+	Missing []string `json:"missing"`
+}
@@ -0,0 +1,146 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"codello.dev/ultrastar"
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// ReviewStateChange is the request schema for changing the review state of a song.
+type ReviewStateChange struct {
+	State model.ReviewState `json:"state"`
+}
+
+// Bind implements the render.Binder interface.
+// Bind makes sure that the state is valid.
+func (c *ReviewStateChange) Bind(*http.Request) error {
+	if !c.State.IsValid() {
+		return fmt.Errorf("invalid state: %q", c.State)
+	}
+	return nil
+}
+
+// CommentAnchor references a line or a beat of a player.
+// In requests exactly one of Line and Beat must be set.
+// In responses Beat is always set and Line is only set for anchors that reference a line.
+type CommentAnchor struct {
+	Player int             `json:"player"`
+	Line   *int            `json:"line,omitempty"`
+	Beat   *ultrastar.Beat `json:"beat,omitempty"`
+}
+
+// SongComment is the response schema for model.SongComment.
+type SongComment struct {
+	render.NopRenderer
+	UUID      uuid.UUID         `json:"uuid"`
+	CreatedAt time.Time         `json:"createdAt"`
+	UpdatedAt time.Time         `json:"updatedAt"`
+	Author    string            `json:"author,omitempty"`
+	Kind      model.CommentKind `json:"kind"`
+	Text      string            `json:"text"`
+	Anchor    *CommentAnchor    `json:"anchor,omitempty"`
+	Resolved  bool              `json:"resolved"`
+}
+
+// FromSongComment converts m into a schema instance.
+func FromSongComment(m model.SongComment) SongComment {
+	c := SongComment{
+		UUID:      m.UUID,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+		Author:    m.Author,
+		Kind:      m.Kind,
+		Text:      m.Text,
+		Resolved:  m.Resolved,
+	}
+	if m.Anchor != nil {
+		c.Anchor = &CommentAnchor{Player: m.Anchor.Player, Beat: &m.Anchor.Beat}
+		if m.Anchor.Line >= 0 {
+			c.Anchor.Line = &m.Anchor.Line
+		}
+	}
+	return c
+}
+
+// NewSongComment is the request schema for adding a comment to the thread of a song.
+type NewSongComment struct {
+	Kind   model.CommentKind `json:"kind"`
+	Text   string            `json:"text"`
+	Anchor *CommentAnchor    `json:"anchor"`
+}
+
+// Bind implements the render.Binder interface.
+// Bind sets the default kind and makes sure that remarks have a text and anchors reference either a line or a beat.
+// Out-of-sync reports can omit the text.
+func (c *NewSongComment) Bind(*http.Request) error {
+	if c.Kind == "" {
+		c.Kind = model.CommentKindRemark
+	}
+	if !c.Kind.IsValid() {
+		return fmt.Errorf("invalid kind: %q", c.Kind)
+	}
+	c.Text = strings.TrimSpace(c.Text)
+	if c.Kind == model.CommentKindRemark && c.Text == "" {
+		return errors.New("the text of a remark must not be empty")
+	}
+	if c.Anchor != nil && (c.Anchor.Line == nil) == (c.Anchor.Beat == nil) {
+		return errors.New("an anchor must reference either a line or a beat")
+	}
+	return nil
+}
+
+// SongCommentUpdate is the request schema for updating a comment.
+// Omitted fields are not changed.
+type SongCommentUpdate struct {
+	Text     *string `json:"text"`
+	Resolved *bool   `json:"resolved"`
+}
+
+// Bind implements the render.Binder interface.
+func (u *SongCommentUpdate) Bind(*http.Request) error {
+	if u.Text != nil {
+		*u.Text = strings.TrimSpace(*u.Text)
+	}
+	return nil
+}
+
+// Apply stores the fields of u into the respective fields of m.
+func (u *SongCommentUpdate) Apply(m *model.SongComment) {
+	if u.Text != nil {
+		m.Text = *u.Text
+	}
+	if u.Resolved != nil {
+		m.Resolved = *u.Resolved
+	}
+}
+
+// ReviewQueueEntry is the response schema for model.ReviewQueueEntry.
+type ReviewQueueEntry struct {
+	render.NopRenderer
+	Song        uuid.UUID         `json:"song"`
+	Title       string            `json:"title"`
+	Artists     []string          `json:"artists,omitempty"`
+	State       model.ReviewState `json:"state"`
+	Since       time.Time         `json:"since"`
+	OpenReports int               `json:"openReports"`
+}
+
+// FromReviewQueueEntry converts m into a schema instance.
+func FromReviewQueueEntry(m model.ReviewQueueEntry) ReviewQueueEntry {
+	return ReviewQueueEntry{
+		Song:        m.Song,
+		Title:       m.Title,
+		Artists:     m.Artists,
+		State:       m.State,
+		Since:       m.Since,
+		OpenReports: m.OpenReports,
+	}
+}
@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core/revision"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// SongRevision is the response schema for model.SongRevision.
+// The snapshot data of a revision is not included.
+type SongRevision struct {
+	render.NopRenderer
+	UUID   uuid.UUID `json:"uuid"`
+	Time   time.Time `json:"time"`
+	Author string    `json:"author,omitempty"`
+}
+
+// FromSongRevision converts m into a schema instance.
+func FromSongRevision(m model.SongRevision) SongRevision {
+	return SongRevision{
+		UUID:   m.UUID,
+		Time:   m.CreatedAt,
+		Author: m.Author,
+	}
+}
+
+// FieldChange describes a single changed metadata field of a song.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// LineChange describes a single added or removed line of notes.
+type LineChange struct {
+	Op      revision.LineOp `json:"op"`
+	OldLine int             `json:"oldLine,omitempty"`
+	NewLine int             `json:"newLine,omitempty"`
+	Text    string          `json:"text"`
+}
+
+// SongRevisionDiff is the response schema for the changes between two song revisions.
+type SongRevisionDiff struct {
+	render.NopRenderer
+	From   *uuid.UUID    `json:"from"` // nil for the first revision of a song
+	To     uuid.UUID     `json:"to"`
+	Fields []FieldChange `json:"fields"`
+	Notes  struct {
+		P1 []LineChange `json:"p1"`
+		P2 []LineChange `json:"p2"`
+	} `json:"notes"`
+}
+
+// FromRevisionDiff converts d into a schema instance.
+// from may be nil if the diff is calculated against an empty revision.
+func FromRevisionDiff(from *model.SongRevision, to model.SongRevision, d revision.Diff) SongRevisionDiff {
+	resp := SongRevisionDiff{
+		To:     to.UUID,
+		Fields: make([]FieldChange, len(d.Fields)),
+	}
+	if from != nil {
+		resp.From = &from.UUID
+	}
+	for i, c := range d.Fields {
+		resp.Fields[i] = FieldChange{c.Field, c.Old, c.New}
+	}
+	resp.Notes.P1 = fromLineChanges(d.NotesP1)
+	resp.Notes.P2 = fromLineChanges(d.NotesP2)
+	return resp
+}
+
+// fromLineChanges converts changes into schema instances.
+func fromLineChanges(changes []revision.LineChange) []LineChange {
+	resp := make([]LineChange, len(changes))
+	for i, c := range changes {
+		resp[i] = LineChange{c.Op, c.OldLine, c.NewLine, c.Text}
+	}
+	return resp
+}
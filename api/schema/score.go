@@ -0,0 +1,83 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core/score"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// PlayStats is the response schema for the play statistics of a song.
+type PlayStats struct {
+	render.NopRenderer
+	Song        uuid.UUID `json:"song"`
+	TimesPlayed int       `json:"timesPlayed"`
+	// LastPlayed is nil if the time is unknown.
+	LastPlayed *time.Time `json:"lastPlayed"`
+}
+
+// FromPlayStats converts m into a schema instance.
+func FromPlayStats(m model.PlayStats) PlayStats {
+	return PlayStats{
+		Song:        m.Song,
+		TimesPlayed: m.TimesPlayed,
+		LastPlayed:  optionalTime(m.LastPlayed),
+	}
+}
+
+// Highscore is the response schema for a highscore of a song.
+type Highscore struct {
+	render.NopRenderer
+	Song       uuid.UUID            `json:"song"`
+	Player     string               `json:"player"`
+	Difficulty model.GameDifficulty `json:"difficulty"`
+	Score      int                  `json:"score"`
+	// Date is nil if the time is unknown.
+	Date *time.Time `json:"date"`
+}
+
+// FromHighscore converts m into a schema instance.
+func FromHighscore(m model.Highscore) Highscore {
+	return Highscore{
+		Song:       m.Song,
+		Player:     m.Player,
+		Difficulty: m.Difficulty,
+		Score:      m.Score,
+		Date:       optionalTime(m.Date),
+	}
+}
+
+// ScoreImport is the response schema for an import of play statistics.
+type ScoreImport struct {
+	render.NopRenderer
+	// Matched is the number of imported songs that matched a song in the library.
+	Matched int `json:"matched"`
+	// Scores is the number of imported highscores.
+	Scores int `json:"scores"`
+	// Unmatched contains the imported songs that did not match any song in the library in the form "Artist : Title".
+	Unmatched []string `json:"unmatched"`
+}
+
+// FromScoreImport converts r into a schema instance.
+func FromScoreImport(r score.Result) ScoreImport {
+	resp := ScoreImport{
+		Matched:   r.Matched,
+		Scores:    r.Scores,
+		Unmatched: make([]string, len(r.Unmatched)),
+	}
+	for i, s := range r.Unmatched {
+		resp.Unmatched[i] = s.String()
+	}
+	return resp
+}
+
+// optionalTime converts t into a pointer that is nil if t is the zero time.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,181 @@
+package schema
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// SessionRW is the main schema for working with party sessions.
+// All fields in SessionRW are readable and writeable fields.
+type SessionRW struct {
+	Name string `json:"name"`
+	// RequestLimit is the maximum number of queued requests per guest.
+	RequestLimit int  `json:"requestLimit"`
+	Closed       bool `json:"closed"`
+}
+
+// Session extends SessionRW with additional read-only fields used in API responses.
+type Session struct {
+	render.NopRenderer
+	SessionRW
+	UUID uuid.UUID `json:"uuid"`
+	// Code is the join code of the session.
+	Code string `json:"code"`
+}
+
+// FromSession converts m into a schema instance representing the current state of m.
+func FromSession(m model.Session) Session {
+	return Session{
+		UUID: m.UUID,
+		Code: m.Code,
+		SessionRW: SessionRW{
+			Name:         m.Name,
+			RequestLimit: m.RequestLimit,
+			Closed:       m.Closed,
+		},
+	}
+}
+
+// GuestSession is the schema of a session as seen by guests.
+// The UUID of a session grants operator access, so it is not included.
+type GuestSession struct {
+	render.NopRenderer
+	SessionRW
+	// Code is the join code of the session.
+	// The code is omitted in session listings.
+	Code string `json:"code,omitempty"`
+}
+
+// FromGuestSession converts m into a schema instance representing the state of m that is visible to guests.
+func FromGuestSession(m model.Session) GuestSession {
+	return GuestSession{
+		Code: m.Code,
+		SessionRW: SessionRW{
+			Name:         m.Name,
+			RequestLimit: m.RequestLimit,
+			Closed:       m.Closed,
+		},
+	}
+}
+
+// Apply stores the fields of s into the respective fields of m.
+func (s *SessionRW) Apply(m *model.Session) {
+	m.Name = s.Name
+	m.RequestLimit = s.RequestLimit
+	m.Closed = s.Closed
+}
+
+// Bind implements the render.Binder interface.
+// Bind makes sure that the session has a name and a valid request limit.
+func (s *SessionRW) Bind(*http.Request) error {
+	if strings.TrimSpace(s.Name) == "" {
+		return errors.New("the session name must not be empty")
+	}
+	if s.RequestLimit < 0 {
+		return errors.New("the request limit must not be negative")
+	}
+	return nil
+}
+
+// SongRequestRW is the schema that guests use to request songs.
+type SongRequestRW struct {
+	Song    uuid.UUID `json:"song"`
+	Singer  string    `json:"singer"`
+	Partner string    `json:"partner"`
+}
+
+// Apply stores the fields of s into the respective fields of m.
+func (s *SongRequestRW) Apply(m *model.SongRequest) {
+	m.Song = s.Song
+	m.Singer = s.Singer
+	m.Partner = s.Partner
+}
+
+// Bind implements the render.Binder interface.
+// Bind makes sure that a song and a singer are specified.
+func (s *SongRequestRW) Bind(*http.Request) error {
+	if s.Song == uuid.Nil {
+		return errors.New("the song must be specified")
+	}
+	if strings.TrimSpace(s.Singer) == "" {
+		return errors.New("the singer name must not be empty")
+	}
+	return nil
+}
+
+// SongRequest extends SongRequestRW with additional read-only fields used in API responses.
+type SongRequest struct {
+	render.NopRenderer
+	SongRequestRW
+	UUID      uuid.UUID          `json:"uuid"`
+	State     model.RequestState `json:"state"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+// FromSongRequest converts m into a schema instance representing the current state of m.
+func FromSongRequest(m model.SongRequest) SongRequest {
+	return SongRequest{
+		UUID:      m.UUID,
+		State:     m.State,
+		CreatedAt: m.CreatedAt,
+		SongRequestRW: SongRequestRW{
+			Song:    m.Song,
+			Singer:  m.Singer,
+			Partner: m.Partner,
+		},
+	}
+}
+
+// SongRequests is the response schema for the requests of a session in queue order.
+type SongRequests []SongRequest
+
+// FromSongRequests converts ms into a schema instance.
+func FromSongRequests(ms []model.SongRequest) SongRequests {
+	s := make(SongRequests, len(ms))
+	for i, m := range ms {
+		s[i] = FromSongRequest(m)
+	}
+	return s
+}
+
+// Render implements the render.Renderer interface.
+func (SongRequests) Render(http.ResponseWriter, *http.Request) error {
+	return nil
+}
+
+// SongRequestState is the schema that operators use to mark requests as sung or skipped.
+type SongRequestState struct {
+	State model.RequestState `json:"state"`
+}
+
+// Bind implements the render.Binder interface.
+// Bind makes sure that the state is valid.
+func (s *SongRequestState) Bind(*http.Request) error {
+	switch s.State {
+	case model.RequestStateQueued, model.RequestStateSung, model.RequestStateSkipped:
+		return nil
+	default:
+		return errors.New("the state must be one of queued, sung or skipped")
+	}
+}
+
+// QueueOrder is the schema that operators use to reorder the queue of a session.
+type QueueOrder struct {
+	// Requests are the UUIDs of all queued requests in their new order.
+	Requests []uuid.UUID `json:"requests"`
+}
+
+// Bind implements the render.Binder interface.
+func (s *QueueOrder) Bind(*http.Request) error {
+	if s.Requests == nil {
+		return errors.New("the requests must be specified")
+	}
+	return nil
+}
@@ -53,14 +53,16 @@ type ImageFile struct {
 // All fields in SongRW are readable and writeable fields.
 // The Song schema extends this with some read-only fields.
 type SongRW struct {
-	Title    string   `json:"title"`
-	Artists  []string `json:"artists,omitempty"`
-	Genre    string   `json:"genre,omitempty"`
-	Edition  string   `json:"edition,omitempty"`
-	Creator  string   `json:"creator,omitempty"`
-	Language string   `json:"language,omitempty"`
-	Year     int      `json:"year,omitempty"`
-	Comment  string   `json:"comment,omitempty"`
+	Title           string   `json:"title"`
+	Artists         []string `json:"artists,omitempty"`
+	FeaturedArtists []string `json:"featuredArtists,omitempty"`
+	Tags            []string `json:"tags,omitempty"`
+	Genre           string   `json:"genre,omitempty"`
+	Edition         string   `json:"edition,omitempty"`
+	Creator         string   `json:"creator,omitempty"`
+	Language        string   `json:"language,omitempty"`
+	Year            int      `json:"year,omitempty"`
+	Comment         string   `json:"comment,omitempty"`
 
 	DuetSinger1 string            `json:"duetSinger1,omitempty"`
 	DuetSinger2 string            `json:"duetSinger2,omitempty"`
@@ -92,6 +94,20 @@ type Song struct {
 	Video      *VideoFile `json:"video"`
 	Cover      *ImageFile `json:"cover"`
 	Background *ImageFile `json:"background"`
+
+	Stats SongStats `json:"stats"`
+
+	// VariantGroup is only set for songs that belong to a variant group.
+	// Variants lists the other songs in the group.
+	VariantGroup    *uuid.UUID            `json:"variantGroup,omitempty"`
+	VariantRelation model.VariantRelation `json:"variantRelation,omitempty"`
+	Variants        []SongVariant         `json:"variants,omitempty"`
+
+	// ReviewState is the state of the song in the review workflow.
+	ReviewState model.ReviewState `json:"reviewState"`
+
+	// DeletedAt is only set for songs in the trash.
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
 }
 
 // FromSong converts m into a schema instance representing the current state of m.
@@ -99,14 +115,16 @@ func FromSong(m model.Song) Song {
 	song := Song{
 		UUID: m.UUID,
 		SongRW: SongRW{
-			Title:    m.Title,
-			Artists:  m.Artists,
-			Genre:    m.Genre,
-			Edition:  m.Edition,
-			Creator:  m.Creator,
-			Language: m.Language,
-			Year:     m.Year,
-			Comment:  m.Comment,
+			Title:           m.Title,
+			Artists:         m.Artists,
+			FeaturedArtists: m.FeaturedArtists,
+			Tags:            m.Tags,
+			Genre:           m.Genre,
+			Edition:         m.Edition,
+			Creator:         m.Creator,
+			Language:        m.Language,
+			Year:            m.Year,
+			Comment:         m.Comment,
 
 			DuetSinger1: m.DuetSinger1,
 			DuetSinger2: m.DuetSinger2,
@@ -119,7 +137,25 @@ func FromSong(m model.Song) Song {
 			End:          m.End,
 			PreviewStart: m.PreviewStart,
 		},
-		Duet: m.IsDuet(),
+		Duet:        m.IsDuet(),
+		Stats:       FromSongStats(m.Stats),
+		ReviewState: m.ReviewState,
+	}
+	if m.Deleted() {
+		song.DeletedAt = &m.DeletedAt
+	}
+	if m.VariantGroup != uuid.Nil {
+		song.VariantGroup = &m.VariantGroup
+		song.VariantRelation = m.VariantRelation
+		song.Variants = make([]SongVariant, len(m.Variants))
+		for i, v := range m.Variants {
+			song.Variants[i] = SongVariant{
+				UUID:     v.Song,
+				Title:    v.Title,
+				Creator:  v.Creator,
+				Relation: v.Relation,
+			}
+		}
 	}
 
 	if m.NoAutoMedley {
@@ -167,6 +203,8 @@ func FromSong(m model.Song) Song {
 func (s *SongRW) Apply(m *model.Song) {
 	m.Title = s.Title
 	m.Artists = s.Artists
+	m.FeaturedArtists = s.FeaturedArtists
+	m.Tags = s.Tags
 	m.Genre = s.Genre
 	m.Edition = s.Edition
 	m.Creator = s.Creator
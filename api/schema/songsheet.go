@@ -0,0 +1,228 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core/revision"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// SongSheetColumns are the columns of a song sheet in the order they are exported.
+// The uuid column identifies a song, all other columns correspond to fields of the SongRW schema.
+var SongSheetColumns = []string{"uuid", "title", "artists", "featuredArtists", "genre", "edition", "language", "year", "creator", "comment"}
+
+// songSheetArtistSeparator separates multiple artists in a single cell of a song sheet.
+const songSheetArtistSeparator = "; "
+
+// songSheetFormulaPrefixes are the characters that make spreadsheet applications interpret a cell as a formula.
+const songSheetFormulaPrefixes = "=+-@\t\r"
+
+// escapeCell prefixes value with a single quote if a spreadsheet application would interpret it as a formula.
+func escapeCell(value string) string {
+	if value != "" && strings.ContainsRune(songSheetFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeCell reverts escapeCell.
+func unescapeCell(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(songSheetFormulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// SongSheet is the CSV response schema for the metadata of multiple songs.
+// The first record contains the column names.
+type SongSheet struct {
+	render.NopRenderer
+	Songs []model.Song
+}
+
+// CSV returns the records of the song sheet.
+// Cells that would be interpreted as formulas by spreadsheet applications are prefixed with a single quote.
+func (s *SongSheet) CSV() [][]string {
+	records := make([][]string, 0, len(s.Songs)+1)
+	records = append(records, SongSheetColumns)
+	for _, song := range s.Songs {
+		year := ""
+		if song.Year != 0 {
+			year = strconv.Itoa(song.Year)
+		}
+		records = append(records, []string{
+			song.UUID.String(),
+			escapeCell(song.Title),
+			escapeCell(strings.Join(song.Artists, songSheetArtistSeparator)),
+			escapeCell(strings.Join(song.FeaturedArtists, songSheetArtistSeparator)),
+			escapeCell(song.Genre),
+			escapeCell(song.Edition),
+			escapeCell(song.Language),
+			year,
+			escapeCell(song.Creator),
+			escapeCell(song.Comment),
+		})
+	}
+	return records
+}
+
+// A SongSheetRow is a single row of an imported song sheet.
+type SongSheetRow struct {
+	// UUID identifies the song described by the row.
+	UUID uuid.UUID
+	// Record is the index of the row in the CSV records.
+	// The header has index 0.
+	Record int
+
+	columns []string
+	values  []string
+}
+
+// ParseSongSheet parses CSV records of a song sheet.
+// The first record must contain the column names, including the uuid column.
+// Other columns are optional, unknown columns are rejected.
+//
+// If the records are invalid, the second return value contains validation errors.
+// The keys of the map are JSON pointers into records.
+func ParseSongSheet(records [][]string) ([]SongSheetRow, map[string]string) {
+	errs := make(map[string]string)
+	if len(records) == 0 {
+		errs[""] = "the header row is missing"
+		return nil, errs
+	}
+	header := records[0]
+	idColumn := -1
+	for i, column := range header {
+		if !slices.Contains(SongSheetColumns, column) {
+			errs[fmt.Sprintf("/0/%d", i)] = fmt.Sprintf("unknown column %q", column)
+		} else if slices.Index(header, column) != i {
+			errs[fmt.Sprintf("/0/%d", i)] = fmt.Sprintf("duplicate column %q", column)
+		} else if column == "uuid" {
+			idColumn = i
+		}
+	}
+	if idColumn < 0 {
+		errs["/0"] = "the uuid column is missing"
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	rows := make([]SongSheetRow, 0, len(records)-1)
+	seen := make(map[uuid.UUID]bool, len(records)-1)
+	for i, record := range records[1:] {
+		index := i + 1
+		if len(record) != len(header) {
+			errs[fmt.Sprintf("/%d", index)] = fmt.Sprintf("expected %d fields, got %d", len(header), len(record))
+			continue
+		}
+		id, err := uuid.Parse(record[idColumn])
+		if err != nil {
+			errs[fmt.Sprintf("/%d/%d", index, idColumn)] = "invalid UUID"
+			continue
+		}
+		if seen[id] {
+			errs[fmt.Sprintf("/%d/%d", index, idColumn)] = "duplicate UUID"
+			continue
+		}
+		seen[id] = true
+		rows = append(rows, SongSheetRow{UUID: id, Record: index, columns: header, values: record})
+	}
+	return rows, errs
+}
+
+// Apply stores the values of the row into the respective fields of s.
+// Fields without a column are not changed.
+// Cells escaped by SongSheet.CSV are unescaped.
+// The returned map contains validation errors as in ParseSongSheet.
+func (row SongSheetRow) Apply(s *SongRW) map[string]string {
+	errs := make(map[string]string)
+	for i, column := range row.columns {
+		value := strings.TrimSpace(unescapeCell(strings.TrimSpace(row.values[i])))
+		pointer := fmt.Sprintf("/%d/%d", row.Record, i)
+		switch column {
+		case "title":
+			if value == "" {
+				errs[pointer] = "title must not be empty"
+			}
+			s.Title = value
+		case "artists":
+			s.Artists = splitArtists(value)
+		case "featuredArtists":
+			s.FeaturedArtists = splitArtists(value)
+		case "genre":
+			s.Genre = value
+		case "edition":
+			s.Edition = value
+		case "language":
+			s.Language = value
+		case "year":
+			s.Year = 0
+			if value != "" {
+				year, err := strconv.Atoi(value)
+				if err != nil || year < 0 {
+					errs[pointer] = "year must be a positive number"
+				}
+				s.Year = year
+			}
+		case "creator":
+			s.Creator = value
+		case "comment":
+			s.Comment = value
+		}
+	}
+	if len(errs) == 0 {
+		if err := s.Bind(nil); err != nil {
+			errs[fmt.Sprintf("/%d", row.Record)] = err.Error()
+		}
+	}
+	return errs
+}
+
+// splitArtists splits a cell of a song sheet into artist names.
+func splitArtists(value string) []string {
+	if value == "" {
+		return nil
+	}
+	artists := strings.Split(value, strings.TrimSpace(songSheetArtistSeparator))
+	for i, artist := range artists {
+		artists[i] = strings.TrimSpace(artist)
+	}
+	return slices.DeleteFunc(artists, func(artist string) bool { return artist == "" })
+}
+
+// SongSheetImport is the response schema for importing a song sheet.
+type SongSheetImport struct {
+	render.NopRenderer
+	// DryRun indicates that the changes have not been applied.
+	DryRun  bool              `json:"dryRun"`
+	Changes []SongSheetChange `json:"changes"`
+}
+
+// SongSheetChange describes the changes of a single song by a song sheet.
+type SongSheetChange struct {
+	Song   uuid.UUID     `json:"song"`
+	Record int           `json:"record"`
+	Fields []FieldChange `json:"fields"`
+}
+
+// NewSongSheetChange calculates the changes between old and updated.
+// record is the index of the row in the song sheet.
+func NewSongSheetChange(old model.Song, updated model.Song, record int) SongSheetChange {
+	d := revision.Compare(model.NewSongRevision(old, ""), model.NewSongRevision(updated, ""))
+	change := SongSheetChange{
+		Song:   old.UUID,
+		Record: record,
+		Fields: make([]FieldChange, len(d.Fields)),
+	}
+	for i, c := range d.Fields {
+		change.Fields[i] = FieldChange{c.Field, c.Old, c.New}
+	}
+	return change
+}
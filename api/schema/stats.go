@@ -0,0 +1,77 @@
+package schema
+
+import (
+	"time"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// Pitch is a pitch as UltraStar pitch number together with its name in scientific pitch notation.
+type Pitch struct {
+	Value ultrastar.Pitch `json:"value"`
+	Name  string          `json:"name"`
+}
+
+// fromPitch converts p into a schema instance.
+func fromPitch(p ultrastar.Pitch) Pitch {
+	return Pitch{p, song.PitchName(p)}
+}
+
+// TrackStats contains statistics about the notes of a single player.
+type TrackStats struct {
+	Notes   int `json:"notes"`
+	Pitched int `json:"pitched"`
+
+	// The pitches are only present if the track contains pitched notes.
+	LowestPitch  *Pitch `json:"lowestPitch"`
+	HighestPitch *Pitch `json:"highestPitch"`
+	MedianPitch  *Pitch `json:"medianPitch"`
+
+	NoteDensity    float64 `json:"noteDensity"` // in notes per second
+	GoldenRatio    float64 `json:"goldenRatio"`
+	FreestyleRatio float64 `json:"freestyleRatio"`
+}
+
+// fromTrackStats converts m into a schema instance.
+func fromTrackStats(m model.TrackStats) TrackStats {
+	stats := TrackStats{
+		Notes:          m.Notes,
+		Pitched:        m.Pitched,
+		NoteDensity:    m.NoteDensity,
+		GoldenRatio:    m.GoldenRatio,
+		FreestyleRatio: m.FreestyleRatio,
+	}
+	if m.Pitched > 0 {
+		lowest, highest, median := fromPitch(m.LowestPitch), fromPitch(m.HighestPitch), fromPitch(m.MedianPitch)
+		stats.LowestPitch, stats.HighestPitch, stats.MedianPitch = &lowest, &highest, &median
+	}
+	return stats
+}
+
+// SongStats contains read-only statistics about the notes of a song.
+type SongStats struct {
+	P1 TrackStats  `json:"p1"`
+	P2 *TrackStats `json:"p2"` // only set for duets
+
+	Length time.Duration `json:"length"`
+	// Difficulty ranges from 1 (easiest) to 5 (hardest).
+	// A value of 0 indicates that the difficulty is unknown.
+	Difficulty model.Difficulty `json:"difficulty"`
+}
+
+// FromSongStats converts m into a schema instance.
+func FromSongStats(m model.SongStats) SongStats {
+	stats := SongStats{
+		P1:         fromTrackStats(m.P1),
+		Length:     m.Length,
+		Difficulty: m.Difficulty,
+	}
+	if m.P2 != nil {
+		p2 := fromTrackStats(*m.P2)
+		stats.P2 = &p2
+	}
+	return stats
+}
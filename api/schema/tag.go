@@ -0,0 +1,63 @@
+package schema
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// tagColorPattern matches valid tag colors.
+var tagColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// TagRW is the main schema for working with tags.
+// All fields in TagRW are readable and writeable fields.
+type TagRW struct {
+	Name  string `json:"name"`
+	Color string `json:"color,omitempty"`
+}
+
+// Tag extends TagRW with additional read-only fields used in API responses.
+type Tag struct {
+	render.NopRenderer
+	TagRW
+	UUID uuid.UUID `json:"uuid"`
+
+	// SongCount is the number of songs in the library that have the tag.
+	SongCount int64 `json:"songCount"`
+}
+
+// FromTag converts m into a schema instance representing the current state of m.
+func FromTag(m model.Tag) Tag {
+	return Tag{
+		UUID:      m.UUID,
+		SongCount: m.SongCount,
+		TagRW: TagRW{
+			Name:  m.Name,
+			Color: m.Color,
+		},
+	}
+}
+
+// Apply stores the fields of s into the respective fields of m.
+func (s *TagRW) Apply(m *model.Tag) {
+	m.Name = s.Name
+	m.Color = s.Color
+}
+
+// Bind implements the render.Binder interface.
+// Bind makes sure that the tag has a name and a valid color.
+func (s *TagRW) Bind(*http.Request) error {
+	if strings.TrimSpace(s.Name) == "" {
+		return errors.New("the tag name must not be empty")
+	}
+	if s.Color != "" && !tagColorPattern.MatchString(strings.TrimSpace(s.Color)) {
+		return errors.New("the tag color must be a hex value of the form #rrggbb")
+	}
+	return nil
+}
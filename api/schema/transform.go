@@ -0,0 +1,102 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"codello.dev/ultrastar"
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Transform is the schema for a single timing transform.
+type Transform struct {
+	Op song.TransformOp `json:"op"`
+	// Value is a number of milliseconds for shiftGap and setVideoGap
+	// and a number of beats for shiftNotes.
+	Value *int64 `json:"value,omitempty"`
+}
+
+// toTransform validates that t contains the value required by t.Op and converts it into a song.Transform.
+func (t Transform) toTransform() (song.Transform, error) {
+	result := song.Transform{Op: t.Op}
+	switch t.Op {
+	case song.ShiftGap, song.SetVideoGap:
+		if t.Value == nil {
+			return result, errors.New("value is required")
+		}
+		result.Duration = time.Duration(*t.Value) * time.Millisecond
+	case song.ShiftNotes:
+		if t.Value == nil {
+			return result, errors.New("value is required")
+		}
+		result.Beats = ultrastar.Beat(*t.Value)
+	case song.DoubleBPM, song.HalveBPM, song.ToAbsolute:
+	default:
+		return result, fmt.Errorf("unknown operation %q", t.Op)
+	}
+	return result, nil
+}
+
+// SongTransform is the request schema for applying timing transforms to a song.
+type SongTransform struct {
+	Transforms []Transform `json:"transforms"`
+
+	// transforms contains the converted transforms after binding.
+	transforms []song.Transform
+}
+
+// Bind implements the render.Binder interface.
+// Bind validates the fields of each transform.
+func (s *SongTransform) Bind(*http.Request) error {
+	if len(s.Transforms) == 0 {
+		return errors.New("at least one transform must be specified")
+	}
+	s.transforms = make([]song.Transform, len(s.Transforms))
+	for i, t := range s.Transforms {
+		transform, err := t.toTransform()
+		if err != nil {
+			return fmt.Errorf("transform %d: %w", i, err)
+		}
+		s.transforms[i] = transform
+	}
+	return nil
+}
+
+// SongTransforms returns the transforms of s.
+// This method must only be called after s has been bound successfully.
+func (s *SongTransform) SongTransforms() []song.Transform {
+	return s.transforms
+}
+
+// BulkTransform is the request schema for applying timing transforms to a selection of songs.
+type BulkTransform struct {
+	SongTransform
+	Songs []uuid.UUID `json:"songs"`
+}
+
+// Bind implements the render.Binder interface.
+// Bind makes sure that at least one song is selected.
+func (s *BulkTransform) Bind(r *http.Request) error {
+	if len(s.Songs) == 0 {
+		return errors.New("at least one song must be specified")
+	}
+	return s.SongTransform.Bind(r)
+}
+
+// TransformResult is the result of applying transforms to a single song in a bulk operation.
+type TransformResult struct {
+	UUID uuid.UUID `json:"uuid"`
+	// Error is omitted if the transforms were applied successfully.
+	Error string `json:"error,omitempty"`
+}
+
+// BulkTransformResult is the response schema of a bulk transform.
+type BulkTransformResult struct {
+	render.NopRenderer
+	Results []TransformResult `json:"results"`
+}
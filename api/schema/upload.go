@@ -2,10 +2,14 @@ package schema
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io/fs"
+	"net/http"
 
 	"github.com/google/uuid"
 
+	"github.com/Karaoke-Manager/karman/core/revision"
 	"github.com/Karaoke-Manager/karman/model"
 	"github.com/Karaoke-Manager/karman/pkg/render"
 )
@@ -131,12 +135,96 @@ type UploadProcessingError struct {
 	render.NopRenderer
 	File    string `json:"file"`
 	Message string `json:"message"`
+	// Duplicate is set if the file contains a duplicate of a song in the library.
+	Duplicate *uuid.UUID `json:"duplicate,omitempty"`
 }
 
 // FromUploadProcessingError creates an UploadProcessingError describing err.
 func FromUploadProcessingError(err model.UploadProcessingError) UploadProcessingError {
-	return UploadProcessingError{
+	e := UploadProcessingError{
 		File:    err.File,
 		Message: err.Message,
 	}
+	if err.Duplicate != uuid.Nil {
+		e.Duplicate = &err.Duplicate
+	}
+	return e
+}
+
+// UploadSong is the response schema for a song in an upload.
+type UploadSong struct {
+	render.NopRenderer
+	Song Song `json:"song"`
+	// Match is nil if the song does not match a song in the library.
+	Match *UploadSongMatch `json:"match"`
+}
+
+// UploadSongMatch describes the library song that a song in an upload matches.
+// Fields and Notes contain the changes from the library song to the uploaded song.
+type UploadSongMatch struct {
+	Song   Song          `json:"song"`
+	Fields []FieldChange `json:"fields"`
+	Notes  struct {
+		P1 []LineChange `json:"p1"`
+		P2 []LineChange `json:"p2"`
+	} `json:"notes"`
+}
+
+// FromUploadSong converts song into a schema instance.
+// match is the library song that song matches and may be nil.
+func FromUploadSong(song model.Song, match *model.Song) UploadSong {
+	resp := UploadSong{Song: FromSong(song)}
+	if match != nil {
+		d := revision.Compare(model.NewSongRevision(*match, ""), model.NewSongRevision(song, ""))
+		resp.Match = &UploadSongMatch{
+			Song:   FromSong(*match),
+			Fields: make([]FieldChange, len(d.Fields)),
+		}
+		for i, c := range d.Fields {
+			resp.Match.Fields[i] = FieldChange{c.Field, c.Old, c.New}
+		}
+		resp.Match.Notes.P1 = fromLineChanges(d.NotesP1)
+		resp.Match.Notes.P2 = fromLineChanges(d.NotesP2)
+	}
+	return resp
+}
+
+// UploadImport is the request schema for importing songs from an upload into the library.
+type UploadImport struct {
+	Songs []UploadImportSong `json:"songs"`
+}
+
+// UploadImportSong selects how a single song is imported.
+type UploadImportSong struct {
+	Song   uuid.UUID          `json:"song"`
+	Action model.ImportAction `json:"action"`
+	// IfMatch is the entity tag of the matched library song.
+	// If specified, the library song is only updated if it has not been modified since.
+	IfMatch string `json:"ifMatch,omitempty"`
+}
+
+// Bind implements the render.Binder interface.
+// Bind makes sure that at least one song is imported and sets the default action.
+func (i *UploadImport) Bind(*http.Request) error {
+	if len(i.Songs) == 0 {
+		return errors.New("at least one song is required")
+	}
+	for j := range i.Songs {
+		switch i.Songs[j].Action {
+		case "":
+			i.Songs[j].Action = model.ImportActionKeepBoth
+		case model.ImportActionReplace, model.ImportActionKeepBoth, model.ImportActionMergeMetadata, model.ImportActionAddVariant:
+		default:
+			return fmt.Errorf("invalid import action: %q", i.Songs[j].Action)
+		}
+	}
+	return nil
+}
+
+// ImportedSongs is the response schema for songs that have been imported from an upload.
+type ImportedSongs []Song
+
+// Render implements the render.Renderer interface.
+func (ImportedSongs) Render(http.ResponseWriter, *http.Request) error {
+	return nil
 }
@@ -0,0 +1,37 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// SongVariant references another song in the variant group of a song.
+type SongVariant struct {
+	UUID     uuid.UUID             `json:"uuid"`
+	Title    string                `json:"title"`
+	Creator  string                `json:"creator,omitempty"`
+	Relation model.VariantRelation `json:"relation"`
+}
+
+// VariantLink is the request schema for adding a song to the variant group of another song.
+type VariantLink struct {
+	Of       uuid.UUID             `json:"of"`
+	Relation model.VariantRelation `json:"relation"`
+}
+
+// Bind implements the render.Binder interface.
+// Bind makes sure that a song is referenced and that the relation is valid.
+func (l *VariantLink) Bind(*http.Request) error {
+	if l.Of == uuid.Nil {
+		return errors.New("of is required")
+	}
+	if l.Relation == model.VariantRelationOriginal || !l.Relation.IsValid() {
+		return fmt.Errorf("invalid relation: %q", l.Relation)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package schema
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// WebhookRW is the main schema for working with webhooks.
+// All fields in WebhookRW are writeable fields.
+type WebhookRW struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+
+	// Secret is write-only.
+	// It is only included in the response when a webhook is created.
+	Secret string `json:"secret,omitempty"`
+}
+
+// Webhook extends WebhookRW with additional read-only fields used in API responses.
+type Webhook struct {
+	render.NopRenderer
+	WebhookRW
+	UUID uuid.UUID `json:"uuid"`
+}
+
+// FromWebhook converts m into a schema instance representing the current state of m.
+// The secret of m is not included.
+func FromWebhook(m model.Webhook) Webhook {
+	events := m.Events
+	if events == nil {
+		events = make([]string, 0)
+	}
+	return Webhook{
+		UUID: m.UUID,
+		WebhookRW: WebhookRW{
+			URL:    m.URL,
+			Events: events,
+		},
+	}
+}
+
+// Apply stores the fields of s into the respective fields of m.
+// The secret of m is only changed if s contains a secret.
+func (s *WebhookRW) Apply(m *model.Webhook) {
+	m.URL = s.URL
+	m.Events = s.Events
+	if s.Secret != "" {
+		m.Secret = s.Secret
+	}
+}
+
+// Bind implements the render.Binder interface.
+// Bind makes sure that the webhook URL is a valid HTTP URL.
+func (s *WebhookRW) Bind(*http.Request) error {
+	u, err := url.Parse(s.URL)
+	if err != nil || !u.IsAbs() || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("the webhook url must be an absolute http or https URL")
+	}
+	return nil
+}
+
+// WebhookDelivery is the response schema for model.WebhookDelivery.
+type WebhookDelivery struct {
+	render.NopRenderer
+	UUID       uuid.UUID     `json:"uuid"`
+	Time       time.Time     `json:"time"`
+	EventID    uuid.UUID     `json:"eventId"`
+	EventType  string        `json:"eventType"`
+	Attempt    int           `json:"attempt"`
+	StatusCode int           `json:"statusCode,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"duration"`
+	Success    bool          `json:"success"`
+}
+
+// FromWebhookDelivery converts m into a schema instance.
+func FromWebhookDelivery(m model.WebhookDelivery) WebhookDelivery {
+	return WebhookDelivery{
+		UUID:       m.UUID,
+		Time:       m.CreatedAt,
+		EventID:    m.EventID,
+		EventType:  m.EventType,
+		Attempt:    m.Attempt,
+		StatusCode: m.StatusCode,
+		Error:      m.Error,
+		Duration:   m.Duration,
+		Success:    m.Successful(),
+	}
+}
@@ -0,0 +1,119 @@
+package artists
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Create implements the POST /v1/artists endpoint.
+// Artists are usually created implicitly when songs are saved.
+// Creating artists explicitly is useful to register names that would otherwise be split into multiple artists.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var data schema.ArtistRW
+	if err := render.Bind(r, &data); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	artist := model.Artist{}
+	data.Apply(&artist)
+	if err := h.artistRepo.CreateArtist(r.Context(), &artist); errors.Is(err, core.ErrConflict) {
+		_ = render.Render(w, r, apierror.ArtistNameConflict())
+		return
+	} else if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not create artist.", tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	render.SetStatus(r, http.StatusCreated)
+	resp := schema.FromArtist(artist)
+	_ = render.Render(w, r, &resp)
+}
+
+// Find implements the GET /v1/artists endpoint.
+func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
+	pagination := middleware.MustGetPagination(r.Context())
+	artists, total, err := h.artistRepo.FindArtists(r.Context(), pagination.Limit, pagination.Offset)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list artists.", "limit", pagination.Limit, "offset", pagination.Offset, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+
+	resp := schema.List[*schema.Artist]{
+		Items:  make([]*schema.Artist, len(artists)),
+		Offset: pagination.Offset,
+		Limit:  pagination.RequestLimit,
+		Total:  total,
+	}
+	for i, artist := range artists {
+		s := schema.FromArtist(artist)
+		resp.Items[i] = &s
+	}
+	_ = render.Render(w, r, &resp)
+}
+
+// Get implements the GET /v1/artists/{uuid} endpoint.
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	artist := MustGetArtist(r.Context())
+	resp := schema.FromArtist(artist)
+	_ = render.Render(w, r, &resp)
+}
+
+// Update implements the PATCH /v1/artists/{uuid} endpoint.
+// Renaming an artist changes the artist name of all songs crediting the artist.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	artist := MustGetArtist(r.Context())
+	update := schema.FromArtist(artist)
+	if err := render.Bind(r, &update); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	update.Apply(&artist)
+	if err := h.artistRepo.UpdateArtist(r.Context(), &artist); errors.Is(err, core.ErrConflict) {
+		_ = render.Render(w, r, apierror.ArtistNameConflict())
+		return
+	} else if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not update artist.", "uuid", artist.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	_ = render.NoContent(w, r)
+}
+
+// Merge implements the POST /v1/artists/{uuid}/merge endpoint.
+// The source artist from the request body is merged into the artist identified by the URL.
+func (h *Handler) Merge(w http.ResponseWriter, r *http.Request) {
+	artist := MustGetArtist(r.Context())
+	var data schema.ArtistMerge
+	if err := render.Bind(r, &data); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	if data.Source == artist.UUID {
+		_ = render.Render(w, r, apierror.ValidationError("An artist cannot be merged into itself.", map[string]string{
+			"/source": "must be a different artist",
+		}))
+		return
+	}
+	if err := h.artistRepo.MergeArtists(r.Context(), &artist, data.Source); errors.Is(err, core.ErrNotFound) {
+		_ = render.Render(w, r, apierror.ValidationError("The source artist does not exist.", map[string]string{
+			"/source": "artist not found",
+		}))
+		return
+	} else if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not merge artists.", "uuid", artist.UUID, "source", data.Source, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	resp := schema.FromArtist(artist)
+	_ = render.Render(w, r, &resp)
+}
@@ -0,0 +1,183 @@
+//go:build database
+
+package artists
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/artist"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_Create(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/artists/")
+	testdata.Artist(t, db, "Queen", "The Queen")
+	url := "/v1/artists/"
+
+	t.Run("201 Created", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"name": "Earth, Wind & Fire", "aliases": ["EWF"]}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusCreated)
+		}
+		var a schema.Artist
+		if err := json.NewDecoder(resp.Body).Decode(&a); err != nil {
+			t.Errorf("POST %s responded with invalid artist schema: %s", url, err)
+			return
+		}
+		if a.UUID == uuid.Nil {
+			t.Errorf("POST %s responded with no artist UUID, expected non-nil UUID", url)
+		}
+		if a.Name != "Earth, Wind & Fire" || !slices.Equal(a.Aliases, []string{"EWF"}) {
+			t.Errorf("POST %s responded with name %q and aliases %q, expected %q and %q", url, a.Name, a.Aliases, "Earth, Wind & Fire", []string{"EWF"})
+		}
+	})
+
+	t.Run("400 Bad Request (Missing Content-Type)", test.MissingContentType(h, http.MethodPost, url, "application/json"))
+
+	t.Run("409 Conflict", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"name": "the queen"}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusConflict, apierror.TypeArtistNameConflict, nil)
+	})
+
+	t.Run("422 Unprocessable Entity", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"name": ""}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+}
+
+func TestHandler_Find(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/artists/")
+	testdata.Artist(t, db, "Queen")
+	testdata.Artist(t, db, "ABBA")
+	testdata.Artist(t, db, "David Bowie")
+	url := "/v1/artists/"
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		test.AssertPagination(t, resp, 0, 25, 3, 3)
+		var artists []schema.Artist
+		if err := json.NewDecoder(resp.Body).Decode(&artists); err != nil {
+			t.Errorf("GET %s responded with invalid artist list schema: %s", url, err)
+			return
+		}
+		if len(artists) != 3 || artists[0].Name != "ABBA" {
+			t.Errorf("GET %s responded with %v, expected 3 artists ordered by name", url, artists)
+		}
+	})
+	t.Run("400 Bad Request (Pagination)", test.InvalidPagination(h, http.MethodGet, url))
+}
+
+func TestHandler_Get(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/artists/")
+	queen := testdata.Artist(t, db, "Queen", "The Queen")
+
+	t.Run("200 OK", func(t *testing.T) {
+		url := fmt.Sprintf("/v1/artists/%s", queen.UUID)
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var a schema.Artist
+		if err := json.NewDecoder(resp.Body).Decode(&a); err != nil {
+			t.Errorf("GET %s responded with invalid artist schema: %s", url, err)
+			return
+		}
+		if a.UUID != queen.UUID || !slices.Equal(a.Aliases, []string{"The Queen"}) {
+			t.Errorf("GET %s responded with %v, expected artist %q with aliases %q", url, a, queen.UUID, queen.Aliases)
+		}
+	})
+	t.Run("400 Bad Request", test.InvalidUUID(h, http.MethodGet, "/v1/artists/"+testdata.InvalidUUID))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodGet, "/v1/artists/"+uuid.New().String(), http.StatusNotFound))
+}
+
+func TestHandler_Update(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/artists/")
+	queen := testdata.Artist(t, db, "Queen")
+	testdata.Artist(t, db, "ABBA")
+	url := fmt.Sprintf("/v1/artists/%s", queen.UUID)
+
+	t.Run("204 No Content", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"aliases": ["The Queen"]}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("PATCH %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
+		}
+		updated, _ := artist.NewDBRepository(nolog.Logger, db).GetArtist(context.TODO(), queen.UUID)
+		if updated.Name != queen.Name || !slices.Equal(updated.Aliases, []string{"The Queen"}) {
+			t.Errorf("PATCH %s produced name %q and aliases %q, expected %q and %q", url, updated.Name, updated.Aliases, queen.Name, []string{"The Queen"})
+		}
+	})
+	t.Run("409 Conflict", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"aliases": ["abba"]}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusConflict, apierror.TypeArtistNameConflict, nil)
+	})
+}
+
+func TestHandler_Merge(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/artists/")
+	queen := testdata.Artist(t, db, "Queen")
+	mercury := testdata.Artist(t, db, "Freddie Mercury", "Freddie")
+	url := fmt.Sprintf("/v1/artists/%s/merge", queen.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"source": %q}`, mercury.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var a schema.Artist
+		if err := json.NewDecoder(resp.Body).Decode(&a); err != nil {
+			t.Errorf("POST %s responded with invalid artist schema: %s", url, err)
+			return
+		}
+		if !slices.Contains(a.Aliases, "Freddie Mercury") || !slices.Contains(a.Aliases, "Freddie") {
+			t.Errorf("POST %s responded with aliases %q, expected the name and aliases of the source artist", url, a.Aliases)
+		}
+	})
+	t.Run("422 Unprocessable Entity (Self)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"source": %q}`, queen.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+	t.Run("422 Unprocessable Entity (Missing Source)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"source": %q}`, uuid.New())))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+}
@@ -0,0 +1,50 @@
+package artists
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core/artist"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Handler implements the /v1/artists endpoints.
+type Handler struct {
+	logger *slog.Logger
+	r      chi.Router
+
+	artistRepo artist.Repository
+}
+
+// NewHandler creates a new Handler instance using the specified repository.
+func NewHandler(
+	logger *slog.Logger,
+	artistRepo artist.Repository,
+) *Handler {
+	r := chi.NewRouter()
+	h := &Handler{
+		logger,
+		r,
+		artistRepo,
+	}
+
+	r.With(middleware.ContentTypeJSON, render.ContentTypeNegotiation("application/json")).Post("/", h.Create)
+	r.With(middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json")).Get("/", h.Find)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.UUID("uuid"))
+		r.Use(h.FetchArtist)
+		r.With(render.ContentTypeNegotiation("application/json")).Get("/{uuid}", h.Get)
+		r.With(middleware.ContentTypeJSON).Patch("/{uuid}", h.Update)
+		r.With(middleware.ContentTypeJSON, render.ContentTypeNegotiation("application/json")).Post("/{uuid}/merge", h.Merge)
+	})
+	return h
+}
+
+// ServeHTTP processes HTTP requests for h.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.r.ServeHTTP(w, r)
+}
@@ -0,0 +1,62 @@
+package artists
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// contextKey is the type for context keys used in this package.
+// This type is intentionally private.
+// Use the accessor functions instead to interact with context values.
+type contextKey int
+
+const (
+	// contextKeyInstance identifies an Artist instance in a context.
+	contextKeyInstance contextKey = iota
+)
+
+// SetArtist sets the artist instance in ctx.
+func SetArtist(ctx context.Context, artist model.Artist) context.Context {
+	return context.WithValue(ctx, contextKeyInstance, artist)
+}
+
+// GetArtist returns a model.Artist instance from the context.
+// If the context does not contain an artist instance, the second return value will be false.
+func GetArtist(ctx context.Context) (model.Artist, bool) {
+	artist, ok := ctx.Value(contextKeyInstance).(model.Artist)
+	return artist, ok
+}
+
+// MustGetArtist returns a model.Artist instance from the context.
+// In contrast to GetArtist this function panics if the context does not contain an artist instance.
+func MustGetArtist(ctx context.Context) model.Artist {
+	return ctx.Value(contextKeyInstance).(model.Artist)
+}
+
+// FetchArtist is a middleware that fetches the model.Artist instance identified by the request and stores it in the request context.
+func (h *Handler) FetchArtist(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := middleware.MustGetUUID(r.Context())
+		artist, err := h.artistRepo.GetArtist(r.Context(), id)
+		if errors.Is(err, core.ErrNotFound) {
+			_ = render.Render(w, r, apierror.ErrNotFound)
+			return
+		} else if err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not fetch artist.", "uuid", id, tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		ctx := SetArtist(r.Context(), artist)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+	return http.HandlerFunc(fn)
+}
@@ -9,7 +9,9 @@ import (
 
 	"github.com/Karaoke-Manager/karman/api/v1/dav/internal"
 	"github.com/Karaoke-Manager/karman/core/media"
+	"github.com/Karaoke-Manager/karman/core/playlist"
 	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/core/tag"
 )
 
 func init() {
@@ -30,16 +32,24 @@ type Handler struct {
 }
 
 // NewHandler creates a new Handler instance using the specified services.
+// prefix is the URL path at which the handler is mounted, such as "/v1/dav/".
+// The TXT files served by the handler are written in the specified dialect.
+// This allows different WebDAV mounts to serve different karaoke programs.
+// Playlists are served as UPL files in the Playlists folder.
+// The Tags folder contains a virtual folder for each tag with the songs that have the tag.
 func NewHandler(
 	logger *slog.Logger,
 	songRepo song.Repository,
 	songSvc song.Service,
+	playlistRepo playlist.Repository,
+	tagRepo tag.Repository,
 	mediaStore media.Store,
+	prefix string,
+	dialect song.Dialect,
 ) *Handler {
 	wh := &webdav.Handler{
-		// TODO: Make this configurable/dynamic
-		Prefix:     "/v1/dav/",
-		FileSystem: internal.NewFlatFS(logger, songRepo, songSvc, mediaStore),
+		Prefix:     prefix,
+		FileSystem: internal.NewFlatFS(logger, songRepo, songSvc, playlistRepo, tagRepo, mediaStore, dialect),
 		LockSystem: webdav.NewMemLS(),
 		Logger:     nil,
 	}
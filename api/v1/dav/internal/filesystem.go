@@ -6,6 +6,7 @@ import (
 	"io/fs"
 	"log/slog"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/google/uuid"
@@ -13,28 +14,44 @@ import (
 
 	"github.com/Karaoke-Manager/karman/core"
 	"github.com/Karaoke-Manager/karman/core/media"
+	"github.com/Karaoke-Manager/karman/core/playlist"
 	songsvc "github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/core/tag"
 	"github.com/Karaoke-Manager/karman/model"
 )
 
 // flatFS implements a [webdav.FileSystem] that serves songs in a flat hierarchy:
 // Each song is contained in a folder that contains the TXT file and the media files.
+// Folder and file names are generated by the song service.
+// Folder names always end with the UUID of the song in parentheses, which keeps them unique.
+// TXT files are written in the configured dialect.
+// In addition, the root directory contains a Playlists folder with the UPL files of all playlists
+// and a Tags folder with a virtual folder for each tag that contains the songs with the tag.
 type flatFS struct {
-	logger     *slog.Logger
-	songRepo   songsvc.Repository
-	songSvc    songsvc.Service
-	mediaStore media.Store
+	logger       *slog.Logger
+	songRepo     songsvc.Repository
+	songSvc      songsvc.Service
+	playlistRepo playlist.Repository
+	tagRepo      tag.Repository
+	mediaStore   media.Store
+	dialect      songsvc.Dialect
 }
 
 // NewFlatFS creates a new [webdav.FileSystem] that serves songs in a flat hierarchy:
 // The root directory contains a folder for each song which in turn contains all the song's files.
+// The TXT files of songs are written in the specified dialect.
+// Playlists are served as UPL files in the Playlists folder.
+// The songs of each tag are served in a virtual folder in the Tags folder.
 func NewFlatFS(
 	logger *slog.Logger,
 	songRepo songsvc.Repository,
 	songSvc songsvc.Service,
+	playlistRepo playlist.Repository,
+	tagRepo tag.Repository,
 	mediaStore media.Store,
+	dialect songsvc.Dialect,
 ) webdav.FileSystem {
-	return &flatFS{logger, songRepo, songSvc, mediaStore}
+	return &flatFS{logger, songRepo, songSvc, playlistRepo, tagRepo, mediaStore, dialect}
 }
 
 // Mkdir is not allowed.
@@ -59,11 +76,23 @@ func (s *flatFS) Rename(ctx context.Context, name, _ string) error {
 func (s *flatFS) find(ctx context.Context, name string) (node, error) {
 	name = strings.TrimSuffix(name, "/")
 	if name == "" {
-		return rootNode{}, nil
+		return rootNode{s.songSvc, s.playlistRepo, s.tagRepo, s.dialect}, nil
 	}
 
 	folder, filename, ok := strings.Cut(name, "/")
+	switch folder {
+	case playlistsFolder:
+		return s.findPlaylist(ctx, name, filename, ok)
+	case tagsFolder:
+		return s.findTag(ctx, name, filename, ok)
+	}
+	return s.findSong(ctx, name, folder, filename, ok, nil)
+}
 
+// findSong returns a node value for the specified file in a song folder.
+// If hasFile is false, the node of the song folder itself is returned.
+// If inTag is not nil, only songs with the tag are found.
+func (s *flatFS) findSong(ctx context.Context, name string, folder string, filename string, hasFile bool, inTag *model.Tag) (node, error) {
 	if !strings.HasSuffix(folder, ")") {
 		s.logger.WarnContext(ctx, "Tried to access unexpected WebDAV song.", "path", name)
 		return nil, fs.ErrNotExist
@@ -86,14 +115,25 @@ func (s *flatFS) find(ctx context.Context, name string) (node, error) {
 	} else if err != nil {
 		return nil, err
 	}
+	if song.Deleted() {
+		return nil, fs.ErrNotExist
+	}
+	if inTag != nil && !slices.ContainsFunc(song.Tags, func(t string) bool { return strings.EqualFold(t, inTag.Name) }) {
+		return nil, fs.ErrNotExist
+	}
 	s.songSvc.Prepare(ctx, &song)
 
-	if !ok {
-		return songNode(song), nil
+	if !hasFile {
+		return songNode{song, s.dialect}, nil
 	}
 
 	if filename == song.TxtFileName {
-		return txtNode(song), nil
+		return txtNode{song, s.dialect}, nil
+	}
+	for i, lyricsName := range song.LyricsFileNames {
+		if filename == lyricsName {
+			return lyricsNode{song, i}, nil
+		}
 	}
 
 	var file *model.File
@@ -118,6 +158,64 @@ func (s *flatFS) find(ctx context.Context, name string) (node, error) {
 	return nil, fs.ErrNotExist
 }
 
+// findPlaylist returns a node value for the specified file in the Playlists folder.
+// If hasFile is false, the node of the Playlists folder itself is returned.
+func (s *flatFS) findPlaylist(ctx context.Context, name string, filename string, hasFile bool) (node, error) {
+	if !hasFile {
+		return playlistsNode{s.playlistRepo, s.songSvc}, nil
+	}
+	stem, ok := strings.CutSuffix(filename, ").upl")
+	idx := strings.LastIndex(stem, " (")
+	if !ok || idx < 0 {
+		s.logger.WarnContext(ctx, "Tried to access unexpected WebDAV playlist.", "path", name)
+		return nil, fs.ErrNotExist
+	}
+	id, err := uuid.Parse(stem[idx+2:])
+	if err != nil {
+		s.logger.WarnContext(ctx, "Tried to access unexpected WebDAV playlist.", "path", name)
+		return nil, fs.ErrNotExist
+	}
+	pl, err := s.playlistRepo.GetPlaylist(ctx, id)
+	if errors.Is(err, core.ErrNotFound) {
+		return nil, fs.ErrNotExist
+	} else if err != nil {
+		return nil, err
+	}
+	return newUPLNode(ctx, pl, s.songRepo, s.songSvc)
+}
+
+// findTag returns a node value for the specified path in the Tags folder.
+// If hasFile is false, the node of the Tags folder itself is returned.
+// Paths inside a tag folder are resolved like song folders in the root directory.
+func (s *flatFS) findTag(ctx context.Context, name string, path string, hasFile bool) (node, error) {
+	if !hasFile {
+		return tagsNode{s.tagRepo, s.songSvc, s.dialect}, nil
+	}
+	folder, rest, ok := strings.Cut(path, "/")
+	stem, isFolder := strings.CutSuffix(folder, ")")
+	idx := strings.LastIndex(stem, " (")
+	if !isFolder || idx < 0 {
+		s.logger.WarnContext(ctx, "Tried to access unexpected WebDAV tag.", "path", name)
+		return nil, fs.ErrNotExist
+	}
+	id, err := uuid.Parse(stem[idx+2:])
+	if err != nil {
+		s.logger.WarnContext(ctx, "Tried to access unexpected WebDAV tag.", "path", name)
+		return nil, fs.ErrNotExist
+	}
+	t, err := s.tagRepo.GetTag(ctx, id)
+	if errors.Is(err, core.ErrNotFound) {
+		return nil, fs.ErrNotExist
+	} else if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return tagNode{t, s.songSvc, s.dialect}, nil
+	}
+	songFolder, filename, hasSongFile := strings.Cut(rest, "/")
+	return s.findSong(ctx, name, songFolder, filename, hasSongFile, &t)
+}
+
 // Stat returns a [fs.FileInfo] for the specified file name, or an error.
 func (s *flatFS) Stat(ctx context.Context, name string) (fs.FileInfo, error) {
 	ref, err := s.find(ctx, name)
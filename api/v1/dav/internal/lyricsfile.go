@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"io/fs"
+	"os"
+	"time"
+
+	"golang.org/x/net/webdav"
+
+	"github.com/Karaoke-Manager/karman/core/media"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// lyricsNode represents the LRC file of a single player of a song.
+type lyricsNode struct {
+	song   model.Song
+	player int // index into song.LyricsFileNames
+}
+
+// lrc renders the lyrics of n in the LRC format.
+func (n lyricsNode) lrc() []byte {
+	notes := n.song.NotesP1
+	if n.player == 1 {
+		notes = n.song.NotesP2
+	}
+	b := &bytes.Buffer{}
+	_ = song.WriteLRC(b, n.song, song.Lyrics(n.song, notes), false)
+	return b.Bytes()
+}
+
+func (n lyricsNode) Stat() (fs.FileInfo, error) {
+	return n, nil
+}
+
+func (n lyricsNode) Name() string {
+	return n.song.LyricsFileNames[n.player]
+}
+
+func (n lyricsNode) Size() int64 {
+	return int64(len(n.lrc()))
+}
+
+func (n lyricsNode) Mode() fs.FileMode {
+	return 0444
+}
+
+func (n lyricsNode) ModTime() time.Time {
+	return n.song.UpdatedAt
+}
+
+func (n lyricsNode) IsDir() bool {
+	return false
+}
+
+func (n lyricsNode) Sys() any {
+	return nil
+}
+
+func (n lyricsNode) ContentType(context.Context) (string, error) {
+	return "application/x-lrc", nil
+}
+
+func (n lyricsNode) Open(_ context.Context, _ song.Repository, _ media.Store, flag int) (webdav.File, error) {
+	if flag&(os.O_RDWR|os.O_WRONLY) != 0 {
+		return nil, fs.ErrPermission
+	}
+	return &lyricsFile{
+		node: n,
+		r:    bytes.NewReader(n.lrc()),
+	}, nil
+}
+
+// lyricsFile represents a lyricsNode that has been opened for reading.
+type lyricsFile struct {
+	node lyricsNode
+	r    *bytes.Reader
+}
+
+func (f *lyricsFile) Close() error {
+	return nil
+}
+
+func (f *lyricsFile) Read(b []byte) (int, error) {
+	return f.r.Read(b)
+}
+
+func (f *lyricsFile) Seek(offset int64, whence int) (int64, error) {
+	return f.r.Seek(offset, whence)
+}
+
+func (f *lyricsFile) Write([]byte) (n int, err error) {
+	return 0, fs.ErrPermission
+}
+
+func (f *lyricsFile) Readdir(int) ([]fs.FileInfo, error) {
+	return nil, fs.ErrInvalid
+}
+
+func (f *lyricsFile) Stat() (fs.FileInfo, error) {
+	return f.node, nil
+}
@@ -0,0 +1,232 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"golang.org/x/net/webdav"
+
+	"github.com/Karaoke-Manager/karman/core/media"
+	"github.com/Karaoke-Manager/karman/core/playlist"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/filename"
+)
+
+// playlistsFolder is the name of the folder containing the playlists.
+// UltraStar Deluxe expects playlists in a folder of this name.
+const playlistsFolder = "Playlists"
+
+// playlistsNode represents the folder containing the UPL files of all playlists.
+type playlistsNode struct {
+	playlistRepo playlist.Repository
+	songSvc      song.Service
+}
+
+func (n playlistsNode) Stat() (fs.FileInfo, error) {
+	return n, nil
+}
+
+func (playlistsNode) Name() string {
+	return playlistsFolder
+}
+
+func (playlistsNode) Size() int64 {
+	return 0
+}
+
+func (playlistsNode) Mode() fs.FileMode {
+	return fs.ModeDir | 0555
+}
+
+func (playlistsNode) ModTime() time.Time {
+	return time.Now()
+}
+
+func (playlistsNode) IsDir() bool {
+	return true
+}
+
+func (playlistsNode) Sys() any {
+	return nil
+}
+
+func (n playlistsNode) Open(ctx context.Context, songRepo song.Repository, _ media.Store, flag int) (webdav.File, error) {
+	if flag&(os.O_RDWR|os.O_WRONLY) != 0 {
+		return nil, fs.ErrInvalid
+	}
+	return &playlistsDir{ctx: ctx, node: n, songRepo: songRepo}, nil
+}
+
+// playlistsDir is a playlistsNode that has been opened for reading.
+type playlistsDir struct {
+	ctx      context.Context
+	pos      int64
+	node     playlistsNode
+	songRepo song.Repository
+}
+
+func (*playlistsDir) Close() error {
+	return nil
+}
+
+func (*playlistsDir) Read([]byte) (int, error) {
+	return 0, fs.ErrInvalid
+}
+
+func (*playlistsDir) Write([]byte) (n int, err error) {
+	return 0, fs.ErrInvalid
+}
+
+func (f *playlistsDir) Seek(offset int64, whence int) (int64, error) {
+	npos := f.pos
+	switch whence {
+	case io.SeekStart:
+		npos = offset
+	case io.SeekCurrent:
+		npos += offset
+	case io.SeekEnd:
+		_, total, err := f.node.playlistRepo.FindPlaylists(f.ctx, 0, 0)
+		if err != nil {
+			return f.pos, err
+		}
+		npos = total + offset
+	default:
+		npos = -1
+	}
+	if npos < 0 {
+		return 0, fs.ErrInvalid
+	}
+	f.pos = npos
+	return f.pos, nil
+}
+
+func (f *playlistsDir) Readdir(count int) ([]fs.FileInfo, error) {
+	if count <= 0 {
+		count = -1
+	}
+	playlists, total, err := f.node.playlistRepo.FindPlaylists(f.ctx, count, f.pos)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]fs.FileInfo, 0, len(playlists))
+	for _, pl := range playlists {
+		n, err := newUPLNode(f.ctx, pl, f.songRepo, f.node.songSvc)
+		if err != nil {
+			return infos, err
+		}
+		infos = append(infos, n)
+		f.pos++
+	}
+	if count > 0 && f.pos >= total {
+		return infos, io.EOF
+	}
+	return infos, nil
+}
+
+func (f *playlistsDir) Stat() (fs.FileInfo, error) {
+	return f.node, nil
+}
+
+// uplNode represents the UPL file of a playlist.
+// The file is rendered when the node is created.
+type uplNode struct {
+	playlist model.Playlist
+	data     []byte
+}
+
+// newUPLNode renders the UPL file of pl.
+// The songs of the playlist are fetched from songRepo.
+func newUPLNode(ctx context.Context, pl model.Playlist, songRepo song.Repository, songSvc song.Service) (uplNode, error) {
+	upl, err := playlist.Export(ctx, pl, songRepo, songSvc)
+	if err != nil {
+		return uplNode{}, err
+	}
+	b := &bytes.Buffer{}
+	if err = playlist.WriteUPL(b, upl); err != nil {
+		return uplNode{}, err
+	}
+	return uplNode{pl, b.Bytes()}, nil
+}
+
+// uplFileName returns the name of the UPL file of pl.
+// Like song folders, the name ends with the UUID of the playlist in parentheses, which keeps it unique.
+func uplFileName(pl model.Playlist) string {
+	return filename.Sanitize(pl.Name) + " (" + pl.UUID.String() + ").upl"
+}
+
+func (n uplNode) Stat() (fs.FileInfo, error) {
+	return n, nil
+}
+
+func (n uplNode) Name() string {
+	return uplFileName(n.playlist)
+}
+
+func (n uplNode) Size() int64 {
+	return int64(len(n.data))
+}
+
+func (n uplNode) Mode() fs.FileMode {
+	return 0444
+}
+
+func (n uplNode) ModTime() time.Time {
+	return n.playlist.UpdatedAt
+}
+
+func (n uplNode) IsDir() bool {
+	return false
+}
+
+func (n uplNode) Sys() any {
+	return nil
+}
+
+func (n uplNode) ContentType(context.Context) (string, error) {
+	return "text/plain; charset=utf-8", nil
+}
+
+func (n uplNode) Open(_ context.Context, _ song.Repository, _ media.Store, flag int) (webdav.File, error) {
+	if flag&(os.O_RDWR|os.O_WRONLY) != 0 {
+		return nil, fs.ErrPermission
+	}
+	return &uplFile{
+		node: n,
+		r:    bytes.NewReader(n.data),
+	}, nil
+}
+
+// uplFile represents an uplNode that has been opened for reading.
+type uplFile struct {
+	node uplNode
+	r    *bytes.Reader
+}
+
+func (f *uplFile) Close() error {
+	return nil
+}
+
+func (f *uplFile) Read(b []byte) (int, error) {
+	return f.r.Read(b)
+}
+
+func (f *uplFile) Seek(offset int64, whence int) (int64, error) {
+	return f.r.Seek(offset, whence)
+}
+
+func (f *uplFile) Write([]byte) (n int, err error) {
+	return 0, fs.ErrPermission
+}
+
+func (f *uplFile) Readdir(int) ([]fs.FileInfo, error) {
+	return nil, fs.ErrInvalid
+}
+
+func (f *uplFile) Stat() (fs.FileInfo, error) {
+	return f.node, nil
+}
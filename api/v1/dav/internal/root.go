@@ -11,11 +11,24 @@ import (
 	"golang.org/x/net/webdav"
 
 	"github.com/Karaoke-Manager/karman/core/media"
+	"github.com/Karaoke-Manager/karman/core/playlist"
 	songsvc "github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/core/tag"
 )
 
 // rootNode represents the root directory of a flatFS.
-type rootNode struct{}
+// The songSvc is used to generate the folder names of songs.
+// The TXT files of all songs are written in the specified dialect.
+// The Playlists and Tags folders are listed before the song folders.
+type rootNode struct {
+	songSvc      songsvc.Service
+	playlistRepo playlist.Repository
+	tagRepo      tag.Repository
+	dialect      songsvc.Dialect
+}
+
+// rootFolders is the number of folders in the root directory that precede the song folders.
+const rootFolders = 2
 
 func (n rootNode) Stat() (fs.FileInfo, error) {
 	return n, nil
@@ -45,17 +58,19 @@ func (rootNode) Sys() any {
 	return nil
 }
 
-func (rootNode) Open(ctx context.Context, songRepo songsvc.Repository, _ media.Store, flag int) (webdav.File, error) {
+func (n rootNode) Open(ctx context.Context, songRepo songsvc.Repository, _ media.Store, flag int) (webdav.File, error) {
 	if flag&(os.O_RDWR|os.O_WRONLY) != 0 {
 		return nil, fs.ErrInvalid
 	}
-	return &rootDir{ctx: ctx, songRepo: songRepo}, nil
+	return &rootDir{ctx: ctx, node: n, songRepo: songRepo}, nil
 }
 
 // rootDir is a rootNode that has been opened for reading.
+// Position 0 is the Playlists folder, position 1 is the Tags folder, all other positions are songs.
 type rootDir struct {
 	ctx      context.Context
 	pos      int64
+	node     rootNode
 	songRepo songsvc.Repository
 }
 
@@ -81,11 +96,11 @@ func (f *rootDir) Seek(offset int64, whence int) (int64, error) {
 	case io.SeekCurrent:
 		npos += offset
 	case io.SeekEnd:
-		_, total, err := f.songRepo.FindSongs(f.ctx, 0, 0)
+		_, total, err := f.songRepo.FindSongs(f.ctx, songsvc.Filter{}, 0, 0)
 		if err != nil {
 			return f.pos, err
 		}
-		npos = total + offset
+		npos = total + rootFolders + offset
 	default:
 		npos = -1
 	}
@@ -100,22 +115,37 @@ func (f *rootDir) Readdir(count int) ([]fs.FileInfo, error) {
 	if count <= 0 {
 		count = -1
 	}
+	infos := make([]fs.FileInfo, 0, max(count, 1))
+	for f.pos < rootFolders && count != 0 {
+		if f.pos == 0 {
+			infos = append(infos, playlistsNode{f.node.playlistRepo, f.node.songSvc})
+		} else {
+			infos = append(infos, tagsNode{f.node.tagRepo, f.node.songSvc, f.node.dialect})
+		}
+		f.pos++
+		if count > 0 {
+			count--
+		}
+	}
+	if count == 0 {
+		return infos, nil
+	}
 	// FIXME: We should probably paginate database request for large databases or provide a more hierarchical FS
-	songs, total, err := f.songRepo.FindSongs(f.ctx, count, f.pos)
-	infos := make([]fs.FileInfo, len(songs))
-	for i, song := range songs {
-		infos[i] = songNode(song)
+	songs, total, err := f.songRepo.FindSongs(f.ctx, songsvc.Filter{}, count, f.pos-rootFolders)
+	for _, song := range songs {
+		f.node.songSvc.Prepare(f.ctx, &song)
+		infos = append(infos, songNode{song, f.node.dialect})
 	}
 	f.pos += int64(len(songs))
 	if err != nil {
 		return infos, err
 	}
-	if count > 0 && f.pos >= total {
+	if count > 0 && f.pos >= total+rootFolders {
 		return infos, io.EOF
 	}
 	return infos, nil
 }
 
 func (f *rootDir) Stat() (fs.FileInfo, error) {
-	return rootNode{}, nil
+	return f.node, nil
 }
@@ -2,7 +2,6 @@ package internal
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"io/fs"
 	"os"
@@ -16,14 +15,19 @@ import (
 )
 
 // songNode represents the directory for a song.
-type songNode model.Song
+// The song must have been prepared by the song service so that its folder and file names are set.
+// The TXT file of the song is written in the specified dialect.
+type songNode struct {
+	song    model.Song
+	dialect song.Dialect
+}
 
 func (n songNode) Stat() (fs.FileInfo, error) {
 	return n, nil
 }
 
 func (n songNode) Name() string {
-	return fmt.Sprintf("%s - %s (%s)", n.Artist, n.Title, n.UUID)
+	return n.song.FolderName
 }
 
 func (n songNode) Size() int64 {
@@ -35,7 +39,7 @@ func (n songNode) Mode() fs.FileMode {
 }
 
 func (n songNode) ModTime() time.Time {
-	return n.UpdatedAt
+	return n.song.UpdatedAt
 }
 
 func (n songNode) IsDir() bool {
@@ -52,14 +56,14 @@ func (n songNode) Open(_ context.Context, _ song.Repository, _ media.Store, flag
 	}
 	return &songDir{
 		pos:  0,
-		song: model.Song(n),
+		node: n,
 	}, nil
 }
 
 // songDir represents a songNode that has been opened for reading.
 type songDir struct {
 	pos  int64
-	song model.Song
+	node songNode
 }
 
 func (*songDir) Close() error {
@@ -83,18 +87,19 @@ func (f *songDir) Seek(offset int64, whence int) (int64, error) {
 		npos += offset
 	case io.SeekEnd:
 		total := int64(1) // txt file always exists
-		if f.song.AudioFile != nil {
+		if f.node.song.AudioFile != nil {
 			total++
 		}
-		if f.song.CoverFile != nil {
+		if f.node.song.CoverFile != nil {
 			total++
 		}
-		if f.song.VideoFile != nil {
+		if f.node.song.VideoFile != nil {
 			total++
 		}
-		if f.song.BackgroundFile != nil {
+		if f.node.song.BackgroundFile != nil {
 			total++
 		}
+		total += int64(len(f.node.song.LyricsFileNames))
 		npos = total + offset
 	default:
 		npos = -1
@@ -110,53 +115,60 @@ func (f *songDir) Readdir(count int) ([]fs.FileInfo, error) {
 	if count <= 0 {
 		count = -1
 	}
-	infos := make([]fs.FileInfo, 0, 5)
+	infos := make([]fs.FileInfo, 0, 5+len(f.node.song.LyricsFileNames))
 	fileIndex := int64(0)
 	if f.pos == fileIndex && len(infos) != count {
-		infos = append(infos, txtNode(f.song))
+		infos = append(infos, txtNode{f.node.song, f.node.dialect})
 		f.pos++
 	}
 	fileIndex++
-	if f.song.AudioFile != nil {
+	if f.node.song.AudioFile != nil {
 		if f.pos == fileIndex && len(infos) != count {
 			infos = append(infos, &mediaNode{
-				name: f.song.AudioFileName,
-				file: f.song.AudioFile,
+				name: f.node.song.AudioFileName,
+				file: f.node.song.AudioFile,
 			})
 			f.pos++
 		}
 		fileIndex++
 	}
-	if f.song.CoverFile != nil {
+	if f.node.song.CoverFile != nil {
 		if f.pos == fileIndex && len(infos) != count {
 			infos = append(infos, &mediaNode{
-				name: f.song.CoverFileName,
-				file: f.song.CoverFile,
+				name: f.node.song.CoverFileName,
+				file: f.node.song.CoverFile,
 			})
 			f.pos++
 		}
 		fileIndex++
 	}
-	if f.song.VideoFile != nil {
+	if f.node.song.VideoFile != nil {
 		if f.pos == fileIndex && len(infos) != count {
 			infos = append(infos, &mediaNode{
-				name: f.song.VideoFileName,
-				file: f.song.VideoFile,
+				name: f.node.song.VideoFileName,
+				file: f.node.song.VideoFile,
 			})
 			f.pos++
 		}
 		fileIndex++
 	}
-	if f.song.BackgroundFile != nil {
+	if f.node.song.BackgroundFile != nil {
 		if f.pos == fileIndex && len(infos) != count {
 			infos = append(infos, &mediaNode{
-				name: f.song.BackgroundFileName,
-				file: f.song.BackgroundFile,
+				name: f.node.song.BackgroundFileName,
+				file: f.node.song.BackgroundFile,
 			})
 			f.pos++
 		}
 		fileIndex++
 	}
+	for i := range f.node.song.LyricsFileNames {
+		if f.pos == fileIndex && len(infos) != count {
+			infos = append(infos, lyricsNode{f.node.song, i})
+			f.pos++
+		}
+		fileIndex++
+	}
 	if count > 0 && f.pos >= fileIndex {
 		return infos, io.EOF
 	}
@@ -164,5 +176,5 @@ func (f *songDir) Readdir(count int) ([]fs.FileInfo, error) {
 }
 
 func (f *songDir) Stat() (fs.FileInfo, error) {
-	return songNode(f.song), nil
+	return f.node, nil
 }
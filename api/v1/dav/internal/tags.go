@@ -0,0 +1,250 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"golang.org/x/net/webdav"
+
+	"github.com/Karaoke-Manager/karman/core/media"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/core/tag"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/filename"
+)
+
+// tagsFolder is the name of the folder containing a virtual folder for each tag.
+const tagsFolder = "Tags"
+
+// tagsNode represents the folder containing the folders of all tags.
+// The songSvc is used to generate the folder names of songs.
+// The TXT files of all songs are written in the specified dialect.
+type tagsNode struct {
+	tagRepo tag.Repository
+	songSvc song.Service
+	dialect song.Dialect
+}
+
+func (n tagsNode) Stat() (fs.FileInfo, error) {
+	return n, nil
+}
+
+func (tagsNode) Name() string {
+	return tagsFolder
+}
+
+func (tagsNode) Size() int64 {
+	return 0
+}
+
+func (tagsNode) Mode() fs.FileMode {
+	return fs.ModeDir | 0555
+}
+
+func (tagsNode) ModTime() time.Time {
+	return time.Now()
+}
+
+func (tagsNode) IsDir() bool {
+	return true
+}
+
+func (tagsNode) Sys() any {
+	return nil
+}
+
+func (n tagsNode) Open(ctx context.Context, _ song.Repository, _ media.Store, flag int) (webdav.File, error) {
+	if flag&(os.O_RDWR|os.O_WRONLY) != 0 {
+		return nil, fs.ErrInvalid
+	}
+	return &tagsDir{ctx: ctx, node: n}, nil
+}
+
+// tagsDir is a tagsNode that has been opened for reading.
+type tagsDir struct {
+	ctx  context.Context
+	pos  int64
+	node tagsNode
+}
+
+func (*tagsDir) Close() error {
+	return nil
+}
+
+func (*tagsDir) Read([]byte) (int, error) {
+	return 0, fs.ErrInvalid
+}
+
+func (*tagsDir) Write([]byte) (n int, err error) {
+	return 0, fs.ErrInvalid
+}
+
+func (f *tagsDir) Seek(offset int64, whence int) (int64, error) {
+	npos := f.pos
+	switch whence {
+	case io.SeekStart:
+		npos = offset
+	case io.SeekCurrent:
+		npos += offset
+	case io.SeekEnd:
+		_, total, err := f.node.tagRepo.FindTags(f.ctx, 0, 0)
+		if err != nil {
+			return f.pos, err
+		}
+		npos = total + offset
+	default:
+		npos = -1
+	}
+	if npos < 0 {
+		return 0, fs.ErrInvalid
+	}
+	f.pos = npos
+	return f.pos, nil
+}
+
+func (f *tagsDir) Readdir(count int) ([]fs.FileInfo, error) {
+	if count <= 0 {
+		count = -1
+	}
+	tags, total, err := f.node.tagRepo.FindTags(f.ctx, count, f.pos)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]fs.FileInfo, 0, len(tags))
+	for _, t := range tags {
+		infos = append(infos, tagNode{t, f.node.songSvc, f.node.dialect})
+		f.pos++
+	}
+	if count > 0 && f.pos >= total {
+		return infos, io.EOF
+	}
+	return infos, nil
+}
+
+func (f *tagsDir) Stat() (fs.FileInfo, error) {
+	return f.node, nil
+}
+
+// tagNode represents the virtual folder of a tag.
+// The folder contains the folders of all songs that have the tag.
+type tagNode struct {
+	tag     model.Tag
+	songSvc song.Service
+	dialect song.Dialect
+}
+
+// tagFolderName returns the name of the folder of t.
+// Like song folders, the name ends with the UUID of the tag in parentheses, which keeps it unique.
+func tagFolderName(t model.Tag) string {
+	return filename.Sanitize(t.Name) + " (" + t.UUID.String() + ")"
+}
+
+func (n tagNode) Stat() (fs.FileInfo, error) {
+	return n, nil
+}
+
+func (n tagNode) Name() string {
+	return tagFolderName(n.tag)
+}
+
+func (tagNode) Size() int64 {
+	return 0
+}
+
+func (tagNode) Mode() fs.FileMode {
+	return fs.ModeDir | 0555
+}
+
+func (n tagNode) ModTime() time.Time {
+	return n.tag.UpdatedAt
+}
+
+func (tagNode) IsDir() bool {
+	return true
+}
+
+func (tagNode) Sys() any {
+	return nil
+}
+
+func (n tagNode) Open(ctx context.Context, songRepo song.Repository, _ media.Store, flag int) (webdav.File, error) {
+	if flag&(os.O_RDWR|os.O_WRONLY) != 0 {
+		return nil, fs.ErrInvalid
+	}
+	return &tagDir{ctx: ctx, node: n, songRepo: songRepo}, nil
+}
+
+// tagDir is a tagNode that has been opened for reading.
+type tagDir struct {
+	ctx      context.Context
+	pos      int64
+	node     tagNode
+	songRepo song.Repository
+}
+
+// filter returns the song filter matching the songs in f.
+func (f *tagDir) filter() song.Filter {
+	return song.Filter{Tags: []string{f.node.tag.Name}}
+}
+
+func (*tagDir) Close() error {
+	return nil
+}
+
+func (*tagDir) Read([]byte) (int, error) {
+	return 0, fs.ErrInvalid
+}
+
+func (*tagDir) Write([]byte) (n int, err error) {
+	return 0, fs.ErrInvalid
+}
+
+func (f *tagDir) Seek(offset int64, whence int) (int64, error) {
+	npos := f.pos
+	switch whence {
+	case io.SeekStart:
+		npos = offset
+	case io.SeekCurrent:
+		npos += offset
+	case io.SeekEnd:
+		_, total, err := f.songRepo.FindSongs(f.ctx, f.filter(), 0, 0)
+		if err != nil {
+			return f.pos, err
+		}
+		npos = total + offset
+	default:
+		npos = -1
+	}
+	if npos < 0 {
+		return 0, fs.ErrInvalid
+	}
+	f.pos = npos
+	return f.pos, nil
+}
+
+func (f *tagDir) Readdir(count int) ([]fs.FileInfo, error) {
+	if count <= 0 {
+		count = -1
+	}
+	songs, total, err := f.songRepo.FindSongs(f.ctx, f.filter(), count, f.pos)
+	infos := make([]fs.FileInfo, 0, len(songs))
+	for _, sng := range songs {
+		f.node.songSvc.Prepare(f.ctx, &sng)
+		infos = append(infos, songNode{sng, f.node.dialect})
+	}
+	f.pos += int64(len(songs))
+	if err != nil {
+		return infos, err
+	}
+	if count > 0 && f.pos >= total {
+		return infos, io.EOF
+	}
+	return infos, nil
+}
+
+func (f *tagDir) Stat() (fs.FileInfo, error) {
+	return f.node, nil
+}
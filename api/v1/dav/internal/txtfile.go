@@ -5,10 +5,8 @@ import (
 	"context"
 	"io/fs"
 	"os"
-	"strings"
 	"time"
 
-	"codello.dev/ultrastar/txt"
 	"golang.org/x/net/webdav"
 
 	"github.com/Karaoke-Manager/karman/core/media"
@@ -17,23 +15,31 @@ import (
 )
 
 // txtNode represents the TXT file for a song.
-type txtNode model.Song
+type txtNode struct {
+	song    model.Song
+	dialect song.Dialect
+}
+
+// txt renders the TXT file of n.
+func (n txtNode) txt() []byte {
+	b := &bytes.Buffer{}
+	if err := song.WriteTxt(b, n.song, n.dialect); err != nil {
+		// TODO: Log error, should not happen
+		return nil
+	}
+	return b.Bytes()
+}
 
 func (n txtNode) Stat() (fs.FileInfo, error) {
 	return n, nil
 }
 
 func (n txtNode) Name() string {
-	return n.TxtFileName
+	return n.song.TxtFileName
 }
 
 func (n txtNode) Size() int64 {
-	b := &strings.Builder{}
-	if err := txt.WriteSong(b, n.Song); err != nil {
-		// TODO: Log error, should not happen
-		return 0
-	}
-	return int64(b.Len())
+	return int64(len(n.txt()))
 }
 
 func (n txtNode) Mode() fs.FileMode {
@@ -41,7 +47,7 @@ func (n txtNode) Mode() fs.FileMode {
 }
 
 func (n txtNode) ModTime() time.Time {
-	return n.UpdatedAt
+	return n.song.UpdatedAt
 }
 
 func (n txtNode) IsDir() bool {
@@ -60,17 +66,15 @@ func (n txtNode) Open(_ context.Context, _ song.Repository, _ media.Store, flag
 	if flag&(os.O_RDWR|os.O_WRONLY) != 0 {
 		return nil, fs.ErrPermission
 	}
-	b := &bytes.Buffer{}
-	_ = txt.WriteSong(b, n.Song)
 	return &txtFile{
-		song: model.Song(n),
-		r:    bytes.NewReader(b.Bytes()),
+		node: n,
+		r:    bytes.NewReader(n.txt()),
 	}, nil
 }
 
 // txtFile represents a txtNode that has been opened for reading.
 type txtFile struct {
-	song model.Song
+	node txtNode
 	r    *bytes.Reader
 }
 
@@ -95,5 +99,5 @@ func (f *txtFile) Readdir(int) ([]fs.FileInfo, error) {
 }
 
 func (f *txtFile) Stat() (fs.FileInfo, error) {
-	return txtNode(f.song), nil
+	return f.node, nil
 }
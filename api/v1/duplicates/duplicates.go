@@ -0,0 +1,141 @@
+package duplicates
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/duplicate"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Find implements the GET /v1/duplicates endpoint.
+// Duplicates are detected on every request, so the results reflect the current state of the library.
+// Songs in uploads or in the trash are not included.
+func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
+	pagination := middleware.MustGetPagination(r.Context())
+	fps, err := h.duplicateRepo.FindFingerprints(r.Context())
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list song fingerprints.", tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	clusters := duplicate.NewDetector(fps).Clusters()
+
+	resp := schema.List[*schema.DuplicateCluster]{
+		Items:  make([]*schema.DuplicateCluster, 0, pagination.Limit),
+		Offset: pagination.Offset,
+		Limit:  pagination.RequestLimit,
+		Total:  int64(len(clusters)),
+	}
+	for i := pagination.Offset; i < int64(len(clusters)) && i < pagination.Offset+int64(pagination.Limit); i++ {
+		songs := make([]model.Song, len(clusters[i].Songs))
+		for j, id := range clusters[i].Songs {
+			if songs[j], err = h.songRepo.GetSong(r.Context(), id); err != nil {
+				h.logger.ErrorContext(r.Context(), "Could not fetch song.", "uuid", id, tint.Err(err))
+				_ = render.Render(w, r, apierror.ErrInternalServerError)
+				return
+			}
+		}
+		item := schema.FromDuplicateCluster(clusters[i], songs)
+		resp.Items = append(resp.Items, &item)
+	}
+	_ = render.Render(w, r, &resp)
+}
+
+// Merge implements the POST /v1/duplicates/merge endpoint.
+// The TXT data of the kept song is not modified.
+// Its media files are replaced by the selected media files of the duplicates.
+// Afterward the duplicates are moved to the trash or deleted permanently.
+func (h *Handler) Merge(w http.ResponseWriter, r *http.Request) {
+	var data schema.DuplicateMerge
+	if err := render.Bind(r, &data); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+
+	errs := make(map[string]string)
+	keep, err := h.songRepo.GetLibrarySong(r.Context(), data.Keep)
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	m := duplicate.Merge{Song: keep, Purge: data.Mode == schema.MergeModeDelete}
+	songs := make(map[uuid.UUID]model.Song, len(data.Duplicates)+1)
+	if err == nil {
+		songs[keep.UUID] = keep
+	} else {
+		errs["/keep"] = "song not found"
+	}
+	for i, id := range data.Duplicates {
+		pointer := fmt.Sprintf("/duplicates/%d", i)
+		if _, seen := songs[id]; seen || id == data.Keep {
+			errs[pointer] = "song is merged more than once"
+			continue
+		}
+		s, err := h.songRepo.GetLibrarySong(r.Context(), id)
+		if errors.Is(err, core.ErrNotFound) {
+			errs[pointer] = "song not found"
+			continue
+		} else if err != nil {
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		songs[id] = s
+		m.Duplicates = append(m.Duplicates, s)
+	}
+	media := []struct {
+		pointer string
+		source  *uuid.UUID
+		target  *uuid.UUID
+		file    func(model.Song) *model.File
+	}{
+		{"/audio", data.Audio, &m.Audio, func(s model.Song) *model.File { return s.AudioFile }},
+		{"/cover", data.Cover, &m.Cover, func(s model.Song) *model.File { return s.CoverFile }},
+		{"/video", data.Video, &m.Video, func(s model.Song) *model.File { return s.VideoFile }},
+		{"/background", data.Background, &m.Background, func(s model.Song) *model.File { return s.BackgroundFile }},
+	}
+	for _, f := range media {
+		if f.source == nil {
+			continue
+		}
+		s, ok := songs[*f.source]
+		if !ok {
+			errs[f.pointer] = "song is not merged"
+		} else if f.file(s) == nil {
+			errs[f.pointer] = "song has no such media file"
+		}
+		*f.target = *f.source
+	}
+	if len(errs) > 0 {
+		_ = render.Render(w, r, apierror.ValidationError("The songs cannot be merged.", errs))
+		return
+	}
+
+	merged, err := m.Apply(r.Context(), h.songRepo)
+	if errors.Is(err, core.ErrPreconditionFailed) {
+		// the kept song has been modified concurrently
+		_ = render.Render(w, r, apierror.PreconditionFailed(""))
+		return
+	} else if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not merge songs.", "uuid", keep.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	h.publish(r.Context(), event.SongUpdated(merged))
+	for _, d := range m.Duplicates {
+		h.publish(r.Context(), event.SongDeleted(d.UUID))
+	}
+	w.Header().Set("ETag", middleware.ETag(merged.UpdatedAt))
+	s := schema.FromSong(merged)
+	_ = render.Render(w, r, &s)
+}
@@ -0,0 +1,132 @@
+//go:build database
+
+package duplicates
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_Find(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/duplicates/")
+	song1 := testdata.SimpleSong(t, db)
+	song2 := testdata.SimpleSong(t, db)
+	testdata.SongWithAudio(t, db)
+	testdata.DeletedSong(t, db)
+	testdata.SongWithUpload(t, db)
+	url := "/v1/duplicates/"
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		test.AssertPagination(t, resp, 0, 25, 1, 1)
+		var clusters []schema.DuplicateCluster
+		if err := json.NewDecoder(resp.Body).Decode(&clusters); err != nil {
+			t.Fatalf("GET %s responded with invalid duplicate list schema: %s", url, err)
+		}
+		if len(clusters) != 1 || len(clusters[0].Songs) != 2 {
+			t.Fatalf("GET %s responded with %v, expected a single cluster with 2 songs", url, clusters)
+		}
+		if clusters[0].Songs[0].UUID != song1.UUID || clusters[0].Songs[1].UUID != song2.UUID {
+			t.Errorf("GET %s responded with songs %s and %s, expected %s and %s", url, clusters[0].Songs[0].UUID, clusters[0].Songs[1].UUID, song1.UUID, song2.UUID)
+		}
+		if expected := []model.DuplicateReason{model.DuplicateReasonTitle}; !slices.Equal(clusters[0].Reasons, expected) {
+			t.Errorf("GET %s responded with reasons %v, expected %v", url, clusters[0].Reasons, expected)
+		}
+	})
+	t.Run("400 Bad Request (Pagination)", test.InvalidPagination(h, http.MethodGet, url))
+}
+
+func TestHandler_Merge(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/duplicates/")
+	songRepo := song.NewDBRepository(nolog.Logger, db)
+	url := "/v1/duplicates/merge"
+
+	t.Run("200 OK (trash)", func(t *testing.T) {
+		keep := testdata.SimpleSong(t, db)
+		other := testdata.SongWithAudio(t, db)
+		body := fmt.Sprintf(`{"keep": %q, "duplicates": [%q], "audio": %q}`, keep.UUID, other.UUID, other.UUID)
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var s schema.Song
+		if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+			t.Fatalf("POST %s responded with invalid song schema: %s", url, err)
+		}
+		if s.UUID != keep.UUID || s.Title != keep.Title {
+			t.Errorf("POST %s responded with song %s (%q), expected %s (%q)", url, s.UUID, s.Title, keep.UUID, keep.Title)
+		}
+		if s.Audio == nil {
+			t.Errorf("POST %s responded with a song without audio, expected the audio of the duplicate", url)
+		}
+		deleted, err := songRepo.GetSong(context.TODO(), other.UUID)
+		if err != nil {
+			t.Fatalf("GetSong(ctx, %q) returned an unexpected error: %s", other.UUID, err)
+		}
+		if !deleted.Deleted() {
+			t.Errorf("POST %s did not move the duplicate to the trash", url)
+		}
+	})
+	t.Run("200 OK (delete)", func(t *testing.T) {
+		keep := testdata.SimpleSong(t, db)
+		other := testdata.SimpleSong(t, db)
+		body := fmt.Sprintf(`{"keep": %q, "duplicates": [%q], "mode": "delete"}`, keep.UUID, other.UUID)
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		if _, err := songRepo.GetSong(context.TODO(), other.UUID); !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("GetSong(ctx, %q) after POST %s returned %v, expected ErrNotFound", other.UUID, url, err)
+		}
+	})
+	t.Run("400 Bad Request (Missing Content-Type)", test.MissingContentType(h, http.MethodPost, url, "application/json"))
+	t.Run("422 Unprocessable Entity (Mode)", func(t *testing.T) {
+		body := fmt.Sprintf(`{"keep": %q, "duplicates": [%q], "mode": "shred"}`, uuid.New(), uuid.New())
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+	t.Run("422 Unprocessable Entity", func(t *testing.T) {
+		keep := testdata.SimpleSong(t, db)
+		deleted := testdata.DeletedSong(t, db)
+		other := testdata.SimpleSong(t, db)
+		body := fmt.Sprintf(`{"keep": %q, "duplicates": [%q, %q], "video": %q}`, keep.UUID, deleted.UUID, other.UUID, other.UUID)
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+
+		if s, err := songRepo.GetSong(context.TODO(), other.UUID); err != nil || s.Deleted() {
+			t.Errorf("POST %s removed a duplicate of an invalid merge", url)
+		}
+	})
+}
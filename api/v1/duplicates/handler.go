@@ -0,0 +1,61 @@
+package duplicates
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core/duplicate"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Handler implements the /v1/duplicates endpoints.
+type Handler struct {
+	logger *slog.Logger
+	r      chi.Router
+
+	duplicateRepo duplicate.Repository
+	songRepo      song.Repository
+	events        event.Bus
+}
+
+// NewHandler creates a new Handler instance using the specified repositories.
+// Changes to songs caused by merges are published to events.
+func NewHandler(
+	logger *slog.Logger,
+	duplicateRepo duplicate.Repository,
+	songRepo song.Repository,
+	events event.Bus,
+) *Handler {
+	r := chi.NewRouter()
+	h := &Handler{
+		logger,
+		r,
+		duplicateRepo,
+		songRepo,
+		events,
+	}
+
+	r.With(middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json")).Get("/", h.Find)
+	r.With(middleware.ContentTypeJSON, render.ContentTypeNegotiation("application/json")).Post("/merge", h.Merge)
+	return h
+}
+
+// ServeHTTP processes HTTP requests for h.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.r.ServeHTTP(w, r)
+}
+
+// publish sends e to the event bus.
+// Events are informational only, so a failure to publish an event does not fail the request.
+func (h *Handler) publish(ctx context.Context, e event.Event) {
+	if err := h.events.Publish(ctx, e); err != nil {
+		h.logger.WarnContext(ctx, "Could not publish event.", "type", e.Type, tint.Err(err))
+	}
+}
@@ -0,0 +1,42 @@
+package events
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Handler implements the /v1/events endpoints.
+type Handler struct {
+	logger *slog.Logger
+	r      chi.Router
+
+	events event.Bus
+
+	// keepAlive is the interval in which comments are sent to keep idle connections open.
+	keepAlive time.Duration
+}
+
+// NewHandler creates a new Handler instance using the specified event bus.
+func NewHandler(logger *slog.Logger, events event.Bus) *Handler {
+	r := chi.NewRouter()
+	h := &Handler{
+		logger,
+		r,
+		events,
+		30 * time.Second,
+	}
+
+	r.With(render.ContentTypeNegotiation("text/event-stream")).Get("/", h.Stream)
+	return h
+}
+
+// ServeHTTP processes HTTP requests for h.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.r.ServeHTTP(w, r)
+}
@@ -0,0 +1,83 @@
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Stream implements the GET /v1/events endpoint.
+// The endpoint sends events as Server-Sent Events until the client disconnects.
+// Events can be filtered by specifying one or more topic query parameters.
+// Events of private topics are only sent if the exact topic is requested (see event.Visible).
+// The stream ends when the server shuts down (see middleware.GetShutdown).
+func (h *Handler) Stream(w http.ResponseWriter, r *http.Request) {
+	topics := r.URL.Query()["topic"]
+	rc := http.NewResponseController(w)
+
+	events, err := h.events.Subscribe(r.Context(), topics...)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not subscribe to events.", "topics", topics, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Prevent reverse proxies such as nginx from buffering the response.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err = rc.Flush(); err != nil {
+		h.logger.ErrorContext(r.Context(), "Event stream does not support flushing.", tint.Err(err))
+		return
+	}
+
+	ticker := time.NewTicker(h.keepAlive)
+	defer ticker.Stop()
+	shutdown := middleware.GetShutdown(r.Context())
+	for {
+		select {
+		case <-shutdown:
+			return
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if !event.Visible(e.Topic, topics) {
+				continue
+			}
+			err = writeEvent(w, e)
+		case <-ticker.C:
+			_, err = io.WriteString(w, ": keep-alive\n\n")
+		}
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			// The client most likely went away.
+			h.logger.DebugContext(r.Context(), "Could not write to event stream.", tint.Err(err))
+			return
+		}
+	}
+}
+
+// writeEvent writes e to w using the Server-Sent Events format.
+func writeEvent(w io.Writer, e event.Event) error {
+	// The data field must not contain line breaks.
+	var data bytes.Buffer
+	if err := json.Compact(&data, e.Data); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data.Bytes())
+	return err
+}
@@ -0,0 +1,105 @@
+package events
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+)
+
+// readEvent reads a single Server-Sent Event from r and returns its fields.
+func readEvent(t *testing.T, r *bufio.Reader) map[string]string {
+	fields := make(map[string]string)
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("could not read event stream: %s", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if len(fields) == 0 {
+				continue
+			}
+			return fields
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		name, value, _ := strings.Cut(line, ": ")
+		fields[name] = value
+	}
+}
+
+func TestHandler_Stream(t *testing.T) {
+	t.Parallel()
+
+	bus := event.NewMemBus()
+	server := httptest.NewServer(NewHandler(nolog.Logger, bus))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/?topic=songs", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("GET /?topic=songs returned an unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /?topic=songs responded with status code %d, expected %d", resp.StatusCode, http.StatusOK)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Errorf("GET /?topic=songs responded with Content-Type %q, expected %q", contentType, "text/event-stream")
+	}
+
+	id := uuid.New()
+	_ = bus.Publish(ctx, event.UploadProgress(model.Upload{Model: model.Model{UUID: uuid.New()}}))
+	_ = bus.Publish(ctx, event.SongDeleted(id))
+
+	fields := readEvent(t, bufio.NewReader(resp.Body))
+	if fields["event"] != string(event.TypeSongDeleted) {
+		t.Errorf("GET /?topic=songs sent event %q, expected %q", fields["event"], event.TypeSongDeleted)
+	}
+	if expected := `{"uuid":"` + id.String() + `"}`; fields["data"] != expected {
+		t.Errorf("GET /?topic=songs sent data %s, expected %s", fields["data"], expected)
+	}
+	if fields["id"] == "" {
+		t.Errorf("GET /?topic=songs sent event without id, expected an id")
+	}
+}
+
+func TestHandler_Stream_Shutdown(t *testing.T) {
+	t.Parallel()
+
+	shutdown := make(chan struct{})
+	server := httptest.NewUnstartedServer(NewHandler(nolog.Logger, event.NewMemBus()))
+	server.Config.BaseContext = func(net.Listener) context.Context {
+		return middleware.SetShutdown(context.Background(), shutdown)
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("GET / returned an unexpected error: %s", err)
+	}
+	defer resp.Body.Close()
+	close(shutdown)
+	if _, err = io.ReadAll(resp.Body); err != nil {
+		t.Errorf("GET / did not end the stream cleanly on shutdown: %s", err)
+	}
+}
@@ -6,12 +6,35 @@ import (
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/Karaoke-Manager/karman/api/v1/artists"
 	"github.com/Karaoke-Manager/karman/api/v1/dav"
+	"github.com/Karaoke-Manager/karman/api/v1/duplicates"
+	"github.com/Karaoke-Manager/karman/api/v1/events"
+	"github.com/Karaoke-Manager/karman/api/v1/headers"
+	"github.com/Karaoke-Manager/karman/api/v1/playlists"
+	"github.com/Karaoke-Manager/karman/api/v1/reviews"
+	"github.com/Karaoke-Manager/karman/api/v1/scores"
+	"github.com/Karaoke-Manager/karman/api/v1/sessions"
 	"github.com/Karaoke-Manager/karman/api/v1/songs"
+	"github.com/Karaoke-Manager/karman/api/v1/tags"
 	"github.com/Karaoke-Manager/karman/api/v1/uploads"
+	"github.com/Karaoke-Manager/karman/api/v1/webhooks"
+	"github.com/Karaoke-Manager/karman/core/artist"
+	"github.com/Karaoke-Manager/karman/core/batch"
+	"github.com/Karaoke-Manager/karman/core/duplicate"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/header"
 	"github.com/Karaoke-Manager/karman/core/media"
+	"github.com/Karaoke-Manager/karman/core/playlist"
+	"github.com/Karaoke-Manager/karman/core/review"
+	"github.com/Karaoke-Manager/karman/core/revision"
+	"github.com/Karaoke-Manager/karman/core/score"
+	"github.com/Karaoke-Manager/karman/core/session"
 	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/core/tag"
 	"github.com/Karaoke-Manager/karman/core/upload"
+	"github.com/Karaoke-Manager/karman/core/variant"
+	"github.com/Karaoke-Manager/karman/core/webhook"
 )
 
 // Handler implements the /v1 API namespace.
@@ -21,39 +44,146 @@ type Handler struct {
 
 // NewHandler creates a new handler using the specified services.
 // This function will create the required sub-handlers automatically.
+// strictPreconditions indicates whether mutating song requests must include an If-Match header.
+// davDialect is the TXT dialect of songs served via WebDAV at /v1/dav.
+// davMounts maps names to the dialects of additional WebDAV mounts at /v1/dav-<name>.
 func NewHandler(
 	logger *slog.Logger,
 	songRepo song.Repository,
 	songSvc song.Service,
+	revisionRepo revision.Repository,
+	artistRepo artist.Repository,
+	tagRepo tag.Repository,
+	headerRepo header.Repository,
+	variantRepo variant.Repository,
+	reviewRepo review.Repository,
+	playlistRepo playlist.Repository,
+	sessionRepo session.Repository,
+	scoreRepo score.Repository,
+	duplicateRepo duplicate.Repository,
 	mediaSvc media.Service,
 	mediaStore media.Store,
 	uploadRepo upload.Repository,
 	uploadStore upload.Store,
+	uploadSvc upload.Service,
+	webhookRepo webhook.Repository,
+	batchSvc batch.Service,
+	batchRepo batch.Repository,
+	eventBus event.Bus,
+	strictPreconditions bool,
+	davDialect song.Dialect,
+	davMounts map[string]song.Dialect,
 ) *Handler {
 	uploadsHandler := uploads.NewHandler(
 		logger,
 		uploadRepo,
 		uploadStore,
+		uploadSvc,
+		songRepo,
+		revisionRepo,
+		eventBus,
 	)
 	songsHandler := songs.NewHandler(
 		logger,
 		songRepo,
 		songSvc,
+		revisionRepo,
+		headerRepo,
+		variantRepo,
+		reviewRepo,
 		mediaStore,
 		mediaSvc,
+		batchSvc,
+		batchRepo,
+		eventBus,
+		strictPreconditions,
+	)
+	artistsHandler := artists.NewHandler(
+		logger,
+		artistRepo,
+	)
+	tagsHandler := tags.NewHandler(
+		logger,
+		tagRepo,
+	)
+	headersHandler := headers.NewHandler(
+		logger,
+		headerRepo,
+	)
+	reviewsHandler := reviews.NewHandler(
+		logger,
+		reviewRepo,
+	)
+	playlistsHandler := playlists.NewHandler(
+		logger,
+		playlistRepo,
+		songRepo,
+		songSvc,
+	)
+	sessionsHandler := sessions.NewHandler(
+		logger,
+		sessionRepo,
+		songRepo,
+		eventBus,
+	)
+	scoresHandler := scores.NewHandler(
+		logger,
+		scoreRepo,
+		songRepo,
+		songSvc,
+	)
+	duplicatesHandler := duplicates.NewHandler(
+		logger,
+		duplicateRepo,
+		songRepo,
+		eventBus,
 	)
 	davHandler := dav.NewHandler(
 		logger,
 		songRepo,
 		songSvc,
+		playlistRepo,
+		tagRepo,
 		mediaStore,
+		"/v1/dav/",
+		davDialect,
+	)
+	webhooksHandler := webhooks.NewHandler(
+		logger,
+		webhookRepo,
+	)
+	eventsHandler := events.NewHandler(
+		logger,
+		eventBus,
 	)
 
 	r := chi.NewRouter()
 	h := &Handler{r}
 	r.Mount("/uploads", uploadsHandler)
 	r.Mount("/songs", songsHandler)
+	r.Mount("/artists", artistsHandler)
+	r.Mount("/tags", tagsHandler)
+	r.Mount("/headers", headersHandler)
+	r.Mount("/review-queue", reviewsHandler)
+	r.Mount("/playlists", playlistsHandler)
+	r.Mount("/sessions", sessionsHandler)
+	r.Mount("/scores", scoresHandler)
+	r.Mount("/duplicates", duplicatesHandler)
 	r.Mount("/dav", davHandler)
+	for name, dialect := range davMounts {
+		r.Mount("/dav-"+name, dav.NewHandler(
+			logger,
+			songRepo,
+			songSvc,
+			playlistRepo,
+			tagRepo,
+			mediaStore,
+			"/v1/dav-"+name+"/",
+			dialect,
+		))
+	}
+	r.Mount("/webhooks", webhooksHandler)
+	r.Mount("/events", eventsHandler)
 	return h
 }
 
@@ -0,0 +1,119 @@
+package headers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/header"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Create implements the POST /v1/headers endpoint.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var data schema.CustomHeaderRW
+	if err := render.Bind(r, &data); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	ch := model.CustomHeader{}
+	data.Apply(&ch)
+	if err := h.headerRepo.CreateHeader(r.Context(), &ch); errors.Is(err, core.ErrConflict) {
+		_ = render.Render(w, r, apierror.HeaderNameConflict())
+		return
+	} else if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not create custom header.", tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	render.SetStatus(r, http.StatusCreated)
+	resp := schema.FromCustomHeader(ch)
+	_ = render.Render(w, r, &resp)
+}
+
+// Find implements the GET /v1/headers endpoint.
+func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
+	pagination := middleware.MustGetPagination(r.Context())
+	headers, total, err := h.headerRepo.FindHeaders(r.Context(), pagination.Limit, pagination.Offset)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list custom headers.", "limit", pagination.Limit, "offset", pagination.Offset, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+
+	resp := schema.List[*schema.CustomHeader]{
+		Items:  make([]*schema.CustomHeader, len(headers)),
+		Offset: pagination.Offset,
+		Limit:  pagination.RequestLimit,
+		Total:  total,
+	}
+	for i, ch := range headers {
+		s := schema.FromCustomHeader(ch)
+		resp.Items[i] = &s
+	}
+	_ = render.Render(w, r, &resp)
+}
+
+// Get implements the GET /v1/headers/{uuid} endpoint.
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	ch := MustGetHeader(r.Context())
+	resp := schema.FromCustomHeader(ch)
+	_ = render.Render(w, r, &resp)
+}
+
+// Update implements the PATCH /v1/headers/{uuid} endpoint.
+// Custom tags of songs are not modified, use the migrate-headers command to rename legacy keys.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	ch := MustGetHeader(r.Context())
+	update := schema.FromCustomHeader(ch)
+	if err := render.Bind(r, &update); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	update.Apply(&ch)
+	if err := h.headerRepo.UpdateHeader(r.Context(), &ch); errors.Is(err, core.ErrConflict) {
+		_ = render.Render(w, r, apierror.HeaderNameConflict())
+		return
+	} else if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not update custom header.", "uuid", ch.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	_ = render.NoContent(w, r)
+}
+
+// Delete implements the DELETE /v1/headers/{uuid} endpoint.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := middleware.MustGetUUID(r.Context())
+	if _, err := h.headerRepo.DeleteHeader(r.Context(), id); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not delete custom header.", "uuid", id, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	_ = render.NoContent(w, r)
+}
+
+// Report implements the GET /v1/headers/report endpoint.
+// The report lists custom tag keys used by library songs that are unknown or not spelled canonically.
+func (h *Handler) Report(w http.ResponseWriter, r *http.Request) {
+	registry, err := header.LoadRegistry(r.Context(), h.headerRepo)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not load custom header registry.", tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	counts, err := h.headerRepo.CountCustomTags(r.Context())
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not count custom tags.", tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	resp := schema.FromKeyReports(registry.Report(counts))
+	_ = render.Render(w, r, &resp)
+}
@@ -0,0 +1,195 @@
+//go:build database
+
+package headers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/header"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_Create(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/headers/")
+	testdata.CustomHeader(t, db, "ALBUM", model.HeaderTypeString, "ALBUMN")
+	url := "/v1/headers/"
+
+	t.Run("201 Created", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"name": "#RATING", "type": "int", "pattern": "^[1-5]$", "aliases": ["SCORE"]}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusCreated)
+		}
+		var ch schema.CustomHeader
+		if err := json.NewDecoder(resp.Body).Decode(&ch); err != nil {
+			t.Errorf("POST %s responded with invalid header schema: %s", url, err)
+			return
+		}
+		if ch.UUID == uuid.Nil {
+			t.Errorf("POST %s responded with no header UUID, expected non-nil UUID", url)
+		}
+		if ch.Name != "RATING" || ch.Type != model.HeaderTypeInt {
+			t.Errorf("POST %s responded with name %q and type %q, expected %q and %q", url, ch.Name, ch.Type, "RATING", model.HeaderTypeInt)
+		}
+	})
+
+	t.Run("400 Bad Request (Missing Content-Type)", test.MissingContentType(h, http.MethodPost, url, "application/json"))
+
+	t.Run("409 Conflict", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"name": "albumn"}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusConflict, apierror.TypeHeaderNameConflict, nil)
+	})
+
+	for name, body := range map[string]string{
+		"Type":    `{"name": "EDITION", "type": "float"}`,
+		"Pattern": `{"name": "EDITION", "pattern": "[a-z"}`,
+	} {
+		t.Run("422 Unprocessable Entity ("+name+")", func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+			resp := test.DoRequest(h, r) //nolint:bodyclose
+			test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+		})
+	}
+}
+
+func TestHandler_Find(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/headers/")
+	testdata.CustomHeader(t, db, "RATING", model.HeaderTypeInt)
+	testdata.CustomHeader(t, db, "ALBUM", model.HeaderTypeString)
+	url := "/v1/headers/"
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertPagination(t, resp, 0, 25, 2, 2)
+		var headers []schema.CustomHeader
+		if err := json.NewDecoder(resp.Body).Decode(&headers); err != nil {
+			t.Errorf("GET %s responded with invalid header list schema: %s", url, err)
+			return
+		}
+		if len(headers) != 2 || headers[0].Name != "ALBUM" {
+			t.Errorf("GET %s responded with %v, expected 2 headers ordered by name", url, headers)
+		}
+	})
+	t.Run("400 Bad Request (Pagination)", test.InvalidPagination(h, http.MethodGet, url))
+}
+
+func TestHandler_Get(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/headers/")
+	album := testdata.CustomHeader(t, db, "ALBUM", model.HeaderTypeString, "ALBUMN")
+
+	t.Run("200 OK", func(t *testing.T) {
+		url := fmt.Sprintf("/v1/headers/%s", album.UUID)
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var ch schema.CustomHeader
+		if err := json.NewDecoder(resp.Body).Decode(&ch); err != nil {
+			t.Errorf("GET %s responded with invalid header schema: %s", url, err)
+			return
+		}
+		if ch.UUID != album.UUID || len(ch.Aliases) != 1 {
+			t.Errorf("GET %s responded with %v, expected header %q with 1 alias", url, ch, album.UUID)
+		}
+	})
+	t.Run("400 Bad Request", test.InvalidUUID(h, http.MethodGet, "/v1/headers/"+testdata.InvalidUUID))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodGet, "/v1/headers/"+uuid.New().String(), http.StatusNotFound))
+}
+
+func TestHandler_Update(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/headers/")
+	rating := testdata.CustomHeader(t, db, "RATING", model.HeaderTypeString)
+	testdata.CustomHeader(t, db, "ALBUM", model.HeaderTypeString)
+	url := fmt.Sprintf("/v1/headers/%s", rating.UUID)
+
+	t.Run("204 No Content", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"type": "int"}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("PATCH %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
+		}
+		updated, _ := header.NewDBRepository(nolog.Logger, db).GetHeader(context.TODO(), rating.UUID)
+		if updated.Name != rating.Name || updated.Type != model.HeaderTypeInt {
+			t.Errorf("PATCH %s produced name %q and type %q, expected %q and %q", url, updated.Name, updated.Type, rating.Name, model.HeaderTypeInt)
+		}
+	})
+	t.Run("409 Conflict", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"aliases": ["Album"]}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusConflict, apierror.TypeHeaderNameConflict, nil)
+	})
+}
+
+func TestHandler_Delete(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/headers/")
+	album := testdata.CustomHeader(t, db, "ALBUM", model.HeaderTypeString)
+	url := fmt.Sprintf("/v1/headers/%s", album.UUID)
+
+	t.Run("204 No Content", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodDelete, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("DELETE %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
+		}
+		if _, err := header.NewDBRepository(nolog.Logger, db).GetHeader(context.TODO(), album.UUID); !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("DELETE %s did not delete the header, GetHeader returned %v", url, err)
+		}
+	})
+	t.Run("400 Bad Request", test.InvalidUUID(h, http.MethodDelete, "/v1/headers/"+testdata.InvalidUUID))
+}
+
+func TestHandler_Report(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/headers/")
+	testdata.CustomHeader(t, db, "ALBUM", model.HeaderTypeString, "ALBUMN")
+	song := testdata.SimpleSong(t, db)
+	if _, err := db.Exec(context.TODO(), `UPDATE songs SET extra = '{"ALBUMN": "Hits", "FOO": "bar"}'::JSONB WHERE uuid = $1`, song.UUID); err != nil {
+		t.Fatalf("could not set custom tags: %s", err)
+	}
+	url := "/v1/headers/report"
+
+	r := httptest.NewRequest(http.MethodGet, url, nil)
+	resp := test.DoRequest(h, r) //nolint:bodyclose
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+	}
+	var report schema.CustomTagsReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		t.Errorf("GET %s responded with invalid report schema: %s", url, err)
+		return
+	}
+	expected := []schema.CustomTagReport{{Key: "ALBUMN", Songs: 1, Canonical: "ALBUM"}, {Key: "FOO", Songs: 1}}
+	if len(report.Keys) != 2 || report.Keys[0] != expected[0] || report.Keys[1] != expected[1] {
+		t.Errorf("GET %s responded with %v, expected %v", url, report.Keys, expected)
+	}
+}
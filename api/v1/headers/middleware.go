@@ -0,0 +1,62 @@
+package headers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// contextKey is the type for context keys used in this package.
+// This type is intentionally private.
+// Use the accessor functions instead to interact with context values.
+type contextKey int
+
+const (
+	// contextKeyInstance identifies a CustomHeader instance in a context.
+	contextKeyInstance contextKey = iota
+)
+
+// SetHeader sets the custom header instance in ctx.
+func SetHeader(ctx context.Context, header model.CustomHeader) context.Context {
+	return context.WithValue(ctx, contextKeyInstance, header)
+}
+
+// GetHeader returns a model.CustomHeader instance from the context.
+// If the context does not contain a header instance, the second return value will be false.
+func GetHeader(ctx context.Context) (model.CustomHeader, bool) {
+	header, ok := ctx.Value(contextKeyInstance).(model.CustomHeader)
+	return header, ok
+}
+
+// MustGetHeader returns a model.CustomHeader instance from the context.
+// In contrast to GetHeader this function panics if the context does not contain a header instance.
+func MustGetHeader(ctx context.Context) model.CustomHeader {
+	return ctx.Value(contextKeyInstance).(model.CustomHeader)
+}
+
+// FetchHeader is a middleware that fetches the model.CustomHeader instance identified by the request and stores it in the request context.
+func (h *Handler) FetchHeader(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := middleware.MustGetUUID(r.Context())
+		header, err := h.headerRepo.GetHeader(r.Context(), id)
+		if errors.Is(err, core.ErrNotFound) {
+			_ = render.Render(w, r, apierror.ErrNotFound)
+			return
+		} else if err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not fetch custom header.", "uuid", id, tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		ctx := SetHeader(r.Context(), header)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+	return http.HandlerFunc(fn)
+}
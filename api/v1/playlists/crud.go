@@ -0,0 +1,143 @@
+package playlists
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/mediatype"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Create implements the POST /v1/playlists endpoint.
+// Playlists can be created from JSON or imported from a UPL file.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	if !mediatype.MustParse(r.Header.Get("Content-Type")).EqualsType(mediatype.ApplicationJSON) {
+		h.importUPL(w, r, model.Playlist{})
+		return
+	}
+	var data schema.PlaylistRW
+	if err := render.Bind(r, &data); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	if !h.validateSongs(w, r, data.Songs) {
+		return
+	}
+	playlist := model.Playlist{}
+	data.Apply(&playlist)
+	if err := h.playlistRepo.CreatePlaylist(r.Context(), &playlist); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not create playlist.", tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	render.SetStatus(r, http.StatusCreated)
+	resp := schema.FromPlaylist(playlist)
+	_ = render.Render(w, r, &resp)
+}
+
+// Find implements the GET /v1/playlists endpoint.
+func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
+	pagination := middleware.MustGetPagination(r.Context())
+	playlists, total, err := h.playlistRepo.FindPlaylists(r.Context(), pagination.Limit, pagination.Offset)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list playlists.", "limit", pagination.Limit, "offset", pagination.Offset, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+
+	resp := schema.List[*schema.Playlist]{
+		Items:  make([]*schema.Playlist, len(playlists)),
+		Offset: pagination.Offset,
+		Limit:  pagination.RequestLimit,
+		Total:  total,
+	}
+	for i, playlist := range playlists {
+		s := schema.FromPlaylist(playlist)
+		resp.Items[i] = &s
+	}
+	_ = render.Render(w, r, &resp)
+}
+
+// Get implements the GET /v1/playlists/{uuid} endpoint.
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	playlist := MustGetPlaylist(r.Context())
+	resp := schema.FromPlaylist(playlist)
+	_ = render.Render(w, r, &resp)
+}
+
+// Update implements the PATCH /v1/playlists/{uuid} endpoint.
+// This endpoint is used to rename a playlist as well as to add, remove and reorder its songs.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	playlist := MustGetPlaylist(r.Context())
+	update := schema.FromPlaylist(playlist)
+	if err := render.Bind(r, &update); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	if !h.validateSongs(w, r, update.Songs) {
+		return
+	}
+	update.Apply(&playlist)
+	if err := h.playlistRepo.UpdatePlaylist(r.Context(), &playlist); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not update playlist.", "uuid", playlist.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	_ = render.NoContent(w, r)
+}
+
+// Delete implements the DELETE /v1/playlists/{uuid} endpoint.
+// Deleting a playlist does not delete its songs.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := middleware.MustGetUUID(r.Context())
+	if _, err := h.playlistRepo.DeletePlaylist(r.Context(), id); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not delete playlist.", "uuid", id, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	_ = render.NoContent(w, r)
+}
+
+// validateSongs makes sure that all songs exist in the library.
+// Songs in uploads or in the trash cannot be added to playlists.
+// If a song is invalid, an error is rendered and false is returned.
+func (h *Handler) validateSongs(w http.ResponseWriter, r *http.Request, songs []uuid.UUID) bool {
+	errs := make(map[string]string)
+	for i, id := range songs {
+		ok, err := h.songExists(r.Context(), id)
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not fetch song.", "uuid", id, tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return false
+		}
+		if !ok {
+			errs[fmt.Sprintf("/songs/%d", i)] = "song not found"
+		}
+	}
+	if len(errs) > 0 {
+		_ = render.Render(w, r, apierror.ValidationError("Some songs do not exist.", errs))
+		return false
+	}
+	return true
+}
+
+// songExists reports whether the song with the specified UUID is a song in the library.
+func (h *Handler) songExists(ctx context.Context, id uuid.UUID) (bool, error) {
+	song, err := h.songRepo.GetSong(ctx, id)
+	if errors.Is(err, core.ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return !song.Deleted() && !song.InUpload, nil
+}
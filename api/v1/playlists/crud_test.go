@@ -0,0 +1,166 @@
+//go:build database
+
+package playlists
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/playlist"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_Create(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/playlists/")
+	song := testdata.SimpleSong(t, db)
+	deleted := testdata.DeletedSong(t, db)
+	url := "/v1/playlists/"
+
+	t.Run("201 Created", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"name": "90s Night", "songs": [%q, %q]}`, song.UUID, song.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusCreated)
+		}
+		var p schema.Playlist
+		if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+			t.Errorf("POST %s responded with invalid playlist schema: %s", url, err)
+			return
+		}
+		if p.UUID == uuid.Nil {
+			t.Errorf("POST %s responded with no playlist UUID, expected non-nil UUID", url)
+		}
+		if p.Name != "90s Night" || !slices.Equal(p.Songs, []uuid.UUID{song.UUID, song.UUID}) {
+			t.Errorf("POST %s responded with name %q and songs %v, expected %q and %v", url, p.Name, p.Songs, "90s Night", []uuid.UUID{song.UUID, song.UUID})
+		}
+	})
+	t.Run("400 Bad Request (Missing Content-Type)", test.MissingContentType(h, http.MethodPost, url, "application/json", "text/plain", "text/x-ultrastar-playlist"))
+	t.Run("422 Unprocessable Entity (Name)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"name": ""}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+	t.Run("422 Unprocessable Entity (Songs)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"name": "Foo", "songs": [%q, %q]}`, uuid.New(), deleted.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+}
+
+func TestHandler_Find(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/playlists/")
+	testdata.Playlist(t, db, "Party")
+	testdata.Playlist(t, db, "Duets only")
+	url := "/v1/playlists/"
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		test.AssertPagination(t, resp, 0, 25, 2, 2)
+		var playlists []schema.Playlist
+		if err := json.NewDecoder(resp.Body).Decode(&playlists); err != nil {
+			t.Errorf("GET %s responded with invalid playlist list schema: %s", url, err)
+			return
+		}
+		if len(playlists) != 2 || playlists[0].Name != "Duets only" {
+			t.Errorf("GET %s responded with %v, expected 2 playlists ordered by name", url, playlists)
+		}
+	})
+	t.Run("400 Bad Request (Pagination)", test.InvalidPagination(h, http.MethodGet, url))
+}
+
+func TestHandler_Get(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/playlists/")
+	song := testdata.SimpleSong(t, db)
+	party := testdata.Playlist(t, db, "Party", song.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		url := fmt.Sprintf("/v1/playlists/%s", party.UUID)
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var p schema.Playlist
+		if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+			t.Errorf("GET %s responded with invalid playlist schema: %s", url, err)
+			return
+		}
+		if p.UUID != party.UUID || !slices.Equal(p.Songs, party.Songs) {
+			t.Errorf("GET %s responded with %v, expected playlist %q with songs %v", url, p, party.UUID, party.Songs)
+		}
+	})
+	t.Run("400 Bad Request", test.InvalidUUID(h, http.MethodGet, "/v1/playlists/"+testdata.InvalidUUID))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodGet, "/v1/playlists/"+uuid.New().String(), http.StatusNotFound))
+}
+
+func TestHandler_Update(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/playlists/")
+	song1 := testdata.SimpleSong(t, db)
+	song2 := testdata.SimpleSong(t, db)
+	party := testdata.Playlist(t, db, "Party", song1.UUID, song2.UUID)
+	url := fmt.Sprintf("/v1/playlists/%s", party.UUID)
+
+	t.Run("204 No Content", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(fmt.Sprintf(`{"name": "Party Hits", "songs": [%q, %q]}`, song2.UUID, song1.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("PATCH %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
+		}
+		updated, _ := playlist.NewDBRepository(nolog.Logger, db).GetPlaylist(context.TODO(), party.UUID)
+		expected := []uuid.UUID{song2.UUID, song1.UUID}
+		if updated.Name != "Party Hits" || !slices.Equal(updated.Songs, expected) {
+			t.Errorf("PATCH %s produced name %q and songs %v, expected %q and %v", url, updated.Name, updated.Songs, "Party Hits", expected)
+		}
+	})
+	t.Run("422 Unprocessable Entity", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(fmt.Sprintf(`{"songs": [%q]}`, uuid.New())))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+}
+
+func TestHandler_Delete(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/playlists/")
+	party := testdata.Playlist(t, db, "Party")
+	url := fmt.Sprintf("/v1/playlists/%s", party.UUID)
+
+	t.Run("204 No Content", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			r := httptest.NewRequest(http.MethodDelete, url, nil)
+			resp := test.DoRequest(h, r) //nolint:bodyclose
+			if resp.StatusCode != http.StatusNoContent {
+				t.Errorf("DELETE %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
+			}
+		}
+		if _, err := playlist.NewDBRepository(nolog.Logger, db).GetPlaylist(context.TODO(), party.UUID); err == nil {
+			t.Errorf("DELETE %s did not delete the playlist", url)
+		}
+	})
+	t.Run("400 Bad Request", test.InvalidUUID(h, http.MethodDelete, "/v1/playlists/"+testdata.InvalidUUID))
+}
@@ -0,0 +1,63 @@
+package playlists
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core/playlist"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Handler implements the /v1/playlists endpoints.
+type Handler struct {
+	logger *slog.Logger
+	r      chi.Router
+
+	playlistRepo playlist.Repository
+	songRepo     song.Repository
+	songSvc      song.Service
+}
+
+// NewHandler creates a new Handler instance using the specified repositories and services.
+// The song repository is used to validate the songs of playlists.
+func NewHandler(
+	logger *slog.Logger,
+	playlistRepo playlist.Repository,
+	songRepo song.Repository,
+	songSvc song.Service,
+) *Handler {
+	r := chi.NewRouter()
+	h := &Handler{
+		logger,
+		r,
+		playlistRepo,
+		songRepo,
+		songSvc,
+	}
+
+	r.With(middleware.RequireContentType("application/json", "text/plain", "text/x-ultrastar-playlist"), render.ContentTypeNegotiation("application/json")).Post("/", h.Create)
+	r.With(middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json")).Get("/", h.Find)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.UUID("uuid"))
+		r.Delete("/{uuid}", h.Delete)
+
+		r.Group(func(r chi.Router) {
+			r.Use(h.FetchPlaylist)
+			r.With(render.ContentTypeNegotiation("application/json")).Get("/{uuid}", h.Get)
+			r.With(middleware.ContentTypeJSON).Patch("/{uuid}", h.Update)
+			r.With(render.ContentTypeNegotiation("text/x-ultrastar-playlist", "text/plain")).Get("/{uuid}/upl", h.GetUPL)
+			r.With(middleware.RequireContentType("text/plain", "text/x-ultrastar-playlist"), render.ContentTypeNegotiation("application/json")).Put("/{uuid}/upl", h.ReplaceUPL)
+		})
+	})
+	return h
+}
+
+// ServeHTTP processes HTTP requests for h.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.r.ServeHTTP(w, r)
+}
@@ -0,0 +1,62 @@
+package playlists
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// contextKey is the type for context keys used in this package.
+// This type is intentionally private.
+// Use the accessor functions instead to interact with context values.
+type contextKey int
+
+const (
+	// contextKeyInstance identifies a Playlist instance in a context.
+	contextKeyInstance contextKey = iota
+)
+
+// SetPlaylist sets the playlist instance in ctx.
+func SetPlaylist(ctx context.Context, playlist model.Playlist) context.Context {
+	return context.WithValue(ctx, contextKeyInstance, playlist)
+}
+
+// GetPlaylist returns a model.Playlist instance from the context.
+// If the context does not contain a playlist instance, the second return value will be false.
+func GetPlaylist(ctx context.Context) (model.Playlist, bool) {
+	playlist, ok := ctx.Value(contextKeyInstance).(model.Playlist)
+	return playlist, ok
+}
+
+// MustGetPlaylist returns a model.Playlist instance from the context.
+// In contrast to GetPlaylist this function panics if the context does not contain a playlist instance.
+func MustGetPlaylist(ctx context.Context) model.Playlist {
+	return ctx.Value(contextKeyInstance).(model.Playlist)
+}
+
+// FetchPlaylist is a middleware that fetches the model.Playlist instance identified by the request and stores it in the request context.
+func (h *Handler) FetchPlaylist(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := middleware.MustGetUUID(r.Context())
+		playlist, err := h.playlistRepo.GetPlaylist(r.Context(), id)
+		if errors.Is(err, core.ErrNotFound) {
+			_ = render.Render(w, r, apierror.ErrNotFound)
+			return
+		} else if err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not fetch playlist.", "uuid", id, tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		ctx := SetPlaylist(r.Context(), playlist)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+	return http.HandlerFunc(fn)
+}
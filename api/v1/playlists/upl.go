@@ -0,0 +1,99 @@
+package playlists
+
+import (
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/playlist"
+	songsvc "github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/mediatype"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// GetUPL implements the GET /v1/playlists/{uuid}/upl endpoint.
+// Songs that have been moved to the trash are not included in the file.
+func (h *Handler) GetUPL(w http.ResponseWriter, r *http.Request) {
+	pl := MustGetPlaylist(r.Context())
+	upl, err := playlist.Export(r.Context(), pl, h.songRepo, h.songSvc)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not export playlist.", "uuid", pl.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	t := render.MustGetNegotiatedContentType(r)
+	if t.Equals(mediatype.TextPlain) {
+		t = t.WithoutParameters("charset", "utf-8")
+	}
+	w.Header().Set("Content-Type", t.String())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": playlist.FileName(pl)}))
+	w.WriteHeader(http.StatusOK)
+	_ = playlist.WriteUPL(w, upl)
+}
+
+// ReplaceUPL implements the PUT /v1/playlists/{uuid}/upl endpoint.
+// The songs of the playlist are replaced by the songs in the UPL file.
+// If the file specifies a name, the playlist is renamed.
+func (h *Handler) ReplaceUPL(w http.ResponseWriter, r *http.Request) {
+	h.importUPL(w, r, MustGetPlaylist(r.Context()))
+}
+
+// importUPL reads a UPL file from the body of r and saves its songs in pl.
+// If pl has no UUID, a new playlist is created.
+// Entries of the file are matched against the songs in the library by artist and title.
+// Entries that do not match any song are reported in the response.
+func (h *Handler) importUPL(w http.ResponseWriter, r *http.Request, pl model.Playlist) {
+	upl, err := playlist.ReadUPL(r.Body)
+	if err != nil {
+		h.logger.WarnContext(r.Context(), "Could not parse UltraStar playlist.", tint.Err(err))
+		_ = render.Render(w, r, apierror.InvalidUPL(err))
+		return
+	}
+	if upl.Name != "" {
+		pl.Name = upl.Name
+	}
+	if pl.Name == "" {
+		_ = render.Render(w, r, apierror.InvalidUPL(errors.New("the playlist has no #Name header")))
+		return
+	}
+
+	songs, _, err := h.songRepo.FindSongs(r.Context(), songsvc.Filter{}, -1, 0)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list songs.", tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	for i := range songs {
+		h.songSvc.Prepare(r.Context(), &songs[i])
+	}
+	var missing []playlist.Entry
+	pl.Songs, missing = playlist.Match(upl.Entries, songs)
+
+	status := http.StatusOK
+	if pl.UUID == uuid.Nil {
+		status = http.StatusCreated
+		err = h.playlistRepo.CreatePlaylist(r.Context(), &pl)
+	} else {
+		err = h.playlistRepo.UpdatePlaylist(r.Context(), &pl)
+	}
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not save playlist.", "uuid", pl.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	resp := schema.PlaylistImport{
+		Playlist: schema.FromPlaylist(pl),
+		Missing:  make([]string, len(missing)),
+	}
+	for i, e := range missing {
+		resp.Missing[i] = e.String()
+	}
+	render.SetStatus(r, status)
+	_ = render.Render(w, r, &resp)
+}
@@ -0,0 +1,99 @@
+//go:build database
+
+package playlists
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_GetUPL(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/playlists/")
+	song := testdata.SimpleSong(t, db)
+	deleted := testdata.DeletedSong(t, db)
+	party := testdata.Playlist(t, db, "Party", song.UUID, deleted.UUID)
+	url := fmt.Sprintf("/v1/playlists/%s/upl", party.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		expected := song.Artists[0] + " : " + song.Title + "\n"
+		if !strings.Contains(string(body), "#Name: Party\n") || !strings.HasSuffix(string(body), "#Songs:\n"+expected) {
+			t.Errorf("GET %s responded with %q, expected the name and a single song %q", url, body, expected)
+		}
+	})
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodGet, fmt.Sprintf("/v1/playlists/%s/upl", uuid.New()), http.StatusNotFound))
+}
+
+func TestHandler_ImportUPL(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/playlists/")
+	song := testdata.SimpleSong(t, db)
+	upl := "#Name: Party\n" + strings.ToUpper(song.Artists[0]) + " : " + song.Title + "\nQueen : Bohemian Rhapsody\n"
+
+	t.Run("201 Created", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/v1/playlists/", strings.NewReader(upl))
+		r.Header.Set("Content-Type", "text/plain")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("POST /v1/playlists/ responded with status code %d, expected %d", resp.StatusCode, http.StatusCreated)
+		}
+		var p schema.PlaylistImport
+		if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+			t.Errorf("POST /v1/playlists/ responded with invalid playlist import schema: %s", err)
+			return
+		}
+		if p.Name != "Party" || !slices.Equal(p.Songs, []uuid.UUID{song.UUID}) {
+			t.Errorf("POST /v1/playlists/ responded with name %q and songs %v, expected %q and %v", p.Name, p.Songs, "Party", []uuid.UUID{song.UUID})
+		}
+		if !slices.Equal(p.Missing, []string{"Queen : Bohemian Rhapsody"}) {
+			t.Errorf("POST /v1/playlists/ responded with missing entries %q, expected %q", p.Missing, []string{"Queen : Bohemian Rhapsody"})
+		}
+	})
+	t.Run("200 OK", func(t *testing.T) {
+		party := testdata.Playlist(t, db, "Old Name")
+		url := fmt.Sprintf("/v1/playlists/%s/upl", party.UUID)
+		r := httptest.NewRequest(http.MethodPut, url, strings.NewReader(upl))
+		r.Header.Set("Content-Type", "text/plain")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("PUT %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var p schema.PlaylistImport
+		if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+			t.Errorf("PUT %s responded with invalid playlist import schema: %s", url, err)
+			return
+		}
+		if p.UUID != party.UUID || p.Name != "Party" || !slices.Equal(p.Songs, []uuid.UUID{song.UUID}) {
+			t.Errorf("PUT %s responded with %v, expected playlist %q named %q with songs %v", url, p, party.UUID, "Party", []uuid.UUID{song.UUID})
+		}
+	})
+	t.Run("400 Bad Request", func(t *testing.T) {
+		for _, body := range []string{"#Name: Party\nFoo\n", "Queen : Bohemian Rhapsody\n"} {
+			r := httptest.NewRequest(http.MethodPost, "/v1/playlists/", strings.NewReader(body))
+			r.Header.Set("Content-Type", "text/plain")
+			resp := test.DoRequest(h, r) //nolint:bodyclose
+			test.AssertProblemDetails(t, resp, http.StatusBadRequest, apierror.TypeInvalidUPL, nil)
+		}
+	})
+}
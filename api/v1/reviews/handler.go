@@ -0,0 +1,42 @@
+package reviews
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core/review"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Handler implements the /v1/review-queue endpoints.
+// The review state and comments of individual songs are managed via the /v1/songs endpoints.
+type Handler struct {
+	logger *slog.Logger
+	r      chi.Router
+
+	reviewRepo review.Repository
+}
+
+// NewHandler creates a new Handler instance using the specified repository.
+func NewHandler(
+	logger *slog.Logger,
+	reviewRepo review.Repository,
+) *Handler {
+	r := chi.NewRouter()
+	h := &Handler{
+		logger,
+		r,
+		reviewRepo,
+	}
+
+	r.With(middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json")).Get("/", h.FindQueue)
+	return h
+}
+
+// ServeHTTP processes HTTP requests for h.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.r.ServeHTTP(w, r)
+}
@@ -0,0 +1,31 @@
+//go:build database
+
+package reviews
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgxutil"
+
+	"github.com/Karaoke-Manager/karman/core/review"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	_ "github.com/Karaoke-Manager/karman/pkg/render/json"
+	"github.com/Karaoke-Manager/karman/test"
+)
+
+// setupHandler prepares a test instance of Handler.
+// The tests in this package are integration tests that run against an actual PostgreSQL database.
+// The database can use testcontainers or be an external service.
+func setupHandler(t *testing.T, prefix string) (*Handler, pgxutil.DB) {
+	db := test.NewDB(t)
+	reviewRepo := review.NewDBRepository(nolog.Logger, db)
+
+	// workaround to support the prefix
+	h := NewHandler(nolog.Logger, reviewRepo)
+	r := chi.NewRouter()
+	r.Mount(strings.TrimSuffix(prefix, "/")+"/", h.r)
+	h.r = r
+	return h, db
+}
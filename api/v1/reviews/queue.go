@@ -0,0 +1,35 @@
+package reviews
+
+import (
+	"net/http"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// FindQueue implements the GET /v1/review-queue endpoint.
+func (h *Handler) FindQueue(w http.ResponseWriter, r *http.Request) {
+	pagination := middleware.MustGetPagination(r.Context())
+	entries, total, err := h.reviewRepo.FindQueue(r.Context(), pagination.Limit, pagination.Offset)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list review queue.", "limit", pagination.Limit, "offset", pagination.Offset, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+
+	resp := schema.List[*schema.ReviewQueueEntry]{
+		Items:  make([]*schema.ReviewQueueEntry, len(entries)),
+		Offset: pagination.Offset,
+		Limit:  pagination.RequestLimit,
+		Total:  total,
+	}
+	for i, entry := range entries {
+		s := schema.FromReviewQueueEntry(entry)
+		resp.Items[i] = &s
+	}
+	_ = render.Render(w, r, &resp)
+}
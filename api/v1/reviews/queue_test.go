@@ -0,0 +1,42 @@
+//go:build database
+
+package reviews
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_FindQueue(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/review-queue/")
+	testdata.NSongs(t, db, 3)
+	pending := testdata.SimpleSong(t, db)
+	testdata.ReviewState(t, db, pending.UUID, model.ReviewStateNeedsReview)
+	reported := testdata.SimpleSong(t, db)
+	testdata.Comment(t, db, reported.UUID, model.CommentKindOutOfSync, "")
+	url := "/v1/review-queue/"
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		test.AssertPagination(t, resp, 0, 25, 2, 2)
+		var entries []schema.ReviewQueueEntry
+		if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+			t.Errorf("GET %s responded with invalid review queue schema: %s", url, err)
+			return
+		}
+		if entries[0].Song != pending.UUID || entries[1].Song != reported.UUID || entries[1].OpenReports != 1 {
+			t.Errorf("GET %s responded with %v, expected the pending song first and the reported song second", url, entries)
+		}
+	})
+	t.Run("400 Bad Request (Pagination)", test.InvalidPagination(h, http.MethodGet, url))
+}
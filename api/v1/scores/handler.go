@@ -0,0 +1,55 @@
+package scores
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core/score"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// maxDatabaseSize is the maximum size of a database file that can be imported.
+// Databases are read into memory completely.
+const maxDatabaseSize = 64 << 20
+
+// Handler implements the /v1/scores endpoints.
+type Handler struct {
+	logger *slog.Logger
+	r      chi.Router
+
+	scoreRepo score.Repository
+	songRepo  song.Repository
+	songSvc   song.Service
+}
+
+// NewHandler creates a new Handler instance using the specified repositories and services.
+// The song repository and service are used to match imported statistics to songs.
+func NewHandler(
+	logger *slog.Logger,
+	scoreRepo score.Repository,
+	songRepo song.Repository,
+	songSvc song.Service,
+) *Handler {
+	r := chi.NewRouter()
+	h := &Handler{
+		logger,
+		r,
+		scoreRepo,
+		songRepo,
+		songSvc,
+	}
+
+	r.With(middleware.RequireContentType("application/vnd.sqlite3", "application/x-sqlite3", "application/octet-stream"), render.ContentTypeNegotiation("application/json")).Post("/usdx", h.ImportUSDX)
+	r.With(middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json")).Get("/plays", h.FindPlays)
+	r.With(middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json")).Get("/highscores", h.FindHighscores)
+	return h
+}
+
+// ServeHTTP processes HTTP requests for h.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.r.ServeHTTP(w, r)
+}
@@ -0,0 +1,121 @@
+package scores
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/score"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// ImportUSDX implements the POST /v1/scores/usdx endpoint.
+// The request body is the Ultrastar.db file of UltraStar Deluxe.
+// Play statistics and highscores of matched songs replace the existing statistics of these songs.
+func (h *Handler) ImportUSDX(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxDatabaseSize))
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		_ = render.Render(w, r, apierror.ErrContentTooLarge)
+		return
+	} else if err != nil {
+		_ = render.Render(w, r, apierror.ErrBadRequest)
+		return
+	}
+	entries, err := score.ReadUSDX(bytes.NewReader(data))
+	if err != nil {
+		_ = render.Render(w, r, apierror.InvalidUSDXDatabase(err))
+		return
+	}
+	result, err := score.Import(r.Context(), entries, h.scoreRepo, h.songRepo, h.songSvc)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not import play statistics.", tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	resp := schema.FromScoreImport(result)
+	_ = render.Render(w, r, &resp)
+}
+
+// FindPlays implements the GET /v1/scores/plays endpoint.
+// The results are ordered by the number of plays, most sung songs first.
+func (h *Handler) FindPlays(w http.ResponseWriter, r *http.Request) {
+	pagination := middleware.MustGetPagination(r.Context())
+	stats, total, err := h.scoreRepo.FindPlayStats(r.Context(), pagination.Limit, pagination.Offset)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list play statistics.", "limit", pagination.Limit, "offset", pagination.Offset, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+
+	resp := schema.List[*schema.PlayStats]{
+		Items:  make([]*schema.PlayStats, len(stats)),
+		Offset: pagination.Offset,
+		Limit:  pagination.RequestLimit,
+		Total:  total,
+	}
+	for i, s := range stats {
+		item := schema.FromPlayStats(s)
+		resp.Items[i] = &item
+	}
+	_ = render.Render(w, r, &resp)
+}
+
+// FindHighscores implements the GET /v1/scores/highscores endpoint.
+// The song and difficulty parameters restrict the results to a single song or difficulty.
+// The results are ordered by score, highest scores first.
+func (h *Handler) FindHighscores(w http.ResponseWriter, r *http.Request) {
+	pagination := middleware.MustGetPagination(r.Context())
+	filter, err := highscoreFilter(r.URL.Query())
+	if err != nil {
+		_ = render.Render(w, r, apierror.BadRequest(err.Error()))
+		return
+	}
+	scores, total, err := h.scoreRepo.FindHighscores(r.Context(), filter, pagination.Limit, pagination.Offset)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list highscores.", "limit", pagination.Limit, "offset", pagination.Offset, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+
+	resp := schema.List[*schema.Highscore]{
+		Items:  make([]*schema.Highscore, len(scores)),
+		Offset: pagination.Offset,
+		Limit:  pagination.RequestLimit,
+		Total:  total,
+	}
+	for i, s := range scores {
+		item := schema.FromHighscore(s)
+		resp.Items[i] = &item
+	}
+	_ = render.Render(w, r, &resp)
+}
+
+// highscoreFilter parses the filter parameters of the GET /v1/scores/highscores endpoint.
+func highscoreFilter(query url.Values) (score.Filter, error) {
+	var filter score.Filter
+	if param := query.Get("song"); param != "" {
+		id, err := uuid.Parse(param)
+		if err != nil {
+			return filter, fmt.Errorf("invalid song: %w", err)
+		}
+		filter.Song = id
+	}
+	switch d := model.GameDifficulty(query.Get("difficulty")); d {
+	case "", model.GameDifficultyEasy, model.GameDifficultyMedium, model.GameDifficultyHard:
+		filter.Difficulty = d
+	default:
+		return filter, fmt.Errorf("invalid difficulty: must be one of %q, %q or %q", model.GameDifficultyEasy, model.GameDifficultyMedium, model.GameDifficultyHard)
+	}
+	return filter, nil
+}
@@ -0,0 +1,139 @@
+//go:build database
+
+package scores
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_ImportUSDX(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/scores/")
+	path := "/v1/scores/usdx"
+
+	t.Run("200 OK", func(t *testing.T) {
+		// testdata/Ultrastar.db contains the simple song as "LEO TOLSTOY : Beloved (Live)"
+		song := testdata.SimpleSong(t, db)
+		r := httptest.NewRequest(http.MethodPost, path, test.MustOpen(t, "testdata/Ultrastar.db"))
+		r.Header.Set("Content-Type", "application/vnd.sqlite3")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("POST %s responded with status code %d, expected %d", path, resp.StatusCode, http.StatusOK)
+		}
+		var result schema.ScoreImport
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			t.Fatalf("POST %s responded with invalid score import schema: %s", path, err)
+		}
+		if result.Matched != 1 || result.Scores != 2 {
+			t.Errorf("POST %s responded with %d matched songs and %d scores, expected 1 and 2", path, result.Matched, result.Scores)
+		}
+		if !slices.Equal(result.Unmatched, []string{"Queen : Bohemian Rhapsody"}) {
+			t.Errorf("POST %s responded with unmatched songs %q, expected %q", path, result.Unmatched, []string{"Queen : Bohemian Rhapsody"})
+		}
+
+		r = httptest.NewRequest(http.MethodGet, "/v1/scores/plays", nil)
+		resp = test.DoRequest(h, r) //nolint:bodyclose
+		var plays []schema.PlayStats
+		if err := json.NewDecoder(resp.Body).Decode(&plays); err != nil {
+			t.Fatalf("GET /v1/scores/plays responded with invalid play statistics list schema: %s", err)
+		}
+		if len(plays) != 1 || plays[0].Song != song.UUID || plays[0].TimesPlayed != 4 {
+			t.Errorf("GET /v1/scores/plays responded with %v, expected %s played 4 times", plays, song.UUID)
+		}
+	})
+	t.Run("400 Bad Request", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader("#TITLE:Not a database"))
+		r.Header.Set("Content-Type", "application/octet-stream")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusBadRequest, apierror.TypeInvalidUSDXDatabase, nil)
+	})
+	t.Run("415 Unsupported Media Type", test.InvalidContentType(h, http.MethodPost, path, "text/plain", "application/vnd.sqlite3", "application/x-sqlite3", "application/octet-stream"))
+}
+
+func TestHandler_FindPlays(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/scores/")
+	for i := 1; i <= 3; i++ {
+		testdata.PlayStats(t, db, testdata.SimpleSong(t, db).UUID, i)
+	}
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/v1/scores/plays?limit=2", nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertPagination(t, resp, 0, 2, 2, 3)
+		var plays []schema.PlayStats
+		if err := json.NewDecoder(resp.Body).Decode(&plays); err != nil {
+			t.Fatalf("GET /v1/scores/plays responded with invalid play statistics list schema: %s", err)
+		}
+		if plays[0].TimesPlayed != 3 || plays[1].TimesPlayed != 2 {
+			t.Errorf("GET /v1/scores/plays responded with %v, expected the most played songs first", plays)
+		}
+	})
+	t.Run("400 Bad Request", test.InvalidPagination(h, http.MethodGet, "/v1/scores/plays"))
+}
+
+func TestHandler_FindHighscores(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/scores/")
+	song1 := testdata.SimpleSong(t, db)
+	song2 := testdata.SimpleSong(t, db)
+	testdata.Highscore(t, db, song1.UUID, "Alice", model.GameDifficultyEasy, 5000)
+	testdata.Highscore(t, db, song1.UUID, "Bob", model.GameDifficultyHard, 9000)
+	testdata.Highscore(t, db, song2.UUID, "Carol", model.GameDifficultyHard, 7000)
+
+	cases := map[string]struct {
+		query    string
+		expected []string
+	}{
+		"all":        {"", []string{"Bob", "Carol", "Alice"}},
+		"song":       {"?song=" + song1.UUID.String(), []string{"Bob", "Alice"}},
+		"difficulty": {"?difficulty=hard", []string{"Bob", "Carol"}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			path := "/v1/scores/highscores" + c.query
+			r := httptest.NewRequest(http.MethodGet, path, nil)
+			resp := test.DoRequest(h, r) //nolint:bodyclose
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("GET %s responded with status code %d, expected %d", path, resp.StatusCode, http.StatusOK)
+			}
+			var scores []schema.Highscore
+			if err := json.NewDecoder(resp.Body).Decode(&scores); err != nil {
+				t.Fatalf("GET %s responded with invalid highscore list schema: %s", path, err)
+			}
+			players := make([]string, len(scores))
+			for i, s := range scores {
+				players[i] = s.Player
+			}
+			if !slices.Equal(players, c.expected) {
+				t.Errorf("GET %s responded with players %v, expected %v", path, players, c.expected)
+			}
+		})
+	}
+	t.Run("400 Bad Request", func(t *testing.T) {
+		for _, query := range []string{"?song=foo", "?difficulty=insane"} {
+			t.Run(query, test.HTTPError(h, http.MethodGet, "/v1/scores/highscores"+query, http.StatusBadRequest))
+		}
+	})
+	t.Run("unknown song", func(t *testing.T) {
+		path := fmt.Sprintf("/v1/scores/highscores?song=%s", uuid.New())
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertPagination(t, resp, 0, 25, 0, 0)
+	})
+}
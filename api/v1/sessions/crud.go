@@ -0,0 +1,125 @@
+package sessions
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Create implements the POST /v1/sessions endpoint.
+// The response contains the join code of the new session.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var data schema.SessionRW
+	if err := render.Bind(r, &data); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	session := model.Session{}
+	data.Apply(&session)
+	if err := h.sessionRepo.CreateSession(r.Context(), &session); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not create session.", tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	render.SetStatus(r, http.StatusCreated)
+	resp := schema.FromSession(session)
+	_ = render.Render(w, r, &resp)
+}
+
+// Find implements the GET /v1/sessions endpoint.
+// The endpoint does not require authentication,
+// so the listed sessions include neither their UUIDs nor their join codes.
+func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
+	pagination := middleware.MustGetPagination(r.Context())
+	sessions, total, err := h.sessionRepo.FindSessions(r.Context(), pagination.Limit, pagination.Offset)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list sessions.", "limit", pagination.Limit, "offset", pagination.Offset, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+
+	resp := schema.List[*schema.GuestSession]{
+		Items:  make([]*schema.GuestSession, len(sessions)),
+		Offset: pagination.Offset,
+		Limit:  pagination.RequestLimit,
+		Total:  total,
+	}
+	for i, session := range sessions {
+		s := schema.FromGuestSession(session)
+		s.Code = ""
+		resp.Items[i] = &s
+	}
+	_ = render.Render(w, r, &resp)
+}
+
+// Get implements the GET /v1/sessions/{uuid} endpoint.
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	session := MustGetSession(r.Context())
+	resp := schema.FromSession(session)
+	_ = render.Render(w, r, &resp)
+}
+
+// Join implements the GET /v1/sessions/join/{code} endpoint.
+// Guests use this endpoint to find the session for a join code.
+// The response does not include the UUID of the session.
+func (h *Handler) Join(w http.ResponseWriter, r *http.Request) {
+	session := MustGetSession(r.Context())
+	resp := schema.FromGuestSession(session)
+	_ = render.Render(w, r, &resp)
+}
+
+// Update implements the PATCH /v1/sessions/{uuid} endpoint.
+// This endpoint is used to rename a session, to change its request limit and to close or reopen it.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	session := MustGetSession(r.Context())
+	update := schema.FromSession(session)
+	if err := render.Bind(r, &update); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	update.Apply(&session)
+	if err := h.sessionRepo.UpdateSession(r.Context(), &session); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not update session.", "uuid", session.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	h.publish(r.Context(), event.SessionUpdated(session))
+	h.publish(r.Context(), event.GuestSessionUpdated(session))
+	_ = render.NoContent(w, r)
+}
+
+// Delete implements the DELETE /v1/sessions/{uuid} endpoint.
+// Deleting a session deletes all of its requests.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := middleware.MustGetUUID(r.Context())
+	// The join code is needed to notify guests.
+	session, err := h.sessionRepo.GetSession(r.Context(), id)
+	if errors.Is(err, core.ErrNotFound) {
+		_ = render.NoContent(w, r)
+		return
+	} else if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not fetch session.", "uuid", id, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	ok, err := h.sessionRepo.DeleteSession(r.Context(), id)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not delete session.", "uuid", id, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	if ok {
+		h.publish(r.Context(), event.SessionDeleted(id))
+		h.publish(r.Context(), event.GuestSessionDeleted(session.Code))
+	}
+	_ = render.NoContent(w, r)
+}
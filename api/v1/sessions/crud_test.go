@@ -0,0 +1,183 @@
+//go:build database
+
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/session"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_Create(t *testing.T) {
+	t.Parallel()
+	h, _ := setupHandler(t, "/v1/sessions/")
+	url := "/v1/sessions/"
+
+	t.Run("201 Created", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"name": "Office Party", "requestLimit": 2}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusCreated)
+		}
+		var s schema.Session
+		if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+			t.Errorf("POST %s responded with invalid session schema: %s", url, err)
+			return
+		}
+		if s.UUID == uuid.Nil || s.Code == "" {
+			t.Errorf("POST %s responded with UUID %q and code %q, expected a UUID and a join code", url, s.UUID, s.Code)
+		}
+		if s.Name != "Office Party" || s.RequestLimit != 2 {
+			t.Errorf("POST %s responded with name %q and limit %d, expected %q and %d", url, s.Name, s.RequestLimit, "Office Party", 2)
+		}
+	})
+	t.Run("400 Bad Request (Missing Content-Type)", test.MissingContentType(h, http.MethodPost, url, "application/json"))
+	t.Run("422 Unprocessable Entity", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"name": "Office Party", "requestLimit": -1}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+}
+
+func TestHandler_Find(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/sessions/")
+	testdata.Session(t, db, "Office Party", 0)
+	testdata.Session(t, db, "Christmas Party", 0)
+	url := "/v1/sessions/"
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		test.AssertPagination(t, resp, 0, 25, 2, 2)
+		var sessions []schema.Session
+		if err := json.NewDecoder(resp.Body).Decode(&sessions); err != nil {
+			t.Errorf("GET %s responded with invalid session list schema: %s", url, err)
+			return
+		}
+		for _, s := range sessions {
+			if s.UUID != uuid.Nil || s.Code != "" {
+				t.Errorf("GET %s responded with UUID %q and code %q, expected neither", url, s.UUID, s.Code)
+			}
+		}
+	})
+	t.Run("400 Bad Request (Pagination)", test.InvalidPagination(h, http.MethodGet, url))
+}
+
+func TestHandler_Get(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/sessions/")
+	party := testdata.Session(t, db, "Office Party", 0)
+
+	t.Run("200 OK", func(t *testing.T) {
+		url := fmt.Sprintf("/v1/sessions/%s", party.UUID)
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var s schema.Session
+		if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+			t.Errorf("GET %s responded with invalid session schema: %s", url, err)
+			return
+		}
+		if s.UUID != party.UUID || s.Code != party.Code {
+			t.Errorf("GET %s responded with session %q and code %q, expected %q and %q", url, s.UUID, s.Code, party.UUID, party.Code)
+		}
+	})
+	t.Run("400 Bad Request", test.InvalidUUID(h, http.MethodGet, "/v1/sessions/"+testdata.InvalidUUID))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodGet, "/v1/sessions/"+uuid.New().String(), http.StatusNotFound))
+}
+
+func TestHandler_Join(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/sessions/")
+	party := testdata.Session(t, db, "Office Party", 0)
+
+	t.Run("200 OK", func(t *testing.T) {
+		url := "/v1/sessions/join/" + strings.ToLower(party.Code)
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var s schema.Session
+		if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+			t.Errorf("GET %s responded with invalid session schema: %s", url, err)
+			return
+		}
+		if s.UUID != uuid.Nil {
+			t.Errorf("GET %s responded with UUID %q, expected no UUID", url, s.UUID)
+		}
+		if s.Code != party.Code || s.Name != party.Name {
+			t.Errorf("GET %s responded with code %q and name %q, expected %q and %q", url, s.Code, s.Name, party.Code, party.Name)
+		}
+	})
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodGet, "/v1/sessions/join/ZZZZZZ", http.StatusNotFound))
+}
+
+func TestHandler_Update(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/sessions/")
+	party := testdata.Session(t, db, "Office Party", 0)
+	url := fmt.Sprintf("/v1/sessions/%s", party.UUID)
+
+	t.Run("204 No Content", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"requestLimit": 3, "closed": true}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("PATCH %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
+		}
+		updated, _ := session.NewDBRepository(nolog.Logger, db).GetSession(context.TODO(), party.UUID)
+		if updated.Name != party.Name || updated.RequestLimit != 3 || !updated.Closed {
+			t.Errorf("PATCH %s produced %+v, expected name %q, limit 3 and a closed session", url, updated, party.Name)
+		}
+	})
+	t.Run("422 Unprocessable Entity", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"name": ""}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+}
+
+func TestHandler_Delete(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/sessions/")
+	party := testdata.Session(t, db, "Office Party", 0)
+	url := fmt.Sprintf("/v1/sessions/%s", party.UUID)
+
+	t.Run("204 No Content", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			r := httptest.NewRequest(http.MethodDelete, url, nil)
+			resp := test.DoRequest(h, r) //nolint:bodyclose
+			if resp.StatusCode != http.StatusNoContent {
+				t.Errorf("DELETE %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
+			}
+		}
+		if _, err := session.NewDBRepository(nolog.Logger, db).GetSession(context.TODO(), party.UUID); err == nil {
+			t.Errorf("DELETE %s did not delete the session", url)
+		}
+	})
+	t.Run("400 Bad Request", test.InvalidUUID(h, http.MethodDelete, "/v1/sessions/"+testdata.InvalidUUID))
+}
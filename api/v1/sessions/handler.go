@@ -0,0 +1,95 @@
+package sessions
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/session"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Handler implements the /v1/sessions endpoints.
+// Operators manage sessions and their queues via the UUID of a session.
+// Guests use the join code of a session to view the queue and to request songs.
+type Handler struct {
+	logger *slog.Logger
+	r      chi.Router
+
+	sessionRepo session.Repository
+	songRepo    song.Repository
+	events      event.Bus
+}
+
+// NewHandler creates a new Handler instance using the specified repositories.
+// The song repository is used to validate requested songs.
+// Changes to sessions and their queues are published to events.
+func NewHandler(
+	logger *slog.Logger,
+	sessionRepo session.Repository,
+	songRepo song.Repository,
+	events event.Bus,
+) *Handler {
+	r := chi.NewRouter()
+	h := &Handler{
+		logger,
+		r,
+		sessionRepo,
+		songRepo,
+		events,
+	}
+
+	r.With(middleware.ContentTypeJSON, render.ContentTypeNegotiation("application/json")).Post("/", h.Create)
+	r.With(middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json")).Get("/", h.Find)
+
+	r.Group(func(r chi.Router) {
+		r.Use(h.FetchSessionByCode)
+		r.With(render.ContentTypeNegotiation("application/json")).Get("/join/{code}", h.Join)
+		r.With(render.ContentTypeNegotiation("application/json")).Get("/join/{code}/requests", h.GetQueue)
+		r.With(middleware.ContentTypeJSON, render.ContentTypeNegotiation("application/json")).Post("/join/{code}/requests", h.CreateRequest)
+	})
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.UUID("uuid"))
+		r.Delete("/{uuid}", h.Delete)
+
+		r.Group(func(r chi.Router) {
+			r.Use(h.FetchSession)
+			r.With(render.ContentTypeNegotiation("application/json")).Get("/{uuid}", h.Get)
+			r.With(middleware.ContentTypeJSON).Patch("/{uuid}", h.Update)
+			r.With(render.ContentTypeNegotiation("application/json")).Get("/{uuid}/requests", h.FindRequests)
+			r.With(middleware.ContentTypeJSON).Put("/{uuid}/queue", h.ReorderQueue)
+			r.With(h.FetchRequest, middleware.ContentTypeJSON).Patch("/{uuid}/requests/{request}", h.UpdateRequest)
+			r.Delete("/{uuid}/requests/{request}", h.DeleteRequest)
+		})
+	})
+	return h
+}
+
+// ServeHTTP processes HTTP requests for h.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.r.ServeHTTP(w, r)
+}
+
+// publish sends e to the event bus.
+// Events are informational only, so a failure to publish an event does not fail the request.
+func (h *Handler) publish(ctx context.Context, e event.Event) {
+	if err := h.events.Publish(ctx, e); err != nil {
+		h.logger.WarnContext(ctx, "Could not publish event.", "type", e.Type, tint.Err(err))
+	}
+}
+
+// publishQueue publishes a change to the queue of s to operators and guests.
+// request is the UUID of the request that has changed or uuid.Nil if the queue has been reordered.
+func (h *Handler) publishQueue(ctx context.Context, s model.Session, request uuid.UUID) {
+	h.publish(ctx, event.SessionQueue(s.UUID, request))
+	h.publish(ctx, event.GuestQueue(s.Code, request))
+}
@@ -0,0 +1,129 @@
+package sessions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// contextKey is the type for context keys used in this package.
+// This type is intentionally private.
+// Use the accessor functions instead to interact with context values.
+type contextKey int
+
+const (
+	// contextKeyInstance identifies a Session instance in a context.
+	contextKeyInstance contextKey = iota
+	// contextKeyRequest identifies a SongRequest instance in a context.
+	contextKeyRequest
+)
+
+// SetSession sets the session instance in ctx.
+func SetSession(ctx context.Context, session model.Session) context.Context {
+	return context.WithValue(ctx, contextKeyInstance, session)
+}
+
+// GetSession returns a model.Session instance from the context.
+// If the context does not contain a session instance, the second return value will be false.
+func GetSession(ctx context.Context) (model.Session, bool) {
+	session, ok := ctx.Value(contextKeyInstance).(model.Session)
+	return session, ok
+}
+
+// MustGetSession returns a model.Session instance from the context.
+// In contrast to GetSession this function panics if the context does not contain a session instance.
+func MustGetSession(ctx context.Context) model.Session {
+	return ctx.Value(contextKeyInstance).(model.Session)
+}
+
+// SetRequest sets the song request instance in ctx.
+func SetRequest(ctx context.Context, request model.SongRequest) context.Context {
+	return context.WithValue(ctx, contextKeyRequest, request)
+}
+
+// GetRequest returns a model.SongRequest instance from the context.
+// If the context does not contain a song request instance, the second return value will be false.
+func GetRequest(ctx context.Context) (model.SongRequest, bool) {
+	request, ok := ctx.Value(contextKeyRequest).(model.SongRequest)
+	return request, ok
+}
+
+// MustGetRequest returns a model.SongRequest instance from the context.
+// In contrast to GetRequest this function panics if the context does not contain a song request instance.
+func MustGetRequest(ctx context.Context) model.SongRequest {
+	return ctx.Value(contextKeyRequest).(model.SongRequest)
+}
+
+// FetchSession is a middleware that fetches the model.Session instance identified by the request and stores it in the request context.
+func (h *Handler) FetchSession(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := middleware.MustGetUUID(r.Context())
+		session, err := h.sessionRepo.GetSession(r.Context(), id)
+		if errors.Is(err, core.ErrNotFound) {
+			_ = render.Render(w, r, apierror.ErrNotFound)
+			return
+		} else if err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not fetch session.", "uuid", id, tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		ctx := SetSession(r.Context(), session)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+	return http.HandlerFunc(fn)
+}
+
+// FetchSessionByCode is a middleware that fetches the model.Session instance identified by the join code in the request
+// and stores it in the request context.
+func (h *Handler) FetchSessionByCode(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		code := chi.URLParam(r, "code")
+		session, err := h.sessionRepo.GetSessionByCode(r.Context(), code)
+		if errors.Is(err, core.ErrNotFound) {
+			_ = render.Render(w, r, apierror.ErrNotFound)
+			return
+		} else if err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not fetch session.", "code", code, tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		ctx := SetSession(r.Context(), session)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+	return http.HandlerFunc(fn)
+}
+
+// FetchRequest is a middleware that fetches the model.SongRequest instance identified by the request and stores it in the request context.
+// This middleware must be used after FetchSession.
+func (h *Handler) FetchRequest(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		session := MustGetSession(r.Context())
+		id, err := uuid.Parse(chi.URLParam(r, "request"))
+		if err != nil {
+			_ = render.Render(w, r, apierror.ErrInvalidUUID)
+			return
+		}
+		request, err := h.sessionRepo.GetRequest(r.Context(), session.UUID, id)
+		if errors.Is(err, core.ErrNotFound) {
+			_ = render.Render(w, r, apierror.ErrNotFound)
+			return
+		} else if err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not fetch request.", "uuid", session.UUID, "request", id, tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		ctx := SetRequest(r.Context(), request)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+	return http.HandlerFunc(fn)
+}
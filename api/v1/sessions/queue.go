@@ -0,0 +1,161 @@
+package sessions
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/session"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// GetQueue implements the GET /v1/sessions/join/{code}/requests endpoint.
+// Guests only see the requests that are still queued.
+func (h *Handler) GetQueue(w http.ResponseWriter, r *http.Request) {
+	s := MustGetSession(r.Context())
+	h.renderRequests(w, r, s, model.RequestStateQueued)
+}
+
+// FindRequests implements the GET /v1/sessions/{uuid}/requests endpoint.
+// The requests can be filtered by specifying one or more state query parameters.
+func (h *Handler) FindRequests(w http.ResponseWriter, r *http.Request) {
+	s := MustGetSession(r.Context())
+	var states []model.RequestState
+	for _, state := range r.URL.Query()["state"] {
+		states = append(states, model.RequestState(state))
+	}
+	h.renderRequests(w, r, s, states...)
+}
+
+// renderRequests renders the requests of s in the specified states.
+func (h *Handler) renderRequests(w http.ResponseWriter, r *http.Request, s model.Session, states ...model.RequestState) {
+	requests, err := h.sessionRepo.FindRequests(r.Context(), s.UUID, states...)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list requests.", "uuid", s.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	resp := schema.FromSongRequests(requests)
+	_ = render.Render(w, r, resp)
+}
+
+// CreateRequest implements the POST /v1/sessions/join/{code}/requests endpoint.
+// Guests use this endpoint to request songs.
+// Only songs in the library can be requested.
+func (h *Handler) CreateRequest(w http.ResponseWriter, r *http.Request) {
+	s := MustGetSession(r.Context())
+	var data schema.SongRequestRW
+	if err := render.Bind(r, &data); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	song, err := h.songRepo.GetSong(r.Context(), data.Song)
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
+		h.logger.ErrorContext(r.Context(), "Could not fetch song.", "uuid", data.Song, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	if err != nil || song.Deleted() || song.InUpload {
+		_ = render.Render(w, r, apierror.ValidationError("The song does not exist.", map[string]string{
+			"/song": "song not found",
+		}))
+		return
+	}
+
+	request := model.SongRequest{}
+	data.Apply(&request)
+	err = h.sessionRepo.CreateRequest(r.Context(), s.UUID, &request)
+	switch {
+	case errors.Is(err, session.ErrSessionClosed):
+		_ = render.Render(w, r, apierror.SessionClosed(s))
+		return
+	case errors.Is(err, session.ErrLimitExceeded):
+		_ = render.Render(w, r, apierror.RequestLimitExceeded(s, request.Singer))
+		return
+	case errors.Is(err, core.ErrNotFound):
+		// the session has been deleted concurrently
+		_ = render.Render(w, r, apierror.ErrNotFound)
+		return
+	case err != nil:
+		h.logger.ErrorContext(r.Context(), "Could not create request.", "uuid", s.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	h.publishQueue(r.Context(), s, request.UUID)
+	render.SetStatus(r, http.StatusCreated)
+	resp := schema.FromSongRequest(request)
+	_ = render.Render(w, r, &resp)
+}
+
+// UpdateRequest implements the PATCH /v1/sessions/{uuid}/requests/{request} endpoint.
+// Operators use this endpoint to mark requests as sung or skipped.
+// A request that is queued again is moved to the end of the queue.
+func (h *Handler) UpdateRequest(w http.ResponseWriter, r *http.Request) {
+	s := MustGetSession(r.Context())
+	request := MustGetRequest(r.Context())
+	var data schema.SongRequestState
+	if err := render.Bind(r, &data); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	request.State = data.State
+	if err := h.sessionRepo.UpdateRequestState(r.Context(), s.UUID, &request); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not update request.", "uuid", s.UUID, "request", request.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	h.publishQueue(r.Context(), s, request.UUID)
+	_ = render.NoContent(w, r)
+}
+
+// DeleteRequest implements the DELETE /v1/sessions/{uuid}/requests/{request} endpoint.
+func (h *Handler) DeleteRequest(w http.ResponseWriter, r *http.Request) {
+	s := MustGetSession(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "request"))
+	if err != nil {
+		_ = render.Render(w, r, apierror.ErrInvalidUUID)
+		return
+	}
+	ok, err := h.sessionRepo.DeleteRequest(r.Context(), s.UUID, id)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not delete request.", "uuid", s.UUID, "request", id, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	if ok {
+		h.publishQueue(r.Context(), s, id)
+	}
+	_ = render.NoContent(w, r)
+}
+
+// ReorderQueue implements the PUT /v1/sessions/{uuid}/queue endpoint.
+// The request body must contain all queued requests of the session in their new order.
+func (h *Handler) ReorderQueue(w http.ResponseWriter, r *http.Request) {
+	s := MustGetSession(r.Context())
+	var data schema.QueueOrder
+	if err := render.Bind(r, &data); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	err := h.sessionRepo.ReorderRequests(r.Context(), s.UUID, data.Requests)
+	if errors.Is(err, session.ErrInvalidOrder) {
+		_ = render.Render(w, r, apierror.InvalidQueueOrder())
+		return
+	} else if errors.Is(err, core.ErrNotFound) {
+		_ = render.Render(w, r, apierror.ErrNotFound)
+		return
+	} else if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not reorder queue.", "uuid", s.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	h.publishQueue(r.Context(), s, uuid.Nil)
+	_ = render.NoContent(w, r)
+}
@@ -0,0 +1,213 @@
+//go:build database
+
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/session"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_CreateRequest(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/sessions/")
+	song := testdata.SimpleSong(t, db)
+	deleted := testdata.DeletedSong(t, db)
+	party := testdata.Session(t, db, "Office Party", 1)
+	url := "/v1/sessions/join/" + party.Code + "/requests"
+
+	t.Run("201 Created", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"song": %q, "singer": "Alice", "partner": "Bob"}`, song.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusCreated)
+		}
+		var req schema.SongRequest
+		if err := json.NewDecoder(resp.Body).Decode(&req); err != nil {
+			t.Errorf("POST %s responded with invalid request schema: %s", url, err)
+			return
+		}
+		if req.UUID == uuid.Nil || req.Song != song.UUID || req.Partner != "Bob" || req.State != model.RequestStateQueued {
+			t.Errorf("POST %s responded with %+v, expected a queued request for song %q", url, req, song.UUID)
+		}
+	})
+	t.Run("422 Unprocessable Entity (Singer)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"song": %q, "singer": " "}`, song.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+	t.Run("422 Unprocessable Entity (Song)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"song": %q, "singer": "Carol"}`, deleted.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, map[string]any{
+			"errors": map[string]any{"/song": "song not found"},
+		})
+	})
+	t.Run("429 Too Many Requests", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"song": %q, "singer": "alice"}`, song.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusTooManyRequests, apierror.TypeRequestLimitExceeded, map[string]any{
+			"limit": float64(1),
+		})
+	})
+	t.Run("409 Conflict", func(t *testing.T) {
+		closed := testdata.Session(t, db, "Closed Party", 0)
+		closed.Closed = true
+		_ = session.NewDBRepository(nolog.Logger, db).UpdateSession(context.TODO(), &closed)
+		url := "/v1/sessions/join/" + closed.Code + "/requests"
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"song": %q, "singer": "Alice"}`, song.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusConflict, apierror.TypeSessionClosed, nil)
+	})
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodPost, "/v1/sessions/join/ZZZZZZ/requests", http.StatusNotFound))
+}
+
+// createRequests creates a request for each singer in the session.
+func createRequests(t *testing.T, repo session.Repository, party model.Session, song uuid.UUID, singers ...string) []model.SongRequest {
+	requests := make([]model.SongRequest, len(singers))
+	for i, singer := range singers {
+		requests[i] = model.SongRequest{Song: song, Singer: singer}
+		if err := repo.CreateRequest(context.TODO(), party.UUID, &requests[i]); err != nil {
+			t.Fatalf("CreateRequest(ctx, %q, &request) returned an unexpected error: %s", party.UUID, err)
+		}
+	}
+	return requests
+}
+
+func TestHandler_FindRequests(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/sessions/")
+	repo := session.NewDBRepository(nolog.Logger, db)
+	song := testdata.SimpleSong(t, db)
+	party := testdata.Session(t, db, "Office Party", 0)
+	requests := createRequests(t, repo, party, song.UUID, "Alice", "Bob")
+	requests[0].State = model.RequestStateSung
+	_ = repo.UpdateRequestState(context.TODO(), party.UUID, &requests[0])
+
+	cases := map[string]int{
+		fmt.Sprintf("/v1/sessions/%s/requests", party.UUID):               2,
+		fmt.Sprintf("/v1/sessions/%s/requests?state=sung", party.UUID):    1,
+		fmt.Sprintf("/v1/sessions/join/%s/requests", party.Code):          1,
+		fmt.Sprintf("/v1/sessions/%s/requests?state=skipped", party.UUID): 0,
+	}
+	for url, expected := range cases {
+		t.Run(url, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, url, nil)
+			resp := test.DoRequest(h, r) //nolint:bodyclose
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+			}
+			var reqs []schema.SongRequest
+			if err := json.NewDecoder(resp.Body).Decode(&reqs); err != nil {
+				t.Errorf("GET %s responded with invalid request list schema: %s", url, err)
+				return
+			}
+			if len(reqs) != expected {
+				t.Errorf("GET %s responded with %d requests, expected %d", url, len(reqs), expected)
+			}
+		})
+	}
+}
+
+func TestHandler_UpdateRequest(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/sessions/")
+	repo := session.NewDBRepository(nolog.Logger, db)
+	song := testdata.SimpleSong(t, db)
+	party := testdata.Session(t, db, "Office Party", 0)
+	requests := createRequests(t, repo, party, song.UUID, "Alice")
+	url := fmt.Sprintf("/v1/sessions/%s/requests/%s", party.UUID, requests[0].UUID)
+
+	t.Run("204 No Content", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"state": "skipped"}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("PATCH %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
+		}
+		updated, _ := repo.GetRequest(context.TODO(), party.UUID, requests[0].UUID)
+		if updated.State != model.RequestStateSkipped {
+			t.Errorf("PATCH %s produced state %q, expected %q", url, updated.State, model.RequestStateSkipped)
+		}
+	})
+	t.Run("422 Unprocessable Entity", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"state": "dancing"}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+	t.Run("400 Bad Request", test.InvalidUUID(h, http.MethodPatch, fmt.Sprintf("/v1/sessions/%s/requests/%s", party.UUID, testdata.InvalidUUID)))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodPatch, fmt.Sprintf("/v1/sessions/%s/requests/%s", party.UUID, uuid.New()), http.StatusNotFound))
+}
+
+func TestHandler_DeleteRequest(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/sessions/")
+	repo := session.NewDBRepository(nolog.Logger, db)
+	song := testdata.SimpleSong(t, db)
+	party := testdata.Session(t, db, "Office Party", 0)
+	requests := createRequests(t, repo, party, song.UUID, "Alice")
+	url := fmt.Sprintf("/v1/sessions/%s/requests/%s", party.UUID, requests[0].UUID)
+
+	t.Run("204 No Content", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			r := httptest.NewRequest(http.MethodDelete, url, nil)
+			resp := test.DoRequest(h, r) //nolint:bodyclose
+			if resp.StatusCode != http.StatusNoContent {
+				t.Errorf("DELETE %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
+			}
+		}
+		if _, err := repo.GetRequest(context.TODO(), party.UUID, requests[0].UUID); err == nil {
+			t.Errorf("DELETE %s did not delete the request", url)
+		}
+	})
+}
+
+func TestHandler_ReorderQueue(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/sessions/")
+	repo := session.NewDBRepository(nolog.Logger, db)
+	song := testdata.SimpleSong(t, db)
+	party := testdata.Session(t, db, "Office Party", 0)
+	requests := createRequests(t, repo, party, song.UUID, "Alice", "Bob")
+	url := fmt.Sprintf("/v1/sessions/%s/queue", party.UUID)
+
+	t.Run("204 No Content", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPut, url, strings.NewReader(fmt.Sprintf(`{"requests": [%q, %q]}`, requests[1].UUID, requests[0].UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("PUT %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
+		}
+		queue, _ := repo.FindRequests(context.TODO(), party.UUID, model.RequestStateQueued)
+		if len(queue) != 2 || queue[0].UUID != requests[1].UUID {
+			t.Errorf("PUT %s produced %v, expected %q to be first", url, queue, requests[1].UUID)
+		}
+	})
+	t.Run("409 Conflict", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPut, url, strings.NewReader(fmt.Sprintf(`{"requests": [%q]}`, requests[0].UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusConflict, apierror.TypeInvalidQueueOrder, nil)
+	})
+}
@@ -0,0 +1,109 @@
+package songs
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// CreateBatch implements the POST /v1/songs/batch endpoint.
+// Small batches are run immediately and respond with 201 Created.
+// Larger batches are run in the background and respond with 202 Accepted.
+func (h *Handler) CreateBatch(w http.ResponseWriter, r *http.Request) {
+	var req schema.SongBatchRequest
+	if err := render.Bind(r, &req); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	songs := req.Songs
+	if req.Query != nil {
+		query, err := url.ParseQuery(*req.Query)
+		if err != nil {
+			_ = render.Render(w, r, apierror.ValidationError("The query is invalid.", map[string]string{"/query": err.Error()}))
+			return
+		}
+		filter, err := songFilter(query)
+		if err != nil {
+			_ = render.Render(w, r, apierror.ValidationError("The query is invalid.", map[string]string{"/query": err.Error()}))
+			return
+		}
+		found, _, err := h.songRepo.FindSongs(r.Context(), filter, -1, 0)
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not list songs.", tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		songs = make([]uuid.UUID, len(found))
+		for i, s := range found {
+			songs[i] = s.UUID
+		}
+	}
+
+	batch := req.Batch(r.Header.Get("From"))
+	if err := h.batchSvc.StartBatch(r.Context(), &batch, songs); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not start batch.", "op", batch.Operation.Op, "songs", len(songs), tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	if batch.State == model.BatchStatePending || batch.State == model.BatchStateRunning {
+		render.SetStatus(r, http.StatusAccepted)
+	} else {
+		render.SetStatus(r, http.StatusCreated)
+	}
+	resp := schema.FromSongBatch(batch)
+	_ = render.Render(w, r, &resp)
+}
+
+// GetBatch implements the GET /v1/songs/batch/{uuid} endpoint.
+func (h *Handler) GetBatch(w http.ResponseWriter, r *http.Request) {
+	id := middleware.MustGetUUID(r.Context())
+	batch, err := h.batchRepo.GetBatch(r.Context(), id)
+	if errors.Is(err, core.ErrNotFound) {
+		_ = render.Render(w, r, apierror.ErrNotFound)
+		return
+	} else if err != nil {
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	resp := schema.FromSongBatch(batch)
+	_ = render.Render(w, r, &resp)
+}
+
+// GetBatchResults implements the GET /v1/songs/batch/{uuid}/results endpoint.
+func (h *Handler) GetBatchResults(w http.ResponseWriter, r *http.Request) {
+	id := middleware.MustGetUUID(r.Context())
+	pagination := middleware.MustGetPagination(r.Context())
+	if _, err := h.batchRepo.GetBatch(r.Context(), id); errors.Is(err, core.ErrNotFound) {
+		_ = render.Render(w, r, apierror.ErrNotFound)
+		return
+	} else if err != nil {
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	results, total, err := h.batchRepo.FindResults(r.Context(), id, pagination.Limit, pagination.Offset)
+	if err != nil {
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	resp := schema.List[*schema.SongBatchResult]{
+		Items:  make([]*schema.SongBatchResult, len(results)),
+		Offset: pagination.Offset,
+		Limit:  pagination.RequestLimit,
+		Total:  total,
+	}
+	for i, result := range results {
+		s := schema.FromSongBatchResult(result)
+		resp.Items[i] = &s
+	}
+	_ = render.Render(w, r, &resp)
+}
@@ -0,0 +1,134 @@
+//go:build database
+
+package songs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_CreateBatch(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	s := testdata.SimpleSong(t, db)
+	songWithUpload := testdata.SongWithUpload(t, db)
+	url := "/v1/songs/batch"
+
+	t.Run("201 Created", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{
+			"songs": [%q, %q],
+			"operation": {"op": "set", "fields": {"genre": "Rock", "year": 1999}}
+		}`, s.UUID, songWithUpload.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusCreated)
+		}
+		var data schema.SongBatch
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("POST %s responded with invalid batch schema: %s", url, err)
+		}
+		if data.Status != model.BatchStateDone || data.SongsTotal != 2 || data.SongsProcessed != 2 || data.Errors != 1 {
+			t.Errorf("POST %s responded with %v, expected a finished batch with one error", url, data)
+		}
+		updated, _ := song.NewDBRepository(nolog.Logger, db).GetSong(context.TODO(), s.UUID)
+		if updated.Genre != "Rock" || updated.Year != 1999 {
+			t.Errorf("POST %s set genre %q and year %d, expected %q and %d", url, updated.Genre, updated.Year, "Rock", 1999)
+		}
+	})
+	t.Run("201 Created (Query)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{
+			"query": "",
+			"operation": {"op": "addCustomTags", "customTags": {"ALBUM": "Batch"}}
+		}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusCreated)
+		}
+		var data schema.SongBatch
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("POST %s responded with invalid batch schema: %s", url, err)
+		}
+		if data.SongsTotal != 1 || data.Errors != 0 {
+			t.Errorf("POST %s selected %d songs with %d errors, expected 1 song without errors", url, data.SongsTotal, data.Errors)
+		}
+	})
+	t.Run("400 Bad Request (Missing Content-Type)", test.MissingContentType(h, http.MethodPost, url, "application/json"))
+	t.Run("422 Unprocessable Entity (Missing Selection)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"operation": {"op": "delete"}}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+	t.Run("422 Unprocessable Entity (Invalid Operation)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"songs": [%q], "operation": {"op": "set"}}`, s.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+	t.Run("422 Unprocessable Entity (Invalid Query)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"query": "minDifficulty=foo", "operation": {"op": "delete"}}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+}
+
+func TestHandler_GetBatch(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	s := testdata.SimpleSong(t, db)
+	missing := uuid.New()
+	batch := model.SongBatch{Operation: model.BatchOperation{Op: model.BatchOpFix}}
+	if err := h.batchSvc.StartBatch(context.TODO(), &batch, []uuid.UUID{s.UUID, missing}); err != nil {
+		t.Fatalf("StartBatch() returned an unexpected error: %s", err)
+	}
+	url := fmt.Sprintf("/v1/songs/batch/%s", batch.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var data schema.SongBatch
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("GET %s responded with invalid batch schema: %s", url, err)
+		}
+		if data.UUID != batch.UUID || data.Operation.Op != model.BatchOpFix || data.SongsTotal != 2 {
+			t.Errorf("GET %s responded with %v, expected %v", url, data, schema.FromSongBatch(batch))
+		}
+	})
+	t.Run("200 OK (Results)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url+"/results", nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		var data schema.List[*schema.SongBatchResult]
+		test.AssertPagination(t, resp, 0, 25, 2, 2)
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("GET %s/results responded with invalid list schema: %s", url, err)
+		}
+		if data.Items[0].Song != s.UUID || data.Items[0].Error != "" || data.Items[1].Song != missing || data.Items[1].Error == "" {
+			t.Errorf("GET %s/results responded with unexpected results", url)
+		}
+	})
+	t.Run("400 Bad Request (Invalid UUID)", test.InvalidUUID(h, http.MethodGet, "/v1/songs/batch/"+testdata.InvalidUUID))
+	t.Run("400 Bad Request (Invalid Pagination)", test.InvalidPagination(h, http.MethodGet, url+"/results"))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodGet, fmt.Sprintf("/v1/songs/batch/%s", uuid.New()), http.StatusNotFound))
+	t.Run("404 Not Found (Results)", test.HTTPError(h, http.MethodGet, fmt.Sprintf("/v1/songs/batch/%s/results", uuid.New()), http.StatusNotFound))
+}
@@ -1,42 +1,86 @@
 package songs
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"codello.dev/ultrastar/txt"
+	"github.com/google/uuid"
 	"github.com/lmittmann/tint"
 
 	"github.com/Karaoke-Manager/karman/api/apierror"
 	"github.com/Karaoke-Manager/karman/api/middleware"
 	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/header"
+	"github.com/Karaoke-Manager/karman/core/midi"
+	songsvc "github.com/Karaoke-Manager/karman/core/song"
 	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/mediatype"
 	"github.com/Karaoke-Manager/karman/pkg/render"
 )
 
 // Create implements the POST /v1/songs endpoint.
+// MIDI files are converted into songs, all other requests must contain UltraStar TXT data.
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
-	data, err := txt.NewReader(r.Body).ReadSong()
-	if err != nil {
+	var song model.Song
+	var err error
+	var maxBytesErr *http.MaxBytesError
+	body := http.MaxBytesReader(w, r.Body, maxSongSize)
+	if mediatype.MustParse(r.Header.Get("Content-Type")).Type() == "audio" {
+		song, err = midi.ReadSong(body)
+		if errors.As(err, &maxBytesErr) {
+			_ = render.Render(w, r, apierror.ErrContentTooLarge)
+			return
+		} else if err != nil {
+			_ = render.Render(w, r, apierror.InvalidMIDI(err))
+			h.logger.WarnContext(r.Context(), "Could not convert MIDI file.", tint.Err(err))
+			return
+		}
+	} else if song.Song, err = txt.NewReader(body).ReadSong(); errors.As(err, &maxBytesErr) {
+		_ = render.Render(w, r, apierror.ErrContentTooLarge)
+		return
+	} else if err != nil {
 		_ = render.Render(w, r, apierror.InvalidUltraStarTXT(err))
 		h.logger.WarnContext(r.Context(), "Could not parse UltraStar TXT.", tint.Err(err))
 		return
 	}
-	song := model.Song{Song: data}
 	h.songSvc.ParseArtists(r.Context(), &song)
+	h.songSvc.DetectMedley(r.Context(), &song)
 	if err = h.songRepo.CreateSong(r.Context(), &song); err != nil {
 		h.logger.ErrorContext(r.Context(), "Could not create song.", tint.Err(err))
 		_ = render.Render(w, r, apierror.ErrInternalServerError)
 		return
 	}
+	h.recordRevision(r, song)
+	h.publish(r.Context(), event.SongCreated(song))
+	setETag(w, song)
 	render.SetStatus(r, http.StatusCreated)
 	s := schema.FromSong(song)
 	_ = render.Render(w, r, &s)
 }
 
 // Find implements the GET /v1/songs endpoint.
+// If a CSV response is requested, the metadata of all matching songs is exported as a song sheet.
 func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
+	if render.MustGetNegotiatedContentType(r).Equals(mediaTypeCSV) {
+		h.exportSheet(w, r)
+		return
+	}
 	pagination := middleware.MustGetPagination(r.Context())
-	songs, total, err := h.songRepo.FindSongs(r.Context(), pagination.Limit, pagination.Offset)
+	filter, err := songFilter(r.URL.Query())
+	if err != nil {
+		_ = render.Render(w, r, apierror.BadRequest(err.Error()))
+		return
+	}
+	songs, total, err := h.songRepo.FindSongs(r.Context(), filter, pagination.Limit, pagination.Offset)
 	if err != nil {
 		h.logger.ErrorContext(r.Context(), "Could not list songs.", "limit", pagination.Limit, "offset", pagination.Offset, tint.Err(err))
 		_ = render.Render(w, r, apierror.ErrInternalServerError)
@@ -56,37 +100,221 @@ func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
 	_ = render.Render(w, r, &resp)
 }
 
+// songFilter parses the filter parameters of the GET /v1/songs endpoint.
+// The range parameter restricts the vocal range of songs, the minDifficulty and maxDifficulty parameters restrict their difficulty.
+// The tag parameter can be repeated and matches songs that have all specified tags.
+// The variantGroup parameter lists the songs of a variant group.
+func songFilter(query url.Values) (songsvc.Filter, error) {
+	var filter songsvc.Filter
+	for _, tag := range query["tag"] {
+		if strings.TrimSpace(tag) == "" {
+			return filter, errors.New("invalid tag: must not be empty")
+		}
+		filter.Tags = append(filter.Tags, tag)
+	}
+	if param := query.Get("range"); param != "" {
+		r, err := songsvc.ParsePitchRange(param)
+		if err != nil {
+			return filter, err
+		}
+		filter.Range = &r
+	}
+	var err error
+	if filter.MinDifficulty, err = difficultyParam(query, "minDifficulty"); err != nil {
+		return filter, err
+	}
+	if filter.MaxDifficulty, err = difficultyParam(query, "maxDifficulty"); err != nil {
+		return filter, err
+	}
+	if param := query.Get("variantGroup"); param != "" {
+		if filter.VariantGroup, err = uuid.Parse(param); err != nil {
+			return filter, errors.New("invalid variantGroup: must be a UUID")
+		}
+	}
+	return filter, nil
+}
+
+// difficultyParam parses the query parameter param as a difficulty.
+// If the parameter is not present, model.DifficultyUnknown is returned.
+func difficultyParam(query url.Values, param string) (model.Difficulty, error) {
+	value := query.Get(param)
+	if value == "" {
+		return model.DifficultyUnknown, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || model.Difficulty(n) < model.DifficultyEasiest || model.Difficulty(n) > model.DifficultyHardest {
+		return model.DifficultyUnknown, fmt.Errorf("invalid %s: must be a number between %d and %d", param, model.DifficultyEasiest, model.DifficultyHardest)
+	}
+	return model.Difficulty(n), nil
+}
+
 // Get implements the GET /v1/songs/{uuid} endpoint.
 func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
 	song := MustGetSong(r.Context())
+	setETag(w, song)
 	resp := schema.FromSong(song)
 	_ = render.Render(w, r, &resp)
 }
 
 // Update implements the PATCH /v1/songs/{uuid} endpoint.
+// Custom tags that are added or modified must conform to the registry of custom headers.
 func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	song := MustGetSong(r.Context())
+	previous := maps.Clone(song.CustomTags)
 	update := schema.FromSong(song)
 	if err := render.Bind(r, &update); err != nil {
 		_ = render.Render(w, r, apierror.BindError(err))
 		return
 	}
 	update.Apply(&song)
-	if err := h.songRepo.UpdateSong(r.Context(), &song); err != nil {
-		h.logger.ErrorContext(r.Context(), "Could not update song.", "uuid", song.UUID, tint.Err(err))
+	problems, err := h.customTagProblems(r.Context(), previous, song.CustomTags)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not validate custom tags.", "uuid", song.UUID, tint.Err(err))
 		_ = render.Render(w, r, apierror.ErrInternalServerError)
 		return
 	}
+	if len(problems) > 0 {
+		_ = render.Render(w, r, apierror.InvalidCustomTags(problems))
+		return
+	}
+	if err := h.updateSong(r.Context(), &song); err != nil {
+		h.renderUpdateError(w, r, song, err)
+		return
+	}
+	h.recordRevision(r, song)
+	setETag(w, song)
+	h.publish(r.Context(), event.SongUpdated(song))
 	_ = render.NoContent(w, r)
 }
 
+// customTagProblems checks the custom tags in tags that differ from previous against the registry of custom headers.
+// Unknown keys and keys that are not spelled canonically are reported unless the registry is empty.
+// Unchanged custom tags are not validated, so that songs with legacy values can still be edited.
+// The result maps the keys of invalid custom tags to a description of the respective problem.
+func (h *Handler) customTagProblems(ctx context.Context, previous map[string]string, tags map[string]string) (map[string]string, error) {
+	changed := make(map[string]string)
+	for key, value := range tags {
+		if old, ok := previous[key]; !ok || old != value {
+			changed[key] = value
+		}
+	}
+	if len(changed) == 0 {
+		return nil, nil
+	}
+	registry, err := header.LoadRegistry(ctx, h.headerRepo)
+	if err != nil {
+		return nil, err
+	}
+	problems := make(map[string]string)
+	for _, p := range registry.Check(changed) {
+		if _, ok := problems[p.Key]; !ok {
+			problems[p.Key] = p.Message
+		}
+	}
+	return problems, nil
+}
+
 // Delete implements the DELETE /v1/songs/{uuid} endpoint.
+// Deleting a song is idempotent, so this endpoint does not use FetchSong.
+// If the request is conditional, the song is fetched to evaluate the precondition.
 func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	id := middleware.MustGetUUID(r.Context())
-	if _, err := h.songRepo.DeleteSong(r.Context(), id); err != nil {
+	if !middleware.HasIfMatch(r) && h.strictPreconditions {
+		_ = render.Render(w, r, apierror.ErrPreconditionRequired)
+		return
+	}
+	if middleware.HasIfMatch(r) {
+		h.deleteIfMatch(w, r, id)
+		return
+	}
+	ok, err := h.songRepo.DeleteSong(r.Context(), id)
+	if err != nil {
 		h.logger.ErrorContext(r.Context(), "Could not delete song.", "uuid", id, tint.Err(err))
 		_ = render.Render(w, r, apierror.ErrInternalServerError)
 		return
 	}
+	if ok {
+		h.publish(r.Context(), event.SongDeleted(id))
+	}
 	_ = render.NoContent(w, r)
 }
+
+// deleteIfMatch implements the DELETE /v1/songs/{uuid} endpoint for conditional requests.
+// The song is only deleted if it has not been modified since the precondition was evaluated.
+func (h *Handler) deleteIfMatch(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	etag := ""
+	song, err := h.songRepo.GetSong(r.Context(), id)
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
+		h.logger.ErrorContext(r.Context(), "Could not fetch song.", "uuid", id, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	if err == nil && !song.Deleted() {
+		etag = middleware.ETag(song.UpdatedAt)
+	}
+	if !middleware.IfMatch(r, etag) {
+		_ = render.Render(w, r, apierror.PreconditionFailed(etag))
+		return
+	}
+	err = h.songRepo.DeleteSongIfUnmodified(r.Context(), id, song.UpdatedAt)
+	if errors.Is(err, core.ErrPreconditionFailed) {
+		_ = render.Render(w, r, apierror.PreconditionFailed(""))
+		return
+	} else if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not delete song.", "uuid", id, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	h.publish(r.Context(), event.SongDeleted(id))
+	_ = render.NoContent(w, r)
+}
+
+// FindDeleted implements the GET /v1/songs/trash endpoint.
+func (h *Handler) FindDeleted(w http.ResponseWriter, r *http.Request) {
+	pagination := middleware.MustGetPagination(r.Context())
+	songs, total, err := h.songRepo.FindDeletedSongs(r.Context(), pagination.Limit, pagination.Offset)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list deleted songs.", "limit", pagination.Limit, "offset", pagination.Offset, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+
+	resp := schema.List[*schema.Song]{
+		Items:  make([]*schema.Song, len(songs)),
+		Offset: pagination.Offset,
+		Limit:  pagination.RequestLimit,
+		Total:  total,
+	}
+	for i, song := range songs {
+		s := schema.FromSong(song)
+		resp.Items[i] = &s
+	}
+	_ = render.Render(w, r, &resp)
+}
+
+// Restore implements the POST /v1/songs/{uuid}/restore endpoint.
+// Restoring a song that is not in the trash has no effect.
+func (h *Handler) Restore(w http.ResponseWriter, r *http.Request) {
+	id := middleware.MustGetUUID(r.Context())
+	ok, err := h.songRepo.RestoreSong(r.Context(), id)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not restore song.", "uuid", id, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	song, err := h.songRepo.GetSong(r.Context(), id)
+	if errors.Is(err, core.ErrNotFound) {
+		_ = render.Render(w, r, apierror.ErrNotFound)
+		return
+	} else if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not fetch song.", "uuid", id, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	if ok {
+		h.publish(r.Context(), event.SongRestored(song))
+	}
+	setETag(w, song)
+	s := schema.FromSong(song)
+	_ = render.Render(w, r, &s)
+}
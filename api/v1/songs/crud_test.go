@@ -13,7 +13,9 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
 	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/model"
 	"github.com/Karaoke-Manager/karman/test"
 	testdata "github.com/Karaoke-Manager/karman/test/data"
 )
@@ -51,8 +53,14 @@ func TestHandler_Create(t *testing.T) {
 			"line": 1,
 		})
 	})
-	t.Run("400 Bad Request (Missing Content-Type)", test.MissingContentType(h, http.MethodPost, url, "text/plain", "text/x-ultrastar"))
-	t.Run("415 Unsupported Media Type", test.InvalidContentType(h, http.MethodPost, url, "application/json", "text/plain", "text/x-ultrastar"))
+	t.Run("400 Bad Request (MIDI)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader("Foo"))
+		r.Header.Set("Content-Type", "audio/midi")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusBadRequest, apierror.TypeInvalidMIDI, nil)
+	})
+	t.Run("400 Bad Request (Missing Content-Type)", test.MissingContentType(h, http.MethodPost, url, "text/plain", "text/x-ultrastar", "audio/midi", "audio/x-midi"))
+	t.Run("415 Unsupported Media Type", test.InvalidContentType(h, http.MethodPost, url, "application/json", "text/plain", "text/x-ultrastar", "audio/midi", "audio/x-midi"))
 }
 
 func TestHandler_Find(t *testing.T) {
@@ -75,7 +83,20 @@ func TestHandler_Find(t *testing.T) {
 			t.Errorf("GET %s responded with %d songs, expected %d", url, len(songs), 25)
 		}
 	})
+	t.Run("200 OK (Filter)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url+"?range=C3..G4&minDifficulty=1", nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertPagination(t, resp, 0, 25, 0, 0)
+	})
 	t.Run("400 Bad Request (Pagination)", test.InvalidPagination(h, http.MethodGet, url))
+	t.Run("400 Bad Request (Range)", test.HTTPError(h, http.MethodGet, url+"?range=G4..C3", http.StatusBadRequest))
+	t.Run("200 OK (Tags)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url+"?tag=christmas&tag=needs-video", nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertPagination(t, resp, 0, 25, 0, 0)
+	})
+	t.Run("400 Bad Request (Difficulty)", test.HTTPError(h, http.MethodGet, url+"?maxDifficulty=6", http.StatusBadRequest))
+	t.Run("400 Bad Request (Tag)", test.HTTPError(h, http.MethodGet, url+"?tag=", http.StatusBadRequest))
 }
 
 func TestHandler_Get(t *testing.T) {
@@ -99,9 +120,20 @@ func TestHandler_Get(t *testing.T) {
 		if song.Genre != simpleSong.Genre {
 			t.Errorf(`GET %s responded with {"genre": %q}, expected %q`, url, song.Genre, simpleSong.Genre)
 		}
+		if etag := resp.Header.Get("ETag"); etag != middleware.ETag(simpleSong.UpdatedAt) {
+			t.Errorf("GET %s responded with ETag %s, expected %s", url, etag, middleware.ETag(simpleSong.UpdatedAt))
+		}
 	})
 	t.Run("400 Bad Request (Invalid UUID)", test.InvalidUUID(h, http.MethodGet, fmt.Sprintf("/v1/songs/%s", testdata.InvalidUUID)))
 	t.Run("404 Not Found", test.HTTPError(h, http.MethodGet, fmt.Sprintf("/v1/songs/%s", uuid.New()), http.StatusNotFound))
+	t.Run("410 Gone", func(t *testing.T) {
+		deletedSong := testdata.DeletedSong(t, db)
+		r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/songs/%s", deletedSong.UUID), nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusGone, apierror.TypeSongDeleted, map[string]any{
+			"uuid": deletedSong.UUID.String(),
+		})
+	})
 }
 
 func TestHandler_Update(t *testing.T) {
@@ -116,10 +148,23 @@ func TestHandler_Update(t *testing.T) {
 			{"title": "Foobar"}
 		`))
 		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("If-Match", middleware.ETag(simpleSong.UpdatedAt))
 		resp := test.DoRequest(h, r) //nolint:bodyclose
 		if resp.StatusCode != http.StatusNoContent {
 			t.Errorf("PATCH %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
 		}
+		if resp.Header.Get("ETag") == "" {
+			t.Errorf("PATCH %s did not respond with an ETag header, expected ETag to be set", url)
+		}
+
+		// The previous ETag is now outdated
+		r = httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`
+			{"title": "Foobaz"}
+		`))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("If-Match", middleware.ETag(simpleSong.UpdatedAt))
+		resp = test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusPreconditionFailed, apierror.TypePreconditionFailed, nil)
 	})
 	t.Run("400 Bad Request (Invalid UUID)", test.InvalidUUID(h, http.MethodGet, fmt.Sprintf("/v1/songs/%s", testdata.InvalidUUID)))
 	t.Run("400 Bad Request (Invalid Body)", func(t *testing.T) {
@@ -142,6 +187,19 @@ func TestHandler_Update(t *testing.T) {
 		resp := test.DoRequest(h, r) //nolint:bodyclose
 		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
 	})
+	t.Run("422 Unprocessable Entity (Custom Tags)", func(t *testing.T) {
+		testdata.CustomHeader(t, db, "RATING", model.HeaderTypeInt)
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`
+			{"extra": {"RATING": "five", "Rating": "5", "PREVIEWSTRAT": "12"}}
+		`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertValidationError(t, resp, map[string]string{
+			"/extra/RATING":       "must be an integer",
+			"/extra/Rating":       "should be spelled #RATING",
+			"/extra/PREVIEWSTRAT": "unknown custom header",
+		})
+	})
 	t.Run("422 Unprocessable Entity (Datatype)", func(t *testing.T) {
 		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`
 			{"title": "Foobar", "gap": "51"}
@@ -160,6 +218,20 @@ func TestHandler_Delete(t *testing.T) {
 	simpleSong := testdata.SimpleSong(t, db)
 	url := fmt.Sprintf("/v1/songs/%s", simpleSong.UUID)
 
+	t.Run("412 Precondition Failed", func(t *testing.T) {
+		song := testdata.SimpleSong(t, db)
+		r := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/v1/songs/%s", song.UUID), nil)
+		r.Header.Set("If-Match", `"outdated"`)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusPreconditionFailed, apierror.TypePreconditionFailed, map[string]any{
+			"etag": middleware.ETag(song.UpdatedAt),
+		})
+
+		r = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/v1/songs/%s", uuid.New()), nil)
+		r.Header.Set("If-Match", "*")
+		resp = test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusPreconditionFailed, apierror.TypePreconditionFailed, nil)
+	})
 	t.Run("204 No Content", func(t *testing.T) {
 		r := httptest.NewRequest(http.MethodDelete, url, nil)
 		resp := test.DoRequest(h, r) //nolint:bodyclose
@@ -173,6 +245,74 @@ func TestHandler_Delete(t *testing.T) {
 		if resp.StatusCode != http.StatusNoContent {
 			t.Errorf("DELETE %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
 		}
+
+		// The song should now be in the trash
+		r = httptest.NewRequest(http.MethodGet, url, nil)
+		resp = test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusGone {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusGone)
+		}
 	})
 	t.Run("400 Bad Request (Invalid UUID)", test.InvalidUUID(h, http.MethodGet, fmt.Sprintf("/v1/songs/%s", testdata.InvalidUUID)))
 }
+
+func TestHandler_FindDeleted(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	testdata.NSongs(t, db, 10)
+	deletedSong := testdata.DeletedSong(t, db)
+	url := "/v1/songs/trash"
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		var songs []schema.Song
+		test.AssertPagination(t, resp, 0, 25, 1, 1)
+		if err := json.NewDecoder(resp.Body).Decode(&songs); err != nil {
+			t.Errorf("GET %s responded with invalid song list schema: %s", url, err)
+			return
+		}
+		if len(songs) != 1 || songs[0].UUID != deletedSong.UUID {
+			t.Errorf("GET %s did not respond with the deleted song %q", url, deletedSong.UUID)
+			return
+		}
+		if songs[0].DeletedAt == nil {
+			t.Errorf(`GET %s responded with {"deletedAt": null}, expected a timestamp`, url)
+		}
+	})
+	t.Run("400 Bad Request (Pagination)", test.InvalidPagination(h, http.MethodGet, url))
+}
+
+func TestHandler_Restore(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	deletedSong := testdata.DeletedSong(t, db)
+	url := fmt.Sprintf("/v1/songs/%s/restore", deletedSong.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		var song schema.Song
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&song); err != nil {
+			t.Errorf("POST %s responded with invalid song schema: %s", url, err)
+			return
+		}
+		if song.DeletedAt != nil {
+			t.Errorf(`POST %s responded with {"deletedAt": %q}, expected null`, url, song.DeletedAt.String())
+		}
+
+		// The song should be available again
+		r = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/songs/%s", deletedSong.UUID), nil)
+		resp = test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET /v1/songs/%s responded with status code %d, expected %d", deletedSong.UUID, resp.StatusCode, http.StatusOK)
+		}
+	})
+	t.Run("400 Bad Request (Invalid UUID)", test.InvalidUUID(h, http.MethodPost, fmt.Sprintf("/v1/songs/%s/restore", testdata.InvalidUUID)))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodPost, fmt.Sprintf("/v1/songs/%s/restore", uuid.New()), http.StatusNotFound))
+}
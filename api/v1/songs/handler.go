@@ -1,35 +1,69 @@
 package songs
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/lmittmann/tint"
 
+	"github.com/Karaoke-Manager/karman/api/apierror"
 	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/batch"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/header"
 	"github.com/Karaoke-Manager/karman/core/media"
+	"github.com/Karaoke-Manager/karman/core/review"
+	"github.com/Karaoke-Manager/karman/core/revision"
 	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/core/variant"
+	"github.com/Karaoke-Manager/karman/model"
 	"github.com/Karaoke-Manager/karman/pkg/render"
 )
 
+// maxSongSize is the maximum size of a TXT or MIDI file that can be uploaded as a song.
+const maxSongSize = 16 << 20
+
 // Handler implements the /v1/songs endpoints.
 type Handler struct {
 	logger *slog.Logger
 	r      chi.Router
 
-	songRepo   song.Repository
-	songSvc    song.Service
-	mediaStore media.Store
-	mediaSvc   media.Service
+	songRepo     song.Repository
+	songSvc      song.Service
+	revisionRepo revision.Repository
+	headerRepo   header.Repository
+	variantRepo  variant.Repository
+	reviewRepo   review.Repository
+	mediaStore   media.Store
+	mediaSvc     media.Service
+	batchSvc     batch.Service
+	batchRepo    batch.Repository
+	events       event.Bus
+
+	// strictPreconditions indicates whether mutating requests must include an If-Match header.
+	strictPreconditions bool
 }
 
 // NewHandler creates a new Handler instance using the specified services.
+// If strictPreconditions is true, mutating requests without an If-Match header are rejected with 428 Precondition Required.
 func NewHandler(
 	logger *slog.Logger,
 	songRepo song.Repository,
 	songSvc song.Service,
+	revisionRepo revision.Repository,
+	headerRepo header.Repository,
+	variantRepo variant.Repository,
+	reviewRepo review.Repository,
 	mediaStore media.Store,
 	mediaSvc media.Service,
+	batchSvc batch.Service,
+	batchRepo batch.Repository,
+	events event.Bus,
+	strictPreconditions bool,
 ) *Handler {
 	r := chi.NewRouter()
 	h := &Handler{
@@ -37,42 +71,82 @@ func NewHandler(
 		r,
 		songRepo,
 		songSvc,
+		revisionRepo,
+		headerRepo,
+		variantRepo,
+		reviewRepo,
 		mediaStore,
 		mediaSvc,
+		batchSvc,
+		batchRepo,
+		events,
+		strictPreconditions,
 	}
 
-	r.With(middleware.RequireContentType("text/plain", "text/x-ultrastar"), render.ContentTypeNegotiation("application/json")).Post("/", h.Create)
-	r.With(middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json")).Get("/", h.Find)
+	r.With(middleware.RequireContentType("text/plain", "text/x-ultrastar", "audio/midi", "audio/x-midi"), render.ContentTypeNegotiation("application/json")).Post("/", h.Create)
+	r.With(middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json", "text/csv")).Get("/", h.Find)
+	r.With(middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json")).Get("/trash", h.FindDeleted)
+	r.With(middleware.ContentTypeJSON, render.ContentTypeNegotiation("application/json")).Post("/transform", h.BulkTransform)
+	r.With(middleware.ContentTypeJSON, render.ContentTypeNegotiation("application/json")).Post("/batch", h.CreateBatch)
+	r.With(middleware.RequireContentType("text/csv"), render.ContentTypeNegotiation("application/json")).Post("/sheet", h.ImportSheet)
+	r.With(middleware.UUID("uuid"), render.ContentTypeNegotiation("application/json")).Get("/batch/{uuid}", h.GetBatch)
+	r.With(middleware.UUID("uuid"), middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json")).Get("/batch/{uuid}/results", h.GetBatchResults)
 
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.UUID("uuid"))
 		r.Delete("/{uuid}", h.Delete)
+		r.With(render.ContentTypeNegotiation("application/json")).Post("/{uuid}/restore", h.Restore)
 
 		r.Group(func(r chi.Router) {
 			r.Use(h.FetchSong)
 			r.With(render.ContentTypeNegotiation("application/json")).Get("/{uuid}", h.Get)
 			r.With(render.ContentTypeNegotiation("text/x-ultrastar", "text/plain")).Get("/{uuid}/txt", h.GetTxt)
+			r.With(render.ContentTypeNegotiation("application/json")).Get("/{uuid}/notes", h.GetNotes)
+			r.With(render.ContentTypeNegotiation("text/plain", "application/json", "application/x-lrc")).Get("/{uuid}/lyrics", h.GetLyrics)
+			r.With(render.ContentTypeNegotiation("application/json")).Get("/{uuid}/medley/suggestion", h.SuggestMedley)
 			// r.Get("{uuid}/archive", h.GetArchive)
 			r.With(render.ContentTypeNegotiation("image/*")).Get("/{uuid}/cover", h.GetCover)
 			r.With(render.ContentTypeNegotiation("image/*")).Get("/{uuid}/background", h.GetBackground)
 			r.With(render.ContentTypeNegotiation("audio/*")).Get("/{uuid}/audio", h.GetAudio)
 			r.With(render.ContentTypeNegotiation("video/*")).Get("/{uuid}/video", h.GetVideo)
+			r.With(middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json")).Get("/{uuid}/revisions", h.FindRevisions)
+			r.With(middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json")).Get("/{uuid}/comments", h.FindComments)
+			r.With(h.FetchRevision, render.ContentTypeNegotiation("application/json")).Get("/{uuid}/revisions/{revision}/diff", h.GetRevisionDiff)
 
 			// Deleting media is allowed in uploads
-			r.Delete("/{uuid}/cover", h.DeleteCover)
-			r.Delete("/{uuid}/background", h.DeleteBackground)
-			r.Delete("/{uuid}/audio", h.DeleteAudio)
-			r.Delete("/{uuid}/video", h.DeleteVideo)
+			r.With(h.CheckPrecondition).Delete("/{uuid}/cover", h.DeleteCover)
+			r.With(h.CheckPrecondition).Delete("/{uuid}/background", h.DeleteBackground)
+			r.With(h.CheckPrecondition).Delete("/{uuid}/audio", h.DeleteAudio)
+			r.With(h.CheckPrecondition).Delete("/{uuid}/video", h.DeleteVideo)
 		})
 
 		r.Group(func(r chi.Router) {
-			r.Use(h.FetchSong, h.CheckModify)
+			r.Use(h.FetchSong, h.CheckModify, h.CheckPrecondition)
 			r.With(middleware.ContentTypeJSON).Patch("/{uuid}", h.Update)
 			r.With(middleware.RequireContentType("text/plain", "text/x-ultrastar"), render.ContentTypeNegotiation("application/json")).Put("/{uuid}/txt", h.ReplaceTxt)
+			r.With(middleware.ContentTypeJSON, render.ContentTypeNegotiation("application/json")).Patch("/{uuid}/notes", h.EditNotes)
+			r.With(middleware.ContentTypeJSON, render.ContentTypeNegotiation("application/json")).Post("/{uuid}/transform", h.Transform)
 			r.With(middleware.RequireContentType("image/*")).Put("/{uuid}/cover", h.ReplaceCover)
 			r.With(middleware.RequireContentType("image/*")).Put("/{uuid}/background", h.ReplaceBackground)
 			r.With(middleware.RequireContentType("audio/*")).Put("/{uuid}/audio", h.ReplaceAudio)
 			r.With(middleware.RequireContentType("video/*")).Put("/{uuid}/video", h.ReplaceVideo)
+			r.With(h.FetchRevision, render.ContentTypeNegotiation("application/json")).Post("/{uuid}/revisions/{revision}/revert", h.RevertRevision)
+		})
+
+		r.Group(func(r chi.Router) {
+			// Variant groups do not change the song itself, so no precondition is required.
+			r.Use(h.FetchSong, h.CheckModify)
+			r.With(middleware.ContentTypeJSON, render.ContentTypeNegotiation("application/json")).Put("/{uuid}/variant", h.LinkVariant)
+			r.Delete("/{uuid}/variant", h.UnlinkVariant)
+		})
+
+		r.Group(func(r chi.Router) {
+			// The review workflow does not change the song itself, so no precondition is required.
+			r.Use(h.FetchSong, h.CheckModify)
+			r.With(middleware.ContentTypeJSON, render.ContentTypeNegotiation("application/json")).Put("/{uuid}/review", h.SetReviewState)
+			r.With(middleware.ContentTypeJSON, render.ContentTypeNegotiation("application/json")).Post("/{uuid}/comments", h.CreateComment)
+			r.With(h.FetchComment, middleware.ContentTypeJSON, render.ContentTypeNegotiation("application/json")).Patch("/{uuid}/comments/{comment}", h.UpdateComment)
+			r.Delete("/{uuid}/comments/{comment}", h.DeleteComment)
 		})
 	})
 	return h
@@ -82,3 +156,48 @@ func NewHandler(
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.r.ServeHTTP(w, r)
 }
+
+// recordRevision records the current state of song as a new revision.
+// The author of the revision is taken from the From header of r.
+// Karman does not authenticate users, so the author is purely informational.
+// Failing to record a revision does not fail the request because the song has already been changed at this point.
+func (h *Handler) recordRevision(r *http.Request, song model.Song) {
+	rev := model.NewSongRevision(song, r.Header.Get("From"))
+	if err := h.revisionRepo.CreateRevision(r.Context(), song.UUID, &rev); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not record song revision.", "uuid", song.UUID, tint.Err(err))
+	}
+}
+
+// updateSong saves song in the song repository.
+// If the request contained a precondition that was matched by CheckPrecondition,
+// the song is only saved if it has not been modified since.
+// Otherwise, core.ErrPreconditionFailed is returned.
+func (h *Handler) updateSong(ctx context.Context, song *model.Song) error {
+	if version, ok := GetVersion(ctx); ok {
+		return h.songRepo.UpdateSongIfUnmodified(ctx, song, version)
+	}
+	return h.songRepo.UpdateSong(ctx, song)
+}
+
+// renderUpdateError renders the error err returned by updateSong.
+func (h *Handler) renderUpdateError(w http.ResponseWriter, r *http.Request, song model.Song, err error) {
+	if errors.Is(err, core.ErrPreconditionFailed) {
+		_ = render.Render(w, r, apierror.PreconditionFailed(""))
+		return
+	}
+	h.logger.ErrorContext(r.Context(), "Could not update song.", "uuid", song.UUID, tint.Err(err))
+	_ = render.Render(w, r, apierror.ErrInternalServerError)
+}
+
+// setETag sets the ETag header of the response to the current entity tag of song.
+func setETag(w http.ResponseWriter, song model.Song) {
+	w.Header().Set("ETag", middleware.ETag(song.UpdatedAt))
+}
+
+// publish sends e to the event bus.
+// Events are informational only, so a failure to publish an event does not fail the request.
+func (h *Handler) publish(ctx context.Context, e event.Event) {
+	if err := h.events.Publish(ctx, e); err != nil {
+		h.logger.WarnContext(ctx, "Could not publish event.", "type", e.Type, tint.Err(err))
+	}
+}
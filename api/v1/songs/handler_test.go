@@ -14,8 +14,15 @@ import (
 	"github.com/jackc/pgxutil"
 
 	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/core/artist"
+	"github.com/Karaoke-Manager/karman/core/batch"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/header"
 	"github.com/Karaoke-Manager/karman/core/media"
+	"github.com/Karaoke-Manager/karman/core/review"
+	"github.com/Karaoke-Manager/karman/core/revision"
 	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/core/variant"
 	"github.com/Karaoke-Manager/karman/pkg/nolog"
 	_ "github.com/Karaoke-Manager/karman/pkg/render/json"
 	"github.com/Karaoke-Manager/karman/test"
@@ -27,13 +34,20 @@ import (
 func setupHandler(t *testing.T, prefix string) (*Handler, pgxutil.DB) {
 	db := test.NewDB(t)
 	songRepo := song.NewDBRepository(nolog.Logger, db)
-	songSvc := song.NewService()
+	songSvc := song.NewService(artist.NewDBRepository(nolog.Logger, db), song.DefaultNaming)
 	mediaStore := media.NewMemStore()
 	mediaRepo := media.NewDBRepository(nolog.Logger, db)
 	mediaService := media.NewFakeService(mediaRepo)
+	revisionRepo := revision.NewDBRepository(nolog.Logger, db)
+	headerRepo := header.NewDBRepository(nolog.Logger, db)
+	variantRepo := variant.NewDBRepository(nolog.Logger, db)
+	reviewRepo := review.NewDBRepository(nolog.Logger, db)
+	events := event.NewMemBus()
+	batchRepo := batch.NewDBRepository(nolog.Logger, db)
+	batchSvc := batch.NewService(nolog.Logger, batchRepo, songRepo, songSvc, revisionRepo, events, nil, nil)
 
 	// workaround to support the prefix
-	h := NewHandler(nolog.Logger, songRepo, songSvc, mediaStore, mediaService)
+	h := NewHandler(nolog.Logger, songRepo, songSvc, revisionRepo, headerRepo, variantRepo, reviewRepo, mediaStore, mediaService, batchSvc, batchRepo, events, false)
 	r := chi.NewRouter()
 	r.Mount(strings.TrimSuffix(prefix, "/")+"/", h.r)
 	h.r = r
@@ -0,0 +1,69 @@
+package songs
+
+import (
+	"mime"
+	"net/http"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	songsvc "github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/pkg/mediatype"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// mediaTypeLRC is the media type of lyrics in the LRC format.
+var mediaTypeLRC = mediatype.MustParse("application/x-lrc")
+
+// GetLyrics implements the GET /v1/songs/{uuid}/lyrics endpoint.
+// JSON responses contain the lyrics of all players.
+// Plain text and LRC responses contain the lyrics of a single player, selected by the player query parameter.
+// The timing query parameter selects between line and syllable timing in LRC responses.
+func (h *Handler) GetLyrics(w http.ResponseWriter, r *http.Request) {
+	song := MustGetSong(r.Context())
+	t := render.MustGetNegotiatedContentType(r)
+	if t.Equals(mediatype.ApplicationJSON) {
+		setETag(w, song)
+		resp := schema.FromSongLyrics(song)
+		_ = render.Render(w, r, &resp)
+		return
+	}
+
+	player, notes := 0, song.NotesP1
+	switch r.URL.Query().Get("player") {
+	case "", "1":
+	case "2":
+		if !song.IsDuet() {
+			_ = render.Render(w, r, apierror.BadRequest("Invalid player: the song is not a duet."))
+			return
+		}
+		player, notes = 1, song.NotesP2
+	default:
+		_ = render.Render(w, r, apierror.BadRequest("Invalid player: must be 1 or 2."))
+		return
+	}
+	var enhanced bool
+	switch r.URL.Query().Get("timing") {
+	case "", "line":
+	case "syllable":
+		enhanced = true
+	default:
+		_ = render.Render(w, r, apierror.BadRequest("Invalid timing: must be line or syllable."))
+		return
+	}
+
+	h.songSvc.Prepare(r.Context(), &song)
+	lines := songsvc.Lyrics(song, notes)
+	if t.Equals(mediatype.TextPlain) {
+		t = t.WithoutParameters("charset", "utf-8")
+	}
+	w.Header().Set("Content-Type", t.String())
+	setETag(w, song)
+	if t.Equals(mediaTypeLRC) {
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": song.LyricsFileNames[player]}))
+		w.WriteHeader(http.StatusOK)
+		_ = songsvc.WriteLRC(w, song, lines, enhanced)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	_ = songsvc.WriteLyrics(w, lines)
+}
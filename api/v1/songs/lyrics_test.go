@@ -0,0 +1,73 @@
+//go:build database
+
+package songs
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_GetLyrics(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	s := songWithNotes(t, db)
+	url := fmt.Sprintf("/v1/songs/%s/lyrics", s.UUID)
+
+	t.Run("200 OK (Text)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		r.Header.Set("Accept", "text/plain")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if expected := "Hello\nworld\n"; string(body) != expected {
+			t.Errorf("GET %s responded with %q, expected %q", url, body, expected)
+		}
+	})
+	t.Run("200 OK (JSON)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		r.Header.Set("Accept", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var data schema.SongLyrics
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("GET %s responded with invalid lyrics schema: %s", url, err)
+		}
+		if len(data.P1) != 2 || len(data.P1[0].Syllables) != 2 || data.P2 != nil {
+			t.Errorf("GET %s responded with %+v, expected two lines for P1 and no P2", url, data)
+		}
+	})
+	t.Run("200 OK (LRC)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url+"?timing=syllable", nil)
+		r.Header.Set("Accept", "application/x-lrc")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		if resp.Header.Get("Content-Disposition") == "" {
+			t.Errorf("GET %s returned no Content-Disposition header, expected non-empty value", url)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if !strings.Contains(string(body), "]<") || !strings.Contains(string(body), "world") {
+			t.Errorf("GET %s responded with %q, expected enhanced LRC lyrics", url, body)
+		}
+	})
+	t.Run("400 Bad Request (Invalid UUID)", test.InvalidUUID(h, http.MethodGet, fmt.Sprintf("/v1/songs/%s/lyrics", testdata.InvalidUUID)))
+	t.Run("400 Bad Request (Player)", test.HTTPError(h, http.MethodGet, url+"?player=2", http.StatusBadRequest))
+	t.Run("400 Bad Request (Timing)", test.HTTPError(h, http.MethodGet, url+"?timing=word", http.StatusBadRequest))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodGet, fmt.Sprintf("/v1/songs/%s/lyrics", uuid.New()), http.StatusNotFound))
+}
@@ -1,6 +1,8 @@
 package songs
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"mime"
 	"net/http"
@@ -10,43 +12,105 @@ import (
 	"github.com/lmittmann/tint"
 
 	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
 	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/event"
+	songsvc "github.com/Karaoke-Manager/karman/core/song"
 	"github.com/Karaoke-Manager/karman/model"
 	"github.com/Karaoke-Manager/karman/pkg/mediatype"
 	"github.com/Karaoke-Manager/karman/pkg/render"
 )
 
 // GetTxt implements the GET /v1/songs/{uuid}/txt endpoint.
+// The naming query parameter can be used to specify a custom template for the referenced file names.
+// The format version and timing mode can be selected via media type parameters or query parameters of the same name.
+// The entity tag of the response depends on the format and naming template (see middleware.VariantETag).
 func (h *Handler) GetTxt(w http.ResponseWriter, r *http.Request) {
 	song := MustGetSong(r.Context())
-	h.songSvc.Prepare(r.Context(), &song)
-
 	t := render.MustGetNegotiatedContentType(r)
+	dialect, err := txtDialect(r, t)
+	if err != nil {
+		_ = render.Render(w, r, apierror.BadRequest("Invalid TXT format: "+err.Error()))
+		return
+	}
+	var naming songsvc.Naming
+	param := r.URL.Query().Get("naming")
+	if param != "" {
+		var err error
+		if naming.File, err = songsvc.ParseTemplate(param); err != nil {
+			_ = render.Render(w, r, apierror.BadRequest("Invalid naming template: "+err.Error()))
+			return
+		}
+	}
+	h.songSvc.PrepareWithNaming(r.Context(), &song, naming)
+
 	if t.Equals(mediatype.TextPlain) {
 		t = t.WithoutParameters("charset", "utf-8")
 	}
+	if !dialect.IsZero() {
+		t = t.WithParameters(map[string]string{"relative": strconv.FormatBool(dialect.Relative)})
+		if dialect.Version != "" {
+			t = t.WithParameters(map[string]string{"version": dialect.Version})
+		}
+	}
 	w.Header().Set("Content-Type", t.String())
 	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": song.TxtFileName}))
+	w.Header().Add("Vary", "Accept")
+	// Each format and naming template produces a different representation.
+	variant := ""
+	if !dialect.IsZero() || param != "" {
+		variant = fmt.Sprintf("%s;%t;%s", dialect.Version, dialect.Relative, param)
+	}
+	w.Header().Set("ETag", middleware.VariantETag(song.UpdatedAt, variant))
 	w.WriteHeader(http.StatusOK)
-	_ = txt.WriteSong(w, song.Song)
+	_ = songsvc.WriteTxt(w, song, dialect)
+}
+
+// txtDialect determines the TXT dialect requested by r.
+// The format version and timing mode are taken from the version and relative parameters of the negotiated media type t.
+// Query parameters of the same names take precedence.
+func txtDialect(r *http.Request, t mediatype.MediaType) (songsvc.Dialect, error) {
+	version, relative := t.Parameter("version"), t.Parameter("relative")
+	if r.URL.Query().Has("version") {
+		version = r.URL.Query().Get("version")
+	}
+	if r.URL.Query().Has("relative") {
+		relative = r.URL.Query().Get("relative")
+	}
+	var rel bool
+	if relative != "" {
+		var err error
+		if rel, err = strconv.ParseBool(relative); err != nil {
+			return songsvc.Dialect{}, fmt.Errorf("invalid relative value %q", relative)
+		}
+	}
+	return songsvc.ParseDialect(version, rel)
 }
 
 // ReplaceTxt implements the PUT /v1/songs/{uuid}/txt endpoint.
+// The TXT data is processed in the same way as in Create.
 func (h *Handler) ReplaceTxt(w http.ResponseWriter, r *http.Request) {
 	var err error
+	var maxBytesErr *http.MaxBytesError
 	song := MustGetSong(r.Context())
-	song.Song, err = txt.NewReader(r.Body).ReadSong()
-	if err != nil {
+	song.Song, err = txt.NewReader(http.MaxBytesReader(w, r.Body, maxSongSize)).ReadSong()
+	if errors.As(err, &maxBytesErr) {
+		_ = render.Render(w, r, apierror.ErrContentTooLarge)
+		return
+	} else if err != nil {
 		h.logger.WarnContext(r.Context(), "Could not parse UltraStar TXT.", tint.Err(err))
 		_ = render.Render(w, r, apierror.InvalidUltraStarTXT(err))
 		return
 	}
 	h.songSvc.ParseArtists(r.Context(), &song)
-	err = h.songRepo.UpdateSong(r.Context(), &song)
-	if err != nil {
-		_ = render.Render(w, r, apierror.ErrInternalServerError)
+	h.songSvc.DetectMedley(r.Context(), &song)
+	if err = h.updateSong(r.Context(), &song); err != nil {
+		h.renderUpdateError(w, r, song, err)
 		return
 	}
+	h.recordRevision(r, song)
+	setETag(w, song)
+	h.publish(r.Context(), event.SongUpdated(song))
 	h.songSvc.Prepare(r.Context(), &song)
 	s := schema.FromSong(song)
 	_ = render.Render(w, r, &s)
@@ -127,10 +191,12 @@ func (h *Handler) ReplaceCover(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	song.CoverFile = &file
-	if err = h.songRepo.UpdateSong(r.Context(), &song); err != nil {
-		_ = render.Render(w, r, apierror.ErrInternalServerError)
+	if err = h.updateSong(r.Context(), &song); err != nil {
+		h.renderUpdateError(w, r, song, err)
 		return
 	}
+	setETag(w, song)
+	h.publish(r.Context(), event.SongUpdated(song))
 	_ = render.NoContent(w, r)
 }
 
@@ -144,10 +210,12 @@ func (h *Handler) ReplaceBackground(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	song.BackgroundFile = &file
-	if err = h.songRepo.UpdateSong(r.Context(), &song); err != nil {
-		_ = render.Render(w, r, apierror.ErrInternalServerError)
+	if err = h.updateSong(r.Context(), &song); err != nil {
+		h.renderUpdateError(w, r, song, err)
 		return
 	}
+	setETag(w, song)
+	h.publish(r.Context(), event.SongUpdated(song))
 	_ = render.NoContent(w, r)
 }
 
@@ -161,10 +229,12 @@ func (h *Handler) ReplaceAudio(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	song.AudioFile = &file
-	if err = h.songRepo.UpdateSong(r.Context(), &song); err != nil {
-		_ = render.Render(w, r, apierror.ErrInternalServerError)
+	if err = h.updateSong(r.Context(), &song); err != nil {
+		h.renderUpdateError(w, r, song, err)
 		return
 	}
+	setETag(w, song)
+	h.publish(r.Context(), event.SongUpdated(song))
 	_ = render.NoContent(w, r)
 }
 
@@ -178,10 +248,12 @@ func (h *Handler) ReplaceVideo(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	song.VideoFile = &file
-	if err = h.songRepo.UpdateSong(r.Context(), &song); err != nil {
-		_ = render.Render(w, r, apierror.ErrInternalServerError)
+	if err = h.updateSong(r.Context(), &song); err != nil {
+		h.renderUpdateError(w, r, song, err)
 		return
 	}
+	setETag(w, song)
+	h.publish(r.Context(), event.SongUpdated(song))
 	_ = render.NoContent(w, r)
 }
 
@@ -189,10 +261,12 @@ func (h *Handler) ReplaceVideo(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) DeleteCover(w http.ResponseWriter, r *http.Request) {
 	song := MustGetSong(r.Context())
 	song.CoverFile = nil
-	if err := h.songRepo.UpdateSong(r.Context(), &song); err != nil {
-		_ = render.Render(w, r, apierror.ErrInternalServerError)
+	if err := h.updateSong(r.Context(), &song); err != nil {
+		h.renderUpdateError(w, r, song, err)
 		return
 	}
+	setETag(w, song)
+	h.publish(r.Context(), event.SongUpdated(song))
 	_ = render.NoContent(w, r)
 }
 
@@ -200,10 +274,12 @@ func (h *Handler) DeleteCover(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) DeleteBackground(w http.ResponseWriter, r *http.Request) {
 	song := MustGetSong(r.Context())
 	song.BackgroundFile = nil
-	if err := h.songRepo.UpdateSong(r.Context(), &song); err != nil {
-		_ = render.Render(w, r, apierror.ErrInternalServerError)
+	if err := h.updateSong(r.Context(), &song); err != nil {
+		h.renderUpdateError(w, r, song, err)
 		return
 	}
+	setETag(w, song)
+	h.publish(r.Context(), event.SongUpdated(song))
 	_ = render.NoContent(w, r)
 }
 
@@ -211,10 +287,12 @@ func (h *Handler) DeleteBackground(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) DeleteAudio(w http.ResponseWriter, r *http.Request) {
 	song := MustGetSong(r.Context())
 	song.AudioFile = nil
-	if err := h.songRepo.UpdateSong(r.Context(), &song); err != nil {
-		_ = render.Render(w, r, apierror.ErrInternalServerError)
+	if err := h.updateSong(r.Context(), &song); err != nil {
+		h.renderUpdateError(w, r, song, err)
 		return
 	}
+	setETag(w, song)
+	h.publish(r.Context(), event.SongUpdated(song))
 	_ = render.NoContent(w, r)
 }
 
@@ -222,9 +300,11 @@ func (h *Handler) DeleteAudio(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) DeleteVideo(w http.ResponseWriter, r *http.Request) {
 	song := MustGetSong(r.Context())
 	song.VideoFile = nil
-	if err := h.songRepo.UpdateSong(r.Context(), &song); err != nil {
-		_ = render.Render(w, r, apierror.ErrInternalServerError)
+	if err := h.updateSong(r.Context(), &song); err != nil {
+		h.renderUpdateError(w, r, song, err)
 		return
 	}
+	setETag(w, song)
+	h.publish(r.Context(), event.SongUpdated(song))
 	_ = render.NoContent(w, r)
 }
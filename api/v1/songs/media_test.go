@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	neturl "net/url"
 	"slices"
 	"strings"
 	"testing"
@@ -16,6 +17,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
 	"github.com/Karaoke-Manager/karman/api/schema"
 	"github.com/Karaoke-Manager/karman/core/media"
 	"github.com/Karaoke-Manager/karman/model"
@@ -39,6 +41,12 @@ func TestHandler_GetTxt(t *testing.T) {
 		if resp.Header.Get("Content-Disposition") == "" {
 			t.Errorf("GET %s returned no Content-Disposition header, expected non-empty value", url)
 		}
+		if etag := resp.Header.Get("ETag"); etag != middleware.ETag(songWithCover.UpdatedAt) {
+			t.Errorf("GET %s returned ETag %s, expected %s", url, etag, middleware.ETag(songWithCover.UpdatedAt))
+		}
+		if vary := resp.Header.Get("Vary"); vary != "Accept" {
+			t.Errorf("GET %s returned Vary %q, expected %q", url, vary, "Accept")
+		}
 		body, err := txt.NewReader(resp.Body).ReadSong()
 		if err != nil {
 			t.Errorf("GET %s responded with an invalid UltraStar song", url)
@@ -50,7 +58,42 @@ func TestHandler_GetTxt(t *testing.T) {
 			t.Errorf("GET %s responded with no #COVER, expected non-empty string", url)
 		}
 	})
+	t.Run("200 OK (Naming)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url+"?naming="+neturl.QueryEscape("{title}< [{kind}]>{ext}"), nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s returned status %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		body, err := txt.NewReader(resp.Body).ReadSong()
+		if err != nil {
+			t.Errorf("GET %s responded with an invalid UltraStar song", url)
+		}
+		if !strings.HasPrefix(body.CoverFileName, songWithCover.Title+" [CO]") {
+			t.Errorf(`GET %s responded with "#COVER:%s", expected the name to start with %q`, url, body.CoverFileName, songWithCover.Title+" [CO]")
+		}
+	})
+	t.Run("200 OK (Version)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		r.Header.Set("Accept", "text/x-ultrastar; version=2.0.0")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s returned status %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		if etag := resp.Header.Get("ETag"); etag == "" || etag == middleware.ETag(songWithCover.UpdatedAt) {
+			t.Errorf("GET %s returned ETag %s, expected an entity tag of the 2.0.0 variant", url, etag)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if !strings.HasPrefix(string(body), "#VERSION:2.0.0") {
+			t.Errorf("GET %s responded with %q, expected a #VERSION:2.0.0 header", url, body)
+		}
+		if strings.Contains(string(body), "#MP3:") {
+			t.Errorf("GET %s responded with an #MP3 header, expected none", url)
+		}
+	})
 	t.Run("400 Bad Request (Invalid UUID)", test.InvalidUUID(h, http.MethodGet, fmt.Sprintf("/v1/songs/%s/txt", testdata.InvalidUUID)))
+	t.Run("400 Bad Request (Version)", test.HTTPError(h, http.MethodGet, url+"?version=3.0.0", http.StatusBadRequest))
+	t.Run("400 Bad Request (Relative)", test.HTTPError(h, http.MethodGet, url+"?version=2.0.0&relative=true", http.StatusBadRequest))
+	t.Run("400 Bad Request (Naming)", test.HTTPError(h, http.MethodGet, url+"?naming="+neturl.QueryEscape("{album}"), http.StatusBadRequest))
 	t.Run("404 Not Found", test.HTTPError(h, http.MethodGet, fmt.Sprintf("/v1/songs/%s/txt", uuid.New()), http.StatusNotFound))
 }
 
@@ -97,6 +140,14 @@ func TestHandler_ReplaceTxt(t *testing.T) {
 	t.Run("400 Bad Request (Missing Content-Type)", test.MissingContentType(h, http.MethodPut, url, "text/plain", "text/x-ultrastar"))
 	t.Run("404 Not Found", test.HTTPError(h, http.MethodPut, fmt.Sprintf("/v1/songs/%s/txt", uuid.New()), http.StatusNotFound))
 	t.Run("409 Conflict", testSongConflict(h, http.MethodPut, "/v1/songs/%s/txt", songWithUpload.UUID))
+	t.Run("413 Content Too Large", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPut, url, strings.NewReader(strings.Repeat("#COMMENT:x\n", maxSongSize/10+1)))
+		r.Header.Set("Content-Type", "text/plain")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusRequestEntityTooLarge {
+			t.Errorf("PUT %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusRequestEntityTooLarge)
+		}
+	})
 	t.Run("415 Unsupported Media Type", test.InvalidContentType(h, http.MethodPut, url, "application/json", "text/plain", "text/x-ultrastar"))
 }
 
@@ -0,0 +1,23 @@
+package songs
+
+import (
+	"net/http"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// SuggestMedley implements the GET /v1/songs/{uuid}/medley/suggestion endpoint.
+// The suggestion is not stored.
+// Clients can accept the suggestion by updating the song.
+func (h *Handler) SuggestMedley(w http.ResponseWriter, r *http.Request) {
+	song := MustGetSong(r.Context())
+	suggestion, ok := h.songSvc.SuggestMedley(r.Context(), song)
+	if !ok {
+		_ = render.Render(w, r, apierror.NoMedleySuggestion(song))
+		return
+	}
+	resp := schema.FromMedleySuggestion(suggestion)
+	_ = render.Render(w, r, &resp)
+}
@@ -0,0 +1,68 @@
+//go:build database
+
+package songs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"codello.dev/ultrastar"
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_SuggestMedley(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	s := testdata.SimpleSong(t, db)
+	s.NotesP1 = ultrastar.Notes{
+		{Type: ultrastar.NoteTypeRegular, Start: 0, Duration: 4, Text: "Intro"},
+		{Type: ultrastar.NoteTypeLineBreak, Start: 6},
+		{Type: ultrastar.NoteTypeRegular, Start: 8, Duration: 4, Text: "Sing"},
+		{Type: ultrastar.NoteTypeLineBreak, Start: 14},
+		{Type: ultrastar.NoteTypeRegular, Start: 16, Duration: 4, Text: "along"},
+		{Type: ultrastar.NoteTypeLineBreak, Start: 22},
+		{Type: ultrastar.NoteTypeRegular, Start: 24, Duration: 4, Text: "Sing"},
+		{Type: ultrastar.NoteTypeLineBreak, Start: 30},
+		{Type: ultrastar.NoteTypeRegular, Start: 32, Duration: 4, Text: "along"},
+	}
+	if err := song.NewDBRepository(nolog.Logger, db).UpdateSong(context.TODO(), &s); err != nil {
+		t.Fatalf("could not set song notes: %s", err)
+	}
+	noChorus := songWithNotes(t, db)
+	url := fmt.Sprintf("/v1/songs/%s/medley/suggestion", s.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var data schema.MedleySuggestion
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("GET %s responded with invalid medley suggestion schema: %s", url, err)
+		}
+		if data.MedleyStartBeat != 8 || data.MedleyEndBeat != 20 {
+			t.Errorf("GET %s suggested medley %d-%d, expected %d-%d", url, data.MedleyStartBeat, data.MedleyEndBeat, 8, 20)
+		}
+	})
+	t.Run("400 Bad Request", test.InvalidUUID(h, http.MethodGet, "/v1/songs/"+testdata.InvalidUUID+"/medley/suggestion"))
+	t.Run("404 Not Found (Song)", test.HTTPError(h, http.MethodGet, fmt.Sprintf("/v1/songs/%s/medley/suggestion", uuid.New()), http.StatusNotFound))
+	t.Run("404 Not Found (No Chorus)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/v1/songs/%s/medley/suggestion", noChorus.UUID), nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusNotFound, apierror.TypeNoMedleySuggestion, map[string]any{
+			"uuid": noChorus.UUID.String(),
+		})
+	})
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"time"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/lmittmann/tint"
 
 	"github.com/Karaoke-Manager/karman/api/apierror"
@@ -22,6 +25,12 @@ type contextKey int
 const (
 	// contextKeyInstance identifies a Song instance in a context.
 	contextKeyInstance contextKey = iota
+	// contextKeyRevision identifies a SongRevision instance in a context.
+	contextKeyRevision
+	// contextKeyComment identifies a SongComment instance in a context.
+	contextKeyComment
+	// contextKeyVersion identifies the version of a song that was matched by a precondition.
+	contextKeyVersion
 )
 
 // SetSong sets the song instance in ctx.
@@ -42,11 +51,60 @@ func MustGetSong(ctx context.Context) model.Song {
 	return ctx.Value(contextKeyInstance).(model.Song)
 }
 
+// SetVersion sets the version of the song that was matched by the precondition of the request in ctx.
+// The version of a song is its UpdatedAt timestamp.
+func SetVersion(ctx context.Context, version time.Time) context.Context {
+	return context.WithValue(ctx, contextKeyVersion, version)
+}
+
+// GetVersion returns the version of the song that was matched by the precondition of the request.
+// If the request was not conditional, the second return value will be false.
+func GetVersion(ctx context.Context) (time.Time, bool) {
+	version, ok := ctx.Value(contextKeyVersion).(time.Time)
+	return version, ok
+}
+
+// SetRevision sets the song revision instance in ctx.
+func SetRevision(ctx context.Context, rev model.SongRevision) context.Context {
+	return context.WithValue(ctx, contextKeyRevision, rev)
+}
+
+// GetRevision returns a model.SongRevision instance from the context.
+// If the context does not contain a revision instance, the second return value will be false.
+func GetRevision(ctx context.Context) (model.SongRevision, bool) {
+	rev, ok := ctx.Value(contextKeyRevision).(model.SongRevision)
+	return rev, ok
+}
+
+// MustGetRevision returns a model.SongRevision instance from the context.
+// In contrast to GetRevision this function panics if the context does not contain a revision instance.
+func MustGetRevision(ctx context.Context) model.SongRevision {
+	return ctx.Value(contextKeyRevision).(model.SongRevision)
+}
+
+// SetComment sets the song comment instance in ctx.
+func SetComment(ctx context.Context, comment model.SongComment) context.Context {
+	return context.WithValue(ctx, contextKeyComment, comment)
+}
+
+// GetComment returns a model.SongComment instance from the context.
+// If the context does not contain a comment instance, the second return value will be false.
+func GetComment(ctx context.Context) (model.SongComment, bool) {
+	comment, ok := ctx.Value(contextKeyComment).(model.SongComment)
+	return comment, ok
+}
+
+// MustGetComment returns a model.SongComment instance from the context.
+// In contrast to GetComment this function panics if the context does not contain a comment instance.
+func MustGetComment(ctx context.Context) model.SongComment {
+	return ctx.Value(contextKeyComment).(model.SongComment)
+}
+
 // FetchSong is a middleware that fetches the model.Song instance identified by the request and stores it in the request context.
+// Songs in the trash are rejected with 410 Gone.
 func (h *Handler) FetchSong(next http.Handler) http.Handler {
 	fn := func(w http.ResponseWriter, r *http.Request) {
 		id := middleware.MustGetUUID(r.Context())
-		// TODO: Maybe support 410 for soft deleted?
 		song, err := h.songRepo.GetSong(r.Context(), id)
 		if errors.Is(err, core.ErrNotFound) {
 			_ = render.Render(w, r, apierror.ErrNotFound)
@@ -56,6 +114,10 @@ func (h *Handler) FetchSong(next http.Handler) http.Handler {
 			_ = render.Render(w, r, apierror.ErrInternalServerError)
 			return
 		}
+		if song.Deleted() {
+			_ = render.Render(w, r, apierror.SongDeleted(song))
+			return
+		}
 		ctx := SetSong(r.Context(), song)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	}
@@ -74,3 +136,83 @@ func (h *Handler) CheckModify(next http.Handler) http.Handler {
 	}
 	return http.HandlerFunc(fn)
 }
+
+// CheckPrecondition is a middleware that evaluates the If-Match header of the request against the current song.
+// The entity tag of a song is derived from its UpdatedAt timestamp.
+// If the precondition does not match, the request is rejected with 412 Precondition Failed.
+// If strict preconditions are enabled, requests without an If-Match header are rejected with 428 Precondition Required.
+// The matched version is stored in the request context,
+// so that the song can be updated only if it has not been modified in the meantime (see Handler.updateSong).
+// This middleware must be used after FetchSong.
+func (h *Handler) CheckPrecondition(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		song := MustGetSong(r.Context())
+		if !middleware.HasIfMatch(r) {
+			if h.strictPreconditions {
+				_ = render.Render(w, r, apierror.ErrPreconditionRequired)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !middleware.IfMatchVersion(r, song.UpdatedAt) {
+			_ = render.Render(w, r, apierror.PreconditionFailed(middleware.ETag(song.UpdatedAt)))
+			return
+		}
+		ctx := SetVersion(r.Context(), song.UpdatedAt)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+	return http.HandlerFunc(fn)
+}
+
+// FetchRevision is a middleware that fetches the model.SongRevision instance identified by the {revision} parameter
+// and stores it in the request context.
+// This middleware must be used after FetchSong.
+func (h *Handler) FetchRevision(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		song := MustGetSong(r.Context())
+		id, err := uuid.Parse(chi.URLParam(r, "revision"))
+		if err != nil {
+			_ = render.Render(w, r, apierror.ErrInvalidUUID)
+			return
+		}
+		rev, err := h.revisionRepo.GetRevision(r.Context(), song.UUID, id)
+		if errors.Is(err, core.ErrNotFound) {
+			_ = render.Render(w, r, apierror.ErrNotFound)
+			return
+		} else if err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not fetch song revision.", "uuid", song.UUID, "revision", id, tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		ctx := SetRevision(r.Context(), rev)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+	return http.HandlerFunc(fn)
+}
+
+// FetchComment is a middleware that fetches the model.SongComment instance identified by the {comment} parameter
+// and stores it in the request context.
+// This middleware must be used after FetchSong.
+func (h *Handler) FetchComment(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		song := MustGetSong(r.Context())
+		id, err := uuid.Parse(chi.URLParam(r, "comment"))
+		if err != nil {
+			_ = render.Render(w, r, apierror.ErrInvalidUUID)
+			return
+		}
+		comment, err := h.reviewRepo.GetComment(r.Context(), song.UUID, id)
+		if errors.Is(err, core.ErrNotFound) {
+			_ = render.Render(w, r, apierror.ErrNotFound)
+			return
+		} else if err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not fetch song comment.", "uuid", song.UUID, "comment", id, tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		ctx := SetComment(r.Context(), comment)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+	return http.HandlerFunc(fn)
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -76,3 +77,47 @@ func TestHandler_CheckModify(t *testing.T) {
 		})
 	})
 }
+
+func TestHandler_CheckPrecondition(t *testing.T) {
+	t.Parallel()
+
+	h, _ := setupHandler(t, "")
+	song := model.Song{Model: model.Model{UUID: uuid.New(), UpdatedAt: time.Now()}}
+	etag := middleware.ETag(song.UpdatedAt)
+
+	m := h.CheckPrecondition(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	request := func(ifMatch string) *http.Request {
+		r := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/v1/songs/%s", song.UUID), nil)
+		if ifMatch != "" {
+			r.Header.Set("If-Match", ifMatch)
+		}
+		return r.WithContext(SetSong(r.Context(), song))
+	}
+
+	t.Run("OK", func(t *testing.T) {
+		for _, ifMatch := range []string{"", etag, "*"} {
+			resp := test.DoRequest(m, request(ifMatch)) //nolint:bodyclose
+			if resp.StatusCode != http.StatusNoContent {
+				t.Errorf("CheckPrecondition() with If-Match %q responded with status code %d, expected %d", ifMatch, resp.StatusCode, http.StatusNoContent)
+			}
+		}
+	})
+	t.Run("412 Precondition Failed", func(t *testing.T) {
+		resp := test.DoRequest(m, request(`"outdated"`)) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusPreconditionFailed, apierror.TypePreconditionFailed, map[string]any{
+			"etag": etag,
+		})
+	})
+	t.Run("428 Precondition Required", func(t *testing.T) {
+		h.strictPreconditions = true
+		defer func() { h.strictPreconditions = false }()
+		resp := test.DoRequest(m, request("")) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusPreconditionRequired, apierror.TypePreconditionRequired, nil)
+		resp = test.DoRequest(m, request(etag)) //nolint:bodyclose
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("CheckPrecondition() with If-Match %q responded with status code %d, expected %d", etag, resp.StatusCode, http.StatusNoContent)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package songs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/event"
+	songsvc "github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// GetNotes implements the GET /v1/songs/{uuid}/notes endpoint.
+func (h *Handler) GetNotes(w http.ResponseWriter, r *http.Request) {
+	song := MustGetSong(r.Context())
+	resp := schema.FromSongNotes(song)
+	setETag(w, song)
+	_ = render.Render(w, r, &resp)
+}
+
+// EditNotes implements the PATCH /v1/songs/{uuid}/notes endpoint.
+// The edits in the request are applied atomically.
+// If any edit is invalid, the song is not modified.
+func (h *Handler) EditNotes(w http.ResponseWriter, r *http.Request) {
+	song := MustGetSong(r.Context())
+	var patch schema.NotesPatch
+	if err := render.Bind(r, &patch); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	var notesErr *songsvc.NotesError
+	if err := h.songSvc.EditNotes(r.Context(), &song, patch.NoteEdits()); errors.As(err, &notesErr) {
+		_ = render.Render(w, r, apierror.InvalidNoteEdits(notesErrorPointer(notesErr), notesErr.Message))
+		return
+	} else if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not edit notes.", "uuid", song.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	if err := h.updateSong(r.Context(), &song); err != nil {
+		h.renderUpdateError(w, r, song, err)
+		return
+	}
+	h.recordRevision(r, song)
+	setETag(w, song)
+	h.publish(r.Context(), event.SongUpdated(song))
+	resp := schema.FromSongNotes(song)
+	_ = render.Render(w, r, &resp)
+}
+
+// notesErrorPointer returns a JSON pointer to the edit or note that caused err.
+// Edits are referenced in the request schema, notes in the response schema.
+func notesErrorPointer(err *songsvc.NotesError) string {
+	switch {
+	case err.Edit >= 0:
+		return fmt.Sprintf("/edits/%d", err.Edit)
+	case err.Note < 0:
+		return fmt.Sprintf("/p%d/%d", err.Player, err.Line)
+	default:
+		return fmt.Sprintf("/p%d/%d/notes/%d", err.Player, err.Line, err.Note)
+	}
+}
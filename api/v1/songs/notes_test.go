@@ -0,0 +1,128 @@
+//go:build database
+
+package songs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"codello.dev/ultrastar"
+	"github.com/google/uuid"
+	"github.com/jackc/pgxutil"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+// songWithNotes inserts a song with two lines of notes into db.
+func songWithNotes(t *testing.T, db pgxutil.DB) model.Song {
+	s := testdata.SimpleSong(t, db)
+	s.NotesP1 = ultrastar.Notes{
+		{Type: ultrastar.NoteTypeRegular, Start: 0, Duration: 2, Pitch: 5, Text: "Hel"},
+		{Type: ultrastar.NoteTypeRegular, Start: 2, Duration: 2, Pitch: 7, Text: "lo "},
+		{Type: ultrastar.NoteTypeLineBreak, Start: 6},
+		{Type: ultrastar.NoteTypeGolden, Start: 8, Duration: 4, Pitch: 9, Text: "world"},
+	}
+	s.NotesP2 = nil
+	if err := song.NewDBRepository(nolog.Logger, db).UpdateSong(context.TODO(), &s); err != nil {
+		t.Fatalf("could not set song notes: %s", err)
+	}
+	return s
+}
+
+func TestHandler_GetNotes(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	s := songWithNotes(t, db)
+	url := fmt.Sprintf("/v1/songs/%s/notes", s.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		if resp.Header.Get("ETag") == "" {
+			t.Errorf("GET %s responded without an ETag header, expected an entity tag", url)
+		}
+		var notes schema.SongNotes
+		if err := json.NewDecoder(resp.Body).Decode(&notes); err != nil {
+			t.Errorf("GET %s responded with invalid notes schema: %s", url, err)
+			return
+		}
+		if len(notes.P1) != 2 || len(notes.P1[0].Notes) != 2 || notes.P1[1].Notes[0].Type != "golden" {
+			t.Errorf("GET %s responded with %v, expected 2 lines with 2 and 1 notes", url, notes.P1)
+		}
+		if notes.P2 != nil {
+			t.Errorf("GET %s responded with p2 = %v, expected no p2 notes", url, notes.P2)
+		}
+	})
+	t.Run("400 Bad Request", test.InvalidUUID(h, http.MethodGet, "/v1/songs/"+testdata.InvalidUUID+"/notes"))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodGet, fmt.Sprintf("/v1/songs/%s/notes", uuid.New()), http.StatusNotFound))
+}
+
+func TestHandler_EditNotes(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	s := songWithNotes(t, db)
+	songWithUpload := testdata.SongWithUpload(t, db)
+	url := fmt.Sprintf("/v1/songs/%s/notes", s.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"edits": [
+			{"op": "setText", "line": 1, "note": 0, "text": "earth"},
+			{"op": "mergeLines", "line": 0},
+			{"op": "splitLine", "line": 0, "note": 1}
+		]}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("PATCH %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var notes schema.SongNotes
+		if err := json.NewDecoder(resp.Body).Decode(&notes); err != nil {
+			t.Errorf("PATCH %s responded with invalid notes schema: %s", url, err)
+			return
+		}
+		if len(notes.P1) != 2 || len(notes.P1[1].Notes) != 2 || notes.P1[1].Start != 2 || notes.P1[1].Notes[1].Text != "earth" {
+			t.Errorf("PATCH %s responded with %v, expected the edits to be applied", url, notes.P1)
+		}
+		updated, _ := song.NewDBRepository(nolog.Logger, db).GetSong(context.TODO(), s.UUID)
+		if len(updated.NotesP1) != 4 || updated.NotesP1[3].Text != "earth" {
+			t.Errorf("PATCH %s did not persist the edits, got notes %v", url, updated.NotesP1)
+		}
+	})
+	t.Run("400 Bad Request (Invalid UUID)", test.InvalidUUID(h, http.MethodPatch, "/v1/songs/"+testdata.InvalidUUID+"/notes"))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodPatch, fmt.Sprintf("/v1/songs/%s/notes", uuid.New()), http.StatusNotFound))
+	t.Run("409 Conflict", testSongConflict(h, http.MethodPatch, "/v1/songs/%s/notes", songWithUpload.UUID))
+
+	t.Run("422 Unprocessable Entity (Missing Field)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"edits": [{"op": "setPitch", "line": 0, "note": 0}]}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+	t.Run("422 Unprocessable Entity (Invalid Result)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"edits": [
+			{"op": "setText", "line": 0, "note": 0, "text": "unchanged"},
+			{"op": "resizeNote", "line": 0, "note": 0, "duration": 100}
+		]}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertValidationError(t, resp, map[string]string{"/p1/0/notes/1": "note overlaps the previous note"})
+		unchanged, _ := song.NewDBRepository(nolog.Logger, db).GetSong(context.TODO(), s.UUID)
+		if unchanged.NotesP1[0].Text == "unchanged" {
+			t.Errorf("PATCH %s modified the song, expected no changes", url)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package songs
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/review"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// SetReviewState implements the PUT /v1/songs/{uuid}/review endpoint.
+// The allowed state transitions are defined by model.ReviewState.CanTransition.
+func (h *Handler) SetReviewState(w http.ResponseWriter, r *http.Request) {
+	song := MustGetSong(r.Context())
+	var change schema.ReviewStateChange
+	if err := render.Bind(r, &change); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	if !song.ReviewState.CanTransition(change.State) {
+		_ = render.Render(w, r, apierror.InvalidReviewTransition(song, change.State))
+		return
+	}
+	if err := h.reviewRepo.SetState(r.Context(), song.UUID, change.State, r.Header.Get("From")); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not set review state.", "uuid", song.UUID, "state", change.State, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	song.ReviewState = change.State
+	h.publish(r.Context(), event.SongUpdated(song))
+	resp := schema.FromSong(song)
+	_ = render.Render(w, r, &resp)
+}
+
+// FindComments implements the GET /v1/songs/{uuid}/comments endpoint.
+func (h *Handler) FindComments(w http.ResponseWriter, r *http.Request) {
+	song := MustGetSong(r.Context())
+	pagination := middleware.MustGetPagination(r.Context())
+	comments, total, err := h.reviewRepo.FindComments(r.Context(), song.UUID, pagination.Limit, pagination.Offset)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list song comments.", "uuid", song.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+
+	resp := schema.List[*schema.SongComment]{
+		Items:  make([]*schema.SongComment, len(comments)),
+		Offset: pagination.Offset,
+		Limit:  pagination.RequestLimit,
+		Total:  total,
+	}
+	for i, c := range comments {
+		s := schema.FromSongComment(c)
+		resp.Items[i] = &s
+	}
+	_ = render.Render(w, r, &resp)
+}
+
+// CreateComment implements the POST /v1/songs/{uuid}/comments endpoint.
+// Anchors are validated against the notes of the song.
+func (h *Handler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	song := MustGetSong(r.Context())
+	var data schema.NewSongComment
+	err := render.Bind(r, &data)
+	if err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+
+	comment := model.SongComment{
+		Author: r.Header.Get("From"),
+		Kind:   data.Kind,
+		Text:   data.Text,
+	}
+	if a := data.Anchor; a != nil {
+		var anchor model.CommentAnchor
+		if a.Line != nil {
+			anchor, err = review.LineAnchor(song, a.Player, *a.Line)
+		} else {
+			anchor, err = review.BeatAnchor(song, a.Player, *a.Beat)
+		}
+		if err != nil {
+			_ = render.Render(w, r, apierror.ValidationError("The comment anchor is invalid.", map[string]string{"/anchor": err.Error()}))
+			return
+		}
+		comment.Anchor = &anchor
+	}
+	if err = h.reviewRepo.CreateComment(r.Context(), song.UUID, &comment); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not create song comment.", "uuid", song.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	render.SetStatus(r, http.StatusCreated)
+	resp := schema.FromSongComment(comment)
+	_ = render.Render(w, r, &resp)
+}
+
+// UpdateComment implements the PATCH /v1/songs/{uuid}/comments/{comment} endpoint.
+func (h *Handler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	song := MustGetSong(r.Context())
+	comment := MustGetComment(r.Context())
+	var update schema.SongCommentUpdate
+	if err := render.Bind(r, &update); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	update.Apply(&comment)
+	if comment.Kind == model.CommentKindRemark && comment.Text == "" {
+		_ = render.Render(w, r, apierror.ValidationError("The comment is invalid.", map[string]string{"/text": "the text of a remark must not be empty"}))
+		return
+	}
+	if err := h.reviewRepo.UpdateComment(r.Context(), song.UUID, &comment); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not update song comment.", "uuid", song.UUID, "comment", comment.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	resp := schema.FromSongComment(comment)
+	_ = render.Render(w, r, &resp)
+}
+
+// DeleteComment implements the DELETE /v1/songs/{uuid}/comments/{comment} endpoint.
+// Deleting a comment is idempotent, so this endpoint does not use FetchComment.
+func (h *Handler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	song := MustGetSong(r.Context())
+	id, err := uuid.Parse(chi.URLParam(r, "comment"))
+	if err != nil {
+		_ = render.Render(w, r, apierror.ErrInvalidUUID)
+		return
+	}
+	if _, err = h.reviewRepo.DeleteComment(r.Context(), song.UUID, id); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not delete song comment.", "uuid", song.UUID, "comment", id, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	_ = render.NoContent(w, r)
+}
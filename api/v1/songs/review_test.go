@@ -0,0 +1,166 @@
+//go:build database
+
+package songs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/review"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_SetReviewState(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	song := testdata.SimpleSong(t, db)
+	upload := testdata.SongWithUpload(t, db)
+	url := fmt.Sprintf("/v1/songs/%s/review", song.UUID)
+
+	request := func(state model.ReviewState) *http.Request {
+		r := httptest.NewRequest(http.MethodPut, url, strings.NewReader(fmt.Sprintf(`{"state": %q}`, state)))
+		r.Header.Set("Content-Type", "application/json")
+		return r
+	}
+
+	t.Run("200 OK", func(t *testing.T) {
+		for _, state := range []model.ReviewState{model.ReviewStateNeedsReview, model.ReviewStateApproved} {
+			resp := test.DoRequest(h, request(state)) //nolint:bodyclose
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("PUT %s with state %s responded with status code %d, expected %d", url, state, resp.StatusCode, http.StatusOK)
+			}
+			var s schema.Song
+			if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+				t.Fatalf("PUT %s responded with invalid song schema: %s", url, err)
+			}
+			if s.ReviewState != state {
+				t.Errorf("PUT %s responded with review state %q, expected %q", url, s.ReviewState, state)
+			}
+		}
+	})
+	t.Run("409 Conflict", testSongConflict(h, http.MethodPut, "/v1/songs/%s/review", upload.UUID))
+	t.Run("409 Conflict (Invalid Transition)", func(t *testing.T) {
+		testdata.ReviewState(t, db, song.UUID, model.ReviewStateDraft)
+		resp := test.DoRequest(h, request(model.ReviewStateApproved)) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusConflict, apierror.TypeInvalidReviewTransition, map[string]any{
+			"uuid": song.UUID.String(),
+		})
+	})
+	t.Run("422 Unprocessable Entity", func(t *testing.T) {
+		resp := test.DoRequest(h, request("done")) //nolint:bodyclose
+		if resp.StatusCode != http.StatusUnprocessableEntity {
+			t.Errorf("PUT %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusUnprocessableEntity)
+		}
+	})
+}
+
+func TestHandler_CreateComment(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	song := testdata.SimpleSong(t, db)
+	url := fmt.Sprintf("/v1/songs/%s/comments", song.UUID)
+
+	request := func(body string) *http.Request {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		return r
+	}
+
+	t.Run("201 Created (Out of Sync)", func(t *testing.T) {
+		resp := test.DoRequest(h, request(`{"kind": "out-of-sync", "anchor": {"player": 0, "line": 0}}`)) //nolint:bodyclose
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusCreated)
+		}
+		var c schema.SongComment
+		if err := json.NewDecoder(resp.Body).Decode(&c); err != nil {
+			t.Fatalf("POST %s responded with invalid comment schema: %s", url, err)
+		}
+		if c.Kind != model.CommentKindOutOfSync || c.Anchor == nil || c.Anchor.Line == nil || c.Anchor.Beat == nil {
+			t.Errorf("POST %s responded with %v, expected an out-of-sync report anchored to line 0", url, c)
+		}
+	})
+	t.Run("201 Created (Remark)", func(t *testing.T) {
+		resp := test.DoRequest(h, request(`{"text": "Golden notes are missing", "anchor": {"player": 0, "beat": 12}}`)) //nolint:bodyclose
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusCreated)
+		}
+	})
+	t.Run("422 Unprocessable Entity (Anchor)", func(t *testing.T) {
+		resp := test.DoRequest(h, request(`{"text": "Wrong pitch", "anchor": {"player": 1, "line": 0}}`)) //nolint:bodyclose
+		test.AssertValidationError(t, resp, map[string]string{"/anchor": "invalid player: song has no player 1"})
+	})
+}
+
+func TestHandler_FindComments(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	song := testdata.SimpleSong(t, db)
+	testdata.Comment(t, db, song.UUID, model.CommentKindRemark, "Check the gap")
+	testdata.Comment(t, db, song.UUID, model.CommentKindOutOfSync, "")
+	url := fmt.Sprintf("/v1/songs/%s/comments", song.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertPagination(t, resp, 0, 25, 2, 2)
+	})
+	t.Run("400 Bad Request (Pagination)", test.InvalidPagination(h, http.MethodGet, url))
+}
+
+func TestHandler_UpdateComment(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	song := testdata.SimpleSong(t, db)
+	report := testdata.Comment(t, db, song.UUID, model.CommentKindOutOfSync, "")
+	url := fmt.Sprintf("/v1/songs/%s/comments/%s", song.UUID, report.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"resolved": true}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("PATCH %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		updated, _ := review.NewDBRepository(nolog.Logger, db).GetComment(context.TODO(), song.UUID, report.UUID)
+		if !updated.Resolved {
+			t.Errorf("PATCH %s did not resolve the comment", url)
+		}
+	})
+	t.Run("404 Not Found", func(t *testing.T) {
+		url := fmt.Sprintf("/v1/songs/%s/comments/%s", song.UUID, uuid.New())
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"resolved": true}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("PATCH %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNotFound)
+		}
+	})
+}
+
+func TestHandler_DeleteComment(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	song := testdata.SimpleSong(t, db)
+	remark := testdata.Comment(t, db, song.UUID, model.CommentKindRemark, "Check the gap")
+	url := fmt.Sprintf("/v1/songs/%s/comments/%s", song.UUID, remark.UUID)
+
+	t.Run("204 No Content", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodDelete, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("DELETE %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+package songs
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/revision"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// FindRevisions implements the GET /v1/songs/{uuid}/revisions endpoint.
+func (h *Handler) FindRevisions(w http.ResponseWriter, r *http.Request) {
+	song := MustGetSong(r.Context())
+	pagination := middleware.MustGetPagination(r.Context())
+	revs, total, err := h.revisionRepo.FindRevisions(r.Context(), song.UUID, pagination.Limit, pagination.Offset)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list song revisions.", "uuid", song.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+
+	resp := schema.List[*schema.SongRevision]{
+		Items:  make([]*schema.SongRevision, len(revs)),
+		Offset: pagination.Offset,
+		Limit:  pagination.RequestLimit,
+		Total:  total,
+	}
+	for i, rev := range revs {
+		s := schema.FromSongRevision(rev)
+		resp.Items[i] = &s
+	}
+	_ = render.Render(w, r, &resp)
+}
+
+// GetRevisionDiff implements the GET /v1/songs/{uuid}/revisions/{revision}/diff endpoint.
+// By default, the revision is compared to its predecessor.
+// A different base revision can be specified via the base query parameter.
+func (h *Handler) GetRevisionDiff(w http.ResponseWriter, r *http.Request) {
+	song := MustGetSong(r.Context())
+	rev := MustGetRevision(r.Context())
+
+	var base model.SongRevision
+	var err error
+	if param := r.URL.Query().Get("base"); param != "" {
+		id, pErr := uuid.Parse(param)
+		if pErr != nil {
+			_ = render.Render(w, r, apierror.ErrInvalidUUID)
+			return
+		}
+		base, err = h.revisionRepo.GetRevision(r.Context(), song.UUID, id)
+		if errors.Is(err, core.ErrNotFound) {
+			_ = render.Render(w, r, apierror.ErrNotFound)
+			return
+		}
+	} else {
+		base, err = h.revisionRepo.GetPreviousRevision(r.Context(), song.UUID, rev.UUID)
+	}
+	if err != nil && !errors.Is(err, core.ErrNotFound) {
+		h.logger.ErrorContext(r.Context(), "Could not fetch song revision.", "uuid", song.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+
+	var resp schema.SongRevisionDiff
+	if err != nil {
+		// The first revision is compared to an empty song.
+		resp = schema.FromRevisionDiff(nil, rev, revision.Compare(model.SongRevision{}, rev))
+	} else {
+		resp = schema.FromRevisionDiff(&base, rev, revision.Compare(base, rev))
+	}
+	_ = render.Render(w, r, &resp)
+}
+
+// RevertRevision implements the POST /v1/songs/{uuid}/revisions/{revision}/revert endpoint.
+// Reverting a song records a new revision, so the revert itself can be reverted as well.
+func (h *Handler) RevertRevision(w http.ResponseWriter, r *http.Request) {
+	song := MustGetSong(r.Context())
+	rev := MustGetRevision(r.Context())
+	rev.Apply(&song)
+	if err := h.updateSong(r.Context(), &song); err != nil {
+		h.renderUpdateError(w, r, song, err)
+		return
+	}
+	h.recordRevision(r, song)
+	setETag(w, song)
+	h.publish(r.Context(), event.SongUpdated(song))
+	s := schema.FromSong(song)
+	_ = render.Render(w, r, &s)
+}
@@ -0,0 +1,126 @@
+//go:build database
+
+package songs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgxutil"
+
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/revision"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+// songWithRevision inserts a song with a single revision into db.
+func songWithRevision(t *testing.T, db pgxutil.DB) (model.Song, model.SongRevision) {
+	song := testdata.SimpleSong(t, db)
+	rev := model.NewSongRevision(song, "")
+	if err := revision.NewDBRepository(nolog.Logger, db).CreateRevision(context.TODO(), song.UUID, &rev); err != nil {
+		t.Fatalf("could not create song revision: %s", err)
+	}
+	return song, rev
+}
+
+func TestHandler_FindRevisions(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	song, _ := songWithRevision(t, db)
+	url := fmt.Sprintf("/v1/songs/%s/revisions", song.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/v1/songs/%s", song.UUID), strings.NewReader(`{"title": "Foobar"}`))
+		r.Header.Set("Content-Type", "application/json")
+		r.Header.Set("From", "jane@example.com")
+		_ = test.DoRequest(h, r) //nolint:bodyclose
+
+		r = httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertPagination(t, resp, 0, 25, 2, 2)
+		var revs []schema.SongRevision
+		if err := json.NewDecoder(resp.Body).Decode(&revs); err != nil {
+			t.Errorf("GET %s responded with invalid revision list schema: %s", url, err)
+			return
+		}
+		if len(revs) != 2 || revs[0].Author != "jane@example.com" {
+			t.Errorf("GET %s did not respond with the newest revision first", url)
+		}
+	})
+	t.Run("400 Bad Request (Pagination)", test.InvalidPagination(h, http.MethodGet, url))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodGet, fmt.Sprintf("/v1/songs/%s/revisions", uuid.New()), http.StatusNotFound))
+}
+
+func TestHandler_GetRevisionDiff(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	song, first := songWithRevision(t, db)
+	second := model.NewSongRevision(song, "")
+	second.Title = "Foobar"
+	if err := revision.NewDBRepository(nolog.Logger, db).CreateRevision(context.TODO(), song.UUID, &second); err != nil {
+		t.Fatalf("could not create song revision: %s", err)
+	}
+	url := fmt.Sprintf("/v1/songs/%s/revisions/%s/diff", song.UUID, second.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var diff schema.SongRevisionDiff
+		if err := json.NewDecoder(resp.Body).Decode(&diff); err != nil {
+			t.Errorf("GET %s responded with invalid diff schema: %s", url, err)
+			return
+		}
+		if diff.From == nil || *diff.From != first.UUID {
+			t.Errorf(`GET %s responded with {"from": %v}, expected %q`, url, diff.From, first.UUID)
+		}
+		if len(diff.Fields) != 1 || diff.Fields[0].Field != "title" {
+			t.Errorf(`GET %s responded with {"fields": %v}, expected a single change of title`, url, diff.Fields)
+		}
+		if len(diff.Notes.P1) != 0 {
+			t.Errorf(`GET %s responded with %d changed lines, expected none`, url, len(diff.Notes.P1))
+		}
+	})
+	t.Run("400 Bad Request (Invalid UUID)", test.InvalidUUID(h, http.MethodGet, fmt.Sprintf("/v1/songs/%s/revisions/%s/diff", song.UUID, testdata.InvalidUUID)))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodGet, fmt.Sprintf("/v1/songs/%s/revisions/%s/diff", song.UUID, uuid.New()), http.StatusNotFound))
+}
+
+func TestHandler_RevertRevision(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	song, rev := songWithRevision(t, db)
+	url := fmt.Sprintf("/v1/songs/%s/revisions/%s/revert", song.UUID, rev.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/v1/songs/%s", song.UUID), strings.NewReader(`{"title": "Foobar"}`))
+		r.Header.Set("Content-Type", "application/json")
+		_ = test.DoRequest(h, r) //nolint:bodyclose
+
+		r = httptest.NewRequest(http.MethodPost, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var s schema.Song
+		if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+			t.Errorf("POST %s responded with invalid song schema: %s", url, err)
+			return
+		}
+		if s.Title != song.Title {
+			t.Errorf(`POST %s responded with {"title": %q}, expected %q`, url, s.Title, song.Title)
+		}
+	})
+	t.Run("400 Bad Request (Invalid UUID)", test.InvalidUUID(h, http.MethodPost, fmt.Sprintf("/v1/songs/%s/revisions/%s/revert", song.UUID, testdata.InvalidUUID)))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodPost, fmt.Sprintf("/v1/songs/%s/revisions/%s/revert", song.UUID, uuid.New()), http.StatusNotFound))
+}
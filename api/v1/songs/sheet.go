@@ -0,0 +1,118 @@
+package songs
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/mediatype"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// mediaTypeCSV is the media type of song sheets.
+var mediaTypeCSV = mediatype.MustParse("text/csv")
+
+// exportSheet implements the CSV variant of the GET /v1/songs endpoint.
+// A song sheet is not paginated, it contains all songs matching the filter.
+func (h *Handler) exportSheet(w http.ResponseWriter, r *http.Request) {
+	filter, err := songFilter(r.URL.Query())
+	if err != nil {
+		_ = render.Render(w, r, apierror.BadRequest(err.Error()))
+		return
+	}
+	songs, _, err := h.songRepo.FindSongs(r.Context(), filter, -1, 0)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list songs.", tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", mediaTypeCSV.WithParameters(map[string]string{"charset": "utf-8"}).String())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "songs.csv"}))
+	_ = render.Render(w, r, &schema.SongSheet{Songs: songs})
+}
+
+// ImportSheet implements the POST /v1/songs/sheet endpoint.
+// Rows are matched to songs by their UUID.
+// If the dryRun query parameter is set, the changes are calculated but not applied.
+// Otherwise, all changes are applied in a single transaction.
+func (h *Handler) ImportSheet(w http.ResponseWriter, r *http.Request) {
+	dryRun := false
+	if param := r.URL.Query().Get("dryRun"); param != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(param); err != nil {
+			_ = render.Render(w, r, apierror.BadRequest("Invalid dryRun: must be a boolean."))
+			return
+		}
+	}
+	var records [][]string
+	if err := render.Decode(r, &records); err != nil {
+		_ = render.Render(w, r, apierror.BadRequest(fmt.Sprintf("Invalid CSV: %s.", err)))
+		return
+	}
+	rows, errs := schema.ParseSongSheet(records)
+	if len(errs) > 0 {
+		_ = render.Render(w, r, apierror.ValidationError("The song sheet is invalid.", errs))
+		return
+	}
+
+	resp := schema.SongSheetImport{DryRun: dryRun, Changes: make([]schema.SongSheetChange, 0)}
+	updated := make([]model.Song, 0, len(rows))
+	for _, row := range rows {
+		pointer := fmt.Sprintf("/%d", row.Record)
+		song, err := h.songRepo.GetSong(r.Context(), row.UUID)
+		if errors.Is(err, core.ErrNotFound) || (err == nil && song.Deleted()) {
+			errs[pointer] = "song not found"
+			continue
+		} else if err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not fetch song.", "uuid", row.UUID, tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		if song.InUpload {
+			errs[pointer] = "song belongs to an upload and cannot be modified"
+			continue
+		}
+		s := schema.FromSong(song)
+		rowErrs := row.Apply(&s.SongRW)
+		for p, msg := range rowErrs {
+			errs[p] = msg
+		}
+		if len(rowErrs) > 0 {
+			continue
+		}
+		update := song
+		s.Apply(&update)
+		change := schema.NewSongSheetChange(song, update, row.Record)
+		if len(change.Fields) == 0 {
+			continue
+		}
+		resp.Changes = append(resp.Changes, change)
+		updated = append(updated, update)
+	}
+	if len(errs) > 0 {
+		_ = render.Render(w, r, apierror.ValidationError("The song sheet is invalid.", errs))
+		return
+	}
+
+	if !dryRun && len(updated) > 0 {
+		if err := h.songRepo.UpdateSongs(r.Context(), updated); err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not update songs.", "count", len(updated), tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		for _, song := range updated {
+			h.recordRevision(r, song)
+			h.publish(r.Context(), event.SongUpdated(song))
+		}
+	}
+	_ = render.Render(w, r, &resp)
+}
@@ -0,0 +1,116 @@
+//go:build database
+
+package songs
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	_ "github.com/Karaoke-Manager/karman/pkg/render/csv"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_Find_CSV(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	s := testdata.SimpleSong(t, db)
+	url := "/v1/songs?limit=1"
+
+	r := httptest.NewRequest(http.MethodGet, url, nil)
+	r.Header.Set("Accept", "text/csv")
+	resp := test.DoRequest(h, r) //nolint:bodyclose
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+	}
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/csv") {
+		t.Errorf("GET %s responded with Content-Type %q, expected %q", url, resp.Header.Get("Content-Type"), "text/csv")
+	}
+	records, err := csv.NewReader(resp.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("GET %s responded with invalid CSV: %s", url, err)
+	}
+	if len(records) != 2 || records[0][0] != "uuid" || records[1][0] != s.UUID.String() || records[1][1] != s.Title {
+		t.Errorf("GET %s responded with %v, expected a header and a row for %s", url, records, s.UUID)
+	}
+}
+
+func TestHandler_ImportSheet(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	s := testdata.SimpleSong(t, db)
+	songWithUpload := testdata.SongWithUpload(t, db)
+	url := "/v1/songs/sheet"
+	songRepo := song.NewDBRepository(nolog.Logger, db)
+
+	t.Run("200 OK (Dry Run)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url+"?dryRun=true", strings.NewReader(fmt.Sprintf("uuid,genre,year\n%s,Rock,1999\n", s.UUID)))
+		r.Header.Set("Content-Type", "text/csv")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var data schema.SongSheetImport
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("POST %s responded with invalid import schema: %s", url, err)
+		}
+		if !data.DryRun || len(data.Changes) != 1 || len(data.Changes[0].Fields) != 2 || data.Changes[0].Fields[0].Field != "genre" {
+			t.Errorf("POST %s responded with %v, expected changes of genre and year", url, data)
+		}
+		if actual, _ := songRepo.GetSong(context.TODO(), s.UUID); actual.Genre == "Rock" {
+			t.Errorf("POST %s?dryRun=true changed the song, expected no change", url)
+		}
+	})
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf("uuid,genre,language\n%s,Rock,English\n", s.UUID)))
+		r.Header.Set("Content-Type", "text/csv")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		actual, _ := songRepo.GetSong(context.TODO(), s.UUID)
+		if actual.Genre != "Rock" || actual.Language != "English" {
+			t.Errorf("POST %s set genre %q and language %q, expected %q and %q", url, actual.Genre, actual.Language, "Rock", "English")
+		}
+	})
+	t.Run("200 OK (Escaped Formula)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf("uuid,comment\n%s,'=1+1\n", s.UUID)))
+		r.Header.Set("Content-Type", "text/csv")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		actual, _ := songRepo.GetSong(context.TODO(), s.UUID)
+		if actual.Comment != "=1+1" {
+			t.Errorf("POST %s set comment %q, expected %q", url, actual.Comment, "=1+1")
+		}
+	})
+	t.Run("400 Bad Request (Missing Content-Type)", test.MissingContentType(h, http.MethodPost, url, "text/csv"))
+	t.Run("422 Unprocessable Entity (Unknown Column)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf("uuid,foo\n%s,bar\n", s.UUID)))
+		r.Header.Set("Content-Type", "text/csv")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertValidationError(t, resp, map[string]string{"/0/1": `unknown column "foo"`})
+	})
+	t.Run("422 Unprocessable Entity (Invalid Year)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf("uuid,year\n%s,foo\n", s.UUID)))
+		r.Header.Set("Content-Type", "text/csv")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertValidationError(t, resp, map[string]string{"/1/1": "year must be a positive number"})
+	})
+	t.Run("422 Unprocessable Entity (Upload)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf("uuid,genre\n%s,Rock\n", songWithUpload.UUID)))
+		r.Header.Set("Content-Type", "text/csv")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertValidationError(t, resp, map[string]string{"/1": "song belongs to an upload and cannot be modified"})
+	})
+}
@@ -0,0 +1,85 @@
+package songs
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/event"
+	songsvc "github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Transform implements the POST /v1/songs/{uuid}/transform endpoint.
+// The transforms in the request are applied atomically.
+func (h *Handler) Transform(w http.ResponseWriter, r *http.Request) {
+	song := MustGetSong(r.Context())
+	var req schema.SongTransform
+	if err := render.Bind(r, &req); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	var transformErr *songsvc.TransformError
+	if err := h.songSvc.Transform(r.Context(), &song, req.SongTransforms()); errors.As(err, &transformErr) {
+		_ = render.Render(w, r, apierror.InvalidTransform(transformErr.Transform, transformErr.Message))
+		return
+	} else if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not transform song.", "uuid", song.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	if err := h.updateSong(r.Context(), &song); err != nil {
+		h.renderUpdateError(w, r, song, err)
+		return
+	}
+	h.recordRevision(r, song)
+	setETag(w, song)
+	h.publish(r.Context(), event.SongUpdated(song))
+	resp := schema.FromSong(song)
+	_ = render.Render(w, r, &resp)
+}
+
+// BulkTransform implements the POST /v1/songs/transform endpoint.
+// The transforms are applied to each selected song independently.
+// If the transforms cannot be applied to a song, the song is not modified and the error is reported in the response.
+// Other songs are transformed nonetheless.
+func (h *Handler) BulkTransform(w http.ResponseWriter, r *http.Request) {
+	var req schema.BulkTransform
+	if err := render.Bind(r, &req); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	resp := schema.BulkTransformResult{Results: make([]schema.TransformResult, len(req.Songs))}
+	for i, id := range req.Songs {
+		resp.Results[i].UUID = id
+		song, err := h.songRepo.GetSong(r.Context(), id)
+		if errors.Is(err, core.ErrNotFound) || (err == nil && song.Deleted()) {
+			resp.Results[i].Error = "song not found"
+			continue
+		} else if err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not fetch song.", "uuid", id, tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		if song.InUpload {
+			resp.Results[i].Error = "song belongs to an upload and cannot be modified"
+			continue
+		}
+		if err = h.songSvc.Transform(r.Context(), &song, req.SongTransforms()); err != nil {
+			resp.Results[i].Error = err.Error()
+			continue
+		}
+		if err = h.songRepo.UpdateSong(r.Context(), &song); err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not update song.", "uuid", song.UUID, tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		h.recordRevision(r, song)
+		h.publish(r.Context(), event.SongUpdated(song))
+	}
+	_ = render.Render(w, r, &resp)
+}
@@ -0,0 +1,112 @@
+//go:build database
+
+package songs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_Transform(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	s := songWithNotes(t, db)
+	songWithUpload := testdata.SongWithUpload(t, db)
+	url := fmt.Sprintf("/v1/songs/%s/transform", s.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"transforms": [
+			{"op": "shiftGap", "value": 250},
+			{"op": "shiftNotes", "value": 2}
+		]}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var data schema.Song
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Errorf("POST %s responded with invalid song schema: %s", url, err)
+		}
+		updated, _ := song.NewDBRepository(nolog.Logger, db).GetSong(context.TODO(), s.UUID)
+		if updated.Gap != s.Gap+250*time.Millisecond {
+			t.Errorf("POST %s set gap to %s, expected %s", url, updated.Gap, s.Gap+250*time.Millisecond)
+		}
+		if updated.NotesP1[0].Start != s.NotesP1[0].Start+2 {
+			t.Errorf("POST %s moved the first note to beat %d, expected %d", url, updated.NotesP1[0].Start, s.NotesP1[0].Start+2)
+		}
+	})
+	t.Run("400 Bad Request (Invalid UUID)", test.InvalidUUID(h, http.MethodPost, "/v1/songs/"+testdata.InvalidUUID+"/transform"))
+	t.Run("400 Bad Request (Missing Content-Type)", test.MissingContentType(h, http.MethodPost, url, "application/json"))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodPost, fmt.Sprintf("/v1/songs/%s/transform", uuid.New()), http.StatusNotFound))
+	t.Run("409 Conflict", testSongConflict(h, http.MethodPost, "/v1/songs/%s/transform", songWithUpload.UUID))
+	t.Run("422 Unprocessable Entity (Missing Value)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"transforms": [{"op": "setVideoGap"}]}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+	t.Run("422 Unprocessable Entity (Invalid Transform)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"transforms": [
+			{"op": "setVideoGap", "value": 1000},
+			{"op": "shiftNotes", "value": -1000}
+		]}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertValidationError(t, resp, map[string]string{"/transforms/1": "notes cannot start before beat 0"})
+	})
+}
+
+func TestHandler_BulkTransform(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	s := testdata.SimpleSong(t, db)
+	songWithUpload := testdata.SongWithUpload(t, db)
+	missing := uuid.New()
+	url := "/v1/songs/transform"
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{
+			"songs": [%q, %q, %q],
+			"transforms": [{"op": "setVideoGap", "value": 1500}]
+		}`, s.UUID, songWithUpload.UUID, missing)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var data schema.BulkTransformResult
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Fatalf("POST %s responded with invalid result schema: %s", url, err)
+		}
+		if len(data.Results) != 3 || data.Results[0].Error != "" || data.Results[1].Error == "" || data.Results[2].Error == "" {
+			t.Errorf("POST %s responded with %v, expected success only for the first song", url, data.Results)
+		}
+		updated, _ := song.NewDBRepository(nolog.Logger, db).GetSong(context.TODO(), s.UUID)
+		if updated.VideoGap != 1500*time.Millisecond {
+			t.Errorf("POST %s set video gap to %s, expected %s", url, updated.VideoGap, 1500*time.Millisecond)
+		}
+	})
+	t.Run("400 Bad Request (Missing Content-Type)", test.MissingContentType(h, http.MethodPost, url, "application/json"))
+	t.Run("422 Unprocessable Entity", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"songs": [], "transforms": [{"op": "toAbsolute"}]}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+}
@@ -0,0 +1,64 @@
+package songs
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/variant"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// LinkVariant implements the PUT /v1/songs/{uuid}/variant endpoint.
+// The song is added to the variant group of another song.
+// The response contains the song including its updated variants.
+func (h *Handler) LinkVariant(w http.ResponseWriter, r *http.Request) {
+	song := MustGetSong(r.Context())
+	var link schema.VariantLink
+	if err := render.Bind(r, &link); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	_, err := h.variantRepo.LinkVariant(r.Context(), song.UUID, link.Of, link.Relation)
+	if errors.Is(err, variant.ErrSameSong) {
+		_ = render.Render(w, r, apierror.ValidationError("The variant could not be linked.", map[string]string{"/of": err.Error()}))
+		return
+	} else if errors.Is(err, core.ErrNotFound) {
+		_ = render.Render(w, r, apierror.ValidationError("The variant could not be linked.", map[string]string{"/of": "song not found"}))
+		return
+	} else if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not link song variant.", "uuid", song.UUID, "of", link.Of, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	if song, err = h.songRepo.GetSong(r.Context(), song.UUID); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not fetch song.", "uuid", song.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	h.publish(r.Context(), event.SongUpdated(song))
+	setETag(w, song)
+	resp := schema.FromSong(song)
+	_ = render.Render(w, r, &resp)
+}
+
+// UnlinkVariant implements the DELETE /v1/songs/{uuid}/variant endpoint.
+// Removing a song from its variant group is idempotent.
+func (h *Handler) UnlinkVariant(w http.ResponseWriter, r *http.Request) {
+	song := MustGetSong(r.Context())
+	ok, err := h.variantRepo.UnlinkVariant(r.Context(), song.UUID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not unlink song variant.", "uuid", song.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	if ok {
+		h.publish(r.Context(), event.SongUpdated(song))
+	}
+	_ = render.NoContent(w, r)
+}
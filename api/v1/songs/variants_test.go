@@ -0,0 +1,108 @@
+//go:build database
+
+package songs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_LinkVariant(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	solo := testdata.SimpleSong(t, db)
+	duet := testdata.SimpleSong(t, db)
+	upload := testdata.SongWithUpload(t, db)
+	url := fmt.Sprintf("/v1/songs/%s/variant", duet.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPut, url, strings.NewReader(fmt.Sprintf(`{"of": %q, "relation": "duet-version"}`, solo.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("PUT %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var song schema.Song
+		if err := json.NewDecoder(resp.Body).Decode(&song); err != nil {
+			t.Fatalf("PUT %s responded with invalid song schema: %s", url, err)
+		}
+		if song.VariantGroup == nil || song.VariantRelation != model.VariantRelationDuetVersion {
+			t.Errorf("PUT %s responded with group %v and relation %q, expected a group and %q", url, song.VariantGroup, song.VariantRelation, model.VariantRelationDuetVersion)
+		}
+		if len(song.Variants) != 1 || song.Variants[0].UUID != solo.UUID || song.Variants[0].Relation != model.VariantRelationOriginal {
+			t.Errorf("PUT %s responded with variants %v, expected the original %s", url, song.Variants, solo.UUID)
+		}
+	})
+	t.Run("409 Conflict", testSongConflict(h, http.MethodPut, "/v1/songs/%s/variant", upload.UUID))
+	t.Run("422 Unprocessable Entity (Not Found)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPut, url, strings.NewReader(fmt.Sprintf(`{"of": %q, "relation": "remix"}`, uuid.New())))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertValidationError(t, resp, map[string]string{"/of": "song not found"})
+	})
+	t.Run("422 Unprocessable Entity (Same Song)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPut, url, strings.NewReader(fmt.Sprintf(`{"of": %q, "relation": "remix"}`, duet.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertValidationError(t, resp, map[string]string{"/of": "a song cannot be a variant of itself"})
+	})
+	t.Run("422 Unprocessable Entity (Invalid Relation)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPut, url, strings.NewReader(fmt.Sprintf(`{"of": %q, "relation": "original"}`, solo.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusUnprocessableEntity {
+			t.Errorf("PUT %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusUnprocessableEntity)
+		}
+	})
+}
+
+func TestHandler_UnlinkVariant(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	solo := testdata.SimpleSong(t, db)
+	duet := testdata.SimpleSong(t, db)
+	testdata.VariantGroup(t, db, solo.UUID, map[uuid.UUID]model.VariantRelation{duet.UUID: model.VariantRelationDuetVersion})
+	url := fmt.Sprintf("/v1/songs/%s/variant", duet.UUID)
+
+	t.Run("204 No Content", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodDelete, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("DELETE %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
+		}
+		song, _ := h.songRepo.GetSong(r.Context(), solo.UUID)
+		if song.VariantGroup != uuid.Nil {
+			t.Errorf("DELETE %s did not remove the variant group, expected no group", url)
+		}
+	})
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodDelete, fmt.Sprintf("/v1/songs/%s/variant", uuid.New()), http.StatusNotFound))
+}
+
+func TestHandler_Find_VariantGroup(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/songs/")
+	testdata.NSongs(t, db, 3)
+	solo := testdata.SimpleSong(t, db)
+	duet := testdata.SimpleSong(t, db)
+	group := testdata.VariantGroup(t, db, solo.UUID, map[uuid.UUID]model.VariantRelation{duet.UUID: model.VariantRelationDuetVersion})
+
+	t.Run("200 OK", func(t *testing.T) {
+		url := fmt.Sprintf("/v1/songs/?variantGroup=%s", group)
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertPagination(t, resp, 0, 25, 2, 2)
+	})
+	t.Run("400 Bad Request", test.HTTPError(h, http.MethodGet, "/v1/songs/?variantGroup=foo", http.StatusBadRequest))
+}
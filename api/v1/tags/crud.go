@@ -0,0 +1,102 @@
+package tags
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Create implements the POST /v1/tags endpoint.
+// Tags are also created implicitly when songs are tagged.
+// Creating tags explicitly is useful to assign a color right away.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var data schema.TagRW
+	if err := render.Bind(r, &data); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	tag := model.Tag{}
+	data.Apply(&tag)
+	if err := h.tagRepo.CreateTag(r.Context(), &tag); errors.Is(err, core.ErrConflict) {
+		_ = render.Render(w, r, apierror.TagNameConflict())
+		return
+	} else if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not create tag.", tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	render.SetStatus(r, http.StatusCreated)
+	resp := schema.FromTag(tag)
+	_ = render.Render(w, r, &resp)
+}
+
+// Find implements the GET /v1/tags endpoint.
+func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
+	pagination := middleware.MustGetPagination(r.Context())
+	tags, total, err := h.tagRepo.FindTags(r.Context(), pagination.Limit, pagination.Offset)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list tags.", "limit", pagination.Limit, "offset", pagination.Offset, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+
+	resp := schema.List[*schema.Tag]{
+		Items:  make([]*schema.Tag, len(tags)),
+		Offset: pagination.Offset,
+		Limit:  pagination.RequestLimit,
+		Total:  total,
+	}
+	for i, tag := range tags {
+		s := schema.FromTag(tag)
+		resp.Items[i] = &s
+	}
+	_ = render.Render(w, r, &resp)
+}
+
+// Get implements the GET /v1/tags/{uuid} endpoint.
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	tag := MustGetTag(r.Context())
+	resp := schema.FromTag(tag)
+	_ = render.Render(w, r, &resp)
+}
+
+// Update implements the PATCH /v1/tags/{uuid} endpoint.
+// Renaming a tag renames it for all songs.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	tag := MustGetTag(r.Context())
+	update := schema.FromTag(tag)
+	if err := render.Bind(r, &update); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	update.Apply(&tag)
+	if err := h.tagRepo.UpdateTag(r.Context(), &tag); errors.Is(err, core.ErrConflict) {
+		_ = render.Render(w, r, apierror.TagNameConflict())
+		return
+	} else if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not update tag.", "uuid", tag.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	_ = render.NoContent(w, r)
+}
+
+// Delete implements the DELETE /v1/tags/{uuid} endpoint.
+// The tag is removed from all songs.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := middleware.MustGetUUID(r.Context())
+	if _, err := h.tagRepo.DeleteTag(r.Context(), id); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not delete tag.", "uuid", id, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	_ = render.NoContent(w, r)
+}
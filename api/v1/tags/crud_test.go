@@ -0,0 +1,164 @@
+//go:build database
+
+package tags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/tag"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_Create(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/tags/")
+	testdata.Tag(t, db, "Christmas", "#ff0000")
+	url := "/v1/tags/"
+
+	t.Run("201 Created", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"name": "party-starter", "color": "#00FF00"}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusCreated)
+		}
+		var tg schema.Tag
+		if err := json.NewDecoder(resp.Body).Decode(&tg); err != nil {
+			t.Errorf("POST %s responded with invalid tag schema: %s", url, err)
+			return
+		}
+		if tg.UUID == uuid.Nil {
+			t.Errorf("POST %s responded with no tag UUID, expected non-nil UUID", url)
+		}
+		if tg.Name != "party-starter" || tg.Color != "#00ff00" {
+			t.Errorf("POST %s responded with name %q and color %q, expected %q and %q", url, tg.Name, tg.Color, "party-starter", "#00ff00")
+		}
+	})
+
+	t.Run("400 Bad Request (Missing Content-Type)", test.MissingContentType(h, http.MethodPost, url, "application/json"))
+
+	t.Run("409 Conflict", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"name": "CHRISTMAS"}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusConflict, apierror.TypeTagNameConflict, nil)
+	})
+
+	t.Run("422 Unprocessable Entity", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"name": "needs-video", "color": "red"}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+}
+
+func TestHandler_Find(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/tags/")
+	testdata.Tag(t, db, "needs-video", "")
+	testdata.Tag(t, db, "Christmas", "#ff0000")
+	url := "/v1/tags/"
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		test.AssertPagination(t, resp, 0, 25, 2, 2)
+		var tags []schema.Tag
+		if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+			t.Errorf("GET %s responded with invalid tag list schema: %s", url, err)
+			return
+		}
+		if len(tags) != 2 || tags[0].Name != "Christmas" {
+			t.Errorf("GET %s responded with %v, expected 2 tags ordered by name", url, tags)
+		}
+	})
+	t.Run("400 Bad Request (Pagination)", test.InvalidPagination(h, http.MethodGet, url))
+}
+
+func TestHandler_Get(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/tags/")
+	christmas := testdata.Tag(t, db, "Christmas", "#ff0000")
+
+	t.Run("200 OK", func(t *testing.T) {
+		url := fmt.Sprintf("/v1/tags/%s", christmas.UUID)
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var tg schema.Tag
+		if err := json.NewDecoder(resp.Body).Decode(&tg); err != nil {
+			t.Errorf("GET %s responded with invalid tag schema: %s", url, err)
+			return
+		}
+		if tg.UUID != christmas.UUID || tg.Color != "#ff0000" {
+			t.Errorf("GET %s responded with %v, expected tag %q with color %q", url, tg, christmas.UUID, christmas.Color)
+		}
+	})
+	t.Run("400 Bad Request", test.InvalidUUID(h, http.MethodGet, "/v1/tags/"+testdata.InvalidUUID))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodGet, "/v1/tags/"+uuid.New().String(), http.StatusNotFound))
+}
+
+func TestHandler_Update(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/tags/")
+	christmas := testdata.Tag(t, db, "Christmas", "")
+	testdata.Tag(t, db, "needs-video", "")
+	url := fmt.Sprintf("/v1/tags/%s", christmas.UUID)
+
+	t.Run("204 No Content", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"color": "#ff0000"}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("PATCH %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
+		}
+		updated, _ := tag.NewDBRepository(nolog.Logger, db).GetTag(context.TODO(), christmas.UUID)
+		if updated.Name != christmas.Name || updated.Color != "#ff0000" {
+			t.Errorf("PATCH %s produced name %q and color %q, expected %q and %q", url, updated.Name, updated.Color, christmas.Name, "#ff0000")
+		}
+	})
+	t.Run("409 Conflict", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"name": "Needs-Video"}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusConflict, apierror.TypeTagNameConflict, nil)
+	})
+}
+
+func TestHandler_Delete(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/tags/")
+	christmas := testdata.Tag(t, db, "Christmas", "")
+	url := fmt.Sprintf("/v1/tags/%s", christmas.UUID)
+
+	t.Run("204 No Content", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodDelete, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		if resp.StatusCode != http.StatusNoContent {
+			t.Errorf("DELETE %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
+		}
+		if _, err := tag.NewDBRepository(nolog.Logger, db).GetTag(context.TODO(), christmas.UUID); !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("DELETE %s did not delete the tag, GetTag returned %v", url, err)
+		}
+	})
+	t.Run("400 Bad Request", test.InvalidUUID(h, http.MethodDelete, "/v1/tags/"+testdata.InvalidUUID))
+}
@@ -0,0 +1,53 @@
+package tags
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core/tag"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Handler implements the /v1/tags endpoints.
+type Handler struct {
+	logger *slog.Logger
+	r      chi.Router
+
+	tagRepo tag.Repository
+}
+
+// NewHandler creates a new Handler instance using the specified repository.
+func NewHandler(
+	logger *slog.Logger,
+	tagRepo tag.Repository,
+) *Handler {
+	r := chi.NewRouter()
+	h := &Handler{
+		logger,
+		r,
+		tagRepo,
+	}
+
+	r.With(middleware.ContentTypeJSON, render.ContentTypeNegotiation("application/json")).Post("/", h.Create)
+	r.With(middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json")).Get("/", h.Find)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.UUID("uuid"))
+		r.Delete("/{uuid}", h.Delete)
+
+		r.Group(func(r chi.Router) {
+			r.Use(h.FetchTag)
+			r.With(render.ContentTypeNegotiation("application/json")).Get("/{uuid}", h.Get)
+			r.With(middleware.ContentTypeJSON).Patch("/{uuid}", h.Update)
+		})
+	})
+	return h
+}
+
+// ServeHTTP processes HTTP requests for h.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.r.ServeHTTP(w, r)
+}
@@ -0,0 +1,62 @@
+package tags
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// contextKey is the type for context keys used in this package.
+// This type is intentionally private.
+// Use the accessor functions instead to interact with context values.
+type contextKey int
+
+const (
+	// contextKeyInstance identifies a Tag instance in a context.
+	contextKeyInstance contextKey = iota
+)
+
+// SetTag sets the tag instance in ctx.
+func SetTag(ctx context.Context, tag model.Tag) context.Context {
+	return context.WithValue(ctx, contextKeyInstance, tag)
+}
+
+// GetTag returns a model.Tag instance from the context.
+// If the context does not contain a tag instance, the second return value will be false.
+func GetTag(ctx context.Context) (model.Tag, bool) {
+	tag, ok := ctx.Value(contextKeyInstance).(model.Tag)
+	return tag, ok
+}
+
+// MustGetTag returns a model.Tag instance from the context.
+// In contrast to GetTag this function panics if the context does not contain a tag instance.
+func MustGetTag(ctx context.Context) model.Tag {
+	return ctx.Value(contextKeyInstance).(model.Tag)
+}
+
+// FetchTag is a middleware that fetches the model.Tag instance identified by the request and stores it in the request context.
+func (h *Handler) FetchTag(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := middleware.MustGetUUID(r.Context())
+		tag, err := h.tagRepo.GetTag(r.Context(), id)
+		if errors.Is(err, core.ErrNotFound) {
+			_ = render.Render(w, r, apierror.ErrNotFound)
+			return
+		} else if err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not fetch tag.", "uuid", id, tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		ctx := SetTag(r.Context(), tag)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+	return http.HandlerFunc(fn)
+}
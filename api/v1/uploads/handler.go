@@ -1,12 +1,17 @@
 package uploads
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/lmittmann/tint"
 
 	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/revision"
+	"github.com/Karaoke-Manager/karman/core/song"
 	"github.com/Karaoke-Manager/karman/core/upload"
 	"github.com/Karaoke-Manager/karman/model"
 	"github.com/Karaoke-Manager/karman/pkg/render"
@@ -17,15 +22,24 @@ type Handler struct {
 	logger *slog.Logger
 	r      chi.Router
 
-	uploadRepo  upload.Repository
-	uploadStore upload.Store
+	uploadRepo   upload.Repository
+	uploadStore  upload.Store
+	uploadSvc    upload.Service
+	songRepo     song.Repository
+	revisionRepo revision.Repository
+	events       event.Bus
 }
 
 // NewHandler creates a new Handler instance using the specified service.
+// Songs imported from uploads are recorded in revisionRepo and published to events.
 func NewHandler(
 	logger *slog.Logger,
 	uploadRepo upload.Repository,
 	uploadStore upload.Store,
+	uploadSvc upload.Service,
+	songRepo song.Repository,
+	revisionRepo revision.Repository,
+	events event.Bus,
 ) *Handler {
 	r := chi.NewRouter()
 	h := &Handler{
@@ -33,6 +47,10 @@ func NewHandler(
 		r,
 		uploadRepo,
 		uploadStore,
+		uploadSvc,
+		songRepo,
+		revisionRepo,
+		events,
 	}
 
 	r.With(render.ContentTypeNegotiation("application/json")).Post("/", h.Create)
@@ -61,23 +79,16 @@ func NewHandler(
 				r.Use(UploadState(model.UploadStateProcessing, model.UploadStateDone))
 				r.With(middleware.Paginate(100, 1000), render.ContentTypeNegotiation("application/json")).Get("/{uuid}/errors", h.GetErrors)
 			})
+
+			r.Group(func(r chi.Router) {
+				r.Use(UploadState(model.UploadStateDone))
+				r.With(middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json")).Get("/{uuid}/songs", h.FindSongs)
+				r.With(middleware.ContentTypeJSON, render.ContentTypeNegotiation("application/json")).Post("/{uuid}/import", h.Import)
+			})
 		})
 	})
 
 	// POST /{uuid}/beginProcessing
-
-	// GET /{uuid}/songs
-
-	// OPTION 1:
-	// GET /{uuid}/songs/{id2}
-	// DELETE /{uuid}/songs{id2}
-	// POST /{uuid}/import
-
-	// OPTION 2:
-	// GET /{uuid}/songs
-	// GET /songs/...
-	// DELETE /songs/...
-	// POST /{uuid}/import (with the songs to import)
 	return h
 }
 
@@ -85,3 +96,11 @@ func NewHandler(
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.r.ServeHTTP(w, r)
 }
+
+// publish sends e to the event bus.
+// Events are informational only, so a failure to publish an event does not fail the request.
+func (h *Handler) publish(ctx context.Context, e event.Event) {
+	if err := h.events.Publish(ctx, e); err != nil {
+		h.logger.WarnContext(ctx, "Could not publish event.", "type", e.Type, tint.Err(err))
+	}
+}
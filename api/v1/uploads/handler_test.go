@@ -17,7 +17,14 @@ import (
 	"github.com/jackc/pgxutil"
 
 	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/core/artist"
+	"github.com/Karaoke-Manager/karman/core/duplicate"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/header"
+	"github.com/Karaoke-Manager/karman/core/revision"
+	"github.com/Karaoke-Manager/karman/core/song"
 	"github.com/Karaoke-Manager/karman/core/upload"
+	"github.com/Karaoke-Manager/karman/core/variant"
 	"github.com/Karaoke-Manager/karman/pkg/nolog"
 	_ "github.com/Karaoke-Manager/karman/pkg/render/json"
 	"github.com/Karaoke-Manager/karman/test"
@@ -38,8 +45,14 @@ func setupHandler(t *testing.T, prefix string) (*Handler, pgxutil.DB) {
 		t.Fatalf("NewFileStore(%q) returned an unexpected error: %s", dir, err)
 	}
 
+	songRepo := song.NewDBRepository(nolog.Logger, db)
+	songSvc := song.NewService(artist.NewDBRepository(nolog.Logger, db), song.DefaultNaming)
+	revisionRepo := revision.NewDBRepository(nolog.Logger, db)
+	events := event.NewMemBus()
+	uploadSvc := upload.NewService(nolog.Logger, uploadRepo, uploadStore, songRepo, songSvc, duplicate.NewDBRepository(nolog.Logger, db), header.NewDBRepository(nolog.Logger, db), variant.NewDBRepository(nolog.Logger, db), events, nil)
+
 	// workaround to support the prefix
-	h := NewHandler(nolog.Logger, uploadRepo, uploadStore)
+	h := NewHandler(nolog.Logger, uploadRepo, uploadStore, uploadSvc, songRepo, revisionRepo, events)
 	r := chi.NewRouter()
 	r.Mount(strings.TrimSuffix(prefix, "/")+"/", h.r)
 	h.r = r
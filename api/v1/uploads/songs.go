@@ -0,0 +1,147 @@
+package uploads
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// FindSongs implements the GET /v1/uploads/{uuid}/songs endpoint.
+// Songs that match a library song include the changes from the library song to the uploaded song.
+func (h *Handler) FindSongs(w http.ResponseWriter, r *http.Request) {
+	upload := MustGetUpload(r.Context())
+	pagination := middleware.MustGetPagination(r.Context())
+	songs, total, err := h.uploadRepo.FindSongs(r.Context(), upload.UUID, pagination.Limit, pagination.Offset)
+	if err != nil {
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+
+	resp := schema.List[*schema.UploadSong]{
+		Items:  make([]*schema.UploadSong, len(songs)),
+		Offset: pagination.Offset,
+		Limit:  pagination.RequestLimit,
+		Total:  total,
+	}
+	for i, s := range songs {
+		uploaded, err := h.songRepo.GetSong(r.Context(), s.Song)
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not fetch song.", "uuid", s.Song, tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		var match *model.Song
+		if s.Match != uuid.Nil {
+			m, err := h.songRepo.GetLibrarySong(r.Context(), s.Match)
+			if err != nil && !errors.Is(err, core.ErrNotFound) {
+				_ = render.Render(w, r, apierror.ErrInternalServerError)
+				return
+			}
+			if err == nil {
+				match = &m
+			}
+		}
+		item := schema.FromUploadSong(uploaded, match)
+		resp.Items[i] = &item
+	}
+	_ = render.Render(w, r, &resp)
+}
+
+// Import implements the POST /v1/uploads/{uuid}/import endpoint.
+// Each song is imported according to its action.
+// All songs are validated before any song is imported.
+// Songs that are not imported remain in the upload.
+func (h *Handler) Import(w http.ResponseWriter, r *http.Request) {
+	upload := MustGetUpload(r.Context())
+	var data schema.UploadImport
+	if err := render.Bind(r, &data); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+
+	errs := make(map[string]string)
+	songs := make([]model.UploadSong, len(data.Songs))
+	versions := make([]time.Time, len(data.Songs))
+	seen := make(map[uuid.UUID]bool, len(data.Songs))
+	for i, s := range data.Songs {
+		pointer := fmt.Sprintf("/songs/%d/song", i)
+		if seen[s.Song] {
+			errs[pointer] = "song is imported more than once"
+			continue
+		}
+		seen[s.Song] = true
+		song, err := h.uploadRepo.GetSong(r.Context(), upload.UUID, s.Song)
+		if errors.Is(err, core.ErrNotFound) {
+			errs[pointer] = "song not found in upload"
+			continue
+		} else if err != nil {
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		songs[i] = song
+		if s.Action == model.ImportActionKeepBoth {
+			continue
+		}
+		if song.Match == uuid.Nil {
+			errs[fmt.Sprintf("/songs/%d/action", i)] = "song does not match a library song"
+			continue
+		}
+		library, err := h.songRepo.GetLibrarySong(r.Context(), song.Match)
+		if errors.Is(err, core.ErrNotFound) {
+			errs[fmt.Sprintf("/songs/%d/action", i)] = "song does not match a library song"
+			continue
+		} else if err != nil {
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		if s.IfMatch != "" {
+			if etag := middleware.ETag(library.UpdatedAt); s.IfMatch != etag {
+				_ = render.Render(w, r, apierror.PreconditionFailed(etag))
+				return
+			}
+			versions[i] = library.UpdatedAt
+		}
+	}
+	if len(errs) > 0 {
+		_ = render.Render(w, r, apierror.ValidationError("The songs cannot be imported.", errs))
+		return
+	}
+
+	resp := make(schema.ImportedSongs, len(songs))
+	for i, s := range songs {
+		action := data.Songs[i].Action
+		imported, err := h.uploadSvc.ImportSong(r.Context(), s, action, versions[i])
+		if errors.Is(err, core.ErrPreconditionFailed) {
+			// the library song has been modified concurrently
+			_ = render.Render(w, r, apierror.PreconditionFailed(""))
+			return
+		} else if err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not import song.", "uuid", s.Song, "action", action, tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		rev := model.NewSongRevision(imported, r.Header.Get("From"))
+		if err = h.revisionRepo.CreateRevision(r.Context(), imported.UUID, &rev); err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not record song revision.", "uuid", imported.UUID, tint.Err(err))
+		}
+		if action == model.ImportActionKeepBoth || action == model.ImportActionAddVariant {
+			h.publish(r.Context(), event.SongCreated(imported))
+		} else {
+			h.publish(r.Context(), event.SongUpdated(imported))
+		}
+		resp[i] = schema.FromSong(imported)
+	}
+	_ = render.Render(w, r, resp)
+}
@@ -0,0 +1,185 @@
+//go:build database
+
+package uploads
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_FindSongs(t *testing.T) {
+	t.Parallel()
+
+	h, db := setupHandler(t, "/v1/uploads/")
+	openUpload := testdata.OpenUpload(t, db)
+	upload, uploaded, library := testdata.DoneUploadWithMatch(t, db)
+	url := fmt.Sprintf("/v1/uploads/%s/songs", upload.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		test.AssertPagination(t, resp, 0, 25, 1, 1)
+		var songs []schema.UploadSong
+		if err := json.NewDecoder(resp.Body).Decode(&songs); err != nil {
+			t.Errorf("GET %s responded with invalid upload song schema: %s", url, err)
+			return
+		}
+		if len(songs) != 1 {
+			t.Fatalf("GET %s responded with %d songs, expected 1", url, len(songs))
+		}
+		if songs[0].Song.UUID != uploaded.UUID {
+			t.Errorf("GET %s responded with song %s, expected %s", url, songs[0].Song.UUID, uploaded.UUID)
+		}
+		if songs[0].Match == nil {
+			t.Fatalf("GET %s responded with no match, expected %s", url, library.UUID)
+		}
+		if songs[0].Match.Song.UUID != library.UUID {
+			t.Errorf("GET %s responded with match %s, expected %s", url, songs[0].Match.Song.UUID, library.UUID)
+		}
+		if len(songs[0].Match.Fields) != 1 || songs[0].Match.Fields[0].Field != "comment" {
+			t.Errorf("GET %s responded with field changes %v, expected a change of the comment", url, songs[0].Match.Fields)
+		}
+	})
+	t.Run("400 Bad Request (Invalid UUID)", test.InvalidUUID(h, http.MethodGet, fmt.Sprintf("/v1/uploads/%s/songs", testdata.InvalidUUID)))
+	t.Run("400 Bad Request (Pagination)", test.InvalidPagination(h, http.MethodGet, url))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodGet, fmt.Sprintf("/v1/uploads/%s/songs", uuid.New()), http.StatusNotFound))
+	t.Run("409 Conflict", testInvalidState(h, http.MethodGet, "/v1/uploads/%s/songs", openUpload.UUID))
+}
+
+func TestHandler_Import(t *testing.T) {
+	t.Parallel()
+
+	h, db := setupHandler(t, "/v1/uploads/")
+	openUpload := testdata.OpenUpload(t, db)
+
+	t.Run("200 OK (Replace)", func(t *testing.T) {
+		upload, uploaded, library := testdata.DoneUploadWithMatch(t, db)
+		url := fmt.Sprintf("/v1/uploads/%s/import", upload.UUID)
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"songs": [{"song": %q, "action": "replace"}]}`, uploaded.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var songs []schema.Song
+		if err := json.NewDecoder(resp.Body).Decode(&songs); err != nil {
+			t.Fatalf("POST %s responded with invalid song schema: %s", url, err)
+		}
+		if len(songs) != 1 {
+			t.Fatalf("POST %s responded with %d songs, expected 1", url, len(songs))
+		}
+		if songs[0].UUID != library.UUID {
+			t.Errorf("POST %s responded with song %s, expected %s", url, songs[0].UUID, library.UUID)
+		}
+		if songs[0].Comment != uploaded.Comment {
+			t.Errorf("POST %s responded with comment %q, expected %q", url, songs[0].Comment, uploaded.Comment)
+		}
+	})
+	t.Run("200 OK (Keep Both)", func(t *testing.T) {
+		upload, uploaded, _ := testdata.DoneUploadWithMatch(t, db)
+		url := fmt.Sprintf("/v1/uploads/%s/import", upload.UUID)
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"songs": [{"song": %q}]}`, uploaded.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var songs []schema.Song
+		if err := json.NewDecoder(resp.Body).Decode(&songs); err != nil {
+			t.Fatalf("POST %s responded with invalid song schema: %s", url, err)
+		}
+		if len(songs) != 1 || songs[0].UUID != uploaded.UUID {
+			t.Errorf("POST %s responded with %v, expected song %s", url, songs, uploaded.UUID)
+		}
+	})
+	t.Run("200 OK (Add Variant)", func(t *testing.T) {
+		upload, uploaded, library := testdata.DoneUploadWithMatch(t, db)
+		url := fmt.Sprintf("/v1/uploads/%s/import", upload.UUID)
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"songs": [{"song": %q, "action": "add-variant"}]}`, uploaded.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var songs []schema.Song
+		if err := json.NewDecoder(resp.Body).Decode(&songs); err != nil {
+			t.Fatalf("POST %s responded with invalid song schema: %s", url, err)
+		}
+		if len(songs) != 1 || songs[0].UUID != uploaded.UUID {
+			t.Fatalf("POST %s responded with %v, expected song %s", url, songs, uploaded.UUID)
+		}
+		if songs[0].VariantGroup == nil || len(songs[0].Variants) != 1 || songs[0].Variants[0].UUID != library.UUID {
+			t.Errorf("POST %s responded with variants %v, expected song %s", url, songs[0].Variants, library.UUID)
+		}
+	})
+	t.Run("412 Precondition Failed", func(t *testing.T) {
+		upload, uploaded, library := testdata.DoneUploadWithMatch(t, db)
+		url := fmt.Sprintf("/v1/uploads/%s/import", upload.UUID)
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"songs": [{"song": %q, "action": "replace", "ifMatch": "\"invalid\""}]}`, uploaded.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusPreconditionFailed, apierror.TypePreconditionFailed, map[string]any{
+			"etag": middleware.ETag(library.UpdatedAt),
+		})
+	})
+	t.Run("400 Bad Request (Invalid UUID)", test.InvalidUUID(h, http.MethodPost, fmt.Sprintf("/v1/uploads/%s/import", testdata.InvalidUUID)))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodPost, fmt.Sprintf("/v1/uploads/%s/import", uuid.New()), http.StatusNotFound))
+	t.Run("409 Conflict", testInvalidState(h, http.MethodPost, "/v1/uploads/%s/import", openUpload.UUID))
+	t.Run("422 Unprocessable Entity (Duplicate)", func(t *testing.T) {
+		upload, uploaded, _ := testdata.DoneUploadWithMatch(t, db)
+		url := fmt.Sprintf("/v1/uploads/%s/import", upload.UUID)
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"songs": [
+			{"song": %q, "action": "merge-metadata"},
+			{"song": %q, "action": "replace"}
+		]}`, uploaded.UUID, uploaded.UUID)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertValidationError(t, resp, map[string]string{
+			"/songs/1/song": "song is imported more than once",
+		})
+	})
+	t.Run("422 Unprocessable Entity (Not Found)", func(t *testing.T) {
+		upload, _, _ := testdata.DoneUploadWithMatch(t, db)
+		url := fmt.Sprintf("/v1/uploads/%s/import", upload.UUID)
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"songs": [{"song": %q}]}`, uuid.New())))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertValidationError(t, resp, map[string]string{
+			"/songs/0/song": "song not found in upload",
+		})
+	})
+	t.Run("422 Unprocessable Entity (No Match)", func(t *testing.T) {
+		upload := testdata.DoneUploadWithSongs(t, db)
+		songs, _, err := h.uploadRepo.FindSongs(context.TODO(), upload.UUID, 1, 0)
+		if err != nil || len(songs) == 0 {
+			t.Fatalf("FindSongs(ctx, %s, 1, 0) returned %v, %s", upload.UUID, songs, err)
+		}
+		url := fmt.Sprintf("/v1/uploads/%s/import", upload.UUID)
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(fmt.Sprintf(`{"songs": [{"song": %q, "action": "replace"}]}`, songs[0].Song)))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertValidationError(t, resp, map[string]string{
+			"/songs/0/action": "song does not match a library song",
+		})
+	})
+}
@@ -0,0 +1,140 @@
+package webhooks
+
+import (
+	"net/http"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/webhook"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Create implements the POST /v1/webhooks endpoint.
+// If no secret is specified, a random secret is generated.
+// The response contains the secret of the webhook.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var data schema.WebhookRW
+	if err := render.Bind(r, &data); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	if invalid := invalidEvents(data.Events); len(invalid) > 0 {
+		_ = render.Render(w, r, apierror.InvalidWebhookEvents(invalid))
+		return
+	}
+	if data.Secret == "" {
+		data.Secret = webhook.NewSecret()
+	}
+	hook := model.Webhook{}
+	data.Apply(&hook)
+	if err := h.webhookRepo.CreateWebhook(r.Context(), &hook); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not create webhook.", tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	render.SetStatus(r, http.StatusCreated)
+	resp := schema.FromWebhook(hook)
+	resp.Secret = hook.Secret
+	_ = render.Render(w, r, &resp)
+}
+
+// invalidEvents returns the indexes of the filters in events that do not match any known event.
+func invalidEvents(events []string) []int {
+	var invalid []int
+	for i, filter := range events {
+		if !webhook.ValidFilter(filter) {
+			invalid = append(invalid, i)
+		}
+	}
+	return invalid
+}
+
+// Find implements the GET /v1/webhooks endpoint.
+func (h *Handler) Find(w http.ResponseWriter, r *http.Request) {
+	pagination := middleware.MustGetPagination(r.Context())
+	hooks, total, err := h.webhookRepo.FindWebhooks(r.Context(), pagination.Limit, pagination.Offset)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list webhooks.", "limit", pagination.Limit, "offset", pagination.Offset, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+
+	resp := schema.List[*schema.Webhook]{
+		Items:  make([]*schema.Webhook, len(hooks)),
+		Offset: pagination.Offset,
+		Limit:  pagination.RequestLimit,
+		Total:  total,
+	}
+	for i, hook := range hooks {
+		s := schema.FromWebhook(hook)
+		resp.Items[i] = &s
+	}
+	_ = render.Render(w, r, &resp)
+}
+
+// Get implements the GET /v1/webhooks/{uuid} endpoint.
+func (h *Handler) Get(w http.ResponseWriter, r *http.Request) {
+	hook := MustGetWebhook(r.Context())
+	resp := schema.FromWebhook(hook)
+	_ = render.Render(w, r, &resp)
+}
+
+// Update implements the PATCH /v1/webhooks/{uuid} endpoint.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	hook := MustGetWebhook(r.Context())
+	update := schema.FromWebhook(hook)
+	if err := render.Bind(r, &update); err != nil {
+		_ = render.Render(w, r, apierror.BindError(err))
+		return
+	}
+	if invalid := invalidEvents(update.Events); len(invalid) > 0 {
+		_ = render.Render(w, r, apierror.InvalidWebhookEvents(invalid))
+		return
+	}
+	update.Apply(&hook)
+	if err := h.webhookRepo.UpdateWebhook(r.Context(), &hook); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not update webhook.", "uuid", hook.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	_ = render.NoContent(w, r)
+}
+
+// Delete implements the DELETE /v1/webhooks/{uuid} endpoint.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := middleware.MustGetUUID(r.Context())
+	if _, err := h.webhookRepo.DeleteWebhook(r.Context(), id); err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not delete webhook.", "uuid", id, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+	_ = render.NoContent(w, r)
+}
+
+// GetDeliveries implements the GET /v1/webhooks/{uuid}/deliveries endpoint.
+func (h *Handler) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	hook := MustGetWebhook(r.Context())
+	pagination := middleware.MustGetPagination(r.Context())
+	deliveries, total, err := h.webhookRepo.FindDeliveries(r.Context(), hook.UUID, pagination.Limit, pagination.Offset)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "Could not list webhook deliveries.", "uuid", hook.UUID, tint.Err(err))
+		_ = render.Render(w, r, apierror.ErrInternalServerError)
+		return
+	}
+
+	resp := schema.List[*schema.WebhookDelivery]{
+		Items:  make([]*schema.WebhookDelivery, len(deliveries)),
+		Offset: pagination.Offset,
+		Limit:  pagination.RequestLimit,
+		Total:  total,
+	}
+	for i, delivery := range deliveries {
+		s := schema.FromWebhookDelivery(delivery)
+		resp.Items[i] = &s
+	}
+	_ = render.Render(w, r, &resp)
+}
@@ -0,0 +1,166 @@
+//go:build database
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/schema"
+	"github.com/Karaoke-Manager/karman/core/webhook"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func TestHandler_Create(t *testing.T) {
+	t.Parallel()
+	h, _ := setupHandler(t, "/v1/webhooks/")
+	url := "/v1/webhooks/"
+
+	t.Run("201 Created", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"url": "https://example.com/hook", "events": ["song"]}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusCreated {
+			t.Errorf("POST %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusCreated)
+		}
+		var hook schema.Webhook
+		if err := json.NewDecoder(resp.Body).Decode(&hook); err != nil {
+			t.Errorf("POST %s responded with invalid webhook schema: %s", url, err)
+			return
+		}
+		if hook.UUID == uuid.Nil {
+			t.Errorf("POST %s responded with no webhook UUID, expected non-nil UUID", url)
+		}
+		if hook.Secret == "" {
+			t.Errorf("POST %s responded without a secret, expected a generated secret", url)
+		}
+	})
+
+	t.Run("400 Bad Request (Missing Content-Type)", test.MissingContentType(h, http.MethodPost, url, "application/json"))
+
+	t.Run("422 Unprocessable Entity", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"url": "ftp://example.com"}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertProblemDetails(t, resp, http.StatusUnprocessableEntity, apierror.TypeValidationError, nil)
+	})
+	t.Run("422 Unprocessable Entity (Events)", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, url, strings.NewReader(`{"url": "https://example.com/hook", "events": ["song", "songs", "upload.finished"]}`))
+		r.Header.Set("Content-Type", "application/json")
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+		test.AssertValidationError(t, resp, map[string]string{
+			"/events/1": "unknown event type or category",
+			"/events/2": "unknown event type or category",
+		})
+	})
+}
+
+func TestHandler_Get(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/webhooks/")
+	hook := testdata.Webhook(t, db)
+
+	t.Run("200 OK", func(t *testing.T) {
+		url := fmt.Sprintf("/v1/webhooks/%s", hook.UUID)
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("GET %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusOK)
+		}
+		var data map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+			t.Errorf("GET %s responded with invalid webhook schema: %s", url, err)
+			return
+		}
+		if _, ok := data["secret"]; ok {
+			t.Errorf("GET %s responded with the webhook secret, expected no secret", url)
+		}
+	})
+
+	t.Run("400 Bad Request", test.InvalidUUID(h, http.MethodGet, "/v1/webhooks/"+testdata.InvalidUUID))
+	t.Run("404 Not Found", test.HTTPError(h, http.MethodGet, "/v1/webhooks/"+uuid.New().String(), http.StatusNotFound))
+}
+
+func TestHandler_Update(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/webhooks/")
+	hook := testdata.Webhook(t, db)
+	url := fmt.Sprintf("/v1/webhooks/%s", hook.UUID)
+
+	r := httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"events": ["upload.done"]}`))
+	r.Header.Set("Content-Type", "application/json")
+	resp := test.DoRequest(h, r) //nolint:bodyclose
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("PATCH %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
+	}
+	updated, _ := webhook.NewDBRepository(nolog.Logger, db).GetWebhook(context.TODO(), hook.UUID)
+	if updated.URL != hook.URL || updated.Secret != hook.Secret {
+		t.Errorf("PATCH %s changed the URL or secret, expected them to be unchanged", url)
+	}
+	if len(updated.Events) != 1 || updated.Events[0] != "upload.done" {
+		t.Errorf("PATCH %s produced events %v, expected %v", url, updated.Events, []string{"upload.done"})
+	}
+
+	r = httptest.NewRequest(http.MethodPatch, url, strings.NewReader(`{"events": ["uploads"]}`))
+	r.Header.Set("Content-Type", "application/json")
+	resp = test.DoRequest(h, r) //nolint:bodyclose
+	test.AssertValidationError(t, resp, map[string]string{"/events/0": "unknown event type or category"})
+}
+
+func TestHandler_Delete(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/webhooks/")
+	hook := testdata.Webhook(t, db)
+	url := fmt.Sprintf("/v1/webhooks/%s", hook.UUID)
+
+	for _, name := range []string{"204 No Content", "204 No Content (Missing)"} {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodDelete, url, nil)
+			resp := test.DoRequest(h, r) //nolint:bodyclose
+			if resp.StatusCode != http.StatusNoContent {
+				t.Errorf("DELETE %s responded with status code %d, expected %d", url, resp.StatusCode, http.StatusNoContent)
+			}
+		})
+	}
+}
+
+func TestHandler_GetDeliveries(t *testing.T) {
+	t.Parallel()
+	h, db := setupHandler(t, "/v1/webhooks/")
+	hook := testdata.Webhook(t, db)
+	repo := webhook.NewDBRepository(nolog.Logger, db)
+	for i := 0; i < 3; i++ {
+		delivery := model.WebhookDelivery{EventID: uuid.New(), EventType: "song.created", Attempt: 1, StatusCode: http.StatusOK}
+		_ = repo.CreateDelivery(context.TODO(), hook.UUID, &delivery)
+	}
+	url := fmt.Sprintf("/v1/webhooks/%s/deliveries", hook.UUID)
+
+	t.Run("200 OK", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, url, nil)
+		resp := test.DoRequest(h, r) //nolint:bodyclose
+
+		test.AssertPagination(t, resp, 0, 25, 3, 3)
+		var deliveries []schema.WebhookDelivery
+		if err := json.NewDecoder(resp.Body).Decode(&deliveries); err != nil {
+			t.Errorf("GET %s responded with invalid delivery list schema: %s", url, err)
+			return
+		}
+		if len(deliveries) != 3 || !deliveries[0].Success {
+			t.Errorf("GET %s responded with %v, expected 3 successful deliveries", url, deliveries)
+		}
+	})
+	t.Run("400 Bad Request (Pagination)", test.InvalidPagination(h, http.MethodGet, url))
+}
@@ -0,0 +1,54 @@
+package webhooks
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core/webhook"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// Handler implements the /v1/webhooks endpoints.
+type Handler struct {
+	logger *slog.Logger
+	r      chi.Router
+
+	webhookRepo webhook.Repository
+}
+
+// NewHandler creates a new Handler instance using the specified repository.
+func NewHandler(
+	logger *slog.Logger,
+	webhookRepo webhook.Repository,
+) *Handler {
+	r := chi.NewRouter()
+	h := &Handler{
+		logger,
+		r,
+		webhookRepo,
+	}
+
+	r.With(middleware.ContentTypeJSON, render.ContentTypeNegotiation("application/json")).Post("/", h.Create)
+	r.With(middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json")).Get("/", h.Find)
+
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.UUID("uuid"))
+		r.Delete("/{uuid}", h.Delete)
+
+		r.Group(func(r chi.Router) {
+			r.Use(h.FetchWebhook)
+			r.With(render.ContentTypeNegotiation("application/json")).Get("/{uuid}", h.Get)
+			r.With(middleware.ContentTypeJSON).Patch("/{uuid}", h.Update)
+			r.With(middleware.Paginate(25, 100), render.ContentTypeNegotiation("application/json")).Get("/{uuid}/deliveries", h.GetDeliveries)
+		})
+	})
+	return h
+}
+
+// ServeHTTP processes HTTP requests for h.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.r.ServeHTTP(w, r)
+}
@@ -0,0 +1,62 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/api/apierror"
+	"github.com/Karaoke-Manager/karman/api/middleware"
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+// contextKey is the type for context keys used in this package.
+// This type is intentionally private.
+// Use the accessor functions instead to interact with context values.
+type contextKey int
+
+const (
+	// contextKeyInstance identifies a Webhook instance in a context.
+	contextKeyInstance contextKey = iota
+)
+
+// SetWebhook sets the webhook instance in ctx.
+func SetWebhook(ctx context.Context, hook model.Webhook) context.Context {
+	return context.WithValue(ctx, contextKeyInstance, hook)
+}
+
+// GetWebhook returns a model.Webhook instance from the context.
+// If the context does not contain a webhook instance, the second return value will be false.
+func GetWebhook(ctx context.Context) (model.Webhook, bool) {
+	hook, ok := ctx.Value(contextKeyInstance).(model.Webhook)
+	return hook, ok
+}
+
+// MustGetWebhook returns a model.Webhook instance from the context.
+// In contrast to GetWebhook this function panics if the context does not contain a webhook instance.
+func MustGetWebhook(ctx context.Context) model.Webhook {
+	return ctx.Value(contextKeyInstance).(model.Webhook)
+}
+
+// FetchWebhook is a middleware that fetches the model.Webhook instance identified by the request and stores it in the request context.
+func (h *Handler) FetchWebhook(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		id := middleware.MustGetUUID(r.Context())
+		hook, err := h.webhookRepo.GetWebhook(r.Context(), id)
+		if errors.Is(err, core.ErrNotFound) {
+			_ = render.Render(w, r, apierror.ErrNotFound)
+			return
+		} else if err != nil {
+			h.logger.ErrorContext(r.Context(), "Could not fetch webhook.", "uuid", id, tint.Err(err))
+			_ = render.Render(w, r, apierror.ErrInternalServerError)
+			return
+		}
+		ctx := SetWebhook(r.Context(), hook)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+	return http.HandlerFunc(fn)
+}
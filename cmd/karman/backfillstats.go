@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Karaoke-Manager/karman/core/song"
+)
+
+// backfillAll indicates whether the --all flag of the "backfill-stats" command was set.
+var backfillAll bool
+
+// init registers the "backfill-stats" command.
+func init() {
+	backfillStatsCmd.Flags().BoolVarP(&backfillAll, "all", "a", false, "Recalculate the statistics of all songs, not only of songs without statistics.")
+	rootCmd.AddCommand(backfillStatsCmd)
+}
+
+// backfillStatsCmd implements the "backfill-stats" command.
+var backfillStatsCmd = &cobra.Command{
+	Use:   "backfill-stats",
+	Short: "Calculate missing song statistics",
+	Long: `Calculate the vocal range, difficulty and other statistics of songs that have no statistics yet.
+Statistics are calculated whenever a song is saved.
+Songs that have not been saved since statistics were introduced have no statistics
+and are not found by the range and difficulty filters of the API until this command is run.`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		cleanups := make([]func(), 0)
+		cleanup := func(close func()) {
+			cleanups = append(cleanups, close)
+		}
+		defer func() {
+			for _, cleanup := range cleanups {
+				//goland:noinspection GoDeferInLoop
+				defer cleanup()
+			}
+		}()
+
+		db, err := setupDatabase(cleanup)
+		if err != nil {
+			return err
+		}
+		songRepo := song.NewDBRepository(logger.With("log", "song.repo"), db)
+		count, err := song.BackfillStats(context.Background(), songRepo, backfillAll)
+		if err != nil {
+			return fmt.Errorf("calculating song statistics: %w", err)
+		}
+		fmt.Printf("Calculated statistics of %d songs.\n", count)
+		return nil
+	},
+}
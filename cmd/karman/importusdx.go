@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/lmittmann/tint"
+	"github.com/spf13/cobra"
+
+	"github.com/Karaoke-Manager/karman/core/artist"
+	"github.com/Karaoke-Manager/karman/core/score"
+	"github.com/Karaoke-Manager/karman/core/song"
+)
+
+// init registers the "import-usdx" command.
+func init() {
+	rootCmd.AddCommand(importUSDXCmd)
+}
+
+// importUSDXCmd implements the "import-usdx" command.
+var importUSDXCmd = &cobra.Command{
+	Use:   "import-usdx <Ultrastar.db>",
+	Short: "Import play statistics from UltraStar Deluxe",
+	Long: `Import play counts and highscores from the Ultrastar.db file of UltraStar Deluxe.
+Songs are matched to the Karman library by their artist and title.
+Statistics of matched songs replace any previously imported statistics of these songs.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(_ *cobra.Command, args []string) error {
+		cleanups := make([]func(), 0)
+		cleanup := func(close func()) {
+			cleanups = append(cleanups, close)
+		}
+		defer func() {
+			for _, cleanup := range cleanups {
+				//goland:noinspection GoDeferInLoop
+				defer cleanup()
+			}
+		}()
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		entries, err := score.ReadUSDX(f)
+		if err != nil {
+			return fmt.Errorf("reading %s: %w", args[0], err)
+		}
+
+		db, err := setupDatabase(cleanup)
+		if err != nil {
+			return err
+		}
+		naming, err := parseNaming()
+		if err != nil {
+			mainLogger.Error("Could not parse naming templates.", tint.Err(err))
+			return err
+		}
+		songRepo := song.NewDBRepository(logger.With("log", "song.repo"), db)
+		songSvc := song.NewService(artist.NewDBRepository(logger.With("log", "artist.repo"), db), naming)
+		scoreRepo := score.NewDBRepository(logger.With("log", "score.repo"), db)
+		result, err := score.Import(context.Background(), entries, scoreRepo, songRepo, songSvc)
+		if err != nil {
+			return fmt.Errorf("importing statistics: %w", err)
+		}
+
+		fmt.Printf("Imported statistics of %d songs with %d highscores.\n", result.Matched, result.Scores)
+		if len(result.Unmatched) > 0 {
+			fmt.Printf("%d songs did not match any song in the library:\n", len(result.Unmatched))
+			for _, e := range result.Unmatched {
+				fmt.Printf("  %s\n", e)
+			}
+		}
+		return nil
+	},
+}
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"log/slog"
+	"time"
 )
 
 type JobConfig struct {
@@ -19,16 +20,26 @@ type Config struct {
 	DBConnection    string `mapstructure:"db-url"`
 	RedisConnection string `mapstructure:"redis-url"`
 	API             struct {
-		Address string `mapstructure:"address"`
+		Address             string            `mapstructure:"address"`
+		StrictPreconditions bool              `mapstructure:"strict-preconditions"`
+		DAVTxtVersion       string            `mapstructure:"dav-txt-version"`
+		DAVRelative         bool              `mapstructure:"dav-relative"`
+		DAVMounts           map[string]string `mapstructure:"dav-mounts"`
 	} `mapstructure:"api"`
 	TaskRunner struct {
 		Workers int `mapstructure:"workers"`
 	} `mapstructure:"task-server"`
 	Uploads struct {
-		Dir string `mapstructure:"dir"`
+		Dir    string   `mapstructure:"dir"`
+		Fixers []string `mapstructure:"fixers"`
 	} `mapstructure:"uploads"`
 	Media struct {
 		Dir string `mapstructure:"dir"`
 	} `mapstructure:"media"`
+	Songs struct {
+		TrashRetention time.Duration `mapstructure:"trash-retention"`
+		FolderNaming   string        `mapstructure:"folder-naming"`
+		FileNaming     string        `mapstructure:"file-naming"`
+	} `mapstructure:"songs"`
 	Jobs map[string]JobConfig `mapstructure:"jobs"`
 }
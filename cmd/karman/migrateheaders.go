@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Karaoke-Manager/karman/core/header"
+	"github.com/Karaoke-Manager/karman/core/song"
+)
+
+// dryRun indicates whether the --dry-run flag of the "migrate-headers" command was set.
+var dryRun bool
+
+// init registers the "migrate-headers" command.
+func init() {
+	migrateHeadersCmd.Flags().BoolVarP(&dryRun, "dry-run", "n", false, "Only report the custom tags that would be renamed.")
+	rootCmd.AddCommand(migrateHeadersCmd)
+}
+
+// migrateHeadersCmd implements the "migrate-headers" command.
+var migrateHeadersCmd = &cobra.Command{
+	Use:   "migrate-headers",
+	Short: "Rename legacy custom tags",
+	Long: `Rename the custom tags of all library songs to the canonical spelling of the registry of custom headers.
+Custom tags that use an alias of a header or a different case than its name are renamed.
+If a song contains both spellings of a header, the value of the canonical spelling is kept.`,
+	Args: cobra.NoArgs,
+	RunE: func(_ *cobra.Command, _ []string) error {
+		cleanups := make([]func(), 0)
+		cleanup := func(close func()) {
+			cleanups = append(cleanups, close)
+		}
+		defer func() {
+			for _, cleanup := range cleanups {
+				//goland:noinspection GoDeferInLoop
+				defer cleanup()
+			}
+		}()
+
+		db, err := setupDatabase(cleanup)
+		if err != nil {
+			return err
+		}
+		headerRepo := header.NewDBRepository(logger.With("log", "header.repo"), db)
+		songRepo := song.NewDBRepository(logger.With("log", "song.repo"), db)
+		registry, err := header.LoadRegistry(context.Background(), headerRepo)
+		if err != nil {
+			return fmt.Errorf("loading custom headers: %w", err)
+		}
+		result, err := header.Migrate(context.Background(), registry, songRepo, dryRun)
+		if err != nil {
+			return fmt.Errorf("migrating custom tags: %w", err)
+		}
+
+		if dryRun {
+			fmt.Printf("Would rename custom tags of %d songs.\n", result.Songs)
+		} else {
+			fmt.Printf("Renamed custom tags of %d songs.\n", result.Songs)
+		}
+		for _, key := range slices.Sorted(maps.Keys(result.Renamed)) {
+			h, _ := registry.Lookup(key)
+			fmt.Printf("  #%s -> #%s (%d songs)\n", key, h.Name, result.Renamed[key])
+		}
+		return nil
+	},
+}
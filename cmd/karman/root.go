@@ -105,7 +105,10 @@ func loadConfig() error {
 	if err := viper.Unmarshal(&config, func(config *mapstructure.DecoderConfig) {
 		config.WeaklyTypedInput = true
 		config.Metadata = &meta
-		config.DecodeHook = internal.TextUnmarshalerDecodeHook
+		config.DecodeHook = mapstructure.ComposeDecodeHookFunc(
+			internal.TextUnmarshalerDecodeHook,
+			mapstructure.StringToTimeDurationHookFunc(),
+		)
 	}); err != nil {
 		return fmt.Errorf("unable to decode config file: %w", err)
 	}
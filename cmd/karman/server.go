@@ -7,10 +7,13 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"regexp"
 	"runtime"
+	"strings"
 	"syscall"
 	"time"
 
@@ -25,25 +28,54 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/Karaoke-Manager/karman/api"
+	"github.com/Karaoke-Manager/karman/api/middleware"
 	"github.com/Karaoke-Manager/karman/cmd/karman/health"
 	"github.com/Karaoke-Manager/karman/cmd/karman/internal"
+	"github.com/Karaoke-Manager/karman/core/artist"
+	"github.com/Karaoke-Manager/karman/core/batch"
+	"github.com/Karaoke-Manager/karman/core/duplicate"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/header"
 	"github.com/Karaoke-Manager/karman/core/media"
+	"github.com/Karaoke-Manager/karman/core/playlist"
+	"github.com/Karaoke-Manager/karman/core/review"
+	"github.com/Karaoke-Manager/karman/core/revision"
+	"github.com/Karaoke-Manager/karman/core/score"
+	"github.com/Karaoke-Manager/karman/core/session"
 	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/core/tag"
 	"github.com/Karaoke-Manager/karman/core/upload"
+	"github.com/Karaoke-Manager/karman/core/variant"
+	"github.com/Karaoke-Manager/karman/core/webhook"
 	"github.com/Karaoke-Manager/karman/task"
 )
 
 // The coreServices struct holds an instance of each core service.
 // This is mainly used to pass around multiple coreServices more conveniently.
 type coreServices struct {
-	songService   song.Service
-	songRepo      song.Repository
-	uploadService upload.Service
-	uploadRepo    upload.Repository
-	uploadStore   upload.Store
-	mediaService  media.Service
-	mediaRepo     media.Repository
-	mediaStore    media.Store
+	songService    song.Service
+	songRepo       song.Repository
+	revisionRepo   revision.Repository
+	artistRepo     artist.Repository
+	tagRepo        tag.Repository
+	headerRepo     header.Repository
+	variantRepo    variant.Repository
+	reviewRepo     review.Repository
+	playlistRepo   playlist.Repository
+	sessionRepo    session.Repository
+	scoreRepo      score.Repository
+	duplicateRepo  duplicate.Repository
+	uploadService  upload.Service
+	uploadRepo     upload.Repository
+	uploadStore    upload.Store
+	mediaService   media.Service
+	mediaRepo      media.Repository
+	mediaStore     media.Store
+	webhookService webhook.Service
+	webhookRepo    webhook.Repository
+	batchService   batch.Service
+	batchRepo      batch.Repository
+	eventBus       event.Bus
 }
 
 // migrate indicates whether the --migrate flag was specified.
@@ -57,6 +89,22 @@ func init() {
 	viper.SetDefault("api.address", ":8080")
 	_ = viper.BindPFlag("api.address", serverCmd.Flag("address"))
 
+	serverCmd.Flags().Bool("strict-preconditions", false, "Require an If-Match header on requests that modify songs.")
+	viper.SetDefault("api.strict-preconditions", false)
+	_ = viper.BindPFlag("api.strict-preconditions", serverCmd.Flag("strict-preconditions"))
+
+	serverCmd.Flags().String("dav-txt-version", "", "UltraStar TXT format version (1.0.0, 1.1.0 or 2.0.0) of the songs served via WebDAV at /v1/dav. If empty, the default format is used.")
+	viper.SetDefault("api.dav-txt-version", "")
+	_ = viper.BindPFlag("api.dav-txt-version", serverCmd.Flag("dav-txt-version"))
+
+	serverCmd.Flags().Bool("dav-relative", false, "Write the notes of songs served via WebDAV at /v1/dav in relative mode.")
+	viper.SetDefault("api.dav-relative", false)
+	_ = viper.BindPFlag("api.dav-relative", serverCmd.Flag("dav-relative"))
+
+	serverCmd.Flags().StringToString("dav-mount", nil, "Additional WebDAV mounts at /v1/dav-<name> with their own TXT format, e.g. performous=2.0.0 or usdx=1.0.0:relative.")
+	viper.SetDefault("api.dav-mounts", map[string]string{})
+	_ = viper.BindPFlag("api.dav-mounts", serverCmd.Flag("dav-mount"))
+
 	serverCmd.Flags().IntP("workers", "w", 2*runtime.NumCPU(), "Number of workers for processing background tasks.")
 	viper.SetDefault("task-server.workers", 2*runtime.NumCPU())
 	_ = viper.BindPFlag("task-server.workers", serverCmd.Flag("workers"))
@@ -65,10 +113,26 @@ func init() {
 	viper.SetDefault("uploads.dir", "/usr/local/share/karman/uploads")
 	_ = viper.BindPFlag("uploads.dir", serverCmd.Flag("uploads-dir"))
 
+	serverCmd.Flags().StringSlice("import-fixers", nil, "Timing transforms that are applied to every uploaded song, e.g. toAbsolute. By default no transforms are applied.")
+	viper.SetDefault("uploads.fixers", []string{})
+	_ = viper.BindPFlag("uploads.fixers", serverCmd.Flag("import-fixers"))
+
 	serverCmd.Flags().String("media-dir", "/usr/local/share/karman/media", "Directory in which media files will be stored.")
 	viper.SetDefault("media.dir", "/usr/local/share/karman/media")
 	_ = viper.BindPFlag("media.dir", serverCmd.Flag("media-dir"))
 
+	serverCmd.Flags().Duration("trash-retention", 30*24*time.Hour, "Duration for which deleted songs are kept in the trash before they are purged.")
+	viper.SetDefault("songs.trash-retention", 30*24*time.Hour)
+	_ = viper.BindPFlag("songs.trash-retention", serverCmd.Flag("trash-retention"))
+
+	serverCmd.Flags().String("folder-naming", song.DefaultNaming.Folder.String(), "Template for the names of song folders. The song UUID is always appended.")
+	viper.SetDefault("songs.folder-naming", song.DefaultNaming.Folder.String())
+	_ = viper.BindPFlag("songs.folder-naming", serverCmd.Flag("folder-naming"))
+
+	serverCmd.Flags().String("file-naming", song.DefaultNaming.File.String(), "Template for the names of song files.")
+	viper.SetDefault("songs.file-naming", song.DefaultNaming.File.String())
+	_ = viper.BindPFlag("songs.file-naming", serverCmd.Flag("file-naming"))
+
 	rootCmd.AddCommand(serverCmd)
 }
 
@@ -119,15 +183,15 @@ var serverCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		services, err := setupServices(db)
+		redisConn, err := setupRedis(cleanup)
 		if err != nil {
 			return err
 		}
-		redisConn, err := setupRedis(cleanup)
+		taskClient := setupAsynqClient(redisConn, cleanup)
+		services, err := setupServices(db, redisConn, taskClient, cleanup)
 		if err != nil {
 			return err
 		}
-		_ = setupAsynqClient(redisConn, cleanup)
 		_ = setupTaskInspector(redisConn, cleanup)
 		if _, err := setupTaskRunner(redisConn, services, sigs, cleanup); err != nil {
 			return err
@@ -136,11 +200,23 @@ var serverCmd = &cobra.Command{
 			return err
 		}
 		healthService := setupHealthCheck(redisConn, db, cleanup)
+		davDialect, err := song.ParseDialect(config.API.DAVTxtVersion, config.API.DAVRelative)
+		if err != nil {
+			mainLogger.Error("Could not parse WebDAV TXT format.", tint.Err(err))
+			return fmt.Errorf("parsing WebDAV TXT format: %w", err)
+		}
+		davMounts, err := parseDAVMounts()
+		if err != nil {
+			mainLogger.Error("Could not parse WebDAV mounts.", tint.Err(err))
+			return fmt.Errorf("parsing WebDAV mounts: %w", err)
+		}
 
 		// Run a healthcheck to log potential connection problems directly.
 		healthService.HealthCheck(context.Background())
 
 		mainLogger.Info(fmt.Sprintf("Running HTTP server on %s.", config.API.Address))
+		// shutdown is closed when the server shuts down, ending open event streams.
+		shutdown := make(chan struct{})
 		server := &http.Server{
 			Addr:              config.API.Address,
 			ReadHeaderTimeout: 3 * time.Second,
@@ -150,14 +226,36 @@ var serverCmd = &cobra.Command{
 				healthService,
 				services.songRepo,
 				services.songService,
+				services.revisionRepo,
+				services.artistRepo,
+				services.tagRepo,
+				services.headerRepo,
+				services.variantRepo,
+				services.reviewRepo,
+				services.playlistRepo,
+				services.sessionRepo,
+				services.scoreRepo,
+				services.duplicateRepo,
 				services.mediaService,
 				services.mediaStore,
 				services.uploadRepo,
 				services.uploadStore,
+				services.uploadService,
+				services.webhookRepo,
+				services.batchService,
+				services.batchRepo,
+				services.eventBus,
+				config.API.StrictPreconditions,
+				davDialect,
+				davMounts,
 				config.Debug,
 			),
 			ErrorLog: slog.NewLogLogger(logger.With("log", "http").Handler(), config.Log.Level),
+			BaseContext: func(net.Listener) context.Context {
+				return middleware.SetShutdown(context.Background(), shutdown)
+			},
 		}
+		server.RegisterOnShutdown(func() { close(shutdown) })
 
 		go waitForSignal(sigs, server)
 
@@ -173,9 +271,22 @@ var serverCmd = &cobra.Command{
 }
 
 // setupServices initializes the core application coreServices.
-func setupServices(db pgxutil.DB) (*coreServices, error) {
+// The redis connection is used for the event bus.
+// The task client is used to schedule webhook deliveries and song batches.
+func setupServices(db pgxutil.DB, redisConn asynq.RedisConnOpt, taskClient *asynq.Client, cleanup func(func())) (*coreServices, error) {
 	mainLogger.Info("Setting up application coreServices.")
-	songService := song.NewService()
+	artistRepo := artist.NewDBRepository(logger.With("log", "artist.repo"), db)
+	naming, err := parseNaming()
+	if err != nil {
+		mainLogger.Error("Could not parse naming templates.", tint.Err(err))
+		return nil, err
+	}
+	songService := song.NewService(artistRepo, naming)
+	fixers, err := parseFixers()
+	if err != nil {
+		mainLogger.Error("Could not parse import fixers.", tint.Err(err))
+		return nil, err
+	}
 	uploadStore, err := upload.NewFileStore(logger.With("log", "upload.store"), config.Uploads.Dir)
 	if err != nil {
 		mainLogger.Error("Could not initialize upload storage.", tint.Err(err))
@@ -191,18 +302,108 @@ func setupServices(db pgxutil.DB) (*coreServices, error) {
 	mediaRepo := media.NewDBRepository(logger.With("log", "media.repo"), db)
 	songRepo := song.NewDBRepository(logger.With("log", "song.repo"), db)
 	uploadRepo := upload.NewDBRepository(logger.With("log", "upload.repo"), db)
+	duplicateRepo := duplicate.NewDBRepository(logger.With("log", "duplicate.repo"), db)
+	// Webhooks are listed for every published event.
+	webhookRepo := webhook.NewCachedRepository(webhook.NewDBRepository(logger.With("log", "webhook.repo"), db), time.Minute)
+	eventBus := webhook.NewDispatcher(
+		logger.With("log", "webhook.dispatcher"),
+		setupEventBus(redisConn, cleanup),
+		webhookRepo,
+		task.NewWebhookQueue(taskClient),
+	)
+	revisionRepo := revision.NewDBRepository(logger.With("log", "revision.repo"), db)
+	batchRepo := batch.NewDBRepository(logger.With("log", "batch.repo"), db)
+	headerRepo := header.NewDBRepository(logger.With("log", "header.repo"), db)
+	variantRepo := variant.NewDBRepository(logger.With("log", "variant.repo"), db)
 	return &coreServices{
 		songService,
 		songRepo,
-		upload.NewService(logger.With("log", "upload.service"), uploadRepo, uploadStore, songRepo, songService),
+		revisionRepo,
+		artistRepo,
+		tag.NewDBRepository(logger.With("log", "tag.repo"), db),
+		headerRepo,
+		variantRepo,
+		review.NewDBRepository(logger.With("log", "review.repo"), db),
+		playlist.NewDBRepository(logger.With("log", "playlist.repo"), db),
+		session.NewDBRepository(logger.With("log", "session.repo"), db),
+		score.NewDBRepository(logger.With("log", "score.repo"), db),
+		duplicateRepo,
+		upload.NewService(logger.With("log", "upload.service"), uploadRepo, uploadStore, songRepo, songService, duplicateRepo, headerRepo, variantRepo, eventBus, fixers),
 		uploadRepo,
 		uploadStore,
-		media.NewService(logger.With("log", "song.service"), mediaRepo, mediaStore),
+		media.NewService(logger.With("log", "song.service"), mediaRepo, mediaStore, eventBus),
 		mediaRepo,
 		mediaStore,
+		webhook.NewService(logger.With("log", "webhook.service"), webhookRepo, nil),
+		webhookRepo,
+		batch.NewService(logger.With("log", "batch.service"), batchRepo, songRepo, songService, revisionRepo, eventBus, task.NewBatchQueue(taskClient), fixers),
+		batchRepo,
+		eventBus,
 	}, nil
 }
 
+// parseNaming parses the naming templates from the configuration.
+func parseNaming() (song.Naming, error) {
+	folder, err := song.ParseTemplate(config.Songs.FolderNaming)
+	if err != nil {
+		return song.Naming{}, fmt.Errorf("parsing folder naming template: %w", err)
+	}
+	file, err := song.ParseTemplate(config.Songs.FileNaming)
+	if err != nil {
+		return song.Naming{}, fmt.Errorf("parsing file naming template: %w", err)
+	}
+	return song.Naming{Folder: folder, File: file}, nil
+}
+
+// davMountNamePattern matches valid names of additional WebDAV mounts.
+var davMountNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// parseDAVMounts parses the additional WebDAV mounts from the configuration.
+// Each mount maps a name to a TXT format version, optionally followed by ":relative".
+func parseDAVMounts() (map[string]song.Dialect, error) {
+	mounts := make(map[string]song.Dialect, len(config.API.DAVMounts))
+	for name, format := range config.API.DAVMounts {
+		if !davMountNamePattern.MatchString(name) {
+			return nil, fmt.Errorf("invalid mount name %q", name)
+		}
+		version, mode, _ := strings.Cut(format, ":")
+		if mode != "" && mode != "relative" {
+			return nil, fmt.Errorf("mount %s: unknown mode %q", name, mode)
+		}
+		dialect, err := song.ParseDialect(version, mode == "relative")
+		if err != nil {
+			return nil, fmt.Errorf("mount %s: %w", name, err)
+		}
+		mounts[name] = dialect
+	}
+	return mounts, nil
+}
+
+// parseFixers parses the import fixers from the configuration.
+func parseFixers() ([]song.Transform, error) {
+	fixers := make([]song.Transform, len(config.Uploads.Fixers))
+	for i, text := range config.Uploads.Fixers {
+		fixer, err := song.ParseTransform(text)
+		if err != nil {
+			return nil, fmt.Errorf("parsing import fixer: %w", err)
+		}
+		fixers[i] = fixer
+	}
+	return fixers, nil
+}
+
+// setupEventBus creates an event.Bus that uses the specified redis connection.
+func setupEventBus(redisConn asynq.RedisConnOpt, cleanup func(func())) event.Bus {
+	redisClient := redisConn.MakeRedisClient().(redis.UniversalClient)
+	cleanup(func() {
+		mainLogger.Info("Closing event bus.")
+		if err := redisClient.Close(); err != nil {
+			mainLogger.Error("Could not close event bus redis connection.", tint.Err(err))
+		}
+	})
+	return event.NewRedisBus(logger.With("log", "event.bus"), redisClient)
+}
+
 // setupDatabase create a database connection pool.
 func setupDatabase(cleanup func(func())) (*pgxpool.Pool, error) {
 	mainLogger.Info("Setting up database connection pool.")
@@ -306,9 +507,15 @@ func setupTaskRunner(redisConn asynq.RedisConnOpt, services *coreServices, sig c
 		}),
 		Logger:   &internal.AsynqLogger{Logger: logger.With("log", "asynq.server"), Sig: sig},
 		LogLevel: internal.AsynqLogLevel(config.Log.Level),
+		RetryDelayFunc: func(n int, err error, t *asynq.Task) time.Duration {
+			if t.Type() == task.TypeDeliverWebhook {
+				return task.WebhookRetryDelay(n)
+			}
+			return asynq.DefaultRetryDelayFunc(n, err, t)
+		},
 		// We perform a health check on redis explicitly, so we do not need to use the health check of the task runner.
 	})
-	h := task.NewHandler(logger.With("log", "task"), services.mediaRepo, services.mediaService, services.uploadService, services.uploadRepo, services.uploadStore)
+	h := task.NewHandler(logger.With("log", "task"), services.mediaRepo, services.mediaService, services.songRepo, services.uploadService, services.uploadRepo, services.uploadStore, services.webhookService, services.batchService)
 	if err := taskRunner.Start(h); err != nil {
 		mainLogger.Error("Could not start task runner.", tint.Err(err))
 		return nil, fmt.Errorf("starting task server: %w", err)
@@ -337,6 +544,12 @@ func setupTaskScheduler(redis asynq.RedisConnOpt, sig chan<- os.Signal, cleanup
 			}
 		},
 	})
+	if spec, ok := jobSchedule(task.TypePruneSongs, "@daily"); ok {
+		if _, err := scheduler.Register(spec, task.NewPruneSongsTask(config.Songs.TrashRetention)); err != nil {
+			mainLogger.Error("Could not schedule task.", "task", task.TypePruneSongs, tint.Err(err))
+			return nil, fmt.Errorf("scheduling %s task: %w", task.TypePruneSongs, err)
+		}
+	}
 	if err := scheduler.Start(); err != nil {
 		mainLogger.Error("Could not start task scheduler.", tint.Err(err))
 		return nil, fmt.Errorf("starting task scheduler: %w", err)
@@ -348,6 +561,23 @@ func setupTaskScheduler(redis asynq.RedisConnOpt, sig chan<- os.Signal, cleanup
 	return scheduler, nil
 }
 
+// jobSchedule returns the cron spec for the named job.
+// If the job is not configured, fallback is used.
+// The second return value is false if the job has been disabled.
+func jobSchedule(name string, fallback string) (string, bool) {
+	job, ok := config.Jobs[name]
+	if !ok {
+		return fallback, true
+	}
+	if !job.Enabled {
+		return "", false
+	}
+	if job.Schedule == "" {
+		return fallback, true
+	}
+	return job.Schedule, true
+}
+
 // setupHealthCheck initializes a health.Service.
 func setupHealthCheck(redisConn asynq.RedisConnOpt, db *pgxpool.Pool, cleanup func(func())) *health.Service {
 	mainLogger.Info("Starting health check service.")
@@ -0,0 +1,134 @@
+package artist
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// fakeRepo is a simple implementation of Repository that can be used for testing.
+// The fake repository does not know about songs, so the SongCount of artists is always 0.
+type fakeRepo struct {
+	// artists is the "database" of a fakeRepo.
+	artists map[uuid.UUID]model.Artist
+}
+
+// NewFakeRepository returns a new Repository implementation backed by an in-memory map.
+func NewFakeRepository() Repository {
+	return &fakeRepo{make(map[uuid.UUID]model.Artist)}
+}
+
+// CreateArtist stores the artist and sets its UUID, CreatedAt, and UpdatedAt fields.
+func (r *fakeRepo) CreateArtist(_ context.Context, artist *model.Artist) error {
+	prepareArtist(artist)
+	if r.conflicts(uuid.Nil, artist) {
+		return core.ErrConflict
+	}
+	artist.UUID = uuid.New()
+	artist.CreatedAt = time.Now()
+	artist.UpdatedAt = artist.CreatedAt
+	r.artists[artist.UUID] = *artist
+	return nil
+}
+
+// GetArtist looks up the artist with the specified UUID.
+func (r *fakeRepo) GetArtist(_ context.Context, id uuid.UUID) (model.Artist, error) {
+	artist, ok := r.artists[id]
+	if !ok {
+		return model.Artist{}, core.ErrNotFound
+	}
+	return artist, nil
+}
+
+// GetArtistByName looks up the artist with the specified name or alias.
+func (r *fakeRepo) GetArtistByName(_ context.Context, name string) (model.Artist, error) {
+	for _, artist := range r.artists {
+		if hasName(artist, name) {
+			return artist, nil
+		}
+	}
+	return model.Artist{}, core.ErrNotFound
+}
+
+// FindArtists returns a list of artists ordered by name, limited by the specified pagination parameters.
+func (r *fakeRepo) FindArtists(_ context.Context, limit int, offset int64) ([]model.Artist, int64, error) {
+	if limit < 0 {
+		limit = math.MaxInt
+	}
+	artists := make([]model.Artist, 0, len(r.artists))
+	for _, artist := range r.artists {
+		artists = append(artists, artist)
+	}
+	slices.SortFunc(artists, func(a, b model.Artist) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	total := int64(len(artists))
+	if offset > total {
+		offset = total
+	}
+	artists = artists[offset:]
+	return artists[:min(limit, len(artists))], total, nil
+}
+
+// UpdateArtist updates the name and aliases of artist.
+func (r *fakeRepo) UpdateArtist(_ context.Context, artist *model.Artist) error {
+	if _, ok := r.artists[artist.UUID]; !ok {
+		return core.ErrNotFound
+	}
+	prepareArtist(artist)
+	if r.conflicts(artist.UUID, artist) {
+		return core.ErrConflict
+	}
+	artist.UpdatedAt = time.Now()
+	r.artists[artist.UUID] = *artist
+	return nil
+}
+
+// MergeArtists adds the name and aliases of source to the aliases of target and deletes source.
+func (r *fakeRepo) MergeArtists(_ context.Context, target *model.Artist, source uuid.UUID) error {
+	t, ok := r.artists[target.UUID]
+	if !ok {
+		return core.ErrNotFound
+	}
+	if target.UUID != source {
+		s, ok := r.artists[source]
+		if !ok {
+			return core.ErrNotFound
+		}
+		t.Aliases = append(append(t.Aliases, s.Aliases...), s.Name)
+		prepareArtist(&t)
+		delete(r.artists, source)
+		r.artists[t.UUID] = t
+	}
+	*target = t
+	return nil
+}
+
+// conflicts reports whether the name or an alias of artist is used by an artist other than the one with the specified UUID.
+func (r *fakeRepo) conflicts(id uuid.UUID, artist *model.Artist) bool {
+	for _, other := range r.artists {
+		if other.UUID == id {
+			continue
+		}
+		if hasName(other, artist.Name) || slices.ContainsFunc(artist.Aliases, func(alias string) bool {
+			return hasName(other, alias)
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasName reports whether name is the name or an alias of artist, ignoring case.
+func hasName(artist model.Artist, name string) bool {
+	return strings.EqualFold(artist.Name, name) || slices.ContainsFunc(artist.Aliases, func(alias string) bool {
+		return strings.EqualFold(alias, name)
+	})
+}
@@ -0,0 +1,47 @@
+package artist
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func Test_fakeRepo_Artists(t *testing.T) {
+	t.Parallel()
+
+	repo := NewFakeRepository()
+	queen := model.Artist{Name: " Queen ", Aliases: []string{"The Queen", "queen", ""}}
+	if err := repo.CreateArtist(context.TODO(), &queen); err != nil {
+		t.Fatalf("CreateArtist(ctx, &artist) returned an unexpected error: %s", err)
+	}
+	if queen.Name != "Queen" || len(queen.Aliases) != 1 {
+		t.Errorf("CreateArtist(ctx, &artist) produced name %q and aliases %q, expected %q and [%q]", queen.Name, queen.Aliases, "Queen", "The Queen")
+	}
+
+	conflict := model.Artist{Name: "the queen"}
+	if err := repo.CreateArtist(context.TODO(), &conflict); !errors.Is(err, core.ErrConflict) {
+		t.Errorf("CreateArtist(ctx, &artist) with a conflicting name returned %v, expected ErrConflict", err)
+	}
+
+	actual, err := repo.GetArtistByName(context.TODO(), "THE QUEEN")
+	if err != nil {
+		t.Errorf("GetArtistByName(ctx, %q) returned an unexpected error: %s", "THE QUEEN", err)
+	} else if actual.UUID != queen.UUID {
+		t.Errorf("GetArtistByName(ctx, %q) returned artist %q, expected %q", "THE QUEEN", actual.UUID, queen.UUID)
+	}
+
+	mercury := model.Artist{Name: "Freddie Mercury", Aliases: []string{"Freddie"}}
+	_ = repo.CreateArtist(context.TODO(), &mercury)
+	if err = repo.MergeArtists(context.TODO(), &queen, mercury.UUID); err != nil {
+		t.Fatalf("MergeArtists(ctx, &target, %q) returned an unexpected error: %s", mercury.UUID, err)
+	}
+	if len(queen.Aliases) != 3 {
+		t.Errorf("MergeArtists(ctx, &target, %q) produced %d aliases, expected %d", mercury.UUID, len(queen.Aliases), 3)
+	}
+	if _, err = repo.GetArtist(context.TODO(), mercury.UUID); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("GetArtist(ctx, %q) after merging returned %v, expected ErrNotFound", mercury.UUID, err)
+	}
+}
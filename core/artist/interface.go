@@ -0,0 +1,45 @@
+package artist
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// A Repository is an interface for storing artists.
+// Artists are also created implicitly when a song that credits an unknown artist is saved.
+//
+// Artist names and aliases are unique across all artists, ignoring case.
+// Operations that would violate this constraint return core.ErrConflict.
+type Repository interface {
+	// CreateArtist creates a new artist with the specified name and aliases.
+	// This method must set artist.UUID, artist.CreatedAt, and artist.UpdatedAt appropriately.
+	CreateArtist(ctx context.Context, artist *model.Artist) error
+
+	// GetArtist fetches the artist with the specified UUID.
+	// If no such artist exists, core.ErrNotFound will be returned.
+	GetArtist(ctx context.Context, id uuid.UUID) (model.Artist, error)
+
+	// GetArtistByName fetches the artist with the specified name or alias, ignoring case.
+	// If no such artist exists, core.ErrNotFound will be returned.
+	GetArtistByName(ctx context.Context, name string) (model.Artist, error)
+
+	// FindArtists returns all artists ordered by name.
+	// Results are paginated with limit and offset.
+	// The second return value contains the total (unpaginated) number of artists.
+	FindArtists(ctx context.Context, limit int, offset int64) ([]model.Artist, int64, error)
+
+	// UpdateArtist saves the name and aliases of the specified artist.
+	// The artist's UUID must already exist in the database, otherwise core.ErrNotFound will be returned.
+	UpdateArtist(ctx context.Context, artist *model.Artist) error
+
+	// MergeArtists merges the artist with UUID source into target.
+	// Songs crediting source will credit target instead.
+	// The name and aliases of source become aliases of target and source is deleted.
+	// The fields of target are updated to reflect the merge.
+	// If either artist does not exist, core.ErrNotFound will be returned.
+	// Merging an artist into itself has no effect.
+	MergeArtists(ctx context.Context, target *model.Artist, source uuid.UUID) error
+}
@@ -0,0 +1,278 @@
+package artist
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/internal/dbutil"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// dbRepo is the main Repository implementation, backed by a PostgreSQL database.
+type dbRepo struct {
+	logger *slog.Logger
+	db     pgxutil.DB // database connection
+}
+
+// NewDBRepository creates a new Repository backed by the specified database connection.
+// db can be a single connection or a connection pool.
+func NewDBRepository(logger *slog.Logger, db pgxutil.DB) Repository {
+	return &dbRepo{logger, db}
+}
+
+// artistColumns selects the columns of an artist a, including its aliases and the number of library songs crediting the artist.
+const artistColumns = `a.uuid, a.created_at, a.updated_at, a.name,
+    ARRAY(SELECT al.name FROM artist_aliases AS al WHERE al.artist_id = a.id ORDER BY al.name) AS aliases,
+    (SELECT COUNT(*) FROM song_artists AS sa JOIN songs AS s ON sa.song_id = s.id
+        WHERE sa.artist_id = a.id AND s.upload_id IS NULL AND s.deleted_at IS NULL) AS song_count`
+
+// artistRow is the data returned by a SELECT query for artists.
+type artistRow struct {
+	UUID      uuid.UUID
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	Name      string
+	Aliases   []string
+	SongCount int64 `db:"song_count"`
+}
+
+// toModel converts r into an equivalent model.Artist.
+func (r artistRow) toModel() model.Artist {
+	return model.Artist{
+		Model: model.Model{
+			UUID:      r.UUID,
+			CreatedAt: r.CreatedAt,
+			UpdatedAt: r.UpdatedAt,
+		},
+		Name:      r.Name,
+		Aliases:   r.Aliases,
+		SongCount: r.SongCount,
+	}
+}
+
+// CreateArtist creates artist in the database.
+func (r *dbRepo) CreateArtist(ctx context.Context, artist *model.Artist) error {
+	prepareArtist(artist)
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		id, err := pgxutil.InsertRowReturning(ctx, tx, "artists", map[string]any{
+			"name": artist.Name,
+		}, "id", pgx.RowTo[int])
+		if err != nil {
+			return err
+		}
+		if err = setAliases(ctx, tx, id, artist); err != nil {
+			return err
+		}
+		*artist, err = getArtist(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		if err = dbutil.Error(err); !errors.Is(err, core.ErrConflict) {
+			r.logger.ErrorContext(ctx, "Could not create artist.", "name", artist.Name, tint.Err(err))
+		}
+		return err
+	}
+	return nil
+}
+
+// GetArtist fetches a single artist from the database by its UUID.
+func (r *dbRepo) GetArtist(ctx context.Context, id uuid.UUID) (model.Artist, error) {
+	row, err := pgxutil.SelectRow(ctx, r.db, `SELECT `+artistColumns+`
+	FROM artists AS a
+	WHERE a.uuid = $1`, []any{id}, pgx.RowToStructByName[artistRow])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not fetch artist.", "uuid", id, tint.Err(err))
+		}
+		return model.Artist{}, dbutil.Error(err)
+	}
+	return row.toModel(), nil
+}
+
+// GetArtistByName fetches a single artist from the database by its name or one of its aliases.
+func (r *dbRepo) GetArtistByName(ctx context.Context, name string) (model.Artist, error) {
+	row, err := pgxutil.SelectRow(ctx, r.db, `SELECT `+artistColumns+`
+	FROM artists AS a
+	WHERE LOWER(a.name) = LOWER($1)
+	   OR a.id IN (SELECT al.artist_id FROM artist_aliases AS al WHERE LOWER(al.name) = LOWER($1))`, []any{name}, pgx.RowToStructByName[artistRow])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not fetch artist.", "name", name, tint.Err(err))
+		}
+		return model.Artist{}, dbutil.Error(err)
+	}
+	return row.toModel(), nil
+}
+
+// FindArtists fetches multiple artists from the database, ordered by name.
+// The results are paginated with limit and offset.
+func (r *dbRepo) FindArtists(ctx context.Context, limit int, offset int64) ([]model.Artist, int64, error) {
+	total, err := pgxutil.SelectRow(ctx, r.db, `SELECT COUNT(*) FROM artists`, nil, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not count artists.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, 0, err
+	}
+	artists, err := pgxutil.Select(ctx, r.db, `SELECT `+artistColumns+`
+	FROM artists AS a
+	ORDER BY LOWER(a.name), a.id
+	LIMIT CASE WHEN $1 < 0 THEN NULL ELSE $1 END OFFSET $2`, []any{limit, offset}, func(row pgx.CollectableRow) (model.Artist, error) {
+		data, err := pgx.RowToStructByName[artistRow](row)
+		return data.toModel(), err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list artists.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, total, err
+	}
+	return artists, total, nil
+}
+
+// UpdateArtist updates the artist in the database with artist.UUID.
+func (r *dbRepo) UpdateArtist(ctx context.Context, artist *model.Artist) error {
+	prepareArtist(artist)
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		previous, err := pgxutil.SelectRow(ctx, tx, `SELECT name FROM artists WHERE uuid = $1`, []any{artist.UUID}, pgx.RowTo[string])
+		if err != nil {
+			return err
+		}
+		id, err := pgxutil.UpdateRowReturning(ctx, tx, "artists", map[string]any{
+			"name": artist.Name,
+		}, map[string]any{
+			"uuid": artist.UUID,
+		}, "id", pgx.RowTo[int])
+		if err != nil {
+			return err
+		}
+		if err = setAliases(ctx, tx, id, artist); err != nil {
+			return err
+		}
+		if previous != artist.Name {
+			// Renaming an artist changes the data of all songs crediting the artist.
+			if err = touchSongs(ctx, tx, id); err != nil {
+				return err
+			}
+		}
+		*artist, err = getArtist(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		if err = dbutil.Error(err); !errors.Is(err, core.ErrNotFound) && !errors.Is(err, core.ErrConflict) {
+			r.logger.ErrorContext(ctx, "Could not update artist.", "uuid", artist.UUID, tint.Err(err))
+		}
+		return err
+	}
+	return nil
+}
+
+// MergeArtists merges source into target.
+// The links of source are moved to target, unless a song already credits target.
+func (r *dbRepo) MergeArtists(ctx context.Context, target *model.Artist, source uuid.UUID) error {
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		targetID, err := pgxutil.SelectRow(ctx, tx, `SELECT id FROM artists WHERE uuid = $1`, []any{target.UUID}, pgx.RowTo[int])
+		if err != nil {
+			return err
+		}
+		if target.UUID != source {
+			src, err := pgxutil.SelectRow(ctx, tx, `SELECT
+			a.id, a.name, ARRAY(SELECT al.name FROM artist_aliases AS al WHERE al.artist_id = a.id) AS aliases
+			FROM artists AS a
+			WHERE a.uuid = $1`, []any{source}, pgx.RowToStructByName[struct {
+				ID      int
+				Name    string
+				Aliases []string
+			}])
+			if err != nil {
+				return err
+			}
+			if _, err = tx.Exec(ctx, `INSERT INTO song_artists (song_id, artist_id, role, position)
+			SELECT sa.song_id, $1, sa.role, sa.position FROM song_artists AS sa WHERE sa.artist_id = $2
+			ON CONFLICT DO NOTHING`, targetID, src.ID); err != nil {
+				return err
+			}
+			// The artists of songs are part of their data, so their modification time has to change.
+			if err = touchSongs(ctx, tx, src.ID); err != nil {
+				return err
+			}
+			if _, err = tx.Exec(ctx, `DELETE FROM artists WHERE id = $1`, src.ID); err != nil {
+				return err
+			}
+			if _, err = tx.Exec(ctx, `INSERT INTO artist_aliases (artist_id, name)
+			SELECT $1, n.name FROM UNNEST($2::TEXT[]) AS n(name)
+			ON CONFLICT DO NOTHING`, targetID, append(src.Aliases, src.Name)); err != nil {
+				return err
+			}
+		}
+		*target, err = getArtist(ctx, tx, targetID)
+		return err
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not merge artists.", "uuid", target.UUID, "source", source, tint.Err(err))
+		}
+		return dbutil.Error(err)
+	}
+	return nil
+}
+
+// touchSongs sets the updated_at timestamp of all songs crediting the artist with the specified ID to the current time.
+// This invalidates the entity tags of the songs.
+func touchSongs(ctx context.Context, db pgxutil.DB, id int) error {
+	_, err := db.Exec(ctx, `UPDATE songs SET updated_at = NOW()
+	WHERE id IN (SELECT sa.song_id FROM song_artists AS sa WHERE sa.artist_id = $1)`, id)
+	return err
+}
+
+// getArtist fetches the artist with the specified ID using db.
+func getArtist(ctx context.Context, db pgxutil.DB, id int) (model.Artist, error) {
+	row, err := pgxutil.SelectRow(ctx, db, `SELECT `+artistColumns+`
+	FROM artists AS a
+	WHERE a.id = $1`, []any{id}, pgx.RowToStructByName[artistRow])
+	return row.toModel(), err
+}
+
+// setAliases replaces the aliases of the artist with the specified ID with artist.Aliases.
+// If the name or one of the aliases of artist is already used by another artist, core.ErrConflict is returned.
+func setAliases(ctx context.Context, db pgxutil.DB, id int, artist *model.Artist) error {
+	names := append([]string{artist.Name}, artist.Aliases...)
+	conflict, err := pgxutil.SelectRow(ctx, db, `SELECT
+		EXISTS(SELECT 1 FROM artists WHERE id <> $1 AND LOWER(name) IN (SELECT LOWER(n) FROM UNNEST($2::TEXT[]) AS n))
+		OR EXISTS(SELECT 1 FROM artist_aliases WHERE artist_id <> $1 AND LOWER(name) IN (SELECT LOWER(n) FROM UNNEST($2::TEXT[]) AS n))`,
+		[]any{id, names}, pgx.RowTo[bool])
+	if err != nil {
+		return err
+	}
+	if conflict {
+		return core.ErrConflict
+	}
+	if _, err = db.Exec(ctx, `DELETE FROM artist_aliases WHERE artist_id = $1`, id); err != nil {
+		return err
+	}
+	_, err = db.Exec(ctx, `INSERT INTO artist_aliases (artist_id, name)
+	SELECT $1, n FROM UNNEST($2::TEXT[]) AS n`, id, artist.Aliases)
+	return err
+}
+
+// prepareArtist normalizes the name and aliases of artist.
+// Whitespace is trimmed, empty aliases and aliases that only differ from another alias or the name in case are removed.
+func prepareArtist(artist *model.Artist) {
+	artist.Name = strings.TrimSpace(artist.Name)
+	aliases := make([]string, 0, len(artist.Aliases))
+	seen := map[string]bool{strings.ToLower(artist.Name): true}
+	for _, alias := range artist.Aliases {
+		alias = strings.TrimSpace(alias)
+		if alias == "" || seen[strings.ToLower(alias)] {
+			continue
+		}
+		seen[strings.ToLower(alias)] = true
+		aliases = append(aliases, alias)
+	}
+	artist.Aliases = aliases
+}
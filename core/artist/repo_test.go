@@ -0,0 +1,67 @@
+//go:build database
+
+package artist
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func Test_dbRepo_CreateArtist(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	testdata.Artist(t, db, "Queen", "The Queen")
+
+	t.Run("success", func(t *testing.T) {
+		artist := model.Artist{Name: "ABBA", Aliases: []string{"Björn & Benny"}}
+		if err := repo.CreateArtist(context.TODO(), &artist); err != nil {
+			t.Fatalf("CreateArtist(ctx, &artist) returned an unexpected error: %s", err)
+		}
+		if artist.UUID == uuid.Nil {
+			t.Errorf("CreateArtist(ctx, &artist) produced artist.UUID = <uuid.Nil>, expected a valid UUID")
+		}
+		if !slices.Equal(artist.Aliases, []string{"Björn & Benny"}) {
+			t.Errorf("CreateArtist(ctx, &artist) produced aliases %q, expected %q", artist.Aliases, []string{"Björn & Benny"})
+		}
+	})
+	t.Run("conflict", func(t *testing.T) {
+		for _, name := range []string{"QUEEN", "the queen"} {
+			artist := model.Artist{Name: name}
+			if err := repo.CreateArtist(context.TODO(), &artist); !errors.Is(err, core.ErrConflict) {
+				t.Errorf("CreateArtist(ctx, &artist) with name %q returned %v, expected ErrConflict", name, err)
+			}
+		}
+	})
+}
+
+func Test_dbRepo_GetArtistByName(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	queen := testdata.Artist(t, db, "Queen", "The Queen")
+
+	for _, name := range []string{"queen", "The Queen"} {
+		actual, err := repo.GetArtistByName(context.TODO(), name)
+		if err != nil {
+			t.Errorf("GetArtistByName(ctx, %q) returned an unexpected error: %s", name, err)
+		} else if actual.UUID != queen.UUID {
+			t.Errorf("GetArtistByName(ctx, %q) returned artist %q, expected %q", name, actual.UUID, queen.UUID)
+		}
+	}
+	if _, err := repo.GetArtistByName(context.TODO(), "ABBA"); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("GetArtistByName(ctx, %q) returned %v, expected ErrNotFound", "ABBA", err)
+	}
+}
@@ -0,0 +1,104 @@
+package batch
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// fakeRepo is a simple implementation of Repository that can be used for testing.
+type fakeRepo struct {
+	// batches is the "database" of a fakeRepo.
+	batches map[uuid.UUID]model.SongBatch
+	// songs contains the songs of each batch in the order they were added.
+	songs map[uuid.UUID][]uuid.UUID
+	// results contains the results of each batch in the order they were recorded.
+	results map[uuid.UUID][]model.SongBatchResult
+}
+
+// NewFakeRepository returns a new Repository implementation backed by in-memory maps.
+func NewFakeRepository() Repository {
+	return &fakeRepo{
+		make(map[uuid.UUID]model.SongBatch),
+		make(map[uuid.UUID][]uuid.UUID),
+		make(map[uuid.UUID][]model.SongBatchResult),
+	}
+}
+
+// CreateBatch stores the batch and sets its UUID, CreatedAt, UpdatedAt, State, and SongsTotal fields.
+func (r *fakeRepo) CreateBatch(_ context.Context, batch *model.SongBatch, songs []uuid.UUID) error {
+	batch.UUID = uuid.New()
+	batch.CreatedAt = time.Now()
+	batch.UpdatedAt = batch.CreatedAt
+	batch.State = model.BatchStatePending
+	batch.SongsTotal = len(songs)
+	batch.SongsProcessed = 0
+	batch.Errors = 0
+	r.batches[batch.UUID] = *batch
+	r.songs[batch.UUID] = slices.Clone(songs)
+	return nil
+}
+
+// GetBatch looks up the batch with the specified UUID.
+func (r *fakeRepo) GetBatch(_ context.Context, id uuid.UUID) (model.SongBatch, error) {
+	batch, ok := r.batches[id]
+	if !ok {
+		return model.SongBatch{}, core.ErrNotFound
+	}
+	return batch, nil
+}
+
+// UpdateBatch saves the state and progress of batch.
+func (r *fakeRepo) UpdateBatch(_ context.Context, batch *model.SongBatch) error {
+	existing, ok := r.batches[batch.UUID]
+	if !ok {
+		return core.ErrNotFound
+	}
+	existing.State = batch.State
+	existing.SongsProcessed = batch.SongsProcessed
+	existing.Errors = batch.Errors
+	existing.UpdatedAt = time.Now()
+	batch.UpdatedAt = existing.UpdatedAt
+	r.batches[batch.UUID] = existing
+	return nil
+}
+
+// FindPendingSongs returns the songs of the batch without a result.
+func (r *fakeRepo) FindPendingSongs(_ context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	songs := make([]uuid.UUID, 0, len(r.songs[id]))
+	for _, song := range r.songs[id] {
+		if !slices.ContainsFunc(r.results[id], func(result model.SongBatchResult) bool { return result.Song == song }) {
+			songs = append(songs, song)
+		}
+	}
+	return songs, nil
+}
+
+// SetResult records result for the batch with the specified UUID.
+func (r *fakeRepo) SetResult(_ context.Context, id uuid.UUID, result model.SongBatchResult) error {
+	if !slices.Contains(r.songs[id], result.Song) {
+		return core.ErrNotFound
+	}
+	r.results[id] = append(r.results[id], result)
+	return nil
+}
+
+// FindResults returns the results of the batch limited by the specified pagination parameters.
+// This implementation orders results by the time they were recorded.
+func (r *fakeRepo) FindResults(_ context.Context, id uuid.UUID, limit int, offset int64) ([]model.SongBatchResult, int64, error) {
+	results := r.results[id]
+	total := int64(len(results))
+	if offset >= total {
+		return []model.SongBatchResult{}, total, nil
+	}
+	end := total
+	if limit >= 0 && offset+int64(limit) < total {
+		end = offset + int64(limit)
+	}
+	return slices.Clone(results[offset:end]), total, nil
+}
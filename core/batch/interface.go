@@ -0,0 +1,63 @@
+package batch
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// MaxSyncSongs is the maximum number of songs of a batch that is processed immediately.
+// Larger batches are processed in the background.
+const MaxSyncSongs = 50
+
+// Service provides an interface for applying operations to many songs at once.
+type Service interface {
+	// StartBatch creates a new batch that applies batch.Operation to songs.
+	// Batches with at most MaxSyncSongs songs are run immediately,
+	// larger batches are scheduled to run in the background.
+	// This method sets the fields of batch to their current values.
+	StartBatch(ctx context.Context, batch *model.SongBatch, songs []uuid.UUID) error
+
+	// RunBatch applies the batch with the specified UUID to its songs.
+	// Songs that do not exist, are in the trash or belong to an upload are reported as errors.
+	// If a batch has been interrupted, only songs without a result are processed.
+	// Batches that are already done or failed are not run again.
+	//
+	// If no such batch exists, core.ErrNotFound is returned.
+	RunBatch(ctx context.Context, id uuid.UUID) error
+}
+
+// Repository provides methods for storing batches and their results.
+type Repository interface {
+	// CreateBatch creates a new batch for the specified songs.
+	// This method must set batch.UUID, batch.CreatedAt, batch.UpdatedAt, batch.State and batch.SongsTotal appropriately.
+	CreateBatch(ctx context.Context, batch *model.SongBatch, songs []uuid.UUID) error
+
+	// GetBatch fetches the batch with the specified UUID.
+	// If no such batch exists, core.ErrNotFound will be returned.
+	GetBatch(ctx context.Context, id uuid.UUID) (model.SongBatch, error)
+
+	// UpdateBatch saves the state and progress of the specified batch.
+	// The UUID of the batch must already exist in the database, otherwise core.ErrNotFound will be returned.
+	UpdateBatch(ctx context.Context, batch *model.SongBatch) error
+
+	// FindPendingSongs returns the UUIDs of the songs of the batch with the specified UUID that do not have a result yet.
+	// Songs are returned in the order they were added to the batch.
+	FindPendingSongs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+
+	// SetResult records the result for a song of the batch with the specified UUID.
+	SetResult(ctx context.Context, id uuid.UUID, result model.SongBatchResult) error
+
+	// FindResults returns a paginated view of the recorded results of the batch with the specified UUID.
+	// The second return value contains the total number of results.
+	FindResults(ctx context.Context, id uuid.UUID, limit int, offset int64) ([]model.SongBatchResult, int64, error)
+}
+
+// A Queue schedules batches to be run in the background.
+// Batches are processed asynchronously, usually by calling Service.RunBatch.
+type Queue interface {
+	// EnqueueBatch schedules the batch with the specified UUID.
+	EnqueueBatch(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,232 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgxutil"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/core/internal/dbutil"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// dbRepo is the main Repository implementation, backed by a PostgreSQL database.
+type dbRepo struct {
+	logger *slog.Logger
+	db     pgxutil.DB
+}
+
+// NewDBRepository creates a new Repository backed by the specified database connection.
+// db can be a single connection or a connection pool.
+func NewDBRepository(logger *slog.Logger, db pgxutil.DB) Repository {
+	return &dbRepo{logger, db}
+}
+
+// operationData is the JSON representation of a model.BatchOperation in the database.
+type operationData struct {
+	Op         model.BatchOp     `json:"op"`
+	Fields     fieldsData        `json:"fields,omitempty"`
+	CustomTags map[string]string `json:"customTags,omitempty"`
+	TagNames   []string          `json:"tagNames,omitempty"`
+	Transforms []string          `json:"transforms,omitempty"`
+}
+
+// fieldsData is the JSON representation of model.SongFields in the database.
+type fieldsData struct {
+	Genre    *string `json:"genre,omitempty"`
+	Edition  *string `json:"edition,omitempty"`
+	Language *string `json:"language,omitempty"`
+	Creator  *string `json:"creator,omitempty"`
+	Year     *int    `json:"year,omitempty"`
+}
+
+// batchRow is the data returned by a SELECT query for batches.
+type batchRow struct {
+	UUID           uuid.UUID
+	CreatedAt      time.Time        `db:"created_at"`
+	UpdatedAt      time.Time        `db:"updated_at"`
+	DeletedAt      pgtype.Timestamp `db:"deleted_at"`
+	State          model.BatchState
+	Operation      operationData
+	Atomic         bool
+	Author         string
+	SongsTotal     int `db:"songs_total"`
+	SongsProcessed int `db:"songs_processed"`
+	Errors         int
+}
+
+// batchColumns are the columns selected into a batchRow.
+const batchColumns = `uuid, created_at, updated_at, deleted_at, state, operation, atomic, author, songs_total, songs_processed, errors`
+
+// toModel converts r to an equivalent model.SongBatch.
+func (r batchRow) toModel() model.SongBatch {
+	batch := model.SongBatch{
+		Model: model.Model{
+			UUID:      r.UUID,
+			CreatedAt: r.CreatedAt,
+			UpdatedAt: r.UpdatedAt,
+		},
+		State: r.State,
+		Operation: model.BatchOperation{
+			Op: r.Operation.Op,
+			Fields: model.SongFields{
+				Genre:    r.Operation.Fields.Genre,
+				Edition:  r.Operation.Fields.Edition,
+				Language: r.Operation.Fields.Language,
+				Creator:  r.Operation.Fields.Creator,
+				Year:     r.Operation.Fields.Year,
+			},
+			CustomTags: r.Operation.CustomTags,
+			TagNames:   r.Operation.TagNames,
+			Transforms: r.Operation.Transforms,
+		},
+		Atomic:         r.Atomic,
+		Author:         r.Author,
+		SongsTotal:     r.SongsTotal,
+		SongsProcessed: r.SongsProcessed,
+		Errors:         r.Errors,
+	}
+	if r.DeletedAt.Valid {
+		batch.DeletedAt = r.DeletedAt.Time
+	}
+	return batch
+}
+
+// fromOperation converts op into its database representation.
+func fromOperation(op model.BatchOperation) operationData {
+	return operationData{
+		Op: op.Op,
+		Fields: fieldsData{
+			Genre:    op.Fields.Genre,
+			Edition:  op.Fields.Edition,
+			Language: op.Fields.Language,
+			Creator:  op.Fields.Creator,
+			Year:     op.Fields.Year,
+		},
+		CustomTags: op.CustomTags,
+		TagNames:   op.TagNames,
+		Transforms: op.Transforms,
+	}
+}
+
+// CreateBatch creates a new batch and its songs in the database.
+func (r *dbRepo) CreateBatch(ctx context.Context, batch *model.SongBatch, songs []uuid.UUID) error {
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		id, err := pgxutil.InsertRowReturning(ctx, tx, "song_batches", map[string]any{
+			"operation":   fromOperation(batch.Operation),
+			"atomic":      batch.Atomic,
+			"author":      batch.Author,
+			"songs_total": len(songs),
+		}, "id", pgx.RowTo[int])
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec(ctx, `INSERT INTO song_batch_songs (batch_id, song)
+		SELECT $1, song FROM UNNEST($2::UUID[]) WITH ORDINALITY AS t(song, n) ORDER BY n`, id, songs); err != nil {
+			return err
+		}
+		row, err := pgxutil.SelectRow(ctx, tx, `SELECT `+batchColumns+`
+		FROM song_batches
+		WHERE id = $1`, []any{id}, pgx.RowToStructByName[batchRow])
+		if err != nil {
+			return err
+		}
+		*batch = row.toModel()
+		return nil
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not create batch.", "op", batch.Operation.Op, "songs", len(songs), tint.Err(err))
+		return dbutil.Error(err)
+	}
+	return nil
+}
+
+// GetBatch fetches a batch from the database.
+func (r *dbRepo) GetBatch(ctx context.Context, id uuid.UUID) (model.SongBatch, error) {
+	row, err := pgxutil.SelectRow(ctx, r.db, `SELECT `+batchColumns+`
+	FROM song_batches
+	WHERE uuid = $1`, []any{id}, pgx.RowToStructByName[batchRow])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not fetch batch.", "uuid", id, tint.Err(err))
+		}
+		return model.SongBatch{}, dbutil.Error(err)
+	}
+	return row.toModel(), nil
+}
+
+// UpdateBatch updates the state and progress of the batch in the database with batch.UUID.
+func (r *dbRepo) UpdateBatch(ctx context.Context, batch *model.SongBatch) error {
+	updatedAt, err := pgxutil.UpdateRowReturning(ctx, r.db, "song_batches", map[string]any{
+		"state":           batch.State,
+		"songs_processed": batch.SongsProcessed,
+		"errors":          batch.Errors,
+	}, map[string]any{
+		"uuid": batch.UUID,
+	}, "updated_at", pgx.RowTo[time.Time])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not update batch.", "uuid", batch.UUID, tint.Err(err))
+		}
+		return dbutil.Error(err)
+	}
+	batch.UpdatedAt = updatedAt
+	return nil
+}
+
+// FindPendingSongs lists the songs of a batch that have not been processed yet.
+func (r *dbRepo) FindPendingSongs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	songs, err := pgxutil.Select(ctx, r.db, `SELECT s.song
+	FROM song_batch_songs s
+	INNER JOIN song_batches b ON s.batch_id = b.id
+	WHERE b.uuid = $1 AND NOT s.processed
+	ORDER BY s.id`, []any{id}, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list pending batch songs.", "uuid", id, tint.Err(err))
+		return nil, err
+	}
+	return songs, nil
+}
+
+// SetResult records the result for a song of a batch.
+func (r *dbRepo) SetResult(ctx context.Context, id uuid.UUID, result model.SongBatchResult) error {
+	_, err := pgxutil.ExecRow(ctx, r.db, `UPDATE song_batch_songs
+	SET processed = TRUE, error = $3
+	WHERE batch_id = (SELECT id FROM song_batches WHERE uuid = $1) AND song = $2`, id, result.Song, result.Error)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not record batch result.", "uuid", id, "song", result.Song, tint.Err(err))
+		}
+		return dbutil.Error(err)
+	}
+	return nil
+}
+
+// FindResults lists the results of a batch with pagination.
+func (r *dbRepo) FindResults(ctx context.Context, id uuid.UUID, limit int, offset int64) ([]model.SongBatchResult, int64, error) {
+	total, err := pgxutil.SelectRow(ctx, r.db, `SELECT COUNT(*)
+	FROM song_batch_songs s
+	INNER JOIN song_batches b ON s.batch_id = b.id
+	WHERE b.uuid = $1 AND s.processed`, []any{id}, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not count batch results.", "uuid", id, "limit", limit, "offset", offset, tint.Err(err))
+		return nil, 0, err
+	}
+	results, err := pgxutil.Select(ctx, r.db, `SELECT s.song, s.error
+	FROM song_batch_songs s
+	INNER JOIN song_batches b ON s.batch_id = b.id
+	WHERE b.uuid = $1 AND s.processed
+	ORDER BY s.id
+	LIMIT CASE WHEN $2 < 0 THEN NULL ELSE $2 END OFFSET $3`, []any{id, limit, offset}, pgx.RowToStructByName[model.SongBatchResult])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list batch results.", "uuid", id, "limit", limit, "offset", offset, tint.Err(err))
+		return nil, total, err
+	}
+	return results, total, nil
+}
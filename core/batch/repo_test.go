@@ -0,0 +1,134 @@
+//go:build database
+
+package batch
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+)
+
+// createBatch creates a batch for songs in repo.
+func createBatch(t *testing.T, repo Repository, songs []uuid.UUID) model.SongBatch {
+	genre := "Rock"
+	batch := model.SongBatch{
+		Operation: model.BatchOperation{Op: model.BatchOpSet, Fields: model.SongFields{Genre: &genre}},
+		Atomic:    true,
+		Author:    "Tester",
+	}
+	if err := repo.CreateBatch(context.TODO(), &batch, songs); err != nil {
+		t.Fatalf("CreateBatch(ctx, &batch, songs) returned an unexpected error: %s", err)
+	}
+	return batch
+}
+
+func Test_dbRepo_CreateBatch(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	batch := createBatch(t, repo, []uuid.UUID{uuid.New(), uuid.New()})
+
+	if batch.UUID == uuid.Nil {
+		t.Errorf("CreateBatch(ctx, &batch, songs) produced batch.UUID = <uuid.Nil>, expected a valid UUID")
+	}
+	if batch.State != model.BatchStatePending {
+		t.Errorf("CreateBatch(ctx, &batch, songs) produced batch.State = %q, expected %q", batch.State, model.BatchStatePending)
+	}
+	if batch.SongsTotal != 2 {
+		t.Errorf("CreateBatch(ctx, &batch, songs) produced batch.SongsTotal = %d, expected %d", batch.SongsTotal, 2)
+	}
+	if batch.Operation.Fields.Genre == nil || *batch.Operation.Fields.Genre != "Rock" {
+		t.Errorf("CreateBatch(ctx, &batch, songs) did not store the genre of the operation")
+	}
+}
+
+func Test_dbRepo_GetBatch(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	expected := createBatch(t, repo, []uuid.UUID{uuid.New()})
+
+	t.Run("existing", func(t *testing.T) {
+		batch, err := repo.GetBatch(context.TODO(), expected.UUID)
+		if err != nil {
+			t.Fatalf("GetBatch(ctx, %q) returned an unexpected error: %s", expected.UUID, err)
+		}
+		if batch.Operation.Op != model.BatchOpSet || !batch.Atomic || batch.Author != "Tester" {
+			t.Errorf("GetBatch(ctx, %q) = %v, expected %v", expected.UUID, batch, expected)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		id := uuid.New()
+		_, err := repo.GetBatch(context.TODO(), id)
+		if !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("GetBatch(ctx, %q) returned an unexpected error: %s, expected %s", id, err, core.ErrNotFound)
+		}
+	})
+}
+
+func Test_dbRepo_UpdateBatch(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	batch := createBatch(t, repo, []uuid.UUID{uuid.New()})
+
+	batch.State = model.BatchStateDone
+	batch.SongsProcessed = 1
+	batch.Errors = 1
+	if err := repo.UpdateBatch(context.TODO(), &batch); err != nil {
+		t.Fatalf("UpdateBatch(ctx, &batch) returned an unexpected error: %s", err)
+	}
+	actual, _ := repo.GetBatch(context.TODO(), batch.UUID)
+	if actual.State != model.BatchStateDone || actual.SongsProcessed != 1 || actual.Errors != 1 {
+		t.Errorf("UpdateBatch(ctx, &batch) stored %v, expected %v", actual, batch)
+	}
+
+	missing := model.SongBatch{Model: model.Model{UUID: uuid.New()}}
+	if err := repo.UpdateBatch(context.TODO(), &missing); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("UpdateBatch(ctx, &missing) returned an unexpected error: %s, expected %s", err, core.ErrNotFound)
+	}
+}
+
+func Test_dbRepo_SetResult(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	songs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	batch := createBatch(t, repo, songs)
+
+	if err := repo.SetResult(context.TODO(), batch.UUID, model.SongBatchResult{Song: songs[1], Error: "failed"}); err != nil {
+		t.Fatalf("SetResult(ctx, %q, result) returned an unexpected error: %s", batch.UUID, err)
+	}
+	if err := repo.SetResult(context.TODO(), batch.UUID, model.SongBatchResult{Song: uuid.New()}); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("SetResult(ctx, %q, result) returned an unexpected error: %s, expected %s", batch.UUID, err, core.ErrNotFound)
+	}
+
+	pending, err := repo.FindPendingSongs(context.TODO(), batch.UUID)
+	if err != nil {
+		t.Fatalf("FindPendingSongs(ctx, %q) returned an unexpected error: %s", batch.UUID, err)
+	}
+	if expected := []uuid.UUID{songs[0], songs[2]}; !slices.Equal(pending, expected) {
+		t.Errorf("FindPendingSongs(ctx, %q) = %v, expected %v", batch.UUID, pending, expected)
+	}
+
+	results, total, err := repo.FindResults(context.TODO(), batch.UUID, 10, 0)
+	if err != nil {
+		t.Fatalf("FindResults(ctx, %q, 10, 0) returned an unexpected error: %s", batch.UUID, err)
+	}
+	if total != 1 || len(results) != 1 || results[0].Song != songs[1] || results[0].Error != "failed" {
+		t.Errorf("FindResults(ctx, %q, 10, 0) = %v, %d, expected a single failed result", batch.UUID, results, total)
+	}
+}
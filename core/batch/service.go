@@ -0,0 +1,339 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/revision"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// service is the main Service implementation.
+type service struct {
+	logger       *slog.Logger
+	repo         Repository
+	songRepo     song.Repository
+	songService  song.Service
+	revisionRepo revision.Repository
+	events       event.Bus
+	queue        Queue
+	// fixers are applied to songs by model.BatchOpFix.
+	fixers []song.Transform
+}
+
+// NewService creates a new Service instance using the specified dependencies.
+// Batches with more than MaxSyncSongs songs are scheduled via queue.
+// If queue is nil, all batches are run immediately.
+// The fixers are the transforms applied by model.BatchOpFix, usually the import fixers of uploads.
+func NewService(
+	logger *slog.Logger,
+	repo Repository,
+	songRepo song.Repository,
+	songService song.Service,
+	revisionRepo revision.Repository,
+	events event.Bus,
+	queue Queue,
+	fixers []song.Transform,
+) Service {
+	return &service{logger, repo, songRepo, songService, revisionRepo, events, queue, fixers}
+}
+
+// StartBatch creates the batch and runs or schedules it.
+// Duplicate songs are only included once.
+func (s *service) StartBatch(ctx context.Context, batch *model.SongBatch, songs []uuid.UUID) error {
+	unique := make([]uuid.UUID, 0, len(songs))
+	seen := make(map[uuid.UUID]bool, len(songs))
+	for _, id := range songs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	if err := s.repo.CreateBatch(ctx, batch, unique); err != nil {
+		return err
+	}
+	if s.queue != nil && len(unique) > MaxSyncSongs {
+		return s.queue.EnqueueBatch(ctx, batch.UUID)
+	}
+	if err := s.RunBatch(ctx, batch.UUID); err != nil {
+		return err
+	}
+	b, err := s.repo.GetBatch(ctx, batch.UUID)
+	if err != nil {
+		return err
+	}
+	*batch = b
+	return nil
+}
+
+// RunBatch applies the batch to its pending songs.
+func (s *service) RunBatch(ctx context.Context, id uuid.UUID) error {
+	batch, err := s.repo.GetBatch(ctx, id)
+	if err != nil {
+		return err
+	}
+	if batch.State == model.BatchStateDone || batch.State == model.BatchStateFailed {
+		return nil
+	}
+	transforms, err := s.transforms(batch.Operation)
+	if err != nil {
+		return err
+	}
+	songs, err := s.repo.FindPendingSongs(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.logger.InfoContext(ctx, "Beginning to run batch.", "uuid", id, "op", batch.Operation.Op, "songs", len(songs))
+	batch.State = model.BatchStateRunning
+	if err = s.repo.UpdateBatch(ctx, &batch); err != nil {
+		return err
+	}
+
+	if batch.Atomic {
+		err = s.runAtomic(ctx, &batch, songs, transforms)
+	} else {
+		err = s.run(ctx, &batch, songs, transforms)
+	}
+	if err != nil {
+		return err
+	}
+	if batch.State == model.BatchStateRunning {
+		batch.State = model.BatchStateDone
+	}
+	if err = s.repo.UpdateBatch(ctx, &batch); err != nil {
+		return err
+	}
+	s.logger.InfoContext(ctx, "Finished running batch.", "uuid", id, "state", batch.State, "errors", batch.Errors)
+	return nil
+}
+
+// errModified is the result message for songs that were modified while a batch was running.
+const errModified = "song was modified while the batch was running"
+
+// run applies batch to each song independently.
+// A song is only changed if it has not been modified since it was prepared.
+func (s *service) run(ctx context.Context, batch *model.SongBatch, songs []uuid.UUID, transforms []song.Transform) error {
+	for _, id := range songs {
+		sng, msg, err := s.prepare(ctx, batch.Operation, id, transforms)
+		if err != nil {
+			return err
+		}
+		if msg == "" {
+			err = s.apply(ctx, *batch, sng)
+			if errors.Is(err, core.ErrPreconditionFailed) {
+				msg = errModified
+			} else if err != nil {
+				return err
+			}
+		}
+		if err = s.record(ctx, batch, model.SongBatchResult{Song: id, Error: msg}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runAtomic applies batch to songs only if it can be applied to every song.
+// Otherwise, the batch fails and results are only recorded for the songs that could not be changed.
+// The changes are saved in a single transaction.
+// If any song has been modified since it was prepared, no song is changed and the batch fails.
+func (s *service) runAtomic(ctx context.Context, batch *model.SongBatch, songs []uuid.UUID, transforms []song.Transform) error {
+	prepared := make([]model.Song, 0, len(songs))
+	for _, id := range songs {
+		sng, msg, err := s.prepare(ctx, batch.Operation, id, transforms)
+		if err != nil {
+			return err
+		}
+		if msg != "" {
+			if err = s.record(ctx, batch, model.SongBatchResult{Song: id, Error: msg}); err != nil {
+				return err
+			}
+			continue
+		}
+		prepared = append(prepared, sng)
+	}
+	if batch.Errors > 0 {
+		batch.State = model.BatchStateFailed
+		batch.SongsProcessed = batch.SongsTotal
+		return nil
+	}
+	versions := make(map[uuid.UUID]time.Time, len(prepared))
+	for _, sng := range prepared {
+		versions[sng.UUID] = sng.UpdatedAt
+	}
+	var err error
+	if batch.Operation.Op == model.BatchOpDelete {
+		err = s.songRepo.DeleteSongsIfUnmodified(ctx, versions)
+	} else {
+		err = s.songRepo.UpdateSongsIfUnmodified(ctx, prepared, versions)
+	}
+	if errors.Is(err, core.ErrPreconditionFailed) {
+		// The repository does not report which song was modified, so the error is recorded for every song.
+		for _, sng := range prepared {
+			if err = s.record(ctx, batch, model.SongBatchResult{Song: sng.UUID, Error: errModified}); err != nil {
+				return err
+			}
+		}
+		batch.State = model.BatchStateFailed
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, sng := range prepared {
+		s.applied(ctx, *batch, sng)
+		if err = s.record(ctx, batch, model.SongBatchResult{Song: sng.UUID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// transforms returns the timing transforms applied by op.
+func (s *service) transforms(op model.BatchOperation) ([]song.Transform, error) {
+	switch op.Op {
+	case model.BatchOpFix:
+		return s.fixers, nil
+	case model.BatchOpTransform:
+		ts := make([]song.Transform, len(op.Transforms))
+		for i, text := range op.Transforms {
+			t, err := song.ParseTransform(text)
+			if err != nil {
+				return nil, fmt.Errorf("parsing transform %d: %w", i, err)
+			}
+			ts[i] = t
+		}
+		return ts, nil
+	default:
+		return nil, nil
+	}
+}
+
+// prepare fetches the song with the specified UUID and applies op to it without persisting the changes.
+// If op cannot be applied to the song, the second return value describes why.
+// The error is only set for unexpected errors that should abort the batch.
+func (s *service) prepare(ctx context.Context, op model.BatchOperation, id uuid.UUID, transforms []song.Transform) (model.Song, string, error) {
+	sng, err := s.songRepo.GetSong(ctx, id)
+	if errors.Is(err, core.ErrNotFound) || (err == nil && sng.Deleted()) {
+		return sng, "song not found", nil
+	} else if err != nil {
+		return sng, "", err
+	}
+	if sng.InUpload {
+		return sng, "song belongs to an upload and cannot be modified", nil
+	}
+	switch op.Op {
+	case model.BatchOpSet:
+		setFields(&sng, op.Fields)
+	case model.BatchOpAddCustomTags:
+		if sng.CustomTags == nil {
+			sng.CustomTags = make(map[string]string, len(op.CustomTags))
+		}
+		for name, value := range op.CustomTags {
+			sng.CustomTags[name] = value
+		}
+	case model.BatchOpRemoveCustomTags:
+		for _, name := range op.TagNames {
+			delete(sng.CustomTags, name)
+		}
+	case model.BatchOpAddTags:
+		sng.Tags = append(sng.Tags, op.TagNames...)
+	case model.BatchOpRemoveTags:
+		sng.Tags = slices.DeleteFunc(sng.Tags, func(tag string) bool {
+			return slices.ContainsFunc(op.TagNames, func(name string) bool {
+				return strings.EqualFold(strings.TrimSpace(name), tag)
+			})
+		})
+	case model.BatchOpDelete:
+	case model.BatchOpTransform, model.BatchOpFix:
+		var transformErr *song.TransformError
+		if err = s.songService.Transform(ctx, &sng, transforms); errors.As(err, &transformErr) {
+			return sng, err.Error(), nil
+		} else if err != nil {
+			return sng, "", err
+		}
+	default:
+		return sng, "", fmt.Errorf("unknown batch operation %q", op.Op)
+	}
+	return sng, "", nil
+}
+
+// setFields sets the non-nil fields of f in sng.
+func setFields(sng *model.Song, f model.SongFields) {
+	if f.Genre != nil {
+		sng.Genre = *f.Genre
+	}
+	if f.Edition != nil {
+		sng.Edition = *f.Edition
+	}
+	if f.Language != nil {
+		sng.Language = *f.Language
+	}
+	if f.Creator != nil {
+		sng.Creator = *f.Creator
+	}
+	if f.Year != nil {
+		sng.Year = *f.Year
+	}
+}
+
+// apply persists the changes that batch made to sng.
+// The changes are only saved if the song has not been modified since it was fetched.
+// Otherwise, core.ErrPreconditionFailed is returned.
+func (s *service) apply(ctx context.Context, batch model.SongBatch, sng model.Song) error {
+	var err error
+	if batch.Operation.Op == model.BatchOpDelete {
+		err = s.songRepo.DeleteSongIfUnmodified(ctx, sng.UUID, sng.UpdatedAt)
+	} else {
+		err = s.songRepo.UpdateSongIfUnmodified(ctx, &sng, sng.UpdatedAt)
+	}
+	if err != nil {
+		return err
+	}
+	s.applied(ctx, batch, sng)
+	return nil
+}
+
+// applied records the side effects of saving the changes that batch made to sng.
+// Updated songs get a new revision authored by the author of batch.
+func (s *service) applied(ctx context.Context, batch model.SongBatch, sng model.Song) {
+	if batch.Operation.Op == model.BatchOpDelete {
+		s.publish(ctx, event.SongDeleted(sng.UUID))
+		return
+	}
+	rev := model.NewSongRevision(sng, batch.Author)
+	if err := s.revisionRepo.CreateRevision(ctx, sng.UUID, &rev); err != nil {
+		s.logger.ErrorContext(ctx, "Could not record song revision.", "uuid", sng.UUID, tint.Err(err))
+	}
+	s.publish(ctx, event.SongUpdated(sng))
+}
+
+// record saves result and updates the progress of batch.
+func (s *service) record(ctx context.Context, batch *model.SongBatch, result model.SongBatchResult) error {
+	if err := s.repo.SetResult(ctx, batch.UUID, result); err != nil {
+		return err
+	}
+	batch.SongsProcessed++
+	if result.Error != "" {
+		batch.Errors++
+	}
+	return s.repo.UpdateBatch(ctx, batch)
+}
+
+// publish sends e to the event bus.
+// Events are informational only, so a failure to publish an event does not fail the batch.
+func (s *service) publish(ctx context.Context, e event.Event) {
+	if err := s.events.Publish(ctx, e); err != nil {
+		s.logger.WarnContext(ctx, "Could not publish event.", "type", e.Type, tint.Err(err))
+	}
+}
@@ -0,0 +1,231 @@
+package batch
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"codello.dev/ultrastar"
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core/artist"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/revision"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+)
+
+// fakeQueue is a Queue that records enqueued batches.
+type fakeQueue struct {
+	batches []uuid.UUID
+}
+
+// EnqueueBatch records id.
+func (q *fakeQueue) EnqueueBatch(_ context.Context, id uuid.UUID) error {
+	q.batches = append(q.batches, id)
+	return nil
+}
+
+// modifyingRepo is a song.Repository that modifies a song right before songs are updated conditionally.
+// It simulates a concurrent edit while a batch is running.
+type modifyingRepo struct {
+	song.Repository
+	id uuid.UUID
+}
+
+// UpdateSongsIfUnmodified modifies the song r.id and then updates songs.
+func (r modifyingRepo) UpdateSongsIfUnmodified(ctx context.Context, songs []model.Song, versions map[uuid.UUID]time.Time) error {
+	sng, _ := r.GetSong(ctx, r.id)
+	sng.Genre = "Rock"
+	_ = r.UpdateSong(ctx, &sng)
+	return r.Repository.UpdateSongsIfUnmodified(ctx, songs, versions)
+}
+
+// setupService creates a service backed by fake repositories.
+func setupService(queue Queue) (*service, Repository, song.Repository) {
+	repo := NewFakeRepository()
+	songRepo := song.NewFakeRepository()
+	songSvc := song.NewService(artist.NewFakeRepository(), song.DefaultNaming)
+	svc := NewService(nolog.Logger, repo, songRepo, songSvc, revision.NewFakeRepository(), event.NewMemBus(), queue, nil)
+	return svc.(*service), repo, songRepo
+}
+
+// createSong creates a song with the specified BPM in songRepo.
+func createSong(t *testing.T, songRepo song.Repository, bpm ultrastar.BPM) model.Song {
+	sng := model.Song{Song: ultrastar.Song{Title: "Song", Artist: "Artist", BPM: bpm, Genre: "Pop"}}
+	if err := songRepo.CreateSong(context.TODO(), &sng); err != nil {
+		t.Fatalf("CreateSong() returned an unexpected error: %s", err)
+	}
+	return sng
+}
+
+func Test_service_StartBatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("set", func(t *testing.T) {
+		svc, repo, songRepo := setupService(nil)
+		a, b := createSong(t, songRepo, 120), createSong(t, songRepo, 120)
+		genre, year := "", 1999
+		batch := model.SongBatch{Operation: model.BatchOperation{
+			Op:     model.BatchOpSet,
+			Fields: model.SongFields{Genre: &genre, Year: &year},
+		}}
+		if err := svc.StartBatch(context.TODO(), &batch, []uuid.UUID{a.UUID, b.UUID, a.UUID}); err != nil {
+			t.Fatalf("StartBatch() returned an unexpected error: %s", err)
+		}
+		if batch.State != model.BatchStateDone {
+			t.Errorf("StartBatch() produced batch.State = %q, expected %q", batch.State, model.BatchStateDone)
+		}
+		if batch.SongsTotal != 2 || batch.SongsProcessed != 2 {
+			t.Errorf("StartBatch() processed %d of %d songs, expected 2 of 2", batch.SongsProcessed, batch.SongsTotal)
+		}
+		for _, id := range []uuid.UUID{a.UUID, b.UUID} {
+			sng, _ := songRepo.GetSong(context.TODO(), id)
+			if sng.Genre != "" || sng.Year != 1999 {
+				t.Errorf("StartBatch() set genre %q and year %d, expected %q and %d", sng.Genre, sng.Year, "", 1999)
+			}
+		}
+		results, total, _ := repo.FindResults(context.TODO(), batch.UUID, -1, 0)
+		if total != 2 || results[0].Error != "" || results[1].Error != "" {
+			t.Errorf("StartBatch() recorded results %v, expected 2 successful results", results)
+		}
+	})
+
+	t.Run("tags", func(t *testing.T) {
+		svc, _, songRepo := setupService(nil)
+		sng := createSong(t, songRepo, 120)
+		sng.Tags = []string{"christmas", "needs-video"}
+		_ = songRepo.UpdateSong(context.TODO(), &sng)
+		batch := model.SongBatch{Operation: model.BatchOperation{Op: model.BatchOpRemoveTags, TagNames: []string{"Needs-Video"}}}
+		if err := svc.StartBatch(context.TODO(), &batch, []uuid.UUID{sng.UUID}); err != nil {
+			t.Fatalf("StartBatch() returned an unexpected error: %s", err)
+		}
+		batch = model.SongBatch{Operation: model.BatchOperation{Op: model.BatchOpAddTags, TagNames: []string{"party-starter"}}}
+		if err := svc.StartBatch(context.TODO(), &batch, []uuid.UUID{sng.UUID}); err != nil {
+			t.Fatalf("StartBatch() returned an unexpected error: %s", err)
+		}
+		if sng, _ = songRepo.GetSong(context.TODO(), sng.UUID); !slices.Equal(sng.Tags, []string{"christmas", "party-starter"}) {
+			t.Errorf("StartBatch() produced tags %q, expected %q", sng.Tags, []string{"christmas", "party-starter"})
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		svc, repo, songRepo := setupService(nil)
+		valid, invalid := createSong(t, songRepo, 120), createSong(t, songRepo, 0)
+		missing := uuid.New()
+		batch := model.SongBatch{Operation: model.BatchOperation{Op: model.BatchOpTransform, Transforms: []string{"doubleBPM"}}}
+		if err := svc.StartBatch(context.TODO(), &batch, []uuid.UUID{valid.UUID, invalid.UUID, missing}); err != nil {
+			t.Fatalf("StartBatch() returned an unexpected error: %s", err)
+		}
+		if batch.State != model.BatchStateDone || batch.Errors != 2 {
+			t.Errorf("StartBatch() produced state %q with %d errors, expected %q with 2 errors", batch.State, batch.Errors, model.BatchStateDone)
+		}
+		if sng, _ := songRepo.GetSong(context.TODO(), valid.UUID); sng.BPM != 240 {
+			t.Errorf("StartBatch() set BPM %v, expected %v", sng.BPM, 240)
+		}
+		results, _, _ := repo.FindResults(context.TODO(), batch.UUID, -1, 0)
+		if len(results) != 3 || results[2].Error != "song not found" {
+			t.Errorf("StartBatch() recorded results %v, expected an error for the missing song", results)
+		}
+	})
+
+	t.Run("atomic", func(t *testing.T) {
+		svc, repo, songRepo := setupService(nil)
+		valid, invalid := createSong(t, songRepo, 120), createSong(t, songRepo, 0)
+		batch := model.SongBatch{Atomic: true, Operation: model.BatchOperation{Op: model.BatchOpTransform, Transforms: []string{"doubleBPM"}}}
+		if err := svc.StartBatch(context.TODO(), &batch, []uuid.UUID{valid.UUID, invalid.UUID}); err != nil {
+			t.Fatalf("StartBatch() returned an unexpected error: %s", err)
+		}
+		if batch.State != model.BatchStateFailed {
+			t.Errorf("StartBatch() produced batch.State = %q, expected %q", batch.State, model.BatchStateFailed)
+		}
+		if sng, _ := songRepo.GetSong(context.TODO(), valid.UUID); sng.BPM != 120 {
+			t.Errorf("StartBatch() modified a song of a failed atomic batch, BPM = %v, expected %v", sng.BPM, 120)
+		}
+		results, _, _ := repo.FindResults(context.TODO(), batch.UUID, -1, 0)
+		if len(results) != 1 || results[0].Song != invalid.UUID {
+			t.Errorf("StartBatch() recorded results %v, expected a single error for %s", results, invalid.UUID)
+		}
+	})
+
+	t.Run("atomic modified", func(t *testing.T) {
+		svc, repo, songRepo := setupService(nil)
+		a, b := createSong(t, songRepo, 120), createSong(t, songRepo, 120)
+		svc.songRepo = modifyingRepo{songRepo, b.UUID}
+		year := 1999
+		batch := model.SongBatch{Atomic: true, Operation: model.BatchOperation{Op: model.BatchOpSet, Fields: model.SongFields{Year: &year}}}
+		if err := svc.StartBatch(context.TODO(), &batch, []uuid.UUID{a.UUID, b.UUID}); err != nil {
+			t.Fatalf("StartBatch() returned an unexpected error: %s", err)
+		}
+		if batch.State != model.BatchStateFailed {
+			t.Errorf("StartBatch() produced batch.State = %q, expected %q", batch.State, model.BatchStateFailed)
+		}
+		if sng, _ := songRepo.GetSong(context.TODO(), a.UUID); sng.Year != 0 {
+			t.Errorf("StartBatch() modified a song of a failed atomic batch, Year = %d, expected 0", sng.Year)
+		}
+		if sng, _ := songRepo.GetSong(context.TODO(), b.UUID); sng.Genre != "Rock" || sng.Year != 0 {
+			t.Errorf("StartBatch() overwrote a concurrent edit, got genre %q and year %d, expected %q and 0", sng.Genre, sng.Year, "Rock")
+		}
+		if _, total, _ := repo.FindResults(context.TODO(), batch.UUID, -1, 0); total != 2 {
+			t.Errorf("StartBatch() recorded %d results, expected 2", total)
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		svc, _, songRepo := setupService(nil)
+		sng := createSong(t, songRepo, 120)
+		batch := model.SongBatch{Operation: model.BatchOperation{Op: model.BatchOpDelete}}
+		if err := svc.StartBatch(context.TODO(), &batch, []uuid.UUID{sng.UUID}); err != nil {
+			t.Fatalf("StartBatch() returned an unexpected error: %s", err)
+		}
+		if sng, _ = songRepo.GetSong(context.TODO(), sng.UUID); !sng.Deleted() {
+			t.Errorf("StartBatch() did not move the song to the trash")
+		}
+	})
+
+	t.Run("custom tags", func(t *testing.T) {
+		svc, _, songRepo := setupService(nil)
+		sng := createSong(t, songRepo, 120)
+		batch := model.SongBatch{Operation: model.BatchOperation{Op: model.BatchOpAddCustomTags, CustomTags: map[string]string{"ALBUM": "Foo"}}}
+		if err := svc.StartBatch(context.TODO(), &batch, []uuid.UUID{sng.UUID}); err != nil {
+			t.Fatalf("StartBatch() returned an unexpected error: %s", err)
+		}
+		if sng, _ = songRepo.GetSong(context.TODO(), sng.UUID); sng.CustomTags["ALBUM"] != "Foo" {
+			t.Errorf("StartBatch() set custom tags %v, expected ALBUM=Foo", sng.CustomTags)
+		}
+		batch = model.SongBatch{Operation: model.BatchOperation{Op: model.BatchOpRemoveCustomTags, TagNames: []string{"ALBUM"}}}
+		if err := svc.StartBatch(context.TODO(), &batch, []uuid.UUID{sng.UUID}); err != nil {
+			t.Fatalf("StartBatch() returned an unexpected error: %s", err)
+		}
+		if sng, _ = songRepo.GetSong(context.TODO(), sng.UUID); len(sng.CustomTags) != 0 {
+			t.Errorf("StartBatch() kept custom tags %v, expected none", sng.CustomTags)
+		}
+	})
+
+	t.Run("queue", func(t *testing.T) {
+		queue := &fakeQueue{}
+		svc, _, songRepo := setupService(queue)
+		songs := make([]uuid.UUID, MaxSyncSongs+1)
+		for i := range songs {
+			songs[i] = createSong(t, songRepo, 120).UUID
+		}
+		batch := model.SongBatch{Operation: model.BatchOperation{Op: model.BatchOpDelete}}
+		if err := svc.StartBatch(context.TODO(), &batch, songs); err != nil {
+			t.Fatalf("StartBatch() returned an unexpected error: %s", err)
+		}
+		if batch.State != model.BatchStatePending {
+			t.Errorf("StartBatch() produced batch.State = %q, expected %q", batch.State, model.BatchStatePending)
+		}
+		if len(queue.batches) != 1 || queue.batches[0] != batch.UUID {
+			t.Errorf("StartBatch() enqueued %v, expected [%s]", queue.batches, batch.UUID)
+		}
+		if err := svc.RunBatch(context.TODO(), batch.UUID); err != nil {
+			t.Fatalf("RunBatch() returned an unexpected error: %s", err)
+		}
+		if sng, _ := songRepo.GetSong(context.TODO(), songs[0]); !sng.Deleted() {
+			t.Errorf("RunBatch() did not move the song to the trash")
+		}
+	})
+}
@@ -0,0 +1,277 @@
+package duplicate
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core/internal/textmatch"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// Thresholds for the detection of duplicates by their lyrics.
+// Two songs are duplicates if the durations of their audio files differ by at most maxDurationDifference
+// and the sets of words in their lyrics have a Jaccard similarity of at least minLyricsSimilarity.
+// Lyrics with less than minLyricsWords different words are not compared.
+const (
+	maxDurationDifference = 2 * time.Second
+	minLyricsSimilarity   = 0.8
+	minLyricsWords        = 10
+)
+
+// A Match is a song that duplicates another song.
+type Match struct {
+	// Song is the UUID of the duplicate.
+	Song uuid.UUID
+	// Reasons are the reasons why the songs are considered duplicates.
+	Reasons []model.DuplicateReason
+}
+
+// A Detector finds duplicates among a set of songs.
+// Songs are duplicates if any of the following is true:
+//   - They have the same title and share an artist, compared as in textmatch.Normalize.
+//   - Their audio files have the same checksum.
+//   - Their audio files have a similar duration and their lyrics contain mostly the same words.
+type Detector struct {
+	// songs contains the fingerprints sorted by the duration of their audio file.
+	songs []entry
+	// titles maps title keys to indexes into songs.
+	titles map[string][]int
+	// audio maps audio checksums to indexes into songs.
+	audio map[string][]int
+	// position maps song UUIDs to their position in the input of NewDetector.
+	position map[uuid.UUID]int
+}
+
+// entry is a fingerprint that has been prepared for comparison.
+type entry struct {
+	Fingerprint
+	// index is the position of the fingerprint in the input of NewDetector.
+	index int
+	// titles are the title keys of the fingerprint.
+	titles []string
+	// words is the set of words in the lyrics.
+	// If the lyrics are not suitable for comparison, words is nil.
+	words map[string]struct{}
+}
+
+// NewDetector creates a Detector for the songs with the fingerprints fps.
+func NewDetector(fps []Fingerprint) *Detector {
+	d := &Detector{
+		songs:    make([]entry, len(fps)),
+		titles:   make(map[string][]int),
+		audio:    make(map[string][]int),
+		position: make(map[uuid.UUID]int, len(fps)),
+	}
+	for i, fp := range fps {
+		d.songs[i] = newEntry(fp, i)
+		d.position[fp.Song] = i
+	}
+	slices.SortStableFunc(d.songs, func(a, b entry) int {
+		return cmp.Compare(a.Duration, b.Duration)
+	})
+	for i, e := range d.songs {
+		for _, key := range e.titles {
+			d.titles[key] = append(d.titles[key], i)
+		}
+		if len(e.Checksum) > 0 {
+			d.audio[string(e.Checksum)] = append(d.audio[string(e.Checksum)], i)
+		}
+	}
+	return d
+}
+
+// newEntry prepares fp for comparison.
+func newEntry(fp Fingerprint, index int) entry {
+	e := entry{Fingerprint: fp, index: index}
+	if title := textmatch.Normalize(fp.Title); title != "" {
+		for _, artist := range fp.Artists {
+			if artist = textmatch.Normalize(artist); artist != "" && !slices.Contains(e.titles, artist+"\x00"+title) {
+				e.titles = append(e.titles, artist+"\x00"+title)
+			}
+		}
+	}
+	if fp.Duration > 0 {
+		e.words = words(fp.Lyrics)
+		if len(e.words) < minLyricsWords {
+			e.words = nil
+		}
+	}
+	return e
+}
+
+// words returns the set of words in s, ignoring case and punctuation.
+func words(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	}) {
+		if w = strings.Trim(w, "'"); w != "" {
+			set[w] = struct{}{}
+		}
+	}
+	return set
+}
+
+// similarLyrics reports whether the word sets a and b are similar enough to consider their songs duplicates.
+func similarLyrics(a, b map[string]struct{}) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	common := 0
+	for w := range a {
+		if _, ok := b[w]; ok {
+			common++
+		}
+	}
+	return float64(common)/float64(len(a)+len(b)-common) >= minLyricsSimilarity
+}
+
+// window returns the range of indexes into d.songs whose duration differs from duration
+// by at most maxDurationDifference.
+func (d *Detector) window(duration time.Duration) (int, int) {
+	start, _ := slices.BinarySearchFunc(d.songs, duration-maxDurationDifference, func(e entry, t time.Duration) int {
+		return cmp.Compare(e.Duration, t)
+	})
+	end, _ := slices.BinarySearchFunc(d.songs, duration+maxDurationDifference+1, func(e entry, t time.Duration) int {
+		return cmp.Compare(e.Duration, t)
+	})
+	return start, end
+}
+
+// Clusters groups the songs of d into clusters of duplicates.
+// Songs without duplicates are not included in any cluster.
+// Clusters and the songs within a cluster are ordered by their position in the input of NewDetector.
+func (d *Detector) Clusters() []model.DuplicateCluster {
+	parent := make([]int, len(d.songs))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	reasons := make(map[int]map[model.DuplicateReason]bool)
+	union := func(i, j int, reason model.DuplicateReason) {
+		ri, rj := find(i), find(j)
+		if ri != rj {
+			parent[rj] = ri
+			for r := range reasons[rj] {
+				if reasons[ri] == nil {
+					reasons[ri] = make(map[model.DuplicateReason]bool)
+				}
+				reasons[ri][r] = true
+			}
+			delete(reasons, rj)
+		}
+		if reasons[ri] == nil {
+			reasons[ri] = make(map[model.DuplicateReason]bool)
+		}
+		reasons[ri][reason] = true
+	}
+
+	for _, group := range d.titles {
+		for _, j := range group[1:] {
+			union(group[0], j, model.DuplicateReasonTitle)
+		}
+	}
+	for _, group := range d.audio {
+		for _, j := range group[1:] {
+			union(group[0], j, model.DuplicateReasonAudio)
+		}
+	}
+	for i, a := range d.songs {
+		if a.words == nil {
+			continue
+		}
+		for j := i + 1; j < len(d.songs) && d.songs[j].Duration-a.Duration <= maxDurationDifference; j++ {
+			if similarLyrics(a.words, d.songs[j].words) {
+				union(i, j, model.DuplicateReasonLyrics)
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	for i := range d.songs {
+		if r := find(i); reasons[r] != nil {
+			members[r] = append(members[r], i)
+		}
+	}
+	clusters := make([]model.DuplicateCluster, 0, len(members))
+	for r, ms := range members {
+		slices.SortFunc(ms, func(a, b int) int { return cmp.Compare(d.songs[a].index, d.songs[b].index) })
+		c := model.DuplicateCluster{Songs: make([]uuid.UUID, len(ms)), Reasons: sortedReasons(reasons[r])}
+		for k, i := range ms {
+			c.Songs[k] = d.songs[i].Song
+		}
+		clusters = append(clusters, c)
+	}
+	slices.SortFunc(clusters, func(a, b model.DuplicateCluster) int {
+		return cmp.Compare(d.position[a.Songs[0]], d.position[b.Songs[0]])
+	})
+	return clusters
+}
+
+// Find returns the songs of d that duplicate the song with the fingerprint fp.
+// A song with the same UUID as fp is not considered a duplicate.
+// Matches are ordered by their position in the input of NewDetector.
+func (d *Detector) Find(fp Fingerprint) []Match {
+	e := newEntry(fp, -1)
+	reasons := make(map[int]map[model.DuplicateReason]bool)
+	add := func(i int, reason model.DuplicateReason) {
+		if d.songs[i].Song == fp.Song {
+			return
+		}
+		if reasons[i] == nil {
+			reasons[i] = make(map[model.DuplicateReason]bool)
+		}
+		reasons[i][reason] = true
+	}
+	for _, key := range e.titles {
+		for _, i := range d.titles[key] {
+			add(i, model.DuplicateReasonTitle)
+		}
+	}
+	if len(e.Checksum) > 0 {
+		for _, i := range d.audio[string(e.Checksum)] {
+			add(i, model.DuplicateReasonAudio)
+		}
+	}
+	if e.words != nil {
+		start, end := d.window(e.Duration)
+		for i := start; i < end; i++ {
+			if similarLyrics(e.words, d.songs[i].words) {
+				add(i, model.DuplicateReasonLyrics)
+			}
+		}
+	}
+
+	indexes := make([]int, 0, len(reasons))
+	for i := range reasons {
+		indexes = append(indexes, i)
+	}
+	slices.SortFunc(indexes, func(a, b int) int { return cmp.Compare(d.songs[a].index, d.songs[b].index) })
+	matches := make([]Match, len(indexes))
+	for k, i := range indexes {
+		matches[k] = Match{Song: d.songs[i].Song, Reasons: sortedReasons(reasons[i])}
+	}
+	return matches
+}
+
+// sortedReasons returns the reasons in set in a fixed order.
+func sortedReasons(set map[model.DuplicateReason]bool) []model.DuplicateReason {
+	reasons := make([]model.DuplicateReason, 0, len(set))
+	for _, r := range []model.DuplicateReason{model.DuplicateReasonTitle, model.DuplicateReasonAudio, model.DuplicateReasonLyrics} {
+		if set[r] {
+			reasons = append(reasons, r)
+		}
+	}
+	return reasons
+}
@@ -0,0 +1,103 @@
+package duplicate
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// lyrics are song lyrics with enough words to be compared.
+const lyrics = `Is this the real life? Is this just fantasy?
+Caught in a landslide, no escape from reality
+Open your eyes, look up to the skies and see`
+
+func TestDetector_Clusters(t *testing.T) {
+	fps := []Fingerprint{
+		{Song: uuid.New(), Artists: []string{"Queen"}, Title: "Bohemian Rhapsody"},
+		{Song: uuid.New(), Artists: []string{"ABBA"}, Title: "Waterloo", Checksum: []byte{1, 2, 3}},
+		{Song: uuid.New(), Artists: []string{"Queen", "David Bowie"}, Title: "Bohemian Rhapsody (Remastered 2011)"},
+		{Song: uuid.New(), Artists: []string{"Unknown"}, Title: "Something", Checksum: []byte{1, 2, 3}},
+		{Song: uuid.New(), Artists: []string{"Queen"}, Title: "Untitled", Duration: 354 * time.Second, Lyrics: lyrics},
+		{Song: uuid.New(), Artists: []string{"Other"}, Title: "Other", Duration: 355 * time.Second, Lyrics: lyrics + "\nI'm just a poor boy"},
+		{Song: uuid.New(), Artists: []string{"Queen"}, Title: "Short Lyrics", Duration: 355 * time.Second, Lyrics: "La la la"},
+		{Song: uuid.New(), Artists: []string{"Queen"}, Title: "Too Long", Duration: 370 * time.Second, Lyrics: lyrics},
+		{Song: uuid.New(), Artists: []string{"Queen"}, Title: "No Audio", Lyrics: lyrics},
+	}
+	expected := []model.DuplicateCluster{
+		{Songs: []uuid.UUID{fps[0].Song, fps[2].Song}, Reasons: []model.DuplicateReason{model.DuplicateReasonTitle}},
+		{Songs: []uuid.UUID{fps[1].Song, fps[3].Song}, Reasons: []model.DuplicateReason{model.DuplicateReasonAudio}},
+		{Songs: []uuid.UUID{fps[4].Song, fps[5].Song}, Reasons: []model.DuplicateReason{model.DuplicateReasonLyrics}},
+	}
+
+	actual := NewDetector(fps).Clusters()
+	if len(actual) != len(expected) {
+		t.Fatalf("Clusters() returned %d clusters, expected %d", len(actual), len(expected))
+	}
+	for i, c := range expected {
+		if !slices.Equal(actual[i].Songs, c.Songs) {
+			t.Errorf("Clusters()[%d].Songs = %v, expected %v", i, actual[i].Songs, c.Songs)
+		}
+		if !slices.Equal(actual[i].Reasons, c.Reasons) {
+			t.Errorf("Clusters()[%d].Reasons = %v, expected %v", i, actual[i].Reasons, c.Reasons)
+		}
+	}
+}
+
+func TestDetector_Clusters_transitive(t *testing.T) {
+	fps := []Fingerprint{
+		{Song: uuid.New(), Artists: []string{"Queen"}, Title: "Bohemian Rhapsody"},
+		{Song: uuid.New(), Artists: []string{"QUEEN"}, Title: "Bohemian Rhapsody", Checksum: []byte{1}},
+		{Song: uuid.New(), Artists: []string{"Unknown"}, Title: "Unknown", Checksum: []byte{1}},
+	}
+	actual := NewDetector(fps).Clusters()
+	if len(actual) != 1 {
+		t.Fatalf("Clusters() returned %d clusters, expected 1", len(actual))
+	}
+	if expected := []uuid.UUID{fps[0].Song, fps[1].Song, fps[2].Song}; !slices.Equal(actual[0].Songs, expected) {
+		t.Errorf("Clusters()[0].Songs = %v, expected %v", actual[0].Songs, expected)
+	}
+	if expected := []model.DuplicateReason{model.DuplicateReasonTitle, model.DuplicateReasonAudio}; !slices.Equal(actual[0].Reasons, expected) {
+		t.Errorf("Clusters()[0].Reasons = %v, expected %v", actual[0].Reasons, expected)
+	}
+}
+
+func TestDetector_Find(t *testing.T) {
+	fps := []Fingerprint{
+		{Song: uuid.New(), Artists: []string{"Queen"}, Title: "Bohemian Rhapsody", Checksum: []byte{1}, Duration: 354 * time.Second, Lyrics: lyrics},
+		{Song: uuid.New(), Artists: []string{"ABBA"}, Title: "Waterloo"},
+		{Song: uuid.New(), Artists: []string{"Queen"}, Title: "Bohemian Rhapsody (Live)"},
+	}
+	d := NewDetector(fps)
+
+	cases := map[string]struct {
+		fp       Fingerprint
+		expected []Match
+	}{
+		"none": {Fingerprint{Song: uuid.New(), Artists: []string{"ABBA"}, Title: "Mamma Mia"}, []Match{}},
+		"title": {Fingerprint{Song: uuid.New(), Artists: []string{"Abba"}, Title: "Waterloo!"}, []Match{
+			{fps[1].Song, []model.DuplicateReason{model.DuplicateReasonTitle}},
+		}},
+		"all": {Fingerprint{Song: uuid.New(), Artists: []string{"Queen"}, Title: "Bohemian Rhapsody", Checksum: []byte{1}, Duration: 355 * time.Second, Lyrics: lyrics}, []Match{
+			{fps[0].Song, []model.DuplicateReason{model.DuplicateReasonTitle, model.DuplicateReasonAudio, model.DuplicateReasonLyrics}},
+			{fps[2].Song, []model.DuplicateReason{model.DuplicateReasonTitle}},
+		}},
+		"self": {fps[1], []Match{}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual := d.Find(c.fp)
+			if len(actual) != len(c.expected) {
+				t.Fatalf("Find(%v) returned %d matches, expected %d", c.fp, len(actual), len(c.expected))
+			}
+			for i, m := range c.expected {
+				if actual[i].Song != m.Song || !slices.Equal(actual[i].Reasons, m.Reasons) {
+					t.Errorf("Find(%v)[%d] = %v, expected %v", c.fp, i, actual[i], m)
+				}
+			}
+		})
+	}
+}
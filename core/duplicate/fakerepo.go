@@ -0,0 +1,68 @@
+package duplicate
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// fakeRepo is a simple implementation of Repository that can be used for testing.
+// Fingerprints are computed from the songs in a song repository.
+type fakeRepo struct {
+	songRepo song.Repository
+}
+
+// NewFakeRepository returns a new Repository implementation that computes fingerprints from the songs in songRepo.
+func NewFakeRepository(songRepo song.Repository) Repository {
+	return &fakeRepo{songRepo}
+}
+
+// FindFingerprints returns the fingerprints of all songs in the library.
+// Because the fake song repository does not track uploads, songs in uploads are included.
+func (r *fakeRepo) FindFingerprints(ctx context.Context) ([]Fingerprint, error) {
+	songs, _, err := r.songRepo.FindSongs(ctx, song.Filter{}, -1, 0)
+	if err != nil {
+		return nil, err
+	}
+	fps := make([]Fingerprint, 0, len(songs))
+	for _, s := range songs {
+		if s.InUpload {
+			continue
+		}
+		fps = append(fps, fingerprint(s))
+	}
+	return fps, nil
+}
+
+// GetFingerprint returns the fingerprint of the song with the specified UUID.
+func (r *fakeRepo) GetFingerprint(ctx context.Context, id uuid.UUID) (Fingerprint, error) {
+	s, err := r.songRepo.GetSong(ctx, id)
+	if err != nil {
+		return Fingerprint{}, err
+	}
+	return fingerprint(s), nil
+}
+
+// fingerprint computes the fingerprint of s.
+func fingerprint(s model.Song) Fingerprint {
+	fp := Fingerprint{
+		Song:    s.UUID,
+		Artists: s.Artists,
+		Title:   s.Title,
+	}
+	if s.AudioFile != nil {
+		fp.Checksum = s.AudioFile.Checksum
+		fp.Duration = s.AudioFile.Duration
+	}
+	lines := song.Lyrics(s, s.NotesP1)
+	texts := make([]string, len(lines))
+	for i, l := range lines {
+		texts[i] = l.Text
+	}
+	fp.Lyrics = strings.Join(texts, "\n")
+	return fp
+}
@@ -0,0 +1,51 @@
+package duplicate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func Test_fakeRepo_FindFingerprints(t *testing.T) {
+	t.Parallel()
+
+	songRepo := song.NewFakeRepository()
+	repo := NewFakeRepository(songRepo)
+	s := model.Song{
+		Artists:   []string{"Queen"},
+		AudioFile: &model.File{Checksum: []byte{1, 2}, Duration: time.Minute},
+	}
+	s.Title = "Bohemian Rhapsody"
+	upload := model.Song{InUpload: true}
+	_ = songRepo.CreateSong(context.TODO(), &s)
+	_ = songRepo.CreateSong(context.TODO(), &upload)
+
+	fps, err := repo.FindFingerprints(context.TODO())
+	if err != nil {
+		t.Fatalf("FindFingerprints(ctx) returned an unexpected error: %s", err)
+	}
+	if len(fps) != 1 {
+		t.Fatalf("FindFingerprints(ctx) returned %d fingerprints, expected 1", len(fps))
+	}
+	if fps[0].Song != s.UUID || fps[0].Title != s.Title || fps[0].Duration != time.Minute || len(fps[0].Checksum) != 2 {
+		t.Errorf("FindFingerprints(ctx) returned %v, expected the fingerprint of %q", fps[0], s.UUID)
+	}
+
+	fp, err := repo.GetFingerprint(context.TODO(), upload.UUID)
+	if err != nil {
+		t.Errorf("GetFingerprint(ctx, %q) returned an unexpected error: %s", upload.UUID, err)
+	}
+	if fp.Song != upload.UUID {
+		t.Errorf("GetFingerprint(ctx, %q) returned the fingerprint of %q", upload.UUID, fp.Song)
+	}
+	if _, err = repo.GetFingerprint(context.TODO(), uuid.New()); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("GetFingerprint(ctx, uuid.New()) returned %v, expected ErrNotFound", err)
+	}
+}
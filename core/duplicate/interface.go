@@ -0,0 +1,38 @@
+package duplicate
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// A Fingerprint contains the data of a song that is used to detect duplicates.
+type Fingerprint struct {
+	// Song is the UUID of the song.
+	Song uuid.UUID
+
+	Artists []string
+	Title   string
+
+	// Checksum and Duration describe the audio file of the song.
+	// If the song has no audio file, Checksum is nil and Duration is 0.
+	Checksum []byte
+	Duration time.Duration
+
+	// Lyrics contains the lyrics of the first player.
+	Lyrics string
+}
+
+// A Repository provides the data required to detect duplicate songs.
+type Repository interface {
+	// FindFingerprints returns the fingerprints of all songs in the library.
+	// Songs in uploads or in the trash are not included.
+	// Fingerprints are returned in the order in which the songs were created.
+	FindFingerprints(ctx context.Context) ([]Fingerprint, error)
+
+	// GetFingerprint returns the fingerprint of the song with the specified UUID.
+	// Songs in uploads or in the trash are included.
+	// If no such song exists, core.ErrNotFound is returned.
+	GetFingerprint(ctx context.Context, id uuid.UUID) (Fingerprint, error)
+}
@@ -0,0 +1,84 @@
+package duplicate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// ErrInvalidMerge indicates that a Merge refers to media of a song that is not part of the merge.
+var ErrInvalidMerge = errors.New("invalid merge")
+
+// A Merge combines duplicate songs into a single song.
+type Merge struct {
+	// Song is the song that is kept.
+	// The TXT data of Song is not modified.
+	Song model.Song
+	// Duplicates are the songs that are removed.
+	Duplicates []model.Song
+
+	// Audio, Cover, Video and Background select the songs whose media files are kept.
+	// Each value must be the UUID of Song or of one of Duplicates.
+	// The value uuid.Nil keeps the respective media file of Song.
+	Audio      uuid.UUID
+	Cover      uuid.UUID
+	Video      uuid.UUID
+	Background uuid.UUID
+
+	// Purge indicates that Duplicates are deleted permanently instead of being moved to the trash.
+	Purge bool
+}
+
+// song returns the song of m with the specified UUID.
+// If id is uuid.Nil, m.Song is returned.
+func (m *Merge) song(id uuid.UUID) (model.Song, error) {
+	if id == uuid.Nil || id == m.Song.UUID {
+		return m.Song, nil
+	}
+	for _, d := range m.Duplicates {
+		if d.UUID == id {
+			return d, nil
+		}
+	}
+	return model.Song{}, fmt.Errorf("%w: song %s is not merged", ErrInvalidMerge, id)
+}
+
+// Apply performs the merge using songRepo.
+// The media files of m.Song are replaced and the duplicates are removed in a single transaction.
+// References to the duplicates (such as playlist entries or highscores) are moved to m.Song.
+// If m.Song has been modified in the repository since it was fetched, no changes are made and core.ErrPreconditionFailed is returned.
+// The updated song is returned.
+// If a media file is selected from a song that is not part of the merge, an error wrapping ErrInvalidMerge is returned.
+func (m *Merge) Apply(ctx context.Context, songRepo song.Repository) (model.Song, error) {
+	kept := m.Song
+	audio, err := m.song(m.Audio)
+	if err != nil {
+		return model.Song{}, err
+	}
+	cover, err := m.song(m.Cover)
+	if err != nil {
+		return model.Song{}, err
+	}
+	video, err := m.song(m.Video)
+	if err != nil {
+		return model.Song{}, err
+	}
+	background, err := m.song(m.Background)
+	if err != nil {
+		return model.Song{}, err
+	}
+	kept.AudioFile, kept.CoverFile, kept.VideoFile, kept.BackgroundFile = audio.AudioFile, cover.CoverFile, video.VideoFile, background.BackgroundFile
+	duplicates := make([]uuid.UUID, len(m.Duplicates))
+	for i, d := range m.Duplicates {
+		duplicates[i] = d.UUID
+	}
+	if err = songRepo.MergeSongs(ctx, &kept, duplicates, m.Purge, m.Song.UpdatedAt); err != nil {
+		return model.Song{}, err
+	}
+	return kept, nil
+}
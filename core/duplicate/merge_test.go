@@ -0,0 +1,99 @@
+package duplicate
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func TestMerge_Apply(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) (song.Repository, model.Song, model.Song) {
+		repo := song.NewFakeRepository()
+		keep := model.Song{CoverFile: &model.File{Model: model.Model{UUID: uuid.New()}}}
+		keep.Title = "Kept"
+		other := model.Song{
+			AudioFile: &model.File{Model: model.Model{UUID: uuid.New()}},
+			CoverFile: &model.File{Model: model.Model{UUID: uuid.New()}},
+		}
+		other.Title = "Duplicate"
+		for _, s := range []*model.Song{&keep, &other} {
+			if err := repo.CreateSong(context.TODO(), s); err != nil {
+				t.Fatalf("CreateSong(ctx, &song) returned an unexpected error: %s", err)
+			}
+		}
+		return repo, keep, other
+	}
+
+	t.Run("trash", func(t *testing.T) {
+		repo, keep, other := setup(t)
+		m := Merge{Song: keep, Duplicates: []model.Song{other}, Audio: other.UUID}
+		merged, err := m.Apply(context.TODO(), repo)
+		if err != nil {
+			t.Fatalf("Apply(ctx, repo) returned an unexpected error: %s", err)
+		}
+		if merged.Title != keep.Title {
+			t.Errorf("Apply(ctx, repo) returned a song with title %q, expected %q", merged.Title, keep.Title)
+		}
+		if merged.AudioFile == nil || merged.AudioFile.UUID != other.AudioFile.UUID {
+			t.Errorf("Apply(ctx, repo) did not keep the audio file of the duplicate")
+		}
+		if merged.CoverFile == nil || merged.CoverFile.UUID != keep.CoverFile.UUID {
+			t.Errorf("Apply(ctx, repo) did not keep the cover file of the song")
+		}
+		deleted, err := repo.GetSong(context.TODO(), other.UUID)
+		if err != nil {
+			t.Fatalf("GetSong(ctx, %q) returned an unexpected error: %s", other.UUID, err)
+		}
+		if !deleted.Deleted() {
+			t.Errorf("Apply(ctx, repo) did not move the duplicate to the trash")
+		}
+	})
+
+	t.Run("purge", func(t *testing.T) {
+		repo, keep, other := setup(t)
+		m := Merge{Song: keep, Duplicates: []model.Song{other}, Purge: true}
+		if _, err := m.Apply(context.TODO(), repo); err != nil {
+			t.Fatalf("Apply(ctx, repo) returned an unexpected error: %s", err)
+		}
+		if _, err := repo.GetSong(context.TODO(), other.UUID); !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("GetSong(ctx, %q) after Apply() returned %v, expected ErrNotFound", other.UUID, err)
+		}
+	})
+
+	t.Run("modified", func(t *testing.T) {
+		repo, keep, other := setup(t)
+		m := Merge{Song: keep, Duplicates: []model.Song{other}}
+		updated := keep
+		updated.Title = "Changed"
+		time.Sleep(time.Millisecond)
+		if err := repo.UpdateSong(context.TODO(), &updated); err != nil {
+			t.Fatalf("UpdateSong(ctx, &song) returned an unexpected error: %s", err)
+		}
+		if _, err := m.Apply(context.TODO(), repo); !errors.Is(err, core.ErrPreconditionFailed) {
+			t.Errorf("Apply(ctx, repo) returned %v, expected ErrPreconditionFailed", err)
+		}
+		if s, _ := repo.GetSong(context.TODO(), other.UUID); s.Deleted() {
+			t.Errorf("Apply(ctx, repo) deleted the duplicate of a failed merge")
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		repo, keep, other := setup(t)
+		m := Merge{Song: keep, Duplicates: []model.Song{other}, Video: uuid.New()}
+		if _, err := m.Apply(context.TODO(), repo); !errors.Is(err, ErrInvalidMerge) {
+			t.Errorf("Apply(ctx, repo) returned %v, expected ErrInvalidMerge", err)
+		}
+		if s, _ := repo.GetSong(context.TODO(), other.UUID); s.Deleted() {
+			t.Errorf("Apply(ctx, repo) deleted the duplicate of an invalid merge")
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package duplicate
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/core/internal/dbutil"
+)
+
+// dbRepo is the main Repository implementation, backed by a PostgreSQL database.
+type dbRepo struct {
+	logger *slog.Logger
+	db     pgxutil.DB // database connection
+}
+
+// NewDBRepository creates a new Repository backed by the specified database connection.
+// db can be a single connection or a connection pool.
+func NewDBRepository(logger *slog.Logger, db pgxutil.DB) Repository {
+	return &dbRepo{logger, db}
+}
+
+// fingerprintQuery selects the fingerprint of songs s.
+// The artists are the canonical names of the main artists of a song.
+const fingerprintQuery = `SELECT s.uuid AS song, s.title, COALESCE(s.lyrics_p1, '') AS lyrics,
+    ARRAY(SELECT ar.name FROM song_artists AS sa JOIN artists AS ar ON sa.artist_id = ar.id
+        WHERE sa.song_id = s.id AND sa.role = 'main' ORDER BY sa.position) AS artists,
+    a.checksum, a.duration
+FROM songs AS s
+    LEFT OUTER JOIN files AS a ON s.audio_file_id = a.id`
+
+// fingerprintRow is the data returned by fingerprintQuery.
+type fingerprintRow struct {
+	Song     uuid.UUID
+	Title    string
+	Lyrics   string
+	Artists  []string
+	Checksum []byte
+	Duration *time.Duration
+}
+
+// toModel converts r into an equivalent Fingerprint.
+func (r fingerprintRow) toModel() Fingerprint {
+	return Fingerprint{
+		Song:     r.Song,
+		Artists:  r.Artists,
+		Title:    r.Title,
+		Checksum: r.Checksum,
+		Duration: dbutil.ZeroNil(r.Duration),
+		Lyrics:   r.Lyrics,
+	}
+}
+
+// FindFingerprints fetches the fingerprints of all songs that are neither in an upload nor in the trash.
+func (r *dbRepo) FindFingerprints(ctx context.Context) ([]Fingerprint, error) {
+	fps, err := pgxutil.Select(ctx, r.db, fingerprintQuery+`
+	WHERE s.upload_id IS NULL AND s.deleted_at IS NULL
+	ORDER BY s.id`, nil, func(row pgx.CollectableRow) (Fingerprint, error) {
+		data, err := pgx.RowToStructByName[fingerprintRow](row)
+		return data.toModel(), err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list song fingerprints.", tint.Err(err))
+		return nil, err
+	}
+	return fps, nil
+}
+
+// GetFingerprint fetches the fingerprint of the song with the specified UUID.
+func (r *dbRepo) GetFingerprint(ctx context.Context, id uuid.UUID) (Fingerprint, error) {
+	row, err := pgxutil.SelectRow(ctx, r.db, fingerprintQuery+`
+	WHERE s.uuid = $1`, []any{id}, pgx.RowToStructByName[fingerprintRow])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not fetch song fingerprint.", "uuid", id, tint.Err(err))
+		}
+		return Fingerprint{}, dbutil.Error(err)
+	}
+	return row.toModel(), nil
+}
@@ -0,0 +1,70 @@
+//go:build database
+
+package duplicate
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func Test_dbRepo_FindFingerprints(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song := testdata.SimpleSong(t, db)
+	withAudio := testdata.SongWithAudio(t, db)
+	testdata.DeletedSong(t, db)
+	testdata.SongWithUpload(t, db)
+
+	fps, err := repo.FindFingerprints(context.TODO())
+	if err != nil {
+		t.Fatalf("FindFingerprints(ctx) returned an unexpected error: %s", err)
+	}
+	if len(fps) != 2 {
+		t.Fatalf("FindFingerprints(ctx) returned %d fingerprints, expected 2", len(fps))
+	}
+	if fps[0].Song != song.UUID || fps[0].Title != song.Title || !slices.Equal(fps[0].Artists, song.Artists) {
+		t.Errorf("FindFingerprints(ctx)[0] = %v, expected the fingerprint of %q", fps[0], song.UUID)
+	}
+	if fps[0].Checksum != nil || fps[0].Duration != 0 {
+		t.Errorf("FindFingerprints(ctx)[0] has audio data, expected none")
+	}
+	if fps[1].Song != withAudio.UUID || fps[1].Duration != withAudio.AudioFile.Duration {
+		t.Errorf("FindFingerprints(ctx)[1] = %v, expected the fingerprint of %q with duration %s", fps[1], withAudio.UUID, withAudio.AudioFile.Duration)
+	}
+}
+
+func Test_dbRepo_GetFingerprint(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song := testdata.SongWithUpload(t, db)
+
+	t.Run("found", func(t *testing.T) {
+		fp, err := repo.GetFingerprint(context.TODO(), song.UUID)
+		if err != nil {
+			t.Fatalf("GetFingerprint(ctx, %q) returned an unexpected error: %s", song.UUID, err)
+		}
+		if fp.Song != song.UUID || fp.Title != song.Title {
+			t.Errorf("GetFingerprint(ctx, %q) = %v, expected the fingerprint of the song", song.UUID, fp)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		id := uuid.New()
+		if _, err := repo.GetFingerprint(context.TODO(), id); !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("GetFingerprint(ctx, %q) returned %v, expected ErrNotFound", id, err)
+		}
+	})
+}
@@ -6,3 +6,10 @@ import (
 
 // ErrNotFound indicates that the requested entity was not found.
 var ErrNotFound = errors.New("not found")
+
+// ErrConflict indicates that an operation could not be performed because it conflicts with an existing entity.
+var ErrConflict = errors.New("conflict")
+
+// ErrPreconditionFailed indicates that an entity has been modified since it was last read,
+// so a conditional operation on the entity was not performed.
+var ErrPreconditionFailed = errors.New("precondition failed")
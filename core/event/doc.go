@@ -0,0 +1,5 @@
+// Package event implements an event bus for Karman.
+// Other parts of the application publish events (such as the progress of upload processing or changes to songs)
+// and interested parties can subscribe to them.
+// The primary consumer of events is the live event stream of the API.
+package event
@@ -0,0 +1,359 @@
+package event
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// Type identifies the kind of event.
+type Type string
+
+const (
+	// TypeUploadProgress indicates that the processing state of an upload has changed.
+	// The event data is an UploadProgressData value.
+	TypeUploadProgress Type = "upload.progress"
+
+	// TypeUploadDone indicates that processing of an upload has finished.
+	// The event data is an UploadProgressData value.
+	TypeUploadDone Type = "upload.done"
+
+	// TypeUploadError indicates that a processing error occurred for an upload.
+	// The event data is an UploadErrorData value.
+	TypeUploadError Type = "upload.error"
+
+	// TypeSongCreated indicates that a new song has been created.
+	// The event data is a SongData value.
+	TypeSongCreated Type = "song.created"
+
+	// TypeSongUpdated indicates that a song has been modified.
+	// The event data is a SongData value.
+	TypeSongUpdated Type = "song.updated"
+
+	// TypeSongDeleted indicates that a song has been moved to the trash.
+	// The event data is a SongData value.
+	TypeSongDeleted Type = "song.deleted"
+
+	// TypeSongRestored indicates that a song has been restored from the trash.
+	// The event data is a SongData value.
+	TypeSongRestored Type = "song.restored"
+
+	// TypeMediaCreated indicates that a new media file has been stored.
+	// The event data is a MediaData value.
+	TypeMediaCreated Type = "media.created"
+
+	// TypeMediaDeleted indicates that a media file has been deleted.
+	// The event data is a MediaData value.
+	TypeMediaDeleted Type = "media.deleted"
+
+	// TypeSessionUpdated indicates that a party session has been modified.
+	// The event data is a SessionData value.
+	TypeSessionUpdated Type = "session.updated"
+
+	// TypeSessionDeleted indicates that a party session has been deleted.
+	// The event data is a SessionData value.
+	TypeSessionDeleted Type = "session.deleted"
+
+	// TypeSessionQueue indicates that the queue of a party session has changed.
+	// This includes new requests, requests whose state has changed, removed requests and a new order of the queue.
+	// The event data is a SessionQueueData value.
+	TypeSessionQueue Type = "session.queue"
+
+	// TypeGuestSessionUpdated indicates that a party session has been modified.
+	// In contrast to TypeSessionUpdated this event is intended for guests.
+	// The event data is a GuestData value.
+	TypeGuestSessionUpdated Type = "guest.session.updated"
+
+	// TypeGuestSessionDeleted indicates that a party session has been deleted.
+	// In contrast to TypeSessionDeleted this event is intended for guests.
+	// The event data is a GuestData value.
+	TypeGuestSessionDeleted Type = "guest.session.deleted"
+
+	// TypeGuestQueue indicates that the queue of a party session has changed.
+	// In contrast to TypeSessionQueue this event is intended for guests.
+	// The event data is a GuestData value.
+	TypeGuestQueue Type = "guest.queue"
+)
+
+// Types lists all known event types.
+var Types = []Type{
+	TypeUploadProgress, TypeUploadDone, TypeUploadError,
+	TypeSongCreated, TypeSongUpdated, TypeSongDeleted, TypeSongRestored,
+	TypeMediaCreated, TypeMediaDeleted,
+	TypeSessionUpdated, TypeSessionDeleted, TypeSessionQueue,
+	TypeGuestSessionUpdated, TypeGuestSessionDeleted, TypeGuestQueue,
+}
+
+const (
+	// TopicUploads is the topic for events concerning uploads.
+	// Events for a specific upload are published to the subtopic "uploads/<uuid>".
+	TopicUploads = "uploads"
+
+	// TopicSongs is the topic for events concerning songs.
+	// Events for a specific song are published to the subtopic "songs/<uuid>".
+	TopicSongs = "songs"
+
+	// TopicMedia is the topic for events concerning media files.
+	// Events for a specific file are published to the subtopic "media/<uuid>".
+	TopicMedia = "media"
+
+	// TopicSessions is the topic for events concerning party sessions.
+	// Events for a specific session are published to the subtopic "sessions/<uuid>".
+	// The UUID of a session grants operator access to the session, so this topic is private (see Visible).
+	TopicSessions = "sessions"
+
+	// TopicGuests is the topic for events concerning guests of party sessions.
+	// Events for a specific session are published to the subtopic "guests/<code>" where code is the canonical join code of the session.
+	// Guest events never contain the UUID of a session.
+	// The join code grants guest access to the session, so this topic is private (see Visible).
+	TopicGuests = "guests"
+)
+
+// privateTopics are the topics whose subtopics are keyed by a secret.
+var privateTopics = []string{TopicSessions, TopicGuests}
+
+// Event is a single event on the Bus.
+type Event struct {
+	// ID uniquely identifies an event.
+	ID uuid.UUID `json:"id"`
+	// Type indicates the kind of event.
+	Type Type `json:"type"`
+	// Topic is a slash-separated path that is used to filter events.
+	Topic string `json:"topic"`
+	// Time is the time at which the event was published.
+	Time time.Time `json:"time"`
+	// Data contains the JSON encoded payload of the event.
+	// The structure of the data depends on the Type.
+	Data json.RawMessage `json:"data"`
+}
+
+// Matches reports whether topic matches the specified filter.
+// A topic matches a filter if it is equal to the filter or if it is a subtopic of the filter.
+// For example the topic "uploads/123" matches the filters "uploads" and "uploads/123", but not "upload" or "songs".
+// An empty filter matches all topics.
+func Matches(topic string, filter string) bool {
+	filter = strings.TrimSuffix(filter, "/")
+	if filter == "" || topic == filter {
+		return true
+	}
+	return strings.HasPrefix(topic, filter+"/")
+}
+
+// MatchesAny reports whether topic matches any of the filters.
+// If filters is empty, every topic matches.
+func MatchesAny(topic string, filters []string) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	for _, filter := range filters {
+		if Matches(topic, filter) {
+			return true
+		}
+	}
+	return false
+}
+
+// Visible reports whether an event with the specified topic may be delivered to a subscriber with the specified filters.
+// Subtopics of private topics are identified by a secret (such as the join code of a party session).
+// Their events are only visible if one of the filters is the exact subtopic, so that subscribers cannot discover the secrets.
+// For all other topics Visible is equivalent to MatchesAny.
+func Visible(topic string, filters []string) bool {
+	for _, private := range privateTopics {
+		if Matches(topic, private) {
+			for _, filter := range filters {
+				if topic == strings.TrimSuffix(filter, "/") {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return MatchesAny(topic, filters)
+}
+
+// UploadProgressData is the payload of TypeUploadProgress events.
+type UploadProgressData struct {
+	UUID           uuid.UUID         `json:"uuid"`
+	Status         model.UploadState `json:"status"`
+	SongsTotal     int               `json:"songsTotal"`
+	SongsProcessed int               `json:"songsProcessed"`
+	Errors         int               `json:"errors"`
+}
+
+// UploadErrorData is the payload of TypeUploadError events.
+type UploadErrorData struct {
+	UUID    uuid.UUID `json:"uuid"`
+	File    string    `json:"file"`
+	Message string    `json:"message"`
+	// Duplicate is set if the file contains a duplicate of a song in the library.
+	Duplicate *uuid.UUID `json:"duplicate,omitempty"`
+}
+
+// SongData is the payload of song events.
+type SongData struct {
+	UUID uuid.UUID `json:"uuid"`
+}
+
+// MediaData is the payload of media events.
+type MediaData struct {
+	UUID uuid.UUID `json:"uuid"`
+	Type string    `json:"type,omitempty"`
+}
+
+// SessionData is the payload of session events.
+type SessionData struct {
+	UUID uuid.UUID `json:"uuid"`
+}
+
+// SessionQueueData is the payload of TypeSessionQueue events.
+type SessionQueueData struct {
+	UUID uuid.UUID `json:"uuid"`
+	// Request is the UUID of the request that has changed.
+	// If the queue has been reordered, Request is nil.
+	Request *uuid.UUID `json:"request,omitempty"`
+}
+
+// GuestData is the payload of guest events.
+type GuestData struct {
+	// Code is the join code of the session.
+	Code string `json:"code"`
+	// Request is the UUID of the request that has changed.
+	// Request is only set for TypeGuestQueue events and is nil if the queue has been reordered.
+	Request *uuid.UUID `json:"request,omitempty"`
+}
+
+// New creates a new event with the specified type and topic.
+// data is encoded as JSON.
+// If the encoding fails, this function panics.
+// The event gets a new ID and the current time.
+func New(typ Type, topic string, data any) Event {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		// All payload types of this package can be encoded.
+		panic(err)
+	}
+	e := Event{Type: typ, Topic: topic, Data: raw}
+	e.Prepare()
+	return e
+}
+
+// UploadProgress creates a TypeUploadProgress event for upload.
+func UploadProgress(upload model.Upload) Event {
+	return New(TypeUploadProgress, TopicUploads+"/"+upload.UUID.String(), UploadProgressData{
+		UUID:           upload.UUID,
+		Status:         upload.State,
+		SongsTotal:     upload.SongsTotal,
+		SongsProcessed: upload.SongsProcessed,
+		Errors:         upload.Errors,
+	})
+}
+
+// UploadDone creates a TypeUploadDone event for upload.
+func UploadDone(upload model.Upload) Event {
+	e := UploadProgress(upload)
+	e.Type = TypeUploadDone
+	return e
+}
+
+// UploadError creates a TypeUploadError event for the processing error err that occurred in upload.
+func UploadError(upload model.Upload, err model.UploadProcessingError) Event {
+	data := UploadErrorData{
+		UUID:    upload.UUID,
+		File:    err.File,
+		Message: err.Message,
+	}
+	if err.Duplicate != uuid.Nil {
+		data.Duplicate = &err.Duplicate
+	}
+	return New(TypeUploadError, TopicUploads+"/"+upload.UUID.String(), data)
+}
+
+// SongCreated creates a TypeSongCreated event for song.
+func SongCreated(song model.Song) Event {
+	return songEvent(TypeSongCreated, song.UUID)
+}
+
+// SongUpdated creates a TypeSongUpdated event for song.
+func SongUpdated(song model.Song) Event {
+	return songEvent(TypeSongUpdated, song.UUID)
+}
+
+// SongDeleted creates a TypeSongDeleted event for the song with the specified UUID.
+func SongDeleted(id uuid.UUID) Event {
+	return songEvent(TypeSongDeleted, id)
+}
+
+// SongRestored creates a TypeSongRestored event for song.
+func SongRestored(song model.Song) Event {
+	return songEvent(TypeSongRestored, song.UUID)
+}
+
+// MediaCreated creates a TypeMediaCreated event for file.
+func MediaCreated(file model.File) Event {
+	return New(TypeMediaCreated, TopicMedia+"/"+file.UUID.String(), MediaData{file.UUID, file.Type.String()})
+}
+
+// MediaDeleted creates a TypeMediaDeleted event for the file with the specified UUID.
+func MediaDeleted(id uuid.UUID) Event {
+	return New(TypeMediaDeleted, TopicMedia+"/"+id.String(), MediaData{UUID: id})
+}
+
+// SessionUpdated creates a TypeSessionUpdated event for session.
+func SessionUpdated(session model.Session) Event {
+	return New(TypeSessionUpdated, TopicSessions+"/"+session.UUID.String(), SessionData{session.UUID})
+}
+
+// SessionDeleted creates a TypeSessionDeleted event for the session with the specified UUID.
+func SessionDeleted(id uuid.UUID) Event {
+	return New(TypeSessionDeleted, TopicSessions+"/"+id.String(), SessionData{id})
+}
+
+// SessionQueue creates a TypeSessionQueue event for the session with the specified UUID.
+// request is the UUID of the request that has changed or uuid.Nil if the queue has been reordered.
+func SessionQueue(session uuid.UUID, request uuid.UUID) Event {
+	data := SessionQueueData{UUID: session}
+	if request != uuid.Nil {
+		data.Request = &request
+	}
+	return New(TypeSessionQueue, TopicSessions+"/"+session.String(), data)
+}
+
+// GuestSessionUpdated creates a TypeGuestSessionUpdated event for session.
+func GuestSessionUpdated(session model.Session) Event {
+	return New(TypeGuestSessionUpdated, TopicGuests+"/"+session.Code, GuestData{Code: session.Code})
+}
+
+// GuestSessionDeleted creates a TypeGuestSessionDeleted event for the session with the specified join code.
+func GuestSessionDeleted(code string) Event {
+	return New(TypeGuestSessionDeleted, TopicGuests+"/"+code, GuestData{Code: code})
+}
+
+// GuestQueue creates a TypeGuestQueue event for the session with the specified join code.
+// request is the UUID of the request that has changed or uuid.Nil if the queue has been reordered.
+func GuestQueue(code string, request uuid.UUID) Event {
+	data := GuestData{Code: code}
+	if request != uuid.Nil {
+		data.Request = &request
+	}
+	return New(TypeGuestQueue, TopicGuests+"/"+code, data)
+}
+
+// songEvent creates a song event of the specified type.
+func songEvent(typ Type, id uuid.UUID) Event {
+	return New(typ, TopicSongs+"/"+id.String(), SongData{id})
+}
+
+// Prepare sets e.ID and e.Time if they are not set yet.
+// Bus implementations call this method before publishing an event.
+func (e *Event) Prepare() {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+}
@@ -0,0 +1,137 @@
+package event
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func TestMatches(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		topic    string
+		filter   string
+		expected bool
+	}{
+		"empty filter":   {"uploads/123", "", true},
+		"equal":          {"uploads/123", "uploads/123", true},
+		"parent":         {"uploads/123", "uploads", true},
+		"trailing slash": {"uploads/123", "uploads/", true},
+		"prefix only":    {"uploads/123", "upload", false},
+		"other topic":    {"uploads/123", "songs", false},
+		"child filter":   {"uploads", "uploads/123", false},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if actual := Matches(c.topic, c.filter); actual != c.expected {
+				t.Errorf("Matches(%q, %q) = %t, expected %t", c.topic, c.filter, actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestMatchesAny(t *testing.T) {
+	t.Parallel()
+
+	if !MatchesAny("songs/123", nil) {
+		t.Errorf("MatchesAny(%q, nil) = false, expected true", "songs/123")
+	}
+	if !MatchesAny("songs/123", []string{"uploads", "songs"}) {
+		t.Errorf("MatchesAny(%q, %v) = false, expected true", "songs/123", []string{"uploads", "songs"})
+	}
+	if MatchesAny("songs/123", []string{"uploads"}) {
+		t.Errorf("MatchesAny(%q, %v) = true, expected false", "songs/123", []string{"uploads"})
+	}
+}
+
+func TestVisible(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		topic    string
+		filters  []string
+		expected bool
+	}{
+		"public":          {"songs/123", nil, true},
+		"public filtered": {"songs/123", []string{"uploads"}, false},
+		"private all":     {"sessions/123", nil, false},
+		"private parent":  {"sessions/123", []string{"sessions"}, false},
+		"private exact":   {"sessions/123", []string{"sessions/123"}, true},
+		"guest parent":    {"guests/ABC", []string{"guests/"}, false},
+		"guest exact":     {"guests/ABC", []string{"songs", "guests/ABC/"}, true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if actual := Visible(c.topic, c.filters); actual != c.expected {
+				t.Errorf("Visible(%q, %v) = %t, expected %t", c.topic, c.filters, actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestGuestQueue(t *testing.T) {
+	t.Parallel()
+
+	request := uuid.New()
+	e := GuestQueue("ABC234", request)
+	if expected := "guests/ABC234"; e.Topic != expected {
+		t.Errorf("GuestQueue(...).Topic = %q, expected %q", e.Topic, expected)
+	}
+	if expected := `{"code":"ABC234","request":"` + request.String() + `"}`; string(e.Data) != expected {
+		t.Errorf("GuestQueue(...) produced data %s, expected %s", e.Data, expected)
+	}
+}
+
+func TestUploadProgress(t *testing.T) {
+	t.Parallel()
+
+	upload := model.Upload{
+		Model:          model.Model{UUID: uuid.New()},
+		State:          model.UploadStateProcessing,
+		SongsTotal:     5,
+		SongsProcessed: 2,
+		Errors:         1,
+	}
+	e := UploadProgress(upload)
+	if e.Type != TypeUploadProgress {
+		t.Errorf("UploadProgress(...).Type = %q, expected %q", e.Type, TypeUploadProgress)
+	}
+	if expected := "uploads/" + upload.UUID.String(); e.Topic != expected {
+		t.Errorf("UploadProgress(...).Topic = %q, expected %q", e.Topic, expected)
+	}
+	var data UploadProgressData
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		t.Fatalf("UploadProgress(...) produced invalid data: %s", err)
+	}
+	if data.UUID != upload.UUID || data.SongsProcessed != 2 || data.SongsTotal != 5 || data.Errors != 1 || data.Status != model.UploadStateProcessing {
+		t.Errorf("UploadProgress(...) produced data %+v, expected values from %+v", data, upload)
+	}
+}
+
+func TestSessionQueue(t *testing.T) {
+	t.Parallel()
+
+	session, request := uuid.New(), uuid.New()
+	e := SessionQueue(session, request)
+	if expected := "sessions/" + session.String(); e.Topic != expected {
+		t.Errorf("SessionQueue(...).Topic = %q, expected %q", e.Topic, expected)
+	}
+	var data SessionQueueData
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		t.Fatalf("SessionQueue(...) produced invalid data: %s", err)
+	}
+	if data.UUID != session || data.Request == nil || *data.Request != request {
+		t.Errorf("SessionQueue(...) produced data %+v, expected session %q and request %q", data, session, request)
+	}
+
+	e = SessionQueue(session, uuid.Nil)
+	if string(e.Data) != `{"uuid":"`+session.String()+`"}` {
+		t.Errorf("SessionQueue(%q, uuid.Nil) produced data %s, expected no request", session, e.Data)
+	}
+}
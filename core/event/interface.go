@@ -0,0 +1,27 @@
+package event
+
+import (
+	"context"
+)
+
+// A Bus distributes events between different parts of Karman.
+// Events published on a Bus are delivered to all subscribers whose topic filters match the event.
+// Implementations must be safe for concurrent use.
+//
+// Event delivery is best effort.
+// Subscribers that do not keep up with the rate of published events may miss some events.
+type Bus interface {
+	// Publish sends e to all current subscribers of the bus.
+	// If e.ID or e.Time are not set, implementations must set them.
+	// An error indicates that the event could not be delivered to the bus.
+	Publish(ctx context.Context, e Event) error
+
+	// Subscribe registers a new subscription for events matching any of the specified topics.
+	// If no topics are given, all events are delivered.
+	// See Matches for details on topic matching.
+	//
+	// The returned channel receives the events of the subscription.
+	// The subscription is active until ctx is canceled.
+	// Afterward the channel is closed.
+	Subscribe(ctx context.Context, topics ...string) (<-chan Event, error)
+}
@@ -0,0 +1,63 @@
+package event
+
+import (
+	"context"
+	"sync"
+)
+
+// subscriptionBuffer is the number of events that are buffered for each subscriber
+// before events for that subscriber are dropped.
+const subscriptionBuffer = 64
+
+// memSubscription is a single subscriber of a memBus.
+type memSubscription struct {
+	topics []string
+	ch     chan Event
+}
+
+// memBus is an in-process Bus implementation.
+type memBus struct {
+	mu   sync.Mutex
+	subs map[*memSubscription]struct{}
+}
+
+// NewMemBus returns a new Bus implementation that delivers events within the current process only.
+// This implementation is mainly intended for testing purposes.
+// If multiple Karman processes need to exchange events use NewRedisBus instead.
+func NewMemBus() Bus {
+	return &memBus{subs: make(map[*memSubscription]struct{})}
+}
+
+// Publish delivers e to all matching subscribers.
+// If a subscriber is not ready to receive the event, it is dropped for that subscriber.
+func (b *memBus) Publish(_ context.Context, e Event) error {
+	e.Prepare()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subs {
+		if !MatchesAny(e.Topic, sub.topics) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe registers a new subscriber.
+func (b *memBus) Subscribe(ctx context.Context, topics ...string) (<-chan Event, error) {
+	sub := &memSubscription{topics, make(chan Event, subscriptionBuffer)}
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subs, sub)
+		close(sub.ch)
+		b.mu.Unlock()
+	}()
+	return sub.ch, nil
+}
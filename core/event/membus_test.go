@@ -0,0 +1,89 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// testBus runs a common set of tests against a Bus implementation.
+func testBus(t *testing.T, bus Bus) {
+	t.Run("delivery", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ch, err := bus.Subscribe(ctx)
+		if err != nil {
+			t.Fatalf("Subscribe(ctx) returned an unexpected error: %s", err)
+		}
+		id := uuid.New()
+		if err = bus.Publish(ctx, SongDeleted(id)); err != nil {
+			t.Fatalf("Publish(ctx, e) returned an unexpected error: %s", err)
+		}
+		e := receive(t, ch)
+		if e.Type != TypeSongDeleted {
+			t.Errorf("Subscribe(ctx) received event of type %q, expected %q", e.Type, TypeSongDeleted)
+		}
+		if e.ID == uuid.Nil {
+			t.Errorf("Subscribe(ctx) received event without ID, expected non-nil ID")
+		}
+		if e.Time.IsZero() {
+			t.Errorf("Subscribe(ctx) received event without time, expected non-zero time")
+		}
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ch, err := bus.Subscribe(ctx, TopicUploads)
+		if err != nil {
+			t.Fatalf("Subscribe(ctx, %q) returned an unexpected error: %s", TopicUploads, err)
+		}
+		_ = bus.Publish(ctx, SongDeleted(uuid.New()))
+		_ = bus.Publish(ctx, New(TypeUploadError, TopicUploads+"/foo", UploadErrorData{}))
+		e := receive(t, ch)
+		if e.Topic != TopicUploads+"/foo" {
+			t.Errorf("Subscribe(ctx, %q) received event with topic %q, expected %q", TopicUploads, e.Topic, TopicUploads+"/foo")
+		}
+	})
+
+	t.Run("cancel", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch, err := bus.Subscribe(ctx)
+		if err != nil {
+			t.Fatalf("Subscribe(ctx) returned an unexpected error: %s", err)
+		}
+		cancel()
+		select {
+		case _, ok := <-ch:
+			if ok {
+				// Events published by other tests may still be delivered.
+				for range ch {
+				}
+			}
+		case <-time.After(5 * time.Second):
+			t.Errorf("Subscribe(ctx) did not close the channel after ctx was canceled")
+		}
+	})
+}
+
+// receive waits for the next event on ch.
+// If no event is received within a short timeout, the test fails.
+func receive(t *testing.T, ch <-chan Event) Event {
+	select {
+	case e, ok := <-ch:
+		if !ok {
+			t.Fatalf("event channel was closed unexpectedly")
+		}
+		return e
+	case <-time.After(5 * time.Second):
+		t.Fatalf("did not receive an event within 5 seconds")
+	}
+	return Event{}
+}
+
+func TestMemBus(t *testing.T) {
+	t.Parallel()
+	testBus(t, NewMemBus())
+}
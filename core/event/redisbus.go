@@ -0,0 +1,88 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/lmittmann/tint"
+	"github.com/redis/go-redis/v9"
+)
+
+// redisChannel is the Redis Pub/Sub channel used to transmit events.
+const redisChannel = "karman:events"
+
+// redisBus is a Bus implementation backed by Redis Pub/Sub.
+type redisBus struct {
+	logger *slog.Logger
+	client redis.UniversalClient
+}
+
+// NewRedisBus creates a new Bus that distributes events via Redis Pub/Sub.
+// Events are delivered to all subscribers connected to the same Redis server,
+// even across multiple Karman processes.
+// The caller is responsible for closing client when the bus is no longer needed.
+func NewRedisBus(logger *slog.Logger, client redis.UniversalClient) Bus {
+	return &redisBus{logger, client}
+}
+
+// Publish encodes e as JSON and sends it to the Redis channel.
+func (b *redisBus) Publish(ctx context.Context, e Event) error {
+	e.Prepare()
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encoding event: %w", err)
+	}
+	if err = b.client.Publish(ctx, redisChannel, data).Err(); err != nil {
+		b.logger.ErrorContext(ctx, "Could not publish event.", "type", e.Type, "topic", e.Topic, tint.Err(err))
+		return err
+	}
+	return nil
+}
+
+// Subscribe subscribes to the Redis channel.
+// Events are filtered locally using the specified topics.
+func (b *redisBus) Subscribe(ctx context.Context, topics ...string) (<-chan Event, error) {
+	pubsub := b.client.Subscribe(ctx, redisChannel)
+	// Wait for the subscription to be confirmed so that no events get lost after this method returns.
+	if _, err := pubsub.Receive(ctx); err != nil {
+		b.logger.ErrorContext(ctx, "Could not subscribe to events.", tint.Err(err))
+		_ = pubsub.Close()
+		return nil, err
+	}
+	ch := make(chan Event, subscriptionBuffer)
+	go func() {
+		defer close(ch)
+		defer func() {
+			if err := pubsub.Close(); err != nil {
+				b.logger.WarnContext(ctx, "Could not close event subscription.", tint.Err(err))
+			}
+		}()
+		msgs := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-msgs:
+				if !ok {
+					return
+				}
+				var e Event
+				if err := json.Unmarshal([]byte(msg.Payload), &e); err != nil {
+					b.logger.WarnContext(ctx, "Received invalid event.", tint.Err(err))
+					continue
+				}
+				if !MatchesAny(e.Topic, topics) {
+					continue
+				}
+				select {
+				case ch <- e:
+				default:
+					// The subscriber is too slow, drop the event.
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
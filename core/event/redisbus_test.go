@@ -0,0 +1,19 @@
+package event
+
+import (
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+)
+
+func TestRedisBus(t *testing.T) {
+	t.Parallel()
+
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	testBus(t, NewRedisBus(nolog.Logger, client))
+}
@@ -0,0 +1,119 @@
+package header
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// fakeRepo is a simple implementation of Repository that can be used for testing.
+// The fake repository does not know about songs, so CountCustomTags always returns an empty map.
+type fakeRepo struct {
+	// headers is the "database" of a fakeRepo.
+	headers map[uuid.UUID]model.CustomHeader
+}
+
+// NewFakeRepository returns a new Repository implementation backed by an in-memory map.
+func NewFakeRepository() Repository {
+	return &fakeRepo{make(map[uuid.UUID]model.CustomHeader)}
+}
+
+// CreateHeader stores the header and sets its UUID, CreatedAt, and UpdatedAt fields.
+func (r *fakeRepo) CreateHeader(_ context.Context, header *model.CustomHeader) error {
+	prepareHeader(header)
+	if r.conflicts(uuid.Nil, header) {
+		return core.ErrConflict
+	}
+	header.UUID = uuid.New()
+	header.CreatedAt = time.Now()
+	header.UpdatedAt = header.CreatedAt
+	r.headers[header.UUID] = *header
+	return nil
+}
+
+// GetHeader looks up the custom header with the specified UUID.
+func (r *fakeRepo) GetHeader(_ context.Context, id uuid.UUID) (model.CustomHeader, error) {
+	header, ok := r.headers[id]
+	if !ok {
+		return model.CustomHeader{}, core.ErrNotFound
+	}
+	return header, nil
+}
+
+// FindHeaders returns a list of custom headers ordered by name, limited by the specified pagination parameters.
+func (r *fakeRepo) FindHeaders(_ context.Context, limit int, offset int64) ([]model.CustomHeader, int64, error) {
+	if limit < 0 {
+		limit = math.MaxInt
+	}
+	headers := make([]model.CustomHeader, 0, len(r.headers))
+	for _, header := range r.headers {
+		headers = append(headers, header)
+	}
+	slices.SortFunc(headers, func(a, b model.CustomHeader) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	total := int64(len(headers))
+	if offset > total {
+		offset = total
+	}
+	headers = headers[offset:]
+	return headers[:min(limit, len(headers))], total, nil
+}
+
+// UpdateHeader updates the stored custom header.
+func (r *fakeRepo) UpdateHeader(_ context.Context, header *model.CustomHeader) error {
+	if _, ok := r.headers[header.UUID]; !ok {
+		return core.ErrNotFound
+	}
+	prepareHeader(header)
+	if r.conflicts(header.UUID, header) {
+		return core.ErrConflict
+	}
+	header.UpdatedAt = time.Now()
+	r.headers[header.UUID] = *header
+	return nil
+}
+
+// DeleteHeader deletes the custom header with the specified UUID.
+func (r *fakeRepo) DeleteHeader(_ context.Context, id uuid.UUID) (bool, error) {
+	if _, ok := r.headers[id]; !ok {
+		return false, nil
+	}
+	delete(r.headers, id)
+	return true, nil
+}
+
+// CountCustomTags returns an empty map.
+func (r *fakeRepo) CountCustomTags(context.Context) (map[string]int64, error) {
+	return make(map[string]int64), nil
+}
+
+// conflicts reports whether the name or an alias of header is used by a header other than the one with the specified UUID.
+func (r *fakeRepo) conflicts(id uuid.UUID, header *model.CustomHeader) bool {
+	names := append([]string{header.Name}, header.Aliases...)
+	for _, other := range r.headers {
+		if other.UUID == id {
+			continue
+		}
+		for _, name := range names {
+			if hasName(other, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasName reports whether name is the name or an alias of header, ignoring case.
+func hasName(header model.CustomHeader, name string) bool {
+	return strings.EqualFold(header.Name, name) || slices.ContainsFunc(header.Aliases, func(alias string) bool {
+		return strings.EqualFold(alias, name)
+	})
+}
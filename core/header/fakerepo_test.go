@@ -0,0 +1,50 @@
+package header
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func Test_fakeRepo_Headers(t *testing.T) {
+	t.Parallel()
+
+	repo := NewFakeRepository()
+	album := model.CustomHeader{Name: " #ALBUM ", Aliases: []string{"ALBUMN", "album", ""}}
+	if err := repo.CreateHeader(context.TODO(), &album); err != nil {
+		t.Fatalf("CreateHeader(ctx, &header) returned an unexpected error: %s", err)
+	}
+	if album.Name != "ALBUM" || album.Type != model.HeaderTypeString || len(album.Aliases) != 1 {
+		t.Errorf("CreateHeader(ctx, &header) produced name %q, type %q and aliases %q, expected %q, %q and [%q]", album.Name, album.Type, album.Aliases, "ALBUM", model.HeaderTypeString, "ALBUMN")
+	}
+
+	conflict := model.CustomHeader{Name: "albumn"}
+	if err := repo.CreateHeader(context.TODO(), &conflict); !errors.Is(err, core.ErrConflict) {
+		t.Errorf("CreateHeader(ctx, &header) with a conflicting name returned %v, expected ErrConflict", err)
+	}
+
+	rating := model.CustomHeader{Name: "RATING", Type: model.HeaderTypeInt}
+	_ = repo.CreateHeader(context.TODO(), &rating)
+	headers, total, err := repo.FindHeaders(context.TODO(), 1, 1)
+	if err != nil {
+		t.Fatalf("FindHeaders(ctx, 1, 1) returned an unexpected error: %s", err)
+	}
+	if total != 2 || len(headers) != 1 || headers[0].UUID != rating.UUID {
+		t.Errorf("FindHeaders(ctx, 1, 1) = %v, %d, expected [%s], 2", headers, total, rating.Name)
+	}
+
+	rating.Aliases = []string{"Album"}
+	if err = repo.UpdateHeader(context.TODO(), &rating); !errors.Is(err, core.ErrConflict) {
+		t.Errorf("UpdateHeader(ctx, &header) with a conflicting alias returned %v, expected ErrConflict", err)
+	}
+
+	if ok, _ := repo.DeleteHeader(context.TODO(), album.UUID); !ok {
+		t.Errorf("DeleteHeader(ctx, %q) = false, expected true", album.UUID)
+	}
+	if _, err = repo.GetHeader(context.TODO(), album.UUID); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("GetHeader(ctx, %q) after deleting returned %v, expected ErrNotFound", album.UUID, err)
+	}
+}
@@ -0,0 +1,41 @@
+package header
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// A Repository is an interface for storing the registry of custom headers.
+//
+// Header names and aliases are unique across all headers, ignoring case.
+// Operations that would violate this constraint return core.ErrConflict.
+type Repository interface {
+	// CreateHeader creates a new custom header.
+	// This method must set header.UUID, header.CreatedAt, and header.UpdatedAt appropriately.
+	CreateHeader(ctx context.Context, header *model.CustomHeader) error
+
+	// GetHeader fetches the custom header with the specified UUID.
+	// If no such header exists, core.ErrNotFound will be returned.
+	GetHeader(ctx context.Context, id uuid.UUID) (model.CustomHeader, error)
+
+	// FindHeaders returns all custom headers ordered by name.
+	// Results are paginated with limit and offset.
+	// The second return value contains the total (unpaginated) number of headers.
+	FindHeaders(ctx context.Context, limit int, offset int64) ([]model.CustomHeader, int64, error)
+
+	// UpdateHeader saves the specified custom header.
+	// The header's UUID must already exist in the database, otherwise core.ErrNotFound will be returned.
+	UpdateHeader(ctx context.Context, header *model.CustomHeader) error
+
+	// DeleteHeader deletes the custom header with the specified UUID.
+	// Custom tags of songs are not modified.
+	// If no such header exists, the first return value will be false.
+	DeleteHeader(ctx context.Context, id uuid.UUID) (bool, error)
+
+	// CountCustomTags returns the number of library songs using each custom tag key.
+	// Keys are compared exactly, so different spellings of a key are counted separately.
+	CountCustomTags(ctx context.Context) (map[string]int64, error)
+}
@@ -0,0 +1,44 @@
+package header
+
+import (
+	"context"
+
+	"github.com/Karaoke-Manager/karman/core/song"
+)
+
+// MigrationResult describes the changes made by Migrate.
+type MigrationResult struct {
+	// Songs is the number of songs whose custom tags were renamed.
+	Songs int
+	// Renamed maps each renamed key to the number of songs in which it was renamed.
+	Renamed map[string]int
+}
+
+// Migrate renames custom tag keys of all library songs that refer to a known header of registry
+// but are not spelled canonically, such as aliases or keys in the wrong case.
+// If dryRun is true, songs are not modified, but the result describes the changes that would have been made.
+func Migrate(ctx context.Context, registry *Registry, songRepo song.Repository, dryRun bool) (MigrationResult, error) {
+	result := MigrationResult{Renamed: make(map[string]int)}
+	songs, _, err := songRepo.FindSongs(ctx, song.Filter{}, -1, 0)
+	if err != nil {
+		return result, err
+	}
+	for _, sng := range songs {
+		tags, renamed := registry.Canonicalize(sng.CustomTags)
+		if len(renamed) == 0 {
+			continue
+		}
+		result.Songs++
+		for key := range renamed {
+			result.Renamed[key]++
+		}
+		if dryRun {
+			continue
+		}
+		sng.CustomTags = tags
+		if err = songRepo.UpdateSong(ctx, &sng); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
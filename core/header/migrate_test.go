@@ -0,0 +1,42 @@
+package header
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	r := testRegistry(t)
+	songRepo := song.NewFakeRepository()
+	legacy := model.Song{}
+	legacy.CustomTags = map[string]string{"ALBUMN": "Hits", "FOO": "bar"}
+	clean := model.Song{}
+	clean.CustomTags = map[string]string{"ALBUM": "Hits"}
+	_ = songRepo.CreateSong(context.TODO(), &legacy)
+	_ = songRepo.CreateSong(context.TODO(), &clean)
+
+	result, err := Migrate(context.TODO(), r, songRepo, true)
+	if err != nil {
+		t.Fatalf("Migrate(ctx, registry, songRepo, true) returned an unexpected error: %s", err)
+	}
+	if result.Songs != 1 || result.Renamed["ALBUMN"] != 1 {
+		t.Errorf("Migrate(ctx, registry, songRepo, true) = %+v, expected 1 song with ALBUMN renamed", result)
+	}
+	if sng, _ := songRepo.GetSong(context.TODO(), legacy.UUID); sng.CustomTags["ALBUMN"] != "Hits" {
+		t.Errorf("Migrate(ctx, registry, songRepo, true) modified custom tags %v, expected no changes", sng.CustomTags)
+	}
+
+	if _, err = Migrate(context.TODO(), r, songRepo, false); err != nil {
+		t.Fatalf("Migrate(ctx, registry, songRepo, false) returned an unexpected error: %s", err)
+	}
+	sng, _ := songRepo.GetSong(context.TODO(), legacy.UUID)
+	if expected := map[string]string{"ALBUM": "Hits", "FOO": "bar"}; !maps.Equal(sng.CustomTags, expected) {
+		t.Errorf("Migrate(ctx, registry, songRepo, false) produced custom tags %v, expected %v", sng.CustomTags, expected)
+	}
+}
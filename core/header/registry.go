@@ -0,0 +1,202 @@
+package header
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// A Registry validates the custom tags of songs against a set of known custom headers.
+// Keys are looked up ignoring case, so misspelled keys that only differ in case are recognized.
+// The zero value is an empty registry that knows no headers.
+type Registry struct {
+	// headers maps the lower case names and aliases of headers to the headers.
+	headers map[string]model.CustomHeader
+	// patterns contains the compiled patterns of headers by their canonical name.
+	patterns map[string]*regexp.Regexp
+}
+
+// NewRegistry creates a registry of the specified headers.
+// An error is returned if the pattern of a header is not a valid regular expression.
+func NewRegistry(headers []model.CustomHeader) (*Registry, error) {
+	r := &Registry{
+		headers:  make(map[string]model.CustomHeader, len(headers)),
+		patterns: make(map[string]*regexp.Regexp),
+	}
+	for _, h := range headers {
+		if h.Pattern != "" {
+			re, err := regexp.Compile(h.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("header %s: invalid pattern: %w", h.Name, err)
+			}
+			r.patterns[h.Name] = re
+		}
+		r.headers[strings.ToLower(h.Name)] = h
+		for _, alias := range h.Aliases {
+			r.headers[strings.ToLower(alias)] = h
+		}
+	}
+	return r, nil
+}
+
+// LoadRegistry creates a registry of all custom headers in repo.
+func LoadRegistry(ctx context.Context, repo Repository) (*Registry, error) {
+	headers, _, err := repo.FindHeaders(ctx, -1, 0)
+	if err != nil {
+		return nil, err
+	}
+	return NewRegistry(headers)
+}
+
+// Empty reports whether r does not know any headers.
+// An empty registry does not report unknown keys.
+func (r *Registry) Empty() bool {
+	return len(r.headers) == 0
+}
+
+// Lookup returns the header that key refers to.
+// Keys are compared to the names and aliases of headers ignoring case.
+// If key does not refer to a known header, the second return value is false.
+func (r *Registry) Lookup(key string) (model.CustomHeader, bool) {
+	h, ok := r.headers[strings.ToLower(key)]
+	return h, ok
+}
+
+// A Problem describes a custom tag that does not conform to the registry.
+type Problem struct {
+	// Key is the key of the custom tag.
+	Key string
+	// Message describes the problem.
+	Message string
+}
+
+// Error returns a description of the problem.
+func (p Problem) Error() string {
+	return fmt.Sprintf("#%s: %s", p.Key, p.Message)
+}
+
+// Validate checks that the values of all known custom tags in tags are valid.
+// Unknown keys are not checked, use Check to report them as well.
+// The problems are sorted by key.
+func (r *Registry) Validate(tags map[string]string) []Problem {
+	var problems []Problem
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		h, ok := r.Lookup(key)
+		if !ok {
+			continue
+		}
+		if msg := r.validateValue(h, tags[key]); msg != "" {
+			problems = append(problems, Problem{key, msg})
+		}
+	}
+	return problems
+}
+
+// Check validates tags like Validate and additionally reports unknown keys and keys that are not spelled canonically.
+// If r is empty, no problems are reported.
+func (r *Registry) Check(tags map[string]string) []Problem {
+	if r.Empty() {
+		return nil
+	}
+	var problems []Problem
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		h, ok := r.Lookup(key)
+		if !ok {
+			problems = append(problems, Problem{key, "unknown custom header"})
+			continue
+		}
+		if key != h.Name {
+			problems = append(problems, Problem{key, fmt.Sprintf("should be spelled #%s", h.Name)})
+		}
+		if msg := r.validateValue(h, tags[key]); msg != "" {
+			problems = append(problems, Problem{key, msg})
+		}
+	}
+	return problems
+}
+
+// validateValue checks that value is valid for h.
+// If the value is invalid, a description of the problem is returned.
+// Otherwise, the empty string is returned.
+func (r *Registry) validateValue(h model.CustomHeader, value string) string {
+	switch h.Type {
+	case model.HeaderTypeInt:
+		if _, err := strconv.Atoi(strings.TrimSpace(value)); err != nil {
+			return "must be an integer"
+		}
+	case model.HeaderTypeDuration:
+		secs, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(value), ",", "."), 64)
+		if err != nil || secs < 0 {
+			return "must be a non-negative number of seconds"
+		}
+	case model.HeaderTypeURL:
+		u, err := url.Parse(strings.TrimSpace(value))
+		if err != nil || !u.IsAbs() || u.Host == "" {
+			return "must be an absolute URL"
+		}
+	}
+	if re := r.patterns[h.Name]; re != nil && !re.MatchString(value) {
+		return fmt.Sprintf("must match the pattern %s", h.Pattern)
+	}
+	return ""
+}
+
+// Canonicalize renames the keys of tags that refer to a known header but are not spelled canonically.
+// If tags contains the canonical key as well, the value of the canonical key is kept.
+// If tags contains several spellings of a header but not the canonical key,
+// the value of the spelling that sorts first is kept.
+// The result is a new map, tags is not modified.
+// The second return value maps renamed keys to their canonical spelling.
+func (r *Registry) Canonicalize(tags map[string]string) (map[string]string, map[string]string) {
+	result := make(map[string]string, len(tags))
+	renamed := make(map[string]string)
+	for _, key := range slices.Sorted(maps.Keys(tags)) {
+		value := tags[key]
+		h, ok := r.Lookup(key)
+		if !ok || key == h.Name {
+			result[key] = value
+			continue
+		}
+		renamed[key] = h.Name
+		if _, exists := tags[h.Name]; exists {
+			continue
+		}
+		if _, exists := result[h.Name]; !exists {
+			result[h.Name] = value
+		}
+	}
+	return result, renamed
+}
+
+// A KeyReport describes a custom tag key that is used by songs but is not the canonical spelling of a known header.
+type KeyReport struct {
+	// Key is the key as used by songs.
+	Key string
+	// Songs is the number of songs using the key.
+	Songs int64
+	// Canonical is the canonical spelling of the key.
+	// If the key does not refer to a known header, Canonical is empty.
+	Canonical string
+}
+
+// Report returns a KeyReport for each key in counts that is unknown or not spelled canonically.
+// counts maps keys to the number of songs using them, as returned by Repository.CountCustomTags.
+// The reports are sorted by key.
+func (r *Registry) Report(counts map[string]int64) []KeyReport {
+	reports := make([]KeyReport, 0)
+	for _, key := range slices.Sorted(maps.Keys(counts)) {
+		h, ok := r.Lookup(key)
+		if ok && key == h.Name {
+			continue
+		}
+		reports = append(reports, KeyReport{Key: key, Songs: counts[key], Canonical: h.Name})
+	}
+	return reports
+}
@@ -0,0 +1,107 @@
+package header
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// testRegistry returns a registry with a few common headers.
+func testRegistry(t *testing.T) *Registry {
+	r, err := NewRegistry([]model.CustomHeader{
+		{Name: "ALBUM", Type: model.HeaderTypeString, Aliases: []string{"ALBUMN"}},
+		{Name: "RATING", Type: model.HeaderTypeInt, Pattern: `^[1-5]$`},
+		{Name: "INTROLENGTH", Type: model.HeaderTypeDuration},
+		{Name: "PROVIDEDBY", Type: model.HeaderTypeURL},
+	})
+	if err != nil {
+		t.Fatalf("NewRegistry() returned an unexpected error: %s", err)
+	}
+	return r
+}
+
+func TestNewRegistry(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewRegistry([]model.CustomHeader{{Name: "RATING", Pattern: "[1-5"}})
+	if err == nil {
+		t.Errorf("NewRegistry() with an invalid pattern did not return an error")
+	}
+}
+
+func TestRegistry_Validate(t *testing.T) {
+	t.Parallel()
+
+	r := testRegistry(t)
+	cases := map[string]struct {
+		key, value string
+		valid      bool
+	}{
+		"string":              {"ALBUM", "Greatest Hits", true},
+		"int":                 {"RATING", "4", true},
+		"int invalid":         {"RATING", "four", false},
+		"pattern":             {"rating", "7", false},
+		"duration":            {"INTROLENGTH", "12,5", true},
+		"duration negative":   {"INTROLENGTH", "-1", false},
+		"url":                 {"PROVIDEDBY", "https://usdb.animux.de", true},
+		"url relative":        {"PROVIDEDBY", "usdb.animux.de", false},
+		"unknown not checked": {"FOO", "bar", true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			problems := r.Validate(map[string]string{c.key: c.value})
+			if c.valid && len(problems) > 0 {
+				t.Errorf("Validate({%q: %q}) = %v, expected no problems", c.key, c.value, problems)
+			} else if !c.valid && len(problems) != 1 {
+				t.Errorf("Validate({%q: %q}) = %v, expected 1 problem", c.key, c.value, problems)
+			}
+		})
+	}
+}
+
+func TestRegistry_Check(t *testing.T) {
+	t.Parallel()
+
+	r := testRegistry(t)
+	problems := r.Check(map[string]string{"ALBUM": "Hits", "Albumn": "Hits", "FOO": "bar"})
+	if len(problems) != 2 || problems[0].Key != "Albumn" || problems[1].Key != "FOO" {
+		t.Errorf("Check(tags) = %v, expected problems for %q and %q", problems, "Albumn", "FOO")
+	}
+	if problems = (&Registry{}).Check(map[string]string{"FOO": "bar"}); len(problems) > 0 {
+		t.Errorf("Check(tags) with an empty registry = %v, expected no problems", problems)
+	}
+}
+
+func TestRegistry_Canonicalize(t *testing.T) {
+	t.Parallel()
+
+	r := testRegistry(t)
+	tags, renamed := r.Canonicalize(map[string]string{"albumn": "Hits", "rating": "3", "RATING": "5", "FOO": "bar"})
+	if expected := map[string]string{"ALBUM": "Hits", "RATING": "5", "FOO": "bar"}; !maps.Equal(tags, expected) {
+		t.Errorf("Canonicalize(tags) = %v, expected %v", tags, expected)
+	}
+	if expected := map[string]string{"albumn": "ALBUM", "rating": "RATING"}; !maps.Equal(renamed, expected) {
+		t.Errorf("Canonicalize(tags) renamed %v, expected %v", renamed, expected)
+	}
+
+	for i := 0; i < 10; i++ {
+		tags, _ = r.Canonicalize(map[string]string{"Album": "A", "albumn": "B", "ALBUMN": "C"})
+		if expected := map[string]string{"ALBUM": "C"}; !maps.Equal(tags, expected) {
+			t.Fatalf("Canonicalize(tags) = %v, expected %v", tags, expected)
+		}
+	}
+}
+
+func TestRegistry_Report(t *testing.T) {
+	t.Parallel()
+
+	r := testRegistry(t)
+	reports := r.Report(map[string]int64{"ALBUM": 10, "ALBUMN": 2, "FOO": 1})
+	if len(reports) != 2 {
+		t.Fatalf("Report(counts) returned %d reports, expected 2", len(reports))
+	}
+	if reports[0] != (KeyReport{Key: "ALBUMN", Songs: 2, Canonical: "ALBUM"}) || reports[1] != (KeyReport{Key: "FOO", Songs: 1}) {
+		t.Errorf("Report(counts) = %v, expected reports for ALBUMN and FOO", reports)
+	}
+}
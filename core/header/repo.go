@@ -0,0 +1,235 @@
+package header
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/internal/dbutil"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// dbRepo is the main Repository implementation, backed by a PostgreSQL database.
+type dbRepo struct {
+	logger *slog.Logger
+	db     pgxutil.DB // database connection
+}
+
+// NewDBRepository creates a new Repository backed by the specified database connection.
+// db can be a single connection or a connection pool.
+func NewDBRepository(logger *slog.Logger, db pgxutil.DB) Repository {
+	return &dbRepo{logger, db}
+}
+
+// headerColumns selects the columns of a custom header h.
+const headerColumns = `h.uuid, h.created_at, h.updated_at, h.name, h.type, h.pattern, h.aliases`
+
+// headerRow is the data returned by a SELECT query for custom headers.
+type headerRow struct {
+	UUID      uuid.UUID
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	Name      string
+	Type      model.HeaderType
+	Pattern   string
+	Aliases   []string
+}
+
+// toModel converts r into an equivalent model.CustomHeader.
+func (r headerRow) toModel() model.CustomHeader {
+	return model.CustomHeader{
+		Model: model.Model{
+			UUID:      r.UUID,
+			CreatedAt: r.CreatedAt,
+			UpdatedAt: r.UpdatedAt,
+		},
+		Name:    r.Name,
+		Type:    r.Type,
+		Pattern: r.Pattern,
+		Aliases: r.Aliases,
+	}
+}
+
+// CreateHeader creates header in the database.
+func (r *dbRepo) CreateHeader(ctx context.Context, header *model.CustomHeader) error {
+	prepareHeader(header)
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if err := checkConflict(ctx, tx, 0, header); err != nil {
+			return err
+		}
+		id, err := pgxutil.InsertRowReturning(ctx, tx, "custom_headers", map[string]any{
+			"name":    header.Name,
+			"type":    header.Type,
+			"pattern": header.Pattern,
+			"aliases": header.Aliases,
+		}, "id", pgx.RowTo[int])
+		if err != nil {
+			return err
+		}
+		*header, err = getHeader(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		if err = dbutil.Error(err); !errors.Is(err, core.ErrConflict) {
+			r.logger.ErrorContext(ctx, "Could not create custom header.", "name", header.Name, tint.Err(err))
+		}
+		return err
+	}
+	return nil
+}
+
+// GetHeader fetches a single custom header from the database by its UUID.
+func (r *dbRepo) GetHeader(ctx context.Context, id uuid.UUID) (model.CustomHeader, error) {
+	row, err := pgxutil.SelectRow(ctx, r.db, `SELECT `+headerColumns+`
+	FROM custom_headers AS h
+	WHERE h.uuid = $1`, []any{id}, pgx.RowToStructByName[headerRow])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not fetch custom header.", "uuid", id, tint.Err(err))
+		}
+		return model.CustomHeader{}, dbutil.Error(err)
+	}
+	return row.toModel(), nil
+}
+
+// FindHeaders fetches multiple custom headers from the database, ordered by name.
+// The results are paginated with limit and offset.
+func (r *dbRepo) FindHeaders(ctx context.Context, limit int, offset int64) ([]model.CustomHeader, int64, error) {
+	total, err := pgxutil.SelectRow(ctx, r.db, `SELECT COUNT(*) FROM custom_headers`, nil, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not count custom headers.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, 0, err
+	}
+	headers, err := pgxutil.Select(ctx, r.db, `SELECT `+headerColumns+`
+	FROM custom_headers AS h
+	ORDER BY LOWER(h.name), h.id
+	LIMIT CASE WHEN $1 < 0 THEN NULL ELSE $1 END OFFSET $2`, []any{limit, offset}, func(row pgx.CollectableRow) (model.CustomHeader, error) {
+		data, err := pgx.RowToStructByName[headerRow](row)
+		return data.toModel(), err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list custom headers.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, total, err
+	}
+	return headers, total, nil
+}
+
+// UpdateHeader updates the custom header in the database with header.UUID.
+func (r *dbRepo) UpdateHeader(ctx context.Context, header *model.CustomHeader) error {
+	prepareHeader(header)
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		id, err := pgxutil.SelectRow(ctx, tx, `SELECT id FROM custom_headers WHERE uuid = $1`, []any{header.UUID}, pgx.RowTo[int])
+		if err != nil {
+			return err
+		}
+		if err = checkConflict(ctx, tx, id, header); err != nil {
+			return err
+		}
+		if _, err = tx.Exec(ctx, `UPDATE custom_headers SET name = $2, type = $3, pattern = $4, aliases = $5 WHERE id = $1`,
+			id, header.Name, header.Type, header.Pattern, header.Aliases); err != nil {
+			return err
+		}
+		*header, err = getHeader(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		if err = dbutil.Error(err); !errors.Is(err, core.ErrNotFound) && !errors.Is(err, core.ErrConflict) {
+			r.logger.ErrorContext(ctx, "Could not update custom header.", "uuid", header.UUID, tint.Err(err))
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteHeader deletes the custom header with the specified UUID.
+func (r *dbRepo) DeleteHeader(ctx context.Context, id uuid.UUID) (bool, error) {
+	_, err := pgxutil.ExecRow(ctx, r.db, `DELETE FROM custom_headers WHERE uuid = $1`, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not delete custom header.", "uuid", id, tint.Err(err))
+		return false, err
+	}
+	return true, nil
+}
+
+// CountCustomTags counts the custom tag keys of songs in the library.
+// Songs in uploads and in the trash are not counted.
+func (r *dbRepo) CountCustomTags(ctx context.Context) (map[string]int64, error) {
+	rows, err := pgxutil.Select(ctx, r.db, `SELECT k.key, COUNT(*) AS count
+	FROM songs AS s, JSONB_OBJECT_KEYS(s.extra) AS k(key)
+	WHERE s.upload_id IS NULL AND s.deleted_at IS NULL
+	GROUP BY k.key`, nil, pgx.RowToStructByName[struct {
+		Key   string
+		Count int64
+	}])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not count custom tags.", tint.Err(err))
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Key] = row.Count
+	}
+	return counts, nil
+}
+
+// getHeader fetches the custom header with the specified ID using db.
+func getHeader(ctx context.Context, db pgxutil.DB, id int) (model.CustomHeader, error) {
+	row, err := pgxutil.SelectRow(ctx, db, `SELECT `+headerColumns+`
+	FROM custom_headers AS h
+	WHERE h.id = $1`, []any{id}, pgx.RowToStructByName[headerRow])
+	return row.toModel(), err
+}
+
+// checkConflict returns core.ErrConflict if the name or an alias of header is used by a header other than the one with the specified ID.
+func checkConflict(ctx context.Context, db pgxutil.DB, id int, header *model.CustomHeader) error {
+	names := append([]string{header.Name}, header.Aliases...)
+	conflict, err := pgxutil.SelectRow(ctx, db, `SELECT EXISTS(SELECT 1 FROM custom_headers AS h
+		WHERE h.id <> $1 AND EXISTS(SELECT 1 FROM UNNEST(h.aliases || h.name) AS a(name), UNNEST($2::TEXT[]) AS n(name)
+			WHERE LOWER(a.name) = LOWER(n.name)))`,
+		[]any{id, names}, pgx.RowTo[bool])
+	if err != nil {
+		return err
+	}
+	if conflict {
+		return core.ErrConflict
+	}
+	return nil
+}
+
+// prepareHeader normalizes header.
+// Whitespace and a leading # are removed from the name and aliases.
+// Empty aliases and aliases that only differ from another alias or the name in case are removed.
+// An empty type is replaced by model.HeaderTypeString.
+func prepareHeader(header *model.CustomHeader) {
+	header.Name = normalizeKey(header.Name)
+	if header.Type == "" {
+		header.Type = model.HeaderTypeString
+	}
+	aliases := make([]string, 0, len(header.Aliases))
+	seen := map[string]bool{strings.ToLower(header.Name): true}
+	for _, alias := range header.Aliases {
+		alias = normalizeKey(alias)
+		if alias == "" || seen[strings.ToLower(alias)] {
+			continue
+		}
+		seen[strings.ToLower(alias)] = true
+		aliases = append(aliases, alias)
+	}
+	header.Aliases = aliases
+}
+
+// normalizeKey removes whitespace and a leading # from key.
+func normalizeKey(key string) string {
+	return strings.TrimPrefix(strings.TrimSpace(key), "#")
+}
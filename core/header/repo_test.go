@@ -0,0 +1,157 @@
+//go:build database
+
+package header
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func Test_dbRepo_CreateHeader(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	testdata.CustomHeader(t, db, "ALBUM", model.HeaderTypeString, "ALBUMN")
+
+	t.Run("success", func(t *testing.T) {
+		header := model.CustomHeader{Name: "#RATING", Type: model.HeaderTypeInt, Pattern: `^[1-5]$`}
+		if err := repo.CreateHeader(context.TODO(), &header); err != nil {
+			t.Fatalf("CreateHeader(ctx, &header) returned an unexpected error: %s", err)
+		}
+		if header.UUID == uuid.Nil {
+			t.Errorf("CreateHeader(ctx, &header) produced header.UUID = <uuid.Nil>, expected a valid UUID")
+		}
+		if header.Name != "RATING" {
+			t.Errorf("CreateHeader(ctx, &header) produced name %q, expected %q", header.Name, "RATING")
+		}
+	})
+	t.Run("conflict", func(t *testing.T) {
+		for _, name := range []string{"album", "Albumn"} {
+			header := model.CustomHeader{Name: name}
+			if err := repo.CreateHeader(context.TODO(), &header); !errors.Is(err, core.ErrConflict) {
+				t.Errorf("CreateHeader(ctx, &header) with name %q returned %v, expected ErrConflict", name, err)
+			}
+		}
+	})
+}
+
+func Test_dbRepo_GetHeader(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	album := testdata.CustomHeader(t, db, "ALBUM", model.HeaderTypeString, "ALBUMN")
+
+	actual, err := repo.GetHeader(context.TODO(), album.UUID)
+	if err != nil {
+		t.Fatalf("GetHeader(ctx, %q) returned an unexpected error: %s", album.UUID, err)
+	}
+	if actual.Name != album.Name || len(actual.Aliases) != 1 {
+		t.Errorf("GetHeader(ctx, %q) = %v, expected %v", album.UUID, actual, album)
+	}
+	if _, err = repo.GetHeader(context.TODO(), uuid.New()); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("GetHeader(ctx, uuid.New()) returned %v, expected ErrNotFound", err)
+	}
+}
+
+func Test_dbRepo_FindHeaders(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	testdata.CustomHeader(t, db, "RATING", model.HeaderTypeInt)
+	testdata.CustomHeader(t, db, "ALBUM", model.HeaderTypeString)
+
+	headers, total, err := repo.FindHeaders(context.TODO(), 1, 0)
+	if err != nil {
+		t.Fatalf("FindHeaders(ctx, 1, 0) returned an unexpected error: %s", err)
+	}
+	if total != 2 || len(headers) != 1 || headers[0].Name != "ALBUM" {
+		t.Errorf("FindHeaders(ctx, 1, 0) = %v, %d, expected [ALBUM], 2", headers, total)
+	}
+}
+
+func Test_dbRepo_UpdateHeader(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	testdata.CustomHeader(t, db, "ALBUM", model.HeaderTypeString)
+	rating := testdata.CustomHeader(t, db, "RATING", model.HeaderTypeString)
+
+	t.Run("success", func(t *testing.T) {
+		header := rating
+		header.Type = model.HeaderTypeInt
+		header.Aliases = []string{"SCORE"}
+		if err := repo.UpdateHeader(context.TODO(), &header); err != nil {
+			t.Fatalf("UpdateHeader(ctx, &header) returned an unexpected error: %s", err)
+		}
+		if header.Type != model.HeaderTypeInt || len(header.Aliases) != 1 {
+			t.Errorf("UpdateHeader(ctx, &header) produced %v, expected type int and alias SCORE", header)
+		}
+	})
+	t.Run("conflict", func(t *testing.T) {
+		header := rating
+		header.Aliases = []string{"album"}
+		if err := repo.UpdateHeader(context.TODO(), &header); !errors.Is(err, core.ErrConflict) {
+			t.Errorf("UpdateHeader(ctx, &header) returned %v, expected ErrConflict", err)
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		header := model.CustomHeader{Name: "FOO"}
+		header.UUID = uuid.New()
+		if err := repo.UpdateHeader(context.TODO(), &header); !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("UpdateHeader(ctx, &header) returned %v, expected ErrNotFound", err)
+		}
+	})
+}
+
+func Test_dbRepo_DeleteHeader(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	album := testdata.CustomHeader(t, db, "ALBUM", model.HeaderTypeString)
+
+	ok, err := repo.DeleteHeader(context.TODO(), album.UUID)
+	if err != nil {
+		t.Fatalf("DeleteHeader(ctx, %q) returned an unexpected error: %s", album.UUID, err)
+	}
+	if !ok {
+		t.Errorf("DeleteHeader(ctx, %q) = false, expected true", album.UUID)
+	}
+	if ok, _ = repo.DeleteHeader(context.TODO(), album.UUID); ok {
+		t.Errorf("DeleteHeader(ctx, %q) a second time = true, expected false", album.UUID)
+	}
+}
+
+func Test_dbRepo_CountCustomTags(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	for _, extra := range []string{`{"ALBUM": "Hits"}`, `{"ALBUMN": "Hits", "ALBUM": "Hits"}`} {
+		song := testdata.SimpleSong(t, db)
+		if _, err := db.Exec(context.TODO(), `UPDATE songs SET extra = $2::JSONB WHERE uuid = $1`, song.UUID, extra); err != nil {
+			t.Fatalf("could not set custom tags: %s", err)
+		}
+	}
+
+	counts, err := repo.CountCustomTags(context.TODO())
+	if err != nil {
+		t.Fatalf("CountCustomTags(ctx) returned an unexpected error: %s", err)
+	}
+	if counts["ALBUM"] != 2 || counts["ALBUMN"] != 1 {
+		t.Errorf("CountCustomTags(ctx) = %v, expected ALBUM: 2 and ALBUMN: 1", counts)
+	}
+}
@@ -4,13 +4,21 @@ import (
 	"errors"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 
 	"github.com/Karaoke-Manager/karman/core"
 )
 
+// uniqueViolation is the PostgreSQL error code for violations of unique constraints.
+const uniqueViolation = "23505"
+
 func Error(err error) error {
 	if errors.Is(err, pgx.ErrNoRows) {
 		return core.ErrNotFound
 	}
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		return core.ErrConflict
+	}
 	return err
 }
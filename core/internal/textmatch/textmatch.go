@@ -0,0 +1,88 @@
+// Package textmatch implements fuzzy comparison of artists, titles and other texts of songs.
+package textmatch
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// featuring contains the separators that introduce featured artists.
+var featuring = []string{" feat. ", " feat ", " ft. ", " ft ", " featuring "}
+
+// Normalize converts s into a form that is suitable for comparing artists and titles.
+// The result contains only lowercase letters and digits.
+func Normalize(s string) string {
+	s = strings.ToLower(s)
+	s = removeBrackets(s)
+	for _, sep := range featuring {
+		if i := strings.Index(s, sep); i >= 0 {
+			s = s[:i]
+		}
+	}
+	s = strings.ReplaceAll(s, "&", " and ")
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "the ")
+
+	var b strings.Builder
+	for _, r := range norm.NFD.String(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// removeBrackets removes all text in parentheses and square brackets from s.
+// If the whole string is bracketed, s is returned unchanged.
+func removeBrackets(s string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range s {
+		switch {
+		case r == '(' || r == '[':
+			depth++
+		case (r == ')' || r == ']') && depth > 0:
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	if strings.TrimSpace(b.String()) == "" {
+		return s
+	}
+	return b.String()
+}
+
+// Similarity returns a value between 0 and 1 that indicates how similar a and b are.
+// The similarity is based on the Levenshtein distance of a and b relative to the length of the longer string.
+func Similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein computes the Levenshtein distance between a and b.
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := range a {
+		curr[0] = i + 1
+		for j := range b {
+			cost := 1
+			if a[i] == b[j] {
+				cost = 0
+			}
+			curr[j+1] = min(prev[j+1]+1, curr[j]+1, prev[j]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,47 @@
+package textmatch
+
+import (
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]struct {
+		v        string
+		expected string
+	}{
+		"case":          {"Bohemian Rhapsody", "bohemianrhapsody"},
+		"punctuation":   {"Don't Stop Me Now!", "dontstopmenow"},
+		"diacritics":    {"Beyoncé", "beyonce"},
+		"brackets":      {"Hello (Radio Edit) [Live]", "hello"},
+		"only brackets": {"(Untitled)", "untitled"},
+		"featuring":     {"Eminem feat. Rihanna", "eminem"},
+		"ampersand":     {"Simon & Garfunkel", "simonandgarfunkel"},
+		"article":       {"The Beatles", "beatles"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if actual := Normalize(c.v); actual != c.expected {
+				t.Errorf("Normalize(%q) = %q, expected %q", c.v, actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	cases := map[string]struct {
+		a, b     string
+		expected float64
+	}{
+		"equal":     {"queen", "queen", 1},
+		"empty":     {"", "", 1},
+		"different": {"abc", "xyz", 0},
+		"typo":      {"bohemianrhapsody", "bohemianrapsody", 0.9375},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if actual := Similarity(c.a, c.b); actual != c.expected {
+				t.Errorf("Similarity(%q, %q) = %v, expected %v", c.a, c.b, actual, c.expected)
+			}
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/tcolgate/mp3" // MP3 support
 
 	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/event"
 	"github.com/Karaoke-Manager/karman/model"
 	"github.com/Karaoke-Manager/karman/pkg/mediatype"
 	"github.com/Karaoke-Manager/karman/pkg/streamio"
@@ -30,13 +31,15 @@ type service struct {
 	logger *slog.Logger
 	repo   Repository
 	store  Store
+	events event.Bus
 }
 
 // NewService creates a new Service instance using the supplied repo and store.
 // The default implementation will store media files in the store as well as in the DB.
 // For each media file there will be an entry in the DB, the actual data however lives in the store.
-func NewService(logger *slog.Logger, repo Repository, store Store) Service {
-	return &service{logger, repo, store}
+// Events about created and deleted files are published to events.
+func NewService(logger *slog.Logger, repo Repository, store Store, events event.Bus) Service {
+	return &service{logger, repo, store, events}
 }
 
 // StoreFile creates a new entity.File in the database and then saves the data from r into the store.
@@ -88,6 +91,7 @@ func (s *service) StoreFile(ctx context.Context, mediaType mediatype.MediaType,
 	if err = s.repo.UpdateFile(ctx, &file); err != nil {
 		return
 	}
+	s.publish(ctx, event.MediaCreated(file))
 	return file, nil
 }
 
@@ -203,6 +207,17 @@ func (s *service) DeleteFile(ctx context.Context, id uuid.UUID) error {
 			return err
 		}
 	}
-	_, err = s.repo.DeleteFile(ctx, file.UUID)
+	ok, err := s.repo.DeleteFile(ctx, file.UUID)
+	if ok {
+		s.publish(ctx, event.MediaDeleted(file.UUID))
+	}
 	return err
 }
+
+// publish sends e to the event bus.
+// Events are informational only, so errors are logged but otherwise ignored.
+func (s *service) publish(ctx context.Context, e event.Event) {
+	if err := s.events.Publish(ctx, e); err != nil {
+		s.logger.WarnContext(ctx, "Could not publish event.", "type", e.Type, tint.Err(err))
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/event"
 	"github.com/Karaoke-Manager/karman/model"
 	"github.com/Karaoke-Manager/karman/pkg/mediatype"
 	"github.com/Karaoke-Manager/karman/pkg/nolog"
@@ -23,7 +24,7 @@ func TestService_StoreFile(t *testing.T) {
 	t.Parallel()
 
 	store, _ := fileStore(t)
-	svc := NewService(nolog.Logger, NewFakeRepository(), store)
+	svc := NewService(nolog.Logger, NewFakeRepository(), store, event.NewMemBus())
 
 	// in order to not blow up repository size we download the test data on the fly.
 	cases := map[string]struct {
@@ -75,7 +76,7 @@ func TestService_DeleteFile(t *testing.T) {
 
 	store := NewMemStore()
 	repo := NewFakeRepository().(*fakeRepo)
-	svc := NewService(nolog.Logger, repo, store)
+	svc := NewService(nolog.Logger, repo, store, event.NewMemBus())
 
 	id := uuid.New()
 	w, _ := store.Create(context.TODO(), mediatype.Nil, id)
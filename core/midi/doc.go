@@ -0,0 +1,6 @@
+// Package midi converts MIDI karaoke files into UltraStar songs.
+// Standard MIDI Files (.mid) and MIDI karaoke files (.kar) are supported as long as they contain lyrics.
+//
+// The conversion is a best effort:
+// Notes are quantized to sixteenth notes and the result usually needs some manual refinement.
+package midi
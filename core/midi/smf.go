@@ -0,0 +1,246 @@
+package midi
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// These errors are returned when a Standard MIDI File cannot be parsed.
+var (
+	ErrInvalidHeader = errors.New("not a standard MIDI file")
+	ErrSMPTE         = errors.New("SMPTE time division is not supported")
+	ErrInvalidTrack  = errors.New("invalid track data")
+)
+
+// MIDI meta event types.
+const (
+	metaText      = 0x01
+	metaTrackName = 0x03
+	metaLyric     = 0x05
+	metaEndTrack  = 0x2F
+	metaTempo     = 0x51
+)
+
+// file is a parsed Standard MIDI File.
+// Only the information relevant for karaoke conversion is kept.
+type file struct {
+	// division is the number of ticks per quarter note.
+	division int
+	tracks   []track
+}
+
+// track is a single track of a file.
+// All times are absolute times in ticks.
+type track struct {
+	name   string
+	notes  []note
+	texts  []textEvent // lyric and text meta events
+	tempos []tempoEvent
+}
+
+// note is a single note of a track.
+type note struct {
+	channel byte
+	key     byte
+	start   int64
+	end     int64
+}
+
+// textEvent is a lyric or text meta event.
+type textEvent struct {
+	tick  int64
+	lyric bool // lyric meta event (as opposed to a text event)
+	text  string
+}
+
+// tempoEvent is a tempo change.
+type tempoEvent struct {
+	tick int64
+	// usPerQuarter is the duration of a quarter note in microseconds.
+	usPerQuarter int
+}
+
+// maxChunkSize is the maximum size of a chunk in a Standard MIDI File.
+// Karaoke files are usually much smaller, larger chunks are rejected to limit memory usage.
+const maxChunkSize = 16 << 20
+
+// readFile parses a Standard MIDI File from r.
+func readFile(r io.Reader) (*file, error) {
+	br := bufio.NewReader(r)
+	id, data, err := readChunk(br)
+	if err != nil || id != "MThd" || len(data) < 6 {
+		return nil, ErrInvalidHeader
+	}
+	ntracks := int(binary.BigEndian.Uint16(data[2:4]))
+	division := binary.BigEndian.Uint16(data[4:6])
+	if division&0x8000 != 0 {
+		return nil, ErrSMPTE
+	}
+	if division == 0 {
+		return nil, ErrInvalidHeader
+	}
+	f := &file{division: int(division), tracks: make([]track, 0, min(ntracks, 64))}
+	for len(f.tracks) < ntracks {
+		id, data, err = readChunk(br)
+		if errors.Is(err, io.EOF) {
+			// some files specify more tracks than they contain
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if id != "MTrk" {
+			// unknown chunks must be ignored
+			continue
+		}
+		t, err := parseTrack(data)
+		if err != nil {
+			return nil, fmt.Errorf("track %d: %w", len(f.tracks), err)
+		}
+		f.tracks = append(f.tracks, t)
+	}
+	return f, nil
+}
+
+// readChunk reads the next chunk from r and returns its type and data.
+// The length of the chunk is not trusted,
+// memory is only allocated for data that is actually present in r.
+func readChunk(r io.Reader) (string, []byte, error) {
+	var header [8]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return "", nil, err
+	}
+	size := int64(binary.BigEndian.Uint32(header[4:]))
+	if size > maxChunkSize {
+		return "", nil, ErrInvalidTrack
+	}
+	data, err := io.ReadAll(io.LimitReader(r, size))
+	if err != nil {
+		return "", nil, err
+	}
+	if int64(len(data)) < size {
+		return "", nil, ErrInvalidTrack
+	}
+	return string(header[:4]), data, nil
+}
+
+// parseTrack parses the events of a track chunk.
+// Notes that are never released end at the end of the track.
+func parseTrack(data []byte) (track, error) {
+	var t track
+	var tick int64
+	var status byte
+	open := make(map[[2]byte]int) // index in t.notes by channel and key
+	pos := 0
+	readVarInt := func() (int, error) {
+		n := 0
+		for i := 0; i < 4; i++ {
+			if pos >= len(data) {
+				return 0, ErrInvalidTrack
+			}
+			b := data[pos]
+			pos++
+			n = n<<7 | int(b&0x7F)
+			if b&0x80 == 0 {
+				return n, nil
+			}
+		}
+		return 0, ErrInvalidTrack
+	}
+	release := func(channel, key byte) {
+		if i, ok := open[[2]byte{channel, key}]; ok {
+			t.notes[i].end = tick
+			delete(open, [2]byte{channel, key})
+		}
+	}
+
+	for pos < len(data) {
+		delta, err := readVarInt()
+		if err != nil {
+			return t, err
+		}
+		tick += int64(delta)
+		if pos >= len(data) {
+			return t, ErrInvalidTrack
+		}
+		if data[pos]&0x80 != 0 {
+			status = data[pos]
+			pos++
+		} else if status == 0 {
+			// running status without a preceding status byte
+			return t, ErrInvalidTrack
+		}
+
+		switch {
+		case status == 0xFF:
+			if pos >= len(data) {
+				return t, ErrInvalidTrack
+			}
+			typ := data[pos]
+			pos++
+			n, err := readVarInt()
+			if err != nil || pos+n > len(data) {
+				return t, ErrInvalidTrack
+			}
+			payload := data[pos : pos+n]
+			pos += n
+			switch typ {
+			case metaText, metaLyric:
+				t.texts = append(t.texts, textEvent{tick, typ == metaLyric, string(payload)})
+			case metaTrackName:
+				if t.name == "" {
+					t.name = string(payload)
+				}
+			case metaTempo:
+				if len(payload) == 3 {
+					tempo := int(payload[0])<<16 | int(payload[1])<<8 | int(payload[2])
+					if tempo == 0 {
+						return t, ErrInvalidTrack
+					}
+					t.tempos = append(t.tempos, tempoEvent{tick, tempo})
+				}
+			case metaEndTrack:
+				pos = len(data)
+			}
+			// meta events cancel running status
+			status = 0
+		case status == 0xF0 || status == 0xF7:
+			n, err := readVarInt()
+			if err != nil || pos+n > len(data) {
+				return t, ErrInvalidTrack
+			}
+			pos += n
+			status = 0
+		case status >= 0xF0:
+			// other system messages are not valid in files
+			return t, ErrInvalidTrack
+		default:
+			size := 2
+			if kind := status & 0xF0; kind == 0xC0 || kind == 0xD0 {
+				size = 1
+			}
+			if pos+size > len(data) {
+				return t, ErrInvalidTrack
+			}
+			args := data[pos : pos+size]
+			pos += size
+			channel := status & 0x0F
+			switch status & 0xF0 {
+			case 0x90:
+				release(channel, args[0])
+				if args[1] > 0 {
+					open[[2]byte{channel, args[0]}] = len(t.notes)
+					t.notes = append(t.notes, note{channel: channel, key: args[0], start: tick, end: -1})
+				}
+			case 0x80:
+				release(channel, args[0])
+			}
+		}
+	}
+	for _, i := range open {
+		t.notes[i].end = tick
+	}
+	return t, nil
+}
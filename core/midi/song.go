@@ -0,0 +1,266 @@
+package midi
+
+import (
+	"cmp"
+	"errors"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// These errors are returned if a MIDI file does not contain the data required for a karaoke song.
+var (
+	ErrNoLyrics = errors.New("file contains no lyrics")
+	ErrNoVocals = errors.New("file contains no vocal track")
+)
+
+const (
+	// defaultTempo is the tempo of a MIDI file without tempo events in microseconds per quarter note (120 BPM).
+	defaultTempo = 500000
+	// beatsPerQuarter is the resolution of the generated notes.
+	// Each UltraStar beat corresponds to a sixteenth note of the MIDI file.
+	beatsPerQuarter = 4
+	// drumChannel is the MIDI channel that is reserved for percussion.
+	drumChannel = 9
+	// middleC is the MIDI key of the middle C, which is pitch 0 in UltraStar.
+	middleC = 60
+)
+
+// ReadSong converts the Standard MIDI File in r into a song.
+// The lyrics are taken from lyric meta events or, for .kar files, from text events.
+// The vocal track is the track whose notes best match the timing of the lyrics.
+// The BPM of the song is derived from the initial tempo of the file,
+// the GAP is the time of the first vocal note.
+// Tempo changes are respected when converting times into beats.
+//
+// Lines of lyrics are separated according to the conventions of .kar files
+// or by line breaks in lyric events.
+// If the lyrics contain no line separators, lines are split at longer pauses.
+func ReadSong(r io.Reader) (model.Song, error) {
+	f, err := readFile(r)
+	if err != nil {
+		return model.Song{}, err
+	}
+	syllables := f.syllables()
+	if len(syllables) == 0 {
+		return model.Song{}, ErrNoLyrics
+	}
+	tolerance := int64(f.division / 8)
+	vocals := f.vocalNotes(syllables, tolerance)
+	if len(vocals) == 0 {
+		return model.Song{}, ErrNoVocals
+	}
+
+	tempos := f.tempos()
+	bpm := ultrastar.BPM(float64(time.Minute/time.Microsecond) / float64(tempos[0].usPerQuarter) * beatsPerQuarter)
+	song := model.Song{Song: ultrastar.Song{BPM: bpm}}
+	f.metadata(&song)
+	song.Gap = f.time(tempos, vocals[0].start)
+	beat := func(tick int64) ultrastar.Beat {
+		d := f.time(tempos, tick) - song.Gap
+		return ultrastar.Beat(math.Round(float64(d) / float64(bpm.Duration(1))))
+	}
+	song.NotesP1 = f.notes(syllables, vocals, tolerance, beat)
+	return song, nil
+}
+
+// syllable is a single syllable of the lyrics.
+type syllable struct {
+	tick    int64
+	text    string
+	newLine bool // the syllable starts a new line
+}
+
+// syllables extracts the lyrics of f.
+// Lyric meta events are preferred over text events.
+// If multiple tracks contain lyrics, the track with the most events is used.
+func (f *file) syllables() []syllable {
+	var best []textEvent
+	for _, lyric := range []bool{true, false} {
+		for _, t := range f.tracks {
+			events := slices.DeleteFunc(slices.Clone(t.texts), func(e textEvent) bool {
+				return e.lyric != lyric || strings.HasPrefix(e.text, "@")
+			})
+			if len(events) > len(best) {
+				best = events
+			}
+		}
+		if len(best) > 0 {
+			break
+		}
+	}
+
+	syllables := make([]syllable, 0, len(best))
+	newLine, markers := false, false
+	for _, e := range best {
+		text := e.text
+		if strings.HasPrefix(text, "/") || strings.HasPrefix(text, "\\") {
+			newLine, markers = true, true
+			text = text[1:]
+		}
+		trimmed := strings.TrimRight(text, "\r\n")
+		lineEnd := len(trimmed) < len(text)
+		trimmed = strings.TrimLeft(trimmed, "\r\n")
+		if trimmed != "" {
+			syllables = append(syllables, syllable{e.tick, trimmed, newLine && len(syllables) > 0})
+			newLine = false
+		}
+		if lineEnd {
+			newLine, markers = true, true
+		}
+	}
+	if !markers {
+		// split lines at pauses of at least two quarter notes
+		for i := 1; i < len(syllables); i++ {
+			syllables[i].newLine = syllables[i].tick-syllables[i-1].tick >= int64(2*f.division)
+		}
+	}
+	return syllables
+}
+
+// vocalNotes selects the notes of the vocal track of f.
+// The vocal track is the track with the most notes starting at the same time as a syllable.
+// The returned notes are monophonic: if multiple notes overlap, only the first (or highest) note is kept.
+func (f *file) vocalNotes(syllables []syllable, tolerance int64) []note {
+	var best []note
+	bestScore := 0
+	for _, t := range f.tracks {
+		notes := slices.DeleteFunc(slices.Clone(t.notes), func(n note) bool { return n.channel == drumChannel })
+		slices.SortStableFunc(notes, func(a, b note) int {
+			if a.start != b.start {
+				return cmp.Compare(a.start, b.start)
+			}
+			return cmp.Compare(b.key, a.key)
+		})
+		mono := make([]note, 0, len(notes))
+		for _, n := range notes {
+			if len(mono) == 0 || n.start >= mono[len(mono)-1].end {
+				mono = append(mono, n)
+			}
+		}
+		score := 0
+		for _, s := range syllables {
+			i, _ := slices.BinarySearchFunc(mono, s.tick-tolerance, func(n note, tick int64) int { return cmp.Compare(n.start, tick) })
+			if i < len(mono) && mono[i].start <= s.tick+tolerance {
+				score++
+			}
+		}
+		if score > bestScore {
+			best, bestScore = mono, score
+		}
+	}
+	return best
+}
+
+// notes merges syllables and vocal notes into UltraStar notes.
+// Each syllable is assigned to the vocal note starting at the same time.
+// Vocal notes without a syllable that directly follow a note of the same line are sung as a continuation ("~").
+// Syllables without a vocal note become freestyle notes.
+func (f *file) notes(syllables []syllable, vocals []note, tolerance int64, beat func(int64) ultrastar.Beat) ultrastar.Notes {
+	notes := make(ultrastar.Notes, 0, len(vocals)+len(syllables))
+	var pitch ultrastar.Pitch
+	add := func(n ultrastar.Note, newLine bool) {
+		if len(notes) > 0 {
+			prev := &notes[len(notes)-1]
+			if prev.Start+prev.Duration > n.Start {
+				prev.Duration = max(n.Start-prev.Start, 1)
+			}
+			n.Start = max(n.Start, prev.Start+prev.Duration)
+			if newLine {
+				notes = append(notes, ultrastar.Note{Type: ultrastar.NoteTypeLineBreak, Start: prev.Start + prev.Duration})
+			}
+		}
+		n.Duration = max(n.Duration, 1)
+		notes = append(notes, n)
+	}
+
+	v := 0
+	for i, s := range syllables {
+		// skip notes before the syllable
+		for v < len(vocals) && vocals[v].start < s.tick-tolerance {
+			v++
+		}
+		next := int64(math.MaxInt64)
+		if i+1 < len(syllables) {
+			next = syllables[i+1].tick - tolerance
+		}
+		if v >= len(vocals) || vocals[v].start > s.tick+tolerance {
+			end := min(next+tolerance, s.tick+int64(f.division))
+			add(ultrastar.Note{Type: ultrastar.NoteTypeFreestyle, Start: beat(s.tick), Duration: beat(end) - beat(s.tick), Pitch: pitch, Text: s.text}, s.newLine)
+			continue
+		}
+		n := vocals[v]
+		pitch = ultrastar.Pitch(int(n.key) - middleC)
+		add(ultrastar.Note{Type: ultrastar.NoteTypeRegular, Start: beat(n.start), Duration: beat(n.end) - beat(n.start), Pitch: pitch, Text: s.text}, s.newLine)
+		end := n.end
+		// melismas
+		for v++; v < len(vocals) && vocals[v].start < next && vocals[v].start-end < int64(f.division); v++ {
+			n = vocals[v]
+			pitch = ultrastar.Pitch(int(n.key) - middleC)
+			add(ultrastar.Note{Type: ultrastar.NoteTypeRegular, Start: beat(n.start), Duration: beat(n.end) - beat(n.start), Pitch: pitch, Text: "~"}, false)
+			end = n.end
+		}
+	}
+	return notes
+}
+
+// tempos returns the tempo changes of f ordered by time.
+// The first tempo change is always at tick 0.
+func (f *file) tempos() []tempoEvent {
+	var tempos []tempoEvent
+	for _, t := range f.tracks {
+		tempos = append(tempos, t.tempos...)
+	}
+	slices.SortStableFunc(tempos, func(a, b tempoEvent) int { return cmp.Compare(a.tick, b.tick) })
+	if len(tempos) == 0 || tempos[0].tick > 0 {
+		tempos = slices.Insert(tempos, 0, tempoEvent{0, defaultTempo})
+	}
+	return tempos
+}
+
+// time converts tick into a duration from the start of f, respecting all tempo changes.
+func (f *file) time(tempos []tempoEvent, tick int64) time.Duration {
+	var us float64
+	for i, t := range tempos {
+		if t.tick >= tick {
+			break
+		}
+		end := tick
+		if i+1 < len(tempos) {
+			end = min(end, tempos[i+1].tick)
+		}
+		us += float64(end-t.tick) * float64(t.usPerQuarter) / float64(f.division)
+	}
+	return time.Duration(math.Round(us)) * time.Microsecond
+}
+
+// metadata sets the title, artist and language of song from f.
+// The metadata is taken from the @T and @L text events of .kar files.
+// If no title is specified, the name of the first track is used.
+func (f *file) metadata(song *model.Song) {
+	var titles []string
+	for _, t := range f.tracks {
+		for _, e := range t.texts {
+			switch {
+			case strings.HasPrefix(e.text, "@T"):
+				titles = append(titles, strings.TrimSpace(e.text[2:]))
+			case strings.HasPrefix(e.text, "@L") && song.Language == "":
+				song.Language = strings.TrimSpace(e.text[2:])
+			}
+		}
+	}
+	if len(titles) > 0 {
+		song.Title = titles[0]
+	} else if len(f.tracks) > 0 {
+		song.Title = strings.TrimSpace(f.tracks[0].name)
+	}
+	if len(titles) > 1 {
+		song.Artist = titles[1]
+	}
+}
@@ -0,0 +1,154 @@
+package midi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"codello.dev/ultrastar"
+)
+
+// event is a MIDI event used to build test files.
+type event struct {
+	delta int
+	data  []byte
+}
+
+// meta returns a meta event of the specified type.
+func meta(delta int, typ byte, text string) event {
+	return event{delta, append([]byte{0xFF, typ, byte(len(text))}, text...)}
+}
+
+// smf encodes a format 1 Standard MIDI File with 480 ticks per quarter note.
+func smf(tracks ...[]event) []byte {
+	b := &bytes.Buffer{}
+	b.WriteString("MThd")
+	_ = binary.Write(b, binary.BigEndian, []uint32{6})
+	_ = binary.Write(b, binary.BigEndian, []uint16{1, uint16(len(tracks)), 480})
+	for _, events := range tracks {
+		var data []byte
+		for _, e := range events {
+			data = append(data, byte(e.delta>>21&0x7F|0x80), byte(e.delta>>14&0x7F|0x80), byte(e.delta>>7&0x7F|0x80), byte(e.delta&0x7F))
+			data = append(data, e.data...)
+		}
+		data = append(data, 0x00, 0xFF, metaEndTrack, 0x00)
+		b.WriteString("MTrk")
+		_ = binary.Write(b, binary.BigEndian, uint32(len(data)))
+		b.Write(data)
+	}
+	return b.Bytes()
+}
+
+// conductor is a conductor track setting the tempo to 120 BPM.
+var conductor = []event{
+	meta(0, metaTrackName, "Track Name"),
+	{0, []byte{0xFF, metaTempo, 3, 0x07, 0xA1, 0x20}},
+}
+
+// expectedNotes are the notes that result from the test files.
+var expectedNotes = ultrastar.Notes{
+	{Type: ultrastar.NoteTypeRegular, Start: 0, Duration: 2, Pitch: 4, Text: "Hel"},
+	{Type: ultrastar.NoteTypeRegular, Start: 2, Duration: 2, Pitch: 2, Text: "lo "},
+	{Type: ultrastar.NoteTypeLineBreak, Start: 4},
+	{Type: ultrastar.NoteTypeRegular, Start: 6, Duration: 4, Pitch: 0, Text: "world"},
+	{Type: ultrastar.NoteTypeRegular, Start: 10, Duration: 2, Pitch: -1, Text: "~"},
+}
+
+func TestReadSong(t *testing.T) {
+	t.Parallel()
+
+	t.Run("lyrics", func(t *testing.T) {
+		data := smf(conductor, []event{
+			meta(480, metaLyric, "Hel"),
+			{0, []byte{0x90, 64, 100}},
+			meta(240, metaLyric, "lo \r"),
+			{0, []byte{0x80, 64, 0}},
+			{0, []byte{0x90, 62, 100}},
+			{240, []byte{62, 0}}, // running status
+			meta(240, metaLyric, "world"),
+			{0, []byte{0x90, 60, 100}},
+			{480, []byte{0x80, 60, 0}},
+			{0, []byte{0x90, 59, 100}},
+			{240, []byte{0x80, 59, 0}},
+		}, []event{
+			{480, []byte{0x99, 36, 100}}, // drums
+			{240, []byte{0x89, 36, 0}},
+		})
+		song, err := ReadSong(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("ReadSong() returned an unexpected error: %s", err)
+		}
+		if song.BPM != 480 || song.Gap != 500*time.Millisecond || song.Title != "Track Name" {
+			t.Errorf("ReadSong() returned BPM %f, GAP %s and title %q, expected 480, %s and %q", song.BPM, song.Gap, song.Title, 500*time.Millisecond, "Track Name")
+		}
+		if !slices.Equal(song.NotesP1, expectedNotes) {
+			t.Errorf("ReadSong() returned notes %v, expected %v", song.NotesP1, expectedNotes)
+		}
+	})
+
+	t.Run("kar", func(t *testing.T) {
+		data := smf(conductor, []event{
+			meta(0, metaText, "@KMIDI KARAOKE FILE"),
+			meta(0, metaText, "@TSong"),
+			meta(0, metaText, "@TArtist"),
+			meta(480, metaText, "\\Hel"),
+			meta(240, metaText, "lo "),
+			meta(480, metaText, "/world"),
+		}, []event{
+			{480, []byte{0x91, 64, 100}},
+			{240, []byte{0x91, 64, 0}},
+			{0, []byte{0x91, 62, 100}},
+			{240, []byte{0x81, 62, 0}},
+			{240, []byte{0x91, 60, 100}},
+			{480, []byte{0x81, 60, 0}},
+			{0, []byte{0x91, 59, 100}},
+			{240, []byte{0x81, 59, 0}},
+		})
+		song, err := ReadSong(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("ReadSong() returned an unexpected error: %s", err)
+		}
+		if song.Title != "Song" || song.Artist != "Artist" {
+			t.Errorf("ReadSong() returned title %q and artist %q, expected %q and %q", song.Title, song.Artist, "Song", "Artist")
+		}
+		if !slices.Equal(song.NotesP1, expectedNotes) {
+			t.Errorf("ReadSong() returned notes %v, expected %v", song.NotesP1, expectedNotes)
+		}
+	})
+
+	t.Run("no lyrics", func(t *testing.T) {
+		data := smf(conductor, []event{{0, []byte{0x90, 60, 100}}, {480, []byte{0x80, 60, 0}}})
+		if _, err := ReadSong(bytes.NewReader(data)); !errors.Is(err, ErrNoLyrics) {
+			t.Errorf("ReadSong() returned error %v, expected ErrNoLyrics", err)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		if _, err := ReadSong(bytes.NewReader([]byte("#TITLE:Song\n"))); !errors.Is(err, ErrInvalidHeader) {
+			t.Errorf("ReadSong() returned error %v, expected ErrInvalidHeader", err)
+		}
+	})
+
+	t.Run("truncated chunk", func(t *testing.T) {
+		// The track claims to be 4 GiB long but contains no data.
+		data := append(smf(), "MTrk\xff\xff\xff\xff"...)
+		data[11] = 1 // one track
+		if _, err := ReadSong(bytes.NewReader(data)); !errors.Is(err, ErrInvalidTrack) {
+			t.Errorf("ReadSong() returned error %v, expected ErrInvalidTrack", err)
+		}
+	})
+
+	t.Run("zero tempo", func(t *testing.T) {
+		data := smf([]event{{0, []byte{0xFF, metaTempo, 3, 0, 0, 0}}}, []event{
+			meta(0, metaLyric, "Hel"),
+			{0, []byte{0x90, 64, 100}},
+			{480, []byte{0x80, 64, 0}},
+		})
+		if _, err := ReadSong(bytes.NewReader(data)); !errors.Is(err, ErrInvalidTrack) {
+			t.Errorf("ReadSong() returned error %v, expected ErrInvalidTrack", err)
+		}
+	})
+}
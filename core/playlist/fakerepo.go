@@ -0,0 +1,88 @@
+package playlist
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// fakeRepo is a simple implementation of Repository that can be used for testing.
+// The fake repository does not know about songs, so songs are never removed from playlists.
+type fakeRepo struct {
+	// playlists is the "database" of a fakeRepo.
+	playlists map[uuid.UUID]model.Playlist
+}
+
+// NewFakeRepository returns a new Repository implementation backed by an in-memory map.
+func NewFakeRepository() Repository {
+	return &fakeRepo{make(map[uuid.UUID]model.Playlist)}
+}
+
+// CreatePlaylist stores the playlist and sets its UUID, CreatedAt, and UpdatedAt fields.
+func (r *fakeRepo) CreatePlaylist(_ context.Context, playlist *model.Playlist) error {
+	preparePlaylist(playlist)
+	playlist.UUID = uuid.New()
+	playlist.CreatedAt = time.Now()
+	playlist.UpdatedAt = playlist.CreatedAt
+	playlist.Songs = slices.Clone(playlist.Songs)
+	r.playlists[playlist.UUID] = *playlist
+	return nil
+}
+
+// GetPlaylist looks up the playlist with the specified UUID.
+func (r *fakeRepo) GetPlaylist(_ context.Context, id uuid.UUID) (model.Playlist, error) {
+	playlist, ok := r.playlists[id]
+	if !ok {
+		return model.Playlist{}, core.ErrNotFound
+	}
+	playlist.Songs = slices.Clone(playlist.Songs)
+	return playlist, nil
+}
+
+// FindPlaylists returns a list of playlists ordered by name, limited by the specified pagination parameters.
+func (r *fakeRepo) FindPlaylists(_ context.Context, limit int, offset int64) ([]model.Playlist, int64, error) {
+	if limit < 0 {
+		limit = math.MaxInt
+	}
+	playlists := make([]model.Playlist, 0, len(r.playlists))
+	for _, playlist := range r.playlists {
+		playlists = append(playlists, playlist)
+	}
+	slices.SortFunc(playlists, func(a, b model.Playlist) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	total := int64(len(playlists))
+	if offset > total {
+		offset = total
+	}
+	playlists = playlists[offset:]
+	return playlists[:min(limit, len(playlists))], total, nil
+}
+
+// UpdatePlaylist updates the name, description and songs of playlist.
+func (r *fakeRepo) UpdatePlaylist(_ context.Context, playlist *model.Playlist) error {
+	if _, ok := r.playlists[playlist.UUID]; !ok {
+		return core.ErrNotFound
+	}
+	preparePlaylist(playlist)
+	playlist.UpdatedAt = time.Now()
+	playlist.Songs = slices.Clone(playlist.Songs)
+	r.playlists[playlist.UUID] = *playlist
+	return nil
+}
+
+// DeletePlaylist removes the playlist with the specified UUID.
+func (r *fakeRepo) DeletePlaylist(_ context.Context, id uuid.UUID) (bool, error) {
+	if _, ok := r.playlists[id]; !ok {
+		return false, nil
+	}
+	delete(r.playlists, id)
+	return true, nil
+}
@@ -0,0 +1,46 @@
+package playlist
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func Test_fakeRepo_Playlists(t *testing.T) {
+	t.Parallel()
+
+	repo := NewFakeRepository()
+	songs := []uuid.UUID{uuid.New(), uuid.New()}
+	playlist := model.Playlist{Name: " 90s Night ", Songs: songs}
+	if err := repo.CreatePlaylist(context.TODO(), &playlist); err != nil {
+		t.Fatalf("CreatePlaylist(ctx, &playlist) returned an unexpected error: %s", err)
+	}
+	if playlist.Name != "90s Night" {
+		t.Errorf("CreatePlaylist(ctx, &playlist) produced name %q, expected %q", playlist.Name, "90s Night")
+	}
+
+	playlist.Songs = []uuid.UUID{songs[1], songs[0], songs[1]}
+	if err := repo.UpdatePlaylist(context.TODO(), &playlist); err != nil {
+		t.Fatalf("UpdatePlaylist(ctx, &playlist) returned an unexpected error: %s", err)
+	}
+	actual, err := repo.GetPlaylist(context.TODO(), playlist.UUID)
+	if err != nil {
+		t.Fatalf("GetPlaylist(ctx, %q) returned an unexpected error: %s", playlist.UUID, err)
+	}
+	if !slices.Equal(actual.Songs, playlist.Songs) {
+		t.Errorf("GetPlaylist(ctx, %q) returned songs %v, expected %v", playlist.UUID, actual.Songs, playlist.Songs)
+	}
+
+	if ok, err := repo.DeletePlaylist(context.TODO(), playlist.UUID); !ok || err != nil {
+		t.Errorf("DeletePlaylist(ctx, %q) returned %t, %v, expected true, <nil>", playlist.UUID, ok, err)
+	}
+	if _, err = repo.GetPlaylist(context.TODO(), playlist.UUID); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("GetPlaylist(ctx, %q) after deleting returned %v, expected ErrNotFound", playlist.UUID, err)
+	}
+}
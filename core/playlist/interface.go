@@ -0,0 +1,36 @@
+package playlist
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// A Repository is an interface for storing playlists.
+// Playlists reference songs by their UUID.
+// Callers are responsible for only adding existing songs to a playlist.
+type Repository interface {
+	// CreatePlaylist creates a new playlist with the specified name, description and songs.
+	// This method must set playlist.UUID, playlist.CreatedAt, and playlist.UpdatedAt appropriately.
+	CreatePlaylist(ctx context.Context, playlist *model.Playlist) error
+
+	// GetPlaylist fetches the playlist with the specified UUID.
+	// If no such playlist exists, core.ErrNotFound will be returned.
+	GetPlaylist(ctx context.Context, id uuid.UUID) (model.Playlist, error)
+
+	// FindPlaylists returns all playlists ordered by name.
+	// Results are paginated with limit and offset.
+	// The second return value contains the total (unpaginated) number of playlists.
+	FindPlaylists(ctx context.Context, limit int, offset int64) ([]model.Playlist, int64, error)
+
+	// UpdatePlaylist saves the name, description and songs of the specified playlist.
+	// The playlist's UUID must already exist in the database, otherwise core.ErrNotFound will be returned.
+	UpdatePlaylist(ctx context.Context, playlist *model.Playlist) error
+
+	// DeletePlaylist deletes the playlist with the specified UUID.
+	// The songs of the playlist are not affected.
+	// If no such playlist exists, the first return value will be false.
+	DeletePlaylist(ctx context.Context, id uuid.UUID) (bool, error)
+}
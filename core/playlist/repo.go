@@ -0,0 +1,191 @@
+package playlist
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/internal/dbutil"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// dbRepo is the main Repository implementation, backed by a PostgreSQL database.
+type dbRepo struct {
+	logger *slog.Logger
+	db     pgxutil.DB // database connection
+}
+
+// NewDBRepository creates a new Repository backed by the specified database connection.
+// db can be a single connection or a connection pool.
+func NewDBRepository(logger *slog.Logger, db pgxutil.DB) Repository {
+	return &dbRepo{logger, db}
+}
+
+// playlistColumns selects the columns of a playlist p, including the UUIDs of its songs in order.
+const playlistColumns = `p.uuid, p.created_at, p.updated_at, p.name, p.description,
+    ARRAY(SELECT s.uuid FROM playlist_songs AS ps JOIN songs AS s ON ps.song_id = s.id
+        WHERE ps.playlist_id = p.id ORDER BY ps.position) AS songs`
+
+// playlistRow is the data returned by a SELECT query for playlists.
+type playlistRow struct {
+	UUID        uuid.UUID
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+	Name        string
+	Description string
+	Songs       []uuid.UUID
+}
+
+// toModel converts r into an equivalent model.Playlist.
+func (r playlistRow) toModel() model.Playlist {
+	return model.Playlist{
+		Model: model.Model{
+			UUID:      r.UUID,
+			CreatedAt: r.CreatedAt,
+			UpdatedAt: r.UpdatedAt,
+		},
+		Name:        r.Name,
+		Description: r.Description,
+		Songs:       r.Songs,
+	}
+}
+
+// CreatePlaylist creates playlist in the database.
+// Songs that do not exist are removed from the playlist.
+func (r *dbRepo) CreatePlaylist(ctx context.Context, playlist *model.Playlist) error {
+	preparePlaylist(playlist)
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		id, err := pgxutil.InsertRowReturning(ctx, tx, "playlists", map[string]any{
+			"name":        playlist.Name,
+			"description": playlist.Description,
+		}, "id", pgx.RowTo[int])
+		if err != nil {
+			return err
+		}
+		if err = setSongs(ctx, tx, id, playlist.Songs); err != nil {
+			return err
+		}
+		*playlist, err = getPlaylist(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not create playlist.", "name", playlist.Name, tint.Err(err))
+		return dbutil.Error(err)
+	}
+	return nil
+}
+
+// GetPlaylist fetches a single playlist from the database by its UUID.
+func (r *dbRepo) GetPlaylist(ctx context.Context, id uuid.UUID) (model.Playlist, error) {
+	row, err := pgxutil.SelectRow(ctx, r.db, `SELECT `+playlistColumns+`
+	FROM playlists AS p
+	WHERE p.uuid = $1`, []any{id}, pgx.RowToStructByName[playlistRow])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not fetch playlist.", "uuid", id, tint.Err(err))
+		}
+		return model.Playlist{}, dbutil.Error(err)
+	}
+	return row.toModel(), nil
+}
+
+// FindPlaylists fetches multiple playlists from the database, ordered by name.
+// The results are paginated with limit and offset.
+func (r *dbRepo) FindPlaylists(ctx context.Context, limit int, offset int64) ([]model.Playlist, int64, error) {
+	total, err := pgxutil.SelectRow(ctx, r.db, `SELECT COUNT(*) FROM playlists`, nil, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not count playlists.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, 0, err
+	}
+	playlists, err := pgxutil.Select(ctx, r.db, `SELECT `+playlistColumns+`
+	FROM playlists AS p
+	ORDER BY LOWER(p.name), p.id
+	LIMIT CASE WHEN $1 < 0 THEN NULL ELSE $1 END OFFSET $2`, []any{limit, offset}, func(row pgx.CollectableRow) (model.Playlist, error) {
+		data, err := pgx.RowToStructByName[playlistRow](row)
+		return data.toModel(), err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list playlists.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, total, err
+	}
+	return playlists, total, nil
+}
+
+// UpdatePlaylist updates the playlist in the database with playlist.UUID.
+// Songs that do not exist are removed from the playlist.
+func (r *dbRepo) UpdatePlaylist(ctx context.Context, playlist *model.Playlist) error {
+	preparePlaylist(playlist)
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		id, err := pgxutil.UpdateRowReturning(ctx, tx, "playlists", map[string]any{
+			"name":        playlist.Name,
+			"description": playlist.Description,
+		}, map[string]any{
+			"uuid": playlist.UUID,
+		}, "id", pgx.RowTo[int])
+		if err != nil {
+			return err
+		}
+		if err = setSongs(ctx, tx, id, playlist.Songs); err != nil {
+			return err
+		}
+		*playlist, err = getPlaylist(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		if err = dbutil.Error(err); !errors.Is(err, core.ErrNotFound) {
+			r.logger.ErrorContext(ctx, "Could not update playlist.", "uuid", playlist.UUID, tint.Err(err))
+		}
+		return err
+	}
+	return nil
+}
+
+// DeletePlaylist deletes the playlist with the specified UUID from the database.
+func (r *dbRepo) DeletePlaylist(ctx context.Context, id uuid.UUID) (bool, error) {
+	_, err := pgxutil.ExecRow(ctx, r.db, `DELETE FROM playlists WHERE uuid = $1`, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not delete playlist.", "uuid", id, tint.Err(err))
+		return false, err
+	}
+	return true, nil
+}
+
+// getPlaylist fetches the playlist with the specified ID using db.
+func getPlaylist(ctx context.Context, db pgxutil.DB, id int) (model.Playlist, error) {
+	row, err := pgxutil.SelectRow(ctx, db, `SELECT `+playlistColumns+`
+	FROM playlists AS p
+	WHERE p.id = $1`, []any{id}, pgx.RowToStructByName[playlistRow])
+	return row.toModel(), err
+}
+
+// setSongs replaces the songs of the playlist with the specified ID with songs.
+// UUIDs that do not identify a song are skipped.
+func setSongs(ctx context.Context, db pgxutil.DB, id int, songs []uuid.UUID) error {
+	if _, err := db.Exec(ctx, `DELETE FROM playlist_songs WHERE playlist_id = $1`, id); err != nil {
+		return err
+	}
+	_, err := db.Exec(ctx, `INSERT INTO playlist_songs (playlist_id, song_id, position)
+	SELECT $1, s.id, o.position FROM UNNEST($2::UUID[]) WITH ORDINALITY AS o(uuid, position)
+	JOIN songs AS s ON s.uuid = o.uuid`, id, songs)
+	return err
+}
+
+// preparePlaylist normalizes the name and description of playlist.
+func preparePlaylist(playlist *model.Playlist) {
+	playlist.Name = strings.TrimSpace(playlist.Name)
+	playlist.Description = strings.TrimSpace(playlist.Description)
+	if playlist.Songs == nil {
+		playlist.Songs = make([]uuid.UUID, 0)
+	}
+}
@@ -0,0 +1,130 @@
+//go:build database
+
+package playlist
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func Test_dbRepo_CreatePlaylist(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song1 := testdata.SimpleSong(t, db)
+	song2 := testdata.SimpleSong(t, db)
+
+	playlist := model.Playlist{Name: "90s Night", Songs: []uuid.UUID{song2.UUID, uuid.New(), song1.UUID, song2.UUID}}
+	if err := repo.CreatePlaylist(context.TODO(), &playlist); err != nil {
+		t.Fatalf("CreatePlaylist(ctx, &playlist) returned an unexpected error: %s", err)
+	}
+	if playlist.UUID == uuid.Nil {
+		t.Errorf("CreatePlaylist(ctx, &playlist) produced playlist.UUID = <uuid.Nil>, expected a valid UUID")
+	}
+	expected := []uuid.UUID{song2.UUID, song1.UUID, song2.UUID}
+	if !slices.Equal(playlist.Songs, expected) {
+		t.Errorf("CreatePlaylist(ctx, &playlist) produced songs %v, expected %v", playlist.Songs, expected)
+	}
+}
+
+func Test_dbRepo_GetPlaylist(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song := testdata.SimpleSong(t, db)
+	expected := testdata.Playlist(t, db, "Duets only", song.UUID)
+
+	t.Run("success", func(t *testing.T) {
+		actual, err := repo.GetPlaylist(context.TODO(), expected.UUID)
+		if err != nil {
+			t.Fatalf("GetPlaylist(ctx, %q) returned an unexpected error: %s", expected.UUID, err)
+		}
+		if actual.Name != expected.Name || !slices.Equal(actual.Songs, expected.Songs) {
+			t.Errorf("GetPlaylist(ctx, %q) returned %q with songs %v, expected %q with songs %v", expected.UUID, actual.Name, actual.Songs, expected.Name, expected.Songs)
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		id := uuid.New()
+		if _, err := repo.GetPlaylist(context.TODO(), id); !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("GetPlaylist(ctx, %q) returned %v, expected ErrNotFound", id, err)
+		}
+	})
+}
+
+func Test_dbRepo_FindPlaylists(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	testdata.Playlist(t, db, "b")
+	testdata.Playlist(t, db, "C")
+	testdata.Playlist(t, db, "a")
+
+	playlists, total, err := repo.FindPlaylists(context.TODO(), 2, 1)
+	if err != nil {
+		t.Fatalf("FindPlaylists(ctx, 2, 1) returned an unexpected error: %s", err)
+	}
+	if total != 3 {
+		t.Errorf("FindPlaylists(ctx, 2, 1) returned total %d, expected %d", total, 3)
+	}
+	if len(playlists) != 2 || playlists[0].Name != "b" || playlists[1].Name != "C" {
+		t.Errorf("FindPlaylists(ctx, 2, 1) returned %v, expected playlists b and C", playlists)
+	}
+}
+
+func Test_dbRepo_UpdatePlaylist(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song1 := testdata.SimpleSong(t, db)
+	song2 := testdata.SimpleSong(t, db)
+
+	t.Run("success", func(t *testing.T) {
+		playlist := testdata.Playlist(t, db, "Party", song1.UUID, song2.UUID)
+		playlist.Name = "Party Hits"
+		playlist.Songs = []uuid.UUID{song2.UUID, song1.UUID}
+		if err := repo.UpdatePlaylist(context.TODO(), &playlist); err != nil {
+			t.Fatalf("UpdatePlaylist(ctx, &playlist) returned an unexpected error: %s", err)
+		}
+		actual, _ := repo.GetPlaylist(context.TODO(), playlist.UUID)
+		if actual.Name != "Party Hits" || !slices.Equal(actual.Songs, playlist.Songs) {
+			t.Errorf("UpdatePlaylist(ctx, &playlist) saved %q with songs %v, expected %q with songs %v", actual.Name, actual.Songs, "Party Hits", playlist.Songs)
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		playlist := model.Playlist{Name: "Foo"}
+		playlist.UUID = uuid.New()
+		if err := repo.UpdatePlaylist(context.TODO(), &playlist); !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("UpdatePlaylist(ctx, &playlist) returned %v, expected ErrNotFound", err)
+		}
+	})
+}
+
+func Test_dbRepo_DeletePlaylist(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song := testdata.SimpleSong(t, db)
+	playlist := testdata.Playlist(t, db, "Party", song.UUID)
+
+	if ok, err := repo.DeletePlaylist(context.TODO(), playlist.UUID); !ok || err != nil {
+		t.Errorf("DeletePlaylist(ctx, %q) returned %t, %v, expected true, <nil>", playlist.UUID, ok, err)
+	}
+	if ok, err := repo.DeletePlaylist(context.TODO(), playlist.UUID); ok || err != nil {
+		t.Errorf("DeletePlaylist(ctx, %q) of a deleted playlist returned %t, %v, expected false, <nil>", playlist.UUID, ok, err)
+	}
+}
@@ -0,0 +1,158 @@
+package playlist
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/filename"
+)
+
+// An Entry is a reference to a single song in a UPL file.
+// Songs are identified by their artist and title.
+type Entry struct {
+	Artist string
+	Title  string
+}
+
+// EntryFor returns the UPL entry for song.
+// The song must have been prepared by the song service so that its artist is set.
+func EntryFor(song model.Song) Entry {
+	return Entry{song.Artist, song.Title}
+}
+
+// String returns the line of e in a UPL file.
+func (e Entry) String() string {
+	return e.Artist + " : " + e.Title
+}
+
+// Matches reports whether e references song, ignoring case.
+// The song must have been prepared by the song service so that its artist is set.
+// An entry also matches if its artist is only one of the artists of song.
+func (e Entry) Matches(song model.Song) bool {
+	if !strings.EqualFold(e.Title, strings.TrimSpace(song.Title)) {
+		return false
+	}
+	if strings.EqualFold(e.Artist, strings.TrimSpace(song.Artist)) {
+		return true
+	}
+	for _, artist := range song.Artists {
+		if strings.EqualFold(e.Artist, artist) {
+			return true
+		}
+	}
+	return false
+}
+
+// A UPL is a playlist in the playlist format of UltraStar Deluxe.
+type UPL struct {
+	Name    string
+	Entries []Entry
+}
+
+// ReadUPL parses a UPL file from r.
+// Lines starting with # are comments, except for the #Name header.
+// All other non-empty lines must be of the form "Artist : Title".
+func ReadUPL(r io.Reader) (UPL, error) {
+	var upl UPL
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if n == 1 {
+			// UltraStar Deluxe may write a byte order mark
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			key, value, ok := strings.Cut(line[1:], ":")
+			if ok && strings.EqualFold(strings.TrimSpace(key), "name") {
+				upl.Name = strings.TrimSpace(value)
+			}
+			continue
+		}
+		artist, title, ok := strings.Cut(line, " : ")
+		if !ok {
+			artist, title, ok = strings.Cut(line, ":")
+		}
+		if !ok {
+			return upl, fmt.Errorf("line %d: expected \"Artist : Title\"", n)
+		}
+		upl.Entries = append(upl.Entries, Entry{strings.TrimSpace(artist), strings.TrimSpace(title)})
+	}
+	return upl, s.Err()
+}
+
+// WriteUPL writes upl to w in the playlist format of UltraStar Deluxe.
+func WriteUPL(w io.Writer, upl UPL) error {
+	bw := bufio.NewWriter(w)
+	_, _ = bw.WriteString("######################################\n")
+	_, _ = bw.WriteString("#Ultrastar Deluxe Playlist Format v1.0\n")
+	_, _ = fmt.Fprintf(bw, "#Playlist \"%s\" with %d Songs.\n", upl.Name, len(upl.Entries))
+	_, _ = bw.WriteString("######################################\n")
+	_, _ = fmt.Fprintf(bw, "#Name: %s\n", upl.Name)
+	_, _ = bw.WriteString("#Songs:\n")
+	for _, e := range upl.Entries {
+		_, _ = bw.WriteString(e.String())
+		_ = bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// Match resolves the entries of a UPL file against songs.
+// Each entry is resolved to the first matching song.
+// The songs must have been prepared by the song service.
+// Entries without a matching song are returned as the second value.
+func Match(entries []Entry, songs []model.Song) ([]uuid.UUID, []Entry) {
+	ids := make([]uuid.UUID, 0, len(entries))
+	missing := make([]Entry, 0)
+	for _, e := range entries {
+		found := false
+		for _, song := range songs {
+			if e.Matches(song) {
+				ids = append(ids, song.UUID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, e)
+		}
+	}
+	return ids, missing
+}
+
+// Export converts playlist into a UPL.
+// The songs of the playlist are fetched from songRepo and prepared by songSvc.
+// Songs that no longer exist or are in the trash are skipped.
+func Export(ctx context.Context, playlist model.Playlist, songRepo song.Repository, songSvc song.Service) (UPL, error) {
+	upl := UPL{Name: playlist.Name, Entries: make([]Entry, 0, len(playlist.Songs))}
+	for _, id := range playlist.Songs {
+		s, err := songRepo.GetSong(ctx, id)
+		if errors.Is(err, core.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return upl, err
+		}
+		if s.Deleted() {
+			continue
+		}
+		songSvc.Prepare(ctx, &s)
+		upl.Entries = append(upl.Entries, EntryFor(s))
+	}
+	return upl, nil
+}
+
+// FileName returns the name of the UPL file for playlist.
+func FileName(playlist model.Playlist) string {
+	return filename.Sanitize(playlist.Name) + ".upl"
+}
@@ -0,0 +1,78 @@
+package playlist
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func TestReadUPL(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success", func(t *testing.T) {
+		data := "\uFEFF######################################\r\n" +
+			"#Ultrastar Deluxe Playlist Format v1.0\r\n" +
+			"#Name: 90s Night\r\n" +
+			"#Songs:\r\n" +
+			"Queen : Bohemian Rhapsody\r\n" +
+			"\r\n" +
+			"ABBA:Waterloo\r\n"
+		upl, err := ReadUPL(strings.NewReader(data))
+		if err != nil {
+			t.Fatalf("ReadUPL() returned an unexpected error: %s", err)
+		}
+		if upl.Name != "90s Night" {
+			t.Errorf("ReadUPL() returned name %q, expected %q", upl.Name, "90s Night")
+		}
+		expected := []Entry{{"Queen", "Bohemian Rhapsody"}, {"ABBA", "Waterloo"}}
+		if !slices.Equal(upl.Entries, expected) {
+			t.Errorf("ReadUPL() returned entries %v, expected %v", upl.Entries, expected)
+		}
+	})
+
+	t.Run("invalid entry", func(t *testing.T) {
+		if _, err := ReadUPL(strings.NewReader("#Name: Foo\nBar\n")); err == nil {
+			t.Errorf("ReadUPL() did not return an error, expected an error for line 2")
+		}
+	})
+}
+
+func TestWriteUPL(t *testing.T) {
+	t.Parallel()
+
+	upl := UPL{Name: "Duets only", Entries: []Entry{{"Queen", "Bohemian Rhapsody"}}}
+	b := &bytes.Buffer{}
+	if err := WriteUPL(b, upl); err != nil {
+		t.Fatalf("WriteUPL() returned an unexpected error: %s", err)
+	}
+	actual, err := ReadUPL(b)
+	if err != nil {
+		t.Fatalf("ReadUPL() of written file returned an unexpected error: %s", err)
+	}
+	if actual.Name != upl.Name || !slices.Equal(actual.Entries, upl.Entries) {
+		t.Errorf("ReadUPL() of written file returned %v, expected %v", actual, upl)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	song := model.Song{Artists: []string{"Queen", "David Bowie"}}
+	song.UUID = uuid.New()
+	song.Artist = "Queen, David Bowie"
+	song.Title = "Under Pressure"
+
+	entries := []Entry{{"queen, david bowie", "under pressure"}, {"David Bowie", "Under Pressure"}, {"Queen", "Bohemian Rhapsody"}}
+	ids, missing := Match(entries, []model.Song{song})
+	if !slices.Equal(ids, []uuid.UUID{song.UUID, song.UUID}) {
+		t.Errorf("Match() returned songs %v, expected %v", ids, []uuid.UUID{song.UUID, song.UUID})
+	}
+	if !slices.Equal(missing, entries[2:]) {
+		t.Errorf("Match() returned missing entries %v, expected %v", missing, entries[2:])
+	}
+}
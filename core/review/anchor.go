@@ -0,0 +1,50 @@
+package review
+
+import (
+	"fmt"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// playerNotes returns the notes of the specified player of s.
+// Player 1 is only valid for duets.
+func playerNotes(s model.Song, player int) (ultrastar.Notes, error) {
+	switch {
+	case player == 0:
+		return s.NotesP1, nil
+	case player == 1 && s.IsDuet():
+		return s.NotesP2, nil
+	default:
+		return nil, fmt.Errorf("invalid player: song has no player %d", player)
+	}
+}
+
+// LineAnchor creates an anchor that references a line of the specified player of s.
+// Line indices are the same as for song.SplitLines.
+// If the line does not exist, an error is returned.
+func LineAnchor(s model.Song, player int, line int) (model.CommentAnchor, error) {
+	notes, err := playerNotes(s, player)
+	if err != nil {
+		return model.CommentAnchor{}, err
+	}
+	lines := song.SplitLines(notes)
+	if line < 0 || line >= len(lines) {
+		return model.CommentAnchor{}, fmt.Errorf("invalid line: player %d has %d lines", player, len(lines))
+	}
+	return model.CommentAnchor{Player: player, Line: line, Beat: lines[line].Notes[0].Start}, nil
+}
+
+// BeatAnchor creates an anchor that references a single beat of the specified player of s.
+// Beats can reference any point in the song, including pauses between notes.
+func BeatAnchor(s model.Song, player int, beat ultrastar.Beat) (model.CommentAnchor, error) {
+	if _, err := playerNotes(s, player); err != nil {
+		return model.CommentAnchor{}, err
+	}
+	if beat < 0 {
+		return model.CommentAnchor{}, fmt.Errorf("invalid beat: must not be negative")
+	}
+	return model.CommentAnchor{Player: player, Line: -1, Beat: beat}, nil
+}
@@ -0,0 +1,51 @@
+package review
+
+import (
+	"testing"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func TestLineAnchor(t *testing.T) {
+	t.Parallel()
+
+	var song model.Song
+	song.NotesP1 = ultrastar.Notes{
+		{Type: ultrastar.NoteTypeRegular, Start: 4, Duration: 2, Text: "Hel"},
+		{Type: ultrastar.NoteTypeRegular, Start: 6, Duration: 2, Text: "lo"},
+		{Type: ultrastar.NoteTypeLineBreak, Start: 10},
+		{Type: ultrastar.NoteTypeRegular, Start: 12, Duration: 4, Text: "World"},
+	}
+
+	anchor, err := LineAnchor(song, 0, 1)
+	if err != nil {
+		t.Fatalf("LineAnchor(song, 0, 1) returned an unexpected error: %s", err)
+	}
+	if anchor != (model.CommentAnchor{Player: 0, Line: 1, Beat: 12}) {
+		t.Errorf("LineAnchor(song, 0, 1) = %v, expected line 1 at beat 12", anchor)
+	}
+	if _, err = LineAnchor(song, 0, 2); err == nil {
+		t.Errorf("LineAnchor(song, 0, 2) did not return an error, expected an invalid line")
+	}
+	if _, err = LineAnchor(song, 1, 0); err == nil {
+		t.Errorf("LineAnchor(song, 1, 0) did not return an error, expected an invalid player")
+	}
+}
+
+func TestBeatAnchor(t *testing.T) {
+	t.Parallel()
+
+	var song model.Song
+	anchor, err := BeatAnchor(song, 0, 42)
+	if err != nil {
+		t.Fatalf("BeatAnchor(song, 0, 42) returned an unexpected error: %s", err)
+	}
+	if anchor != (model.CommentAnchor{Player: 0, Line: -1, Beat: 42}) {
+		t.Errorf("BeatAnchor(song, 0, 42) = %v, expected beat 42 without a line", anchor)
+	}
+	if _, err = BeatAnchor(song, 0, -1); err == nil {
+		t.Errorf("BeatAnchor(song, 0, -1) did not return an error, expected an invalid beat")
+	}
+}
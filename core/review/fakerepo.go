@@ -0,0 +1,141 @@
+package review
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// state is the review state of a song and the time it was set.
+type state struct {
+	state     model.ReviewState
+	changedAt time.Time
+}
+
+// fakeRepo is a simple implementation of Repository that can be used for testing.
+// The fake repository does not know about songs, so it never returns core.ErrNotFound for unknown songs
+// and queue entries do not include the title and artists of songs.
+type fakeRepo struct {
+	states map[uuid.UUID]state
+	// comments maps song UUIDs to their threads, oldest comment first.
+	comments map[uuid.UUID][]model.SongComment
+}
+
+// NewFakeRepository returns a new Repository implementation backed by in-memory maps.
+func NewFakeRepository() Repository {
+	return &fakeRepo{make(map[uuid.UUID]state), make(map[uuid.UUID][]model.SongComment)}
+}
+
+// SetState sets the review state of the song.
+func (r *fakeRepo) SetState(_ context.Context, songID uuid.UUID, s model.ReviewState, _ string) error {
+	r.states[songID] = state{s, time.Now()}
+	return nil
+}
+
+// CreateComment appends comment to the thread of the song.
+func (r *fakeRepo) CreateComment(_ context.Context, songID uuid.UUID, comment *model.SongComment) error {
+	comment.UUID = uuid.New()
+	comment.CreatedAt = time.Now()
+	comment.UpdatedAt = comment.CreatedAt
+	r.comments[songID] = append(r.comments[songID], *comment)
+	return nil
+}
+
+// GetComment fetches a comment of the song.
+func (r *fakeRepo) GetComment(_ context.Context, songID uuid.UUID, id uuid.UUID) (model.SongComment, error) {
+	i := r.indexOf(songID, id)
+	if i < 0 {
+		return model.SongComment{}, core.ErrNotFound
+	}
+	return r.comments[songID][i], nil
+}
+
+// FindComments returns the thread of the song, paginated by limit and offset.
+func (r *fakeRepo) FindComments(_ context.Context, songID uuid.UUID, limit int, offset int64) ([]model.SongComment, int64, error) {
+	comments := r.comments[songID]
+	if limit < 0 {
+		limit = math.MaxInt
+	}
+	start := min(int(offset), len(comments))
+	end := min(start+limit, len(comments))
+	return slices.Clone(comments[start:end]), int64(len(comments)), nil
+}
+
+// UpdateComment saves the text and resolution of the comment.
+func (r *fakeRepo) UpdateComment(_ context.Context, songID uuid.UUID, comment *model.SongComment) error {
+	i := r.indexOf(songID, comment.UUID)
+	if i < 0 {
+		return core.ErrNotFound
+	}
+	c := &r.comments[songID][i]
+	c.Text = comment.Text
+	c.Resolved = comment.Resolved
+	c.UpdatedAt = time.Now()
+	comment.UpdatedAt = c.UpdatedAt
+	return nil
+}
+
+// DeleteComment deletes the comment of the song.
+func (r *fakeRepo) DeleteComment(_ context.Context, songID uuid.UUID, id uuid.UUID) (bool, error) {
+	i := r.indexOf(songID, id)
+	if i < 0 {
+		return false, nil
+	}
+	r.comments[songID] = slices.Delete(r.comments[songID], i, i+1)
+	return true, nil
+}
+
+// FindQueue returns the songs that need a review or have unresolved out-of-sync reports.
+func (r *fakeRepo) FindQueue(_ context.Context, limit int, offset int64) ([]model.ReviewQueueEntry, int64, error) {
+	entries := make([]model.ReviewQueueEntry, 0)
+	songs := make(map[uuid.UUID]bool)
+	for id := range r.states {
+		songs[id] = true
+	}
+	for id := range r.comments {
+		songs[id] = true
+	}
+	for id := range songs {
+		entry := model.ReviewQueueEntry{Song: id, State: model.ReviewStateDraft}
+		if s, ok := r.states[id]; ok {
+			entry.State = s.state
+			if s.state == model.ReviewStateNeedsReview {
+				entry.Since = s.changedAt
+			}
+		}
+		for _, c := range r.comments[id] {
+			if c.Kind != model.CommentKindOutOfSync || c.Resolved {
+				continue
+			}
+			entry.OpenReports++
+			if entry.Since.IsZero() || c.CreatedAt.Before(entry.Since) {
+				entry.Since = c.CreatedAt
+			}
+		}
+		if !entry.Since.IsZero() {
+			entries = append(entries, entry)
+		}
+	}
+	slices.SortFunc(entries, func(a, b model.ReviewQueueEntry) int {
+		return a.Since.Compare(b.Since)
+	})
+	if limit < 0 {
+		limit = math.MaxInt
+	}
+	start := min(int(offset), len(entries))
+	end := min(start+limit, len(entries))
+	return entries[start:end], int64(len(entries)), nil
+}
+
+// indexOf returns the index of the comment with UUID id in the thread of the song or -1.
+func (r *fakeRepo) indexOf(songID uuid.UUID, id uuid.UUID) int {
+	return slices.IndexFunc(r.comments[songID], func(c model.SongComment) bool {
+		return c.UUID == id
+	})
+}
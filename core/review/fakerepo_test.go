@@ -0,0 +1,41 @@
+package review
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func Test_fakeRepo_FindQueue(t *testing.T) {
+	t.Parallel()
+
+	repo := NewFakeRepository()
+	draft, pending, reported := uuid.New(), uuid.New(), uuid.New()
+	_ = repo.SetState(context.TODO(), draft, model.ReviewStateDraft, "")
+	_ = repo.SetState(context.TODO(), pending, model.ReviewStateNeedsReview, "")
+	_ = repo.CreateComment(context.TODO(), draft, &model.SongComment{Kind: model.CommentKindRemark, Text: "Nice"})
+	report := model.SongComment{Kind: model.CommentKindOutOfSync, Text: "Chorus is late"}
+	_ = repo.CreateComment(context.TODO(), reported, &report)
+
+	entries, total, err := repo.FindQueue(context.TODO(), -1, 0)
+	if err != nil {
+		t.Fatalf("FindQueue(ctx, -1, 0) returned an unexpected error: %s", err)
+	}
+	if total != 2 || len(entries) != 2 || entries[0].Song != pending || entries[1].Song != reported {
+		t.Errorf("FindQueue(ctx, -1, 0) returned %v, expected the pending and the reported song", entries)
+	}
+	if entries[1].OpenReports != 1 {
+		t.Errorf("FindQueue(ctx, -1, 0) returned %d open reports, expected %d", entries[1].OpenReports, 1)
+	}
+
+	report.Resolved = true
+	if err = repo.UpdateComment(context.TODO(), reported, &report); err != nil {
+		t.Fatalf("UpdateComment(ctx, reported, report) returned an unexpected error: %s", err)
+	}
+	if _, total, _ = repo.FindQueue(context.TODO(), -1, 0); total != 1 {
+		t.Errorf("FindQueue(ctx, -1, 0) returned %d entries after resolving the report, expected %d", total, 1)
+	}
+}
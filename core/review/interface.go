@@ -0,0 +1,53 @@
+// Package review implements the review workflow of songs.
+//
+// Each song has a review state (see model.ReviewState) and a thread of comments.
+// Comments are either remarks of reviewers or out-of-sync reports of singers.
+// Songs that need a review or have unresolved out-of-sync reports form the review queue.
+package review
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// A Repository stores the review states and comments of songs.
+// The review state of a song is included in the song itself (see model.Song.ReviewState).
+type Repository interface {
+	// SetState sets the review state of the library song with UUID songID.
+	// author identifies who changed the state and may be empty.
+	// If no such song exists, core.ErrNotFound is returned.
+	SetState(ctx context.Context, songID uuid.UUID, state model.ReviewState, author string) error
+
+	// CreateComment adds comment to the thread of the song with UUID songID.
+	// This method must set comment.UUID, comment.CreatedAt and comment.UpdatedAt.
+	// If no such song exists, core.ErrNotFound is returned.
+	CreateComment(ctx context.Context, songID uuid.UUID, comment *model.SongComment) error
+
+	// GetComment fetches the comment with UUID id from the thread of the song with UUID songID.
+	// If no such comment exists, core.ErrNotFound is returned.
+	GetComment(ctx context.Context, songID uuid.UUID, id uuid.UUID) (model.SongComment, error)
+
+	// FindComments returns the thread of the song with UUID songID.
+	// Comments are ordered by their creation time, the oldest comment first.
+	// Results are paginated with limit and offset.
+	// The second return value contains the total (unpaginated) number of comments.
+	FindComments(ctx context.Context, songID uuid.UUID, limit int, offset int64) ([]model.SongComment, int64, error)
+
+	// UpdateComment saves the text and resolution of comment.
+	// The kind, author and anchor of a comment cannot be changed.
+	// If no such comment exists, core.ErrNotFound is returned.
+	UpdateComment(ctx context.Context, songID uuid.UUID, comment *model.SongComment) error
+
+	// DeleteComment deletes the comment with UUID id from the thread of the song with UUID songID.
+	// If no such comment exists, the first return value is false.
+	DeleteComment(ctx context.Context, songID uuid.UUID, id uuid.UUID) (bool, error)
+
+	// FindQueue returns the library songs that need a review or have unresolved out-of-sync reports.
+	// Songs are ordered by the time at which they entered the queue, the oldest entry first.
+	// Results are paginated with limit and offset.
+	// The second return value contains the total (unpaginated) number of entries.
+	FindQueue(ctx context.Context, limit int, offset int64) ([]model.ReviewQueueEntry, int64, error)
+}
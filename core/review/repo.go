@@ -0,0 +1,242 @@
+package review
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"codello.dev/ultrastar"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgxutil"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/core/internal/dbutil"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// dbRepo is the main Repository implementation, backed by a PostgreSQL database.
+type dbRepo struct {
+	logger *slog.Logger
+	db     pgxutil.DB // database connection
+}
+
+// NewDBRepository creates a new Repository backed by the specified database connection.
+// db can be a single connection or a connection pool.
+func NewDBRepository(logger *slog.Logger, db pgxutil.DB) Repository {
+	return &dbRepo{logger, db}
+}
+
+// commentColumns selects the columns of a comment c.
+const commentColumns = `c.uuid, c.created_at, c.updated_at, c.author, c.kind, c.text,
+    c.anchor_player, c.anchor_line, c.anchor_beat, c.resolved`
+
+// commentRow is the data returned by a SELECT query for comments.
+type commentRow struct {
+	UUID         uuid.UUID
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+	Author       string
+	Kind         string
+	Text         string
+	AnchorPlayer pgtype.Int4 `db:"anchor_player"`
+	AnchorLine   pgtype.Int4 `db:"anchor_line"`
+	AnchorBeat   pgtype.Int4 `db:"anchor_beat"`
+	Resolved     bool
+}
+
+// toModel converts r into an equivalent model.SongComment.
+func (r commentRow) toModel() model.SongComment {
+	c := model.SongComment{
+		Model: model.Model{
+			UUID:      r.UUID,
+			CreatedAt: r.CreatedAt,
+			UpdatedAt: r.UpdatedAt,
+		},
+		Author:   r.Author,
+		Kind:     model.CommentKind(r.Kind),
+		Text:     r.Text,
+		Resolved: r.Resolved,
+	}
+	if r.AnchorPlayer.Valid {
+		c.Anchor = &model.CommentAnchor{
+			Player: int(r.AnchorPlayer.Int32),
+			Line:   int(r.AnchorLine.Int32),
+			Beat:   ultrastar.Beat(r.AnchorBeat.Int32),
+		}
+	}
+	return c
+}
+
+// SetState creates or updates the review state of a song.
+func (r *dbRepo) SetState(ctx context.Context, songID uuid.UUID, state model.ReviewState, author string) error {
+	_, err := pgxutil.ExecRow(ctx, r.db, `INSERT INTO song_reviews (song_id, state, changed_by)
+	SELECT id, $2, $3 FROM songs WHERE uuid = $1 AND upload_id IS NULL AND deleted_at IS NULL
+	ON CONFLICT (song_id) DO UPDATE SET state = excluded.state, changed_at = NOW(), changed_by = excluded.changed_by`,
+		songID, state, author)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not set review state.", "uuid", songID, "state", state, tint.Err(err))
+		}
+		return dbutil.Error(err)
+	}
+	return nil
+}
+
+// CreateComment inserts comment into the thread of a song.
+func (r *dbRepo) CreateComment(ctx context.Context, songID uuid.UUID, comment *model.SongComment) error {
+	var player, line, beat *int
+	if comment.Anchor != nil {
+		b := int(comment.Anchor.Beat)
+		player, line, beat = &comment.Anchor.Player, &comment.Anchor.Line, &b
+	}
+	row, err := pgxutil.SelectRow(ctx, r.db, `INSERT INTO song_comments (
+		song_id, author, kind, text, anchor_player, anchor_line, anchor_beat, resolved
+	) SELECT id, $2, $3, $4, $5, $6, $7, $8 FROM songs WHERE uuid = $1
+	RETURNING uuid, created_at, updated_at`, []any{
+		songID, comment.Author, comment.Kind, comment.Text, player, line, beat, comment.Resolved,
+	}, pgx.RowToStructByName[struct {
+		UUID      uuid.UUID
+		CreatedAt time.Time `db:"created_at"`
+		UpdatedAt time.Time `db:"updated_at"`
+	}])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not create song comment.", "uuid", songID, tint.Err(err))
+		}
+		return dbutil.Error(err)
+	}
+	comment.UUID = row.UUID
+	comment.CreatedAt = row.CreatedAt
+	comment.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+// GetComment fetches a single comment of a song.
+func (r *dbRepo) GetComment(ctx context.Context, songID uuid.UUID, id uuid.UUID) (model.SongComment, error) {
+	row, err := pgxutil.SelectRow(ctx, r.db, `SELECT `+commentColumns+`
+	FROM song_comments AS c
+	INNER JOIN songs AS s ON c.song_id = s.id
+	WHERE s.uuid = $1 AND c.uuid = $2`, []any{songID, id}, pgx.RowToStructByName[commentRow])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not fetch song comment.", "uuid", songID, "comment", id, tint.Err(err))
+		}
+		return model.SongComment{}, dbutil.Error(err)
+	}
+	return row.toModel(), nil
+}
+
+// FindComments fetches the thread of a song, oldest comment first.
+func (r *dbRepo) FindComments(ctx context.Context, songID uuid.UUID, limit int, offset int64) ([]model.SongComment, int64, error) {
+	total, err := pgxutil.SelectRow(ctx, r.db, `SELECT COUNT(*)
+	FROM song_comments AS c
+	INNER JOIN songs AS s ON c.song_id = s.id
+	WHERE s.uuid = $1`, []any{songID}, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not count song comments.", "uuid", songID, tint.Err(err))
+		return nil, 0, err
+	}
+	comments, err := pgxutil.Select(ctx, r.db, `SELECT `+commentColumns+`
+	FROM song_comments AS c
+	INNER JOIN songs AS s ON c.song_id = s.id
+	WHERE s.uuid = $1
+	ORDER BY c.created_at, c.id
+	LIMIT CASE WHEN $2 < 0 THEN NULL ELSE $2 END OFFSET $3`, []any{songID, limit, offset}, func(row pgx.CollectableRow) (model.SongComment, error) {
+		data, err := pgx.RowToStructByName[commentRow](row)
+		return data.toModel(), err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list song comments.", "uuid", songID, "limit", limit, "offset", offset, tint.Err(err))
+		return nil, total, err
+	}
+	return comments, total, nil
+}
+
+// UpdateComment saves the text and resolution of a comment.
+func (r *dbRepo) UpdateComment(ctx context.Context, songID uuid.UUID, comment *model.SongComment) error {
+	updatedAt, err := pgxutil.SelectRow(ctx, r.db, `UPDATE song_comments AS c SET text = $3, resolved = $4
+	FROM songs AS s
+	WHERE c.song_id = s.id AND s.uuid = $1 AND c.uuid = $2
+	RETURNING c.updated_at`, []any{songID, comment.UUID, comment.Text, comment.Resolved}, pgx.RowTo[time.Time])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not update song comment.", "uuid", songID, "comment", comment.UUID, tint.Err(err))
+		}
+		return dbutil.Error(err)
+	}
+	comment.UpdatedAt = updatedAt
+	return nil
+}
+
+// DeleteComment deletes a comment of a song.
+func (r *dbRepo) DeleteComment(ctx context.Context, songID uuid.UUID, id uuid.UUID) (bool, error) {
+	_, err := pgxutil.ExecRow(ctx, r.db, `DELETE FROM song_comments AS c
+	USING songs AS s
+	WHERE c.song_id = s.id AND s.uuid = $1 AND c.uuid = $2`, songID, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		r.logger.ErrorContext(ctx, "Could not delete song comment.", "uuid", songID, "comment", id, tint.Err(err))
+		return false, err
+	}
+	return true, nil
+}
+
+// queueQuery selects the library songs s in the review queue.
+// reports contains the number of unresolved out-of-sync reports of each song and the time the oldest report was filed.
+// A song enters the queue when its state is changed to needs-review or when the first unresolved report is filed.
+const queueQuery = `FROM songs AS s
+        LEFT OUTER JOIN song_reviews AS sr ON sr.song_id = s.id
+        LEFT OUTER JOIN (SELECT c.song_id, COUNT(*) AS count, MIN(c.created_at) AS oldest
+            FROM song_comments AS c
+            WHERE c.kind = 'out-of-sync' AND NOT c.resolved
+            GROUP BY c.song_id) AS reports ON reports.song_id = s.id
+    WHERE s.upload_id IS NULL AND s.deleted_at IS NULL
+        AND (sr.state = 'needs-review' OR reports.count > 0)`
+
+// queueRow is the data returned by a SELECT query for the review queue.
+type queueRow struct {
+	UUID        uuid.UUID
+	Title       string
+	Artists     []string
+	State       string
+	Since       time.Time
+	OpenReports int `db:"open_reports"`
+}
+
+// FindQueue fetches the review queue, oldest entry first.
+func (r *dbRepo) FindQueue(ctx context.Context, limit int, offset int64) ([]model.ReviewQueueEntry, int64, error) {
+	total, err := pgxutil.SelectRow(ctx, r.db, `SELECT COUNT(*) `+queueQuery, nil, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not count review queue.", tint.Err(err))
+		return nil, 0, err
+	}
+	entries, err := pgxutil.Select(ctx, r.db, `SELECT s.uuid, s.title,
+    ARRAY(SELECT ar.name FROM song_artists AS sa JOIN artists AS ar ON sa.artist_id = ar.id
+        WHERE sa.song_id = s.id AND sa.role = 'main' ORDER BY sa.position) AS artists,
+    COALESCE(sr.state, 'draft') AS state,
+    LEAST(CASE WHEN sr.state = 'needs-review' THEN sr.changed_at END, reports.oldest) AS since,
+    COALESCE(reports.count, 0) AS open_reports
+    `+queueQuery+`
+    ORDER BY since, s.id
+	LIMIT CASE WHEN $1 < 0 THEN NULL ELSE $1 END OFFSET $2`, []any{limit, offset}, func(row pgx.CollectableRow) (model.ReviewQueueEntry, error) {
+		data, err := pgx.RowToStructByName[queueRow](row)
+		return model.ReviewQueueEntry{
+			Song:        data.UUID,
+			Title:       data.Title,
+			Artists:     data.Artists,
+			State:       model.ReviewState(data.State),
+			Since:       data.Since,
+			OpenReports: data.OpenReports,
+		}, err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list review queue.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, total, err
+	}
+	return entries, total, nil
+}
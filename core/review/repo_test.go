@@ -0,0 +1,129 @@
+//go:build database
+
+package review
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func Test_dbRepo_SetState(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	songRepo := song.NewDBRepository(nolog.Logger, db)
+	simple := testdata.SimpleSong(t, db)
+
+	if s, _ := songRepo.GetSong(context.TODO(), simple.UUID); s.ReviewState != model.ReviewStateDraft {
+		t.Errorf("GetSong(ctx, %s) returned review state %q, expected %q", simple.UUID, s.ReviewState, model.ReviewStateDraft)
+	}
+	for _, state := range []model.ReviewState{model.ReviewStateNeedsReview, model.ReviewStateApproved} {
+		if err := repo.SetState(context.TODO(), simple.UUID, state, "Tester"); err != nil {
+			t.Fatalf("SetState(ctx, %s, %q, author) returned an unexpected error: %s", simple.UUID, state, err)
+		}
+		if s, _ := songRepo.GetSong(context.TODO(), simple.UUID); s.ReviewState != state {
+			t.Errorf("SetState(ctx, %s, %q, author) produced review state %q", simple.UUID, state, s.ReviewState)
+		}
+	}
+	deleted := testdata.DeletedSong(t, db)
+	for _, id := range []uuid.UUID{uuid.New(), deleted.UUID} {
+		if err := repo.SetState(context.TODO(), id, model.ReviewStateBroken, ""); !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("SetState(ctx, %s, %q, author) returned %v, expected ErrNotFound", id, model.ReviewStateBroken, err)
+		}
+	}
+}
+
+func Test_dbRepo_Comments(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	simple := testdata.SimpleSong(t, db)
+	remark := testdata.Comment(t, db, simple.UUID, model.CommentKindRemark, "Check the gap")
+
+	comment := model.SongComment{
+		Author: "Singer",
+		Kind:   model.CommentKindOutOfSync,
+		Text:   "The second line is late",
+		Anchor: &model.CommentAnchor{Player: 0, Line: 1, Beat: 12},
+	}
+	if err := repo.CreateComment(context.TODO(), simple.UUID, &comment); err != nil {
+		t.Fatalf("CreateComment(ctx, %s, comment) returned an unexpected error: %s", simple.UUID, err)
+	}
+	if comment.UUID == uuid.Nil {
+		t.Errorf("CreateComment(ctx, %s, comment) did not set the UUID", simple.UUID)
+	}
+	actual, err := repo.GetComment(context.TODO(), simple.UUID, comment.UUID)
+	if err != nil {
+		t.Fatalf("GetComment(ctx, %s, %s) returned an unexpected error: %s", simple.UUID, comment.UUID, err)
+	}
+	if actual.Anchor == nil || *actual.Anchor != *comment.Anchor || actual.Kind != comment.Kind {
+		t.Errorf("GetComment(ctx, %s, %s) = %v, expected %v", simple.UUID, comment.UUID, actual, comment)
+	}
+	if err = repo.CreateComment(context.TODO(), uuid.New(), &model.SongComment{Kind: model.CommentKindRemark}); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("CreateComment(ctx, uuid, comment) returned %v, expected ErrNotFound", err)
+	}
+
+	comments, total, err := repo.FindComments(context.TODO(), simple.UUID, -1, 0)
+	if err != nil {
+		t.Fatalf("FindComments(ctx, %s, -1, 0) returned an unexpected error: %s", simple.UUID, err)
+	}
+	if total != 2 || len(comments) != 2 || comments[0].UUID != remark.UUID || comments[0].Anchor != nil {
+		t.Errorf("FindComments(ctx, %s, -1, 0) returned %v, expected the remark first", simple.UUID, comments)
+	}
+
+	comment.Resolved = true
+	if err = repo.UpdateComment(context.TODO(), simple.UUID, &comment); err != nil {
+		t.Fatalf("UpdateComment(ctx, %s, comment) returned an unexpected error: %s", simple.UUID, err)
+	}
+	if actual, _ = repo.GetComment(context.TODO(), simple.UUID, comment.UUID); !actual.Resolved {
+		t.Errorf("UpdateComment(ctx, %s, comment) did not resolve the comment", simple.UUID)
+	}
+
+	if ok, err := repo.DeleteComment(context.TODO(), simple.UUID, remark.UUID); err != nil || !ok {
+		t.Errorf("DeleteComment(ctx, %s, %s) = %t, %v, expected true, nil", simple.UUID, remark.UUID, ok, err)
+	}
+	if ok, _ := repo.DeleteComment(context.TODO(), simple.UUID, remark.UUID); ok {
+		t.Errorf("DeleteComment(ctx, %s, %s) a second time = true, expected false", simple.UUID, remark.UUID)
+	}
+}
+
+func Test_dbRepo_FindQueue(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	testdata.NSongs(t, db, 3)
+	pending := testdata.SimpleSong(t, db)
+	testdata.ReviewState(t, db, pending.UUID, model.ReviewStateNeedsReview)
+	reported := testdata.SimpleSong(t, db)
+	testdata.Comment(t, db, reported.UUID, model.CommentKindOutOfSync, "Out of sync")
+	testdata.Comment(t, db, reported.UUID, model.CommentKindOutOfSync, "Still out of sync")
+	approved := testdata.SimpleSong(t, db)
+	testdata.ReviewState(t, db, approved.UUID, model.ReviewStateApproved)
+
+	entries, total, err := repo.FindQueue(context.TODO(), -1, 0)
+	if err != nil {
+		t.Fatalf("FindQueue(ctx, -1, 0) returned an unexpected error: %s", err)
+	}
+	if total != 2 || len(entries) != 2 {
+		t.Fatalf("FindQueue(ctx, -1, 0) returned %d entries, expected %d", total, 2)
+	}
+	if entries[0].Song != pending.UUID || entries[0].State != model.ReviewStateNeedsReview || entries[0].Title != pending.Title {
+		t.Errorf("FindQueue(ctx, -1, 0) returned %v as first entry, expected song %s", entries[0], pending.UUID)
+	}
+	if entries[1].Song != reported.UUID || entries[1].State != model.ReviewStateDraft || entries[1].OpenReports != 2 {
+		t.Errorf("FindQueue(ctx, -1, 0) returned %v as second entry, expected song %s with 2 reports", entries[1], reported.UUID)
+	}
+}
@@ -0,0 +1,175 @@
+package revision
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// A Diff describes the changes between two revisions of a song.
+type Diff struct {
+	// Fields contains the metadata fields that have changed.
+	Fields []FieldChange
+
+	// NotesP1 and NotesP2 contain the changed lines of the notes of each player.
+	// Lines are formatted as in the UltraStar TXT format.
+	NotesP1 []LineChange
+	NotesP2 []LineChange
+}
+
+// Empty indicates whether d contains no changes.
+func (d Diff) Empty() bool {
+	return len(d.Fields) == 0 && len(d.NotesP1) == 0 && len(d.NotesP2) == 0
+}
+
+// A FieldChange describes a single metadata field that has changed between two revisions.
+type FieldChange struct {
+	// Field is the name of the field as used in the song schema of the API.
+	Field string
+	Old   any
+	New   any
+}
+
+// A LineOp identifies the kind of change of a LineChange.
+type LineOp string
+
+const (
+	// LineAdded indicates that a line was added in the new revision.
+	LineAdded LineOp = "+"
+
+	// LineRemoved indicates that a line was removed in the new revision.
+	LineRemoved LineOp = "-"
+)
+
+// A LineChange describes a single line of notes that has been added or removed.
+// A modified line is represented as a removal followed by an addition.
+type LineChange struct {
+	Op LineOp
+
+	// OldLine is the 1-based line number in the old revision.
+	// The value is 0 for added lines.
+	OldLine int
+
+	// NewLine is the 1-based line number in the new revision.
+	// The value is 0 for removed lines.
+	NewLine int
+
+	Text string
+}
+
+// Compare calculates the changes necessary to get from revision from to revision to.
+func Compare(from model.SongRevision, to model.SongRevision) Diff {
+	var d Diff
+	field := func(name string, o, n any, equal bool) {
+		if !equal {
+			d.Fields = append(d.Fields, FieldChange{name, o, n})
+		}
+	}
+	field("title", from.Title, to.Title, from.Title == to.Title)
+	field("artists", from.Artists, to.Artists, slices.Equal(from.Artists, to.Artists))
+	field("featuredArtists", from.FeaturedArtists, to.FeaturedArtists, slices.Equal(from.FeaturedArtists, to.FeaturedArtists))
+	field("genre", from.Genre, to.Genre, from.Genre == to.Genre)
+	field("edition", from.Edition, to.Edition, from.Edition == to.Edition)
+	field("creator", from.Creator, to.Creator, from.Creator == to.Creator)
+	field("language", from.Language, to.Language, from.Language == to.Language)
+	field("year", from.Year, to.Year, from.Year == to.Year)
+	field("comment", from.Comment, to.Comment, from.Comment == to.Comment)
+	field("duetSinger1", from.DuetSinger1, to.DuetSinger1, from.DuetSinger1 == to.DuetSinger1)
+	field("duetSinger2", from.DuetSinger2, to.DuetSinger2, from.DuetSinger2 == to.DuetSinger2)
+	field("extra", from.CustomTags, to.CustomTags, maps.Equal(from.CustomTags, to.CustomTags))
+	field("bpm", from.BPM, to.BPM, from.BPM == to.BPM)
+	field("gap", from.Gap, to.Gap, from.Gap == to.Gap)
+	field("videoGap", from.VideoGap, to.VideoGap, from.VideoGap == to.VideoGap)
+	field("start", from.Start, to.Start, from.Start == to.Start)
+	field("end", from.End, to.End, from.End == to.End)
+	field("previewStart", from.PreviewStart, to.PreviewStart, from.PreviewStart == to.PreviewStart)
+	field("medleyStartBeat", from.MedleyStartBeat, to.MedleyStartBeat, from.MedleyStartBeat == to.MedleyStartBeat)
+	field("medleyEndBeat", from.MedleyEndBeat, to.MedleyEndBeat, from.MedleyEndBeat == to.MedleyEndBeat)
+	field("noAutoMedley", from.NoAutoMedley, to.NoAutoMedley, from.NoAutoMedley == to.NoAutoMedley)
+
+	d.NotesP1 = diffLines(noteLines(from.NotesP1), noteLines(to.NotesP1))
+	d.NotesP2 = diffLines(noteLines(from.NotesP2), noteLines(to.NotesP2))
+	return d
+}
+
+// noteLines formats each note in ns as a line in the UltraStar TXT format.
+func noteLines(ns ultrastar.Notes) []string {
+	lines := make([]string, len(ns))
+	for i, n := range ns {
+		if n.Type == ultrastar.NoteTypeLineBreak {
+			lines[i] = fmt.Sprintf("%c %d", n.Type, n.Start)
+		} else {
+			lines[i] = fmt.Sprintf("%c %d %d %d %s", n.Type, n.Start, n.Duration, n.Pitch, n.Text)
+		}
+	}
+	return lines
+}
+
+// maxDiffCells limits the size of the table used by diffLines to calculate the longest common subsequence.
+// The table of 4 Mi cells occupies 16 MiB.
+const maxDiffCells = 4 << 20
+
+// diffLines calculates a minimal line diff between a and b.
+// The diff is based on the longest common subsequence of a and b.
+// Common prefixes and suffixes are stripped beforehand, so small edits are cheap even for long songs.
+// If the remaining lines are too many to calculate the subsequence, all remaining lines of a are removed
+// and all remaining lines of b are added.
+func diffLines(a, b []string) []LineChange {
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	a, b = a[pre:len(a)-suf], b[pre:len(b)-suf]
+	if len(a) == 0 && len(b) == 0 {
+		return nil
+	}
+
+	n, m := len(a), len(b)
+	if (n+1)*(m+1) > maxDiffCells {
+		changes := make([]LineChange, 0, n+m)
+		for i, line := range a {
+			changes = append(changes, LineChange{Op: LineRemoved, OldLine: pre + i + 1, Text: line})
+		}
+		for j, line := range b {
+			changes = append(changes, LineChange{Op: LineAdded, NewLine: pre + j + 1, Text: line})
+		}
+		return changes
+	}
+
+	// lcs[i*(m+1)+j] is the length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([]int32, (n+1)*(m+1))
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*(m+1)+j] = lcs[(i+1)*(m+1)+j+1] + 1
+			} else {
+				lcs[i*(m+1)+j] = max(lcs[(i+1)*(m+1)+j], lcs[i*(m+1)+j+1])
+			}
+		}
+	}
+
+	changes := make([]LineChange, 0)
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[i] == b[j]:
+			i++
+			j++
+		case j == m || (i < n && lcs[(i+1)*(m+1)+j] >= lcs[i*(m+1)+j+1]):
+			changes = append(changes, LineChange{Op: LineRemoved, OldLine: pre + i + 1, Text: a[i]})
+			i++
+		default:
+			changes = append(changes, LineChange{Op: LineAdded, NewLine: pre + j + 1, Text: b[j]})
+			j++
+		}
+	}
+	return changes
+}
@@ -0,0 +1,106 @@
+package revision
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	from := model.SongRevision{
+		Song: ultrastar.Song{
+			Title: "Foo",
+			BPM:   120,
+			NotesP1: ultrastar.Notes{
+				{Type: ultrastar.NoteTypeRegular, Start: 0, Duration: 4, Pitch: 5, Text: "Hel"},
+				{Type: ultrastar.NoteTypeRegular, Start: 4, Duration: 4, Pitch: 5, Text: "lo"},
+				{Type: ultrastar.NoteTypeLineBreak, Start: 10},
+				{Type: ultrastar.NoteTypeRegular, Start: 12, Duration: 2, Pitch: 3, Text: "World"},
+			},
+		},
+		Artists: []string{"Bar"},
+	}
+	to := from
+	to.Title = "Foo (Remix)"
+	to.Artists = []string{"Bar"}
+	to.NotesP1 = slices.Clone(from.NotesP1)
+	to.NotesP1[1].Pitch = 7
+	to.NotesP1 = append(to.NotesP1, ultrastar.Note{Type: ultrastar.NoteTypeGolden, Start: 16, Duration: 2, Pitch: 3, Text: "!"})
+
+	d := Compare(from, to)
+	if len(d.Fields) != 1 || d.Fields[0].Field != "title" {
+		t.Errorf("Compare(from, to).Fields = %v, expected a single change of title", d.Fields)
+	}
+	expected := []LineChange{
+		{Op: LineRemoved, OldLine: 2, Text: ": 4 4 5 lo"},
+		{Op: LineAdded, NewLine: 2, Text: ": 4 4 7 lo"},
+		{Op: LineAdded, NewLine: 5, Text: "* 16 2 3 !"},
+	}
+	if !slices.Equal(d.NotesP1, expected) {
+		t.Errorf("Compare(from, to).NotesP1 = %v, expected %v", d.NotesP1, expected)
+	}
+	if len(d.NotesP2) != 0 {
+		t.Errorf("Compare(from, to).NotesP2 = %v, expected no changes", d.NotesP2)
+	}
+}
+
+func TestCompare_Equal(t *testing.T) {
+	t.Parallel()
+
+	rev := model.SongRevision{
+		Song:    ultrastar.Song{Title: "Foo", CustomTags: map[string]string{}},
+		Artists: []string{"Bar"},
+	}
+	other := rev
+	other.CustomTags = nil
+	if d := Compare(rev, other); !d.Empty() {
+		t.Errorf("Compare(rev, other) = %v, expected an empty diff", d)
+	}
+}
+
+func Test_diffLines(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		a, b     []string
+		expected []LineChange
+	}{
+		"equal":   {[]string{"a", "b"}, []string{"a", "b"}, nil},
+		"append":  {[]string{"a"}, []string{"a", "b"}, []LineChange{{Op: LineAdded, NewLine: 2, Text: "b"}}},
+		"remove":  {[]string{"a", "b", "c"}, []string{"a", "c"}, []LineChange{{Op: LineRemoved, OldLine: 2, Text: "b"}}},
+		"replace": {[]string{"a"}, []string{"b"}, []LineChange{{Op: LineRemoved, OldLine: 1, Text: "a"}, {Op: LineAdded, NewLine: 1, Text: "b"}}},
+		"empty":   {nil, []string{"a"}, []LineChange{{Op: LineAdded, NewLine: 1, Text: "a"}}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			changes := diffLines(c.a, c.b)
+			if !slices.Equal(changes, c.expected) {
+				t.Errorf("diffLines(%v, %v) = %v, expected %v", c.a, c.b, changes, c.expected)
+			}
+		})
+	}
+
+	t.Run("large", func(t *testing.T) {
+		a, b := make([]string, 5000), make([]string, 5000)
+		for i := range a {
+			a[i], b[i] = fmt.Sprintf("a%d", i), fmt.Sprintf("b%d", i)
+		}
+		a, b = append([]string{"x"}, a...), append([]string{"x"}, b...)
+		changes := diffLines(a, b)
+		if len(changes) != 10000 {
+			t.Fatalf("diffLines(a, b) returned %d changes, expected 10000", len(changes))
+		}
+		if first := changes[0]; first != (LineChange{Op: LineRemoved, OldLine: 2, Text: "a0"}) {
+			t.Errorf("diffLines(a, b)[0] = %v, expected line 2 to be removed", first)
+		}
+		if last := changes[9999]; last != (LineChange{Op: LineAdded, NewLine: 5001, Text: "b4999"}) {
+			t.Errorf("diffLines(a, b)[9999] = %v, expected line 5001 to be added", last)
+		}
+	})
+}
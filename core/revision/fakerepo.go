@@ -0,0 +1,72 @@
+package revision
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// fakeRepo is a simple implementation of Repository that can be used for testing.
+type fakeRepo struct {
+	// revisions contains the revisions of songs in chronological order.
+	revisions map[uuid.UUID][]model.SongRevision
+}
+
+// NewFakeRepository returns a new Repository implementation backed by an in-memory map.
+// The fake repository does not check whether songs exist.
+func NewFakeRepository() Repository {
+	return &fakeRepo{make(map[uuid.UUID][]model.SongRevision)}
+}
+
+// CreateRevision stores rev and sets its UUID and CreatedAt fields.
+func (r *fakeRepo) CreateRevision(_ context.Context, songID uuid.UUID, rev *model.SongRevision) error {
+	rev.UUID = uuid.New()
+	rev.CreatedAt = time.Now()
+	r.revisions[songID] = append(r.revisions[songID], *rev)
+	return nil
+}
+
+// GetRevision looks up the revision with the specified UUID.
+func (r *fakeRepo) GetRevision(_ context.Context, songID uuid.UUID, id uuid.UUID) (model.SongRevision, error) {
+	idx := r.index(songID, id)
+	if idx < 0 {
+		return model.SongRevision{}, core.ErrNotFound
+	}
+	return r.revisions[songID][idx], nil
+}
+
+// GetPreviousRevision looks up the revision that was created before the revision with the specified UUID.
+func (r *fakeRepo) GetPreviousRevision(_ context.Context, songID uuid.UUID, id uuid.UUID) (model.SongRevision, error) {
+	idx := r.index(songID, id)
+	if idx <= 0 {
+		return model.SongRevision{}, core.ErrNotFound
+	}
+	return r.revisions[songID][idx-1], nil
+}
+
+// FindRevisions returns a paginated list of revisions of a song, the newest revision first.
+func (r *fakeRepo) FindRevisions(_ context.Context, songID uuid.UUID, limit int, offset int64) ([]model.SongRevision, int64, error) {
+	revs := slices.Clone(r.revisions[songID])
+	slices.Reverse(revs)
+	total := int64(len(revs))
+	if offset > total {
+		offset = total
+	}
+	revs = revs[offset:]
+	if limit >= 0 && limit < len(revs) {
+		revs = revs[:limit]
+	}
+	return revs, total, nil
+}
+
+// index returns the index of the revision with the specified UUID in r.revisions[songID] or -1.
+func (r *fakeRepo) index(songID uuid.UUID, id uuid.UUID) int {
+	return slices.IndexFunc(r.revisions[songID], func(rev model.SongRevision) bool {
+		return rev.UUID == id
+	})
+}
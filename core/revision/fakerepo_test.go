@@ -0,0 +1,51 @@
+package revision
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func Test_fakeRepo_Revisions(t *testing.T) {
+	t.Parallel()
+
+	repo := NewFakeRepository()
+	songID := uuid.New()
+	first := model.SongRevision{Author: "first"}
+	second := model.SongRevision{Author: "second"}
+	_ = repo.CreateRevision(context.TODO(), songID, &first)
+	_ = repo.CreateRevision(context.TODO(), songID, &second)
+
+	revs, total, err := repo.FindRevisions(context.TODO(), songID, -1, 0)
+	if err != nil {
+		t.Errorf("FindRevisions(ctx, %q, -1, 0) returned an unexpected error: %s", songID, err)
+		return
+	}
+	if total != 2 || len(revs) != 2 {
+		t.Errorf("FindRevisions(ctx, %q, -1, 0) returned %d revisions (total %d), expected %d", songID, len(revs), total, 2)
+		return
+	}
+	if revs[0].UUID != second.UUID {
+		t.Errorf("FindRevisions(ctx, %q, -1, 0) did not return the newest revision first", songID)
+	}
+
+	prev, err := repo.GetPreviousRevision(context.TODO(), songID, second.UUID)
+	if err != nil {
+		t.Errorf("GetPreviousRevision(ctx, %q, %q) returned an unexpected error: %s", songID, second.UUID, err)
+	} else if prev.UUID != first.UUID {
+		t.Errorf("GetPreviousRevision(ctx, %q, %q) = %q, expected %q", songID, second.UUID, prev.UUID, first.UUID)
+	}
+	_, err = repo.GetPreviousRevision(context.TODO(), songID, first.UUID)
+	if !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("GetPreviousRevision(ctx, %q, %q) returned %v, expected ErrNotFound", songID, first.UUID, err)
+	}
+	_, err = repo.GetRevision(context.TODO(), uuid.New(), first.UUID)
+	if !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("GetRevision(ctx, <other song>, %q) returned %v, expected ErrNotFound", first.UUID, err)
+	}
+}
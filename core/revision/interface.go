@@ -0,0 +1,32 @@
+package revision
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// A Repository stores the revision history of songs.
+// Revisions are immutable, once recorded they cannot be changed.
+type Repository interface {
+	// CreateRevision records rev as the newest revision of the song with the specified UUID.
+	// This method must set rev.UUID and rev.CreatedAt.
+	// If no such song exists, core.ErrNotFound will be returned.
+	CreateRevision(ctx context.Context, songID uuid.UUID, rev *model.SongRevision) error
+
+	// GetRevision fetches the revision with the specified UUID belonging to the song with UUID songID.
+	// If no such revision exists, core.ErrNotFound will be returned.
+	GetRevision(ctx context.Context, songID uuid.UUID, id uuid.UUID) (model.SongRevision, error)
+
+	// GetPreviousRevision fetches the revision that was recorded directly before the revision with the specified UUID.
+	// If the revision is the first revision of the song, core.ErrNotFound will be returned.
+	GetPreviousRevision(ctx context.Context, songID uuid.UUID, id uuid.UUID) (model.SongRevision, error)
+
+	// FindRevisions returns the revisions of the song with the specified UUID.
+	// The newest revisions are returned first.
+	// Results are paginated with limit and offset.
+	// The second return value contains the total (unpaginated) number of revisions.
+	FindRevisions(ctx context.Context, songID uuid.UUID, limit int, offset int64) ([]model.SongRevision, int64, error)
+}
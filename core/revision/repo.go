@@ -0,0 +1,217 @@
+package revision
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"codello.dev/ultrastar"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/core/internal/dbutil"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// dbRepo is the main Repository implementation, backed by a PostgreSQL database.
+type dbRepo struct {
+	logger *slog.Logger
+	db     pgxutil.DB // database connection
+}
+
+// NewDBRepository creates a new Repository backed by the specified database connection.
+// db can be a single connection or a connection pool.
+func NewDBRepository(logger *slog.Logger, db pgxutil.DB) Repository {
+	return &dbRepo{logger, db}
+}
+
+// revisionRow is the data returned by a SELECT query for revisions.
+type revisionRow struct {
+	UUID      uuid.UUID
+	CreatedAt time.Time `db:"created_at"`
+	Author    string
+
+	BPM             ultrastar.BPM
+	Gap             time.Duration
+	VideoGap        time.Duration `db:"video_gap"`
+	Start           time.Duration
+	End             time.Duration
+	PreviewStart    time.Duration  `db:"preview_start"`
+	MedleyStartBeat ultrastar.Beat `db:"medley_start_beat"`
+	MedleyEndBeat   ultrastar.Beat `db:"medley_end_beat"`
+	ManualMedley    bool           `db:"manual_medley"`
+
+	Title           string
+	Artists         []string
+	FeaturedArtists []string `db:"featured_artists"`
+	Genre           string
+	Edition         string
+	Creator         string
+	Language        string
+	Year            int
+	Comment         string
+	Extra           map[string]string
+	DuetSinger1     string `db:"duet_singer1"`
+	DuetSinger2     string `db:"duet_singer2"`
+
+	NotesP1 dbutil.Notes `db:"notes_p1"`
+	NotesP2 dbutil.Notes `db:"notes_p2"`
+}
+
+// toModel converts r into an equivalent model.SongRevision.
+func (r revisionRow) toModel() model.SongRevision {
+	return model.SongRevision{
+		UUID:            r.UUID,
+		CreatedAt:       r.CreatedAt,
+		Author:          r.Author,
+		Artists:         r.Artists,
+		FeaturedArtists: r.FeaturedArtists,
+		Song: ultrastar.Song{
+			BPM:             r.BPM,
+			Gap:             r.Gap,
+			VideoGap:        r.VideoGap,
+			Start:           r.Start,
+			End:             r.End,
+			PreviewStart:    r.PreviewStart,
+			MedleyStartBeat: r.MedleyStartBeat,
+			MedleyEndBeat:   r.MedleyEndBeat,
+			NoAutoMedley:    r.ManualMedley,
+			Title:           r.Title,
+			Genre:           r.Genre,
+			Edition:         r.Edition,
+			Creator:         r.Creator,
+			Language:        r.Language,
+			Year:            r.Year,
+			Comment:         r.Comment,
+			CustomTags:      r.Extra,
+			DuetSinger1:     r.DuetSinger1,
+			DuetSinger2:     r.DuetSinger2,
+			NotesP1:         ultrastar.Notes(r.NotesP1),
+			NotesP2:         ultrastar.Notes(r.NotesP2),
+		},
+	}
+}
+
+// CreateRevision records rev for the song with the specified UUID.
+func (r *dbRepo) CreateRevision(ctx context.Context, songID uuid.UUID, rev *model.SongRevision) error {
+	prepareRevision(rev)
+	row, err := pgxutil.SelectRow(ctx, r.db, `INSERT INTO song_revisions (
+		song_id, author,
+		title, artists, featured_artists, genre, edition, creator, language, year, comment, extra,
+		bpm, gap, video_gap, start, "end", preview_start, medley_start_beat, medley_end_beat, manual_medley,
+		notes_p1, notes_p2, duet_singer1, duet_singer2
+	) SELECT
+		songs.id, $2,
+		$3, $4, $5, $6, $7, $8, $9, $10, $11, $12,
+		$13, $14, $15, $16, $17, $18, $19, $20, $21,
+		$22, $23, $24, $25
+	FROM songs WHERE songs.uuid = $1
+	RETURNING uuid, created_at`, []any{
+		songID, rev.Author,
+		rev.Title, rev.Artists, rev.FeaturedArtists, rev.Genre, rev.Edition, rev.Creator, rev.Language, rev.Year, rev.Comment, rev.CustomTags,
+		rev.BPM, rev.Gap, rev.VideoGap, rev.Start, rev.End, rev.PreviewStart, rev.MedleyStartBeat, rev.MedleyEndBeat, rev.NoAutoMedley,
+		dbutil.Notes(rev.NotesP1), dbutil.Notes(rev.NotesP2), rev.DuetSinger1, rev.DuetSinger2,
+	}, pgx.RowToStructByName[struct {
+		UUID      uuid.UUID
+		CreatedAt time.Time `db:"created_at"`
+	}])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not create song revision.", "uuid", songID, tint.Err(err))
+		}
+		return dbutil.Error(err)
+	}
+	rev.UUID = row.UUID
+	rev.CreatedAt = row.CreatedAt
+	return nil
+}
+
+// GetRevision fetches a single revision of a song.
+func (r *dbRepo) GetRevision(ctx context.Context, songID uuid.UUID, id uuid.UUID) (model.SongRevision, error) {
+	row, err := pgxutil.SelectRow(ctx, r.db, `SELECT
+    r.uuid, r.created_at, r.author,
+    r.title, r.artists, r.featured_artists, r.genre, r.edition, r.creator, r.language, r.year, r.comment, r.extra,
+    r.bpm, r.gap, r.video_gap, r.start, r."end", r.preview_start, r.medley_start_beat, r.medley_end_beat, r.manual_medley,
+    r.notes_p1, r.notes_p2, r.duet_singer1, r.duet_singer2
+	FROM song_revisions AS r
+	INNER JOIN songs AS s ON r.song_id = s.id
+	WHERE s.uuid = $1 AND r.uuid = $2`, []any{songID, id}, pgx.RowToStructByName[revisionRow])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not fetch song revision.", "uuid", songID, "revision", id, tint.Err(err))
+		}
+		return model.SongRevision{}, dbutil.Error(err)
+	}
+	return row.toModel(), nil
+}
+
+// GetPreviousRevision fetches the revision of a song that was recorded before the revision with the specified UUID.
+func (r *dbRepo) GetPreviousRevision(ctx context.Context, songID uuid.UUID, id uuid.UUID) (model.SongRevision, error) {
+	row, err := pgxutil.SelectRow(ctx, r.db, `SELECT
+    r.uuid, r.created_at, r.author,
+    r.title, r.artists, r.featured_artists, r.genre, r.edition, r.creator, r.language, r.year, r.comment, r.extra,
+    r.bpm, r.gap, r.video_gap, r.start, r."end", r.preview_start, r.medley_start_beat, r.medley_end_beat, r.manual_medley,
+    r.notes_p1, r.notes_p2, r.duet_singer1, r.duet_singer2
+	FROM song_revisions AS r
+	INNER JOIN songs AS s ON r.song_id = s.id
+	INNER JOIN song_revisions AS c ON c.song_id = s.id AND c.uuid = $2
+	WHERE s.uuid = $1 AND (r.created_at, r.id) < (c.created_at, c.id)
+	ORDER BY r.created_at DESC, r.id DESC
+	LIMIT 1`, []any{songID, id}, pgx.RowToStructByName[revisionRow])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not fetch previous song revision.", "uuid", songID, "revision", id, tint.Err(err))
+		}
+		return model.SongRevision{}, dbutil.Error(err)
+	}
+	return row.toModel(), nil
+}
+
+// FindRevisions lists the revisions of a song with pagination.
+func (r *dbRepo) FindRevisions(ctx context.Context, songID uuid.UUID, limit int, offset int64) ([]model.SongRevision, int64, error) {
+	total, err := pgxutil.SelectRow(ctx, r.db, `SELECT COUNT(*)
+	FROM song_revisions AS r
+	INNER JOIN songs AS s ON r.song_id = s.id
+	WHERE s.uuid = $1`, []any{songID}, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not count song revisions.", "uuid", songID, "limit", limit, "offset", offset, tint.Err(err))
+		return nil, 0, err
+	}
+	revs, err := pgxutil.Select(ctx, r.db, `SELECT
+    r.uuid, r.created_at, r.author,
+    r.title, r.artists, r.featured_artists, r.genre, r.edition, r.creator, r.language, r.year, r.comment, r.extra,
+    r.bpm, r.gap, r.video_gap, r.start, r."end", r.preview_start, r.medley_start_beat, r.medley_end_beat, r.manual_medley,
+    r.notes_p1, r.notes_p2, r.duet_singer1, r.duet_singer2
+	FROM song_revisions AS r
+	INNER JOIN songs AS s ON r.song_id = s.id
+	WHERE s.uuid = $1
+	ORDER BY r.created_at DESC, r.id DESC
+	LIMIT CASE WHEN $2 < 0 THEN NULL ELSE $2 END OFFSET $3`, []any{songID, limit, offset}, func(row pgx.CollectableRow) (model.SongRevision, error) {
+		data, err := pgx.RowToStructByName[revisionRow](row)
+		return data.toModel(), err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list song revisions.", "uuid", songID, "limit", limit, "offset", offset, tint.Err(err))
+		return nil, total, err
+	}
+	return revs, total, nil
+}
+
+// prepareRevision replaces nil values in rev with non-nil zero values in order to avoid constraint violations.
+func prepareRevision(rev *model.SongRevision) {
+	if rev.Artists == nil {
+		rev.Artists = make([]string, 0)
+	}
+	if rev.FeaturedArtists == nil {
+		rev.FeaturedArtists = make([]string, 0)
+	}
+	if rev.CustomTags == nil {
+		rev.CustomTags = make(map[string]string)
+	}
+	if rev.NotesP1 == nil {
+		rev.NotesP1 = make(ultrastar.Notes, 0)
+	}
+}
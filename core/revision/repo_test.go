@@ -0,0 +1,94 @@
+//go:build database
+
+package revision
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func Test_dbRepo_CreateRevision(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song := testdata.SimpleSong(t, db)
+
+	t.Run("success", func(t *testing.T) {
+		rev := model.NewSongRevision(song, "jane@example.com")
+		if err := repo.CreateRevision(context.TODO(), song.UUID, &rev); err != nil {
+			t.Fatalf("CreateRevision(ctx, %q, &rev) returned an unexpected error: %s", song.UUID, err)
+		}
+		if rev.UUID == uuid.Nil {
+			t.Errorf("CreateRevision(ctx, %q, &rev) produced rev.UUID = <uuid.Nil>, expected a valid UUID", song.UUID)
+		}
+		if rev.CreatedAt.IsZero() {
+			t.Errorf("CreateRevision(ctx, %q, &rev) produced rev.CreatedAt = 0, expected a valid date", song.UUID)
+		}
+
+		actual, err := repo.GetRevision(context.TODO(), song.UUID, rev.UUID)
+		if err != nil {
+			t.Fatalf("GetRevision(ctx, %q, %q) returned an unexpected error: %s", song.UUID, rev.UUID, err)
+		}
+		if actual.Author != rev.Author || actual.Title != song.Title || !slices.Equal(actual.Artists, song.Artists) {
+			t.Errorf("GetRevision(ctx, %q, %q) did not return the recorded data", song.UUID, rev.UUID)
+		}
+		if len(actual.NotesP1) != len(song.NotesP1) {
+			t.Errorf("GetRevision(ctx, %q, %q) returned %d notes, expected %d", song.UUID, rev.UUID, len(actual.NotesP1), len(song.NotesP1))
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		id := uuid.New()
+		rev := model.SongRevision{}
+		err := repo.CreateRevision(context.TODO(), id, &rev)
+		if !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("CreateRevision(ctx, %q, &rev) returned %v, expected ErrNotFound", id, err)
+		}
+	})
+}
+
+func Test_dbRepo_FindRevisions(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song := testdata.SimpleSong(t, db)
+	revs := make([]model.SongRevision, 3)
+	for i := range revs {
+		revs[i] = model.NewSongRevision(song, "")
+		if err := repo.CreateRevision(context.TODO(), song.UUID, &revs[i]); err != nil {
+			t.Fatalf("CreateRevision(ctx, %q, &rev) returned an unexpected error: %s", song.UUID, err)
+		}
+	}
+
+	actual, total, err := repo.FindRevisions(context.TODO(), song.UUID, 2, 0)
+	if err != nil {
+		t.Fatalf("FindRevisions(ctx, %q, 2, 0) returned an unexpected error: %s", song.UUID, err)
+	}
+	if total != 3 {
+		t.Errorf("FindRevisions(ctx, %q, 2, 0) = _, %d, _, expected %d", song.UUID, total, 3)
+	}
+	if len(actual) != 2 || actual[0].UUID != revs[2].UUID {
+		t.Errorf("FindRevisions(ctx, %q, 2, 0) did not return the newest revisions first", song.UUID)
+	}
+
+	prev, err := repo.GetPreviousRevision(context.TODO(), song.UUID, revs[2].UUID)
+	if err != nil {
+		t.Errorf("GetPreviousRevision(ctx, %q, %q) returned an unexpected error: %s", song.UUID, revs[2].UUID, err)
+	} else if prev.UUID != revs[1].UUID {
+		t.Errorf("GetPreviousRevision(ctx, %q, %q) = %q, expected %q", song.UUID, revs[2].UUID, prev.UUID, revs[1].UUID)
+	}
+	if _, err = repo.GetPreviousRevision(context.TODO(), song.UUID, revs[0].UUID); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("GetPreviousRevision(ctx, %q, %q) returned %v, expected ErrNotFound", song.UUID, revs[0].UUID, err)
+	}
+}
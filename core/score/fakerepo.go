@@ -0,0 +1,87 @@
+package score
+
+import (
+	"cmp"
+	"context"
+	"math"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// fakeRepo is a simple implementation of Repository that can be used for testing.
+// The fake repository does not know about songs, so statistics of any song UUID are stored and returned.
+type fakeRepo struct {
+	// stats and scores are the "database" of a fakeRepo.
+	stats  map[uuid.UUID]model.PlayStats
+	scores map[uuid.UUID][]model.Highscore
+}
+
+// NewFakeRepository returns a new Repository implementation backed by in-memory maps.
+func NewFakeRepository() Repository {
+	return &fakeRepo{
+		make(map[uuid.UUID]model.PlayStats),
+		make(map[uuid.UUID][]model.Highscore),
+	}
+}
+
+// ReplaceStats stores stats and scores, replacing existing values of the same songs.
+func (r *fakeRepo) ReplaceStats(_ context.Context, stats []model.PlayStats, scores []model.Highscore) error {
+	for _, s := range stats {
+		r.stats[s.Song] = s
+		delete(r.scores, s.Song)
+	}
+	for _, s := range scores {
+		r.scores[s.Song] = append(r.scores[s.Song], s)
+	}
+	return nil
+}
+
+// FindPlayStats returns the stored play statistics, most played songs first.
+func (r *fakeRepo) FindPlayStats(_ context.Context, limit int, offset int64) ([]model.PlayStats, int64, error) {
+	stats := make([]model.PlayStats, 0, len(r.stats))
+	for _, s := range r.stats {
+		if s.TimesPlayed > 0 {
+			stats = append(stats, s)
+		}
+	}
+	slices.SortFunc(stats, func(a, b model.PlayStats) int {
+		return cmp.Or(cmp.Compare(b.TimesPlayed, a.TimesPlayed), b.LastPlayed.Compare(a.LastPlayed))
+	})
+	return paginate(stats, limit, offset)
+}
+
+// FindHighscores returns the stored highscores matching filter, highest scores first.
+func (r *fakeRepo) FindHighscores(_ context.Context, filter Filter, limit int, offset int64) ([]model.Highscore, int64, error) {
+	scores := make([]model.Highscore, 0)
+	for song, ss := range r.scores {
+		if filter.Song != uuid.Nil && song != filter.Song {
+			continue
+		}
+		for _, s := range ss {
+			if filter.Difficulty == "" || s.Difficulty == filter.Difficulty {
+				scores = append(scores, s)
+			}
+		}
+	}
+	slices.SortStableFunc(scores, func(a, b model.Highscore) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return paginate(scores, limit, offset)
+}
+
+// paginate applies limit and offset to values.
+// The second return value is the total number of values.
+func paginate[T any](values []T, limit int, offset int64) ([]T, int64, error) {
+	if limit < 0 {
+		limit = math.MaxInt
+	}
+	total := int64(len(values))
+	if offset > total {
+		offset = total
+	}
+	values = values[offset:]
+	return values[:min(limit, len(values))], total, nil
+}
@@ -0,0 +1,49 @@
+package score
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func Test_fakeRepo_Stats(t *testing.T) {
+	t.Parallel()
+
+	repo := NewFakeRepository()
+	song1, song2 := uuid.New(), uuid.New()
+	stats := []model.PlayStats{{Song: song1, TimesPlayed: 2}, {Song: song2, TimesPlayed: 5, LastPlayed: time.Now()}}
+	scores := []model.Highscore{
+		{Song: song1, Player: "Alice", Difficulty: model.GameDifficultyEasy, Score: 5000},
+		{Song: song2, Player: "Bob", Difficulty: model.GameDifficultyHard, Score: 9000},
+	}
+	if err := repo.ReplaceStats(context.TODO(), stats, scores); err != nil {
+		t.Fatalf("ReplaceStats(ctx, stats, scores) returned an unexpected error: %s", err)
+	}
+	actual, total, err := repo.FindPlayStats(context.TODO(), 1, 0)
+	if err != nil {
+		t.Fatalf("FindPlayStats(ctx, 1, 0) returned an unexpected error: %s", err)
+	}
+	if total != 2 || len(actual) != 1 || actual[0].Song != song2 {
+		t.Errorf("FindPlayStats(ctx, 1, 0) returned %v (total %d), expected only %s (total 2)", actual, total, song2)
+	}
+
+	// replacing the stats of song2 removes its scores
+	if err = repo.ReplaceStats(context.TODO(), []model.PlayStats{{Song: song2, TimesPlayed: 1}}, nil); err != nil {
+		t.Fatalf("ReplaceStats(ctx, stats, nil) returned an unexpected error: %s", err)
+	}
+	highscores, total, err := repo.FindHighscores(context.TODO(), Filter{}, -1, 0)
+	if err != nil {
+		t.Fatalf("FindHighscores(ctx, {}, -1, 0) returned an unexpected error: %s", err)
+	}
+	if total != 1 || highscores[0].Player != "Alice" {
+		t.Errorf("FindHighscores(ctx, {}, -1, 0) returned %v, expected only the score of Alice", highscores)
+	}
+	_, total, _ = repo.FindHighscores(context.TODO(), Filter{Difficulty: model.GameDifficultyHard}, -1, 0)
+	if total != 0 {
+		t.Errorf("FindHighscores(ctx, {hard}, -1, 0) returned %d results, expected 0", total)
+	}
+}
@@ -0,0 +1,80 @@
+package score
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// A Result summarizes an import of play statistics.
+type Result struct {
+	// Matched is the number of imported songs that matched a song in the library.
+	Matched int
+	// Scores is the number of imported highscores.
+	Scores int
+	// Unmatched contains the imported songs that did not match any song in the library.
+	Unmatched []USDXSong
+}
+
+// Resolve matches entries against songs and converts them into play statistics and highscores.
+// The songs must have been prepared by the song service.
+// If multiple entries match the same song, their statistics are combined.
+// Entries without a matching song are returned in Result.Unmatched.
+func Resolve(entries []USDXSong, songs []model.Song) ([]model.PlayStats, []model.Highscore, Result) {
+	m := NewMatcher(songs)
+	var result Result
+	stats := make([]model.PlayStats, 0)
+	scores := make([]model.Highscore, 0)
+	index := make(map[uuid.UUID]int)
+	for _, e := range entries {
+		id, ok := m.Match(e.Artist, e.Title)
+		if !ok {
+			result.Unmatched = append(result.Unmatched, e)
+			continue
+		}
+		result.Matched++
+		i, ok := index[id]
+		if !ok {
+			i = len(stats)
+			index[id] = i
+			stats = append(stats, model.PlayStats{Song: id})
+		}
+		stats[i].TimesPlayed += e.TimesPlayed
+		if last := e.LastPlayed(); last.After(stats[i].LastPlayed) {
+			stats[i].LastPlayed = last
+		}
+		for _, s := range e.Scores {
+			scores = append(scores, model.Highscore{
+				Song:       id,
+				Player:     s.Player,
+				Difficulty: s.Difficulty,
+				Score:      s.Score,
+				Date:       s.Date,
+			})
+		}
+	}
+	result.Scores = len(scores)
+	return stats, scores, result
+}
+
+// Import matches entries against the songs in the library and stores their play statistics and highscores in repo.
+// Statistics of matched songs are replaced.
+// The songs are fetched from songRepo and prepared by songSvc.
+// Songs in uploads or in the trash are not matched.
+func Import(ctx context.Context, entries []USDXSong, repo Repository, songRepo song.Repository, songSvc song.Service) (Result, error) {
+	songs, _, err := songRepo.FindSongs(ctx, song.Filter{}, -1, 0)
+	if err != nil {
+		return Result{}, err
+	}
+	for i := range songs {
+		songSvc.Prepare(ctx, &songs[i])
+	}
+	stats, scores, result := Resolve(entries, songs)
+	if err = repo.ReplaceStats(ctx, stats, scores); err != nil {
+		return Result{}, err
+	}
+	return result, nil
+}
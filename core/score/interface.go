@@ -0,0 +1,45 @@
+package score
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// A Repository is an interface for storing play statistics and highscores of songs.
+// Statistics reference songs by their UUID.
+// Callers are responsible for only storing statistics of existing songs.
+type Repository interface {
+	// ReplaceStats stores the play statistics and highscores of the songs in stats.
+	// Existing statistics and highscores of these songs are replaced.
+	// Every highscore in scores must belong to a song in stats.
+	// Statistics of other songs are not affected.
+	ReplaceStats(ctx context.Context, stats []model.PlayStats, scores []model.Highscore) error
+
+	// FindPlayStats returns the play statistics of all songs that have been played at least once,
+	// ordered by the number of plays, most played songs first.
+	// Songs in uploads or in the trash are not included.
+	// Results are paginated with limit and offset.
+	// The second return value contains the total (unpaginated) number of results.
+	FindPlayStats(ctx context.Context, limit int, offset int64) ([]model.PlayStats, int64, error)
+
+	// FindHighscores returns the highscores matching filter, ordered by score, highest scores first.
+	// Highscores of songs in uploads or in the trash are not included.
+	// Results are paginated with limit and offset.
+	// The second return value contains the total (unpaginated) number of results.
+	FindHighscores(ctx context.Context, filter Filter, limit int, offset int64) ([]model.Highscore, int64, error)
+}
+
+// A Filter restricts the highscores returned by Repository.FindHighscores.
+// The zero value matches all highscores.
+type Filter struct {
+	// Song restricts the results to highscores of the song with this UUID.
+	// uuid.Nil matches all songs.
+	Song uuid.UUID
+
+	// Difficulty restricts the results to highscores achieved at this difficulty.
+	// The empty string matches all difficulties.
+	Difficulty model.GameDifficulty
+}
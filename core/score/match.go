@@ -0,0 +1,92 @@
+package score
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core/internal/textmatch"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// Thresholds for fuzzy matching.
+// If the titles of two songs are equal, their artists must have at least a similarity of minArtistSimilarity.
+// If the artists are equal, their titles must have at least a similarity of minTitleSimilarity.
+const (
+	minArtistSimilarity = 0.8
+	minTitleSimilarity  = 0.85
+)
+
+// A Matcher finds songs by their artist and title.
+// Artists and titles are compared ignoring case, punctuation, diacritics, bracketed additions such as "(Radio Edit)"
+// and featured artists.
+// If no song matches exactly, songs with a similar artist or title are considered.
+type Matcher struct {
+	// songs maps normalized titles to normalized artists to song UUIDs.
+	songs map[string]map[string]uuid.UUID
+	// artists maps normalized artists to normalized titles of their songs.
+	artists map[string][]string
+}
+
+// NewMatcher creates a Matcher for songs.
+// The songs must have been prepared by the song service so that their artist is set.
+// If multiple songs have the same artist and title, the first one is matched.
+func NewMatcher(songs []model.Song) *Matcher {
+	m := &Matcher{
+		songs:   make(map[string]map[string]uuid.UUID),
+		artists: make(map[string][]string),
+	}
+	for _, song := range songs {
+		title := textmatch.Normalize(song.Title)
+		m.add(textmatch.Normalize(song.Artist), title, song.UUID)
+		for _, artist := range song.Artists {
+			m.add(textmatch.Normalize(artist), title, song.UUID)
+		}
+	}
+	return m
+}
+
+// add adds a song with the normalized artist and title to m.
+func (m *Matcher) add(artist, title string, id uuid.UUID) {
+	if artist == "" || title == "" {
+		return
+	}
+	byArtist, ok := m.songs[title]
+	if !ok {
+		byArtist = make(map[string]uuid.UUID)
+		m.songs[title] = byArtist
+	}
+	if _, ok = byArtist[artist]; !ok {
+		byArtist[artist] = id
+		m.artists[artist] = append(m.artists[artist], title)
+	}
+}
+
+// Match returns the UUID of the song with the specified artist and title.
+// If no song matches, the second return value is false.
+func (m *Matcher) Match(artist, title string) (uuid.UUID, bool) {
+	artist, title = textmatch.Normalize(artist), textmatch.Normalize(title)
+	if artist == "" || title == "" {
+		return uuid.Nil, false
+	}
+	if id, ok := m.songs[title][artist]; ok {
+		return id, true
+	}
+	best, bestSimilarity := "", minArtistSimilarity
+	for candidate := range m.songs[title] {
+		if s := textmatch.Similarity(artist, candidate); s > bestSimilarity || (s == bestSimilarity && (best == "" || candidate < best)) {
+			best, bestSimilarity = candidate, s
+		}
+	}
+	if best != "" {
+		return m.songs[title][best], true
+	}
+	bestSimilarity = minTitleSimilarity
+	for _, candidate := range m.artists[artist] {
+		if s := textmatch.Similarity(title, candidate); s > bestSimilarity || (s == bestSimilarity && (best == "" || candidate < best)) {
+			best, bestSimilarity = candidate, s
+		}
+	}
+	if best != "" {
+		return m.songs[best][artist], true
+	}
+	return uuid.Nil, false
+}
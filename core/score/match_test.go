@@ -0,0 +1,63 @@
+package score
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func TestMatcher_Match(t *testing.T) {
+	songs := []model.Song{
+		{Model: model.Model{UUID: uuid.New()}, Artists: []string{"Queen"}},
+		{Model: model.Model{UUID: uuid.New()}, Artists: []string{"Simon", "Garfunkel"}},
+		{Model: model.Model{UUID: uuid.New()}},
+	}
+	songs[0].Artist, songs[0].Title = "Queen", "Bohemian Rhapsody"
+	songs[1].Artist, songs[1].Title = "Simon & Garfunkel", "The Sound of Silence"
+	songs[2].Artist, songs[2].Title = "Beyoncé", "Crazy in Love"
+	m := NewMatcher(songs)
+
+	cases := map[string]struct {
+		artist, title string
+		expected      uuid.UUID
+	}{
+		"exact":           {"Queen", "Bohemian Rhapsody", songs[0].UUID},
+		"normalized":      {"QUEEN", "Bohemian Rhapsody (Remastered 2011)", songs[0].UUID},
+		"single artist":   {"Garfunkel", "The Sound of Silence", songs[1].UUID},
+		"similar artist":  {"Beyonce Knowles", "Crazy in Love", uuid.Nil},
+		"artist typo":     {"Beyonse", "Crazy in Love", songs[2].UUID},
+		"title typo":      {"Queen", "Bohemian Rapsody", songs[0].UUID},
+		"different title": {"Queen", "Don't Stop Me Now", uuid.Nil},
+		"unknown":         {"Unknown Artist", "Unknown Song", uuid.Nil},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, ok := m.Match(c.artist, c.title)
+			if ok != (c.expected != uuid.Nil) || actual != c.expected {
+				t.Errorf("m.Match(%q, %q) = %s, %t, expected %s", c.artist, c.title, actual, ok, c.expected)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	song := model.Song{Model: model.Model{UUID: uuid.New()}}
+	song.Artist, song.Title = "Queen", "Bohemian Rhapsody"
+	entries := []USDXSong{
+		{"Queen", "Bohemian Rhapsody", 3, []USDXScore{{Player: "Freddie", Score: 9000}}},
+		{"Queen", "Bohemian Rhapsody (Live)", 2, []USDXScore{{Player: "Brian", Score: 8000}}},
+		{"ABBA", "Waterloo", 1, nil},
+	}
+	stats, scores, result := Resolve(entries, []model.Song{song})
+	if len(stats) != 1 || stats[0].Song != song.UUID || stats[0].TimesPlayed != 5 {
+		t.Errorf("Resolve() returned stats %v, expected a single song played 5 times", stats)
+	}
+	if len(scores) != 2 || result.Scores != 2 {
+		t.Errorf("Resolve() returned %d scores, expected 2", len(scores))
+	}
+	if result.Matched != 2 || len(result.Unmatched) != 1 || result.Unmatched[0].Title != "Waterloo" {
+		t.Errorf("Resolve() returned %d matched and unmatched %v, expected 2 matched and [ABBA : Waterloo] unmatched", result.Matched, result.Unmatched)
+	}
+}
@@ -0,0 +1,188 @@
+package score
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// dbRepo is the main Repository implementation, backed by a PostgreSQL database.
+type dbRepo struct {
+	logger *slog.Logger
+	db     pgxutil.DB // database connection
+}
+
+// NewDBRepository creates a new Repository backed by the specified database connection.
+// db can be a single connection or a connection pool.
+func NewDBRepository(logger *slog.Logger, db pgxutil.DB) Repository {
+	return &dbRepo{logger, db}
+}
+
+// playStatsRow is the data returned by a SELECT query for play statistics.
+type playStatsRow struct {
+	Song        uuid.UUID
+	TimesPlayed int        `db:"times_played"`
+	LastPlayed  *time.Time `db:"last_played"`
+}
+
+// toModel converts r into an equivalent model.PlayStats.
+func (r playStatsRow) toModel() model.PlayStats {
+	stats := model.PlayStats{
+		Song:        r.Song,
+		TimesPlayed: r.TimesPlayed,
+	}
+	if r.LastPlayed != nil {
+		stats.LastPlayed = *r.LastPlayed
+	}
+	return stats
+}
+
+// highscoreRow is the data returned by a SELECT query for highscores.
+type highscoreRow struct {
+	Song       uuid.UUID
+	Player     string
+	Difficulty string
+	Score      int
+	PlayedAt   *time.Time `db:"played_at"`
+}
+
+// toModel converts r into an equivalent model.Highscore.
+func (r highscoreRow) toModel() model.Highscore {
+	score := model.Highscore{
+		Song:       r.Song,
+		Player:     r.Player,
+		Difficulty: model.GameDifficulty(r.Difficulty),
+		Score:      r.Score,
+	}
+	if r.PlayedAt != nil {
+		score.Date = *r.PlayedAt
+	}
+	return score
+}
+
+// nullTime converts t into a value for a nullable timestamp column.
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// ReplaceStats replaces the statistics and highscores of the songs in stats in a single transaction.
+// Songs that do not exist in the database are ignored.
+func (r *dbRepo) ReplaceStats(ctx context.Context, stats []model.PlayStats, scores []model.Highscore) error {
+	songs := make([]uuid.UUID, len(stats))
+	played := make([]int, len(stats))
+	last := make([]*time.Time, len(stats))
+	for i, s := range stats {
+		songs[i] = s.Song
+		played[i] = s.TimesPlayed
+		last[i] = nullTime(s.LastPlayed)
+	}
+	scoreSongs := make([]uuid.UUID, len(scores))
+	players := make([]string, len(scores))
+	difficulties := make([]string, len(scores))
+	values := make([]int, len(scores))
+	dates := make([]*time.Time, len(scores))
+	for i, s := range scores {
+		scoreSongs[i] = s.Song
+		players[i] = s.Player
+		difficulties[i] = string(s.Difficulty)
+		values[i] = s.Score
+		dates[i] = nullTime(s.Date)
+	}
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM song_scores
+		WHERE song_id IN (SELECT id FROM songs WHERE uuid = ANY($1))`, songs); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO song_plays (song_id, times_played, last_played)
+		SELECT s.id, p.times_played, p.last_played
+		FROM UNNEST($1::UUID[], $2::INTEGER[], $3::TIMESTAMP[]) AS p(song, times_played, last_played)
+		JOIN songs AS s ON s.uuid = p.song
+		ON CONFLICT (song_id) DO UPDATE SET times_played = excluded.times_played, last_played = excluded.last_played`,
+			songs, played, last); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `INSERT INTO song_scores (song_id, player, difficulty, score, played_at)
+		SELECT s.id, h.player, h.difficulty, h.score, h.played_at
+		FROM UNNEST($1::UUID[], $2::TEXT[], $3::TEXT[], $4::INTEGER[], $5::TIMESTAMP[]) AS h(song, player, difficulty, score, played_at)
+		JOIN songs AS s ON s.uuid = h.song`,
+			scoreSongs, players, difficulties, values, dates)
+		return err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not save play statistics.", "songs", len(stats), "scores", len(scores), tint.Err(err))
+		return err
+	}
+	return nil
+}
+
+// FindPlayStats fetches play statistics from the database, most played songs first.
+// The results are paginated with limit and offset.
+func (r *dbRepo) FindPlayStats(ctx context.Context, limit int, offset int64) ([]model.PlayStats, int64, error) {
+	total, err := pgxutil.SelectRow(ctx, r.db, `SELECT COUNT(*)
+	FROM song_plays AS p
+	JOIN songs AS s ON s.id = p.song_id
+	WHERE p.times_played > 0 AND s.upload_id IS NULL AND s.deleted_at IS NULL`, nil, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not count play statistics.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, 0, err
+	}
+	stats, err := pgxutil.Select(ctx, r.db, `SELECT s.uuid AS song, p.times_played, p.last_played
+	FROM song_plays AS p
+	JOIN songs AS s ON s.id = p.song_id
+	WHERE p.times_played > 0 AND s.upload_id IS NULL AND s.deleted_at IS NULL
+	ORDER BY p.times_played DESC, p.last_played DESC NULLS LAST, s.id
+	LIMIT CASE WHEN $1 < 0 THEN NULL ELSE $1 END OFFSET $2`, []any{limit, offset}, func(row pgx.CollectableRow) (model.PlayStats, error) {
+		data, err := pgx.RowToStructByName[playStatsRow](row)
+		return data.toModel(), err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list play statistics.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, total, err
+	}
+	return stats, total, nil
+}
+
+// FindHighscores fetches highscores matching filter from the database, highest scores first.
+// The results are paginated with limit and offset.
+func (r *dbRepo) FindHighscores(ctx context.Context, filter Filter, limit int, offset int64) ([]model.Highscore, int64, error) {
+	const where = `WHERE s.upload_id IS NULL AND s.deleted_at IS NULL
+	  AND ($1::UUID IS NULL OR s.uuid = $1)
+	  AND ($2 = '' OR h.difficulty = $2)`
+	var song *uuid.UUID
+	if filter.Song != uuid.Nil {
+		song = &filter.Song
+	}
+	args := []any{song, string(filter.Difficulty)}
+	total, err := pgxutil.SelectRow(ctx, r.db, `SELECT COUNT(*)
+	FROM song_scores AS h
+	JOIN songs AS s ON s.id = h.song_id
+	`+where, args, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not count highscores.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, 0, err
+	}
+	scores, err := pgxutil.Select(ctx, r.db, `SELECT s.uuid AS song, h.player, h.difficulty, h.score, h.played_at
+	FROM song_scores AS h
+	JOIN songs AS s ON s.id = h.song_id
+	`+where+`
+	ORDER BY h.score DESC, h.played_at NULLS LAST, h.id
+	LIMIT CASE WHEN $3 < 0 THEN NULL ELSE $3 END OFFSET $4`, append(args, limit, offset), func(row pgx.CollectableRow) (model.Highscore, error) {
+		data, err := pgx.RowToStructByName[highscoreRow](row)
+		return data.toModel(), err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list highscores.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, total, err
+	}
+	return scores, total, nil
+}
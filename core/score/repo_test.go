@@ -0,0 +1,112 @@
+//go:build database
+
+package score
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func Test_dbRepo_ReplaceStats(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song := testdata.SimpleSong(t, db)
+	other := testdata.SimpleSong(t, db)
+	testdata.PlayStats(t, db, song.UUID, 10)
+	testdata.Highscore(t, db, song.UUID, "Old", model.GameDifficultyEasy, 1000)
+	otherScore := testdata.Highscore(t, db, other.UUID, "Other", model.GameDifficultyEasy, 2000)
+
+	date := time.Date(2024, 3, 1, 20, 0, 0, 0, time.UTC)
+	stats := []model.PlayStats{{Song: song.UUID, TimesPlayed: 3, LastPlayed: date}, {Song: uuid.New(), TimesPlayed: 7}}
+	scores := []model.Highscore{{Song: song.UUID, Player: "New", Difficulty: model.GameDifficultyHard, Score: 9000, Date: date}}
+	if err := repo.ReplaceStats(context.TODO(), stats, scores); err != nil {
+		t.Fatalf("ReplaceStats(ctx, stats, scores) returned an unexpected error: %s", err)
+	}
+
+	actual, total, err := repo.FindPlayStats(context.TODO(), -1, 0)
+	if err != nil {
+		t.Fatalf("FindPlayStats(ctx, -1, 0) returned an unexpected error: %s", err)
+	}
+	if total != 1 || actual[0].Song != song.UUID || actual[0].TimesPlayed != 3 || !actual[0].LastPlayed.Equal(date) {
+		t.Errorf("FindPlayStats(ctx, -1, 0) returned %v, expected %v", actual, stats[:1])
+	}
+	highscores, total, err := repo.FindHighscores(context.TODO(), Filter{}, -1, 0)
+	if err != nil {
+		t.Fatalf("FindHighscores(ctx, {}, -1, 0) returned an unexpected error: %s", err)
+	}
+	if total != 2 || highscores[0].Player != "New" || highscores[1].Player != otherScore.Player {
+		t.Errorf("FindHighscores(ctx, {}, -1, 0) returned %v, expected the new score and the score of the other song", highscores)
+	}
+}
+
+func Test_dbRepo_FindPlayStats(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song1 := testdata.SimpleSong(t, db)
+	song2 := testdata.SimpleSong(t, db)
+	deleted := testdata.DeletedSong(t, db)
+	testdata.PlayStats(t, db, song1.UUID, 2)
+	testdata.PlayStats(t, db, song2.UUID, 8)
+	testdata.PlayStats(t, db, deleted.UUID, 20)
+
+	stats, total, err := repo.FindPlayStats(context.TODO(), 1, 0)
+	if err != nil {
+		t.Fatalf("FindPlayStats(ctx, 1, 0) returned an unexpected error: %s", err)
+	}
+	if total != 2 {
+		t.Errorf("FindPlayStats(ctx, 1, 0) returned total %d, expected 2", total)
+	}
+	if len(stats) != 1 || stats[0].Song != song2.UUID || stats[0].TimesPlayed != 8 {
+		t.Errorf("FindPlayStats(ctx, 1, 0) returned %v, expected %s played 8 times", stats, song2.UUID)
+	}
+}
+
+func Test_dbRepo_FindHighscores(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song1 := testdata.SimpleSong(t, db)
+	song2 := testdata.SimpleSong(t, db)
+	testdata.Highscore(t, db, song1.UUID, "Alice", model.GameDifficultyEasy, 5000)
+	testdata.Highscore(t, db, song1.UUID, "Bob", model.GameDifficultyHard, 9000)
+	testdata.Highscore(t, db, song2.UUID, "Carol", model.GameDifficultyHard, 7000)
+
+	cases := map[string]struct {
+		filter   Filter
+		expected []string
+	}{
+		"all":        {Filter{}, []string{"Bob", "Carol", "Alice"}},
+		"song":       {Filter{Song: song1.UUID}, []string{"Bob", "Alice"}},
+		"difficulty": {Filter{Difficulty: model.GameDifficultyHard}, []string{"Bob", "Carol"}},
+		"both":       {Filter{Song: song2.UUID, Difficulty: model.GameDifficultyEasy}, []string{}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			scores, total, err := repo.FindHighscores(context.TODO(), c.filter, -1, 0)
+			if err != nil {
+				t.Fatalf("FindHighscores(ctx, %v, -1, 0) returned an unexpected error: %s", c.filter, err)
+			}
+			players := make([]string, len(scores))
+			for i, s := range scores {
+				players[i] = s.Player
+			}
+			if total != int64(len(c.expected)) || !slices.Equal(players, c.expected) {
+				t.Errorf("FindHighscores(ctx, %v, -1, 0) returned %v (total %d), expected %v", c.filter, players, total, c.expected)
+			}
+		})
+	}
+}
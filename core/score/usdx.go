@@ -0,0 +1,143 @@
+package score
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/sqlite"
+)
+
+// ErrInvalidDatabase indicates that a file is not a database of UltraStar Deluxe.
+var ErrInvalidDatabase = errors.New("not an UltraStar Deluxe database")
+
+// A USDXSong is a song in the database of UltraStar Deluxe.
+// UltraStar Deluxe identifies songs by their artist and title.
+type USDXSong struct {
+	Artist      string
+	Title       string
+	TimesPlayed int
+	Scores      []USDXScore
+}
+
+// String returns the song in the form "Artist : Title", as used in UltraStar playlists.
+func (s USDXSong) String() string {
+	return s.Artist + " : " + s.Title
+}
+
+// LastPlayed returns the date of the most recent score of s.
+// If s has no scores with a date, the zero time is returned.
+func (s USDXSong) LastPlayed() time.Time {
+	var last time.Time
+	for _, score := range s.Scores {
+		if score.Date.After(last) {
+			last = score.Date
+		}
+	}
+	return last
+}
+
+// A USDXScore is a highscore in the database of UltraStar Deluxe.
+type USDXScore struct {
+	Player     string
+	Difficulty model.GameDifficulty
+	Score      int
+	Date       time.Time
+}
+
+// usdxDifficulties maps the difficulty values of UltraStar Deluxe to game difficulties.
+var usdxDifficulties = map[int64]model.GameDifficulty{
+	0: model.GameDifficultyEasy,
+	1: model.GameDifficultyMedium,
+	2: model.GameDifficultyHard,
+}
+
+// ReadUSDX reads the songs and highscores from the Ultrastar.db database file of UltraStar Deluxe in r.
+// Songs are returned in the order of the database.
+// If r does not contain a database of UltraStar Deluxe, an error wrapping ErrInvalidDatabase is returned.
+func ReadUSDX(r io.ReaderAt) ([]USDXSong, error) {
+	db, err := sqlite.Open(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDatabase, err)
+	}
+	songTable, err := db.Table("us_songs")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDatabase, err)
+	}
+	scoreTable, err := db.Table("us_scores")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDatabase, err)
+	}
+	id, artist, title, played := songTable.Column("ID"), songTable.Column("Artist"), songTable.Column("Title"), songTable.Column("TimesPlayed")
+	if id < 0 || artist < 0 || title < 0 || played < 0 {
+		return nil, fmt.Errorf("%w: missing columns in table us_songs", ErrInvalidDatabase)
+	}
+	songID, difficulty, player, score, date := scoreTable.Column("SongID"), scoreTable.Column("Difficulty"), scoreTable.Column("Player"), scoreTable.Column("Score"), scoreTable.Column("Date")
+	if songID < 0 || difficulty < 0 || player < 0 || score < 0 {
+		return nil, fmt.Errorf("%w: missing columns in table us_scores", ErrInvalidDatabase)
+	}
+
+	songs := make([]USDXSong, 0, len(songTable.Rows))
+	index := make(map[int64]int, len(songTable.Rows))
+	for _, row := range songTable.Rows {
+		index[toInt(row[id])] = len(songs)
+		songs = append(songs, USDXSong{
+			Artist:      strings.TrimSpace(toString(row[artist])),
+			Title:       strings.TrimSpace(toString(row[title])),
+			TimesPlayed: int(toInt(row[played])),
+		})
+	}
+	for _, row := range scoreTable.Rows {
+		i, ok := index[toInt(row[songID])]
+		if !ok {
+			// scores of deleted songs may remain in the database
+			continue
+		}
+		s := USDXScore{
+			Player:     strings.TrimSpace(toString(row[player])),
+			Difficulty: usdxDifficulties[toInt(row[difficulty])],
+			Score:      int(toInt(row[score])),
+		}
+		if s.Difficulty == "" {
+			s.Difficulty = model.GameDifficultyMedium
+		}
+		if date >= 0 {
+			if ts := toInt(row[date]); ts > 0 {
+				s.Date = time.Unix(ts, 0).UTC()
+			}
+		}
+		songs[i].Scores = append(songs[i].Scores, s)
+	}
+	return songs, nil
+}
+
+// toString converts a value read from an SQLite database into a string.
+// UltraStar Deluxe stores some texts as BLOBs.
+func toString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return strings.ToValidUTF8(string(v), "�")
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// toInt converts a value read from an SQLite database into an integer.
+// Values that are not numbers are converted to 0.
+func toInt(v any) int64 {
+	switch v := v.(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,59 @@
+package score
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func TestReadUSDX(t *testing.T) {
+	f, err := os.Open("testdata/Ultrastar.db")
+	if err != nil {
+		t.Fatalf("os.Open() returned an unexpected error: %s", err)
+	}
+	defer f.Close()
+
+	songs, err := ReadUSDX(f)
+	if err != nil {
+		t.Fatalf("ReadUSDX(f) returned an unexpected error: %s", err)
+	}
+	if len(songs) != 3 {
+		t.Fatalf("ReadUSDX(f) returned %d songs, expected 3", len(songs))
+	}
+	queen := songs[0]
+	if queen.String() != "Queen : Bohemian Rhapsody" || queen.TimesPlayed != 12 {
+		t.Errorf("ReadUSDX(f) returned %q played %d times, expected %q played 12 times", queen, queen.TimesPlayed, "Queen : Bohemian Rhapsody")
+	}
+	if len(queen.Scores) != 2 {
+		t.Fatalf("ReadUSDX(f) returned %d scores for %q, expected 2", len(queen.Scores), queen)
+	}
+	expected := USDXScore{"Brian", model.GameDifficultyHard, 7200, time.Unix(1710000000, 0).UTC()}
+	if queen.Scores[1] != expected {
+		t.Errorf("ReadUSDX(f) returned score %v, expected %v", queen.Scores[1], expected)
+	}
+	if !queen.LastPlayed().Equal(expected.Date) {
+		t.Errorf("queen.LastPlayed() = %s, expected %s", queen.LastPlayed(), expected.Date)
+	}
+	if abba := songs[1]; len(abba.Scores) != 1 || !abba.Scores[0].Date.IsZero() || !abba.LastPlayed().IsZero() {
+		t.Errorf("ReadUSDX(f) returned scores %v for %q, expected a single score without date", abba.Scores, abba)
+	}
+}
+
+func TestReadUSDX_Invalid(t *testing.T) {
+	f, err := os.Open("../../pkg/sqlite/testdata/utf16.db")
+	if err != nil {
+		t.Fatalf("os.Open() returned an unexpected error: %s", err)
+	}
+	defer f.Close()
+
+	if _, err := ReadUSDX(f); !errors.Is(err, ErrInvalidDatabase) {
+		t.Errorf("ReadUSDX(f) returned error %v, expected %v", err, ErrInvalidDatabase)
+	}
+	if _, err := ReadUSDX(strings.NewReader("#TITLE:Not a database")); !errors.Is(err, ErrInvalidDatabase) {
+		t.Errorf("ReadUSDX(txt) returned error %v, expected %v", err, ErrInvalidDatabase)
+	}
+}
@@ -0,0 +1,43 @@
+package session
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+)
+
+const (
+	// codeAlphabet contains the characters of join codes.
+	// Characters that are easily confused, such as O and 0, are omitted.
+	codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	// codeLength is the number of characters of a join code.
+	codeLength = 6
+	// codeAttempts is the number of codes that are tried before creating a session fails.
+	codeAttempts = 5
+)
+
+// newCode generates a random join code.
+func newCode() string {
+	b := make([]byte, codeLength)
+	n := big.NewInt(int64(len(codeAlphabet)))
+	for i := range b {
+		j, err := rand.Int(rand.Reader, n)
+		if err != nil {
+			// crypto/rand does not fail on supported platforms
+			panic(err)
+		}
+		b[i] = codeAlphabet[j.Int64()]
+	}
+	return string(b)
+}
+
+// NormalizeCode converts code into the canonical form of join codes.
+// Join codes are case-insensitive and guests may enter them with spaces or dashes.
+func NormalizeCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(code))
+}
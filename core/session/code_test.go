@@ -0,0 +1,37 @@
+package session
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_newCode(t *testing.T) {
+	t.Parallel()
+
+	code := newCode()
+	if len(code) != codeLength {
+		t.Errorf("newCode() returned %q, expected %d characters", code, codeLength)
+	}
+	if strings.Trim(code, codeAlphabet) != "" {
+		t.Errorf("newCode() returned %q, expected only characters from %q", code, codeAlphabet)
+	}
+	if NormalizeCode(code) != code {
+		t.Errorf("NormalizeCode(%q) = %q, expected the code to be normalized", code, NormalizeCode(code))
+	}
+}
+
+func TestNormalizeCode(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"ABC234":   "ABC234",
+		"abc234":   "ABC234",
+		"abc-234":  "ABC234",
+		" ABC 234": "ABC234",
+	}
+	for code, expected := range cases {
+		if actual := NormalizeCode(code); actual != expected {
+			t.Errorf("NormalizeCode(%q) = %q, expected %q", code, actual, expected)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package session
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// fakeSession is a session together with its requests in queue order.
+type fakeSession struct {
+	model.Session
+	requests []model.SongRequest
+}
+
+// fakeRepo is a simple implementation of Repository that can be used for testing.
+// The fake repository does not know about songs, so any song can be requested.
+type fakeRepo struct {
+	// sessions is the "database" of a fakeRepo.
+	sessions map[uuid.UUID]*fakeSession
+}
+
+// NewFakeRepository returns a new Repository implementation backed by an in-memory map.
+func NewFakeRepository() Repository {
+	return &fakeRepo{make(map[uuid.UUID]*fakeSession)}
+}
+
+// CreateSession stores the session and sets its UUID, Code, CreatedAt, and UpdatedAt fields.
+func (r *fakeRepo) CreateSession(_ context.Context, session *model.Session) error {
+	session.Name = strings.TrimSpace(session.Name)
+	session.UUID = uuid.New()
+	session.Code = newCode()
+	session.CreatedAt = time.Now()
+	session.UpdatedAt = session.CreatedAt
+	r.sessions[session.UUID] = &fakeSession{Session: *session}
+	return nil
+}
+
+// GetSession looks up the session with the specified UUID.
+func (r *fakeRepo) GetSession(_ context.Context, id uuid.UUID) (model.Session, error) {
+	s, ok := r.sessions[id]
+	if !ok {
+		return model.Session{}, core.ErrNotFound
+	}
+	return s.Session, nil
+}
+
+// GetSessionByCode looks up the session with the specified join code.
+func (r *fakeRepo) GetSessionByCode(_ context.Context, code string) (model.Session, error) {
+	code = NormalizeCode(code)
+	for _, s := range r.sessions {
+		if s.Code == code {
+			return s.Session, nil
+		}
+	}
+	return model.Session{}, core.ErrNotFound
+}
+
+// FindSessions returns a list of sessions, the most recent session first, limited by the specified pagination parameters.
+func (r *fakeRepo) FindSessions(_ context.Context, limit int, offset int64) ([]model.Session, int64, error) {
+	if limit < 0 {
+		limit = math.MaxInt
+	}
+	sessions := make([]model.Session, 0, len(r.sessions))
+	for _, s := range r.sessions {
+		sessions = append(sessions, s.Session)
+	}
+	slices.SortFunc(sessions, func(a, b model.Session) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	total := int64(len(sessions))
+	if offset > total {
+		offset = total
+	}
+	sessions = sessions[offset:]
+	return sessions[:min(limit, len(sessions))], total, nil
+}
+
+// UpdateSession updates the name, request limit and closed state of session.
+func (r *fakeRepo) UpdateSession(_ context.Context, session *model.Session) error {
+	s, ok := r.sessions[session.UUID]
+	if !ok {
+		return core.ErrNotFound
+	}
+	session.Name = strings.TrimSpace(session.Name)
+	session.Code = s.Code
+	session.CreatedAt = s.CreatedAt
+	session.UpdatedAt = time.Now()
+	s.Session = *session
+	return nil
+}
+
+// DeleteSession removes the session with the specified UUID.
+func (r *fakeRepo) DeleteSession(_ context.Context, id uuid.UUID) (bool, error) {
+	if _, ok := r.sessions[id]; !ok {
+		return false, nil
+	}
+	delete(r.sessions, id)
+	return true, nil
+}
+
+// CreateRequest adds request to the end of the queue of the session.
+func (r *fakeRepo) CreateRequest(_ context.Context, session uuid.UUID, request *model.SongRequest) error {
+	s, ok := r.sessions[session]
+	if !ok {
+		return core.ErrNotFound
+	}
+	if s.Closed {
+		return ErrSessionClosed
+	}
+	prepareRequest(request)
+	if s.RequestLimit > 0 {
+		count := 0
+		for _, req := range s.requests {
+			if req.State == model.RequestStateQueued && strings.EqualFold(req.Singer, request.Singer) {
+				count++
+			}
+		}
+		if count >= s.RequestLimit {
+			return ErrLimitExceeded
+		}
+	}
+	request.UUID = uuid.New()
+	request.State = model.RequestStateQueued
+	request.CreatedAt = time.Now()
+	request.UpdatedAt = request.CreatedAt
+	s.requests = append(s.requests, *request)
+	return nil
+}
+
+// GetRequest looks up the request with the specified UUID in the session.
+func (r *fakeRepo) GetRequest(_ context.Context, session uuid.UUID, id uuid.UUID) (model.SongRequest, error) {
+	s, ok := r.sessions[session]
+	if !ok {
+		return model.SongRequest{}, core.ErrNotFound
+	}
+	i := s.index(id)
+	if i < 0 {
+		return model.SongRequest{}, core.ErrNotFound
+	}
+	return s.requests[i], nil
+}
+
+// FindRequests returns the requests of the session in queue order, filtered by states.
+func (r *fakeRepo) FindRequests(_ context.Context, session uuid.UUID, states ...model.RequestState) ([]model.SongRequest, error) {
+	requests := make([]model.SongRequest, 0)
+	s, ok := r.sessions[session]
+	if !ok {
+		return requests, nil
+	}
+	for _, req := range s.requests {
+		if len(states) == 0 || slices.Contains(states, req.State) {
+			requests = append(requests, req)
+		}
+	}
+	return requests, nil
+}
+
+// UpdateRequestState sets the state of request.
+// A request that is queued again is moved to the end of the queue.
+func (r *fakeRepo) UpdateRequestState(_ context.Context, session uuid.UUID, request *model.SongRequest) error {
+	s, ok := r.sessions[session]
+	if !ok {
+		return core.ErrNotFound
+	}
+	i := s.index(request.UUID)
+	if i < 0 {
+		return core.ErrNotFound
+	}
+	req := s.requests[i]
+	requeued := request.State == model.RequestStateQueued && req.State != model.RequestStateQueued
+	req.State = request.State
+	req.UpdatedAt = time.Now()
+	if requeued {
+		s.requests = append(slices.Delete(s.requests, i, i+1), req)
+	} else {
+		s.requests[i] = req
+	}
+	*request = req
+	return nil
+}
+
+// ReorderRequests places the queued requests of the session in the specified order after all other requests.
+func (r *fakeRepo) ReorderRequests(_ context.Context, session uuid.UUID, order []uuid.UUID) error {
+	s, ok := r.sessions[session]
+	if !ok {
+		return core.ErrNotFound
+	}
+	queued := make([]uuid.UUID, 0, len(order))
+	for _, req := range s.requests {
+		if req.State == model.RequestStateQueued {
+			queued = append(queued, req.UUID)
+		}
+	}
+	if !sameRequests(queued, order) {
+		return ErrInvalidOrder
+	}
+	requests := slices.DeleteFunc(slices.Clone(s.requests), func(req model.SongRequest) bool {
+		return req.State == model.RequestStateQueued
+	})
+	for _, id := range order {
+		requests = append(requests, s.requests[s.index(id)])
+	}
+	s.requests = requests
+	return nil
+}
+
+// DeleteRequest removes the request with the specified UUID from the session.
+func (r *fakeRepo) DeleteRequest(_ context.Context, session uuid.UUID, id uuid.UUID) (bool, error) {
+	s, ok := r.sessions[session]
+	if !ok {
+		return false, nil
+	}
+	i := s.index(id)
+	if i < 0 {
+		return false, nil
+	}
+	s.requests = slices.Delete(s.requests, i, i+1)
+	return true, nil
+}
+
+// index returns the index of the request with the specified UUID in s.requests or -1.
+func (s *fakeSession) index(id uuid.UUID) int {
+	return slices.IndexFunc(s.requests, func(req model.SongRequest) bool {
+		return req.UUID == id
+	})
+}
@@ -0,0 +1,61 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func Test_fakeRepo_Requests(t *testing.T) {
+	t.Parallel()
+
+	repo := NewFakeRepository()
+	session := model.Session{Name: "Office Party", RequestLimit: 1}
+	if err := repo.CreateSession(context.TODO(), &session); err != nil {
+		t.Fatalf("CreateSession(ctx, &session) returned an unexpected error: %s", err)
+	}
+	if actual, err := repo.GetSessionByCode(context.TODO(), " "+session.Code[:3]+"-"+session.Code[3:]+" "); err != nil || actual.UUID != session.UUID {
+		t.Errorf("GetSessionByCode(ctx, %q) returned %q, %v, expected %q, <nil>", session.Code, actual.UUID, err, session.UUID)
+	}
+
+	first := model.SongRequest{Song: uuid.New(), Singer: "Alice"}
+	if err := repo.CreateRequest(context.TODO(), session.UUID, &first); err != nil {
+		t.Fatalf("CreateRequest(ctx, %q, &request) returned an unexpected error: %s", session.UUID, err)
+	}
+	second := model.SongRequest{Song: uuid.New(), Singer: "alice"}
+	if err := repo.CreateRequest(context.TODO(), session.UUID, &second); !errors.Is(err, ErrLimitExceeded) {
+		t.Errorf("CreateRequest(ctx, %q, &request) returned %v, expected ErrLimitExceeded", session.UUID, err)
+	}
+	second.Singer = "Bob"
+	if err := repo.CreateRequest(context.TODO(), session.UUID, &second); err != nil {
+		t.Fatalf("CreateRequest(ctx, %q, &request) returned an unexpected error: %s", session.UUID, err)
+	}
+
+	if err := repo.ReorderRequests(context.TODO(), session.UUID, []uuid.UUID{second.UUID}); !errors.Is(err, ErrInvalidOrder) {
+		t.Errorf("ReorderRequests(ctx, %q, order) returned %v, expected ErrInvalidOrder", session.UUID, err)
+	}
+	if err := repo.ReorderRequests(context.TODO(), session.UUID, []uuid.UUID{second.UUID, first.UUID}); err != nil {
+		t.Errorf("ReorderRequests(ctx, %q, order) returned an unexpected error: %s", session.UUID, err)
+	}
+	second.State = model.RequestStateSung
+	if err := repo.UpdateRequestState(context.TODO(), session.UUID, &second); err != nil {
+		t.Errorf("UpdateRequestState(ctx, %q, &request) returned an unexpected error: %s", session.UUID, err)
+	}
+	queue, _ := repo.FindRequests(context.TODO(), session.UUID, model.RequestStateQueued)
+	if len(queue) != 1 || queue[0].UUID != first.UUID {
+		t.Errorf("FindRequests(ctx, %q, %q) returned %d requests, expected only %q", session.UUID, model.RequestStateQueued, len(queue), first.UUID)
+	}
+
+	session.Closed = true
+	if err := repo.UpdateSession(context.TODO(), &session); err != nil {
+		t.Fatalf("UpdateSession(ctx, &session) returned an unexpected error: %s", err)
+	}
+	third := model.SongRequest{Song: uuid.New(), Singer: "Carol"}
+	if err := repo.CreateRequest(context.TODO(), session.UUID, &third); !errors.Is(err, ErrSessionClosed) {
+		t.Errorf("CreateRequest(ctx, %q, &request) returned %v, expected ErrSessionClosed", session.UUID, err)
+	}
+}
@@ -0,0 +1,83 @@
+package session
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// These errors are returned if a song request cannot be accepted.
+var (
+	// ErrSessionClosed indicates that a session does not accept new requests.
+	ErrSessionClosed = errors.New("session is closed")
+	// ErrLimitExceeded indicates that a guest has reached the request limit of a session.
+	ErrLimitExceeded = errors.New("request limit exceeded")
+	// ErrInvalidOrder indicates that a new order of the queue does not contain exactly the queued requests.
+	ErrInvalidOrder = errors.New("order does not match the queue")
+)
+
+// A Repository is an interface for storing party sessions and their song requests.
+// Requests reference songs by their UUID.
+// Callers are responsible for only requesting existing songs.
+type Repository interface {
+	// CreateSession creates a new session with the specified name and request limit.
+	// This method generates a new join code for the session.
+	// This method must set session.UUID, session.Code, session.CreatedAt, and session.UpdatedAt appropriately.
+	CreateSession(ctx context.Context, session *model.Session) error
+
+	// GetSession fetches the session with the specified UUID.
+	// If no such session exists, core.ErrNotFound will be returned.
+	GetSession(ctx context.Context, id uuid.UUID) (model.Session, error)
+
+	// GetSessionByCode fetches the session with the specified join code.
+	// Join codes are case-insensitive.
+	// If no such session exists, core.ErrNotFound will be returned.
+	GetSessionByCode(ctx context.Context, code string) (model.Session, error)
+
+	// FindSessions returns all sessions, the most recent session first.
+	// Results are paginated with limit and offset.
+	// The second return value contains the total (unpaginated) number of sessions.
+	FindSessions(ctx context.Context, limit int, offset int64) ([]model.Session, int64, error)
+
+	// UpdateSession saves the name, request limit and closed state of the specified session.
+	// The session's UUID must already exist in the database, otherwise core.ErrNotFound will be returned.
+	UpdateSession(ctx context.Context, session *model.Session) error
+
+	// DeleteSession deletes the session with the specified UUID including all of its requests.
+	// If no such session exists, the first return value will be false.
+	DeleteSession(ctx context.Context, id uuid.UUID) (bool, error)
+
+	// CreateRequest adds request to the end of the queue of the specified session.
+	// If the session is closed, ErrSessionClosed is returned.
+	// If the session has a request limit and the singer already has that many queued requests,
+	// ErrLimitExceeded is returned.
+	// Guests are identified by the name of the singer, ignoring case.
+	// If the session or the song does not exist, core.ErrNotFound is returned.
+	// This method must set request.UUID, request.State, request.CreatedAt, and request.UpdatedAt appropriately.
+	CreateRequest(ctx context.Context, session uuid.UUID, request *model.SongRequest) error
+
+	// GetRequest fetches the request with the specified UUID from the specified session.
+	// If no such request exists, core.ErrNotFound will be returned.
+	GetRequest(ctx context.Context, session uuid.UUID, id uuid.UUID) (model.SongRequest, error)
+
+	// FindRequests returns the requests of the specified session in queue order.
+	// If states are specified, only requests in one of the states are returned.
+	FindRequests(ctx context.Context, session uuid.UUID, states ...model.RequestState) ([]model.SongRequest, error)
+
+	// UpdateRequestState sets the state of the specified request.
+	// A request that is queued again is moved to the end of the queue.
+	// If no such request exists, core.ErrNotFound will be returned.
+	UpdateRequestState(ctx context.Context, session uuid.UUID, request *model.SongRequest) error
+
+	// ReorderRequests changes the order of the queue of the specified session.
+	// order must contain the UUIDs of all queued requests of the session exactly once,
+	// otherwise ErrInvalidOrder is returned.
+	ReorderRequests(ctx context.Context, session uuid.UUID, order []uuid.UUID) error
+
+	// DeleteRequest deletes the request with the specified UUID from the specified session.
+	// If no such request exists, the first return value will be false.
+	DeleteRequest(ctx context.Context, session uuid.UUID, id uuid.UUID) (bool, error)
+}
@@ -0,0 +1,373 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/internal/dbutil"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// dbRepo is the main Repository implementation, backed by a PostgreSQL database.
+type dbRepo struct {
+	logger *slog.Logger
+	db     pgxutil.DB // database connection
+}
+
+// NewDBRepository creates a new Repository backed by the specified database connection.
+// db can be a single connection or a connection pool.
+func NewDBRepository(logger *slog.Logger, db pgxutil.DB) Repository {
+	return &dbRepo{logger, db}
+}
+
+// sessionColumns selects the columns of a session.
+const sessionColumns = `uuid, created_at, updated_at, name, code, request_limit, closed`
+
+// sessionRow is the data returned by a SELECT query for sessions.
+type sessionRow struct {
+	UUID         uuid.UUID
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+	Name         string
+	Code         string
+	RequestLimit int `db:"request_limit"`
+	Closed       bool
+}
+
+// toModel converts r into an equivalent model.Session.
+func (r sessionRow) toModel() model.Session {
+	return model.Session{
+		Model: model.Model{
+			UUID:      r.UUID,
+			CreatedAt: r.CreatedAt,
+			UpdatedAt: r.UpdatedAt,
+		},
+		Name:         r.Name,
+		Code:         r.Code,
+		RequestLimit: r.RequestLimit,
+		Closed:       r.Closed,
+	}
+}
+
+// requestColumns selects the columns of a request r joined with its song s.
+const requestColumns = `r.uuid, r.created_at, r.updated_at, s.uuid AS song, r.singer, r.partner, r.state`
+
+// requestRow is the data returned by a SELECT query for requests.
+type requestRow struct {
+	UUID      uuid.UUID
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	Song      uuid.UUID
+	Singer    string
+	Partner   string
+	State     model.RequestState
+}
+
+// toModel converts r into an equivalent model.SongRequest.
+func (r requestRow) toModel() model.SongRequest {
+	return model.SongRequest{
+		Model: model.Model{
+			UUID:      r.UUID,
+			CreatedAt: r.CreatedAt,
+			UpdatedAt: r.UpdatedAt,
+		},
+		Song:    r.Song,
+		Singer:  r.Singer,
+		Partner: r.Partner,
+		State:   r.State,
+	}
+}
+
+// CreateSession creates session in the database.
+// If the generated join code is already in use, another code is tried.
+func (r *dbRepo) CreateSession(ctx context.Context, session *model.Session) error {
+	session.Name = strings.TrimSpace(session.Name)
+	var err error
+	for i := 0; i < codeAttempts; i++ {
+		var row sessionRow
+		row, err = pgxutil.InsertRowReturning(ctx, r.db, "sessions", map[string]any{
+			"name":          session.Name,
+			"code":          newCode(),
+			"request_limit": session.RequestLimit,
+			"closed":        session.Closed,
+		}, sessionColumns, pgx.RowToStructByName[sessionRow])
+		if err = dbutil.Error(err); err == nil {
+			*session = row.toModel()
+			return nil
+		} else if !errors.Is(err, core.ErrConflict) {
+			break
+		}
+	}
+	r.logger.ErrorContext(ctx, "Could not create session.", "name", session.Name, tint.Err(err))
+	return err
+}
+
+// GetSession fetches a single session from the database by its UUID.
+func (r *dbRepo) GetSession(ctx context.Context, id uuid.UUID) (model.Session, error) {
+	row, err := pgxutil.SelectRow(ctx, r.db, `SELECT `+sessionColumns+` FROM sessions WHERE uuid = $1`,
+		[]any{id}, pgx.RowToStructByName[sessionRow])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not fetch session.", "uuid", id, tint.Err(err))
+		}
+		return model.Session{}, dbutil.Error(err)
+	}
+	return row.toModel(), nil
+}
+
+// GetSessionByCode fetches a single session from the database by its join code.
+func (r *dbRepo) GetSessionByCode(ctx context.Context, code string) (model.Session, error) {
+	code = NormalizeCode(code)
+	row, err := pgxutil.SelectRow(ctx, r.db, `SELECT `+sessionColumns+` FROM sessions WHERE code = $1`,
+		[]any{code}, pgx.RowToStructByName[sessionRow])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not fetch session.", "code", code, tint.Err(err))
+		}
+		return model.Session{}, dbutil.Error(err)
+	}
+	return row.toModel(), nil
+}
+
+// FindSessions fetches multiple sessions from the database, the most recent session first.
+// The results are paginated with limit and offset.
+func (r *dbRepo) FindSessions(ctx context.Context, limit int, offset int64) ([]model.Session, int64, error) {
+	total, err := pgxutil.SelectRow(ctx, r.db, `SELECT COUNT(*) FROM sessions`, nil, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not count sessions.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, 0, err
+	}
+	sessions, err := pgxutil.Select(ctx, r.db, `SELECT `+sessionColumns+`
+	FROM sessions
+	ORDER BY created_at DESC, id DESC
+	LIMIT CASE WHEN $1 < 0 THEN NULL ELSE $1 END OFFSET $2`, []any{limit, offset}, func(row pgx.CollectableRow) (model.Session, error) {
+		data, err := pgx.RowToStructByName[sessionRow](row)
+		return data.toModel(), err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list sessions.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, total, err
+	}
+	return sessions, total, nil
+}
+
+// UpdateSession updates the session in the database with session.UUID.
+func (r *dbRepo) UpdateSession(ctx context.Context, session *model.Session) error {
+	session.Name = strings.TrimSpace(session.Name)
+	row, err := pgxutil.UpdateRowReturning(ctx, r.db, "sessions", map[string]any{
+		"name":          session.Name,
+		"request_limit": session.RequestLimit,
+		"closed":        session.Closed,
+	}, map[string]any{
+		"uuid": session.UUID,
+	}, sessionColumns, pgx.RowToStructByName[sessionRow])
+	if err != nil {
+		if err = dbutil.Error(err); !errors.Is(err, core.ErrNotFound) {
+			r.logger.ErrorContext(ctx, "Could not update session.", "uuid", session.UUID, tint.Err(err))
+		}
+		return err
+	}
+	*session = row.toModel()
+	return nil
+}
+
+// DeleteSession deletes the session with the specified UUID from the database.
+func (r *dbRepo) DeleteSession(ctx context.Context, id uuid.UUID) (bool, error) {
+	_, err := pgxutil.ExecRow(ctx, r.db, `DELETE FROM sessions WHERE uuid = $1`, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not delete session.", "uuid", id, tint.Err(err))
+		return false, err
+	}
+	return true, nil
+}
+
+// lockedSession is the data of a session that is needed to accept requests.
+type lockedSession struct {
+	ID           int
+	RequestLimit int `db:"request_limit"`
+	Closed       bool
+}
+
+// CreateRequest adds request to the queue of the specified session.
+// The session is locked while the request limit is checked.
+func (r *dbRepo) CreateRequest(ctx context.Context, session uuid.UUID, request *model.SongRequest) error {
+	prepareRequest(request)
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		s, err := pgxutil.SelectRow(ctx, tx, `SELECT id, request_limit, closed FROM sessions WHERE uuid = $1 FOR UPDATE`,
+			[]any{session}, pgx.RowToStructByName[lockedSession])
+		if err != nil {
+			return err
+		}
+		if s.Closed {
+			return ErrSessionClosed
+		}
+		if s.RequestLimit > 0 {
+			count, err := pgxutil.SelectRow(ctx, tx, `SELECT COUNT(*) FROM session_requests
+			WHERE session_id = $1 AND state = $2 AND LOWER(singer) = LOWER($3)`,
+				[]any{s.ID, model.RequestStateQueued, request.Singer}, pgx.RowTo[int])
+			if err != nil {
+				return err
+			}
+			if count >= s.RequestLimit {
+				return ErrLimitExceeded
+			}
+		}
+		row, err := pgxutil.SelectRow(ctx, tx, `INSERT INTO session_requests (session_id, song_id, singer, partner, state, position)
+		SELECT $1, s.id, $3, $4, $5, (SELECT COALESCE(MAX(position), 0) + 1 FROM session_requests WHERE session_id = $1)
+		FROM songs AS s WHERE s.uuid = $2
+		RETURNING uuid, created_at, updated_at, $2::UUID AS song, singer, partner, state`,
+			[]any{s.ID, request.Song, request.Singer, request.Partner, model.RequestStateQueued}, pgx.RowToStructByName[requestRow])
+		if err != nil {
+			return err
+		}
+		*request = row.toModel()
+		return nil
+	})
+	if err != nil {
+		if err = dbutil.Error(err); !errors.Is(err, core.ErrNotFound) && !errors.Is(err, ErrSessionClosed) && !errors.Is(err, ErrLimitExceeded) {
+			r.logger.ErrorContext(ctx, "Could not create request.", "session", session, "song", request.Song, tint.Err(err))
+		}
+		return err
+	}
+	return nil
+}
+
+// GetRequest fetches a single request from the database.
+func (r *dbRepo) GetRequest(ctx context.Context, session uuid.UUID, id uuid.UUID) (model.SongRequest, error) {
+	row, err := pgxutil.SelectRow(ctx, r.db, `SELECT `+requestColumns+`
+	FROM session_requests AS r
+	JOIN songs AS s ON s.id = r.song_id
+	JOIN sessions AS se ON se.id = r.session_id
+	WHERE se.uuid = $1 AND r.uuid = $2`, []any{session, id}, pgx.RowToStructByName[requestRow])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not fetch request.", "session", session, "uuid", id, tint.Err(err))
+		}
+		return model.SongRequest{}, dbutil.Error(err)
+	}
+	return row.toModel(), nil
+}
+
+// FindRequests fetches the requests of a session from the database in queue order.
+func (r *dbRepo) FindRequests(ctx context.Context, session uuid.UUID, states ...model.RequestState) ([]model.SongRequest, error) {
+	filter := make([]string, len(states))
+	for i, state := range states {
+		filter[i] = string(state)
+	}
+	requests, err := pgxutil.Select(ctx, r.db, `SELECT `+requestColumns+`
+	FROM session_requests AS r
+	JOIN songs AS s ON s.id = r.song_id
+	JOIN sessions AS se ON se.id = r.session_id
+	WHERE se.uuid = $1 AND (CARDINALITY($2::TEXT[]) = 0 OR r.state = ANY($2))
+	ORDER BY r.position, r.id`, []any{session, filter}, func(row pgx.CollectableRow) (model.SongRequest, error) {
+		data, err := pgx.RowToStructByName[requestRow](row)
+		return data.toModel(), err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list requests.", "session", session, "states", states, tint.Err(err))
+		return nil, err
+	}
+	return requests, nil
+}
+
+// UpdateRequestState updates the state of the request in the database.
+func (r *dbRepo) UpdateRequestState(ctx context.Context, session uuid.UUID, request *model.SongRequest) error {
+	row, err := pgxutil.SelectRow(ctx, r.db, `UPDATE session_requests AS r
+	SET state = $3,
+	    position = CASE WHEN $3 = $4 AND r.state <> $4
+	        THEN (SELECT MAX(position) + 1 FROM session_requests WHERE session_id = r.session_id)
+	        ELSE r.position END
+	FROM sessions AS se, songs AS s
+	WHERE se.id = r.session_id AND s.id = r.song_id AND se.uuid = $1 AND r.uuid = $2
+	RETURNING `+requestColumns, []any{session, request.UUID, request.State, model.RequestStateQueued}, pgx.RowToStructByName[requestRow])
+	if err != nil {
+		if err = dbutil.Error(err); !errors.Is(err, core.ErrNotFound) {
+			r.logger.ErrorContext(ctx, "Could not update request.", "session", session, "uuid", request.UUID, tint.Err(err))
+		}
+		return err
+	}
+	*request = row.toModel()
+	return nil
+}
+
+// ReorderRequests assigns new positions to the queued requests of a session.
+// The reordered requests are placed after all other requests of the session.
+func (r *dbRepo) ReorderRequests(ctx context.Context, session uuid.UUID, order []uuid.UUID) error {
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		id, err := pgxutil.SelectRow(ctx, tx, `SELECT id FROM sessions WHERE uuid = $1 FOR UPDATE`, []any{session}, pgx.RowTo[int])
+		if err != nil {
+			return err
+		}
+		queued, err := pgxutil.Select(ctx, tx, `SELECT uuid FROM session_requests WHERE session_id = $1 AND state = $2`,
+			[]any{id, model.RequestStateQueued}, pgx.RowTo[uuid.UUID])
+		if err != nil {
+			return err
+		}
+		if !sameRequests(queued, order) {
+			return ErrInvalidOrder
+		}
+		_, err = tx.Exec(ctx, `UPDATE session_requests AS r
+		SET position = o.position + (SELECT MAX(position) FROM session_requests WHERE session_id = $1)
+		FROM UNNEST($2::UUID[]) WITH ORDINALITY AS o(uuid, position)
+		WHERE r.session_id = $1 AND r.uuid = o.uuid`, id, order)
+		return err
+	})
+	if err != nil {
+		if err = dbutil.Error(err); !errors.Is(err, core.ErrNotFound) && !errors.Is(err, ErrInvalidOrder) {
+			r.logger.ErrorContext(ctx, "Could not reorder requests.", "session", session, tint.Err(err))
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteRequest deletes the request with the specified UUID from the database.
+func (r *dbRepo) DeleteRequest(ctx context.Context, session uuid.UUID, id uuid.UUID) (bool, error) {
+	_, err := pgxutil.ExecRow(ctx, r.db, `DELETE FROM session_requests AS r
+	USING sessions AS se
+	WHERE se.id = r.session_id AND se.uuid = $1 AND r.uuid = $2`, session, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not delete request.", "session", session, "uuid", id, tint.Err(err))
+		return false, err
+	}
+	return true, nil
+}
+
+// prepareRequest normalizes the names of request.
+func prepareRequest(request *model.SongRequest) {
+	request.Singer = strings.TrimSpace(request.Singer)
+	request.Partner = strings.TrimSpace(request.Partner)
+}
+
+// sameRequests reports whether order contains exactly the UUIDs in queued, each exactly once.
+func sameRequests(queued []uuid.UUID, order []uuid.UUID) bool {
+	if len(queued) != len(order) {
+		return false
+	}
+	seen := make(map[uuid.UUID]bool, len(queued))
+	for _, id := range queued {
+		seen[id] = false
+	}
+	for _, id := range order {
+		if done, ok := seen[id]; !ok || done {
+			return false
+		}
+		seen[id] = true
+	}
+	return true
+}
@@ -0,0 +1,199 @@
+//go:build database
+
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func Test_dbRepo_CreateSession(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+
+	session := model.Session{Name: " Office Party ", RequestLimit: 2}
+	if err := repo.CreateSession(context.TODO(), &session); err != nil {
+		t.Fatalf("CreateSession(ctx, &session) returned an unexpected error: %s", err)
+	}
+	if session.UUID == uuid.Nil {
+		t.Errorf("CreateSession(ctx, &session) produced session.UUID = <uuid.Nil>, expected a valid UUID")
+	}
+	if len(session.Code) != codeLength {
+		t.Errorf("CreateSession(ctx, &session) produced session.Code = %q, expected a code of length %d", session.Code, codeLength)
+	}
+	if session.Name != "Office Party" {
+		t.Errorf("CreateSession(ctx, &session) produced name %q, expected %q", session.Name, "Office Party")
+	}
+}
+
+func Test_dbRepo_GetSessionByCode(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	expected := testdata.Session(t, db, "Office Party", 0)
+
+	t.Run("success", func(t *testing.T) {
+		actual, err := repo.GetSessionByCode(context.TODO(), " "+expected.Code+" ")
+		if err != nil {
+			t.Fatalf("GetSessionByCode(ctx, %q) returned an unexpected error: %s", expected.Code, err)
+		}
+		if actual.UUID != expected.UUID {
+			t.Errorf("GetSessionByCode(ctx, %q) returned session %q, expected %q", expected.Code, actual.UUID, expected.UUID)
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		if _, err := repo.GetSessionByCode(context.TODO(), "ZZZZZZ"); !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("GetSessionByCode(ctx, %q) returned %v, expected ErrNotFound", "ZZZZZZ", err)
+		}
+	})
+}
+
+func Test_dbRepo_UpdateSession(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	session := testdata.Session(t, db, "Office Party", 0)
+
+	session.Name = "Christmas Party"
+	session.Closed = true
+	if err := repo.UpdateSession(context.TODO(), &session); err != nil {
+		t.Fatalf("UpdateSession(ctx, &session) returned an unexpected error: %s", err)
+	}
+	actual, err := repo.GetSession(context.TODO(), session.UUID)
+	if err != nil {
+		t.Fatalf("GetSession(ctx, %q) returned an unexpected error: %s", session.UUID, err)
+	}
+	if actual.Name != session.Name || !actual.Closed {
+		t.Errorf("GetSession(ctx, %q) returned %q (closed: %t), expected %q (closed: true)", session.UUID, actual.Name, actual.Closed, session.Name)
+	}
+}
+
+func Test_dbRepo_DeleteSession(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	session := testdata.Session(t, db, "Office Party", 0)
+
+	if ok, err := repo.DeleteSession(context.TODO(), session.UUID); !ok || err != nil {
+		t.Errorf("DeleteSession(ctx, %q) returned %t, %v, expected true, <nil>", session.UUID, ok, err)
+	}
+	if ok, err := repo.DeleteSession(context.TODO(), session.UUID); ok || err != nil {
+		t.Errorf("DeleteSession(ctx, %q) returned %t, %v, expected false, <nil>", session.UUID, ok, err)
+	}
+}
+
+func Test_dbRepo_CreateRequest(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song := testdata.SimpleSong(t, db)
+	session := testdata.Session(t, db, "Office Party", 1)
+
+	t.Run("success", func(t *testing.T) {
+		request := model.SongRequest{Song: song.UUID, Singer: " Alice ", Partner: "Bob"}
+		if err := repo.CreateRequest(context.TODO(), session.UUID, &request); err != nil {
+			t.Fatalf("CreateRequest(ctx, %q, &request) returned an unexpected error: %s", session.UUID, err)
+		}
+		if request.UUID == uuid.Nil || request.Song != song.UUID || request.Singer != "Alice" || request.State != model.RequestStateQueued {
+			t.Errorf("CreateRequest(ctx, %q, &request) produced %+v, expected a queued request for %q by %q", session.UUID, request, song.UUID, "Alice")
+		}
+	})
+	t.Run("limit exceeded", func(t *testing.T) {
+		request := model.SongRequest{Song: song.UUID, Singer: "ALICE"}
+		if err := repo.CreateRequest(context.TODO(), session.UUID, &request); !errors.Is(err, ErrLimitExceeded) {
+			t.Errorf("CreateRequest(ctx, %q, &request) returned %v, expected ErrLimitExceeded", session.UUID, err)
+		}
+	})
+	t.Run("song not found", func(t *testing.T) {
+		request := model.SongRequest{Song: uuid.New(), Singer: "Carol"}
+		if err := repo.CreateRequest(context.TODO(), session.UUID, &request); !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("CreateRequest(ctx, %q, &request) returned %v, expected ErrNotFound", session.UUID, err)
+		}
+	})
+	t.Run("closed", func(t *testing.T) {
+		closed := testdata.Session(t, db, "Closed Party", 0)
+		closed.Closed = true
+		if err := repo.UpdateSession(context.TODO(), &closed); err != nil {
+			t.Fatalf("UpdateSession(ctx, &session) returned an unexpected error: %s", err)
+		}
+		request := model.SongRequest{Song: song.UUID, Singer: "Alice"}
+		if err := repo.CreateRequest(context.TODO(), closed.UUID, &request); !errors.Is(err, ErrSessionClosed) {
+			t.Errorf("CreateRequest(ctx, %q, &request) returned %v, expected ErrSessionClosed", closed.UUID, err)
+		}
+	})
+}
+
+func Test_dbRepo_Queue(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song := testdata.SimpleSong(t, db)
+	session := testdata.Session(t, db, "Office Party", 0)
+	requests := make([]model.SongRequest, 3)
+	for i := range requests {
+		requests[i] = model.SongRequest{Song: song.UUID, Singer: "Alice"}
+		if err := repo.CreateRequest(context.TODO(), session.UUID, &requests[i]); err != nil {
+			t.Fatalf("CreateRequest(ctx, %q, &request) returned an unexpected error: %s", session.UUID, err)
+		}
+	}
+	assertQueue := func(t *testing.T, expected ...uuid.UUID) {
+		t.Helper()
+		queue, err := repo.FindRequests(context.TODO(), session.UUID, model.RequestStateQueued)
+		if err != nil {
+			t.Fatalf("FindRequests(ctx, %q, %q) returned an unexpected error: %s", session.UUID, model.RequestStateQueued, err)
+		}
+		actual := make([]uuid.UUID, len(queue))
+		for i, req := range queue {
+			actual[i] = req.UUID
+		}
+		if len(actual) != len(expected) {
+			t.Fatalf("FindRequests(ctx, %q, %q) returned %v, expected %v", session.UUID, model.RequestStateQueued, actual, expected)
+		}
+		for i := range actual {
+			if actual[i] != expected[i] {
+				t.Fatalf("FindRequests(ctx, %q, %q) returned %v, expected %v", session.UUID, model.RequestStateQueued, actual, expected)
+			}
+		}
+	}
+
+	if err := repo.ReorderRequests(context.TODO(), session.UUID, []uuid.UUID{requests[2].UUID, requests[0].UUID}); !errors.Is(err, ErrInvalidOrder) {
+		t.Errorf("ReorderRequests(ctx, %q, order) returned %v, expected ErrInvalidOrder", session.UUID, err)
+	}
+	if err := repo.ReorderRequests(context.TODO(), session.UUID, []uuid.UUID{requests[2].UUID, requests[0].UUID, requests[1].UUID}); err != nil {
+		t.Fatalf("ReorderRequests(ctx, %q, order) returned an unexpected error: %s", session.UUID, err)
+	}
+	assertQueue(t, requests[2].UUID, requests[0].UUID, requests[1].UUID)
+
+	requests[2].State = model.RequestStateSkipped
+	if err := repo.UpdateRequestState(context.TODO(), session.UUID, &requests[2]); err != nil {
+		t.Fatalf("UpdateRequestState(ctx, %q, &request) returned an unexpected error: %s", session.UUID, err)
+	}
+	assertQueue(t, requests[0].UUID, requests[1].UUID)
+
+	requests[2].State = model.RequestStateQueued
+	if err := repo.UpdateRequestState(context.TODO(), session.UUID, &requests[2]); err != nil {
+		t.Fatalf("UpdateRequestState(ctx, %q, &request) returned an unexpected error: %s", session.UUID, err)
+	}
+	assertQueue(t, requests[0].UUID, requests[1].UUID, requests[2].UUID)
+
+	if ok, err := repo.DeleteRequest(context.TODO(), session.UUID, requests[0].UUID); !ok || err != nil {
+		t.Errorf("DeleteRequest(ctx, %q, %q) returned %t, %v, expected true, <nil>", session.UUID, requests[0].UUID, ok, err)
+	}
+	assertQueue(t, requests[1].UUID, requests[2].UUID)
+}
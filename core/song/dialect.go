@@ -0,0 +1,172 @@
+package song
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"codello.dev/ultrastar"
+	"codello.dev/ultrastar/txt"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// A Dialect describes a variant of the UltraStar TXT format.
+// Different karaoke programs and versions thereof understand different headers and timing modes.
+// The zero value writes songs exactly as the txt package does.
+type Dialect struct {
+	// Version is the value of the #VERSION header.
+	// If Version is empty, no #VERSION header is written.
+	Version string
+	// MP3 and Audio indicate whether the audio file is referenced via the #MP3 and #AUDIO headers respectively.
+	MP3   bool
+	Audio bool
+	// Relative indicates that notes are written in relative mode,
+	// where the beats of each line are relative to the preceding line break.
+	Relative bool
+
+	// custom is set for all dialects except the zero value.
+	custom bool
+}
+
+// dialectVersions are the known versions of the UltraStar TXT format.
+// See https://usdx.eu/format/ for details.
+var dialectVersions = map[string]Dialect{
+	// Version 1.0.0 is the legacy format that is understood by all programs.
+	"1.0.0": {MP3: true, custom: true},
+	// Version 1.1.0 introduces #AUDIO but keeps #MP3 for compatibility.
+	"1.1.0": {Version: "1.1.0", MP3: true, Audio: true, custom: true},
+	// Version 2.0.0 removes #MP3 as well as the relative mode.
+	"2.0.0": {Version: "2.0.0", Audio: true, custom: true},
+}
+
+// DialectVersions returns the known versions of the UltraStar TXT format in ascending order.
+func DialectVersions() []string {
+	return slices.Sorted(maps.Keys(dialectVersions))
+}
+
+// ParseDialect returns the dialect for the specified format version.
+// If relative is true, the dialect writes notes in relative mode.
+// If version is empty and relative is false, the zero Dialect is returned.
+// If version is empty and relative is true, version 1.0.0 is used.
+func ParseDialect(version string, relative bool) (Dialect, error) {
+	if version == "" {
+		if !relative {
+			return Dialect{}, nil
+		}
+		version = "1.0.0"
+	}
+	d, ok := dialectVersions[version]
+	if !ok {
+		return Dialect{}, fmt.Errorf("unknown version %q", version)
+	}
+	if relative && !d.MP3 {
+		// the relative mode has been removed together with #MP3
+		return Dialect{}, fmt.Errorf("version %s does not support relative mode", version)
+	}
+	d.Relative = relative
+	return d, nil
+}
+
+// IsZero reports whether d is the zero Dialect.
+func (d Dialect) IsZero() bool {
+	return !d.custom
+}
+
+// WriteTxt writes song as UltraStar TXT in the dialect d to w.
+// Songs are converted between absolute and relative mode as necessary.
+// In relative mode the medley of song is omitted because it cannot be expressed in relative beats.
+func WriteTxt(w io.Writer, song model.Song, d Dialect) error {
+	if d.IsZero() {
+		return txt.WriteSong(w, song.Song)
+	}
+	song.NotesP1 = slices.Clone(song.NotesP1)
+	song.NotesP2 = slices.Clone(song.NotesP2)
+	song.CustomTags = maps.Clone(song.CustomTags)
+	_ = applyTransform(&song, Transform{Op: ToAbsolute})
+	if d.Relative {
+		if song.CustomTags == nil {
+			song.CustomTags = make(map[string]string, 1)
+		}
+		song.CustomTags[relativeTag] = "yes"
+		song.NotesP1 = relativeNotes(song.NotesP1)
+		song.NotesP2 = relativeNotes(song.NotesP2)
+		song.MedleyStartBeat, song.MedleyEndBeat = 0, 0
+	}
+
+	b := &bytes.Buffer{}
+	if err := txt.WriteSong(b, song.Song); err != nil {
+		return err
+	}
+	return d.rewriteHeaders(w, b)
+}
+
+// rewriteHeaders copies the TXT data from r to w, replacing the #VERSION, #MP3 and #AUDIO headers according to d.
+// The #VERSION header is written first, the audio headers replace the first audio header in r.
+// Line endings in r are preserved, the #VERSION header uses the line ending of the first line in r.
+func (d Dialect) rewriteHeaders(w io.Writer, r io.Reader) error {
+	br := bufio.NewReader(r)
+	bw := bufio.NewWriter(w)
+	versionWritten := d.Version == ""
+	audioWritten := false
+	for {
+		line, err := br.ReadString('\n')
+		if !versionWritten {
+			versionWritten = true
+			ending := line[len(strings.TrimRight(line, "\r\n")):]
+			if ending == "" {
+				ending = "\n"
+			}
+			_, _ = fmt.Fprintf(bw, "#VERSION:%s%s", d.Version, ending)
+		}
+		if line != "" && !strings.HasPrefix(line, "#") {
+			// end of headers, copy the remainder
+			_, _ = bw.WriteString(line)
+			_, _ = io.Copy(bw, br)
+			break
+		}
+		key, value, _ := strings.Cut(strings.TrimPrefix(line, "#"), ":")
+		switch strings.ToUpper(strings.TrimSpace(key)) {
+		case "VERSION":
+		case "MP3", "AUDIO":
+			if audioWritten {
+				break
+			}
+			audioWritten = true
+			value, ending := strings.TrimRight(value, "\r\n"), line[len(strings.TrimRight(line, "\r\n")):]
+			if d.MP3 {
+				_, _ = bw.WriteString("#MP3:" + value + ending)
+			}
+			if d.Audio {
+				_, _ = bw.WriteString("#AUDIO:" + value + ending)
+			}
+		default:
+			_, _ = bw.WriteString(line)
+		}
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// relativeNotes converts absolute notes into notes in relative mode.
+// This is the inverse of absoluteNotes.
+// The notes are modified in place.
+func relativeNotes(notes ultrastar.Notes) ultrastar.Notes {
+	var offset ultrastar.Beat
+	for i := range notes {
+		start := notes[i].Start
+		notes[i].Start -= offset
+		if notes[i].Type == ultrastar.NoteTypeLineBreak {
+			offset = start
+		}
+	}
+	return notes
+}
@@ -0,0 +1,99 @@
+package song
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"codello.dev/ultrastar"
+)
+
+func TestParseDialect(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		version  string
+		relative bool
+		expected Dialect
+		ok       bool
+	}{
+		"default":           {"", false, Dialect{}, true},
+		"default relative":  {"", true, Dialect{MP3: true, Relative: true, custom: true}, true},
+		"1.1.0":             {"1.1.0", false, Dialect{Version: "1.1.0", MP3: true, Audio: true, custom: true}, true},
+		"2.0.0":             {"2.0.0", false, Dialect{Version: "2.0.0", Audio: true, custom: true}, true},
+		"2.0.0 relative":    {"2.0.0", true, Dialect{}, false},
+		"unknown version":   {"3.0.0", false, Dialect{}, false},
+		"1.0.0 is not zero": {"1.0.0", false, Dialect{MP3: true, custom: true}, true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			d, err := ParseDialect(c.version, c.relative)
+			if c.ok && err != nil {
+				t.Fatalf("ParseDialect(%q, %t) returned an unexpected error: %s", c.version, c.relative, err)
+			} else if !c.ok && err == nil {
+				t.Fatalf("ParseDialect(%q, %t) did not return an error, expected an error", c.version, c.relative)
+			}
+			if d != c.expected {
+				t.Errorf("ParseDialect(%q, %t) = %+v, expected %+v", c.version, c.relative, d, c.expected)
+			}
+		})
+	}
+}
+
+func TestDialect_rewriteHeaders(t *testing.T) {
+	t.Parallel()
+
+	input := "#TITLE:Song\r\n#VERSION:1.0.0\r\n#MP3:song.mp3\r\n#AUDIO:song.mp3\r\n#BPM:120\r\n: 0 1 0 #1\r\nE\r\n"
+	cases := map[string]struct {
+		dialect  Dialect
+		expected string
+	}{
+		"1.0.0": {dialectVersions["1.0.0"], "#TITLE:Song\r\n#MP3:song.mp3\r\n#BPM:120\r\n: 0 1 0 #1\r\nE\r\n"},
+		"1.1.0": {dialectVersions["1.1.0"], "#VERSION:1.1.0\r\n#TITLE:Song\r\n#MP3:song.mp3\r\n#AUDIO:song.mp3\r\n#BPM:120\r\n: 0 1 0 #1\r\nE\r\n"},
+		"2.0.0": {dialectVersions["2.0.0"], "#VERSION:2.0.0\r\n#TITLE:Song\r\n#AUDIO:song.mp3\r\n#BPM:120\r\n: 0 1 0 #1\r\nE\r\n"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := &strings.Builder{}
+			if err := c.dialect.rewriteHeaders(b, strings.NewReader(input)); err != nil {
+				t.Fatalf("rewriteHeaders() returned an unexpected error: %s", err)
+			}
+			if b.String() != c.expected {
+				t.Errorf("rewriteHeaders() wrote %q, expected %q", b.String(), c.expected)
+			}
+		})
+	}
+	t.Run("LF", func(t *testing.T) {
+		b := &strings.Builder{}
+		expected := "#VERSION:2.0.0\n#TITLE:Song\n#AUDIO:song.mp3\nE\n"
+		if err := dialectVersions["2.0.0"].rewriteHeaders(b, strings.NewReader("#TITLE:Song\n#MP3:song.mp3\nE\n")); err != nil {
+			t.Fatalf("rewriteHeaders() returned an unexpected error: %s", err)
+		}
+		if b.String() != expected {
+			t.Errorf("rewriteHeaders() wrote %q, expected %q", b.String(), expected)
+		}
+	})
+}
+
+func Test_relativeNotes(t *testing.T) {
+	t.Parallel()
+
+	notes := ultrastar.Notes{
+		{Type: ultrastar.NoteTypeRegular, Start: 2, Duration: 2},
+		{Type: ultrastar.NoteTypeLineBreak, Start: 6},
+		{Type: ultrastar.NoteTypeRegular, Start: 8, Duration: 2},
+		{Type: ultrastar.NoteTypeLineBreak, Start: 12},
+		{Type: ultrastar.NoteTypeRegular, Start: 15, Duration: 1},
+	}
+	relative := relativeNotes(slices.Clone(notes))
+	starts := make([]ultrastar.Beat, len(relative))
+	for i, n := range relative {
+		starts[i] = n.Start
+	}
+	if expected := []ultrastar.Beat{2, 6, 2, 6, 3}; !slices.Equal(starts, expected) {
+		t.Errorf("relativeNotes() produced starts %v, expected %v", starts, expected)
+	}
+	if absolute := absoluteNotes(relative); !slices.Equal(absolute, notes) {
+		t.Errorf("absoluteNotes(relativeNotes(notes)) = %v, expected %v", absolute, notes)
+	}
+}
@@ -3,6 +3,8 @@ package song
 import (
 	"context"
 	"math"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,11 +25,13 @@ func NewFakeRepository() Repository {
 	return &fakeRepo{make(map[uuid.UUID]model.Song)}
 }
 
-// CreateSong stores the song and sets its UUID, CreatedAt, and UpdatedAt fields.
+// CreateSong stores the song and sets its UUID, CreatedAt, UpdatedAt, and Stats fields.
 func (r *fakeRepo) CreateSong(_ context.Context, song *model.Song) error {
+	song.Stats = CalculateStats(*song)
 	song.UUID = uuid.New()
 	song.CreatedAt = time.Now()
 	song.UpdatedAt = song.CreatedAt
+	song.ReviewState = model.ReviewStateDraft
 	r.songs[song.UUID] = *song
 	return nil
 }
@@ -41,32 +45,102 @@ func (r *fakeRepo) GetSong(_ context.Context, id uuid.UUID) (model.Song, error)
 	return song, nil
 }
 
-// FindSongs returns a list of songs limited by the specified pagination parameters.
-// This implementation does not support complex filter queries.
-func (r *fakeRepo) FindSongs(_ context.Context, limit int, offset int64) ([]model.Song, int64, error) {
+// GetLibrarySong looks up the song with the specified UUID if it is neither in the trash nor in an upload.
+func (r *fakeRepo) GetLibrarySong(_ context.Context, id uuid.UUID) (model.Song, error) {
+	song, ok := r.songs[id]
+	if !ok || song.Deleted() || song.InUpload {
+		return model.Song{}, core.ErrNotFound
+	}
+	return song, nil
+}
+
+// FindSongs returns a list of songs matching filter, limited by the specified pagination parameters.
+func (r *fakeRepo) FindSongs(_ context.Context, filter Filter, limit int, offset int64) ([]model.Song, int64, error) {
+	songs, total := r.findSongs(false, filter, limit, offset)
+	return songs, total, nil
+}
+
+// FindDeletedSongs returns a list of songs in the trash limited by the specified pagination parameters.
+// This implementation does not order songs by their deletion time.
+func (r *fakeRepo) FindDeletedSongs(_ context.Context, limit int, offset int64) ([]model.Song, int64, error) {
+	songs, total := r.findSongs(true, Filter{}, limit, offset)
+	return songs, total, nil
+}
+
+// findSongs returns the songs that are (or are not) in the trash and match filter, paginated by limit and offset.
+func (r *fakeRepo) findSongs(deleted bool, filter Filter, limit int, offset int64) ([]model.Song, int64) {
 	if limit < 0 {
 		limit = math.MaxInt
 	}
 	songs := make([]model.Song, 0)
 	idx := int64(0)
 	for _, song := range r.songs {
+		if song.Deleted() != deleted || !filter.Matches(song) {
+			continue
+		}
 		if idx < offset {
 			idx++
 			continue
 		}
-		if len(songs) >= limit {
-			break
+		idx++
+		if len(songs) < limit {
+			songs = append(songs, song)
 		}
-		songs = append(songs, song)
 	}
-	return songs, int64(len(r.songs)), nil
+	return songs, idx
 }
 
-// DeleteSong deletes the song with the specified UUID (if it exists).
+// DeleteSong moves the song with the specified UUID to the trash (if it exists).
 func (r *fakeRepo) DeleteSong(_ context.Context, id uuid.UUID) (bool, error) {
-	_, ok := r.songs[id]
+	song, ok := r.songs[id]
+	if !ok || song.Deleted() {
+		return false, nil
+	}
+	song.DeletedAt = time.Now()
+	r.songs[id] = song
+	return true, nil
+}
+
+// DeleteSongIfUnmodified moves the song to the trash if its UpdatedAt timestamp equals version.
+func (r *fakeRepo) DeleteSongIfUnmodified(ctx context.Context, id uuid.UUID, version time.Time) error {
+	if current, ok := r.songs[id]; !ok || current.Deleted() || !current.UpdatedAt.Equal(version) {
+		return core.ErrPreconditionFailed
+	}
+	_, err := r.DeleteSong(ctx, id)
+	return err
+}
+
+// RestoreSong removes the song with the specified UUID from the trash (if it is in the trash).
+func (r *fakeRepo) RestoreSong(_ context.Context, id uuid.UUID) (bool, error) {
+	song, ok := r.songs[id]
+	if !ok || !song.Deleted() {
+		return false, nil
+	}
+	song.DeletedAt = time.Time{}
+	r.songs[id] = song
+	return true, nil
+}
+
+// PurgeSongs deletes all songs that have been in the trash for longer than retention.
+func (r *fakeRepo) PurgeSongs(_ context.Context, retention time.Duration) (int64, error) {
+	before := time.Now().Add(-retention)
+	n := int64(0)
+	for id, song := range r.songs {
+		if song.Deleted() && song.DeletedAt.Before(before) {
+			delete(r.songs, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+// PurgeSong deletes the song with the specified UUID.
+func (r *fakeRepo) PurgeSong(_ context.Context, id uuid.UUID) (bool, error) {
+	if _, ok := r.songs[id]; !ok {
+		return false, nil
+	}
 	delete(r.songs, id)
-	return ok, nil
+	return true, nil
 }
 
 // UpdateSong updates the data of song.
@@ -75,7 +149,86 @@ func (r *fakeRepo) UpdateSong(_ context.Context, song *model.Song) error {
 	if !ok {
 		return core.ErrNotFound
 	}
+	song.Stats = CalculateStats(*song)
 	song.UpdatedAt = time.Now()
 	r.songs[song.UUID] = *song
 	return nil
 }
+
+// UpdateSongIfUnmodified updates the song if its UpdatedAt timestamp equals version.
+func (r *fakeRepo) UpdateSongIfUnmodified(ctx context.Context, song *model.Song, version time.Time) error {
+	if current, ok := r.songs[song.UUID]; !ok || current.Deleted() || !current.UpdatedAt.Equal(version) {
+		return core.ErrPreconditionFailed
+	}
+	return r.UpdateSong(ctx, song)
+}
+
+// MergeSongs updates song and removes the duplicates.
+// The tags of the duplicates are added to song.
+// The fake repository does not store other references to songs, so there is nothing else to move.
+func (r *fakeRepo) MergeSongs(ctx context.Context, song *model.Song, duplicates []uuid.UUID, purge bool, version time.Time) error {
+	if !version.IsZero() {
+		if current, ok := r.songs[song.UUID]; !ok || current.Deleted() || !current.UpdatedAt.Equal(version) {
+			return core.ErrPreconditionFailed
+		}
+	}
+	if _, ok := r.songs[song.UUID]; !ok {
+		return core.ErrNotFound
+	}
+	for _, id := range duplicates {
+		if d, ok := r.songs[id]; ok && id != song.UUID {
+			for _, tag := range d.Tags {
+				if !slices.ContainsFunc(song.Tags, func(t string) bool { return strings.EqualFold(t, tag) }) {
+					song.Tags = append(song.Tags, tag)
+				}
+			}
+		}
+	}
+	if err := r.UpdateSong(ctx, song); err != nil {
+		return err
+	}
+	for _, id := range duplicates {
+		if purge {
+			_, _ = r.PurgeSong(ctx, id)
+		} else {
+			_, _ = r.DeleteSong(ctx, id)
+		}
+	}
+	return nil
+}
+
+// UpdateSongsIfUnmodified updates all songs in the repository if none of them has been modified.
+func (r *fakeRepo) UpdateSongsIfUnmodified(ctx context.Context, songs []model.Song, versions map[uuid.UUID]time.Time) error {
+	for _, song := range songs {
+		if current, ok := r.songs[song.UUID]; !ok || current.Deleted() || !current.UpdatedAt.Equal(versions[song.UUID]) {
+			return core.ErrPreconditionFailed
+		}
+	}
+	return r.UpdateSongs(ctx, songs)
+}
+
+// DeleteSongsIfUnmodified moves all songs to the trash if none of them has been modified.
+func (r *fakeRepo) DeleteSongsIfUnmodified(ctx context.Context, versions map[uuid.UUID]time.Time) error {
+	for id, version := range versions {
+		if current, ok := r.songs[id]; !ok || current.Deleted() || !current.UpdatedAt.Equal(version) {
+			return core.ErrPreconditionFailed
+		}
+	}
+	for id := range versions {
+		_, _ = r.DeleteSong(ctx, id)
+	}
+	return nil
+}
+
+// UpdateSongs updates all songs in the repository if all of them exist.
+func (r *fakeRepo) UpdateSongs(ctx context.Context, songs []model.Song) error {
+	for _, song := range songs {
+		if _, ok := r.songs[song.UUID]; !ok {
+			return core.ErrNotFound
+		}
+	}
+	for i := range songs {
+		_ = r.UpdateSong(ctx, &songs[i])
+	}
+	return nil
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"codello.dev/ultrastar"
 	"github.com/google/uuid"
 
 	"github.com/Karaoke-Manager/karman/core"
@@ -84,7 +86,7 @@ func Test_fakeRepo_FindSongs(t *testing.T) {
 
 	for name, c := range cases {
 		t.Run(name, func(t *testing.T) {
-			songs, total, err := repo.FindSongs(context.TODO(), c.Limit, c.Offset)
+			songs, total, err := repo.FindSongs(context.TODO(), Filter{}, c.Limit, c.Offset)
 			if err != nil {
 				t.Errorf("FindSongs(ctx, %d, %d) returned an unexpected error: %s", c.Limit, c.Offset, err)
 				return
@@ -99,6 +101,36 @@ func Test_fakeRepo_FindSongs(t *testing.T) {
 	}
 }
 
+func Test_fakeRepo_FindSongs_Filter(t *testing.T) {
+	t.Parallel()
+
+	repo := NewFakeRepository()
+	low := &model.Song{Song: ultrastar.Song{BPM: 60, NotesP1: ultrastar.Notes{
+		{Type: ultrastar.NoteTypeRegular, Start: 0, Duration: 1, Pitch: -10},
+		{Type: ultrastar.NoteTypeRegular, Start: 2, Duration: 1, Pitch: -5},
+	}}}
+	high := &model.Song{Song: ultrastar.Song{BPM: 60, NotesP1: ultrastar.Notes{
+		{Type: ultrastar.NoteTypeRegular, Start: 0, Duration: 1, Pitch: 5},
+		{Type: ultrastar.NoteTypeRegular, Start: 2, Duration: 1, Pitch: 10},
+	}}}
+	_ = repo.CreateSong(context.TODO(), low)
+	_ = repo.CreateSong(context.TODO(), high)
+	_ = repo.CreateSong(context.TODO(), &model.Song{})
+
+	songs, total, err := repo.FindSongs(context.TODO(), Filter{Range: &PitchRange{-12, 0}}, -1, 0)
+	if err != nil {
+		t.Fatalf("FindSongs(ctx, filter, -1, 0) returned an unexpected error: %s", err)
+	}
+	if total != 1 || len(songs) != 1 || songs[0].UUID != low.UUID {
+		t.Errorf("FindSongs(ctx, filter, -1, 0) returned %d songs, expected only %q", total, low.UUID)
+	}
+
+	_, total, _ = repo.FindSongs(context.TODO(), Filter{MinDifficulty: model.DifficultyEasiest}, -1, 0)
+	if total != 2 {
+		t.Errorf("FindSongs(ctx, filter, -1, 0) returned total = %d, expected %d", total, 2)
+	}
+}
+
 func Test_fakeRepo_DeleteSong(t *testing.T) {
 	t.Parallel()
 
@@ -124,6 +156,94 @@ func Test_fakeRepo_DeleteSong(t *testing.T) {
 	}
 }
 
+func Test_fakeRepo_RestoreSong(t *testing.T) {
+	t.Parallel()
+
+	repo := NewFakeRepository()
+	expected := model.Song{}
+	_ = repo.CreateSong(context.TODO(), &expected)
+	_, _ = repo.DeleteSong(context.TODO(), expected.UUID)
+
+	ok, err := repo.RestoreSong(context.TODO(), expected.UUID)
+	if err != nil {
+		t.Errorf("RestoreSong(ctx, %q) returned an unexpected error: %s", expected.UUID, err)
+	}
+	if !ok {
+		t.Errorf("RestoreSong(ctx, %q) returned ok = %t, expected %t", expected.UUID, ok, true)
+	}
+	_, total, _ := repo.FindSongs(context.TODO(), Filter{}, -1, 0)
+	if total != 1 {
+		t.Errorf("FindSongs(ctx, -1, 0) after RestoreSong(ctx, %q) returned total = %d, expected %d", expected.UUID, total, 1)
+	}
+
+	ok, err = repo.RestoreSong(context.TODO(), expected.UUID)
+	if err != nil {
+		t.Errorf("RestoreSong(ctx, %q) [2nd time] returned an unexpected error: %s", expected.UUID, err)
+	}
+	if ok {
+		t.Errorf("RestoreSong(ctx, %q) [2nd time] returned ok = %t, expected %t", expected.UUID, ok, false)
+	}
+}
+
+func Test_fakeRepo_PurgeSongs(t *testing.T) {
+	t.Parallel()
+
+	repo := NewFakeRepository()
+	for i := 0; i < 3; i++ {
+		song := &model.Song{}
+		_ = repo.CreateSong(context.TODO(), song)
+		if i > 0 {
+			_, _ = repo.DeleteSong(context.TODO(), song.UUID)
+		}
+	}
+
+	n, err := repo.PurgeSongs(context.TODO(), time.Hour)
+	if err != nil {
+		t.Errorf("PurgeSongs(ctx, %s) returned an unexpected error: %s", time.Hour, err)
+	}
+	if n != 0 {
+		t.Errorf("PurgeSongs(ctx, %s) = %d, _, expected %d", time.Hour, n, 0)
+	}
+
+	n, err = repo.PurgeSongs(context.TODO(), -time.Hour)
+	if err != nil {
+		t.Errorf("PurgeSongs(ctx, %s) returned an unexpected error: %s", -time.Hour, err)
+	}
+	if n != 2 {
+		t.Errorf("PurgeSongs(ctx, %s) = %d, _, expected %d", -time.Hour, n, 2)
+	}
+	_, total, _ := repo.FindSongs(context.TODO(), Filter{}, -1, 0)
+	if total != 1 {
+		t.Errorf("FindSongs(ctx, -1, 0) after PurgeSongs() returned total = %d, expected %d", total, 1)
+	}
+}
+
+func Test_fakeRepo_PurgeSong(t *testing.T) {
+	t.Parallel()
+
+	repo := NewFakeRepository()
+	song := &model.Song{}
+	_ = repo.CreateSong(context.TODO(), song)
+
+	ok, err := repo.PurgeSong(context.TODO(), song.UUID)
+	if err != nil {
+		t.Errorf("PurgeSong(ctx, %q) returned an unexpected error: %s", song.UUID, err)
+	}
+	if !ok {
+		t.Errorf("PurgeSong(ctx, %q) = false, _, expected true", song.UUID)
+	}
+	if _, err = repo.GetSong(context.TODO(), song.UUID); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("GetSong(ctx, %q) after PurgeSong() returned %v, expected ErrNotFound", song.UUID, err)
+	}
+	ok, err = repo.PurgeSong(context.TODO(), song.UUID)
+	if err != nil {
+		t.Errorf("PurgeSong(ctx, %q) returned an unexpected error: %s", song.UUID, err)
+	}
+	if ok {
+		t.Errorf("PurgeSong(ctx, %q) = true, _, expected false", song.UUID)
+	}
+}
+
 func Test_fakeRepo_UpdateSong(t *testing.T) {
 	t.Parallel()
 
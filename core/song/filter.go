@@ -0,0 +1,89 @@
+package song
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"codello.dev/ultrastar"
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// A Filter restricts the songs returned by Repository.FindSongs.
+// The zero value matches all songs.
+type Filter struct {
+	// Range matches songs with at least one track whose vocal range lies within Range.
+	// If Range is nil, songs are not filtered by their vocal range.
+	Range *PitchRange
+
+	// MinDifficulty and MaxDifficulty match songs with a difficulty in the specified bounds.
+	// A zero value disables the respective bound.
+	MinDifficulty model.Difficulty
+	MaxDifficulty model.Difficulty
+
+	// Tags matches songs that have all the specified tags.
+	// Tag names are compared ignoring case.
+	Tags []string
+
+	// VariantGroup matches songs in the specified variant group.
+	// If VariantGroup is uuid.Nil, songs are not filtered by their variant group.
+	VariantGroup uuid.UUID
+}
+
+// PitchRange is an inclusive range of pitches.
+type PitchRange struct {
+	Lowest  ultrastar.Pitch
+	Highest ultrastar.Pitch
+}
+
+// Fits reports whether the vocal range of stats lies within r.
+// Tracks without pitched notes never fit.
+func (r PitchRange) Fits(stats model.TrackStats) bool {
+	return stats.Pitched > 0 && stats.LowestPitch >= r.Lowest && stats.HighestPitch <= r.Highest
+}
+
+// Matches reports whether song matches f.
+// The stats of song must have been calculated.
+func (f Filter) Matches(song model.Song) bool {
+	if f.Range != nil && !f.Range.Fits(song.Stats.P1) && (song.Stats.P2 == nil || !f.Range.Fits(*song.Stats.P2)) {
+		return false
+	}
+	if f.MinDifficulty != 0 && song.Stats.Difficulty < f.MinDifficulty {
+		return false
+	}
+	if f.MaxDifficulty != 0 && song.Stats.Difficulty > f.MaxDifficulty {
+		return false
+	}
+	if f.VariantGroup != uuid.Nil && song.VariantGroup != f.VariantGroup {
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !slices.ContainsFunc(song.Tags, func(t string) bool { return strings.EqualFold(t, strings.TrimSpace(tag)) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParsePitchRange parses a pitch range of the form "low..high", such as "C3..G4".
+// Both pitches are parsed using ParsePitch.
+func ParsePitchRange(s string) (PitchRange, error) {
+	low, high, ok := strings.Cut(s, "..")
+	if !ok {
+		return PitchRange{}, fmt.Errorf("invalid pitch range %q", s)
+	}
+	var r PitchRange
+	var err error
+	if r.Lowest, err = ParsePitch(low); err != nil {
+		return r, err
+	}
+	if r.Highest, err = ParsePitch(high); err != nil {
+		return r, err
+	}
+	if r.Lowest > r.Highest {
+		return r, fmt.Errorf("invalid pitch range %q: %s is higher than %s", s, low, high)
+	}
+	return r, nil
+}
@@ -2,6 +2,7 @@ package song
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -13,35 +14,125 @@ type Repository interface {
 	// CreateSong creates a new song with the specified data.
 	// An existing song.UUID must be ignored.
 	// This method must set song.UUID, song.CreatedAt, and song.UpdatedAt appropriately.
+	// Artists are linked by name or alias, song.Artists and song.FeaturedArtists are set to the canonical artist names.
+	// Tags are linked by name, unknown tags are created and song.Tags is set to the canonical tag names.
 	CreateSong(ctx context.Context, song *model.Song) error
 
 	// GetSong fetches the song with the specified UUID.
+	// Songs in the trash are returned as well, their DeletedAt field will be set.
 	// If no such song exists, core.ErrNotFound will be returned.
 	GetSong(ctx context.Context, id uuid.UUID) (model.Song, error)
 
-	// FindSongs returns all songs matching the specified query.
+	// GetLibrarySong fetches the song with the specified UUID if it is part of the library.
+	// If no such song exists or the song is in the trash or in an upload, core.ErrNotFound will be returned.
+	GetLibrarySong(ctx context.Context, id uuid.UUID) (model.Song, error)
+
+	// FindSongs returns all songs matching the specified filter.
+	// Songs in the trash are not included.
 	// Results are paginated with limit and offset.
 	// The second return value contains the total (unpaginated) number of songs.
 	//
 	// If no songs match, no error will be returned.
-	FindSongs(ctx context.Context, limit int, offset int64) ([]model.Song, int64, error)
+	FindSongs(ctx context.Context, filter Filter, limit int, offset int64) ([]model.Song, int64, error)
 
 	// UpdateSong saves updates for the specified song.
 	// The song's UUID must already exist in the database, otherwise e core.ErrNotFound will be returned.
+	// Artists and tags are linked the same way as in CreateSong.
 	UpdateSong(ctx context.Context, song *model.Song) error
 
-	// DeleteSong deletes the song with the specified UUID.
-	// If no such song exists, the first return value will be false.
+	// UpdateSongIfUnmodified works like UpdateSong but only saves the song
+	// if its UpdatedAt timestamp in the repository still equals version.
+	// If the song has been modified or deleted since, no changes are made and core.ErrPreconditionFailed is returned.
+	UpdateSongIfUnmodified(ctx context.Context, song *model.Song, version time.Time) error
+
+	// UpdateSongs saves updates for all specified songs in a single transaction.
+	// If any of the songs does not exist, no song is updated and core.ErrNotFound will be returned.
+	// Artists and tags are linked the same way as in CreateSong.
+	UpdateSongs(ctx context.Context, songs []model.Song) error
+
+	// UpdateSongsIfUnmodified works like UpdateSongs but only saves the songs
+	// if the UpdatedAt timestamp of every song in the repository still equals its entry in versions.
+	// If any of the songs has been modified or deleted since, no song is updated and core.ErrPreconditionFailed is returned.
+	UpdateSongsIfUnmodified(ctx context.Context, songs []model.Song, versions map[uuid.UUID]time.Time) error
+
+	// FindDeletedSongs returns all songs that are currently in the trash.
+	// Results are paginated with limit and offset, the most recently deleted songs come first.
+	// The second return value contains the total (unpaginated) number of songs in the trash.
+	FindDeletedSongs(ctx context.Context, limit int, offset int64) ([]model.Song, int64, error)
+
+	// DeleteSong moves the song with the specified UUID to the trash.
+	// Songs in the trash can be restored until they are purged.
+	// If no such song exists or the song already is in the trash, the first return value will be false.
 	DeleteSong(ctx context.Context, id uuid.UUID) (bool, error)
+
+	// DeleteSongIfUnmodified works like DeleteSong but only moves the song to the trash
+	// if its UpdatedAt timestamp in the repository still equals version.
+	// If the song has been modified or deleted since, core.ErrPreconditionFailed is returned.
+	DeleteSongIfUnmodified(ctx context.Context, id uuid.UUID, version time.Time) error
+
+	// DeleteSongsIfUnmodified moves the songs with the UUIDs in versions to the trash in a single transaction
+	// if the UpdatedAt timestamp of every song in the repository still equals its entry in versions.
+	// If any of the songs has been modified or deleted since, no song is deleted and core.ErrPreconditionFailed is returned.
+	DeleteSongsIfUnmodified(ctx context.Context, versions map[uuid.UUID]time.Time) error
+
+	// RestoreSong removes the song with the specified UUID from the trash.
+	// If no such song exists in the trash, the first return value will be false.
+	RestoreSong(ctx context.Context, id uuid.UUID) (bool, error)
+
+	// PurgeSongs permanently deletes all songs that have been in the trash for longer than retention.
+	// Media files of purged songs are not deleted by this method.
+	// The first return value contains the number of songs that were purged.
+	PurgeSongs(ctx context.Context, retention time.Duration) (int64, error)
+
+	// PurgeSong permanently deletes the song with the specified UUID, regardless of whether it is in the trash.
+	// Media files of the song are not deleted by this method.
+	// If no such song exists, the first return value will be false.
+	PurgeSong(ctx context.Context, id uuid.UUID) (bool, error)
+
+	// MergeSongs saves song and removes the songs with the specified UUIDs in a single transaction.
+	// Entries in playlists, play statistics, highscores, song requests of party sessions, variant links,
+	// review comments and review states of the removed songs are moved to song.
+	// Variant links and review states are only moved if song has none.
+	// The tags of the removed songs are added to song.
+	// If purge is true, the removed songs are deleted permanently, otherwise they are moved to the trash.
+	// If version is not the zero time, song is only saved if its UpdatedAt timestamp in the repository still equals version.
+	// If the song has been modified or deleted since, no changes are made and core.ErrPreconditionFailed is returned.
+	MergeSongs(ctx context.Context, song *model.Song, duplicates []uuid.UUID, purge bool, version time.Time) error
 }
 
 // A Service implements modification logic for Songs.
 type Service interface {
-	// ParseArtists sets song.Artists based on other song fields.
+	// ParseArtists sets song.Artists and song.FeaturedArtists based on other song fields.
 	// This method should be used to process songs parsed from a TXT source where multiple artists are not supported.
 	ParseArtists(ctx context.Context, song *model.Song)
 
 	// Prepare prepares song for TXT serialization.
-	// This includes merging multiple artists and setting appropriate file names.
+	// This includes merging multiple artists into a canonical artist string
+	// and setting folder and file names that are valid on all common operating systems.
 	Prepare(ctx context.Context, song *model.Song)
+
+	// PrepareWithNaming works like Prepare but uses naming to generate the folder and file names.
+	// Nil templates in naming fall back to the naming of the service.
+	PrepareWithNaming(ctx context.Context, song *model.Song, naming Naming)
+
+	// EditNotes applies edits to the notes of song in order.
+	// Edits are applied atomically: If any edit cannot be applied or the resulting notes are invalid,
+	// a *NotesError is returned and song is not modified.
+	// This method does not persist the changes.
+	EditNotes(ctx context.Context, song *model.Song, edits []NoteEdit) error
+
+	// Transform applies the timing transforms ts to song in order.
+	// Transforms are applied atomically: If any transform cannot be applied,
+	// a *TransformError is returned and song is not modified.
+	// This method does not persist the changes.
+	Transform(ctx context.Context, song *model.Song, ts []Transform) error
+
+	// SuggestMedley proposes a medley and a preview start for song based on repeated lyrics.
+	// If no chorus can be found, the second return value is false.
+	SuggestMedley(ctx context.Context, song model.Song) (MedleySuggestion, bool)
+
+	// DetectMedley sets the medley and preview start of song to the result of SuggestMedley
+	// unless the song already specifies them.
+	// This method does not persist the changes.
+	DetectMedley(ctx context.Context, song *model.Song)
 }
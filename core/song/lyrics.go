@@ -0,0 +1,119 @@
+package song
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// A Syllable is the text of a single note together with its timing.
+type Syllable struct {
+	Start time.Duration
+	End   time.Duration
+	Text  string
+}
+
+// A LyricLine is a single line of lyrics together with its timing.
+type LyricLine struct {
+	// Start and End are the start of the first and the end of the last syllable of the line.
+	Start time.Duration
+	End   time.Duration
+	// Text is the complete text of the line without leading or trailing whitespace.
+	Text      string
+	Syllables []Syllable
+}
+
+// Lyrics computes the timed lyrics of notes, which must belong to song.
+// Times are relative to the start of the audio and are calculated from the BPM and GAP of song.
+// If song has no valid BPM, all times are 0.
+// Lines without any text are omitted.
+func Lyrics(song model.Song, notes ultrastar.Notes) []LyricLine {
+	at := func(b ultrastar.Beat) time.Duration {
+		if !song.BPM.IsValid() {
+			return 0
+		}
+		return song.Gap + song.BPM.Duration(b)
+	}
+	lines := make([]LyricLine, 0)
+	for _, line := range SplitLines(notes) {
+		l := LyricLine{Syllables: make([]Syllable, 0, len(line.Notes))}
+		var b strings.Builder
+		for _, n := range line.Notes {
+			if !validNoteType(n.Type) {
+				continue
+			}
+			l.Syllables = append(l.Syllables, Syllable{Start: at(n.Start), End: at(n.Start + n.Duration), Text: n.Text})
+			b.WriteString(n.Text)
+		}
+		l.Text = strings.TrimSpace(b.String())
+		if l.Text == "" {
+			continue
+		}
+		l.Start = l.Syllables[0].Start
+		l.End = l.Syllables[len(l.Syllables)-1].End
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+// WriteLyrics writes lines as plain text to w, one line of lyrics per line of text.
+func WriteLyrics(w io.Writer, lines []LyricLine) error {
+	bw := bufio.NewWriter(w)
+	for _, l := range lines {
+		_, _ = bw.WriteString(l.Text)
+		_ = bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// WriteLRC writes lines in the LRC format to w.
+// The header of the file contains the metadata of song, which should have been prepared by the Service.
+// If enhanced is true, each syllable is prefixed with its own timestamp, as specified by the enhanced LRC format.
+func WriteLRC(w io.Writer, song model.Song, lines []LyricLine, enhanced bool) error {
+	bw := bufio.NewWriter(w)
+	header := [][2]string{
+		{"ar", song.Artist},
+		{"ti", song.Title},
+		{"al", song.Edition},
+		{"by", song.Creator},
+	}
+	for _, tag := range header {
+		if value := strings.TrimSpace(tag[1]); value != "" {
+			_, _ = fmt.Fprintf(bw, "[%s:%s]\n", tag[0], value)
+		}
+	}
+	if song.Stats.Length > 0 {
+		_, _ = fmt.Fprintf(bw, "[length:%02d:%02d]\n", int(song.Stats.Length.Minutes()), int(song.Stats.Length.Seconds())%60)
+	}
+	for _, l := range lines {
+		_, _ = fmt.Fprintf(bw, "[%s]", lrcTimestamp(l.Start))
+		if !enhanced {
+			_, _ = bw.WriteString(l.Text)
+		} else {
+			for i, s := range l.Syllables {
+				text := s.Text
+				if i == 0 {
+					text = strings.TrimLeft(text, " ")
+				}
+				_, _ = fmt.Fprintf(bw, "<%s>%s", lrcTimestamp(s.Start), text)
+			}
+			_, _ = fmt.Fprintf(bw, "<%s>", lrcTimestamp(l.End))
+		}
+		_ = bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+// lrcTimestamp formats d as an LRC timestamp of the form mm:ss.xx.
+// Negative durations are formatted as 00:00.00.
+func lrcTimestamp(d time.Duration) string {
+	d = max(d, 0)
+	cs := d.Milliseconds() / 10
+	return fmt.Sprintf("%02d:%02d.%02d", cs/6000, cs/100%60, cs%100)
+}
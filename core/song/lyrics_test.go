@@ -0,0 +1,91 @@
+package song
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// lyricsSong returns a song with two lines of lyrics at 60 BPM, so that each beat is a second.
+func lyricsSong() model.Song {
+	song := model.Song{Song: ultrastar.Song{Title: "Song", Creator: "Me", BPM: 60, Gap: 500 * time.Millisecond, NotesP1: ultrastar.Notes{
+		{Type: ultrastar.NoteTypeRegular, Start: 0, Duration: 1, Text: "Hel"},
+		{Type: ultrastar.NoteTypeRegular, Start: 1, Duration: 1, Text: "lo "},
+		{Type: ultrastar.NoteTypeGolden, Start: 2, Duration: 2, Text: "world"},
+		{Type: ultrastar.NoteTypeLineBreak, Start: 5},
+		{Type: ultrastar.NoteTypeFreestyle, Start: 6, Duration: 1, Text: " ~ "},
+		{Type: ultrastar.NoteTypeLineBreak, Start: 8},
+		{Type: ultrastar.NoteTypeRap, Start: 70, Duration: 2, Text: "Bye"},
+	}}}
+	song.Artist = "Artist"
+	return song
+}
+
+func TestLyrics(t *testing.T) {
+	t.Parallel()
+
+	lines := Lyrics(lyricsSong(), lyricsSong().NotesP1)
+	if len(lines) != 3 {
+		t.Fatalf("Lyrics() returned %d lines, expected %d", len(lines), 3)
+	}
+	if lines[0].Text != "Hello world" || lines[2].Text != "Bye" {
+		t.Errorf("Lyrics() returned lines %q and %q, expected %q and %q", lines[0].Text, lines[2].Text, "Hello world", "Bye")
+	}
+	if lines[0].Start != 500*time.Millisecond || lines[0].End != 4500*time.Millisecond {
+		t.Errorf("Lyrics() returned line from %s to %s, expected %s to %s", lines[0].Start, lines[0].End, 500*time.Millisecond, 4500*time.Millisecond)
+	}
+	if len(lines[0].Syllables) != 3 || lines[0].Syllables[1].Start != 1500*time.Millisecond {
+		t.Errorf("Lyrics() returned syllables %v, expected 3 syllables with the second starting at %s", lines[0].Syllables, 1500*time.Millisecond)
+	}
+
+	lines = Lyrics(model.Song{}, lyricsSong().NotesP1)
+	if lines[2].Start != 0 {
+		t.Errorf("Lyrics() without BPM returned start %s, expected 0", lines[2].Start)
+	}
+}
+
+func TestWriteLyrics(t *testing.T) {
+	t.Parallel()
+
+	b := &strings.Builder{}
+	if err := WriteLyrics(b, Lyrics(lyricsSong(), lyricsSong().NotesP1)); err != nil {
+		t.Fatalf("WriteLyrics() returned an unexpected error: %s", err)
+	}
+	if expected := "Hello world\n~\nBye\n"; b.String() != expected {
+		t.Errorf("WriteLyrics() wrote %q, expected %q", b.String(), expected)
+	}
+}
+
+func TestWriteLRC(t *testing.T) {
+	t.Parallel()
+
+	song := lyricsSong()
+	song.Stats = CalculateStats(song)
+	lines := Lyrics(song, song.NotesP1)
+	cases := map[string]struct {
+		enhanced bool
+		expected string
+	}{
+		"line": {false, "[ar:Artist]\n[ti:Song]\n[by:Me]\n[length:01:12]\n" +
+			"[00:00.50]Hello world\n[00:06.50]~\n[01:10.50]Bye\n"},
+		"syllable": {true, "[ar:Artist]\n[ti:Song]\n[by:Me]\n[length:01:12]\n" +
+			"[00:00.50]<00:00.50>Hel<00:01.50>lo <00:02.50>world<00:04.50>\n" +
+			"[00:06.50]<00:06.50>~ <00:07.50>\n" +
+			"[01:10.50]<01:10.50>Bye<01:12.50>\n"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := &strings.Builder{}
+			if err := WriteLRC(b, song, lines, c.enhanced); err != nil {
+				t.Fatalf("WriteLRC() returned an unexpected error: %s", err)
+			}
+			if b.String() != c.expected {
+				t.Errorf("WriteLRC() wrote %q, expected %q", b.String(), c.expected)
+			}
+		})
+	}
+}
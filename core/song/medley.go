@@ -0,0 +1,106 @@
+package song
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// minChorusLines is the minimum number of consecutive repeated lines that are considered a chorus.
+const minChorusLines = 2
+
+// MedleySuggestion is a proposal for the medley and preview of a song.
+type MedleySuggestion struct {
+	// MedleyStartBeat and MedleyEndBeat enclose the first occurrence of the chorus.
+	MedleyStartBeat ultrastar.Beat
+	MedleyEndBeat   ultrastar.Beat
+	// PreviewStart is the time of MedleyStartBeat.
+	// If the song has no valid BPM, PreviewStart is 0.
+	PreviewStart time.Duration
+}
+
+// SuggestMedley analyses the lyrics of song and proposes a medley and a preview start.
+// The chorus of the song is assumed to be the longest sequence of lyric lines in NotesP1 that is repeated later in the song.
+// If no such sequence exists, the second return value is false.
+func (s *service) SuggestMedley(_ context.Context, song model.Song) (MedleySuggestion, bool) {
+	lines := SplitLines(song.NotesP1)
+	first, last, ok := findChorus(lines)
+	if !ok {
+		return MedleySuggestion{}, false
+	}
+	end := lines[last].Notes[len(lines[last].Notes)-1]
+	suggestion := MedleySuggestion{
+		MedleyStartBeat: lines[first].Notes[0].Start,
+		MedleyEndBeat:   end.Start + end.Duration,
+	}
+	if song.BPM.IsValid() {
+		suggestion.PreviewStart = song.Gap + song.BPM.Duration(suggestion.MedleyStartBeat)
+	}
+	return suggestion, true
+}
+
+// DetectMedley sets the medley and preview start of song to the result of SuggestMedley.
+// Values that are already present in song are not changed.
+// Songs that disable the automatic medley keep their medley settings.
+func (s *service) DetectMedley(ctx context.Context, song *model.Song) {
+	hasMedley := song.NoAutoMedley || (song.MedleyStartBeat != 0 && song.MedleyEndBeat != 0)
+	if hasMedley && song.PreviewStart != 0 {
+		return
+	}
+	suggestion, ok := s.SuggestMedley(ctx, *song)
+	if !ok {
+		return
+	}
+	if !hasMedley {
+		song.MedleyStartBeat = suggestion.MedleyStartBeat
+		song.MedleyEndBeat = suggestion.MedleyEndBeat
+	}
+	if song.PreviewStart == 0 {
+		song.PreviewStart = suggestion.PreviewStart
+	}
+}
+
+// findChorus finds the longest sequence of lines that is repeated later in lines.
+// The sequence and its repetition must not overlap.
+// If multiple sequences have the same length, the earliest one is returned.
+// The first and last line of the sequence are returned.
+func findChorus(lines []Line) (first int, last int, ok bool) {
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		texts[i] = lineText(line)
+	}
+	length := 0
+	for i := range texts {
+		for j := i + 1; j < len(texts); j++ {
+			k := 0
+			for i+k < j && j+k < len(texts) && texts[i+k] != "" && texts[i+k] == texts[j+k] {
+				k++
+			}
+			if k > length {
+				first, length = i, k
+			}
+		}
+	}
+	if length < minChorusLines {
+		return 0, 0, false
+	}
+	return first, first + length - 1, true
+}
+
+// lineText returns the normalized lyrics of line.
+// Normalized lyrics are lowercase and contain only words of letters, digits and apostrophes separated by single spaces.
+func lineText(line Line) string {
+	var b strings.Builder
+	for _, n := range line.Notes {
+		b.WriteString(n.Text)
+	}
+	words := strings.FieldsFunc(strings.ToLower(b.String()), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\''
+	})
+	return strings.Join(words, " ")
+}
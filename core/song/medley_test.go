@@ -0,0 +1,84 @@
+package song
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/core/artist"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// lyricsNotes returns notes with one note per line, each line containing the respective text.
+// Each line starts 10 beats after the previous line and each note has a duration of 4 beats.
+func lyricsNotes(texts ...string) ultrastar.Notes {
+	notes := make(ultrastar.Notes, 0, 2*len(texts))
+	for i, text := range texts {
+		start := ultrastar.Beat(10 * i)
+		if i > 0 {
+			notes = append(notes, ultrastar.Note{Type: ultrastar.NoteTypeLineBreak, Start: start - 2})
+		}
+		notes = append(notes, ultrastar.Note{Type: ultrastar.NoteTypeRegular, Start: start, Duration: 4, Text: text})
+	}
+	return notes
+}
+
+func Test_service_SuggestMedley(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(artist.NewFakeRepository(), DefaultNaming)
+	cases := map[string]struct {
+		texts    []string
+		expected MedleySuggestion
+		ok       bool
+	}{
+		"chorus": {
+			[]string{"verse one", "Oh yeah, ", "we sing!", "verse two", "oh YEAH", "we sing", "end"},
+			MedleySuggestion{MedleyStartBeat: 10, MedleyEndBeat: 24, PreviewStart: time.Second + ultrastar.BPM(100).Duration(10)},
+			true,
+		},
+		"longest repetition": {
+			[]string{"a", "b", "x", "a", "b", "c", "y", "a", "b", "c"},
+			MedleySuggestion{MedleyStartBeat: 30, MedleyEndBeat: 54, PreviewStart: time.Second + ultrastar.BPM(100).Duration(30)},
+			true,
+		},
+		"single repeated line": {[]string{"a", "b", "a"}, MedleySuggestion{}, false},
+		"no repetition":        {[]string{"a", "b", "c", "d"}, MedleySuggestion{}, false},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			song := model.Song{Song: ultrastar.Song{BPM: 100, Gap: time.Second, NotesP1: lyricsNotes(c.texts...)}}
+			actual, ok := svc.SuggestMedley(context.TODO(), song)
+			if ok != c.ok {
+				t.Fatalf("SuggestMedley() returned ok = %t, expected %t", ok, c.ok)
+			}
+			if actual != c.expected {
+				t.Errorf("SuggestMedley() = %+v, expected %+v", actual, c.expected)
+			}
+		})
+	}
+}
+
+func Test_service_DetectMedley(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(artist.NewFakeRepository(), DefaultNaming)
+	notes := lyricsNotes("intro", "chorus", "line", "verse", "chorus", "line")
+
+	t.Run("missing", func(t *testing.T) {
+		song := model.Song{Song: ultrastar.Song{BPM: 100, NotesP1: notes}}
+		svc.DetectMedley(context.TODO(), &song)
+		if song.MedleyStartBeat != 10 || song.MedleyEndBeat != 24 || song.PreviewStart != ultrastar.BPM(100).Duration(10) {
+			t.Errorf("DetectMedley() set medley %d-%d with preview %s, expected medley 10-24 with a preview", song.MedleyStartBeat, song.MedleyEndBeat, song.PreviewStart)
+		}
+	})
+	t.Run("present", func(t *testing.T) {
+		song := model.Song{Song: ultrastar.Song{BPM: 100, NoAutoMedley: true, PreviewStart: 3 * time.Second, NotesP1: notes}}
+		svc.DetectMedley(context.TODO(), &song)
+		if song.MedleyStartBeat != 0 || song.MedleyEndBeat != 0 || song.PreviewStart != 3*time.Second {
+			t.Errorf("DetectMedley() modified the song, expected no changes")
+		}
+	})
+}
@@ -0,0 +1,74 @@
+package song
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/filename"
+)
+
+// maxNameLength is the maximum length in bytes of generated folder and file names.
+// The limit keeps the combined path of a song folder and a file below the 260 character limit of Windows,
+// leaving some room for the parent directory.
+const maxNameLength = 100
+
+// templateFields are the placeholders that can be used in naming templates.
+// The kind and ext placeholders are empty for folder names.
+var templateFields = []string{"artist", "title", "genre", "edition", "language", "year", "creator", "uuid", "kind", "ext"}
+
+// Naming configures how folder and file names are generated for songs.
+// A nil template falls back to the default of the Service.
+type Naming struct {
+	// Folder is the template for the folder that contains the files of a song.
+	// The UUID of the song is always appended to the folder name to keep it unique.
+	Folder *filename.Template
+	// File is the template for the TXT file and the media files of a song.
+	File *filename.Template
+}
+
+// DefaultNaming is the naming used by a Service if no other naming is configured.
+var DefaultNaming = Naming{
+	Folder: filename.MustParse("{artist} - {title}"),
+	File:   filename.MustParse("{artist} - {title}< [{kind}]>{ext}"),
+}
+
+// ParseTemplate parses text into a naming template.
+// In addition to the syntax of the template, this function validates that only known placeholders are used.
+func ParseTemplate(text string) (*filename.Template, error) {
+	t, err := filename.Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range t.Fields() {
+		if !slices.Contains(templateFields, field) {
+			return nil, fmt.Errorf("unknown placeholder {%s}", field)
+		}
+	}
+	return t, nil
+}
+
+// templateValues returns the values for template placeholders describing song.
+func templateValues(song *model.Song) map[string]string {
+	values := map[string]string{
+		"artist":   song.Artist,
+		"title":    song.Title,
+		"genre":    song.Genre,
+		"edition":  song.Edition,
+		"language": song.Language,
+		"creator":  song.Creator,
+		"uuid":     song.UUID.String(),
+	}
+	if song.Year != 0 {
+		values["year"] = strconv.Itoa(song.Year)
+	}
+	return values
+}
+
+// fileName renders a sanitized file name using t.
+func fileName(t *filename.Template, values map[string]string, kind string, ext string) string {
+	values["kind"] = kind
+	values["ext"] = ext
+	return filename.Truncate(filename.Sanitize(t.Execute(values)), maxNameLength)
+}
@@ -0,0 +1,282 @@
+package song
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// A Line is a single line of lyrics.
+// Lines are separated by line breaks in the UltraStar format.
+type Line struct {
+	// Start is the beat of the line break that precedes the line.
+	// The value is ignored for the first line of a player.
+	Start ultrastar.Beat
+	// Notes are the notes of the line, ordered by their start beat.
+	// Notes never contain line breaks.
+	Notes []ultrastar.Note
+}
+
+// SplitLines splits notes at line breaks.
+// Empty lines are omitted so that the indices of lines are stable for a given set of notes.
+// The returned lines do not share memory with notes.
+func SplitLines(notes ultrastar.Notes) []Line {
+	lines := make([]Line, 0)
+	current := Line{Notes: make([]ultrastar.Note, 0)}
+	for _, n := range notes {
+		if n.Type == ultrastar.NoteTypeLineBreak {
+			if len(current.Notes) > 0 {
+				lines = append(lines, current)
+			}
+			current = Line{Start: n.Start, Notes: make([]ultrastar.Note, 0)}
+			continue
+		}
+		current.Notes = append(current.Notes, n)
+	}
+	if len(current.Notes) > 0 {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// JoinLines is the inverse of SplitLines.
+// A line break is inserted before each line except the first.
+func JoinLines(lines []Line) ultrastar.Notes {
+	notes := make(ultrastar.Notes, 0)
+	for i, line := range lines {
+		if i > 0 {
+			notes = append(notes, ultrastar.Note{Type: ultrastar.NoteTypeLineBreak, Start: line.Start})
+		}
+		notes = append(notes, line.Notes...)
+	}
+	return notes
+}
+
+// A NoteOp identifies the kind of change of a NoteEdit.
+type NoteOp string
+
+const (
+	// InsertNote inserts NoteEdit.Value before the note at NoteEdit.Note.
+	// If NoteEdit.Note is the number of notes in the line, the note is appended to the line.
+	// If NoteEdit.Line is the number of lines, a new line is appended.
+	InsertNote NoteOp = "insertNote"
+	// DeleteNote deletes a note.
+	DeleteNote NoteOp = "deleteNote"
+	// MoveNote sets the start beat of a note to NoteEdit.Start.
+	MoveNote NoteOp = "moveNote"
+	// ResizeNote sets the duration of a note to NoteEdit.Duration.
+	ResizeNote NoteOp = "resizeNote"
+	// SetPitch sets the pitch of a note to NoteEdit.Pitch.
+	SetPitch NoteOp = "setPitch"
+	// SetText sets the syllable of a note to NoteEdit.Text.
+	SetText NoteOp = "setText"
+	// SetType sets the type of note to NoteEdit.Type.
+	SetType NoteOp = "setType"
+	// SplitLine inserts a line break before a note.
+	// The line break is placed at NoteEdit.Start.
+	// If NoteEdit.Start is 0, the line break is placed at the end of the preceding note.
+	SplitLine NoteOp = "splitLine"
+	// MergeLines removes the line break after a line, merging the line with the following line.
+	MergeLines NoteOp = "mergeLines"
+)
+
+// A NoteEdit is a single, granular change to the notes of a song.
+// Lines and notes are identified by their indices as returned by SplitLines.
+// Depending on Op, only some of the fields are used.
+type NoteEdit struct {
+	Op NoteOp
+	// Player is the player whose notes are edited (1 or 2).
+	// Editing the notes of player 2 turns a song into a duet.
+	Player int
+	Line   int
+	Note   int
+
+	Value    ultrastar.Note     // InsertNote
+	Start    ultrastar.Beat     // MoveNote, SplitLine
+	Duration ultrastar.Beat     // ResizeNote
+	Pitch    ultrastar.Pitch    // SetPitch
+	Text     string             // SetText
+	Type     ultrastar.NoteType // SetType
+}
+
+// NotesError indicates that a list of NoteEdit values could not be applied to a song.
+type NotesError struct {
+	// Edit is the index of the edit that could not be applied.
+	// If all edits could be applied but the resulting notes are invalid, Edit is -1.
+	Edit int
+	// Player, Line and Note identify the invalid note if Edit is -1.
+	// If a whole line is invalid, Note is -1.
+	Player int
+	Line   int
+	Note   int
+	// Message describes the error.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *NotesError) Error() string {
+	if e.Edit >= 0 {
+		return fmt.Sprintf("edit %d: %s", e.Edit, e.Message)
+	}
+	if e.Note < 0 {
+		return fmt.Sprintf("player %d, line %d: %s", e.Player, e.Line, e.Message)
+	}
+	return fmt.Sprintf("player %d, line %d, note %d: %s", e.Player, e.Line, e.Note, e.Message)
+}
+
+var (
+	errInvalidPlayer = errors.New("player must be 1 or 2")
+	errLineIndex     = errors.New("line index out of range")
+	errNoteIndex     = errors.New("note index out of range")
+	errLineBreak     = errors.New("line breaks cannot be edited as notes, use splitLine or mergeLines instead")
+)
+
+// EditNotes applies edits to the notes of song in order.
+// Each edit refers to the lines and notes as they are after the previous edits.
+// The resulting notes are validated.
+// If an edit cannot be applied or the result is invalid, a *NotesError is returned and song is not modified.
+func (s *service) EditNotes(_ context.Context, song *model.Song, edits []NoteEdit) error {
+	players := [2][]Line{SplitLines(song.NotesP1), SplitLines(song.NotesP2)}
+	for i, e := range edits {
+		if e.Player != 1 && e.Player != 2 {
+			return &NotesError{Edit: i, Message: errInvalidPlayer.Error()}
+		}
+		if err := applyNoteEdit(&players[e.Player-1], e); err != nil {
+			return &NotesError{Edit: i, Message: err.Error()}
+		}
+	}
+	for i, lines := range players {
+		if err := validateLines(lines); err != nil {
+			err.Player = i + 1
+			return err
+		}
+	}
+	song.NotesP1 = JoinLines(players[0])
+	if len(players[1]) > 0 {
+		song.NotesP2 = JoinLines(players[1])
+	} else {
+		song.NotesP2 = nil
+	}
+	return nil
+}
+
+// applyNoteEdit applies e to lines.
+// The validity of the resulting notes is not checked.
+func applyNoteEdit(lines *[]Line, e NoteEdit) error {
+	ls := *lines
+	switch e.Op {
+	case InsertNote:
+		if e.Value.Type == ultrastar.NoteTypeLineBreak {
+			return errLineBreak
+		}
+		if e.Line == len(ls) {
+			if e.Note != 0 {
+				return errNoteIndex
+			}
+			*lines = append(ls, Line{Start: e.Value.Start, Notes: []ultrastar.Note{e.Value}})
+			return nil
+		}
+		if e.Line < 0 || e.Line > len(ls) {
+			return errLineIndex
+		}
+		if e.Note < 0 || e.Note > len(ls[e.Line].Notes) {
+			return errNoteIndex
+		}
+		ls[e.Line].Notes = slices.Insert(ls[e.Line].Notes, e.Note, e.Value)
+	case DeleteNote, MoveNote, ResizeNote, SetPitch, SetText, SetType:
+		if e.Line < 0 || e.Line >= len(ls) {
+			return errLineIndex
+		}
+		if e.Note < 0 || e.Note >= len(ls[e.Line].Notes) {
+			return errNoteIndex
+		}
+		n := &ls[e.Line].Notes[e.Note]
+		switch e.Op {
+		case DeleteNote:
+			ls[e.Line].Notes = slices.Delete(ls[e.Line].Notes, e.Note, e.Note+1)
+		case MoveNote:
+			n.Start = e.Start
+		case ResizeNote:
+			n.Duration = e.Duration
+		case SetPitch:
+			n.Pitch = e.Pitch
+		case SetText:
+			n.Text = e.Text
+		case SetType:
+			if e.Type == ultrastar.NoteTypeLineBreak {
+				return errLineBreak
+			}
+			n.Type = e.Type
+		}
+	case SplitLine:
+		if e.Line < 0 || e.Line >= len(ls) {
+			return errLineIndex
+		}
+		notes := ls[e.Line].Notes
+		if e.Note <= 0 || e.Note >= len(notes) {
+			return errors.New("a line can only be split between two notes")
+		}
+		start := e.Start
+		if start == 0 {
+			start = notes[e.Note-1].Start + notes[e.Note-1].Duration
+		}
+		line := Line{Start: start, Notes: slices.Clone(notes[e.Note:])}
+		ls[e.Line].Notes = notes[:e.Note:e.Note]
+		*lines = slices.Insert(ls, e.Line+1, line)
+	case MergeLines:
+		if e.Line < 0 || e.Line+1 >= len(ls) {
+			return errLineIndex
+		}
+		ls[e.Line].Notes = append(ls[e.Line].Notes, ls[e.Line+1].Notes...)
+		*lines = slices.Delete(ls, e.Line+1, e.Line+2)
+	default:
+		return fmt.Errorf("unknown operation %q", e.Op)
+	}
+	return nil
+}
+
+// validateLines checks that lines form valid UltraStar notes.
+// Notes must have a positive duration and a known type and must not overlap.
+// Each line must contain at least one note,
+// and each line break must be placed between the previous line's last note and the line's first note.
+// The Player field of a returned error is not set.
+func validateLines(lines []Line) *NotesError {
+	var prev *ultrastar.Note
+	for i, line := range lines {
+		if len(line.Notes) == 0 {
+			return &NotesError{Edit: -1, Line: i, Note: -1, Message: "line contains no notes"}
+		}
+		if i > 0 && (line.Start < prev.Start || line.Start > line.Notes[0].Start) {
+			return &NotesError{Edit: -1, Line: i, Note: -1, Message: "line break must be placed between the last note of the previous line and the first note of the line"}
+		}
+		for j := range line.Notes {
+			n := &line.Notes[j]
+			switch {
+			case !validNoteType(n.Type):
+				return &NotesError{Edit: -1, Line: i, Note: j, Message: "invalid note type"}
+			case n.Duration <= 0:
+				return &NotesError{Edit: -1, Line: i, Note: j, Message: "duration must be positive"}
+			case prev != nil && n.Start < prev.Start+prev.Duration:
+				return &NotesError{Edit: -1, Line: i, Note: j, Message: "note overlaps the previous note"}
+			}
+			prev = n
+		}
+	}
+	return nil
+}
+
+// validNoteType reports whether t is a type of a singable note.
+func validNoteType(t ultrastar.NoteType) bool {
+	switch t {
+	case ultrastar.NoteTypeRegular, ultrastar.NoteTypeGolden, ultrastar.NoteTypeFreestyle,
+		ultrastar.NoteTypeRap, ultrastar.NoteTypeGoldenRap:
+		return true
+	default:
+		return false
+	}
+}
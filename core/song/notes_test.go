@@ -0,0 +1,141 @@
+package song
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/core/artist"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// testNotes returns two lines of notes: "Hel-lo" and "world".
+func testNotes() ultrastar.Notes {
+	return ultrastar.Notes{
+		{Type: ultrastar.NoteTypeRegular, Start: 0, Duration: 2, Pitch: 5, Text: "Hel"},
+		{Type: ultrastar.NoteTypeRegular, Start: 2, Duration: 2, Pitch: 7, Text: "lo "},
+		{Type: ultrastar.NoteTypeLineBreak, Start: 6},
+		{Type: ultrastar.NoteTypeGolden, Start: 8, Duration: 4, Pitch: 9, Text: "world"},
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	lines := SplitLines(testNotes())
+	if len(lines) != 2 {
+		t.Fatalf("SplitLines() returned %d lines, expected %d", len(lines), 2)
+	}
+	if len(lines[0].Notes) != 2 || len(lines[1].Notes) != 1 || lines[1].Start != 6 {
+		t.Errorf("SplitLines() returned %v, expected lines of 2 and 1 notes with a line break at beat 6", lines)
+	}
+	if joined := JoinLines(lines); !slices.Equal(joined, testNotes()) {
+		t.Errorf("JoinLines(SplitLines(notes)) = %v, expected %v", joined, testNotes())
+	}
+}
+
+func Test_service_EditNotes(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(artist.NewFakeRepository(), DefaultNaming)
+	cases := map[string]struct {
+		edits    []NoteEdit
+		expected ultrastar.Notes
+	}{
+		"insert note": {
+			[]NoteEdit{{Op: InsertNote, Player: 1, Line: 1, Note: 1, Value: ultrastar.Note{Type: ultrastar.NoteTypeRegular, Start: 12, Duration: 1, Text: "!"}}},
+			append(testNotes(), ultrastar.Note{Type: ultrastar.NoteTypeRegular, Start: 12, Duration: 1, Text: "!"}),
+		},
+		"edit note": {
+			[]NoteEdit{
+				{Op: MoveNote, Player: 1, Line: 1, Note: 0, Start: 7},
+				{Op: ResizeNote, Player: 1, Line: 1, Note: 0, Duration: 5},
+				{Op: SetPitch, Player: 1, Line: 1, Note: 0, Pitch: 3},
+				{Op: SetText, Player: 1, Line: 1, Note: 0, Text: "earth"},
+				{Op: SetType, Player: 1, Line: 1, Note: 0, Type: ultrastar.NoteTypeFreestyle},
+			},
+			append(testNotes()[:3], ultrastar.Note{Type: ultrastar.NoteTypeFreestyle, Start: 7, Duration: 5, Pitch: 3, Text: "earth"}),
+		},
+		"merge and split": {
+			[]NoteEdit{
+				{Op: MergeLines, Player: 1, Line: 0},
+				{Op: SplitLine, Player: 1, Line: 0, Note: 2, Start: 6},
+			},
+			testNotes(),
+		},
+		"split at note end": {
+			[]NoteEdit{{Op: SplitLine, Player: 1, Line: 0, Note: 1}},
+			slices.Insert(testNotes(), 1, ultrastar.Note{Type: ultrastar.NoteTypeLineBreak, Start: 2}),
+		},
+		"delete note": {
+			[]NoteEdit{{Op: DeleteNote, Player: 1, Line: 0, Note: 1}},
+			slices.Delete(testNotes(), 1, 2),
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			song := model.Song{Song: ultrastar.Song{NotesP1: testNotes()}}
+			if err := svc.EditNotes(context.TODO(), &song, c.edits); err != nil {
+				t.Fatalf("EditNotes() returned an unexpected error: %s", err)
+			}
+			if !slices.Equal(song.NotesP1, c.expected) {
+				t.Errorf("EditNotes() produced notes %v, expected %v", song.NotesP1, c.expected)
+			}
+		})
+	}
+
+	t.Run("duet", func(t *testing.T) {
+		song := model.Song{Song: ultrastar.Song{NotesP1: testNotes()}}
+		edit := NoteEdit{Op: InsertNote, Player: 2, Line: 0, Note: 0, Value: ultrastar.Note{Type: ultrastar.NoteTypeRegular, Start: 0, Duration: 1, Text: "Hi"}}
+		if err := svc.EditNotes(context.TODO(), &song, []NoteEdit{edit}); err != nil {
+			t.Fatalf("EditNotes() returned an unexpected error: %s", err)
+		}
+		if !song.IsDuet() || len(song.NotesP2) != 1 {
+			t.Errorf("EditNotes() produced NotesP2 = %v, expected a single note", song.NotesP2)
+		}
+	})
+}
+
+func Test_service_EditNotes_Invalid(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(artist.NewFakeRepository(), DefaultNaming)
+	cases := map[string]struct {
+		edits []NoteEdit
+		edit  int
+		line  int
+		note  int
+	}{
+		"invalid player":      {[]NoteEdit{{Op: DeleteNote, Player: 3}}, 0, 0, 0},
+		"unknown operation":   {[]NoteEdit{{Op: "foo", Player: 1}}, 0, 0, 0},
+		"line out of range":   {[]NoteEdit{{Op: DeleteNote, Player: 1, Line: 2}}, 0, 0, 0},
+		"note out of range":   {[]NoteEdit{{Op: SetPitch, Player: 1, Line: 1, Note: 1}}, 0, 0, 0},
+		"second edit":         {[]NoteEdit{{Op: MergeLines, Player: 1, Line: 0}, {Op: MergeLines, Player: 1, Line: 0}}, 1, 0, 0},
+		"insert line break":   {[]NoteEdit{{Op: InsertNote, Player: 1, Value: ultrastar.Note{Type: ultrastar.NoteTypeLineBreak}}}, 0, 0, 0},
+		"split at line start": {[]NoteEdit{{Op: SplitLine, Player: 1, Line: 0, Note: 0}}, 0, 0, 0},
+		"overlap":             {[]NoteEdit{{Op: ResizeNote, Player: 1, Line: 0, Note: 0, Duration: 3}}, -1, 0, 1},
+		"zero duration":       {[]NoteEdit{{Op: ResizeNote, Player: 1, Line: 1, Note: 0, Duration: 0}}, -1, 1, 0},
+		"empty line":          {[]NoteEdit{{Op: DeleteNote, Player: 1, Line: 1, Note: 0}}, -1, 1, -1},
+		"line break position": {[]NoteEdit{{Op: MoveNote, Player: 1, Line: 1, Note: 0, Start: 5}}, -1, 1, -1},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			song := model.Song{Song: ultrastar.Song{NotesP1: testNotes()}}
+			err := svc.EditNotes(context.TODO(), &song, c.edits)
+			var notesErr *NotesError
+			if !errors.As(err, &notesErr) {
+				t.Fatalf("EditNotes() returned %v, expected a *NotesError", err)
+			}
+			if notesErr.Edit != c.edit {
+				t.Errorf("EditNotes() returned an error for edit %d, expected %d", notesErr.Edit, c.edit)
+			}
+			if c.edit < 0 && (notesErr.Line != c.line || notesErr.Note != c.note) {
+				t.Errorf("EditNotes() returned an error for line %d, note %d, expected line %d, note %d", notesErr.Line, notesErr.Note, c.line, c.note)
+			}
+			if !slices.Equal(song.NotesP1, testNotes()) {
+				t.Errorf("EditNotes() modified the song, expected no changes")
+			}
+		})
+	}
+}
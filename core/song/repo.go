@@ -3,7 +3,9 @@ package song
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	"codello.dev/ultrastar"
@@ -13,6 +15,7 @@ import (
 	"github.com/jackc/pgxutil"
 	"github.com/lmittmann/tint"
 
+	"github.com/Karaoke-Manager/karman/core"
 	"github.com/Karaoke-Manager/karman/core/internal/dbutil"
 	"github.com/Karaoke-Manager/karman/model"
 	"github.com/Karaoke-Manager/karman/pkg/mediatype"
@@ -30,6 +33,33 @@ func NewDBRepository(logger *slog.Logger, db pgxutil.DB) Repository {
 	return &dbRepo{logger, db}
 }
 
+// artistColumns selects the canonical names of the main and featured artists of a song s.
+// The artists are ordered by their position within the song.
+const artistColumns = `ARRAY(SELECT ar.name FROM song_artists AS sa JOIN artists AS ar ON sa.artist_id = ar.id
+        WHERE sa.song_id = s.id AND sa.role = 'main' ORDER BY sa.position) AS artists,
+    ARRAY(SELECT ar.name FROM song_artists AS sa JOIN artists AS ar ON sa.artist_id = ar.id
+        WHERE sa.song_id = s.id AND sa.role = 'featured' ORDER BY sa.position) AS featured_artists`
+
+// tagColumns selects the names of the tags of a song s, ordered by name.
+const tagColumns = `ARRAY(SELECT t.name FROM song_tags AS st JOIN tags AS t ON st.tag_id = t.id
+        WHERE st.song_id = s.id ORDER BY LOWER(t.name)) AS tags`
+
+// variantColumns selects the variant group of a song s, its relation to the group, and the other library songs in the group.
+// Variants are ordered by relation and title, with the original song first.
+const variantColumns = `g.uuid AS variant_group, COALESCE(sv.relation, '') AS variant_relation,
+    COALESCE((SELECT JSONB_AGG(JSONB_BUILD_OBJECT('song', o.uuid, 'title', o.title, 'creator', o.creator, 'relation', ov.relation)
+            ORDER BY ov.relation <> 'original', ov.relation, o.title, o.id)
+        FROM song_variants AS ov JOIN songs AS o ON ov.song_id = o.id
+        WHERE ov.group_id = sv.group_id AND ov.song_id <> s.id AND o.upload_id IS NULL AND o.deleted_at IS NULL), '[]'::JSONB) AS variants`
+
+// reviewColumns selects the review state of a song s.
+// Songs without an explicit review state are drafts.
+const reviewColumns = `COALESCE((SELECT sr.state FROM song_reviews AS sr WHERE sr.song_id = s.id), 'draft') AS review_state`
+
+// variantJoins joins the variant group g of a song s.
+const variantJoins = `LEFT OUTER JOIN song_variants AS sv ON sv.song_id = s.id
+        LEFT OUTER JOIN song_groups AS g ON sv.group_id = g.id`
+
 // songRow is the data returned by a SELECT query for songs.
 // This type is used by GetSong and FindSongs.
 type songRow struct {
@@ -50,21 +80,33 @@ type songRow struct {
 	MedleyEndBeat   ultrastar.Beat `db:"medley_end_beat"`
 	ManualMedley    bool           `db:"manual_medley"`
 
-	Title       string
-	Artists     []string
-	Genre       string
-	Edition     string
-	Creator     string
-	Language    string
-	Year        int
-	Comment     string
-	Extra       map[string]string
-	DuetSinger1 string `db:"duet_singer1"`
-	DuetSinger2 string `db:"duet_singer2"`
+	Title           string
+	Artists         []string
+	FeaturedArtists []string `db:"featured_artists"`
+	Tags            []string
+	Genre           string
+	Edition         string
+	Creator         string
+	Language        string
+	Year            int
+	Comment         string
+	Extra           map[string]string
+	DuetSinger1     string `db:"duet_singer1"`
+	DuetSinger2     string `db:"duet_singer2"`
 
 	NotesP1 dbutil.Notes `db:"notes_p1"`
 	NotesP2 dbutil.Notes `db:"notes_p2"`
 
+	VariantGroup    uuid.NullUUID `db:"variant_group"`
+	VariantRelation string        `db:"variant_relation"`
+	Variants        []variantRow
+	ReviewState     string `db:"review_state"`
+
+	StatsP1    trackStatsRow    `db:"stats_p1"`
+	StatsP2    *trackStatsRow   `db:"stats_p2"`
+	Length     time.Duration    `db:"length"`
+	Difficulty model.Difficulty `db:"difficulty"`
+
 	AudioUUID      uuid.NullUUID        `db:"audio_uuid"`
 	AudioCreatedAt pgtype.Timestamp     `db:"audio_created_at"`
 	AudioUpdatedAt pgtype.Timestamp     `db:"audio_updated_at"`
@@ -118,8 +160,14 @@ func (r songRow) toModel() model.Song {
 			CreatedAt: r.CreatedAt,
 			UpdatedAt: r.UpdatedAt,
 		},
-		InUpload: r.UploadID.Valid,
-		Artists:  r.Artists,
+		InUpload:        r.UploadID.Valid,
+		Artists:         r.Artists,
+		FeaturedArtists: r.FeaturedArtists,
+		Tags:            r.Tags,
+		VariantGroup:    r.VariantGroup.UUID,
+		VariantRelation: model.VariantRelation(r.VariantRelation),
+		Variants:        make([]model.SongVariant, len(r.Variants)),
+		ReviewState:     model.ReviewState(r.ReviewState),
 		Song: ultrastar.Song{
 			BPM:             r.BPM,
 			Gap:             r.Gap,
@@ -142,7 +190,20 @@ func (r songRow) toModel() model.Song {
 			DuetSinger2:     r.DuetSinger2,
 			NotesP1:         ultrastar.Notes(r.NotesP1),
 			NotesP2:         ultrastar.Notes(r.NotesP2),
-		}}
+		},
+		Stats: model.SongStats{
+			P1:         r.StatsP1.toModel(),
+			Length:     r.Length,
+			Difficulty: r.Difficulty,
+		},
+	}
+	if r.StatsP2 != nil {
+		p2 := r.StatsP2.toModel()
+		song.Stats.P2 = &p2
+	}
+	for i, v := range r.Variants {
+		song.Variants[i] = model.SongVariant(v)
+	}
 	if r.DeletedAt.Valid {
 		song.DeletedAt = r.DeletedAt.Time
 	}
@@ -223,11 +284,24 @@ func (r songRow) toModel() model.Song {
 
 // CreateSong creates song in the database.
 // This method also sets nil values in song to equivalent zero values in order to avoid constraint violations.
+// The artists of song are linked to existing artists by name or alias, unknown artists are created.
+// song.Artists and song.FeaturedArtists are set to the canonical artist names.
 func (r *dbRepo) CreateSong(ctx context.Context, song *model.Song) error {
 	prepareSong(song)
-	row, err := pgxutil.InsertRowReturning(ctx, r.db, "songs", map[string]any{
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		return r.createSong(ctx, tx, song)
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not create song.", tint.Err(err))
+		return err
+	}
+	return nil
+}
+
+// createSong implements CreateSong using the specified database connection.
+func (r *dbRepo) createSong(ctx context.Context, db pgxutil.DB, song *model.Song) error {
+	row, err := pgxutil.InsertRowReturning(ctx, db, "songs", map[string]any{
 		"title":    song.Title,
-		"artists":  song.Artists,
 		"genre":    song.Genre,
 		"edition":  song.Edition,
 		"creator":  song.Creator,
@@ -250,18 +324,30 @@ func (r *dbRepo) CreateSong(ctx context.Context, song *model.Song) error {
 		"notes_p2":     dbutil.Notes(song.NotesP2),
 		"duet_singer1": song.DuetSinger1,
 		"duet_singer2": song.DuetSinger2,
-	}, "uuid, created_at, updated_at", pgx.RowToStructByName[struct {
+
+		"stats_p1":   fromTrackStats(song.Stats.P1),
+		"stats_p2":   fromTrackStatsPtr(song.Stats.P2),
+		"length":     song.Stats.Length,
+		"difficulty": song.Stats.Difficulty,
+	}, "id, uuid, created_at, updated_at", pgx.RowToStructByName[struct {
+		ID        int
 		UUID      uuid.UUID
 		CreatedAt time.Time `db:"created_at"`
 		UpdatedAt time.Time `db:"updated_at"`
 	}])
 	if err != nil {
-		r.logger.ErrorContext(ctx, "Could not create song.", tint.Err(err))
+		return err
+	}
+	if err = setArtists(ctx, db, row.ID, song); err != nil {
+		return err
+	}
+	if err = setTags(ctx, db, row.ID, song); err != nil {
 		return err
 	}
 	song.UUID = row.UUID
 	song.CreatedAt = row.CreatedAt
 	song.UpdatedAt = row.UpdatedAt
+	song.ReviewState = model.ReviewStateDraft
 	return nil
 }
 
@@ -269,9 +355,14 @@ func (r *dbRepo) CreateSong(ctx context.Context, song *model.Song) error {
 func (r *dbRepo) GetSong(ctx context.Context, id uuid.UUID) (model.Song, error) {
 	row, err := pgxutil.SelectRow(ctx, r.db, `SELECT
     s.uuid, s.created_at, s.updated_at, s.deleted_at, s.upload_id,
-    s.title, s.artists, s.genre, s.edition, s.creator, s.language, s.year, s.comment, s.extra,
+    s.title, s.genre, s.edition, s.creator, s.language, s.year, s.comment, s.extra,
     s.bpm, s.gap, s.video_gap, s.start, s."end", s.preview_start, s.medley_start_beat, s.medley_end_beat, s.manual_medley,
+    s.stats_p1, s.stats_p2, s.length, s.difficulty,
     s.duet_singer1, s.duet_singer2, s.notes_p1, s.notes_p2,
+    `+artistColumns+`,
+    `+tagColumns+`,
+    `+variantColumns+`,
+    `+reviewColumns+`,
     
     a.uuid AS audio_uuid, a.created_at AS audio_created_at, a.updated_at AS audio_updated_at, a.deleted_at AS audio_deleted_at, a.type AS audio_type, a.size AS audio_size, a.checksum AS audio_checksum, a.duration AS audio_duration,
     CASE WHEN a.upload_id IS NULL THEN '' ELSE a.path END AS audio_path,
@@ -286,6 +377,7 @@ func (r *dbRepo) GetSong(ctx context.Context, id uuid.UUID) (model.Song, error)
     	LEFT OUTER JOIN files AS c ON s.cover_file_id = c.id
     	LEFT OUTER JOIN files AS v ON s.video_file_id = v.id
     	LEFT OUTER JOIN files AS b ON s.background_file_id = b.id
+        `+variantJoins+`
     WHERE S.uuid = $1`, []any{id}, pgx.RowToStructByName[songRow])
 	if err != nil {
 		if !errors.Is(err, pgx.ErrNoRows) {
@@ -296,12 +388,27 @@ func (r *dbRepo) GetSong(ctx context.Context, id uuid.UUID) (model.Song, error)
 	return row.toModel(), nil
 }
 
+// GetLibrarySong fetches a single song from the database by its UUID.
+// Songs in the trash or in uploads are treated as missing.
+func (r *dbRepo) GetLibrarySong(ctx context.Context, id uuid.UUID) (model.Song, error) {
+	song, err := r.GetSong(ctx, id)
+	if err != nil {
+		return model.Song{}, err
+	}
+	if song.Deleted() || song.InUpload {
+		return model.Song{}, core.ErrNotFound
+	}
+	return song, nil
+}
+
 // FindSongs fetches multiple songs from the database.
+// Songs that have been moved to the trash are not included.
 // The results are paginated with limit and offset.
-func (r *dbRepo) FindSongs(ctx context.Context, limit int, offset int64) ([]model.Song, int64, error) {
+func (r *dbRepo) FindSongs(ctx context.Context, filter Filter, limit int, offset int64) ([]model.Song, int64, error) {
+	condition, args := filterCondition(filter)
 	total, err := pgxutil.SelectRow(ctx, r.db, `SELECT COUNT(*)
 	FROM songs AS s
-	WHERE s.upload_id IS NULL`, nil, pgx.RowTo[int64])
+	WHERE s.upload_id IS NULL AND s.deleted_at IS NULL AND `+condition, args, pgx.RowTo[int64])
 	if err != nil {
 		r.logger.ErrorContext(ctx, "Could not count songs.", "limit", limit, "offset", offset, tint.Err(err))
 		return nil, 0, err
@@ -309,9 +416,14 @@ func (r *dbRepo) FindSongs(ctx context.Context, limit int, offset int64) ([]mode
 
 	songs, err := pgxutil.Select(ctx, r.db, `SELECT
     s.uuid, s.created_at, s.updated_at, s.deleted_at, s.upload_id,
-    s.title, s.artists, s.genre, s.edition, s.creator, s.language, s.year, s.comment, s.extra,
+    s.title, s.genre, s.edition, s.creator, s.language, s.year, s.comment, s.extra,
     s.bpm, s.gap, s.video_gap, s.start, s."end", s.preview_start, s.medley_start_beat, s.medley_end_beat, s.manual_medley,
+    s.stats_p1, s.stats_p2, s.length, s.difficulty,
     s.notes_p1, s.notes_p2, s.duet_singer1, s.duet_singer2,
+    `+artistColumns+`,
+    `+tagColumns+`,
+    `+variantColumns+`,
+    `+reviewColumns+`,
     
     a.uuid AS audio_uuid, a.created_at AS audio_created_at, a.updated_at AS audio_updated_at, a.deleted_at AS audio_deleted_at, a.type AS audio_type, a.size AS audio_size, a.checksum AS audio_checksum, a.duration AS audio_duration,
     CASE WHEN a.upload_id IS NULL THEN '' ELSE a.path END AS audio_path,
@@ -326,8 +438,9 @@ func (r *dbRepo) FindSongs(ctx context.Context, limit int, offset int64) ([]mode
     	LEFT OUTER JOIN files AS c ON s.cover_file_id = c.id
     	LEFT OUTER JOIN files AS v ON s.video_file_id = v.id
     	LEFT OUTER JOIN files AS b ON s.background_file_id = b.id
-	WHERE s.upload_id IS NULL
-	LIMIT CASE WHEN $1 < 0 THEN NULL ELSE $1 END OFFSET $2`, []any{limit, offset}, func(row pgx.CollectableRow) (model.Song, error) {
+        `+variantJoins+`
+	WHERE s.upload_id IS NULL AND s.deleted_at IS NULL AND `+condition+fmt.Sprintf(`
+	LIMIT CASE WHEN $%[1]d < 0 THEN NULL ELSE $%[1]d END OFFSET $%[2]d`, len(args)+1, len(args)+2), append(args, limit, offset), func(row pgx.CollectableRow) (model.Song, error) {
 		data, err := pgx.RowToStructByName[songRow](row)
 		return data.toModel(), err
 	})
@@ -337,11 +450,139 @@ func (r *dbRepo) FindSongs(ctx context.Context, limit int, offset int64) ([]mode
 	return songs, total, err
 }
 
+// FindDeletedSongs fetches songs from the database that have been moved to the trash.
+// The most recently deleted songs are returned first.
+// The results are paginated with limit and offset.
+func (r *dbRepo) FindDeletedSongs(ctx context.Context, limit int, offset int64) ([]model.Song, int64, error) {
+	total, err := pgxutil.SelectRow(ctx, r.db, `SELECT COUNT(*)
+	FROM songs AS s
+	WHERE s.upload_id IS NULL AND s.deleted_at IS NOT NULL`, nil, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not count deleted songs.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, 0, err
+	}
+
+	songs, err := pgxutil.Select(ctx, r.db, `SELECT
+    s.uuid, s.created_at, s.updated_at, s.deleted_at, s.upload_id,
+    s.title, s.genre, s.edition, s.creator, s.language, s.year, s.comment, s.extra,
+    s.bpm, s.gap, s.video_gap, s.start, s."end", s.preview_start, s.medley_start_beat, s.medley_end_beat, s.manual_medley,
+    s.stats_p1, s.stats_p2, s.length, s.difficulty,
+    s.notes_p1, s.notes_p2, s.duet_singer1, s.duet_singer2,
+    `+artistColumns+`,
+    `+tagColumns+`,
+    `+variantColumns+`,
+    `+reviewColumns+`,
+    
+    a.uuid AS audio_uuid, a.created_at AS audio_created_at, a.updated_at AS audio_updated_at, a.deleted_at AS audio_deleted_at, a.type AS audio_type, a.size AS audio_size, a.checksum AS audio_checksum, a.duration AS audio_duration,
+    CASE WHEN a.upload_id IS NULL THEN '' ELSE a.path END AS audio_path,
+    c.uuid AS cover_uuid, c.created_at AS cover_created_at, c.updated_at AS cover_updated_at, c.deleted_at AS cover_deleted_at, c.type AS cover_type, c.size AS cover_size, c.checksum AS cover_checksum, c.width cover_width, c.height AS cover_height,
+    CASE WHEN c.upload_id IS NULL THEN '' ELSE c.path END AS cover_path,
+    v.uuid AS video_uuid, v.created_at AS video_created_at, v.updated_at AS video_updated_at, v.deleted_at AS video_deleted_at, v.type AS video_type, v.size AS video_size, v.checksum AS video_checksum, v.duration AS video_duration, v.width AS video_width, v.height AS video_height,
+    CASE WHEN v.upload_id IS NULL THEN '' ELSE v.path END AS video_path,
+    b.uuid AS bg_uuid, b.created_at AS bg_created_at, b.updated_at AS bg_updated_at, b.deleted_at AS bg_deleted_at, b.type AS bg_type, b.size AS bg_size, b.checksum AS bg_checksum, b.width AS bg_width, b.height AS bg_height,
+    CASE WHEN b.upload_id IS NULL THEN '' ELSE b.path END AS bg_path
+    FROM songs AS s
+        LEFT OUTER JOIN files AS a ON s.audio_file_id = a.id
+    	LEFT OUTER JOIN files AS c ON s.cover_file_id = c.id
+    	LEFT OUTER JOIN files AS v ON s.video_file_id = v.id
+    	LEFT OUTER JOIN files AS b ON s.background_file_id = b.id
+        `+variantJoins+`
+	WHERE s.upload_id IS NULL AND s.deleted_at IS NOT NULL
+	ORDER BY s.deleted_at DESC, s.id
+	LIMIT CASE WHEN $1 < 0 THEN NULL ELSE $1 END OFFSET $2`, []any{limit, offset}, func(row pgx.CollectableRow) (model.Song, error) {
+		data, err := pgx.RowToStructByName[songRow](row)
+		return data.toModel(), err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list deleted songs.", "limit", limit, "offset", offset, tint.Err(err))
+	}
+	return songs, total, err
+}
+
 // UpdateSong updates the song in the database with song.UUID.
 // File references must already exist in the database, or they will be set to nil.
 // Data of file references (size, checksum, ...) is not updated.
+// The artists of song are linked the same way as in CreateSong.
 func (r *dbRepo) UpdateSong(ctx context.Context, song *model.Song) error {
 	prepareSong(song)
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		return r.updateSong(ctx, tx, song, nil)
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not update song.", "uuid", song.UUID, tint.Err(err))
+		}
+		return dbutil.Error(err)
+	}
+	return nil
+}
+
+// UpdateSongIfUnmodified updates the song in the database with song.UUID if its updated_at timestamp equals version.
+// The check and the update are performed by a single statement, so concurrent updates cannot get lost.
+func (r *dbRepo) UpdateSongIfUnmodified(ctx context.Context, song *model.Song, version time.Time) error {
+	prepareSong(song)
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		return r.updateSong(ctx, tx, song, &version)
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return core.ErrPreconditionFailed
+	} else if err != nil {
+		r.logger.ErrorContext(ctx, "Could not update song.", "uuid", song.UUID, tint.Err(err))
+		return dbutil.Error(err)
+	}
+	return nil
+}
+
+// UpdateSongs updates all songs in a single transaction.
+// The songs are updated in place, the same way as by UpdateSong.
+func (r *dbRepo) UpdateSongs(ctx context.Context, songs []model.Song) error {
+	for i := range songs {
+		prepareSong(&songs[i])
+	}
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		for i := range songs {
+			if err := r.updateSong(ctx, tx, &songs[i], nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not update songs.", "count", len(songs), tint.Err(err))
+		}
+		return dbutil.Error(err)
+	}
+	return nil
+}
+
+// UpdateSongsIfUnmodified updates all songs in a single transaction if their updated_at timestamps equal their versions.
+func (r *dbRepo) UpdateSongsIfUnmodified(ctx context.Context, songs []model.Song, versions map[uuid.UUID]time.Time) error {
+	for i := range songs {
+		prepareSong(&songs[i])
+	}
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		for i := range songs {
+			version := versions[songs[i].UUID]
+			if err := r.updateSong(ctx, tx, &songs[i], &version); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return core.ErrPreconditionFailed
+	} else if err != nil {
+		r.logger.ErrorContext(ctx, "Could not update songs.", "count", len(songs), tint.Err(err))
+		return dbutil.Error(err)
+	}
+	return nil
+}
+
+// updateSong implements UpdateSong using the specified database connection.
+// If version is not nil, the song is only updated if its updated_at timestamp equals *version.
+// Otherwise, pgx.ErrNoRows is returned.
+func (r *dbRepo) updateSong(ctx context.Context, db pgxutil.DB, song *model.Song, version *time.Time) error {
 	var audioUUID, coverUUID, videoUUID, backgroundUUID uuid.NullUUID
 	if song.AudioFile != nil {
 		audioUUID = uuid.NullUUID{UUID: song.AudioFile.UUID, Valid: true}
@@ -355,22 +596,26 @@ func (r *dbRepo) UpdateSong(ctx context.Context, song *model.Song) error {
 	if song.BackgroundFile != nil {
 		backgroundUUID = uuid.NullUUID{UUID: song.BackgroundFile.UUID, Valid: true}
 	}
-	row, err := pgxutil.SelectRow(ctx, r.db, `UPDATE songs SET 
-		title = $2, artists = $3, genre = $4, edition = $5, creator = $6, language = $7, year = $8, comment = $9, extra = $10,
-		bpm = $11, gap = $12, video_gap = $13, start = $14, "end" = $15, preview_start = $16, medley_start_beat = $17, medley_end_beat = $18, manual_medley = $19,
-		notes_p1 = $20, notes_p2 = $21, duet_singer1 = $22, duet_singer2 = $23,
-		audio_file_id = CASE WHEN $24::uuid IS NULL THEN NULL ELSE (SELECT id FROM files WHERE uuid = $24) END,
-		cover_file_id = CASE WHEN $25::uuid IS NULL THEN NULL ELSE (SELECT id FROM files WHERE uuid = $25) END,
-		video_file_id = CASE WHEN $26::uuid IS NULL THEN NULL ELSE (SELECT id FROM files WHERE uuid = $26) END,
-		background_file_id = CASE WHEN $27::uuid IS NULL THEN NULL ELSE (SELECT id FROM files WHERE uuid = $27) END
-	WHERE uuid = $1
-	RETURNING updated_at, audio_file_id, cover_file_id, video_file_id, background_file_id`, []any{
+	row, err := pgxutil.SelectRow(ctx, db, `UPDATE songs SET 
+		title = $2, genre = $3, edition = $4, creator = $5, language = $6, year = $7, comment = $8, extra = $9,
+		bpm = $10, gap = $11, video_gap = $12, start = $13, "end" = $14, preview_start = $15, medley_start_beat = $16, medley_end_beat = $17, manual_medley = $18,
+		notes_p1 = $19, notes_p2 = $20, duet_singer1 = $21, duet_singer2 = $22,
+		audio_file_id = CASE WHEN $23::uuid IS NULL THEN NULL ELSE (SELECT id FROM files WHERE uuid = $23) END,
+		cover_file_id = CASE WHEN $24::uuid IS NULL THEN NULL ELSE (SELECT id FROM files WHERE uuid = $24) END,
+		video_file_id = CASE WHEN $25::uuid IS NULL THEN NULL ELSE (SELECT id FROM files WHERE uuid = $25) END,
+		background_file_id = CASE WHEN $26::uuid IS NULL THEN NULL ELSE (SELECT id FROM files WHERE uuid = $26) END,
+		stats_p1 = $27, stats_p2 = $28, length = $29, difficulty = $30
+	WHERE uuid = $1 AND ($31::TIMESTAMP IS NULL OR updated_at = $31)
+	RETURNING id, updated_at, audio_file_id, cover_file_id, video_file_id, background_file_id`, []any{
 		song.UUID,
-		song.Title, song.Artists, song.Genre, song.Edition, song.Creator, song.Language, song.Year, song.Comment, song.CustomTags,
+		song.Title, song.Genre, song.Edition, song.Creator, song.Language, song.Year, song.Comment, song.CustomTags,
 		song.BPM, song.Gap, song.VideoGap, song.Start, song.End, song.PreviewStart, song.MedleyStartBeat, song.MedleyEndBeat, song.NoAutoMedley,
 		dbutil.Notes(song.NotesP1), dbutil.Notes(song.NotesP2), song.DuetSinger1, song.DuetSinger2,
 		audioUUID, coverUUID, videoUUID, backgroundUUID,
+		fromTrackStats(song.Stats.P1), fromTrackStatsPtr(song.Stats.P2), song.Stats.Length, song.Stats.Difficulty,
+		version,
 	}, pgx.RowToStructByName[struct {
+		ID               int
 		UpdatedAt        time.Time   `db:"updated_at"`
 		AudioFileID      pgtype.Int4 `db:"audio_file_id"`
 		CoverFileID      pgtype.Int4 `db:"cover_file_id"`
@@ -378,8 +623,13 @@ func (r *dbRepo) UpdateSong(ctx context.Context, song *model.Song) error {
 		BackgroundFileID pgtype.Int4 `db:"background_file_id"`
 	}])
 	if err != nil {
-		r.logger.ErrorContext(ctx, "Could not update song.", "uuid", song.UUID, tint.Err(err))
-		return dbutil.Error(err)
+		return err
+	}
+	if err = setArtists(ctx, db, row.ID, song); err != nil {
+		return err
+	}
+	if err = setTags(ctx, db, row.ID, song); err != nil {
+		return err
 	}
 	song.UpdatedAt = row.UpdatedAt
 	if !row.AudioFileID.Valid {
@@ -397,10 +647,10 @@ func (r *dbRepo) UpdateSong(ctx context.Context, song *model.Song) error {
 	return nil
 }
 
-// DeleteSong deletes the song with the specified UUID from the database.
-// If no song with the specified UUID existed, the first return value will be false.
+// DeleteSong moves the song with the specified UUID to the trash by setting its deleted_at timestamp.
+// If no song with the specified UUID existed or the song already was in the trash, the first return value will be false.
 func (r *dbRepo) DeleteSong(ctx context.Context, id uuid.UUID) (bool, error) {
-	_, err := pgxutil.ExecRow(ctx, r.db, `DELETE FROM songs WHERE uuid = $1`, id)
+	_, err := pgxutil.ExecRow(ctx, r.db, `UPDATE songs SET deleted_at = NOW() WHERE uuid = $1 AND deleted_at IS NULL`, id)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return false, nil
 	}
@@ -411,12 +661,223 @@ func (r *dbRepo) DeleteSong(ctx context.Context, id uuid.UUID) (bool, error) {
 	return true, nil
 }
 
+// DeleteSongIfUnmodified moves the song with the specified UUID to the trash if its updated_at timestamp equals version.
+func (r *dbRepo) DeleteSongIfUnmodified(ctx context.Context, id uuid.UUID, version time.Time) error {
+	_, err := pgxutil.ExecRow(ctx, r.db, `UPDATE songs SET deleted_at = NOW() WHERE uuid = $1 AND deleted_at IS NULL AND updated_at = $2`, id, version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return core.ErrPreconditionFailed
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not delete song.", "uuid", id, tint.Err(err))
+		return err
+	}
+	return nil
+}
+
+// DeleteSongsIfUnmodified moves the songs to the trash in a single transaction if their updated_at timestamps equal their versions.
+func (r *dbRepo) DeleteSongsIfUnmodified(ctx context.Context, versions map[uuid.UUID]time.Time) error {
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		for id, version := range versions {
+			if _, err := pgxutil.ExecRow(ctx, tx, `UPDATE songs SET deleted_at = NOW() WHERE uuid = $1 AND deleted_at IS NULL AND updated_at = $2`, id, version); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return core.ErrPreconditionFailed
+	} else if err != nil {
+		r.logger.ErrorContext(ctx, "Could not delete songs.", "count", len(versions), tint.Err(err))
+		return err
+	}
+	return nil
+}
+
+// RestoreSong removes the song with the specified UUID from the trash.
+// If no song with the specified UUID is in the trash, the first return value will be false.
+func (r *dbRepo) RestoreSong(ctx context.Context, id uuid.UUID) (bool, error) {
+	_, err := pgxutil.ExecRow(ctx, r.db, `UPDATE songs SET deleted_at = NULL WHERE uuid = $1 AND deleted_at IS NOT NULL`, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not restore song.", "uuid", id, tint.Err(err))
+		return false, err
+	}
+	return true, nil
+}
+
+// PurgeSongs permanently deletes all songs that have been in the trash for longer than retention.
+// Media files of purged songs are not deleted but become orphaned.
+// The first return value indicates the number of purged songs.
+func (r *dbRepo) PurgeSongs(ctx context.Context, retention time.Duration) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM songs WHERE deleted_at IS NOT NULL AND deleted_at < NOW() - $1::INTERVAL`, retention)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not purge deleted songs.", "retention", retention, tint.Err(err))
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// PurgeSong permanently deletes the song with the specified UUID.
+// Media files of the song are not deleted but become orphaned.
+// If no song with the specified UUID existed, the first return value will be false.
+func (r *dbRepo) PurgeSong(ctx context.Context, id uuid.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM songs WHERE uuid = $1`, id)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not purge song.", "uuid", id, tint.Err(err))
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// MergeSongs updates song and removes the duplicates in a single transaction.
+// References to the duplicates are moved to song before the duplicates are removed.
+func (r *dbRepo) MergeSongs(ctx context.Context, song *model.Song, duplicates []uuid.UUID, purge bool, version time.Time) error {
+	prepareSong(song)
+	var v *time.Time
+	if !version.IsZero() {
+		v = &version
+	}
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if err := r.updateSong(ctx, tx, song, v); err != nil {
+			return err
+		}
+		if err := mergeReferences(ctx, tx, song, duplicates); err != nil {
+			return err
+		}
+		query := `UPDATE songs SET deleted_at = NOW() WHERE uuid = ANY($1) AND uuid <> $2 AND deleted_at IS NULL`
+		if purge {
+			query = `DELETE FROM songs WHERE uuid = ANY($1) AND uuid <> $2`
+		}
+		_, err := tx.Exec(ctx, query, duplicates, song.UUID)
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) && v != nil {
+		return core.ErrPreconditionFailed
+	} else if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not merge songs.", "uuid", song.UUID, "duplicates", duplicates, tint.Err(err))
+		}
+		return dbutil.Error(err)
+	}
+	return nil
+}
+
+// mergeReferences moves references to the songs with the UUIDs in duplicates to song.
+// The tags of the duplicates are added to song and song.Tags is updated accordingly.
+func mergeReferences(ctx context.Context, db pgxutil.DB, song *model.Song, duplicates []uuid.UUID) error {
+	targetID, err := pgxutil.SelectRow(ctx, db, `SELECT id FROM songs WHERE uuid = $1`, []any{song.UUID}, pgx.RowTo[int])
+	if err != nil {
+		return err
+	}
+	sourceIDs, err := pgxutil.Select(ctx, db, `SELECT id FROM songs WHERE uuid = ANY($1) AND id <> $2`, []any{duplicates, targetID}, pgx.RowTo[int])
+	if err != nil {
+		return err
+	}
+	for _, table := range []string{"playlist_songs", "song_scores", "session_requests", "song_comments"} {
+		if _, err = db.Exec(ctx, `UPDATE `+table+` SET song_id = $1 WHERE song_id = ANY($2)`, targetID, sourceIDs); err != nil {
+			return err
+		}
+	}
+	// Play statistics are added up.
+	if _, err = db.Exec(ctx, `INSERT INTO song_plays (song_id, times_played, last_played)
+	SELECT $1, SUM(p.times_played), MAX(p.last_played) FROM song_plays AS p WHERE p.song_id = ANY($2)
+	HAVING COUNT(*) > 0
+	ON CONFLICT (song_id) DO UPDATE SET times_played = song_plays.times_played + EXCLUDED.times_played,
+	    last_played = GREATEST(song_plays.last_played, EXCLUDED.last_played)`, targetID, sourceIDs); err != nil {
+		return err
+	}
+	if _, err = db.Exec(ctx, `DELETE FROM song_plays WHERE song_id = ANY($1)`, sourceIDs); err != nil {
+		return err
+	}
+	// A song belongs to at most one variant group, so a link is only moved if the target is not a variant yet.
+	if _, err = db.Exec(ctx, `UPDATE song_variants SET song_id = $1
+	WHERE song_id = (SELECT MIN(v.song_id) FROM song_variants AS v WHERE v.song_id = ANY($2))
+	  AND NOT EXISTS (SELECT 1 FROM song_variants AS v WHERE v.song_id = $1)`, targetID, sourceIDs); err != nil {
+		return err
+	}
+	// Likewise, a song has a single review state, so the state of a duplicate is only moved if the target is a draft.
+	if _, err = db.Exec(ctx, `UPDATE song_reviews SET song_id = $1
+	WHERE song_id = (SELECT MIN(r.song_id) FROM song_reviews AS r WHERE r.song_id = ANY($2))
+	  AND NOT EXISTS (SELECT 1 FROM song_reviews AS r WHERE r.song_id = $1)`, targetID, sourceIDs); err != nil {
+		return err
+	}
+	if _, err = db.Exec(ctx, `INSERT INTO song_tags (song_id, tag_id)
+	SELECT $1, t.tag_id FROM song_tags AS t WHERE t.song_id = ANY($2)
+	ON CONFLICT DO NOTHING`, targetID, sourceIDs); err != nil {
+		return err
+	}
+	song.Tags, err = pgxutil.SelectRow(ctx, db, `SELECT `+tagColumns+` FROM songs AS s WHERE s.id = $1`, []any{targetID}, pgx.RowTo[[]string])
+	return err
+}
+
+// setArtists replaces the artists credited by the song with the specified ID with song.Artists and song.FeaturedArtists.
+// Artists are matched by name or alias, ignoring case.
+// Artists that do not exist yet are created.
+// An artist is only linked once per song, main artists take precedence over featured artists.
+// Afterward song.Artists and song.FeaturedArtists are set to the canonical names of the linked artists.
+func setArtists(ctx context.Context, db pgxutil.DB, id int, song *model.Song) error {
+	if _, err := db.Exec(ctx, `DELETE FROM song_artists WHERE song_id = $1`, id); err != nil {
+		return err
+	}
+	_, err := db.Exec(ctx, `INSERT INTO song_artists (song_id, artist_id, role, position)
+	SELECT $1, artist_id(a.name), a.role, a.position
+	FROM (SELECT TRIM(name) AS name, 'main' AS role, position FROM UNNEST($2::TEXT[]) WITH ORDINALITY AS t(name, position)
+	      UNION ALL
+	      SELECT TRIM(name) AS name, 'featured' AS role, position FROM UNNEST($3::TEXT[]) WITH ORDINALITY AS t(name, position)) AS a
+	WHERE a.name <> ''
+	ORDER BY a.role = 'featured', a.position
+	ON CONFLICT DO NOTHING`, id, song.Artists, song.FeaturedArtists)
+	if err != nil {
+		return err
+	}
+	row, err := pgxutil.SelectRow(ctx, db, `SELECT `+artistColumns+` FROM songs AS s WHERE s.id = $1`, []any{id}, pgx.RowToStructByName[struct {
+		Artists         []string
+		FeaturedArtists []string `db:"featured_artists"`
+	}])
+	if err != nil {
+		return err
+	}
+	song.Artists = row.Artists
+	song.FeaturedArtists = row.FeaturedArtists
+	return nil
+}
+
+// setTags replaces the tags of the song with the specified ID with song.Tags.
+// Tags are linked by name, unknown tags are created.
+// song.Tags is set to the canonical tag names.
+func setTags(ctx context.Context, db pgxutil.DB, id int, song *model.Song) error {
+	if _, err := db.Exec(ctx, `DELETE FROM song_tags WHERE song_id = $1`, id); err != nil {
+		return err
+	}
+	_, err := db.Exec(ctx, `INSERT INTO song_tags (song_id, tag_id)
+	SELECT $1, tag_id(t.name)
+	FROM (SELECT TRIM(name) AS name, position FROM UNNEST($2::TEXT[]) WITH ORDINALITY AS n(name, position)) AS t
+	WHERE t.name <> ''
+	ORDER BY t.position
+	ON CONFLICT DO NOTHING`, id, song.Tags)
+	if err != nil {
+		return err
+	}
+	song.Tags, err = pgxutil.SelectRow(ctx, db, `SELECT `+tagColumns+` FROM songs AS s WHERE s.id = $1`, []any{id}, pgx.RowTo[[]string])
+	return err
+}
+
 // prepareSong modifies song in a way that it can be inserted into the database.
 // This mainly concerns replacing nil values with non-nil zero values.
+// The stats of song are recalculated as well.
 func prepareSong(song *model.Song) {
+	song.Stats = CalculateStats(*song)
 	if song.Artists == nil {
 		song.Artists = make([]string, 0)
 	}
+	if song.FeaturedArtists == nil {
+		song.FeaturedArtists = make([]string, 0)
+	}
+	if song.Tags == nil {
+		song.Tags = make([]string, 0)
+	}
 	if song.CustomTags == nil {
 		song.CustomTags = make(map[string]string)
 	}
@@ -424,3 +885,78 @@ func prepareSong(song *model.Song) {
 		song.NotesP1 = make(ultrastar.Notes, 0)
 	}
 }
+
+// filterCondition returns an SQL condition that matches the songs s that match filter.
+// The second return value contains the parameters of the condition, starting at $1.
+func filterCondition(filter Filter) (string, []any) {
+	conditions := []string{"TRUE"}
+	args := make([]any, 0)
+	if filter.Range != nil {
+		args = append(args, filter.Range.Lowest, filter.Range.Highest)
+		fits := func(column string) string {
+			return fmt.Sprintf(`((%[1]s->>'pitched')::INT > 0 AND (%[1]s->>'lowestPitch')::INT >= $%[2]d AND (%[1]s->>'highestPitch')::INT <= $%[3]d)`,
+				column, len(args)-1, len(args))
+		}
+		conditions = append(conditions, "("+fits("s.stats_p1")+" OR "+fits("s.stats_p2")+")")
+	}
+	if filter.MinDifficulty != 0 {
+		args = append(args, filter.MinDifficulty)
+		conditions = append(conditions, fmt.Sprintf("s.difficulty >= $%d", len(args)))
+	}
+	if filter.MaxDifficulty != 0 {
+		args = append(args, filter.MaxDifficulty)
+		conditions = append(conditions, fmt.Sprintf("s.difficulty <= $%d", len(args)))
+	}
+	if filter.VariantGroup != uuid.Nil {
+		args = append(args, filter.VariantGroup)
+		conditions = append(conditions, fmt.Sprintf(`EXISTS(SELECT 1 FROM song_variants AS fv JOIN song_groups AS fg ON fv.group_id = fg.id
+		WHERE fv.song_id = s.id AND fg.uuid = $%d)`, len(args)))
+	}
+	if len(filter.Tags) > 0 {
+		args = append(args, filter.Tags)
+		conditions = append(conditions, fmt.Sprintf(`NOT EXISTS(SELECT 1 FROM UNNEST($%d::TEXT[]) AS f(name)
+		WHERE NOT EXISTS(SELECT 1 FROM song_tags AS st JOIN tags AS t ON st.tag_id = t.id
+			WHERE st.song_id = s.id AND LOWER(t.name) = LOWER(TRIM(f.name))))`, len(args)))
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// variantRow is the representation of model.SongVariant in the variants column.
+type variantRow struct {
+	Song     uuid.UUID             `json:"song"`
+	Title    string                `json:"title"`
+	Creator  string                `json:"creator"`
+	Relation model.VariantRelation `json:"relation"`
+}
+
+// trackStatsRow is the representation of model.TrackStats in the database.
+type trackStatsRow struct {
+	Notes          int             `json:"notes"`
+	Pitched        int             `json:"pitched"`
+	LowestPitch    ultrastar.Pitch `json:"lowestPitch"`
+	HighestPitch   ultrastar.Pitch `json:"highestPitch"`
+	MedianPitch    ultrastar.Pitch `json:"medianPitch"`
+	NoteDensity    float64         `json:"noteDensity"`
+	GoldenRatio    float64         `json:"goldenRatio"`
+	FreestyleRatio float64         `json:"freestyleRatio"`
+}
+
+// fromTrackStats converts stats into its database representation.
+func fromTrackStats(stats model.TrackStats) trackStatsRow {
+	return trackStatsRow(stats)
+}
+
+// fromTrackStatsPtr converts stats into its database representation.
+// A nil value is converted into a nil value.
+func fromTrackStatsPtr(stats *model.TrackStats) *trackStatsRow {
+	if stats == nil {
+		return nil
+	}
+	row := fromTrackStats(*stats)
+	return &row
+}
+
+// toModel converts r into an equivalent model.TrackStats.
+func (r trackStatsRow) toModel() model.TrackStats {
+	return model.TrackStats(r)
+}
@@ -5,12 +5,15 @@ package song
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
 	"time"
 
+	"codello.dev/ultrastar"
 	"github.com/google/uuid"
 
 	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/artist"
 	"github.com/Karaoke-Manager/karman/model"
 	"github.com/Karaoke-Manager/karman/pkg/mediatype"
 	"github.com/Karaoke-Manager/karman/pkg/nolog"
@@ -115,7 +118,7 @@ func Test_dbRepo_FindSongs(t *testing.T) {
 
 	for name, c := range cases {
 		t.Run(name, func(t *testing.T) {
-			songs, total, err := repo.FindSongs(context.TODO(), c.Limit, c.Offset)
+			songs, total, err := repo.FindSongs(context.TODO(), Filter{}, c.Limit, c.Offset)
 			if err != nil {
 				t.Errorf("FindSongs(ctx, %d, %d) returned an unexpected error: %d", c.Limit, c.Offset, err)
 				return
@@ -130,6 +133,127 @@ func Test_dbRepo_FindSongs(t *testing.T) {
 	}
 }
 
+func Test_dbRepo_FindSongs_Filter(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	testdata.NSongs(t, db, 5)
+	low := model.Song{Song: ultrastar.Song{Title: "Low", BPM: 60, NotesP1: ultrastar.Notes{
+		{Type: ultrastar.NoteTypeRegular, Start: 0, Duration: 1, Pitch: -10},
+		{Type: ultrastar.NoteTypeRegular, Start: 2, Duration: 1, Pitch: -5},
+	}}}
+	high := model.Song{Song: ultrastar.Song{Title: "High", BPM: 60, NotesP1: ultrastar.Notes{
+		{Type: ultrastar.NoteTypeRegular, Start: 0, Duration: 1, Pitch: 5},
+		{Type: ultrastar.NoteTypeRegular, Start: 2, Duration: 1, Pitch: 10},
+	}}}
+	if err := repo.CreateSong(context.TODO(), &low); err != nil {
+		t.Fatalf("CreateSong(ctx, &low) returned an unexpected error: %s", err)
+	}
+	if err := repo.CreateSong(context.TODO(), &high); err != nil {
+		t.Fatalf("CreateSong(ctx, &high) returned an unexpected error: %s", err)
+	}
+
+	songs, total, err := repo.FindSongs(context.TODO(), Filter{Range: &PitchRange{-12, 0}}, -1, 0)
+	if err != nil {
+		t.Fatalf("FindSongs(ctx, filter, -1, 0) returned an unexpected error: %s", err)
+	}
+	if total != 1 || len(songs) != 1 || songs[0].UUID != low.UUID {
+		t.Errorf("FindSongs(ctx, filter, -1, 0) returned %d songs, expected only %q", total, low.UUID)
+	} else if songs[0].Stats.P1.LowestPitch != -10 || songs[0].Stats.Difficulty != low.Stats.Difficulty {
+		t.Errorf("FindSongs(ctx, filter, -1, 0) returned stats %+v, expected %+v", songs[0].Stats, low.Stats)
+	}
+
+	_, total, err = repo.FindSongs(context.TODO(), Filter{MinDifficulty: model.DifficultyEasiest, MaxDifficulty: model.DifficultyHardest}, -1, 0)
+	if err != nil {
+		t.Fatalf("FindSongs(ctx, filter, -1, 0) returned an unexpected error: %s", err)
+	}
+	if total != 2 {
+		t.Errorf("FindSongs(ctx, filter, -1, 0) returned total = %d, expected %d", total, 2)
+	}
+
+	low.Tags = []string{"Christmas", "duet"}
+	high.Tags = []string{"christmas"}
+	if err = repo.UpdateSongs(context.TODO(), []model.Song{low, high}); err != nil {
+		t.Fatalf("UpdateSongs(ctx, songs) returned an unexpected error: %s", err)
+	}
+	songs, total, err = repo.FindSongs(context.TODO(), Filter{Tags: []string{"CHRISTMAS", "Duet"}}, -1, 0)
+	if err != nil {
+		t.Fatalf("FindSongs(ctx, filter, -1, 0) returned an unexpected error: %s", err)
+	}
+	if total != 1 || len(songs) != 1 || songs[0].UUID != low.UUID {
+		t.Errorf("FindSongs(ctx, filter, -1, 0) returned %d songs, expected only %q", total, low.UUID)
+	}
+}
+
+func Test_dbRepo_FindDeletedSongs(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	testdata.NSongs(t, db, 5)
+	deleted := testdata.DeletedSong(t, db)
+
+	songs, total, err := repo.FindDeletedSongs(context.TODO(), -1, 0)
+	if err != nil {
+		t.Errorf("FindDeletedSongs(ctx, -1, 0) returned an unexpected error: %s", err)
+		return
+	}
+	if total != 1 {
+		t.Errorf("FindDeletedSongs(ctx, -1, 0) = _, %d, _, expected %d", total, 1)
+	}
+	if len(songs) != 1 || songs[0].UUID != deleted.UUID {
+		t.Errorf("FindDeletedSongs(ctx, -1, 0) did not return the deleted song %q", deleted.UUID)
+	} else if !songs[0].Deleted() {
+		t.Errorf("FindDeletedSongs(ctx, -1, 0) returned a song without DeletedAt, expected DeletedAt to be set")
+	}
+
+	_, total, err = repo.FindSongs(context.TODO(), Filter{}, -1, 0)
+	if err != nil {
+		t.Errorf("FindSongs(ctx, -1, 0) returned an unexpected error: %s", err)
+		return
+	}
+	if total != 5 {
+		t.Errorf("FindSongs(ctx, -1, 0) = _, %d, _, expected %d", total, 5)
+	}
+}
+
+func Test_dbRepo_UpdateSongs(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+
+	t.Run("success", func(t *testing.T) {
+		songs := []model.Song{testdata.SimpleSong(t, db), testdata.SimpleSong(t, db)}
+		songs[0].Genre = "Rock"
+		songs[1].Genre = "Pop"
+		if err := repo.UpdateSongs(context.TODO(), songs); err != nil {
+			t.Fatalf("UpdateSongs(ctx, songs) returned an unexpected error: %s", err)
+		}
+		for _, expected := range songs {
+			actual, _ := repo.GetSong(context.TODO(), expected.UUID)
+			if actual.Genre != expected.Genre {
+				t.Errorf("UpdateSongs(ctx, songs) set genre %q, expected %q", actual.Genre, expected.Genre)
+			}
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		song := testdata.SimpleSong(t, db)
+		song.Genre = "Changed"
+		missing := model.Song{}
+		missing.UUID = uuid.New()
+		err := repo.UpdateSongs(context.TODO(), []model.Song{song, missing})
+		if !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("UpdateSongs(ctx, songs) returned an unexpected error: %s, expected ErrNotFound", err)
+		}
+		if actual, _ := repo.GetSong(context.TODO(), song.UUID); actual.Genre == "Changed" {
+			t.Errorf("UpdateSongs(ctx, songs) updated a song although another song was missing")
+		}
+	})
+}
+
 func Test_dbRepo_UpdateSong(t *testing.T) {
 	t.Parallel()
 
@@ -194,6 +318,185 @@ func Test_dbRepo_UpdateSong(t *testing.T) {
 	})
 }
 
+func Test_dbRepo_UpdateSongIfUnmodified(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+
+	t.Run("unmodified", func(t *testing.T) {
+		song := testdata.SimpleSong(t, db)
+		song.Title = "Changed"
+		if err := repo.UpdateSongIfUnmodified(context.TODO(), &song, song.UpdatedAt); err != nil {
+			t.Errorf("UpdateSongIfUnmodified(ctx, &song, version) returned an unexpected error: %s", err)
+		}
+	})
+
+	t.Run("modified", func(t *testing.T) {
+		song := testdata.SimpleSong(t, db)
+		version := song.UpdatedAt
+		first, second := song, song
+		first.Title = "First"
+		second.Title = "Second"
+		if err := repo.UpdateSongIfUnmodified(context.TODO(), &first, version); err != nil {
+			t.Fatalf("UpdateSongIfUnmodified(ctx, &first, version) returned an unexpected error: %s", err)
+		}
+		err := repo.UpdateSongIfUnmodified(context.TODO(), &second, version)
+		if !errors.Is(err, core.ErrPreconditionFailed) {
+			t.Errorf("UpdateSongIfUnmodified(ctx, &second, version) returned an unexpected error: %s, expected ErrPreconditionFailed", err)
+		}
+		actual, _ := repo.GetSong(context.TODO(), song.UUID)
+		if actual.Title != "First" {
+			t.Errorf("GetSong(ctx, %q) returned song.Title = %q, expected %q", song.UUID, actual.Title, "First")
+		}
+	})
+}
+
+func Test_dbRepo_DeleteSongIfUnmodified(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song := testdata.SimpleSong(t, db)
+	version := song.UpdatedAt
+
+	song.Title = "Changed"
+	if err := repo.UpdateSong(context.TODO(), &song); err != nil {
+		t.Fatalf("UpdateSong(ctx, &song) returned an unexpected error: %s", err)
+	}
+	err := repo.DeleteSongIfUnmodified(context.TODO(), song.UUID, version)
+	if !errors.Is(err, core.ErrPreconditionFailed) {
+		t.Errorf("DeleteSongIfUnmodified(ctx, %q, version) returned an unexpected error: %s, expected ErrPreconditionFailed", song.UUID, err)
+	}
+	if err = repo.DeleteSongIfUnmodified(context.TODO(), song.UUID, song.UpdatedAt); err != nil {
+		t.Errorf("DeleteSongIfUnmodified(ctx, %q, song.UpdatedAt) returned an unexpected error: %s", song.UUID, err)
+	}
+}
+
+func Test_dbRepo_MergeSongs(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+
+	t.Run("success", func(t *testing.T) {
+		keep := testdata.SimpleSong(t, db)
+		dup := testdata.SimpleSong(t, db)
+		testdata.Playlist(t, db, "Favorites", dup.UUID)
+		testdata.PlayStats(t, db, keep.UUID, 2)
+		testdata.PlayStats(t, db, dup.UUID, 3)
+		testdata.Highscore(t, db, dup.UUID, "Alice", model.GameDifficultyMedium, 7000)
+		testdata.Comment(t, db, dup.UUID, model.CommentKindRemark, "Check the gap")
+		testdata.ReviewState(t, db, dup.UUID, model.ReviewStateNeedsReview)
+		dup.Tags = []string{"Rock"}
+		if err := repo.UpdateSong(context.TODO(), &dup); err != nil {
+			t.Fatalf("UpdateSong(ctx, &song) returned an unexpected error: %s", err)
+		}
+
+		if err := repo.MergeSongs(context.TODO(), &keep, []uuid.UUID{dup.UUID}, true, keep.UpdatedAt); err != nil {
+			t.Fatalf("MergeSongs(ctx, &song, duplicates, true, version) returned an unexpected error: %s", err)
+		}
+		if _, err := repo.GetSong(context.TODO(), dup.UUID); !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("GetSong(ctx, %q) after MergeSongs() returned %v, expected ErrNotFound", dup.UUID, err)
+		}
+		var playlists, plays, scores int
+		err := db.QueryRow(context.TODO(), `SELECT
+		    (SELECT COUNT(*) FROM playlist_songs WHERE song_id = s.id),
+		    (SELECT COALESCE(SUM(times_played), 0) FROM song_plays WHERE song_id = s.id),
+		    (SELECT COUNT(*) FROM song_scores WHERE song_id = s.id)
+		FROM songs AS s WHERE s.uuid = $1`, keep.UUID).Scan(&playlists, &plays, &scores)
+		if err != nil {
+			t.Fatalf("could not query references: %s", err)
+		}
+		if playlists != 1 || plays != 5 || scores != 1 {
+			t.Errorf("MergeSongs() produced %d playlist entries, %d plays and %d highscores, expected 1, 5 and 1", playlists, plays, scores)
+		}
+		var comments int
+		var state string
+		err = db.QueryRow(context.TODO(), `SELECT
+		    (SELECT COUNT(*) FROM song_comments WHERE song_id = s.id),
+		    (SELECT COALESCE(MAX(state), '') FROM song_reviews WHERE song_id = s.id)
+		FROM songs AS s WHERE s.uuid = $1`, keep.UUID).Scan(&comments, &state)
+		if err != nil {
+			t.Fatalf("could not query review data: %s", err)
+		}
+		if comments != 1 || state != string(model.ReviewStateNeedsReview) {
+			t.Errorf("MergeSongs() produced %d comments and review state %q, expected 1 and %q", comments, state, model.ReviewStateNeedsReview)
+		}
+		if !slices.Equal(keep.Tags, []string{"Rock"}) {
+			t.Errorf("MergeSongs() produced tags %q, expected %q", keep.Tags, []string{"Rock"})
+		}
+		if actual, _ := repo.GetSong(context.TODO(), keep.UUID); !slices.Equal(actual.Tags, []string{"Rock"}) {
+			t.Errorf("GetSong(ctx, %q) after MergeSongs() returned tags %q, expected %q", keep.UUID, actual.Tags, []string{"Rock"})
+		}
+	})
+
+	t.Run("modified", func(t *testing.T) {
+		keep := testdata.SimpleSong(t, db)
+		dup := testdata.SimpleSong(t, db)
+		version := keep.UpdatedAt
+		if err := repo.UpdateSong(context.TODO(), &keep); err != nil {
+			t.Fatalf("UpdateSong(ctx, &song) returned an unexpected error: %s", err)
+		}
+		err := repo.MergeSongs(context.TODO(), &keep, []uuid.UUID{dup.UUID}, false, version)
+		if !errors.Is(err, core.ErrPreconditionFailed) {
+			t.Errorf("MergeSongs(ctx, &song, duplicates, false, version) returned an unexpected error: %s, expected ErrPreconditionFailed", err)
+		}
+		if actual, _ := repo.GetSong(context.TODO(), dup.UUID); actual.Deleted() {
+			t.Errorf("MergeSongs() moved the duplicate to the trash, expected no changes")
+		}
+	})
+}
+
+func Test_dbRepo_SongArtists(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	artistRepo := artist.NewDBRepository(nolog.Logger, db)
+	queen := testdata.Artist(t, db, "Queen", "The Queen")
+
+	song := model.Song{Artists: []string{"the queen", "David Bowie"}, FeaturedArtists: []string{"Freddie Mercury"}}
+	song.Title = "Under Pressure"
+	if err := repo.CreateSong(context.TODO(), &song); err != nil {
+		t.Fatalf("CreateSong(ctx, &song) returned an unexpected error: %s", err)
+	}
+	if !slices.Equal(song.Artists, []string{"Queen", "David Bowie"}) {
+		t.Errorf("CreateSong(ctx, &song) produced artists %q, expected %q", song.Artists, []string{"Queen", "David Bowie"})
+	}
+	queen, _ = artistRepo.GetArtist(context.TODO(), queen.UUID)
+	if queen.SongCount != 1 {
+		t.Errorf("GetArtist(ctx, %q) returned SongCount = %d, expected %d", queen.UUID, queen.SongCount, 1)
+	}
+
+	mercury, err := artistRepo.GetArtistByName(context.TODO(), "Freddie Mercury")
+	if err != nil {
+		t.Fatalf("GetArtistByName(ctx, %q) returned an unexpected error: %s", "Freddie Mercury", err)
+	}
+	if err = artistRepo.MergeArtists(context.TODO(), &queen, mercury.UUID); err != nil {
+		t.Fatalf("MergeArtists(ctx, &target, %q) returned an unexpected error: %s", mercury.UUID, err)
+	}
+	actual, err := repo.GetSong(context.TODO(), song.UUID)
+	if err != nil {
+		t.Fatalf("GetSong(ctx, %q) returned an unexpected error: %s", song.UUID, err)
+	}
+	if !slices.Equal(actual.Artists, []string{"Queen", "David Bowie"}) || len(actual.FeaturedArtists) != 0 {
+		t.Errorf("GetSong(ctx, %q) returned artists %q featuring %q after merge, expected %q", song.UUID, actual.Artists, actual.FeaturedArtists, []string{"Queen", "David Bowie"})
+	}
+	if !actual.UpdatedAt.After(song.UpdatedAt) {
+		t.Errorf("GetSong(ctx, %q) returned song.UpdatedAt = %s after merge, expected a time after %s", song.UUID, actual.UpdatedAt, song.UpdatedAt)
+	}
+
+	queen.Name = "QUEEN"
+	if err = artistRepo.UpdateArtist(context.TODO(), &queen); err != nil {
+		t.Fatalf("UpdateArtist(ctx, &artist) returned an unexpected error: %s", err)
+	}
+	actual, _ = repo.GetSong(context.TODO(), song.UUID)
+	if actual.Artists[0] != "QUEEN" {
+		t.Errorf("GetSong(ctx, %q) returned artist %q after rename, expected %q", song.UUID, actual.Artists[0], "QUEEN")
+	}
+}
+
 func Test_dbRepo_DeleteSong(t *testing.T) {
 	t.Parallel()
 
@@ -219,3 +522,98 @@ func Test_dbRepo_DeleteSong(t *testing.T) {
 		t.Errorf("DeleteSong(ctx, %q) [2nd time] = %t, _, expected %t", song.UUID, ok, false)
 	}
 }
+
+func Test_dbRepo_RestoreSong(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song := testdata.DeletedSong(t, db)
+
+	ok, err := repo.RestoreSong(context.TODO(), song.UUID)
+	if err != nil {
+		t.Errorf("RestoreSong(ctx, %q) returned an unexpected error: %s", song.UUID, err)
+		return
+	}
+	if !ok {
+		t.Errorf("RestoreSong(ctx, %q) = %t, _, expected %t", song.UUID, ok, true)
+	}
+	restored, err := repo.GetSong(context.TODO(), song.UUID)
+	if err != nil {
+		t.Errorf("GetSong(ctx, %q) returned an unexpected error: %s", song.UUID, err)
+		return
+	}
+	if restored.Deleted() {
+		t.Errorf("RestoreSong(ctx, %q) did not clear DeletedAt", song.UUID)
+	}
+
+	ok, err = repo.RestoreSong(context.TODO(), song.UUID)
+	if err != nil {
+		t.Errorf("RestoreSong(ctx, %q) [2nd time] returned an unexpected error: %s", song.UUID, err)
+		return
+	}
+	if ok {
+		t.Errorf("RestoreSong(ctx, %q) [2nd time] = %t, _, expected %t", song.UUID, ok, false)
+	}
+}
+
+func Test_dbRepo_PurgeSongs(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song := testdata.SimpleSong(t, db)
+	deleted := testdata.DeletedSong(t, db)
+	trashed := testdata.SimpleSong(t, db)
+	if _, err := repo.DeleteSong(context.TODO(), trashed.UUID); err != nil {
+		t.Fatalf("DeleteSong(ctx, %q) returned an unexpected error: %s", trashed.UUID, err)
+	}
+
+	n, err := repo.PurgeSongs(context.TODO(), 24*time.Hour)
+	if err != nil {
+		t.Errorf("PurgeSongs(ctx, %s) returned an unexpected error: %s", 24*time.Hour, err)
+		return
+	}
+	if n != 1 {
+		t.Errorf("PurgeSongs(ctx, %s) = %d, _, expected %d", 24*time.Hour, n, 1)
+	}
+	if _, err = repo.GetSong(context.TODO(), deleted.UUID); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("GetSong(ctx, %q) after PurgeSongs() returned %v, expected ErrNotFound", deleted.UUID, err)
+	}
+	for _, id := range []uuid.UUID{song.UUID, trashed.UUID} {
+		if _, err = repo.GetSong(context.TODO(), id); err != nil {
+			t.Errorf("GetSong(ctx, %q) after PurgeSongs() returned an unexpected error: %s", id, err)
+		}
+	}
+}
+
+func Test_dbRepo_PurgeSong(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	song := testdata.SimpleSong(t, db)
+	other := testdata.SimpleSong(t, db)
+
+	ok, err := repo.PurgeSong(context.TODO(), song.UUID)
+	if err != nil {
+		t.Errorf("PurgeSong(ctx, %q) returned an unexpected error: %s", song.UUID, err)
+		return
+	}
+	if !ok {
+		t.Errorf("PurgeSong(ctx, %q) = false, _, expected true", song.UUID)
+	}
+	if _, err = repo.GetSong(context.TODO(), song.UUID); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("GetSong(ctx, %q) after PurgeSong() returned %v, expected ErrNotFound", song.UUID, err)
+	}
+	if _, err = repo.GetSong(context.TODO(), other.UUID); err != nil {
+		t.Errorf("GetSong(ctx, %q) after PurgeSong() returned an unexpected error: %s", other.UUID, err)
+	}
+	ok, err = repo.PurgeSong(context.TODO(), song.UUID)
+	if err != nil {
+		t.Errorf("PurgeSong(ctx, %q) returned an unexpected error: %s", song.UUID, err)
+	}
+	if ok {
+		t.Errorf("PurgeSong(ctx, %q) = true, _, expected false", song.UUID)
+	}
+}
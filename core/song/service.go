@@ -2,48 +2,146 @@ package song
 
 import (
 	"context"
-	"fmt"
 	"mime"
+	"regexp"
 	"strings"
 
+	"github.com/Karaoke-Manager/karman/core/artist"
 	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/filename"
 	"github.com/Karaoke-Manager/karman/pkg/mediatype"
 )
 
+var (
+	// featuringPattern matches the separator between the main artists and the featured artists of a song,
+	// such as "feat.", "ft." or "featuring", optionally enclosed in parentheses.
+	featuringPattern = regexp.MustCompile(`(?i)\s+[(\[]?(?:feat\.?|ft\.?|featuring)\s+`)
+	// artistSeparatorPattern matches separators between multiple artists, such as "A, B", "A & B" or "A x B".
+	artistSeparatorPattern = regexp.MustCompile(`\s*,\s*|\s+[&x×]\s+`)
+)
+
 // service is the default Service implementation.
-type service struct{}
+type service struct {
+	artistRepo artist.Repository
+	naming     Naming
+}
 
 // NewService creates a new Service.
-func NewService() Service {
-	return &service{}
+// The artistRepo is used to recognize artists whose names contain separator characters.
+// The naming is used by Prepare. Nil templates fall back to DefaultNaming.
+func NewService(artistRepo artist.Repository, naming Naming) Service {
+	if naming.Folder == nil {
+		naming.Folder = DefaultNaming.Folder
+	}
+	if naming.File == nil {
+		naming.File = DefaultNaming.File
+	}
+	return &service{artistRepo, naming}
 }
 
-// ParseArtists splits song.Artist into song.Artists.
-func (s *service) ParseArtists(_ context.Context, song *model.Song) {
-	artists := strings.Split(song.Artist, ",")
-	for i, artist := range artists {
-		artists[i] = strings.TrimSpace(artist)
-	}
-	// TODO: Parse featured artists as well
-	song.Artists = artists
+// ParseArtists splits song.Artist into song.Artists and song.FeaturedArtists.
+// Featured artists are separated from the main artists by "feat.", "ft." or "featuring".
+// Multiple artists are separated by commas, "&" or "x", unless the complete name is a known artist.
+func (s *service) ParseArtists(ctx context.Context, song *model.Song) {
+	main, featured := song.Artist, ""
+	if loc := featuringPattern.FindStringIndex(main); loc != nil {
+		main, featured = main[:loc[0]], main[loc[1]:]
+		if strings.HasSuffix(featured, ")") || strings.HasSuffix(featured, "]") {
+			featured = featured[:len(featured)-1]
+		}
+	}
+	song.Artists = s.splitArtists(ctx, main)
+	song.FeaturedArtists = s.splitArtists(ctx, featured)
 }
 
-// Prepare sets song.Artist as well as file names for referenced files.
-func (s *service) Prepare(_ context.Context, song *model.Song) {
+// splitArtists splits the names of multiple artists in value.
+// Names of known artists (including aliases) are not split, even if they contain separators.
+// This also applies to known artists that are only a part of value, as in "Simon & Garfunkel, Queen".
+func (s *service) splitArtists(ctx context.Context, value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return []string{}
+	}
+	// segments contains the start and end indexes of the parts of value between separators.
+	seps := artistSeparatorPattern.FindAllStringIndex(value, -1)
+	segments := make([][2]int, 0, len(seps)+1)
+	start := 0
+	for _, sep := range seps {
+		segments = append(segments, [2]int{start, sep[0]})
+		start = sep[1]
+	}
+	segments = append(segments, [2]int{start, len(value)})
+
+	artists := make([]string, 0)
+	for i := 0; i < len(segments); i++ {
+		// Find the longest sequence of segments starting at i that forms the name of a known artist.
+		end := i
+		for j := len(segments) - 1; j > i; j-- {
+			if s.isKnownArtist(ctx, value[segments[i][0]:segments[j][1]]) {
+				end = j
+				break
+			}
+		}
+		if name := strings.TrimSpace(value[segments[i][0]:segments[end][1]]); name != "" {
+			artists = append(artists, name)
+		}
+		i = end
+	}
+	return artists
+}
+
+// isKnownArtist reports whether name is the name or an alias of a known artist.
+func (s *service) isKnownArtist(ctx context.Context, name string) bool {
+	_, err := s.artistRepo.GetArtistByName(ctx, strings.TrimSpace(name))
+	return err == nil
+}
+
+// Prepare sets song.Artist as well as the folder name and file names for referenced files
+// using the naming of s.
+func (s *service) Prepare(ctx context.Context, song *model.Song) {
+	s.PrepareWithNaming(ctx, song, s.naming)
+}
+
+// PrepareWithNaming sets song.Artist as well as the folder name and file names for referenced files.
+// The artist is rendered as the main artists followed by the featured artists, e.g. "A, B feat. C".
+// If multiple files of the song get the same name, the names are numbered
+// in the order TXT, audio, cover, video, background, lyrics.
+// Duets get a separate lyrics file for each player.
+func (s *service) PrepareWithNaming(_ context.Context, song *model.Song, naming Naming) {
+	if naming.Folder == nil {
+		naming.Folder = s.naming.Folder
+	}
+	if naming.File == nil {
+		naming.File = s.naming.File
+	}
 	song.Artist = strings.Join(song.Artists, ", ")
-	// TODO: Generate safe file names
-	song.TxtFileName = fmt.Sprintf("%s - %s.txt", song.Artist, song.Title)
+	if len(song.FeaturedArtists) > 0 {
+		song.Artist += " feat. " + strings.Join(song.FeaturedArtists, ", ")
+	}
+
+	values := templateValues(song)
+	song.FolderName = fileName(naming.Folder, values, "", "") + " (" + song.UUID.String() + ")"
+	var names filename.Set
+	song.TxtFileName = names.Add(fileName(naming.File, values, "", ".txt"))
 	if song.AudioFile != nil {
-		song.AudioFileName = fmt.Sprintf("%s - %s [AUDIO]%s", song.Artist, song.Title, s.extensionForType(song.AudioFile.Type))
+		song.AudioFileName = names.Add(fileName(naming.File, values, "AUDIO", s.extensionForType(song.AudioFile.Type)))
 	}
 	if song.CoverFile != nil {
-		song.CoverFileName = fmt.Sprintf("%s - %s [CO]%s", song.Artist, song.Title, s.extensionForType(song.CoverFile.Type))
+		song.CoverFileName = names.Add(fileName(naming.File, values, "CO", s.extensionForType(song.CoverFile.Type)))
 	}
 	if song.VideoFile != nil {
-		song.VideoFileName = fmt.Sprintf("%s - %s [VIDEO]%s", song.Artist, song.Title, s.extensionForType(song.VideoFile.Type))
+		song.VideoFileName = names.Add(fileName(naming.File, values, "VIDEO", s.extensionForType(song.VideoFile.Type)))
 	}
 	if song.BackgroundFile != nil {
-		song.BackgroundFileName = fmt.Sprintf("%s - %s [BG]%s", song.Artist, song.Title, s.extensionForType(song.BackgroundFile.Type))
+		song.BackgroundFileName = names.Add(fileName(naming.File, values, "BG", s.extensionForType(song.BackgroundFile.Type)))
+	}
+	if song.IsDuet() {
+		song.LyricsFileNames = []string{
+			names.Add(fileName(naming.File, values, "P1", ".lrc")),
+			names.Add(fileName(naming.File, values, "P2", ".lrc")),
+		}
+	} else {
+		song.LyricsFileNames = []string{names.Add(fileName(naming.File, values, "", ".lrc"))}
 	}
 }
 
@@ -6,44 +6,75 @@ import (
 	"testing"
 
 	"codello.dev/ultrastar"
+	"github.com/google/uuid"
 
+	"github.com/Karaoke-Manager/karman/core/artist"
 	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/filename"
+	"github.com/Karaoke-Manager/karman/pkg/mediatype"
 )
 
 func Test_service_ParseArtists(t *testing.T) {
 	t.Parallel()
 
-	svc := NewService()
-	song := model.Song{
-		Song: ultrastar.Song{
-			Artist: "Foo, Bar",
-		},
+	artistRepo := artist.NewFakeRepository()
+	if err := artistRepo.CreateArtist(context.TODO(), &model.Artist{Name: "Earth, Wind & Fire"}); err != nil {
+		t.Fatalf("CreateArtist() returned an unexpected error: %s", err)
 	}
-
-	svc.ParseArtists(context.TODO(), &song)
-	if !slices.Equal(song.Artists, []string{"Foo", "Bar"}) {
-		t.Errorf("ParseArtists(%q) = %v, expected %v", song.Artist, song.Artists, []string{"Foo", "Bar"})
+	if err := artistRepo.CreateArtist(context.TODO(), &model.Artist{Name: "Simon & Garfunkel"}); err != nil {
+		t.Fatalf("CreateArtist() returned an unexpected error: %s", err)
+	}
+	svc := NewService(artistRepo, DefaultNaming)
+	cases := map[string]struct {
+		artist   string
+		main     []string
+		featured []string
+	}{
+		"single":          {"Queen", []string{"Queen"}, []string{}},
+		"comma":           {"Foo, Bar", []string{"Foo", "Bar"}, []string{}},
+		"ampersand":       {"Foo & Bar", []string{"Foo", "Bar"}, []string{}},
+		"x":               {"Foo x Bar", []string{"Foo", "Bar"}, []string{}},
+		"feat":            {"Foo feat. Bar", []string{"Foo"}, []string{"Bar"}},
+		"ft":              {"Foo ft. Bar", []string{"Foo"}, []string{"Bar"}},
+		"featuring":       {"Foo Featuring Bar & Baz", []string{"Foo"}, []string{"Bar", "Baz"}},
+		"parentheses":     {"Foo (feat. Bar)", []string{"Foo"}, []string{"Bar"}},
+		"no separator":    {"Daft Punk", []string{"Daft Punk"}, []string{}},
+		"known artist":    {"Earth, Wind & Fire", []string{"Earth, Wind & Fire"}, []string{}},
+		"known and feat.": {"Earth, Wind & Fire feat. Foo", []string{"Earth, Wind & Fire"}, []string{"Foo"}},
+		"known in list":   {"Simon & Garfunkel, Queen", []string{"Simon & Garfunkel", "Queen"}, []string{}},
+		"known at end":    {"Queen & Earth, Wind & Fire", []string{"Queen", "Earth, Wind & Fire"}, []string{}},
+		"empty":           {"", []string{}, []string{}},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			song := model.Song{Song: ultrastar.Song{Artist: c.artist}}
+			svc.ParseArtists(context.TODO(), &song)
+			if !slices.Equal(song.Artists, c.main) {
+				t.Errorf("ParseArtists(%q) produced song.Artists = %q, expected %q", c.artist, song.Artists, c.main)
+			}
+			if !slices.Equal(song.FeaturedArtists, c.featured) {
+				t.Errorf("ParseArtists(%q) produced song.FeaturedArtists = %q, expected %q", c.artist, song.FeaturedArtists, c.featured)
+			}
+		})
 	}
 }
 
 func Test_service_Prepare(t *testing.T) {
 	t.Parallel()
 
-	svc := NewService()
+	svc := NewService(artist.NewFakeRepository(), DefaultNaming)
 	song := model.Song{
 		Song: ultrastar.Song{
 			Artist: "Queen",
 		},
-		Artists:   []string{"Foo", "Bar"},
-		AudioFile: &model.File{},
+		Artists:         []string{"Foo", "Bar"},
+		FeaturedArtists: []string{"Baz"},
+		AudioFile:       &model.File{},
 	}
 
 	svc.Prepare(context.TODO(), &song)
-	if len(song.Artist) == 0 {
-		t.Errorf("Prepare() did not set song.Artist, expected non-zero value")
-	}
-	if song.Artist == "Queen" {
-		t.Errorf("Prepare() procuded song.Artist = %q, expected different value", song.Artist)
+	if song.Artist != "Foo, Bar feat. Baz" {
+		t.Errorf("Prepare() produced song.Artist = %q, expected %q", song.Artist, "Foo, Bar feat. Baz")
 	}
 	if song.TxtFileName == "" {
 		t.Errorf("Prepare() did not set song.TxtFileName, expected non-zero value")
@@ -55,3 +86,46 @@ func Test_service_Prepare(t *testing.T) {
 		t.Errorf("Prepare() set song.VideoFileName = %q, expected empty string", song.VideoFileName)
 	}
 }
+
+func Test_service_PrepareWithNaming(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(artist.NewFakeRepository(), DefaultNaming)
+	song := model.Song{
+		Model:     model.Model{UUID: uuid.New()},
+		Song:      ultrastar.Song{Title: "What? / Why: ..."},
+		Artists:   []string{"AC/DC"},
+		AudioFile: &model.File{Type: mediatype.AudioMPEG},
+		CoverFile: &model.File{Type: mediatype.ImageJPEG},
+	}
+
+	t.Run("default", func(t *testing.T) {
+		svc.PrepareWithNaming(context.TODO(), &song, Naming{})
+		if expected := "AC_DC - What_ _ Why_ (" + song.UUID.String() + ")"; song.FolderName != expected {
+			t.Errorf("PrepareWithNaming() produced song.FolderName = %q, expected %q", song.FolderName, expected)
+		}
+		if expected := "AC_DC - What_ _ Why_ ....txt"; song.TxtFileName != expected {
+			t.Errorf("PrepareWithNaming() produced song.TxtFileName = %q, expected %q", song.TxtFileName, expected)
+		}
+		if expected := "AC_DC - What_ _ Why_ ... [AUDIO].mp3"; song.AudioFileName != expected {
+			t.Errorf("PrepareWithNaming() produced song.AudioFileName = %q, expected %q", song.AudioFileName, expected)
+		}
+	})
+	t.Run("collisions", func(t *testing.T) {
+		svc.PrepareWithNaming(context.TODO(), &song, Naming{File: filename.MustParse("{artist}")})
+		names := []string{song.TxtFileName, song.AudioFileName, song.CoverFileName}
+		expected := []string{"AC_DC", "AC_DC (2)", "AC_DC (3)"}
+		if !slices.Equal(names, expected) {
+			t.Errorf("PrepareWithNaming() produced file names %q, expected %q", names, expected)
+		}
+	})
+}
+
+func TestParseTemplate(t *testing.T) {
+	if _, err := ParseTemplate("{artist} - {title}< [{kind}]>{ext}"); err != nil {
+		t.Errorf("ParseTemplate() returned an unexpected error: %s", err)
+	}
+	if _, err := ParseTemplate("{artist} - {album}"); err == nil {
+		t.Errorf("ParseTemplate() with an unknown placeholder did not return an error, but an error was expected")
+	}
+}
@@ -0,0 +1,195 @@
+package song
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// pitchNames are the names of the pitches within an octave, starting at C.
+var pitchNames = [12]string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+
+// PitchName returns the scientific pitch notation of p, such as "C4" or "F#3".
+// In UltraStar songs, pitch 0 is the middle C (C4).
+func PitchName(p ultrastar.Pitch) string {
+	octave, idx := int(p)/12, int(p)%12
+	if idx < 0 {
+		octave, idx = octave-1, idx+12
+	}
+	return pitchNames[idx] + strconv.Itoa(octave+4)
+}
+
+// ParsePitch parses a pitch in scientific pitch notation, such as "C4", "F#3" or "Bb2".
+// Alternatively the pitch can be given as an UltraStar pitch number, where 0 is the middle C.
+func ParsePitch(s string) (ultrastar.Pitch, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return ultrastar.Pitch(n), nil
+	}
+	if len(s) < 2 {
+		return 0, fmt.Errorf("invalid pitch %q", s)
+	}
+	idx := strings.Index("C D EF G A B", strings.ToUpper(s[:1]))
+	if idx < 0 {
+		return 0, fmt.Errorf("invalid pitch %q", s)
+	}
+	rest := s[1:]
+	switch rest[0] {
+	case '#':
+		idx, rest = idx+1, rest[1:]
+	case 'b':
+		idx, rest = idx-1, rest[1:]
+	}
+	octave, err := strconv.Atoi(rest)
+	if err != nil {
+		return 0, fmt.Errorf("invalid pitch %q", s)
+	}
+	return ultrastar.Pitch((octave-4)*12 + idx), nil
+}
+
+const (
+	// easyNoteDensity and hardNoteDensity are the thresholds in notes per second
+	// at which the note density of a track increases its difficulty.
+	easyNoteDensity = 1.5
+	hardNoteDensity = 2.5
+	// easyRange and hardRange are the thresholds in semitones
+	// at which the vocal range of a track increases its difficulty.
+	easyRange = 12
+	hardRange = 19
+)
+
+// CalculateStats calculates statistics about the notes of song.
+// The Length of the stats requires a valid BPM, otherwise only the song's gap or end time is considered.
+func CalculateStats(song model.Song) model.SongStats {
+	stats := model.SongStats{P1: trackStats(song.NotesP1, song.BPM)}
+	if song.IsDuet() {
+		p2 := trackStats(song.NotesP2, song.BPM)
+		stats.P2 = &p2
+	}
+
+	stats.Difficulty = difficulty(stats.P1)
+	if stats.P2 != nil {
+		stats.Difficulty = max(stats.Difficulty, difficulty(*stats.P2))
+	}
+
+	if song.End != 0 {
+		stats.Length = song.End
+	} else {
+		var end ultrastar.Beat
+		for _, notes := range []ultrastar.Notes{song.NotesP1, song.NotesP2} {
+			if len(notes) > 0 {
+				last := notes[len(notes)-1]
+				end = max(end, last.Start+last.Duration)
+			}
+		}
+		stats.Length = song.Gap
+		if song.BPM.IsValid() {
+			stats.Length += song.BPM.Duration(end)
+		}
+	}
+	return stats
+}
+
+// BackfillStats calculates and saves the statistics of songs in repo that have no statistics yet.
+// Statistics are calculated whenever a song is saved,
+// so this is only necessary for songs that have not been saved since statistics were introduced.
+// If all is true, the statistics of all songs are recalculated.
+// Songs in the trash are included.
+// The first return value is the number of updated songs.
+func BackfillStats(ctx context.Context, repo Repository, all bool) (int, error) {
+	songs, _, err := repo.FindSongs(ctx, Filter{}, -1, 0)
+	if err != nil {
+		return 0, err
+	}
+	deleted, _, err := repo.FindDeletedSongs(ctx, -1, 0)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, sng := range append(songs, deleted...) {
+		if !all && sng.Stats != (model.SongStats{}) {
+			continue
+		}
+		if stats := CalculateStats(sng); !all && stats == (model.SongStats{}) {
+			// The song has no notes, so there is nothing to backfill.
+			continue
+		}
+		if err = repo.UpdateSong(ctx, &sng); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// trackStats calculates the statistics of a single track.
+func trackStats(notes ultrastar.Notes, bpm ultrastar.BPM) model.TrackStats {
+	var stats model.TrackStats
+	pitches := make([]ultrastar.Pitch, 0, len(notes))
+	var golden, freestyle int
+	var first, last ultrastar.Beat
+	for _, n := range notes {
+		if !validNoteType(n.Type) {
+			continue
+		}
+		if stats.Notes == 0 {
+			first = n.Start
+		}
+		stats.Notes++
+		last = max(last, n.Start+n.Duration)
+		switch n.Type {
+		case ultrastar.NoteTypeGolden, ultrastar.NoteTypeGoldenRap:
+			golden++
+		case ultrastar.NoteTypeFreestyle:
+			freestyle++
+		}
+		if n.Type == ultrastar.NoteTypeRegular || n.Type == ultrastar.NoteTypeGolden {
+			pitches = append(pitches, n.Pitch)
+		}
+	}
+	if stats.Notes == 0 {
+		return stats
+	}
+	stats.GoldenRatio = float64(golden) / float64(stats.Notes)
+	stats.FreestyleRatio = float64(freestyle) / float64(stats.Notes)
+	if bpm.IsValid() && last > first {
+		stats.NoteDensity = float64(stats.Notes) / (bpm.Duration(last - first).Seconds())
+	}
+	stats.Pitched = len(pitches)
+	if stats.Pitched > 0 {
+		slices.Sort(pitches)
+		stats.LowestPitch = pitches[0]
+		stats.HighestPitch = pitches[len(pitches)-1]
+		stats.MedianPitch = pitches[len(pitches)/2]
+	}
+	return stats
+}
+
+// difficulty derives a difficulty rating from the statistics of a track.
+// The rating increases with the note density and the vocal range of the track.
+func difficulty(stats model.TrackStats) model.Difficulty {
+	if stats.Notes == 0 {
+		return model.DifficultyUnknown
+	}
+	d := model.DifficultyEasiest
+	switch {
+	case stats.NoteDensity >= hardNoteDensity:
+		d += 2
+	case stats.NoteDensity >= easyNoteDensity:
+		d++
+	}
+	if stats.Pitched > 0 {
+		switch r := stats.HighestPitch - stats.LowestPitch; {
+		case r >= hardRange:
+			d += 2
+		case r >= easyRange:
+			d++
+		}
+	}
+	return d
+}
@@ -0,0 +1,195 @@
+package song
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func TestPitchName(t *testing.T) {
+	t.Parallel()
+
+	cases := map[ultrastar.Pitch]string{
+		0:   "C4",
+		1:   "C#4",
+		11:  "B4",
+		12:  "C5",
+		-1:  "B3",
+		-12: "C3",
+		-13: "B2",
+	}
+	for p, expected := range cases {
+		if actual := PitchName(p); actual != expected {
+			t.Errorf("PitchName(%d) = %q, expected %q", p, actual, expected)
+		}
+	}
+}
+
+func TestParsePitch(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		expected ultrastar.Pitch
+		ok       bool
+	}{
+		"C4":  {0, true},
+		"c4":  {0, true},
+		"F#3": {-6, true},
+		"Bb2": {-14, true},
+		"A4":  {9, true},
+		"-5":  {-5, true},
+		"H4":  {0, false},
+		"C":   {0, false},
+		"C#x": {0, false},
+		"":    {0, false},
+	}
+	for s, c := range cases {
+		actual, err := ParsePitch(s)
+		if c.ok && err != nil {
+			t.Errorf("ParsePitch(%q) returned an unexpected error: %s", s, err)
+		} else if !c.ok && err == nil {
+			t.Errorf("ParsePitch(%q) did not return an error, expected an error", s)
+		} else if c.ok && actual != c.expected {
+			t.Errorf("ParsePitch(%q) = %d, expected %d", s, actual, c.expected)
+		}
+	}
+}
+
+func TestParsePitchRange(t *testing.T) {
+	t.Parallel()
+
+	r, err := ParsePitchRange("C3..G4")
+	if err != nil {
+		t.Fatalf("ParsePitchRange(%q) returned an unexpected error: %s", "C3..G4", err)
+	}
+	if r.Lowest != -12 || r.Highest != 7 {
+		t.Errorf("ParsePitchRange(%q) = %+v, expected {Lowest:-12 Highest:7}", "C3..G4", r)
+	}
+	for _, s := range []string{"C3", "G4..C3", "C3..X"} {
+		if _, err = ParsePitchRange(s); err == nil {
+			t.Errorf("ParsePitchRange(%q) did not return an error, expected an error", s)
+		}
+	}
+}
+
+func TestCalculateStats(t *testing.T) {
+	t.Parallel()
+
+	song := model.Song{Song: ultrastar.Song{BPM: 60, Gap: time.Second, NotesP1: ultrastar.Notes{
+		{Type: ultrastar.NoteTypeRegular, Start: 0, Duration: 1, Pitch: 0},
+		{Type: ultrastar.NoteTypeGolden, Start: 1, Duration: 1, Pitch: 4},
+		{Type: ultrastar.NoteTypeLineBreak, Start: 2},
+		{Type: ultrastar.NoteTypeFreestyle, Start: 3, Duration: 1, Pitch: 30},
+		{Type: ultrastar.NoteTypeRegular, Start: 4, Duration: 4, Pitch: 2},
+	}}}
+	stats := CalculateStats(song)
+	if stats.P2 != nil {
+		t.Errorf("CalculateStats(song) returned stats for P2, expected none")
+	}
+	p1 := stats.P1
+	if p1.Notes != 4 || p1.Pitched != 3 {
+		t.Errorf("CalculateStats(song) counted %d notes and %d pitched notes, expected 4 and 3", p1.Notes, p1.Pitched)
+	}
+	if p1.LowestPitch != 0 || p1.HighestPitch != 4 || p1.MedianPitch != 2 {
+		t.Errorf("CalculateStats(song) returned pitches %d/%d/%d, expected 0/4/2", p1.LowestPitch, p1.HighestPitch, p1.MedianPitch)
+	}
+	if p1.GoldenRatio != 0.25 || p1.FreestyleRatio != 0.25 {
+		t.Errorf("CalculateStats(song) returned golden ratio %f and freestyle ratio %f, expected 0.25 each", p1.GoldenRatio, p1.FreestyleRatio)
+	}
+	if p1.NoteDensity != 0.5 {
+		t.Errorf("CalculateStats(song) returned note density %f, expected %f", p1.NoteDensity, 0.5)
+	}
+	if stats.Length != 9*time.Second {
+		t.Errorf("CalculateStats(song) returned length %s, expected %s", stats.Length, 9*time.Second)
+	}
+	if stats.Difficulty != model.DifficultyEasiest {
+		t.Errorf("CalculateStats(song) returned difficulty %d, expected %d", stats.Difficulty, model.DifficultyEasiest)
+	}
+}
+
+func TestCalculateStats_Difficulty(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]struct {
+		stats    model.TrackStats
+		expected model.Difficulty
+	}{
+		"empty":        {model.TrackStats{}, model.DifficultyUnknown},
+		"easy":         {model.TrackStats{Notes: 10, Pitched: 10, NoteDensity: 1, HighestPitch: 5}, 1},
+		"dense":        {model.TrackStats{Notes: 10, Pitched: 10, NoteDensity: 2, HighestPitch: 5}, 2},
+		"wide":         {model.TrackStats{Notes: 10, Pitched: 10, NoteDensity: 1, LowestPitch: -10, HighestPitch: 10}, 3},
+		"hardest":      {model.TrackStats{Notes: 10, Pitched: 10, NoteDensity: 3, LowestPitch: -10, HighestPitch: 10}, model.DifficultyHardest},
+		"unpitched":    {model.TrackStats{Notes: 10, NoteDensity: 3}, 3},
+		"medium range": {model.TrackStats{Notes: 10, Pitched: 10, NoteDensity: 1, LowestPitch: 0, HighestPitch: 12}, 2},
+	}
+	for name, c := range cases {
+		if actual := difficulty(c.stats); actual != c.expected {
+			t.Errorf("difficulty(%s) = %d, expected %d", name, actual, c.expected)
+		}
+	}
+}
+
+func TestBackfillStats(t *testing.T) {
+	t.Parallel()
+
+	repo := NewFakeRepository()
+	song := model.Song{Song: ultrastar.Song{BPM: 60, NotesP1: ultrastar.Notes{
+		{Type: ultrastar.NoteTypeRegular, Start: 0, Duration: 1, Pitch: 0},
+	}}}
+	_ = repo.CreateSong(context.TODO(), &song)
+	// simulate a song that was created before statistics were introduced
+	song.Stats = model.SongStats{}
+	repo.(*fakeRepo).songs[song.UUID] = song
+	empty := model.Song{}
+	_ = repo.CreateSong(context.TODO(), &empty)
+
+	count, err := BackfillStats(context.TODO(), repo, false)
+	if err != nil {
+		t.Fatalf("BackfillStats(ctx, repo, false) returned an unexpected error: %s", err)
+	}
+	if count != 1 {
+		t.Errorf("BackfillStats(ctx, repo, false) = %d, _, expected %d", count, 1)
+	}
+	actual, _ := repo.GetSong(context.TODO(), song.UUID)
+	if actual.Stats.P1.Notes != 1 {
+		t.Errorf("BackfillStats(ctx, repo, false) set %d notes in the stats, expected %d", actual.Stats.P1.Notes, 1)
+	}
+
+	if count, _ = BackfillStats(context.TODO(), repo, true); count != 2 {
+		t.Errorf("BackfillStats(ctx, repo, true) = %d, _, expected %d", count, 2)
+	}
+}
+
+func TestFilter_Matches(t *testing.T) {
+	t.Parallel()
+
+	narrow := model.TrackStats{Notes: 5, Pitched: 5, LowestPitch: 0, HighestPitch: 5}
+	wide := model.TrackStats{Notes: 5, Pitched: 5, LowestPitch: -12, HighestPitch: 12}
+	cases := map[string]struct {
+		filter   Filter
+		stats    model.SongStats
+		expected bool
+	}{
+		"zero filter":       {Filter{}, model.SongStats{}, true},
+		"range fits":        {Filter{Range: &PitchRange{-2, 7}}, model.SongStats{P1: narrow}, true},
+		"range too small":   {Filter{Range: &PitchRange{-2, 7}}, model.SongStats{P1: wide}, false},
+		"duet fits":         {Filter{Range: &PitchRange{-2, 7}}, model.SongStats{P1: wide, P2: &narrow}, true},
+		"no pitched notes":  {Filter{Range: &PitchRange{-2, 7}}, model.SongStats{}, false},
+		"difficulty fits":   {Filter{MinDifficulty: 2, MaxDifficulty: 3}, model.SongStats{Difficulty: 3}, true},
+		"difficulty low":    {Filter{MinDifficulty: 2}, model.SongStats{Difficulty: 1}, false},
+		"difficulty high":   {Filter{MaxDifficulty: 3}, model.SongStats{Difficulty: 4}, false},
+		"unknown excluded":  {Filter{MinDifficulty: 1}, model.SongStats{}, false},
+		"unknown unbounded": {Filter{MaxDifficulty: 3}, model.SongStats{}, true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if actual := c.filter.Matches(model.Song{Stats: c.stats}); actual != c.expected {
+				t.Errorf("Matches() = %t, expected %t", actual, c.expected)
+			}
+		})
+	}
+}
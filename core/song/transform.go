@@ -0,0 +1,229 @@
+package song
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// relativeTag is the custom tag that marks songs whose beats are relative to the preceding line break.
+const relativeTag = "RELATIVE"
+
+// A TransformOp identifies the kind of change of a Transform.
+type TransformOp string
+
+const (
+	// ShiftGap adds Transform.Duration to the gap of a song.
+	// All notes are moved in time relative to the audio.
+	ShiftGap TransformOp = "shiftGap"
+	// DoubleBPM doubles the BPM of a song and doubles all beats.
+	// The timing of the notes does not change.
+	DoubleBPM TransformOp = "doubleBPM"
+	// HalveBPM halves the BPM of a song and halves all beats.
+	// The timing of the notes does not change.
+	// If any beat or duration is odd, the transform fails.
+	HalveBPM TransformOp = "halveBPM"
+	// ToAbsolute converts a song in relative mode into a song with absolute beats.
+	// Songs that are not in relative mode are not changed.
+	ToAbsolute TransformOp = "toAbsolute"
+	// ShiftNotes adds Transform.Beats to the start of all notes, line breaks and medley beats.
+	ShiftNotes TransformOp = "shiftNotes"
+	// SetVideoGap sets the video gap of a song to Transform.Duration.
+	SetVideoGap TransformOp = "setVideoGap"
+)
+
+// A Transform is an operation that changes the timing of a song.
+// Depending on Op, only some of the fields are used.
+type Transform struct {
+	Op       TransformOp
+	Duration time.Duration  // ShiftGap, SetVideoGap
+	Beats    ultrastar.Beat // ShiftNotes
+}
+
+// ParseTransform parses the textual representation of a transform.
+// A transform consists of its operation, optionally followed by "=" and a value.
+// The value of ShiftGap and SetVideoGap is a duration like "-150ms",
+// the value of ShiftNotes is a number of beats.
+func ParseTransform(text string) (Transform, error) {
+	op, value, hasValue := strings.Cut(strings.TrimSpace(text), "=")
+	t := Transform{Op: TransformOp(op)}
+	var err error
+	switch t.Op {
+	case ShiftGap, SetVideoGap:
+		if !hasValue {
+			return t, fmt.Errorf("transform %s requires a duration", op)
+		}
+		t.Duration, err = time.ParseDuration(value)
+	case ShiftNotes:
+		if !hasValue {
+			return t, fmt.Errorf("transform %s requires a number of beats", op)
+		}
+		var beats int
+		beats, err = strconv.Atoi(value)
+		t.Beats = ultrastar.Beat(beats)
+	case DoubleBPM, HalveBPM, ToAbsolute:
+		if hasValue {
+			return t, fmt.Errorf("transform %s does not accept a value", op)
+		}
+	default:
+		return t, fmt.Errorf("unknown transform %q", op)
+	}
+	if err != nil {
+		return t, fmt.Errorf("invalid value for transform %s: %w", op, err)
+	}
+	return t, nil
+}
+
+// String returns the textual representation of t as accepted by ParseTransform.
+func (t Transform) String() string {
+	switch t.Op {
+	case ShiftGap, SetVideoGap:
+		return string(t.Op) + "=" + t.Duration.String()
+	case ShiftNotes:
+		return string(t.Op) + "=" + strconv.Itoa(int(t.Beats))
+	default:
+		return string(t.Op)
+	}
+}
+
+// TransformError indicates that a list of Transform values could not be applied to a song.
+type TransformError struct {
+	// Transform is the index of the transform that could not be applied.
+	Transform int
+	// Message describes the error.
+	Message string
+}
+
+// Error implements the error interface.
+func (e *TransformError) Error() string {
+	return fmt.Sprintf("transform %d: %s", e.Transform, e.Message)
+}
+
+var (
+	errInvalidBPM   = errors.New("song has no valid BPM")
+	errOddBeat      = errors.New("cannot halve BPM because a beat or duration is odd")
+	errNegativeBeat = errors.New("notes cannot start before beat 0")
+)
+
+// Transform applies ts to song in order.
+// If a transform cannot be applied, a *TransformError is returned and song is not modified.
+func (s *service) Transform(_ context.Context, song *model.Song, ts []Transform) error {
+	result := *song
+	result.NotesP1 = slices.Clone(song.NotesP1)
+	result.NotesP2 = slices.Clone(song.NotesP2)
+	result.CustomTags = maps.Clone(song.CustomTags)
+	for i, t := range ts {
+		if err := applyTransform(&result, t); err != nil {
+			return &TransformError{Transform: i, Message: err.Error()}
+		}
+	}
+	*song = result
+	return nil
+}
+
+// applyTransform applies t to song.
+// If t cannot be applied, song may be partially modified.
+func applyTransform(song *model.Song, t Transform) error {
+	switch t.Op {
+	case ShiftGap:
+		song.Gap += t.Duration
+	case SetVideoGap:
+		song.VideoGap = t.Duration
+	case DoubleBPM:
+		if !song.BPM.IsValid() {
+			return errInvalidBPM
+		}
+		song.BPM *= 2
+		return mapBeats(song, func(b ultrastar.Beat) (ultrastar.Beat, error) { return b * 2, nil }, true)
+	case HalveBPM:
+		if !song.BPM.IsValid() {
+			return errInvalidBPM
+		}
+		song.BPM /= 2
+		return mapBeats(song, func(b ultrastar.Beat) (ultrastar.Beat, error) {
+			if b%2 != 0 {
+				return 0, errOddBeat
+			}
+			return b / 2, nil
+		}, true)
+	case ShiftNotes:
+		return mapBeats(song, func(b ultrastar.Beat) (ultrastar.Beat, error) {
+			if b+t.Beats < 0 {
+				return 0, errNegativeBeat
+			}
+			return b + t.Beats, nil
+		}, false)
+	case ToAbsolute:
+		for key, value := range song.CustomTags {
+			if strings.EqualFold(key, relativeTag) {
+				delete(song.CustomTags, key)
+				if strings.EqualFold(value, "yes") {
+					song.NotesP1 = absoluteNotes(song.NotesP1)
+					song.NotesP2 = absoluteNotes(song.NotesP2)
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("unknown transform %q", t.Op)
+	}
+	return nil
+}
+
+// mapBeats replaces the start beats of all notes and the medley beats of song with the result of f.
+// If durations is true, note durations are mapped as well.
+func mapBeats(song *model.Song, f func(ultrastar.Beat) (ultrastar.Beat, error), durations bool) error {
+	var err error
+	for _, notes := range []ultrastar.Notes{song.NotesP1, song.NotesP2} {
+		for i := range notes {
+			if notes[i].Start, err = f(notes[i].Start); err != nil {
+				return err
+			}
+			if durations && notes[i].Type != ultrastar.NoteTypeLineBreak {
+				if notes[i].Duration, err = f(notes[i].Duration); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if song.MedleyStartBeat == 0 && song.MedleyEndBeat == 0 {
+		return nil
+	}
+	if song.MedleyStartBeat, err = f(song.MedleyStartBeat); err != nil {
+		return err
+	}
+	song.MedleyEndBeat, err = f(song.MedleyEndBeat)
+	return err
+}
+
+// absoluteNotes converts notes in relative mode into absolute notes.
+// In relative mode the beats of a line are relative to the line break preceding it,
+// which is itself relative to the previous line break.
+// A line break may specify the offset of the next line as a second value (as in "- 12 14").
+// This value is stored in the Duration of the line break.
+// If it is omitted, the next line is relative to the line break itself.
+// The notes are modified in place.
+func absoluteNotes(notes ultrastar.Notes) ultrastar.Notes {
+	var offset ultrastar.Beat
+	for i := range notes {
+		start := notes[i].Start + offset
+		if notes[i].Type == ultrastar.NoteTypeLineBreak {
+			if notes[i].Duration != 0 {
+				offset += notes[i].Duration
+			} else {
+				offset = start
+			}
+			notes[i].Duration = 0
+		}
+		notes[i].Start = start
+	}
+	return notes
+}
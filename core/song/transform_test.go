@@ -0,0 +1,159 @@
+package song
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"codello.dev/ultrastar"
+
+	"github.com/Karaoke-Manager/karman/core/artist"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func TestParseTransform(t *testing.T) {
+	cases := map[string]struct {
+		text     string
+		expected Transform
+		wantErr  bool
+	}{
+		"no value":       {"toAbsolute", Transform{Op: ToAbsolute}, false},
+		"duration":       {"shiftGap=-150ms", Transform{Op: ShiftGap, Duration: -150 * time.Millisecond}, false},
+		"beats":          {"shiftNotes=4", Transform{Op: ShiftNotes, Beats: 4}, false},
+		"unknown":        {"foo", Transform{}, true},
+		"missing value":  {"setVideoGap", Transform{}, true},
+		"invalid value":  {"shiftNotes=1.5", Transform{}, true},
+		"unwanted value": {"doubleBPM=2", Transform{}, true},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := ParseTransform(c.text)
+			if c.wantErr {
+				if err == nil {
+					t.Errorf("ParseTransform(%q) did not return an error, expected an error", c.text)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTransform(%q) returned an unexpected error: %s", c.text, err)
+			}
+			if actual != c.expected {
+				t.Errorf("ParseTransform(%q) = %v, expected %v", c.text, actual, c.expected)
+			}
+			if actual.String() != c.text {
+				t.Errorf("ParseTransform(%q).String() = %q, expected %q", c.text, actual.String(), c.text)
+			}
+		})
+	}
+}
+
+func Test_service_Transform(t *testing.T) {
+	t.Parallel()
+
+	svc := NewService(artist.NewFakeRepository(), DefaultNaming)
+	newSong := func() model.Song {
+		return model.Song{Song: ultrastar.Song{
+			BPM:             300,
+			Gap:             time.Second,
+			MedleyStartBeat: 2,
+			MedleyEndBeat:   12,
+			NotesP1:         testNotes(),
+		}}
+	}
+
+	t.Run("shift gap", func(t *testing.T) {
+		song := newSong()
+		if err := svc.Transform(context.TODO(), &song, []Transform{{Op: ShiftGap, Duration: -200 * time.Millisecond}}); err != nil {
+			t.Fatalf("Transform() returned an unexpected error: %s", err)
+		}
+		if song.Gap != 800*time.Millisecond {
+			t.Errorf("Transform() set Gap = %s, expected %s", song.Gap, 800*time.Millisecond)
+		}
+	})
+	t.Run("double and halve BPM", func(t *testing.T) {
+		song := newSong()
+		if err := svc.Transform(context.TODO(), &song, []Transform{{Op: DoubleBPM}}); err != nil {
+			t.Fatalf("Transform() returned an unexpected error: %s", err)
+		}
+		if song.BPM != 600 || song.NotesP1[3].Start != 16 || song.NotesP1[3].Duration != 8 || song.MedleyEndBeat != 24 {
+			t.Errorf("Transform() produced BPM %v with notes %v, expected doubled beats", song.BPM, song.NotesP1)
+		}
+		if song.BPM.Duration(song.NotesP1[3].Start) != newSong().BPM.Duration(newSong().NotesP1[3].Start) {
+			t.Errorf("Transform() changed the timing of notes, expected the timing to be preserved")
+		}
+		if err := svc.Transform(context.TODO(), &song, []Transform{{Op: HalveBPM}}); err != nil {
+			t.Fatalf("Transform() returned an unexpected error: %s", err)
+		}
+		if expected := newSong(); song.BPM != expected.BPM || !slices.Equal(song.NotesP1, expected.NotesP1) {
+			t.Errorf("Transform() produced BPM %v with notes %v, expected the original song", song.BPM, song.NotesP1)
+		}
+	})
+	t.Run("shift notes", func(t *testing.T) {
+		song := newSong()
+		if err := svc.Transform(context.TODO(), &song, []Transform{{Op: ShiftNotes, Beats: 3}}); err != nil {
+			t.Fatalf("Transform() returned an unexpected error: %s", err)
+		}
+		if song.NotesP1[0].Start != 3 || song.NotesP1[2].Start != 9 || song.MedleyStartBeat != 5 {
+			t.Errorf("Transform() produced notes %v, expected notes to be shifted by 3 beats", song.NotesP1)
+		}
+	})
+	t.Run("to absolute", func(t *testing.T) {
+		song := newSong()
+		song.CustomTags = map[string]string{"RELATIVE": "yes"}
+		song.NotesP1 = ultrastar.Notes{
+			{Type: ultrastar.NoteTypeRegular, Start: 0, Duration: 2, Text: "Hel"},
+			{Type: ultrastar.NoteTypeLineBreak, Start: 4},
+			{Type: ultrastar.NoteTypeRegular, Start: 2, Duration: 2, Text: "lo"},
+		}
+		if err := svc.Transform(context.TODO(), &song, []Transform{{Op: ToAbsolute}}); err != nil {
+			t.Fatalf("Transform() returned an unexpected error: %s", err)
+		}
+		if song.NotesP1[2].Start != 6 {
+			t.Errorf("Transform() set the start of the last note to %d, expected %d", song.NotesP1[2].Start, 6)
+		}
+		if _, ok := song.CustomTags["RELATIVE"]; ok {
+			t.Errorf("Transform() did not remove the RELATIVE tag")
+		}
+	})
+	t.Run("to absolute with offset", func(t *testing.T) {
+		// - 4 6 (the second value is the offset of the next line)
+		song := newSong()
+		song.CustomTags = map[string]string{"RELATIVE": "yes"}
+		song.NotesP1 = ultrastar.Notes{
+			{Type: ultrastar.NoteTypeRegular, Start: 0, Duration: 2, Text: "Hel"},
+			{Type: ultrastar.NoteTypeLineBreak, Start: 4, Duration: 6},
+			{Type: ultrastar.NoteTypeRegular, Start: 0, Duration: 2, Text: "lo"},
+			{Type: ultrastar.NoteTypeLineBreak, Start: 3},
+			{Type: ultrastar.NoteTypeRegular, Start: 1, Duration: 2, Text: " world"},
+		}
+		if err := svc.Transform(context.TODO(), &song, []Transform{{Op: ToAbsolute}}); err != nil {
+			t.Fatalf("Transform() returned an unexpected error: %s", err)
+		}
+		starts := make([]ultrastar.Beat, len(song.NotesP1))
+		for i, n := range song.NotesP1 {
+			starts[i] = n.Start
+		}
+		if expected := []ultrastar.Beat{0, 4, 6, 9, 10}; !slices.Equal(starts, expected) {
+			t.Errorf("Transform() produced start beats %v, expected %v", starts, expected)
+		}
+		if song.NotesP1[1].Duration != 0 {
+			t.Errorf("Transform() kept the offset %d of the line break, expected %d", song.NotesP1[1].Duration, 0)
+		}
+	})
+	t.Run("invalid", func(t *testing.T) {
+		song := newSong()
+		err := svc.Transform(context.TODO(), &song, []Transform{
+			{Op: SetVideoGap, Duration: time.Second},
+			{Op: ShiftNotes, Beats: -1},
+		})
+		var transformErr *TransformError
+		if !errors.As(err, &transformErr) || transformErr.Transform != 1 {
+			t.Fatalf("Transform() returned %v, expected a *TransformError for transform 1", err)
+		}
+		if song.VideoGap != 0 || !slices.Equal(song.NotesP1, testNotes()) {
+			t.Errorf("Transform() modified the song, expected no changes")
+		}
+	})
+}
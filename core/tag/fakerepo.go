@@ -0,0 +1,101 @@
+package tag
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// fakeRepo is a simple implementation of Repository that can be used for testing.
+// The fake repository does not know about songs, so the SongCount of tags is always 0.
+type fakeRepo struct {
+	// tags is the "database" of a fakeRepo.
+	tags map[uuid.UUID]model.Tag
+}
+
+// NewFakeRepository returns a new Repository implementation backed by an in-memory map.
+func NewFakeRepository() Repository {
+	return &fakeRepo{make(map[uuid.UUID]model.Tag)}
+}
+
+// CreateTag stores the tag and sets its UUID, CreatedAt, and UpdatedAt fields.
+func (r *fakeRepo) CreateTag(_ context.Context, tag *model.Tag) error {
+	prepareTag(tag)
+	if r.conflicts(uuid.Nil, tag.Name) {
+		return core.ErrConflict
+	}
+	tag.UUID = uuid.New()
+	tag.CreatedAt = time.Now()
+	tag.UpdatedAt = tag.CreatedAt
+	r.tags[tag.UUID] = *tag
+	return nil
+}
+
+// GetTag looks up the tag with the specified UUID.
+func (r *fakeRepo) GetTag(_ context.Context, id uuid.UUID) (model.Tag, error) {
+	tag, ok := r.tags[id]
+	if !ok {
+		return model.Tag{}, core.ErrNotFound
+	}
+	return tag, nil
+}
+
+// FindTags returns a list of tags ordered by name, limited by the specified pagination parameters.
+func (r *fakeRepo) FindTags(_ context.Context, limit int, offset int64) ([]model.Tag, int64, error) {
+	if limit < 0 {
+		limit = math.MaxInt
+	}
+	tags := make([]model.Tag, 0, len(r.tags))
+	for _, tag := range r.tags {
+		tags = append(tags, tag)
+	}
+	slices.SortFunc(tags, func(a, b model.Tag) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	total := int64(len(tags))
+	if offset > total {
+		offset = total
+	}
+	tags = tags[offset:]
+	return tags[:min(limit, len(tags))], total, nil
+}
+
+// UpdateTag updates the name and color of tag.
+func (r *fakeRepo) UpdateTag(_ context.Context, tag *model.Tag) error {
+	if _, ok := r.tags[tag.UUID]; !ok {
+		return core.ErrNotFound
+	}
+	prepareTag(tag)
+	if r.conflicts(tag.UUID, tag.Name) {
+		return core.ErrConflict
+	}
+	tag.UpdatedAt = time.Now()
+	r.tags[tag.UUID] = *tag
+	return nil
+}
+
+// DeleteTag deletes the tag with the specified UUID.
+func (r *fakeRepo) DeleteTag(_ context.Context, id uuid.UUID) (bool, error) {
+	if _, ok := r.tags[id]; !ok {
+		return false, nil
+	}
+	delete(r.tags, id)
+	return true, nil
+}
+
+// conflicts reports whether name is used by a tag other than the one with the specified UUID, ignoring case.
+func (r *fakeRepo) conflicts(id uuid.UUID, name string) bool {
+	for _, other := range r.tags {
+		if other.UUID != id && strings.EqualFold(other.Name, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func Test_fakeRepo_Tags(t *testing.T) {
+	t.Parallel()
+
+	repo := NewFakeRepository()
+	christmas := model.Tag{Name: " Christmas ", Color: "#FF0000"}
+	if err := repo.CreateTag(context.TODO(), &christmas); err != nil {
+		t.Fatalf("CreateTag(ctx, &tag) returned an unexpected error: %s", err)
+	}
+	if christmas.Name != "Christmas" || christmas.Color != "#ff0000" {
+		t.Errorf("CreateTag(ctx, &tag) produced name %q and color %q, expected %q and %q", christmas.Name, christmas.Color, "Christmas", "#ff0000")
+	}
+
+	conflict := model.Tag{Name: "christmas"}
+	if err := repo.CreateTag(context.TODO(), &conflict); !errors.Is(err, core.ErrConflict) {
+		t.Errorf("CreateTag(ctx, &tag) with a conflicting name returned %v, expected ErrConflict", err)
+	}
+
+	party := model.Tag{Name: "party-starter"}
+	_ = repo.CreateTag(context.TODO(), &party)
+	tags, total, err := repo.FindTags(context.TODO(), 1, 1)
+	if err != nil {
+		t.Fatalf("FindTags(ctx, 1, 1) returned an unexpected error: %s", err)
+	}
+	if total != 2 || len(tags) != 1 || tags[0].UUID != party.UUID {
+		t.Errorf("FindTags(ctx, 1, 1) = %v, %d, expected [%s], 2", tags, total, party.Name)
+	}
+
+	party.Name = "CHRISTMAS"
+	if err = repo.UpdateTag(context.TODO(), &party); !errors.Is(err, core.ErrConflict) {
+		t.Errorf("UpdateTag(ctx, &tag) with a conflicting name returned %v, expected ErrConflict", err)
+	}
+
+	if ok, _ := repo.DeleteTag(context.TODO(), christmas.UUID); !ok {
+		t.Errorf("DeleteTag(ctx, %q) = false, expected true", christmas.UUID)
+	}
+	if _, err = repo.GetTag(context.TODO(), christmas.UUID); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("GetTag(ctx, %q) after deleting returned %v, expected ErrNotFound", christmas.UUID, err)
+	}
+}
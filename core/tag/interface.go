@@ -0,0 +1,38 @@
+package tag
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// A Repository is an interface for storing tags.
+// Tags are also created implicitly when a song is saved with an unknown tag.
+//
+// Tag names are unique, ignoring case.
+// Operations that would violate this constraint return core.ErrConflict.
+type Repository interface {
+	// CreateTag creates a new tag with the specified name and color.
+	// This method must set tag.UUID, tag.CreatedAt, and tag.UpdatedAt appropriately.
+	CreateTag(ctx context.Context, tag *model.Tag) error
+
+	// GetTag fetches the tag with the specified UUID.
+	// If no such tag exists, core.ErrNotFound will be returned.
+	GetTag(ctx context.Context, id uuid.UUID) (model.Tag, error)
+
+	// FindTags returns all tags ordered by name.
+	// Results are paginated with limit and offset.
+	// The second return value contains the total (unpaginated) number of tags.
+	FindTags(ctx context.Context, limit int, offset int64) ([]model.Tag, int64, error)
+
+	// UpdateTag saves the name and color of the specified tag.
+	// Renaming a tag renames it for all songs.
+	// The tag's UUID must already exist in the database, otherwise core.ErrNotFound will be returned.
+	UpdateTag(ctx context.Context, tag *model.Tag) error
+
+	// DeleteTag deletes the tag with the specified UUID and removes it from all songs.
+	// If no such tag exists, the first return value will be false.
+	DeleteTag(ctx context.Context, id uuid.UUID) (bool, error)
+}
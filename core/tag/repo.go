@@ -0,0 +1,172 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/internal/dbutil"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// dbRepo is the main Repository implementation, backed by a PostgreSQL database.
+type dbRepo struct {
+	logger *slog.Logger
+	db     pgxutil.DB // database connection
+}
+
+// NewDBRepository creates a new Repository backed by the specified database connection.
+// db can be a single connection or a connection pool.
+func NewDBRepository(logger *slog.Logger, db pgxutil.DB) Repository {
+	return &dbRepo{logger, db}
+}
+
+// tagColumns selects the columns of a tag t, including the number of library songs with the tag.
+const tagColumns = `t.uuid, t.created_at, t.updated_at, t.name, t.color,
+    (SELECT COUNT(*) FROM song_tags AS st JOIN songs AS s ON st.song_id = s.id
+        WHERE st.tag_id = t.id AND s.upload_id IS NULL AND s.deleted_at IS NULL) AS song_count`
+
+// tagRow is the data returned by a SELECT query for tags.
+type tagRow struct {
+	UUID      uuid.UUID
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	Name      string
+	Color     string
+	SongCount int64 `db:"song_count"`
+}
+
+// toModel converts r into an equivalent model.Tag.
+func (r tagRow) toModel() model.Tag {
+	return model.Tag{
+		Model: model.Model{
+			UUID:      r.UUID,
+			CreatedAt: r.CreatedAt,
+			UpdatedAt: r.UpdatedAt,
+		},
+		Name:      r.Name,
+		Color:     r.Color,
+		SongCount: r.SongCount,
+	}
+}
+
+// CreateTag creates tag in the database.
+func (r *dbRepo) CreateTag(ctx context.Context, tag *model.Tag) error {
+	prepareTag(tag)
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		id, err := pgxutil.InsertRowReturning(ctx, tx, "tags", map[string]any{
+			"name":  tag.Name,
+			"color": tag.Color,
+		}, "id", pgx.RowTo[int])
+		if err != nil {
+			return err
+		}
+		*tag, err = getTag(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		if err = dbutil.Error(err); !errors.Is(err, core.ErrConflict) {
+			r.logger.ErrorContext(ctx, "Could not create tag.", "name", tag.Name, tint.Err(err))
+		}
+		return err
+	}
+	return nil
+}
+
+// GetTag fetches a single tag from the database by its UUID.
+func (r *dbRepo) GetTag(ctx context.Context, id uuid.UUID) (model.Tag, error) {
+	row, err := pgxutil.SelectRow(ctx, r.db, `SELECT `+tagColumns+`
+	FROM tags AS t
+	WHERE t.uuid = $1`, []any{id}, pgx.RowToStructByName[tagRow])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not fetch tag.", "uuid", id, tint.Err(err))
+		}
+		return model.Tag{}, dbutil.Error(err)
+	}
+	return row.toModel(), nil
+}
+
+// FindTags fetches multiple tags from the database, ordered by name.
+// The results are paginated with limit and offset.
+func (r *dbRepo) FindTags(ctx context.Context, limit int, offset int64) ([]model.Tag, int64, error) {
+	total, err := pgxutil.SelectRow(ctx, r.db, `SELECT COUNT(*) FROM tags`, nil, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not count tags.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, 0, err
+	}
+	tags, err := pgxutil.Select(ctx, r.db, `SELECT `+tagColumns+`
+	FROM tags AS t
+	ORDER BY LOWER(t.name), t.id
+	LIMIT CASE WHEN $1 < 0 THEN NULL ELSE $1 END OFFSET $2`, []any{limit, offset}, func(row pgx.CollectableRow) (model.Tag, error) {
+		data, err := pgx.RowToStructByName[tagRow](row)
+		return data.toModel(), err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list tags.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, total, err
+	}
+	return tags, total, nil
+}
+
+// UpdateTag updates the tag in the database with tag.UUID.
+func (r *dbRepo) UpdateTag(ctx context.Context, tag *model.Tag) error {
+	prepareTag(tag)
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		id, err := pgxutil.UpdateRowReturning(ctx, tx, "tags", map[string]any{
+			"name":  tag.Name,
+			"color": tag.Color,
+		}, map[string]any{
+			"uuid": tag.UUID,
+		}, "id", pgx.RowTo[int])
+		if err != nil {
+			return err
+		}
+		*tag, err = getTag(ctx, tx, id)
+		return err
+	})
+	if err != nil {
+		if err = dbutil.Error(err); !errors.Is(err, core.ErrNotFound) && !errors.Is(err, core.ErrConflict) {
+			r.logger.ErrorContext(ctx, "Could not update tag.", "uuid", tag.UUID, tint.Err(err))
+		}
+		return err
+	}
+	return nil
+}
+
+// DeleteTag deletes the tag with the specified UUID.
+// The tag is removed from all songs via the foreign key constraint.
+func (r *dbRepo) DeleteTag(ctx context.Context, id uuid.UUID) (bool, error) {
+	_, err := pgxutil.ExecRow(ctx, r.db, `DELETE FROM tags WHERE uuid = $1`, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not delete tag.", "uuid", id, tint.Err(err))
+		return false, err
+	}
+	return true, nil
+}
+
+// getTag fetches the tag with the specified ID using db.
+func getTag(ctx context.Context, db pgxutil.DB, id int) (model.Tag, error) {
+	row, err := pgxutil.SelectRow(ctx, db, `SELECT `+tagColumns+`
+	FROM tags AS t
+	WHERE t.id = $1`, []any{id}, pgx.RowToStructByName[tagRow])
+	return row.toModel(), err
+}
+
+// prepareTag normalizes the name and color of tag.
+// Whitespace is trimmed and the color is converted to lower case.
+func prepareTag(tag *model.Tag) {
+	tag.Name = strings.TrimSpace(tag.Name)
+	tag.Color = strings.ToLower(strings.TrimSpace(tag.Color))
+}
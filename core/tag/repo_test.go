@@ -0,0 +1,94 @@
+//go:build database
+
+package tag
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func Test_dbRepo_CreateTag(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+
+	t.Run("success", func(t *testing.T) {
+		tag := model.Tag{Name: " Christmas ", Color: "#FF0000"}
+		if err := repo.CreateTag(context.TODO(), &tag); err != nil {
+			t.Fatalf("CreateTag(ctx, &tag) returned an unexpected error: %s", err)
+		}
+		if tag.UUID == uuid.Nil {
+			t.Errorf("CreateTag(ctx, &tag) produced tag.UUID = <uuid.Nil>, expected a valid UUID")
+		}
+		if tag.Name != "Christmas" || tag.Color != "#ff0000" {
+			t.Errorf("CreateTag(ctx, &tag) produced name %q and color %q, expected %q and %q", tag.Name, tag.Color, "Christmas", "#ff0000")
+		}
+	})
+	t.Run("conflict", func(t *testing.T) {
+		tag := model.Tag{Name: "CHRISTMAS"}
+		if err := repo.CreateTag(context.TODO(), &tag); !errors.Is(err, core.ErrConflict) {
+			t.Errorf("CreateTag(ctx, &tag) with name %q returned %v, expected ErrConflict", tag.Name, err)
+		}
+	})
+}
+
+func Test_dbRepo_SongTags(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	songRepo := song.NewDBRepository(nolog.Logger, db)
+	christmas := model.Tag{Name: "Christmas", Color: "#ff0000"}
+	if err := repo.CreateTag(context.TODO(), &christmas); err != nil {
+		t.Fatalf("CreateTag(ctx, &tag) returned an unexpected error: %s", err)
+	}
+
+	sng := testdata.SimpleSong(t, db)
+	sng.Tags = []string{"christmas", " needs-video ", "Needs-Video"}
+	if err := songRepo.UpdateSong(context.TODO(), &sng); err != nil {
+		t.Fatalf("UpdateSong(ctx, &song) returned an unexpected error: %s", err)
+	}
+	if expected := []string{"Christmas", "needs-video"}; !slices.Equal(sng.Tags, expected) {
+		t.Errorf("UpdateSong(ctx, &song) produced tags %q, expected %q", sng.Tags, expected)
+	}
+
+	tags, total, err := repo.FindTags(context.TODO(), -1, 0)
+	if err != nil {
+		t.Fatalf("FindTags(ctx, -1, 0) returned an unexpected error: %s", err)
+	}
+	if total != 2 || tags[0].SongCount != 1 || tags[1].Name != "needs-video" {
+		t.Errorf("FindTags(ctx, -1, 0) = %v, %d, expected two tags with one song each", tags, total)
+	}
+
+	christmas.Name = "Xmas"
+	if err = repo.UpdateTag(context.TODO(), &christmas); err != nil {
+		t.Fatalf("UpdateTag(ctx, &tag) returned an unexpected error: %s", err)
+	}
+	sng, _ = songRepo.GetSong(context.TODO(), sng.UUID)
+	if !slices.Contains(sng.Tags, "Xmas") {
+		t.Errorf("UpdateTag(ctx, &tag) did not rename the tag of the song, got %q", sng.Tags)
+	}
+
+	if ok, err := repo.DeleteTag(context.TODO(), christmas.UUID); err != nil || !ok {
+		t.Fatalf("DeleteTag(ctx, %q) = %t, %v, expected true, nil", christmas.UUID, ok, err)
+	}
+	sng, _ = songRepo.GetSong(context.TODO(), sng.UUID)
+	if !slices.Equal(sng.Tags, []string{"needs-video"}) {
+		t.Errorf("DeleteTag(ctx, %q) did not remove the tag from the song, got %q", christmas.UUID, sng.Tags)
+	}
+	if ok, _ := repo.DeleteTag(context.TODO(), christmas.UUID); ok {
+		t.Errorf("DeleteTag(ctx, %q) for a missing tag = true, expected false", christmas.UUID)
+	}
+}
@@ -0,0 +1,114 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// ErrNoMatch indicates that an uploaded song cannot update a library song because it does not match any song in the library.
+var ErrNoMatch = errors.New("song does not match a library song")
+
+// ImportSong imports an uploaded song into the library.
+// Songs imported with model.ImportActionKeepBoth are moved into the library unchanged.
+// Songs imported with model.ImportActionAddVariant are moved into the library as well
+// and become a variant of the matched library song (see variantRelation).
+// For the other actions the matched library song is updated and the uploaded song is deleted permanently
+// in a single transaction.
+// Media files of the library song are not modified.
+func (s *service) ImportSong(ctx context.Context, song model.UploadSong, action model.ImportAction, version time.Time) (model.Song, error) {
+	if action == model.ImportActionKeepBoth {
+		if _, err := s.repo.ImportSong(ctx, song.Song); err != nil {
+			return model.Song{}, err
+		}
+		return s.songRepo.GetSong(ctx, song.Song)
+	}
+
+	if song.Match == uuid.Nil {
+		return model.Song{}, ErrNoMatch
+	}
+	uploaded, err := s.songRepo.GetSong(ctx, song.Song)
+	if err != nil {
+		return model.Song{}, err
+	}
+	library, err := s.songRepo.GetLibrarySong(ctx, song.Match)
+	if errors.Is(err, core.ErrNotFound) {
+		return model.Song{}, ErrNoMatch
+	} else if err != nil {
+		return model.Song{}, err
+	}
+	switch action {
+	case model.ImportActionAddVariant:
+		if _, err = s.repo.ImportSong(ctx, uploaded.UUID); err != nil {
+			return model.Song{}, err
+		}
+		if _, err = s.variantRepo.LinkVariant(ctx, uploaded.UUID, library.UUID, variantRelation(uploaded, library)); err != nil {
+			return model.Song{}, err
+		}
+		return s.songRepo.GetSong(ctx, uploaded.UUID)
+	case model.ImportActionReplace:
+		model.NewSongRevision(uploaded, "").Apply(&library)
+	case model.ImportActionMergeMetadata:
+		mergeMetadata(&library, uploaded)
+	default:
+		return model.Song{}, fmt.Errorf("unknown import action: %q", action)
+	}
+	if !version.IsZero() && !library.UpdatedAt.Equal(version) {
+		return model.Song{}, core.ErrPreconditionFailed
+	}
+	if err = s.songRepo.MergeSongs(ctx, &library, []uuid.UUID{uploaded.UUID}, true, library.UpdatedAt); err != nil {
+		return model.Song{}, err
+	}
+	return library, nil
+}
+
+// variantRelation determines the relation of an uploaded song that is imported as a variant of library.
+// A duet chart of a solo song is a duet version, all other charts are alternative charts.
+// The relation can be changed after the import.
+func variantRelation(uploaded model.Song, library model.Song) model.VariantRelation {
+	if uploaded.IsDuet() && !library.IsDuet() {
+		return model.VariantRelationDuetVersion
+	}
+	return model.VariantRelationAlternativeChart
+}
+
+// mergeMetadata copies the metadata of src to dst.
+// Empty values of src do not overwrite values of dst.
+// Custom tags are merged, values of src take precedence.
+// The notes and timing of dst are not modified.
+func mergeMetadata(dst *model.Song, src model.Song) {
+	str := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	str(&dst.Title, src.Title)
+	if len(src.Artists) > 0 {
+		dst.Artist = src.Artist
+		dst.Artists = src.Artists
+		dst.FeaturedArtists = src.FeaturedArtists
+	}
+	str(&dst.Genre, src.Genre)
+	str(&dst.Edition, src.Edition)
+	str(&dst.Creator, src.Creator)
+	str(&dst.Language, src.Language)
+	if src.Year != 0 {
+		dst.Year = src.Year
+	}
+	str(&dst.Comment, src.Comment)
+	str(&dst.DuetSinger1, src.DuetSinger1)
+	str(&dst.DuetSinger2, src.DuetSinger2)
+	if len(src.CustomTags) > 0 {
+		tags := make(map[string]string, len(dst.CustomTags)+len(src.CustomTags))
+		maps.Copy(tags, dst.CustomTags)
+		maps.Copy(tags, src.CustomTags)
+		dst.CustomTags = tags
+	}
+}
@@ -0,0 +1,35 @@
+package upload
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func TestMergeMetadata(t *testing.T) {
+	var dst, src model.Song
+	dst.Title, dst.Artist, dst.Artists = "Bohemian Rapsody", "Queen", []string{"Queen"}
+	dst.Genre, dst.Year, dst.Gap = "Rock", 1975, 1200
+	dst.CustomTags = map[string]string{"A": "1", "B": "2"}
+	src.Title, src.Creator, src.Gap = "Bohemian Rhapsody", "Someone", 5000
+	src.CustomTags = map[string]string{"B": "3"}
+
+	mergeMetadata(&dst, src)
+	if dst.Title != src.Title {
+		t.Errorf("mergeMetadata() set Title = %q, expected %q", dst.Title, src.Title)
+	}
+	if dst.Creator != src.Creator {
+		t.Errorf("mergeMetadata() set Creator = %q, expected %q", dst.Creator, src.Creator)
+	}
+	if dst.Genre != "Rock" || dst.Year != 1975 || !slices.Equal(dst.Artists, []string{"Queen"}) {
+		t.Errorf("mergeMetadata() overwrote metadata with empty values")
+	}
+	if dst.Gap != 1200 {
+		t.Errorf("mergeMetadata() set Gap = %s, expected the timing to be unchanged", dst.Gap)
+	}
+	if expected := map[string]string{"A": "1", "B": "3"}; !maps.Equal(dst.CustomTags, expected) {
+		t.Errorf("mergeMetadata() set CustomTags = %v, expected %v", dst.CustomTags, expected)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"io/fs"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -23,6 +24,17 @@ type Service interface {
 	// Implementations must make sure that a nil value is returned if and only if
 	// the upload was deleted from both the database and the storage system.
 	DeleteUpload(ctx context.Context, id uuid.UUID) error
+
+	// ImportSong imports a song from an upload into the library according to action.
+	// Importing with model.ImportActionReplace or model.ImportActionMergeMetadata updates the matched library song
+	// and deletes the uploaded song.
+	// Importing with model.ImportActionAddVariant imports the song as a new song
+	// and adds it to the variant group of the matched library song.
+	// If the song does not match a library song, these actions return ErrNoMatch.
+	// If version is not the zero time, the library song is only updated if its UpdatedAt timestamp still equals version.
+	// Otherwise, core.ErrPreconditionFailed is returned.
+	// The resulting library song is returned.
+	ImportSong(ctx context.Context, song model.UploadSong, action model.ImportAction, version time.Time) (model.Song, error)
 }
 
 // Repository provides methods for storing uploads.
@@ -69,6 +81,23 @@ type Repository interface {
 	// If no files exist or the specified upload does not exist, the first return value will be false.
 	// Files will only be deleted from the database. The actual files remain in the filesystem until the upload is deleted.
 	ClearFiles(ctx context.Context, upload *model.Upload) (bool, error)
+
+	// FindSongs returns a paginated list of the songs in the upload with the specified UUID
+	// together with the library songs they match.
+	// Songs are returned in the order in which they were processed.
+	FindSongs(ctx context.Context, id uuid.UUID, limit int, offset int64) ([]model.UploadSong, int64, error)
+
+	// GetSong returns the song with the UUID song from the upload with the UUID id.
+	// If the upload does not contain such a song, core.ErrNotFound is returned.
+	GetSong(ctx context.Context, id uuid.UUID, song uuid.UUID) (model.UploadSong, error)
+
+	// SetSongMatch records that the song with the UUID song is a version of the library song with the UUID match.
+	// If match is uuid.Nil, an existing match is removed.
+	SetSongMatch(ctx context.Context, song uuid.UUID, match uuid.UUID) error
+
+	// ImportSong moves the song with the specified UUID from its upload into the library.
+	// If no such song exists in an upload, the first return value will be false.
+	ImportSong(ctx context.Context, id uuid.UUID) (bool, error)
 }
 
 // Store is an interface used by the upload service to facilitate the actual file storage.
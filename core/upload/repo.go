@@ -68,6 +68,22 @@ func (r uploadRow) toModel() model.Upload {
 	return upload
 }
 
+// uploadErrorRow is the data returned by a SELECT query for upload errors.
+type uploadErrorRow struct {
+	File      string
+	Message   string
+	Duplicate uuid.NullUUID
+}
+
+// toModel converts r to an equivalent model.UploadProcessingError.
+func (r uploadErrorRow) toModel() model.UploadProcessingError {
+	return model.UploadProcessingError{
+		File:      r.File,
+		Message:   r.Message,
+		Duplicate: r.Duplicate.UUID,
+	}
+}
+
 // CreateUpload creates a new upload in the database.
 func (r *dbRepo) CreateUpload(ctx context.Context, upload *model.Upload) error {
 	row, err := pgxutil.InsertRowReturning(ctx, r.db, "uploads", map[string]any{
@@ -158,11 +174,16 @@ func (r *dbRepo) DeleteUpload(ctx context.Context, id uuid.UUID) (bool, error) {
 
 // CreateError creates a processing error for an upload.
 func (r *dbRepo) CreateError(ctx context.Context, upload *model.Upload, processingError model.UploadProcessingError) error {
-	_, err := pgxutil.ExecRow(ctx, r.db, `INSERT INTO upload_errors (upload_id, file, message)
-		VALUES ((SELECT uploads.id FROM uploads WHERE uuid = $1), $2, $3)`,
+	var duplicate *uuid.UUID
+	if processingError.Duplicate != uuid.Nil {
+		duplicate = &processingError.Duplicate
+	}
+	_, err := pgxutil.ExecRow(ctx, r.db, `INSERT INTO upload_errors (upload_id, file, message, duplicate_id)
+		VALUES ((SELECT uploads.id FROM uploads WHERE uuid = $1), $2, $3, (SELECT songs.id FROM songs WHERE uuid = $4))`,
 		upload.UUID,
 		processingError.File,
 		processingError.Message,
+		duplicate,
 	)
 	if err != nil {
 		r.logger.ErrorContext(ctx, "Could not create upload error.", "uuid", upload.UUID, tint.Err(err))
@@ -190,10 +211,15 @@ func (r *dbRepo) GetErrors(ctx context.Context, id uuid.UUID, limit int, offset
 		return nil, 0, dbutil.Error(err)
 	}
 	uploadErrors, err := pgxutil.Select(ctx, r.db, `SELECT
-    file, message
-	FROM upload_errors
-	WHERE upload_id = $1
-	LIMIT CASE WHEN $2 < 0 THEN NULL ELSE $2 END OFFSET $3`, []any{row.ID, limit, offset}, pgx.RowToStructByName[model.UploadProcessingError])
+    e.file, e.message, s.uuid AS duplicate
+	FROM upload_errors AS e
+	LEFT OUTER JOIN songs AS s ON e.duplicate_id = s.id
+	WHERE e.upload_id = $1
+	ORDER BY e.id
+	LIMIT CASE WHEN $2 < 0 THEN NULL ELSE $2 END OFFSET $3`, []any{row.ID, limit, offset}, func(row pgx.CollectableRow) (model.UploadProcessingError, error) {
+		data, err := pgx.RowToStructByName[uploadErrorRow](row)
+		return data.toModel(), err
+	})
 	if err != nil {
 		r.logger.ErrorContext(ctx, "Could not list upload errors.", "uuid", id, "limit", limit, "offset", offset, tint.Err(err))
 		return nil, row.Total, err
@@ -249,3 +275,93 @@ func (r *dbRepo) ClearFiles(ctx context.Context, upload *model.Upload) (bool, er
 	}
 	return true, nil
 }
+
+// uploadSongRow is the data returned by a SELECT query for songs in an upload.
+type uploadSongRow struct {
+	Song  uuid.UUID
+	Match uuid.NullUUID
+}
+
+// toModel converts r to an equivalent model.UploadSong.
+func (r uploadSongRow) toModel() model.UploadSong {
+	return model.UploadSong{
+		Song:  r.Song,
+		Match: r.Match.UUID,
+	}
+}
+
+// FindSongs lists the songs of an upload with pagination.
+func (r *dbRepo) FindSongs(ctx context.Context, id uuid.UUID, limit int, offset int64) ([]model.UploadSong, int64, error) {
+	total, err := pgxutil.SelectRow(ctx, r.db, `SELECT COUNT(*)
+	FROM songs AS s
+	JOIN uploads AS u ON s.upload_id = u.id
+	WHERE u.uuid = $1`, []any{id}, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not count upload songs.", "uuid", id, "limit", limit, "offset", offset, tint.Err(err))
+		return nil, 0, err
+	}
+	songs, err := pgxutil.Select(ctx, r.db, `SELECT s.uuid AS song, m.uuid AS match
+	FROM songs AS s
+	JOIN uploads AS u ON s.upload_id = u.id
+	LEFT OUTER JOIN songs AS m ON s.upload_match_id = m.id
+	WHERE u.uuid = $1
+	ORDER BY s.id
+	LIMIT CASE WHEN $2 < 0 THEN NULL ELSE $2 END OFFSET $3`, []any{id, limit, offset}, func(row pgx.CollectableRow) (model.UploadSong, error) {
+		data, err := pgx.RowToStructByName[uploadSongRow](row)
+		return data.toModel(), err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list upload songs.", "uuid", id, "limit", limit, "offset", offset, tint.Err(err))
+		return nil, total, err
+	}
+	return songs, total, nil
+}
+
+// GetSong fetches a single song of an upload.
+func (r *dbRepo) GetSong(ctx context.Context, id uuid.UUID, song uuid.UUID) (model.UploadSong, error) {
+	row, err := pgxutil.SelectRow(ctx, r.db, `SELECT s.uuid AS song, m.uuid AS match
+	FROM songs AS s
+	JOIN uploads AS u ON s.upload_id = u.id
+	LEFT OUTER JOIN songs AS m ON s.upload_match_id = m.id
+	WHERE u.uuid = $1 AND s.uuid = $2`, []any{id, song}, pgx.RowToStructByName[uploadSongRow])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not fetch upload song.", "uuid", id, "song", song, tint.Err(err))
+		}
+		return model.UploadSong{}, dbutil.Error(err)
+	}
+	return row.toModel(), nil
+}
+
+// SetSongMatch sets the library song that an uploaded song matches.
+func (r *dbRepo) SetSongMatch(ctx context.Context, song uuid.UUID, match uuid.UUID) error {
+	var m *uuid.UUID
+	if match != uuid.Nil {
+		m = &match
+	}
+	_, err := pgxutil.ExecRow(ctx, r.db, `UPDATE songs
+	SET upload_match_id = (SELECT id FROM songs WHERE uuid = $2)
+	WHERE uuid = $1 AND upload_id IS NOT NULL`, song, m)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not set upload song match.", "song", song, "match", match, tint.Err(err))
+		}
+		return dbutil.Error(err)
+	}
+	return nil
+}
+
+// ImportSong removes the song with the specified UUID from its upload.
+func (r *dbRepo) ImportSong(ctx context.Context, id uuid.UUID) (bool, error) {
+	_, err := pgxutil.ExecRow(ctx, r.db, `UPDATE songs
+	SET upload_id = NULL, upload_match_id = NULL
+	WHERE uuid = $1 AND upload_id IS NOT NULL`, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not import upload song.", "uuid", id, tint.Err(err))
+		return false, err
+	}
+	return true, nil
+}
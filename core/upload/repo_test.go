@@ -294,3 +294,107 @@ func Test_dbRepo_ClearFiles(t *testing.T) {
 		t.Errorf("ClearFiles(ctx, %q) = %t, nil [2nd time], expected %t", upload.UUID, ok, false)
 	}
 }
+
+func Test_dbRepo_FindSongs(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	upload, song, library := testdata.DoneUploadWithMatch(t, db)
+	other := testdata.DoneUploadWithSongs(t, db)
+
+	songs, total, err := repo.FindSongs(context.TODO(), upload.UUID, -1, 0)
+	if err != nil {
+		t.Fatalf("FindSongs(ctx, %q, -1, 0) returned an unexpected error: %s", upload.UUID, err)
+	}
+	if total != 1 || len(songs) != 1 {
+		t.Fatalf("FindSongs(ctx, %q, -1, 0) returned %d songs (total %d), expected 1", upload.UUID, len(songs), total)
+	}
+	if expected := (model.UploadSong{Song: song.UUID, Match: library.UUID}); songs[0] != expected {
+		t.Errorf("FindSongs(ctx, %q, -1, 0)[0] = %v, expected %v", upload.UUID, songs[0], expected)
+	}
+
+	songs, total, err = repo.FindSongs(context.TODO(), other.UUID, 1, 0)
+	if err != nil {
+		t.Fatalf("FindSongs(ctx, %q, 1, 0) returned an unexpected error: %s", other.UUID, err)
+	}
+	if total != 2 || len(songs) != 1 || songs[0].Match != uuid.Nil {
+		t.Errorf("FindSongs(ctx, %q, 1, 0) = %v (total %d), expected 1 unmatched song (total 2)", other.UUID, songs, total)
+	}
+}
+
+func Test_dbRepo_GetSong(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	upload, song, library := testdata.DoneUploadWithMatch(t, db)
+
+	t.Run("found", func(t *testing.T) {
+		actual, err := repo.GetSong(context.TODO(), upload.UUID, song.UUID)
+		if err != nil {
+			t.Fatalf("GetSong(ctx, %q, %q) returned an unexpected error: %s", upload.UUID, song.UUID, err)
+		}
+		if actual.Song != song.UUID || actual.Match != library.UUID {
+			t.Errorf("GetSong(ctx, %q, %q) = %v, expected a match with %q", upload.UUID, song.UUID, actual, library.UUID)
+		}
+	})
+
+	t.Run("not in upload", func(t *testing.T) {
+		if _, err := repo.GetSong(context.TODO(), upload.UUID, library.UUID); !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("GetSong(ctx, %q, %q) returned %v, expected ErrNotFound", upload.UUID, library.UUID, err)
+		}
+	})
+}
+
+func Test_dbRepo_SetSongMatch(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	upload, song, _ := testdata.DoneUploadWithMatch(t, db)
+	other := testdata.SimpleSong(t, db)
+
+	if err := repo.SetSongMatch(context.TODO(), song.UUID, other.UUID); err != nil {
+		t.Fatalf("SetSongMatch(ctx, %q, %q) returned an unexpected error: %s", song.UUID, other.UUID, err)
+	}
+	if actual, _ := repo.GetSong(context.TODO(), upload.UUID, song.UUID); actual.Match != other.UUID {
+		t.Errorf("GetSong(ctx, %q, %q) after SetSongMatch() returned match %q, expected %q", upload.UUID, song.UUID, actual.Match, other.UUID)
+	}
+	if err := repo.SetSongMatch(context.TODO(), song.UUID, uuid.Nil); err != nil {
+		t.Fatalf("SetSongMatch(ctx, %q, %q) returned an unexpected error: %s", song.UUID, uuid.Nil, err)
+	}
+	if actual, _ := repo.GetSong(context.TODO(), upload.UUID, song.UUID); actual.Match != uuid.Nil {
+		t.Errorf("GetSong(ctx, %q, %q) after SetSongMatch(nil) returned match %q, expected none", upload.UUID, song.UUID, actual.Match)
+	}
+	if err := repo.SetSongMatch(context.TODO(), other.UUID, song.UUID); !errors.Is(err, core.ErrNotFound) {
+		t.Errorf("SetSongMatch(ctx, %q, _) for a library song returned %v, expected ErrNotFound", other.UUID, err)
+	}
+}
+
+func Test_dbRepo_ImportSong(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	upload, song, _ := testdata.DoneUploadWithMatch(t, db)
+
+	ok, err := repo.ImportSong(context.TODO(), song.UUID)
+	if err != nil {
+		t.Errorf("ImportSong(ctx, %q) returned an unexpected error: %s", song.UUID, err)
+	}
+	if !ok {
+		t.Errorf("ImportSong(ctx, %q) = %t, nil, expected %t", song.UUID, ok, true)
+	}
+	if _, total, _ := repo.FindSongs(context.TODO(), upload.UUID, -1, 0); total != 0 {
+		t.Errorf("FindSongs(ctx, %q, -1, 0) after ImportSong() returned total %d, expected 0", upload.UUID, total)
+	}
+
+	ok, err = repo.ImportSong(context.TODO(), song.UUID)
+	if err != nil {
+		t.Errorf("ImportSong(ctx, %q) [2nd time] returned an unexpected error: %s", song.UUID, err)
+	}
+	if ok {
+		t.Errorf("ImportSong(ctx, %q) = %t, nil [2nd time], expected %t", song.UUID, ok, false)
+	}
+}
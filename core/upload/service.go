@@ -6,27 +6,50 @@ import (
 	"io/fs"
 	"log/slog"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"codello.dev/ultrastar/txt"
 	"github.com/google/uuid"
+	"github.com/lmittmann/tint"
 
+	"github.com/Karaoke-Manager/karman/core/duplicate"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/header"
+	"github.com/Karaoke-Manager/karman/core/midi"
 	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/core/variant"
 	"github.com/Karaoke-Manager/karman/model"
 )
 
+// midiExtensions are the file extensions of MIDI files that are imported as songs.
+var midiExtensions = map[string]bool{".mid": true, ".midi": true, ".kar": true}
+
 type service struct {
 	logger *slog.Logger
 	repo   Repository
 	store  Store
 
-	songRepo    song.Repository
-	songService song.Service
+	songRepo      song.Repository
+	songService   song.Service
+	duplicateRepo duplicate.Repository
+	headerRepo    header.Repository
+	variantRepo   variant.Repository
+	events        event.Bus
+
+	// fixers are applied to every song during processing.
+	fixers []song.Transform
 }
 
 // NewService creates a new Service instance using the supplied repo and store.
-func NewService(logger *slog.Logger, repo Repository, store Store, songRepo song.Repository, songService song.Service) Service {
-	return &service{logger, repo, store, songRepo, songService}
+// Processing progress and errors are published to events.
+// The fixers are applied to every song found in an upload.
+// Songs that duplicate songs in the library are reported as processing errors using duplicateRepo
+// and matched to the library song they are a version of.
+// Custom tags that do not conform to the registry of custom headers in headerRepo are reported as processing errors as well.
+// Songs imported as variants of library songs are linked using variantRepo.
+func NewService(logger *slog.Logger, repo Repository, store Store, songRepo song.Repository, songService song.Service, duplicateRepo duplicate.Repository, headerRepo header.Repository, variantRepo variant.Repository, events event.Bus, fixers []song.Transform) Service {
+	return &service{logger, repo, store, songRepo, songService, duplicateRepo, headerRepo, variantRepo, events, fixers}
 }
 
 func (s *service) ProcessUpload(ctx context.Context, id uuid.UUID) error {
@@ -42,7 +65,7 @@ func (s *service) ProcessUpload(ctx context.Context, id uuid.UUID) error {
 	upload.SongsTotal = -1
 	upload.SongsProcessed = 0
 	// TODO: Logging
-	if err = s.repo.UpdateUpload(ctx, &upload); err != nil {
+	if err = s.updateUpload(ctx, &upload); err != nil {
 		return err
 	}
 	if _, err = s.repo.ClearErrors(ctx, &upload); err != nil {
@@ -60,13 +83,13 @@ func (s *service) ProcessUpload(ctx context.Context, id uuid.UUID) error {
 	uploadFiles := s.store.FS(ctx, upload.UUID)
 	err = fs.WalkDir(uploadFiles, ".", func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
-			return s.repo.CreateError(ctx, &upload, model.UploadProcessingError{File: path, Message: fmt.Sprintf("could not list files: %s", err)})
+			return s.createError(ctx, &upload, model.UploadProcessingError{File: path, Message: fmt.Sprintf("could not list files: %s", err)})
 		}
 		if d.IsDir() {
 			return nil
 		}
 		ext := strings.ToLower(filepath.Ext(d.Name()))
-		if ext == ".txt" || ext == ".txd" {
+		if ext == ".txt" || ext == ".txd" || midiExtensions[ext] {
 			songFiles = append(songFiles, path)
 		}
 		return nil
@@ -74,15 +97,25 @@ func (s *service) ProcessUpload(ctx context.Context, id uuid.UUID) error {
 	if err != nil {
 		return err
 	}
+	songFiles = skipMIDICompanions(songFiles)
 
 	upload.SongsTotal = len(songFiles)
 	upload.SongsProcessed = 0
-	if err = s.repo.UpdateUpload(ctx, &upload); err != nil {
+	if err = s.updateUpload(ctx, &upload); err != nil {
 		return err
 	}
 
+	fps, err := s.duplicateRepo.FindFingerprints(ctx)
+	if err != nil {
+		return err
+	}
+	library := duplicate.NewDetector(fps)
+	registry, err := header.LoadRegistry(ctx, s.headerRepo)
+	if err != nil {
+		return err
+	}
 	for _, path := range songFiles {
-		ok, err := s.processFile(ctx, &upload, path)
+		ok, err := s.processFile(ctx, &upload, library, registry, path)
 		if err != nil {
 			return err
 		}
@@ -90,46 +123,167 @@ func (s *service) ProcessUpload(ctx context.Context, id uuid.UUID) error {
 			upload.Errors++
 		}
 		upload.SongsProcessed++
-		if err = s.repo.UpdateUpload(ctx, &upload); err != nil {
+		if err = s.updateUpload(ctx, &upload); err != nil {
 			return err
 		}
 	}
+	s.publish(ctx, event.UploadDone(upload))
 	return nil
 }
 
-func (s *service) processFile(ctx context.Context, upload *model.Upload, path string) (_ bool, err error) {
+// skipMIDICompanions removes MIDI files from paths that are located in a folder with a TXT file.
+// Such MIDI files usually accompany a song that is already present as a TXT file
+// and would otherwise be imported as a duplicate of that song.
+func skipMIDICompanions(paths []string) []string {
+	txtDirs := make(map[string]bool)
+	for _, path := range paths {
+		if ext := strings.ToLower(filepath.Ext(path)); ext == ".txt" || ext == ".txd" {
+			txtDirs[filepath.Dir(path)] = true
+		}
+	}
+	return slices.DeleteFunc(paths, func(path string) bool {
+		return midiExtensions[strings.ToLower(filepath.Ext(path))] && txtDirs[filepath.Dir(path)]
+	})
+}
+
+func (s *service) processFile(ctx context.Context, upload *model.Upload, library *duplicate.Detector, registry *header.Registry, path string) (_ bool, err error) {
 	f, err := s.store.Open(ctx, upload.UUID, path)
 	if err != nil {
-		err = s.repo.CreateError(ctx, upload, model.UploadProcessingError{File: path, Message: "could not open file"})
+		err = s.createError(ctx, upload, model.UploadProcessingError{File: path, Message: "could not open file"})
 		if err != nil {
 			return false, err
 		}
 	}
 	defer func() {
 		if cErr := f.Close(); cErr != nil {
-			cErr = s.repo.CreateError(ctx, upload, model.UploadProcessingError{File: path, Message: fmt.Sprintf("could not close file: %s", err)})
+			cErr = s.createError(ctx, upload, model.UploadProcessingError{File: path, Message: fmt.Sprintf("could not close file: %s", err)})
 			if err == nil {
 				err = cErr
 			}
 		}
 	}()
-	rawSong, err := txt.NewReader(f).ReadSong()
-	if err != nil {
-		return false, s.repo.CreateError(ctx, upload, model.UploadProcessingError{File: path, Message: fmt.Sprintf("could not parse song: %s", err)})
+	var sng model.Song
+	name := filepath.Base(path)
+	if ext := strings.ToLower(filepath.Ext(name)); midiExtensions[ext] {
+		// MIDI files are converted into a TXT file of the same name.
+		sng, err = midi.ReadSong(f)
+		name = strings.TrimSuffix(name, filepath.Ext(name)) + ".txt"
+	} else {
+		sng.Song, err = txt.NewReader(f).ReadSong()
 	}
-	sng := model.Song{
-		Song:        rawSong,
-		InUpload:    true,
-		TxtFileName: filepath.Base(path),
+	if err != nil {
+		return false, s.createError(ctx, upload, model.UploadProcessingError{File: path, Message: fmt.Sprintf("could not parse song: %s", err)})
 	}
+	sng.InUpload = true
+	sng.TxtFileName = name
 	s.songService.ParseArtists(ctx, &sng)
+	if err = s.songService.Transform(ctx, &sng, s.fixers); err != nil {
+		// The song is imported without fixes.
+		err = s.createError(ctx, upload, model.UploadProcessingError{File: path, Message: fmt.Sprintf("could not apply import fixers: %s", err)})
+		if err != nil {
+			return false, err
+		}
+	}
+	s.songService.DetectMedley(ctx, &sng)
 	if err = s.songRepo.CreateSong(ctx, &sng); err != nil {
-		return false, s.repo.CreateError(ctx, upload, model.UploadProcessingError{File: path, Message: fmt.Sprintf("could not save song to database: %s", err)})
+		return false, s.createError(ctx, upload, model.UploadProcessingError{File: path, Message: fmt.Sprintf("could not save song to database: %s", err)})
 	}
 	// TODO: Save media files
+	if err = s.flagDuplicates(ctx, upload, library, path, sng); err != nil {
+		return false, err
+	}
+	if err = s.checkCustomTags(ctx, upload, registry, path, sng); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
+// checkCustomTags creates a processing error for every custom tag of sng that does not conform to registry.
+// This includes unknown keys and keys that are not spelled canonically.
+// The song is imported nonetheless.
+func (s *service) checkCustomTags(ctx context.Context, upload *model.Upload, registry *header.Registry, path string, sng model.Song) error {
+	for _, p := range registry.Check(sng.CustomTags) {
+		if err := s.createError(ctx, upload, model.UploadProcessingError{File: path, Message: p.Error()}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flagDuplicates creates a processing error for every song in library that sng duplicates.
+// The song is imported nonetheless.
+// The best duplicate with an identical audio file or the same artist and title is recorded as the match of sng.
+func (s *service) flagDuplicates(ctx context.Context, upload *model.Upload, library *duplicate.Detector, path string, sng model.Song) error {
+	fp, err := s.duplicateRepo.GetFingerprint(ctx, sng.UUID)
+	if err != nil {
+		return err
+	}
+	matches := library.Find(fp)
+	for _, m := range matches {
+		reasons := make([]string, len(m.Reasons))
+		for i, r := range m.Reasons {
+			reasons[i] = string(r)
+		}
+		err = s.createError(ctx, upload, model.UploadProcessingError{
+			File:      path,
+			Message:   fmt.Sprintf("song duplicates song %s in the library (%s)", m.Song, strings.Join(reasons, ", ")),
+			Duplicate: m.Song,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if match := bestMatch(matches); match != uuid.Nil {
+		return s.repo.SetSongMatch(ctx, sng.UUID, match)
+	}
+	return nil
+}
+
+// bestMatch returns the song in matches that an uploaded song most likely is a version of.
+// Songs with an identical audio file are preferred over songs with the same artist and title.
+// Songs that only have similar lyrics are not considered.
+// If no song qualifies, uuid.Nil is returned.
+func bestMatch(matches []duplicate.Match) uuid.UUID {
+	for _, reason := range []model.DuplicateReason{model.DuplicateReasonAudio, model.DuplicateReasonTitle} {
+		for _, m := range matches {
+			if slices.Contains(m.Reasons, reason) {
+				return m.Song
+			}
+		}
+	}
+	return uuid.Nil
+}
+
+// updateUpload saves the processing state of upload and publishes a progress event.
+// If all songs of upload have been processed, the upload state is set to model.UploadStateDone.
+func (s *service) updateUpload(ctx context.Context, upload *model.Upload) error {
+	if upload.SongsTotal >= 0 && upload.SongsProcessed >= upload.SongsTotal {
+		upload.State = model.UploadStateDone
+	}
+	if err := s.repo.UpdateUpload(ctx, upload); err != nil {
+		return err
+	}
+	s.publish(ctx, event.UploadProgress(*upload))
+	return nil
+}
+
+// createError saves a processing error for upload and publishes an error event.
+func (s *service) createError(ctx context.Context, upload *model.Upload, processingError model.UploadProcessingError) error {
+	if err := s.repo.CreateError(ctx, upload, processingError); err != nil {
+		return err
+	}
+	s.publish(ctx, event.UploadError(*upload, processingError))
+	return nil
+}
+
+// publish sends e to the event bus.
+// Events are informational only, so errors are logged but otherwise ignored.
+func (s *service) publish(ctx context.Context, e event.Event) {
+	if err := s.events.Publish(ctx, e); err != nil {
+		s.logger.WarnContext(ctx, "Could not publish event.", "type", e.Type, tint.Err(err))
+	}
+}
+
 // DeleteUpload deletes an upload from the database and file storage.
 func (s *service) DeleteUpload(ctx context.Context, id uuid.UUID) error {
 	err := s.store.Delete(ctx, id, ".")
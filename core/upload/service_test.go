@@ -1,5 +1,19 @@
 package upload
 
+import (
+	"slices"
+	"testing"
+)
+
+func TestSkipMIDICompanions(t *testing.T) {
+	paths := []string{"a/song.txt", "a/song.mid", "b/song.kar", "c/Song.TXT", "c/song.MIDI", "c/sub/other.mid"}
+	expected := []string{"a/song.txt", "b/song.kar", "c/Song.TXT", "c/sub/other.mid"}
+	actual := skipMIDICompanions(slices.Clone(paths))
+	if !slices.Equal(actual, expected) {
+		t.Errorf("skipMIDICompanions(%q) = %q, expected %q", paths, actual, expected)
+	}
+}
+
 /*
 func TestService_DeleteUpload(t *testing.T) {
 	repo := fakeRepo
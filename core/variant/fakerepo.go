@@ -0,0 +1,65 @@
+package variant
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// membership is the variant group of a song and its relation to the group.
+type membership struct {
+	group    uuid.UUID
+	relation model.VariantRelation
+}
+
+// fakeRepo is a simple implementation of Repository that can be used for testing.
+// The fake repository does not know about songs, so linking never returns core.ErrNotFound.
+type fakeRepo struct {
+	// songs maps song UUIDs to their variant group.
+	songs map[uuid.UUID]membership
+}
+
+// NewFakeRepository returns a new Repository implementation backed by an in-memory map.
+func NewFakeRepository() Repository {
+	return &fakeRepo{make(map[uuid.UUID]membership)}
+}
+
+// LinkVariant adds the song to the group of other, creating the group if necessary.
+func (r *fakeRepo) LinkVariant(_ context.Context, id uuid.UUID, other uuid.UUID, relation model.VariantRelation) (uuid.UUID, error) {
+	if id == other {
+		return uuid.Nil, ErrSameSong
+	}
+	m, ok := r.songs[other]
+	if !ok {
+		m = membership{uuid.New(), model.VariantRelationOriginal}
+		r.songs[other] = m
+	}
+	r.songs[id] = membership{m.group, relation}
+	r.deleteEmptyGroups()
+	return m.group, nil
+}
+
+// UnlinkVariant removes the song from its group.
+func (r *fakeRepo) UnlinkVariant(_ context.Context, id uuid.UUID) (bool, error) {
+	if _, ok := r.songs[id]; !ok {
+		return false, nil
+	}
+	delete(r.songs, id)
+	r.deleteEmptyGroups()
+	return true, nil
+}
+
+// deleteEmptyGroups removes the songs of groups with less than two songs.
+func (r *fakeRepo) deleteEmptyGroups() {
+	counts := make(map[uuid.UUID]int)
+	for _, m := range r.songs {
+		counts[m.group]++
+	}
+	for id, m := range r.songs {
+		if counts[m.group] < 2 {
+			delete(r.songs, id)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package variant
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+func Test_fakeRepo_Variants(t *testing.T) {
+	t.Parallel()
+
+	repo := NewFakeRepository().(*fakeRepo)
+	solo, duet, remix := uuid.New(), uuid.New(), uuid.New()
+	group, err := repo.LinkVariant(context.TODO(), duet, solo, model.VariantRelationDuetVersion)
+	if err != nil {
+		t.Fatalf("LinkVariant(ctx, duet, solo, %q) returned an unexpected error: %s", model.VariantRelationDuetVersion, err)
+	}
+	if repo.songs[solo] != (membership{group, model.VariantRelationOriginal}) {
+		t.Errorf("LinkVariant(ctx, duet, solo, %q) did not add solo as the original of the group", model.VariantRelationDuetVersion)
+	}
+	if g, _ := repo.LinkVariant(context.TODO(), remix, duet, model.VariantRelationRemix); g != group {
+		t.Errorf("LinkVariant(ctx, remix, duet, %q) = %s, expected the existing group %s", model.VariantRelationRemix, g, group)
+	}
+	if _, err = repo.LinkVariant(context.TODO(), solo, solo, model.VariantRelationLive); !errors.Is(err, ErrSameSong) {
+		t.Errorf("LinkVariant(ctx, solo, solo, %q) returned %v, expected ErrSameSong", model.VariantRelationLive, err)
+	}
+
+	if ok, _ := repo.UnlinkVariant(context.TODO(), remix); !ok {
+		t.Errorf("UnlinkVariant(ctx, remix) = false, expected true")
+	}
+	if ok, _ := repo.UnlinkVariant(context.TODO(), duet); !ok {
+		t.Errorf("UnlinkVariant(ctx, duet) = false, expected true")
+	}
+	if _, ok := repo.songs[solo]; ok {
+		t.Errorf("UnlinkVariant(ctx, duet) did not delete the group with a single song")
+	}
+}
@@ -0,0 +1,37 @@
+// Package variant groups songs that are variants of each other,
+// such as a solo and a duet chart of the same song or charts of a remix.
+//
+// Each song belongs to at most one variant group.
+// The variants of a song are included in the song itself (see model.Song.Variants).
+package variant
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// ErrSameSong indicates that a song cannot be linked as a variant of itself.
+var ErrSameSong = errors.New("a song cannot be a variant of itself")
+
+// A Repository manages the variant groups of songs.
+// Only songs in the library can be linked, songs in uploads and in the trash are treated as non-existent.
+type Repository interface {
+	// LinkVariant adds the song with UUID id to the variant group of the song with UUID other.
+	// relation describes how the song relates to the group.
+	// If other does not belong to a group yet, a new group is created with other as its original.
+	// If the song already belongs to a different group, it is moved to the group of other.
+	// The UUID of the variant group is returned.
+	//
+	// If either song does not exist, core.ErrNotFound is returned.
+	// If id and other are the same, ErrSameSong is returned.
+	LinkVariant(ctx context.Context, id uuid.UUID, other uuid.UUID, relation model.VariantRelation) (uuid.UUID, error)
+
+	// UnlinkVariant removes the song with UUID id from its variant group.
+	// Groups that contain less than two songs afterward are deleted.
+	// If the song does not belong to a variant group, the first return value is false.
+	UnlinkVariant(ctx context.Context, id uuid.UUID) (bool, error)
+}
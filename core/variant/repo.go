@@ -0,0 +1,108 @@
+package variant
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/internal/dbutil"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// dbRepo is the main Repository implementation, backed by a PostgreSQL database.
+type dbRepo struct {
+	logger *slog.Logger
+	db     pgxutil.DB // database connection
+}
+
+// NewDBRepository creates a new Repository backed by the specified database connection.
+// db can be a single connection or a connection pool.
+func NewDBRepository(logger *slog.Logger, db pgxutil.DB) Repository {
+	return &dbRepo{logger, db}
+}
+
+// LinkVariant adds the song to the variant group of other within a single transaction.
+func (r *dbRepo) LinkVariant(ctx context.Context, id uuid.UUID, other uuid.UUID, relation model.VariantRelation) (uuid.UUID, error) {
+	if id == other {
+		return uuid.Nil, ErrSameSong
+	}
+	var group uuid.UUID
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		songID, err := librarySongID(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		otherID, err := librarySongID(ctx, tx, other)
+		if err != nil {
+			return err
+		}
+		groupID, err := pgxutil.SelectRow(ctx, tx, `SELECT group_id FROM song_variants WHERE song_id = $1`, []any{otherID}, pgx.RowTo[int])
+		if errors.Is(err, pgx.ErrNoRows) {
+			if groupID, err = pgxutil.SelectRow(ctx, tx, `INSERT INTO song_groups DEFAULT VALUES RETURNING id`, nil, pgx.RowTo[int]); err != nil {
+				return err
+			}
+			_, err = tx.Exec(ctx, `INSERT INTO song_variants (song_id, group_id, relation) VALUES ($1, $2, $3)`,
+				otherID, groupID, model.VariantRelationOriginal)
+		}
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec(ctx, `INSERT INTO song_variants (song_id, group_id, relation) VALUES ($1, $2, $3)
+		ON CONFLICT (song_id) DO UPDATE SET group_id = excluded.group_id, relation = excluded.relation`,
+			songID, groupID, relation); err != nil {
+			return err
+		}
+		if err = deleteEmptyGroups(ctx, tx); err != nil {
+			return err
+		}
+		group, err = pgxutil.SelectRow(ctx, tx, `SELECT uuid FROM song_groups WHERE id = $1`, []any{groupID}, pgx.RowTo[uuid.UUID])
+		return err
+	})
+	if err != nil {
+		if err = dbutil.Error(err); !errors.Is(err, core.ErrNotFound) {
+			r.logger.ErrorContext(ctx, "Could not link song variant.", "uuid", id, "other", other, tint.Err(err))
+		}
+		return uuid.Nil, err
+	}
+	return group, nil
+}
+
+// UnlinkVariant removes the song from its variant group within a single transaction.
+func (r *dbRepo) UnlinkVariant(ctx context.Context, id uuid.UUID) (bool, error) {
+	ok := true
+	err := pgx.BeginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		_, err := pgxutil.ExecRow(ctx, tx, `DELETE FROM song_variants WHERE song_id = (SELECT id FROM songs WHERE uuid = $1)`, id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			ok = false
+			return nil
+		} else if err != nil {
+			return err
+		}
+		return deleteEmptyGroups(ctx, tx)
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not unlink song variant.", "uuid", id, tint.Err(err))
+		return false, err
+	}
+	return ok, nil
+}
+
+// librarySongID returns the ID of the library song with the specified UUID.
+// If no such song exists, pgx.ErrNoRows is returned.
+func librarySongID(ctx context.Context, db pgxutil.DB, id uuid.UUID) (int, error) {
+	return pgxutil.SelectRow(ctx, db, `SELECT id FROM songs WHERE uuid = $1 AND upload_id IS NULL AND deleted_at IS NULL`,
+		[]any{id}, pgx.RowTo[int])
+}
+
+// deleteEmptyGroups deletes variant groups with less than two songs.
+func deleteEmptyGroups(ctx context.Context, db pgxutil.DB) error {
+	_, err := db.Exec(ctx, `DELETE FROM song_groups AS g
+	WHERE (SELECT COUNT(*) FROM song_variants AS sv WHERE sv.group_id = g.id) < 2`)
+	return err
+}
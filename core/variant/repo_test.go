@@ -0,0 +1,91 @@
+//go:build database
+
+package variant
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/song"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func Test_dbRepo_LinkVariant(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	songRepo := song.NewDBRepository(nolog.Logger, db)
+	solo := testdata.SimpleSong(t, db)
+	duet := testdata.SimpleSong(t, db)
+	remix := testdata.SimpleSong(t, db)
+
+	t.Run("new group", func(t *testing.T) {
+		group, err := repo.LinkVariant(context.TODO(), duet.UUID, solo.UUID, model.VariantRelationDuetVersion)
+		if err != nil {
+			t.Fatalf("LinkVariant(ctx, duet, solo, %q) returned an unexpected error: %s", model.VariantRelationDuetVersion, err)
+		}
+		actual, _ := songRepo.GetSong(context.TODO(), solo.UUID)
+		if actual.VariantGroup != group || actual.VariantRelation != model.VariantRelationOriginal {
+			t.Errorf("LinkVariant(ctx, duet, solo, %q) produced group %s and relation %q for solo, expected %s and %q", model.VariantRelationDuetVersion, actual.VariantGroup, actual.VariantRelation, group, model.VariantRelationOriginal)
+		}
+		if len(actual.Variants) != 1 || actual.Variants[0].Song != duet.UUID || actual.Variants[0].Relation != model.VariantRelationDuetVersion {
+			t.Errorf("LinkVariant(ctx, duet, solo, %q) produced variants %v for solo, expected [duet]", model.VariantRelationDuetVersion, actual.Variants)
+		}
+	})
+	t.Run("existing group", func(t *testing.T) {
+		group, err := repo.LinkVariant(context.TODO(), remix.UUID, duet.UUID, model.VariantRelationRemix)
+		if err != nil {
+			t.Fatalf("LinkVariant(ctx, remix, duet, %q) returned an unexpected error: %s", model.VariantRelationRemix, err)
+		}
+		actual, _ := songRepo.GetSong(context.TODO(), remix.UUID)
+		if actual.VariantGroup != group || len(actual.Variants) != 2 || actual.Variants[0].Song != solo.UUID {
+			t.Errorf("LinkVariant(ctx, remix, duet, %q) produced variants %v, expected solo and duet", model.VariantRelationRemix, actual.Variants)
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		deleted := testdata.DeletedSong(t, db)
+		for _, id := range []uuid.UUID{uuid.New(), deleted.UUID} {
+			if _, err := repo.LinkVariant(context.TODO(), id, solo.UUID, model.VariantRelationLive); !errors.Is(err, core.ErrNotFound) {
+				t.Errorf("LinkVariant(ctx, %s, solo, %q) returned %v, expected ErrNotFound", id, model.VariantRelationLive, err)
+			}
+		}
+	})
+	t.Run("same song", func(t *testing.T) {
+		if _, err := repo.LinkVariant(context.TODO(), solo.UUID, solo.UUID, model.VariantRelationLive); !errors.Is(err, ErrSameSong) {
+			t.Errorf("LinkVariant(ctx, solo, solo, %q) returned %v, expected ErrSameSong", model.VariantRelationLive, err)
+		}
+	})
+}
+
+func Test_dbRepo_UnlinkVariant(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	songRepo := song.NewDBRepository(nolog.Logger, db)
+	solo := testdata.SimpleSong(t, db)
+	duet := testdata.SimpleSong(t, db)
+	testdata.VariantGroup(t, db, solo.UUID, map[uuid.UUID]model.VariantRelation{duet.UUID: model.VariantRelationDuetVersion})
+
+	ok, err := repo.UnlinkVariant(context.TODO(), duet.UUID)
+	if err != nil {
+		t.Fatalf("UnlinkVariant(ctx, duet) returned an unexpected error: %s", err)
+	}
+	if !ok {
+		t.Errorf("UnlinkVariant(ctx, duet) = false, expected true")
+	}
+	if actual, _ := songRepo.GetSong(context.TODO(), solo.UUID); actual.VariantGroup != uuid.Nil {
+		t.Errorf("UnlinkVariant(ctx, duet) did not delete the group with a single song")
+	}
+	if ok, _ = repo.UnlinkVariant(context.TODO(), duet.UUID); ok {
+		t.Errorf("UnlinkVariant(ctx, duet) a second time = true, expected false")
+	}
+}
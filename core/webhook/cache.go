@@ -0,0 +1,77 @@
+package webhook
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// cachedRepo is a Repository that caches the list of all webhooks.
+// Webhooks are listed for every published event, so the list is read much more often than it changes.
+type cachedRepo struct {
+	Repository
+	ttl time.Duration
+
+	mu      sync.Mutex
+	hooks   []model.Webhook
+	expires time.Time
+}
+
+// NewCachedRepository wraps repo so that listing all webhooks via FindWebhooks(ctx, -1, 0) is cached.
+// The cache is invalidated when webhooks are created, updated or deleted via the returned Repository.
+// Changes made by other means (such as another Karman instance) become visible after at most ttl.
+func NewCachedRepository(repo Repository, ttl time.Duration) Repository {
+	return &cachedRepo{Repository: repo, ttl: ttl}
+}
+
+// FindWebhooks returns the cached list of webhooks if all webhooks are requested.
+// Paginated requests are passed to the underlying repository.
+func (r *cachedRepo) FindWebhooks(ctx context.Context, limit int, offset int64) ([]model.Webhook, int64, error) {
+	if limit != -1 || offset != 0 {
+		return r.Repository.FindWebhooks(ctx, limit, offset)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.hooks == nil || time.Now().After(r.expires) {
+		hooks, _, err := r.Repository.FindWebhooks(ctx, -1, 0)
+		if err != nil {
+			return nil, 0, err
+		}
+		if hooks == nil {
+			hooks = make([]model.Webhook, 0)
+		}
+		r.hooks = hooks
+		r.expires = time.Now().Add(r.ttl)
+	}
+	return slices.Clone(r.hooks), int64(len(r.hooks)), nil
+}
+
+// invalidate clears the cached list of webhooks.
+func (r *cachedRepo) invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks = nil
+}
+
+// CreateWebhook creates the webhook and invalidates the cache.
+func (r *cachedRepo) CreateWebhook(ctx context.Context, hook *model.Webhook) error {
+	defer r.invalidate()
+	return r.Repository.CreateWebhook(ctx, hook)
+}
+
+// UpdateWebhook updates the webhook and invalidates the cache.
+func (r *cachedRepo) UpdateWebhook(ctx context.Context, hook *model.Webhook) error {
+	defer r.invalidate()
+	return r.Repository.UpdateWebhook(ctx, hook)
+}
+
+// DeleteWebhook deletes the webhook and invalidates the cache.
+func (r *cachedRepo) DeleteWebhook(ctx context.Context, id uuid.UUID) (bool, error) {
+	defer r.invalidate()
+	return r.Repository.DeleteWebhook(ctx, id)
+}
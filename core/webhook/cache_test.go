@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// countingRepo is a Repository that counts the calls to FindWebhooks.
+type countingRepo struct {
+	Repository
+	calls int
+}
+
+// FindWebhooks counts the call and passes it to the underlying repository.
+func (r *countingRepo) FindWebhooks(ctx context.Context, limit int, offset int64) ([]model.Webhook, int64, error) {
+	r.calls++
+	return r.Repository.FindWebhooks(ctx, limit, offset)
+}
+
+func Test_cachedRepo_FindWebhooks(t *testing.T) {
+	t.Parallel()
+
+	inner := &countingRepo{Repository: NewFakeRepository()}
+	repo := NewCachedRepository(inner, time.Hour)
+	_ = repo.CreateWebhook(context.TODO(), &model.Webhook{URL: "https://example.com/a"})
+
+	for i := 0; i < 3; i++ {
+		if hooks, _, _ := repo.FindWebhooks(context.TODO(), -1, 0); len(hooks) != 1 {
+			t.Fatalf("FindWebhooks(ctx, -1, 0) returned %d webhooks, expected %d", len(hooks), 1)
+		}
+	}
+	if inner.calls != 1 {
+		t.Errorf("FindWebhooks(ctx, -1, 0) queried the repository %d times, expected %d", inner.calls, 1)
+	}
+
+	hook := model.Webhook{URL: "https://example.com/b"}
+	_ = repo.CreateWebhook(context.TODO(), &hook)
+	if hooks, _, _ := repo.FindWebhooks(context.TODO(), -1, 0); len(hooks) != 2 {
+		t.Errorf("FindWebhooks(ctx, -1, 0) returned %d webhooks after CreateWebhook, expected %d", len(hooks), 2)
+	}
+	_, _ = repo.DeleteWebhook(context.TODO(), hook.UUID)
+	if hooks, _, _ := repo.FindWebhooks(context.TODO(), -1, 0); len(hooks) != 1 {
+		t.Errorf("FindWebhooks(ctx, -1, 0) returned %d webhooks after DeleteWebhook, expected %d", len(hooks), 1)
+	}
+	if inner.calls != 3 {
+		t.Errorf("FindWebhooks(ctx, -1, 0) queried the repository %d times, expected %d", inner.calls, 3)
+	}
+}
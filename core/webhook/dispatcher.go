@@ -0,0 +1,47 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/core/event"
+)
+
+// dispatcher is an event.Bus that schedules webhook deliveries for all published events.
+type dispatcher struct {
+	event.Bus
+	logger *slog.Logger
+	repo   Repository
+	queue  Queue
+}
+
+// NewDispatcher wraps bus so that every event published to it is also delivered to all matching webhooks.
+// Deliveries are scheduled via queue.
+// Subscriptions are handled by bus directly.
+func NewDispatcher(logger *slog.Logger, bus event.Bus, repo Repository, queue Queue) event.Bus {
+	return &dispatcher{bus, logger, repo, queue}
+}
+
+// Publish publishes e to the underlying bus and enqueues deliveries for all webhooks accepting e.
+func (d *dispatcher) Publish(ctx context.Context, e event.Event) error {
+	e.Prepare()
+	err := d.Bus.Publish(ctx, e)
+	hooks, _, fErr := d.repo.FindWebhooks(ctx, -1, 0)
+	if fErr != nil {
+		d.logger.ErrorContext(ctx, "Could not list webhooks for event.", "type", e.Type, tint.Err(fErr))
+		return errors.Join(err, fErr)
+	}
+	for _, hook := range hooks {
+		if !hook.Accepts(string(e.Type)) {
+			continue
+		}
+		if qErr := d.queue.EnqueueDelivery(ctx, hook.UUID, e); qErr != nil {
+			d.logger.ErrorContext(ctx, "Could not enqueue webhook delivery.", "uuid", hook.UUID, "type", e.Type, tint.Err(qErr))
+			err = errors.Join(err, qErr)
+		}
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+)
+
+// fakeQueue is a Queue that records all enqueued deliveries.
+type fakeQueue map[uuid.UUID][]event.Event
+
+// EnqueueDelivery records the delivery.
+func (q fakeQueue) EnqueueDelivery(_ context.Context, id uuid.UUID, e event.Event) error {
+	q[id] = append(q[id], e)
+	return nil
+}
+
+func Test_dispatcher_Publish(t *testing.T) {
+	t.Parallel()
+
+	repo := NewFakeRepository()
+	all := model.Webhook{URL: "https://example.com/all"}
+	songs := model.Webhook{URL: "https://example.com/songs", Events: []string{"song"}}
+	uploads := model.Webhook{URL: "https://example.com/uploads", Events: []string{string(event.TypeUploadDone)}}
+	for _, hook := range []*model.Webhook{&all, &songs, &uploads} {
+		_ = repo.CreateWebhook(context.TODO(), hook)
+	}
+	queue := make(fakeQueue)
+	bus := NewDispatcher(nolog.Logger, event.NewMemBus(), repo, queue)
+
+	e := event.SongDeleted(uuid.New())
+	if err := bus.Publish(context.TODO(), e); err != nil {
+		t.Fatalf("Publish(ctx, e) returned an unexpected error: %s", err)
+	}
+	if len(queue[all.UUID]) != 1 {
+		t.Errorf("Publish(ctx, e) enqueued %d deliveries for a webhook without filters, expected %d", len(queue[all.UUID]), 1)
+	}
+	if len(queue[songs.UUID]) != 1 {
+		t.Errorf("Publish(ctx, e) enqueued %d deliveries for a song webhook, expected %d", len(queue[songs.UUID]), 1)
+	} else if queue[songs.UUID][0].ID != e.ID {
+		t.Errorf("Publish(ctx, e) enqueued event %s, expected %s", queue[songs.UUID][0].ID, e.ID)
+	}
+	if len(queue[uploads.UUID]) != 0 {
+		t.Errorf("Publish(ctx, e) enqueued %d deliveries for an upload webhook, expected %d", len(queue[uploads.UUID]), 0)
+	}
+}
@@ -0,0 +1,111 @@
+package webhook
+
+import (
+	"context"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// fakeRepo is a simple implementation of Repository that can be used for testing.
+type fakeRepo struct {
+	// hooks is the "database" of a fakeRepo.
+	hooks map[uuid.UUID]model.Webhook
+	// deliveries contains the delivery logs of webhooks in chronological order.
+	deliveries map[uuid.UUID][]model.WebhookDelivery
+}
+
+// NewFakeRepository returns a new Repository implementation backed by in-memory maps.
+func NewFakeRepository() Repository {
+	return &fakeRepo{
+		make(map[uuid.UUID]model.Webhook),
+		make(map[uuid.UUID][]model.WebhookDelivery),
+	}
+}
+
+// CreateWebhook stores the webhook and sets its UUID, CreatedAt, and UpdatedAt fields.
+func (r *fakeRepo) CreateWebhook(_ context.Context, hook *model.Webhook) error {
+	hook.UUID = uuid.New()
+	hook.CreatedAt = time.Now()
+	hook.UpdatedAt = hook.CreatedAt
+	r.hooks[hook.UUID] = *hook
+	return nil
+}
+
+// GetWebhook looks up the webhook with the specified UUID.
+func (r *fakeRepo) GetWebhook(_ context.Context, id uuid.UUID) (model.Webhook, error) {
+	hook, ok := r.hooks[id]
+	if !ok {
+		return model.Webhook{}, core.ErrNotFound
+	}
+	return hook, nil
+}
+
+// FindWebhooks returns a list of webhooks limited by the specified pagination parameters.
+func (r *fakeRepo) FindWebhooks(_ context.Context, limit int, offset int64) ([]model.Webhook, int64, error) {
+	if limit < 0 {
+		limit = math.MaxInt
+	}
+	hooks := make([]model.Webhook, 0)
+	idx := int64(0)
+	for _, hook := range r.hooks {
+		if idx < offset {
+			idx++
+			continue
+		}
+		if len(hooks) >= limit {
+			break
+		}
+		hooks = append(hooks, hook)
+	}
+	return hooks, int64(len(r.hooks)), nil
+}
+
+// UpdateWebhook updates the data of hook.
+func (r *fakeRepo) UpdateWebhook(_ context.Context, hook *model.Webhook) error {
+	if _, ok := r.hooks[hook.UUID]; !ok {
+		return core.ErrNotFound
+	}
+	hook.UpdatedAt = time.Now()
+	r.hooks[hook.UUID] = *hook
+	return nil
+}
+
+// DeleteWebhook deletes the webhook with the specified UUID (if it exists) and its delivery log.
+func (r *fakeRepo) DeleteWebhook(_ context.Context, id uuid.UUID) (bool, error) {
+	_, ok := r.hooks[id]
+	delete(r.hooks, id)
+	delete(r.deliveries, id)
+	return ok, nil
+}
+
+// CreateDelivery appends delivery to the log of the webhook.
+func (r *fakeRepo) CreateDelivery(_ context.Context, id uuid.UUID, delivery *model.WebhookDelivery) error {
+	if _, ok := r.hooks[id]; !ok {
+		return core.ErrNotFound
+	}
+	delivery.UUID = uuid.New()
+	delivery.CreatedAt = time.Now()
+	r.deliveries[id] = append(r.deliveries[id], *delivery)
+	return nil
+}
+
+// FindDeliveries returns the delivery log of the webhook, newest deliveries first.
+func (r *fakeRepo) FindDeliveries(_ context.Context, id uuid.UUID, limit int, offset int64) ([]model.WebhookDelivery, int64, error) {
+	deliveries := slices.Clone(r.deliveries[id])
+	slices.Reverse(deliveries)
+	total := int64(len(deliveries))
+	if offset > total {
+		offset = total
+	}
+	deliveries = deliveries[offset:]
+	if limit >= 0 && limit < len(deliveries) {
+		deliveries = deliveries[:limit]
+	}
+	return deliveries, total, nil
+}
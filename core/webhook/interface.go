@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// Service provides an interface for delivering events to webhooks.
+type Service interface {
+	// Deliver sends e to the webhook with the specified UUID and records the delivery attempt.
+	// attempt is the 1-based number of the delivery attempt and is recorded in the delivery log.
+	//
+	// If the webhook does not exist, core.ErrNotFound is returned.
+	// If the webhook could not be reached or did not respond with a 2xx status code, an error is returned.
+	Deliver(ctx context.Context, id uuid.UUID, e event.Event, attempt int) error
+}
+
+// Repository provides methods for storing webhooks and their delivery logs.
+type Repository interface {
+	// CreateWebhook creates a new webhook with the specified data.
+	// This method must set hook.UUID, hook.CreatedAt, and hook.UpdatedAt appropriately.
+	CreateWebhook(ctx context.Context, hook *model.Webhook) error
+
+	// GetWebhook fetches the webhook with the specified UUID.
+	// If no such webhook exists, core.ErrNotFound will be returned.
+	GetWebhook(ctx context.Context, id uuid.UUID) (model.Webhook, error)
+
+	// FindWebhooks gives a paginated view to all webhooks.
+	// If limit is -1, all webhooks are returned.
+	// This method returns the page contents, the total number of webhooks and an error (if one occurred).
+	FindWebhooks(ctx context.Context, limit int, offset int64) ([]model.Webhook, int64, error)
+
+	// UpdateWebhook saves updates for the specified webhook.
+	// The UUID of the webhook must already exist in the database, otherwise core.ErrNotFound will be returned.
+	UpdateWebhook(ctx context.Context, hook *model.Webhook) error
+
+	// DeleteWebhook deletes the webhook with the specified UUID, if it exists.
+	// The delivery log of the webhook is deleted as well.
+	// If no such webhook exists, the first return value will be false.
+	DeleteWebhook(ctx context.Context, id uuid.UUID) (bool, error)
+
+	// CreateDelivery adds a delivery attempt to the log of the webhook with the specified UUID.
+	// This method must set delivery.UUID and delivery.CreatedAt appropriately.
+	CreateDelivery(ctx context.Context, id uuid.UUID, delivery *model.WebhookDelivery) error
+
+	// FindDeliveries returns a paginated view of the delivery log of the webhook with the specified UUID.
+	// The newest deliveries are returned first.
+	// The second return value contains the total number of deliveries for the webhook.
+	FindDeliveries(ctx context.Context, id uuid.UUID, limit int, offset int64) ([]model.WebhookDelivery, int64, error)
+}
+
+// A Queue schedules deliveries of events to webhooks.
+// Deliveries are processed asynchronously, usually by calling Service.Deliver.
+type Queue interface {
+	// EnqueueDelivery schedules the delivery of e to the webhook with the specified UUID.
+	EnqueueDelivery(ctx context.Context, id uuid.UUID, e event.Event) error
+}
@@ -0,0 +1,231 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgxutil"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/core/internal/dbutil"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// dbRepo is the main Repository implementation, backed by a PostgreSQL database.
+type dbRepo struct {
+	logger *slog.Logger
+	db     pgxutil.DB
+}
+
+// NewDBRepository creates a new Repository backed by the specified database connection.
+// db can be a single connection or a connection pool.
+func NewDBRepository(logger *slog.Logger, db pgxutil.DB) Repository {
+	return &dbRepo{logger, db}
+}
+
+// webhookRow is the data returned by a SELECT query for webhooks.
+type webhookRow struct {
+	UUID      uuid.UUID
+	CreatedAt time.Time        `db:"created_at"`
+	UpdatedAt time.Time        `db:"updated_at"`
+	DeletedAt pgtype.Timestamp `db:"deleted_at"`
+	URL       string
+	Secret    string
+	Events    []string
+}
+
+// toModel converts r to an equivalent model.Webhook.
+func (r webhookRow) toModel() model.Webhook {
+	hook := model.Webhook{
+		Model: model.Model{
+			UUID:      r.UUID,
+			CreatedAt: r.CreatedAt,
+			UpdatedAt: r.UpdatedAt,
+		},
+		URL:    r.URL,
+		Secret: r.Secret,
+		Events: r.Events,
+	}
+	if r.DeletedAt.Valid {
+		hook.DeletedAt = r.DeletedAt.Time
+	}
+	return hook
+}
+
+// deliveryRow is the data returned by a SELECT query for webhook deliveries.
+type deliveryRow struct {
+	UUID       uuid.UUID
+	CreatedAt  time.Time `db:"created_at"`
+	EventID    uuid.UUID `db:"event_id"`
+	EventType  string    `db:"event_type"`
+	Attempt    int
+	StatusCode int `db:"status_code"`
+	Error      string
+	Duration   time.Duration
+}
+
+// toModel converts r to an equivalent model.WebhookDelivery.
+func (r deliveryRow) toModel() model.WebhookDelivery {
+	return model.WebhookDelivery{
+		UUID:       r.UUID,
+		CreatedAt:  r.CreatedAt,
+		EventID:    r.EventID,
+		EventType:  r.EventType,
+		Attempt:    r.Attempt,
+		StatusCode: r.StatusCode,
+		Error:      r.Error,
+		Duration:   r.Duration,
+	}
+}
+
+// CreateWebhook creates a new webhook in the database.
+func (r *dbRepo) CreateWebhook(ctx context.Context, hook *model.Webhook) error {
+	prepareWebhook(hook)
+	row, err := pgxutil.InsertRowReturning(ctx, r.db, "webhooks", map[string]any{
+		"url":    hook.URL,
+		"secret": hook.Secret,
+		"events": hook.Events,
+	}, "uuid, created_at, updated_at, deleted_at, url, secret, events", pgx.RowToStructByName[webhookRow])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not create webhook.", tint.Err(err))
+		return err
+	}
+	*hook = row.toModel()
+	return nil
+}
+
+// GetWebhook fetches a webhook from the database.
+func (r *dbRepo) GetWebhook(ctx context.Context, id uuid.UUID) (model.Webhook, error) {
+	row, err := pgxutil.SelectRow(ctx, r.db, `SELECT
+    uuid, created_at, updated_at, deleted_at, url, secret, events
+	FROM webhooks
+	WHERE uuid = $1`, []any{id}, pgx.RowToStructByName[webhookRow])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not fetch webhook.", "uuid", id, tint.Err(err))
+		}
+		return model.Webhook{}, dbutil.Error(err)
+	}
+	return row.toModel(), nil
+}
+
+// FindWebhooks lists webhooks with pagination.
+func (r *dbRepo) FindWebhooks(ctx context.Context, limit int, offset int64) ([]model.Webhook, int64, error) {
+	total, err := pgxutil.SelectRow(ctx, r.db, `SELECT COUNT(*) FROM webhooks`, nil, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not count webhooks.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, 0, err
+	}
+	hooks, err := pgxutil.Select(ctx, r.db, `SELECT
+    uuid, created_at, updated_at, deleted_at, url, secret, events
+	FROM webhooks
+	ORDER BY id
+	LIMIT CASE WHEN $1 < 0 THEN NULL ELSE $1 END OFFSET $2`, []any{limit, offset}, func(row pgx.CollectableRow) (model.Webhook, error) {
+		data, err := pgx.RowToStructByName[webhookRow](row)
+		return data.toModel(), err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list webhooks.", "limit", limit, "offset", offset, tint.Err(err))
+		return nil, total, err
+	}
+	return hooks, total, nil
+}
+
+// UpdateWebhook updates the webhook in the database with hook.UUID.
+func (r *dbRepo) UpdateWebhook(ctx context.Context, hook *model.Webhook) error {
+	prepareWebhook(hook)
+	updatedAt, err := pgxutil.UpdateRowReturning(ctx, r.db, "webhooks", map[string]any{
+		"url":    hook.URL,
+		"secret": hook.Secret,
+		"events": hook.Events,
+	}, map[string]any{
+		"uuid": hook.UUID,
+	}, "updated_at", pgx.RowTo[time.Time])
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			r.logger.ErrorContext(ctx, "Could not update webhook.", "uuid", hook.UUID, tint.Err(err))
+		}
+		return dbutil.Error(err)
+	}
+	hook.UpdatedAt = updatedAt
+	return nil
+}
+
+// DeleteWebhook deletes the webhook with the specified UUID.
+// The database schema takes care of deleting the delivery log.
+func (r *dbRepo) DeleteWebhook(ctx context.Context, id uuid.UUID) (bool, error) {
+	_, err := pgxutil.ExecRow(ctx, r.db, `DELETE FROM webhooks WHERE uuid = $1`, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not delete webhook.", "uuid", id, tint.Err(err))
+		return false, err
+	}
+	return true, nil
+}
+
+// CreateDelivery adds a delivery to the log of a webhook.
+func (r *dbRepo) CreateDelivery(ctx context.Context, id uuid.UUID, delivery *model.WebhookDelivery) error {
+	row, err := pgxutil.SelectRow(ctx, r.db, `INSERT INTO webhook_deliveries
+    (webhook_id, event_id, event_type, attempt, status_code, error, duration)
+	VALUES ((SELECT webhooks.id FROM webhooks WHERE uuid = $1), $2, $3, $4, $5, $6, $7)
+	RETURNING uuid, created_at`, []any{
+		id,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.Attempt,
+		delivery.StatusCode,
+		delivery.Error,
+		delivery.Duration,
+	}, pgx.RowToStructByName[struct {
+		UUID      uuid.UUID
+		CreatedAt time.Time `db:"created_at"`
+	}])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not create webhook delivery.", "uuid", id, tint.Err(err))
+		return dbutil.Error(err)
+	}
+	delivery.UUID = row.UUID
+	delivery.CreatedAt = row.CreatedAt
+	return nil
+}
+
+// FindDeliveries lists the deliveries of a webhook with pagination.
+func (r *dbRepo) FindDeliveries(ctx context.Context, id uuid.UUID, limit int, offset int64) ([]model.WebhookDelivery, int64, error) {
+	total, err := pgxutil.SelectRow(ctx, r.db, `SELECT COUNT(*)
+	FROM webhook_deliveries
+	INNER JOIN webhooks ON webhook_deliveries.webhook_id = webhooks.id
+	WHERE webhooks.uuid = $1`, []any{id}, pgx.RowTo[int64])
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not count webhook deliveries.", "uuid", id, "limit", limit, "offset", offset, tint.Err(err))
+		return nil, 0, err
+	}
+	deliveries, err := pgxutil.Select(ctx, r.db, `SELECT
+    d.uuid, d.created_at, d.event_id, d.event_type, d.attempt, d.status_code, d.error, d.duration
+	FROM webhook_deliveries d
+	INNER JOIN webhooks ON d.webhook_id = webhooks.id
+	WHERE webhooks.uuid = $1
+	ORDER BY d.created_at DESC, d.id DESC
+	LIMIT CASE WHEN $2 < 0 THEN NULL ELSE $2 END OFFSET $3`, []any{id, limit, offset}, func(row pgx.CollectableRow) (model.WebhookDelivery, error) {
+		data, err := pgx.RowToStructByName[deliveryRow](row)
+		return data.toModel(), err
+	})
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Could not list webhook deliveries.", "uuid", id, "limit", limit, "offset", offset, tint.Err(err))
+		return nil, total, err
+	}
+	return deliveries, total, nil
+}
+
+// prepareWebhook ensures that non-null fields are set to appropriate zero values.
+func prepareWebhook(hook *model.Webhook) {
+	if hook.Events == nil {
+		hook.Events = make([]string, 0)
+	}
+}
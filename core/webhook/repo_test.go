@@ -0,0 +1,144 @@
+//go:build database
+
+package webhook
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+	"github.com/Karaoke-Manager/karman/test"
+	testdata "github.com/Karaoke-Manager/karman/test/data"
+)
+
+func Test_dbRepo_CreateWebhook(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+
+	hook := model.Webhook{URL: "https://example.com", Events: []string{"song"}}
+	if err := repo.CreateWebhook(context.TODO(), &hook); err != nil {
+		t.Fatalf("CreateWebhook(ctx, &hook) returned an unexpected error: %s", err)
+	}
+	if hook.UUID == uuid.Nil {
+		t.Errorf("CreateWebhook(ctx, &hook) produced hook.UUID = <uuid.Nil>, expected a valid UUID")
+	}
+	if hook.CreatedAt.IsZero() {
+		t.Errorf("CreateWebhook(ctx, &hook) produced hook.CreatedAt = 0, expected a valid date")
+	}
+	if !slices.Equal(hook.Events, []string{"song"}) {
+		t.Errorf("CreateWebhook(ctx, &hook) produced hook.Events = %v, expected %v", hook.Events, []string{"song"})
+	}
+}
+
+func Test_dbRepo_GetWebhook(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	expected := testdata.Webhook(t, db)
+
+	t.Run("existing", func(t *testing.T) {
+		hook, err := repo.GetWebhook(context.TODO(), expected.UUID)
+		if err != nil {
+			t.Fatalf("GetWebhook(ctx, %q) returned an unexpected error: %s", expected.UUID, err)
+		}
+		if hook.URL != expected.URL || hook.Secret != expected.Secret {
+			t.Errorf("GetWebhook(ctx, %q) = %v, expected %v", expected.UUID, hook, expected)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		id := uuid.New()
+		_, err := repo.GetWebhook(context.TODO(), id)
+		if !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("GetWebhook(ctx, %q) returned an unexpected error: %s, expected %s", id, err, core.ErrNotFound)
+		}
+	})
+}
+
+func Test_dbRepo_UpdateWebhook(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	hook := testdata.Webhook(t, db)
+
+	hook.URL = "https://example.org"
+	if err := repo.UpdateWebhook(context.TODO(), &hook); err != nil {
+		t.Fatalf("UpdateWebhook(ctx, &hook) returned an unexpected error: %s", err)
+	}
+	updated, _ := repo.GetWebhook(context.TODO(), hook.UUID)
+	if updated.URL != hook.URL {
+		t.Errorf("UpdateWebhook(ctx, &hook) did not update the URL, got %q, expected %q", updated.URL, hook.URL)
+	}
+}
+
+func Test_dbRepo_DeleteWebhook(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	hook := testdata.Webhook(t, db)
+
+	ok, err := repo.DeleteWebhook(context.TODO(), hook.UUID)
+	if err != nil {
+		t.Errorf("DeleteWebhook(ctx, %q) returned an unexpected error: %s", hook.UUID, err)
+	}
+	if !ok {
+		t.Errorf("DeleteWebhook(ctx, %q) = false, expected true", hook.UUID)
+	}
+	ok, err = repo.DeleteWebhook(context.TODO(), hook.UUID)
+	if err != nil {
+		t.Errorf("DeleteWebhook(ctx, %q) returned an unexpected error: %s", hook.UUID, err)
+	}
+	if ok {
+		t.Errorf("DeleteWebhook(ctx, %q) = true, expected false", hook.UUID)
+	}
+}
+
+func Test_dbRepo_Deliveries(t *testing.T) {
+	t.Parallel()
+
+	db := test.NewDB(t)
+	repo := NewDBRepository(nolog.Logger, db)
+	hook := testdata.Webhook(t, db)
+
+	for i := 1; i <= 3; i++ {
+		delivery := model.WebhookDelivery{
+			EventID:    uuid.New(),
+			EventType:  "song.created",
+			Attempt:    i,
+			StatusCode: 500,
+			Duration:   time.Second,
+		}
+		if err := repo.CreateDelivery(context.TODO(), hook.UUID, &delivery); err != nil {
+			t.Fatalf("CreateDelivery(ctx, %q, &delivery) returned an unexpected error: %s", hook.UUID, err)
+		}
+		if delivery.UUID == uuid.Nil {
+			t.Errorf("CreateDelivery(ctx, %q, &delivery) did not set delivery.UUID, expected a valid UUID", hook.UUID)
+		}
+	}
+
+	deliveries, total, err := repo.FindDeliveries(context.TODO(), hook.UUID, 2, 0)
+	if err != nil {
+		t.Fatalf("FindDeliveries(ctx, %q, 2, 0) returned an unexpected error: %s", hook.UUID, err)
+	}
+	if total != 3 {
+		t.Errorf("FindDeliveries(ctx, %q, 2, 0) returned total = %d, expected %d", hook.UUID, total, 3)
+	}
+	if len(deliveries) != 2 {
+		t.Fatalf("FindDeliveries(ctx, %q, 2, 0) returned %d deliveries, expected %d", hook.UUID, len(deliveries), 2)
+	}
+	if deliveries[0].Attempt != 3 {
+		t.Errorf("FindDeliveries(ctx, %q, 2, 0) returned attempt %d first, expected %d", hook.UUID, deliveries[0].Attempt, 3)
+	}
+}
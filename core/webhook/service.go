@@ -0,0 +1,139 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// These are the HTTP headers that are sent with each webhook request.
+const (
+	// HeaderEvent contains the type of the delivered event.
+	HeaderEvent = "X-Karman-Event"
+	// HeaderDelivery contains the ID of the delivered event.
+	// Multiple delivery attempts of the same event use the same ID.
+	HeaderDelivery = "X-Karman-Delivery"
+	// HeaderSignature contains the signature of the request body.
+	// See Sign for details.
+	HeaderSignature = "X-Karman-Signature-256"
+)
+
+// service is the default Service implementation.
+type service struct {
+	logger *slog.Logger
+	repo   Repository
+	client *http.Client
+}
+
+// NewService creates a new Service instance that delivers webhooks using client.
+// If client is nil, a default client with a timeout of 10 seconds is used.
+func NewService(logger *slog.Logger, repo Repository, client *http.Client) Service {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &service{logger, repo, client}
+}
+
+// Sign computes the signature of a webhook payload.
+// The signature is the hex encoded HMAC-SHA256 of body using secret as key, prefixed with "sha256=".
+// Receivers of webhooks should compute the same signature and compare it to the HeaderSignature header.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidFilter reports whether filter is a known event type or the category of a known event type.
+// See model.Webhook for the semantics of filters.
+func ValidFilter(filter string) bool {
+	for _, t := range event.Types {
+		category, _, _ := strings.Cut(string(t), ".")
+		if filter == string(t) || filter == category {
+			return true
+		}
+	}
+	return false
+}
+
+// NewSecret generates a new random secret suitable for signing webhook payloads.
+func NewSecret() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand does not fail on supported platforms.
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// Deliver sends e to the webhook and records the attempt in the delivery log.
+func (s *service) Deliver(ctx context.Context, id uuid.UUID, e event.Event, attempt int) error {
+	hook, err := s.repo.GetWebhook(ctx, id)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encoding event: %w", err)
+	}
+
+	delivery := model.WebhookDelivery{
+		EventID:   e.ID,
+		EventType: string(e.Type),
+		Attempt:   attempt,
+	}
+	start := time.Now()
+	delivery.StatusCode, err = s.send(ctx, hook, e, body)
+	delivery.Duration = time.Since(start)
+	if err == nil && !delivery.Successful() {
+		err = fmt.Errorf("webhook responded with status %d", delivery.StatusCode)
+	}
+	if err != nil {
+		delivery.Error = err.Error()
+		s.logger.WarnContext(ctx, "Could not deliver webhook.", "uuid", hook.UUID, "event", e.ID, "attempt", attempt, tint.Err(err))
+	}
+	if cErr := s.repo.CreateDelivery(ctx, hook.UUID, &delivery); cErr != nil {
+		// A failure to record the delivery does not affect the result of the delivery.
+		// In particular, successful deliveries should not be retried.
+		s.logger.ErrorContext(ctx, "Could not record webhook delivery.", "uuid", hook.UUID, "event", e.ID, tint.Err(cErr))
+	}
+	return err
+}
+
+// send performs the HTTP request for a delivery and returns the response status code.
+func (s *service) send(ctx context.Context, hook model.Webhook, e event.Event, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Karman-Webhook")
+	req.Header.Set(HeaderEvent, string(e.Type))
+	req.Header.Set(HeaderDelivery, e.ID.String())
+	if hook.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(hook.Secret, body))
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain the body so that the connection can be reused.
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	return resp.StatusCode, nil
+}
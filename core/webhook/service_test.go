@@ -0,0 +1,106 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/model"
+	"github.com/Karaoke-Manager/karman/pkg/nolog"
+)
+
+func TestSign(t *testing.T) {
+	t.Parallel()
+
+	// Reference value computed with: echo -n 'Hello World' | openssl dgst -sha256 -hmac secret
+	expected := "sha256=82ce0d2f821fa0ce5447b21306f214c99240fecc6387779d7515148bbdd0c415"
+	if actual := Sign("secret", []byte("Hello World")); actual != expected {
+		t.Errorf("Sign(%q, %q) = %q, expected %q", "secret", "Hello World", actual, expected)
+	}
+}
+
+func TestValidFilter(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]bool{
+		"song":          true,
+		"song.created":  true,
+		"guest":         true,
+		"guest.queue":   true,
+		"songs":         false,
+		"song.create":   false,
+		"song.":         false,
+		"":              false,
+		"Song.Created":  false,
+		"upload.done.x": false,
+	}
+	for filter, expected := range cases {
+		if actual := ValidFilter(filter); actual != expected {
+			t.Errorf("ValidFilter(%q) = %t, expected %t", filter, actual, expected)
+		}
+	}
+}
+
+func Test_service_Deliver(t *testing.T) {
+	t.Parallel()
+
+	var status = http.StatusNoContent
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	repo := NewFakeRepository()
+	svc := NewService(nolog.Logger, repo, server.Client())
+	hook := model.Webhook{URL: server.URL, Secret: "secret"}
+	_ = repo.CreateWebhook(context.TODO(), &hook)
+	e := event.SongCreated(model.Song{Model: model.Model{UUID: uuid.New()}})
+
+	t.Run("success", func(t *testing.T) {
+		if err := svc.Deliver(context.TODO(), hook.UUID, e, 1); err != nil {
+			t.Fatalf("Deliver(ctx, %q, e, 1) returned an unexpected error: %s", hook.UUID, err)
+		}
+		if actual := received.Header.Get(HeaderEvent); actual != string(event.TypeSongCreated) {
+			t.Errorf("Deliver(ctx, %q, e, 1) sent %s = %q, expected %q", hook.UUID, HeaderEvent, actual, event.TypeSongCreated)
+		}
+		if actual, expected := received.Header.Get(HeaderSignature), Sign("secret", body); actual != expected {
+			t.Errorf("Deliver(ctx, %q, e, 1) sent %s = %q, expected %q", hook.UUID, HeaderSignature, actual, expected)
+		}
+		deliveries, _, _ := repo.FindDeliveries(context.TODO(), hook.UUID, -1, 0)
+		if len(deliveries) != 1 {
+			t.Fatalf("Deliver(ctx, %q, e, 1) recorded %d deliveries, expected %d", hook.UUID, len(deliveries), 1)
+		}
+		if !deliveries[0].Successful() {
+			t.Errorf("Deliver(ctx, %q, e, 1) recorded an unsuccessful delivery, expected success", hook.UUID)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		status = http.StatusInternalServerError
+		if err := svc.Deliver(context.TODO(), hook.UUID, e, 2); err == nil {
+			t.Fatalf("Deliver(ctx, %q, e, 2) did not return an error, expected an error", hook.UUID)
+		}
+		deliveries, _, _ := repo.FindDeliveries(context.TODO(), hook.UUID, 1, 0)
+		if deliveries[0].StatusCode != http.StatusInternalServerError || deliveries[0].Error == "" || deliveries[0].Attempt != 2 {
+			t.Errorf("Deliver(ctx, %q, e, 2) recorded %+v, expected a failed second attempt", hook.UUID, deliveries[0])
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		id := uuid.New()
+		if err := svc.Deliver(context.TODO(), id, e, 1); !errors.Is(err, core.ErrNotFound) {
+			t.Errorf("Deliver(ctx, %q, e, 1) returned an unexpected error: %v, expected %s", id, err, core.ErrNotFound)
+		}
+	})
+}
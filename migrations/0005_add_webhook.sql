@@ -0,0 +1,43 @@
+-- +goose Up
+-- Table webhooks stores external endpoints that get notified about events.
+CREATE TABLE webhooks
+(
+    LIKE entity INCLUDING ALL,
+
+    url    TEXT   NOT NULL,
+    secret TEXT   NOT NULL DEFAULT '',
+    events TEXT[] NOT NULL DEFAULT '{}'
+);
+
+-- Table webhook_deliveries stores the delivery log of webhooks.
+-- Each row represents a single delivery attempt.
+-- Deliveries are never updated so this table is not created from the entity table.
+CREATE TABLE webhook_deliveries
+(
+    id          INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    uuid        UUID UNIQUE NOT NULL DEFAULT uuid_generate_v4(),
+    created_at  TIMESTAMP   NOT NULL DEFAULT NOW(),
+
+    webhook_id  INT         NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+    event_id    UUID        NOT NULL,
+    event_type  TEXT        NOT NULL,
+    attempt     INT         NOT NULL DEFAULT 1,
+    status_code INT         NOT NULL DEFAULT 0,
+    error       TEXT        NOT NULL DEFAULT '',
+    duration    INTERVAL    NOT NULL DEFAULT '0'::INTERVAL
+);
+
+CREATE INDEX webhook_deliveries_webhook_id_created_at_idx ON webhook_deliveries (webhook_id, created_at);
+
+-- Trigger updated_at sets webhooks.updated_at during updates.
+CREATE TRIGGER updated_at
+    BEFORE UPDATE
+    ON webhooks
+    FOR EACH ROW
+EXECUTE PROCEDURE tg_set_updated_at();
+
+
+-- +goose Down
+DROP TRIGGER IF EXISTS updated_at ON webhooks;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhooks;
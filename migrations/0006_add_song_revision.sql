@@ -0,0 +1,56 @@
+-- +goose Up
+-- Table song_revisions stores snapshots of the metadata and notes of songs.
+-- A new revision is recorded whenever a song is changed.
+-- Revisions are never updated so this table is not created from the entity table.
+CREATE TABLE song_revisions
+(
+    id                INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    uuid              UUID UNIQUE NOT NULL DEFAULT uuid_generate_v4(),
+    created_at        TIMESTAMP   NOT NULL DEFAULT NOW(),
+
+    song_id           INTEGER     NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    author            TEXT        NOT NULL DEFAULT '',
+
+    bpm               FLOAT       NOT NULL DEFAULT 0,
+    gap               INTERVAL    NOT NULL DEFAULT '0'::INTERVAL,
+    video_gap         INTERVAL    NOT NULL DEFAULT '0'::INTERVAL,
+    start             INTERVAL    NOT NULL DEFAULT '0'::INTERVAL,
+    "end"             INTERVAL    NOT NULL DEFAULT '0'::INTERVAL,
+    preview_start     INTERVAL    NOT NULL DEFAULT '0'::INTERVAL,
+    medley_start_beat INTEGER     NOT NULL DEFAULT 0,
+    medley_end_beat   INTEGER     NOT NULL DEFAULT 0,
+    manual_medley     BOOLEAN     NOT NULL DEFAULT FALSE,
+
+    title             TEXT        NOT NULL DEFAULT '',
+    artists           TEXT[]      NOT NULL DEFAULT '{}'::TEXT[],
+    genre             TEXT        NOT NULL DEFAULT '',
+    edition           TEXT        NOT NULL DEFAULT '',
+    creator           TEXT        NOT NULL DEFAULT '',
+    language          TEXT        NOT NULL DEFAULT '',
+    year              INT         NOT NULL DEFAULT 0,
+    comment           TEXT        NOT NULL DEFAULT '',
+    extra             JSONB       NOT NULL DEFAULT '{}'::JSONB,
+
+    notes_p1          TEXT        NOT NULL DEFAULT '',
+    notes_p2          TEXT,
+    duet_singer1      TEXT        NOT NULL DEFAULT '',
+    duet_singer2      TEXT        NOT NULL DEFAULT ''
+);
+
+CREATE INDEX song_revisions_song_id_created_at_idx ON song_revisions (song_id, created_at);
+
+-- Existing library songs get an initial revision so that their first change can be reverted.
+INSERT INTO song_revisions (song_id, created_at,
+                            bpm, gap, video_gap, start, "end", preview_start, medley_start_beat, medley_end_beat, manual_medley,
+                            title, artists, genre, edition, creator, language, year, comment, extra,
+                            notes_p1, notes_p2, duet_singer1, duet_singer2)
+SELECT id, updated_at,
+       bpm, gap, video_gap, start, "end", preview_start, medley_start_beat, medley_end_beat, manual_medley,
+       title, artists, genre, edition, creator, language, year, comment, extra,
+       notes_p1, notes_p2, duet_singer1, duet_singer2
+FROM songs
+WHERE upload_id IS NULL;
+
+
+-- +goose Down
+DROP TABLE IF EXISTS song_revisions;
@@ -0,0 +1,109 @@
+-- +goose Up
+-- Table artists stores the artists that songs are credited to.
+-- Artist names are unique (ignoring case).
+CREATE TABLE artists
+(
+    LIKE entity INCLUDING ALL,
+
+    name TEXT NOT NULL
+);
+
+CREATE UNIQUE INDEX artists_name_key ON artists (LOWER(name));
+
+-- Trigger updated_at sets artists.updated_at during updates.
+CREATE TRIGGER updated_at
+    BEFORE UPDATE
+    ON artists
+    FOR EACH ROW
+EXECUTE PROCEDURE tg_set_updated_at();
+
+-- Table artist_aliases stores alternative names of artists, such as different spellings.
+-- An alias belongs to exactly one artist.
+CREATE TABLE artist_aliases
+(
+    artist_id INTEGER NOT NULL REFERENCES artists (id) ON DELETE CASCADE,
+    name      TEXT    NOT NULL
+);
+
+CREATE UNIQUE INDEX artist_aliases_name_key ON artist_aliases (LOWER(name));
+CREATE INDEX artist_aliases_artist_id_idx ON artist_aliases (artist_id);
+
+-- Table song_artists links songs to the artists they credit.
+-- The role indicates whether an artist is a main or a featured artist of the song.
+-- The position orders the artists of a song within each role.
+CREATE TABLE song_artists
+(
+    song_id   INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    artist_id INTEGER NOT NULL REFERENCES artists (id) ON DELETE CASCADE,
+    role      TEXT    NOT NULL CHECK ( role IN ('main', 'featured') ),
+    position  INTEGER NOT NULL,
+
+    PRIMARY KEY (song_id, artist_id)
+);
+
+CREATE INDEX song_artists_artist_id_idx ON song_artists (artist_id);
+
+-- +goose StatementBegin
+-- Function artist_id returns the ID of the artist with the specified name or alias.
+-- Names are compared ignoring case.
+-- If no such artist exists, a new artist is created.
+CREATE FUNCTION artist_id(artist_name TEXT)
+    RETURNS INTEGER
+    RETURNS NULL ON NULL INPUT
+AS
+$$
+DECLARE
+    result INTEGER;
+BEGIN
+    SELECT id INTO result FROM artists WHERE LOWER(name) = LOWER(artist_name);
+    IF result IS NULL THEN
+        SELECT artist_id INTO result FROM artist_aliases WHERE LOWER(name) = LOWER(artist_name);
+    END IF;
+    IF result IS NULL THEN
+        INSERT INTO artists (name) VALUES (artist_name) ON CONFLICT DO NOTHING RETURNING id INTO result;
+    END IF;
+    IF result IS NULL THEN
+        -- The artist has been created concurrently.
+        SELECT id INTO result FROM artists WHERE LOWER(name) = LOWER(artist_name);
+    END IF;
+    RETURN result;
+END;
+$$ LANGUAGE plpgsql
+    VOLATILE;
+-- +goose StatementEnd
+
+-- Existing artist names become artist entities.
+INSERT INTO song_artists (song_id, artist_id, role, position)
+SELECT s.id, artist_id(TRIM(a.name)), 'main', a.position
+FROM songs AS s,
+     UNNEST(s.artists) WITH ORDINALITY AS a(name, position)
+WHERE TRIM(a.name) <> ''
+ORDER BY s.id, a.position
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE songs
+    DROP COLUMN artists;
+
+ALTER TABLE song_revisions
+    ADD COLUMN featured_artists TEXT[] NOT NULL DEFAULT '{}'::TEXT[];
+
+
+-- +goose Down
+ALTER TABLE song_revisions
+    DROP COLUMN IF EXISTS featured_artists;
+
+ALTER TABLE songs
+    ADD COLUMN artists TEXT[] NOT NULL DEFAULT '{}'::TEXT[];
+
+UPDATE songs AS s
+SET artists = ARRAY(SELECT a.name
+                    FROM song_artists AS sa
+                             JOIN artists AS a ON sa.artist_id = a.id
+                    WHERE sa.song_id = s.id
+                    ORDER BY sa.role = 'featured', sa.position);
+
+DROP FUNCTION IF EXISTS artist_id;
+DROP TABLE IF EXISTS song_artists;
+DROP TABLE IF EXISTS artist_aliases;
+DROP TRIGGER IF EXISTS updated_at ON artists;
+DROP TABLE IF EXISTS artists;
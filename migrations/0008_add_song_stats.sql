@@ -0,0 +1,23 @@
+-- +goose Up
+-- Columns stats_p1 and stats_p2 store statistics about the notes of each player of a song,
+-- such as the vocal range or the note density.
+-- The statistics are calculated by Karman whenever a song is saved.
+-- Songs that have not been saved since this migration have empty statistics
+-- until they are calculated by the "karman backfill-stats" command.
+ALTER TABLE songs
+    ADD COLUMN stats_p1   JSONB    NOT NULL DEFAULT '{}'::JSONB,
+    ADD COLUMN stats_p2   JSONB,
+    ADD COLUMN length     INTERVAL NOT NULL DEFAULT '0'::INTERVAL,
+    ADD COLUMN difficulty SMALLINT NOT NULL DEFAULT 0;
+
+CREATE INDEX songs_difficulty_idx ON songs (difficulty);
+
+
+-- +goose Down
+DROP INDEX IF EXISTS songs_difficulty_idx;
+
+ALTER TABLE songs
+    DROP COLUMN IF EXISTS stats_p1,
+    DROP COLUMN IF EXISTS stats_p2,
+    DROP COLUMN IF EXISTS length,
+    DROP COLUMN IF EXISTS difficulty;
@@ -0,0 +1,36 @@
+-- +goose Up
+-- Table playlists stores named collections of songs.
+CREATE TABLE playlists
+(
+    LIKE entity INCLUDING ALL,
+
+    name        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+-- Trigger updated_at sets playlists.updated_at during updates.
+CREATE TRIGGER updated_at
+    BEFORE UPDATE
+    ON playlists
+    FOR EACH ROW
+EXECUTE PROCEDURE tg_set_updated_at();
+
+-- Table playlist_songs stores the songs of each playlist.
+-- The position orders the songs within a playlist.
+-- A song can appear multiple times in the same playlist.
+CREATE TABLE playlist_songs
+(
+    playlist_id INTEGER NOT NULL REFERENCES playlists (id) ON DELETE CASCADE,
+    song_id     INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    position    INTEGER NOT NULL,
+
+    PRIMARY KEY (playlist_id, position)
+);
+
+CREATE INDEX playlist_songs_song_id_idx ON playlist_songs (song_id);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS playlist_songs;
+DROP TRIGGER IF EXISTS updated_at ON playlists;
+DROP TABLE IF EXISTS playlists;
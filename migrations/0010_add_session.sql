@@ -0,0 +1,51 @@
+-- +goose Up
+-- Table sessions stores party sessions in which guests can request songs.
+-- Guests join a session using its code.
+CREATE TABLE sessions
+(
+    LIKE entity INCLUDING ALL,
+
+    name          TEXT    NOT NULL,
+    code          TEXT    NOT NULL UNIQUE,
+    request_limit INTEGER NOT NULL DEFAULT 0,
+    closed        BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+-- Trigger updated_at sets sessions.updated_at during updates.
+CREATE TRIGGER updated_at
+    BEFORE UPDATE
+    ON sessions
+    FOR EACH ROW
+EXECUTE PROCEDURE tg_set_updated_at();
+
+-- Table session_requests stores the song requests of each session.
+-- The position orders the requests within a session.
+-- Positions are not necessarily consecutive.
+CREATE TABLE session_requests
+(
+    LIKE entity INCLUDING ALL,
+
+    session_id INTEGER NOT NULL REFERENCES sessions (id) ON DELETE CASCADE,
+    song_id    INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    singer     TEXT    NOT NULL,
+    partner    TEXT    NOT NULL DEFAULT '',
+    state      TEXT    NOT NULL DEFAULT 'queued',
+    position   INTEGER NOT NULL
+);
+
+CREATE INDEX session_requests_session_id_idx ON session_requests (session_id, position);
+CREATE INDEX session_requests_song_id_idx ON session_requests (song_id);
+
+-- Trigger updated_at sets session_requests.updated_at during updates.
+CREATE TRIGGER updated_at
+    BEFORE UPDATE
+    ON session_requests
+    FOR EACH ROW
+EXECUTE PROCEDURE tg_set_updated_at();
+
+
+-- +goose Down
+DROP TRIGGER IF EXISTS updated_at ON session_requests;
+DROP TABLE IF EXISTS session_requests;
+DROP TRIGGER IF EXISTS updated_at ON sessions;
+DROP TABLE IF EXISTS sessions;
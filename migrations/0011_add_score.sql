@@ -0,0 +1,31 @@
+-- +goose Up
+-- Table song_plays stores how often each song has been sung.
+-- Play statistics are imported from karaoke games, Karman does not count plays itself.
+CREATE TABLE song_plays
+(
+    song_id      INTEGER   NOT NULL PRIMARY KEY REFERENCES songs (id) ON DELETE CASCADE,
+    times_played INTEGER   NOT NULL DEFAULT 0,
+    last_played  TIMESTAMP NULL
+);
+
+CREATE INDEX song_plays_times_played_idx ON song_plays (times_played);
+
+-- Table song_scores stores the highscores achieved for each song.
+-- The difficulty is the difficulty setting of the game, not the difficulty of the song.
+CREATE TABLE song_scores
+(
+    id         INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    song_id    INTEGER   NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    player     TEXT      NOT NULL,
+    difficulty TEXT      NOT NULL,
+    score      INTEGER   NOT NULL,
+    played_at  TIMESTAMP NULL
+);
+
+CREATE INDEX song_scores_song_id_idx ON song_scores (song_id);
+CREATE INDEX song_scores_score_idx ON song_scores (score);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS song_scores;
+DROP TABLE IF EXISTS song_plays;
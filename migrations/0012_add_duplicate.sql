@@ -0,0 +1,10 @@
+-- +goose Up
+-- Column upload_errors.duplicate_id references the library song that a song in an upload duplicates.
+-- Duplicates are reported as processing errors but do not prevent a song from being imported.
+ALTER TABLE upload_errors
+    ADD COLUMN duplicate_id INTEGER NULL REFERENCES songs (id) ON DELETE SET NULL;
+
+
+-- +goose Down
+ALTER TABLE upload_errors
+    DROP COLUMN IF EXISTS duplicate_id;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Column songs.upload_match_id references the library song that a song in an upload is a version of.
+-- The match is determined during upload processing and used when the song is imported into the library.
+ALTER TABLE songs
+    ADD COLUMN upload_match_id INTEGER NULL REFERENCES songs (id) ON DELETE SET NULL;
+
+
+-- +goose Down
+ALTER TABLE songs
+    DROP COLUMN IF EXISTS upload_match_id;
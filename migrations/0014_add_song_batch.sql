@@ -0,0 +1,41 @@
+-- +goose Up
+-- Table song_batches stores operations that are applied to many songs at once.
+-- The operation is stored as JSON because its fields depend on the kind of operation.
+CREATE TABLE song_batches
+(
+    LIKE entity INCLUDING ALL,
+
+    state           TEXT    NOT NULL DEFAULT 'pending',
+    operation       JSONB   NOT NULL,
+    atomic          BOOLEAN NOT NULL DEFAULT FALSE,
+    author          TEXT    NOT NULL DEFAULT '',
+    songs_total     INT     NOT NULL DEFAULT 0,
+    songs_processed INT     NOT NULL DEFAULT 0,
+    errors          INT     NOT NULL DEFAULT 0
+);
+
+-- Table song_batch_songs stores the songs of a batch and their results.
+-- Songs are referenced by their UUID so that results persist when a song is purged.
+CREATE TABLE song_batch_songs
+(
+    id        INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    batch_id  INT     NOT NULL REFERENCES song_batches (id) ON DELETE CASCADE,
+    song      UUID    NOT NULL,
+    processed BOOLEAN NOT NULL DEFAULT FALSE,
+    error     TEXT    NOT NULL DEFAULT '',
+
+    UNIQUE (batch_id, song)
+);
+
+-- Trigger updated_at sets song_batches.updated_at during updates.
+CREATE TRIGGER updated_at
+    BEFORE UPDATE
+    ON song_batches
+    FOR EACH ROW
+EXECUTE PROCEDURE tg_set_updated_at();
+
+
+-- +goose Down
+DROP TRIGGER IF EXISTS updated_at ON song_batches;
+DROP TABLE IF EXISTS song_batch_songs;
+DROP TABLE IF EXISTS song_batches;
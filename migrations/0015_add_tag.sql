@@ -0,0 +1,64 @@
+-- +goose Up
+-- Table tags stores user-defined labels for songs.
+-- Tag names are unique (ignoring case).
+-- The color is a hex value of the form #rrggbb or an empty string.
+CREATE TABLE tags
+(
+    LIKE entity INCLUDING ALL,
+
+    name  TEXT NOT NULL,
+    color TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX tags_name_key ON tags (LOWER(name));
+
+-- Trigger updated_at sets tags.updated_at during updates.
+CREATE TRIGGER updated_at
+    BEFORE UPDATE
+    ON tags
+    FOR EACH ROW
+EXECUTE PROCEDURE tg_set_updated_at();
+
+-- Table song_tags links songs to their tags.
+CREATE TABLE song_tags
+(
+    song_id INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    tag_id  INTEGER NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+
+    PRIMARY KEY (song_id, tag_id)
+);
+
+CREATE INDEX song_tags_tag_id_idx ON song_tags (tag_id);
+
+-- +goose StatementBegin
+-- Function tag_id returns the ID of the tag with the specified name.
+-- Names are compared ignoring case.
+-- If no such tag exists, a new tag is created.
+CREATE FUNCTION tag_id(tag_name TEXT)
+    RETURNS INTEGER
+    RETURNS NULL ON NULL INPUT
+AS
+$$
+DECLARE
+    result INTEGER;
+BEGIN
+    SELECT id INTO result FROM tags WHERE LOWER(name) = LOWER(tag_name);
+    IF result IS NULL THEN
+        INSERT INTO tags (name) VALUES (tag_name) ON CONFLICT DO NOTHING RETURNING id INTO result;
+    END IF;
+    IF result IS NULL THEN
+        -- The tag has been created concurrently.
+        SELECT id INTO result FROM tags WHERE LOWER(name) = LOWER(tag_name);
+    END IF;
+    RETURN result;
+END;
+$$ LANGUAGE plpgsql
+    VOLATILE;
+-- +goose StatementEnd
+
+
+-- +goose Down
+DROP FUNCTION IF EXISTS tag_id;
+DROP TABLE IF EXISTS song_tags;
+DROP TRIGGER IF EXISTS updated_at ON tags;
+DROP TABLE IF EXISTS tags;
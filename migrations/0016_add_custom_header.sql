@@ -0,0 +1,27 @@
+-- +goose Up
+-- Table custom_headers stores the registry of known custom headers of TXT files.
+-- Names are stored without the leading # and are unique (ignoring case).
+-- Aliases are legacy spellings of a header that are renamed to its name.
+CREATE TABLE custom_headers
+(
+    LIKE entity INCLUDING ALL,
+
+    name    TEXT   NOT NULL,
+    type    TEXT   NOT NULL DEFAULT 'string',
+    pattern TEXT   NOT NULL DEFAULT '',
+    aliases TEXT[] NOT NULL DEFAULT '{}'
+);
+
+CREATE UNIQUE INDEX custom_headers_name_key ON custom_headers (LOWER(name));
+
+-- Trigger updated_at sets custom_headers.updated_at during updates.
+CREATE TRIGGER updated_at
+    BEFORE UPDATE
+    ON custom_headers
+    FOR EACH ROW
+EXECUTE PROCEDURE tg_set_updated_at();
+
+
+-- +goose Down
+DROP TRIGGER IF EXISTS updated_at ON custom_headers;
+DROP TABLE IF EXISTS custom_headers;
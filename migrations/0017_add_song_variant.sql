@@ -0,0 +1,32 @@
+-- +goose Up
+-- Table song_groups stores groups of songs that are variants of each other,
+-- such as a solo and a duet chart of the same song.
+CREATE TABLE song_groups
+(
+    LIKE entity INCLUDING ALL
+);
+
+-- Trigger updated_at sets song_groups.updated_at during updates.
+CREATE TRIGGER updated_at
+    BEFORE UPDATE
+    ON song_groups
+    FOR EACH ROW
+EXECUTE PROCEDURE tg_set_updated_at();
+
+-- Table song_variants assigns songs to their variant group.
+-- Each song belongs to at most one group.
+-- The relation describes how the song relates to the group (e.g. 'original' or 'duet-version').
+CREATE TABLE song_variants
+(
+    song_id  INTEGER PRIMARY KEY REFERENCES songs (id) ON DELETE CASCADE,
+    group_id INTEGER NOT NULL REFERENCES song_groups (id) ON DELETE CASCADE,
+    relation TEXT    NOT NULL
+);
+
+CREATE INDEX song_variants_group_id_idx ON song_variants (group_id);
+
+
+-- +goose Down
+DROP TABLE IF EXISTS song_variants;
+DROP TRIGGER IF EXISTS updated_at ON song_groups;
+DROP TABLE IF EXISTS song_groups;
@@ -0,0 +1,47 @@
+-- +goose Up
+-- Table song_reviews stores the review state of songs (e.g. 'needs-review' or 'approved').
+-- Songs without a row are drafts.
+-- changed_at is the time of the last state change, changed_by identifies who made it.
+CREATE TABLE song_reviews
+(
+    song_id    INTEGER PRIMARY KEY REFERENCES songs (id) ON DELETE CASCADE,
+    state      TEXT      NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    changed_by TEXT      NOT NULL DEFAULT ''
+);
+
+CREATE INDEX song_reviews_state_idx ON song_reviews (state);
+
+-- Table song_comments stores the review thread of songs.
+-- The kind is either 'remark' or 'out-of-sync'.
+-- Comments can be anchored to a line or beat of a player.
+-- If anchor_player is NULL the comment refers to the song as a whole.
+-- anchor_line is -1 for anchors that only reference a beat.
+CREATE TABLE song_comments
+(
+    LIKE entity INCLUDING ALL,
+
+    song_id       INTEGER NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    author        TEXT    NOT NULL DEFAULT '',
+    kind          TEXT    NOT NULL,
+    text          TEXT    NOT NULL,
+    anchor_player INTEGER,
+    anchor_line   INTEGER,
+    anchor_beat   INTEGER,
+    resolved      BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX song_comments_song_id_idx ON song_comments (song_id);
+
+-- Trigger updated_at sets song_comments.updated_at during updates.
+CREATE TRIGGER updated_at
+    BEFORE UPDATE
+    ON song_comments
+    FOR EACH ROW
+EXECUTE PROCEDURE tg_set_updated_at();
+
+
+-- +goose Down
+DROP TRIGGER IF EXISTS updated_at ON song_comments;
+DROP TABLE IF EXISTS song_comments;
+DROP TABLE IF EXISTS song_reviews;
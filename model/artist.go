@@ -0,0 +1,18 @@
+package model
+
+// An Artist is a performer that songs are credited to.
+// Artists are created implicitly when a song credits an artist that is not yet known.
+type Artist struct {
+	Model
+
+	// Name is the canonical name of the artist.
+	// The canonical name is used when songs are exported in the UltraStar TXT format.
+	Name string
+
+	// Aliases are alternative names of the artist, such as different spellings.
+	// Songs crediting an alias are linked to the artist.
+	Aliases []string
+
+	// SongCount is the number of songs in the library that credit the artist.
+	SongCount int64 // read only
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+// BatchState indicates in which processing state a SongBatch currently is.
+type BatchState string
+
+const (
+	// BatchStatePending indicates that a batch has been created, but processing has not started yet.
+	BatchStatePending BatchState = "pending"
+
+	// BatchStateRunning indicates that a batch is currently being processed.
+	BatchStateRunning BatchState = "running"
+
+	// BatchStateDone indicates that processing has finished.
+	// Individual songs may still have failed.
+	BatchStateDone BatchState = "done"
+
+	// BatchStateFailed indicates that an atomic batch could not be applied to all of its songs.
+	// No song has been changed.
+	BatchStateFailed BatchState = "failed"
+)
+
+// BatchOp identifies the kind of change of a BatchOperation.
+type BatchOp string
+
+const (
+	// BatchOpSet sets the song fields in BatchOperation.Fields.
+	BatchOpSet BatchOp = "set"
+
+	// BatchOpAddCustomTags sets the custom tags in BatchOperation.CustomTags.
+	// Existing custom tags with the same name are overwritten.
+	BatchOpAddCustomTags BatchOp = "addCustomTags"
+
+	// BatchOpRemoveCustomTags removes the custom tags named in BatchOperation.TagNames.
+	BatchOpRemoveCustomTags BatchOp = "removeCustomTags"
+
+	// BatchOpAddTags adds the tags named in BatchOperation.TagNames.
+	// Unknown tags are created.
+	BatchOpAddTags BatchOp = "addTags"
+
+	// BatchOpRemoveTags removes the tags named in BatchOperation.TagNames.
+	BatchOpRemoveTags BatchOp = "removeTags"
+
+	// BatchOpDelete moves songs to the trash.
+	BatchOpDelete BatchOp = "delete"
+
+	// BatchOpTransform applies the timing transforms in BatchOperation.Transforms.
+	BatchOpTransform BatchOp = "transform"
+
+	// BatchOpFix applies the import fixers that are applied to uploaded songs.
+	BatchOpFix BatchOp = "fix"
+)
+
+// A BatchOperation is a change that is applied to every song of a SongBatch.
+// Which fields are used depends on Op.
+type BatchOperation struct {
+	Op BatchOp
+
+	// Fields are the values set by BatchOpSet.
+	Fields SongFields
+
+	// CustomTags are the custom tags set by BatchOpAddCustomTags.
+	CustomTags map[string]string
+
+	// TagNames are the names of the custom tags removed by BatchOpRemoveCustomTags
+	// or the names of the tags added or removed by BatchOpAddTags and BatchOpRemoveTags.
+	TagNames []string
+
+	// Transforms are the timing transforms applied by BatchOpTransform in their textual representation.
+	Transforms []string
+}
+
+// SongFields contains values for song metadata fields.
+// A nil value leaves the field unchanged.
+// An empty string or a year of 0 clears the field.
+type SongFields struct {
+	Genre    *string
+	Edition  *string
+	Language *string
+	Creator  *string
+	Year     *int
+}
+
+// A SongBatch applies a single BatchOperation to many songs.
+// Batches with many songs are processed in the background.
+type SongBatch struct {
+	Model
+
+	State     BatchState // read only
+	Operation BatchOperation
+
+	// Atomic indicates that the batch is applied to all songs or to none of them.
+	// If the operation fails for any song of an atomic batch, no song is changed.
+	Atomic bool
+
+	// Author is recorded as the author of the song revisions created by the batch.
+	Author string
+
+	// The total number of songs in the batch.
+	SongsTotal int // read only
+
+	// The number of songs that have been processed.
+	SongsProcessed int // read only
+
+	// The number of songs that could not be changed.
+	Errors int // read only
+}
+
+// A SongBatchResult is the result of applying a SongBatch to a single song.
+type SongBatchResult struct {
+	// Song is the UUID of the song.
+	Song uuid.UUID
+	// Error describes why the song could not be changed.
+	// If the song was changed successfully, Error is empty.
+	Error string
+}
@@ -0,0 +1,27 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+// DuplicateReason indicates why two songs are considered duplicates.
+type DuplicateReason string
+
+const (
+	// DuplicateReasonTitle indicates that the songs have the same artist and title,
+	// ignoring case, punctuation and bracketed additions.
+	DuplicateReasonTitle DuplicateReason = "title"
+	// DuplicateReasonAudio indicates that the songs use identical audio files.
+	DuplicateReasonAudio DuplicateReason = "audio"
+	// DuplicateReasonLyrics indicates that the songs have audio files of a similar duration and similar lyrics.
+	DuplicateReasonLyrics DuplicateReason = "lyrics"
+)
+
+// A DuplicateCluster is a group of songs that are probably the same song.
+// Each song in the cluster is a duplicate of at least one other song in the cluster.
+type DuplicateCluster struct {
+	// Songs are the UUIDs of the songs in the cluster.
+	Songs []uuid.UUID
+	// Reasons are the reasons why songs in the cluster are considered duplicates.
+	Reasons []DuplicateReason
+}
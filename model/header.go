@@ -0,0 +1,40 @@
+package model
+
+// HeaderType is the type of the values of a custom header.
+type HeaderType string
+
+const (
+	// HeaderTypeString allows any value.
+	HeaderTypeString HeaderType = "string"
+
+	// HeaderTypeInt allows integer values.
+	HeaderTypeInt HeaderType = "int"
+
+	// HeaderTypeDuration allows a non-negative number of seconds, such as "12.5".
+	HeaderTypeDuration HeaderType = "duration"
+
+	// HeaderTypeURL allows absolute URLs.
+	HeaderTypeURL HeaderType = "url"
+)
+
+// A CustomHeader is a known custom header of UltraStar TXT files, such as #ALBUM.
+// Custom headers are stored in the CustomTags of songs.
+// The registry of custom headers is defined by administrators
+// and is used to validate custom tags and to detect unknown or misspelled headers.
+type CustomHeader struct {
+	Model
+
+	// Name is the canonical spelling of the header, without the leading #.
+	Name string
+
+	// Type is the type of the header's values.
+	Type HeaderType
+
+	// Pattern is an optional regular expression that values of the header must match.
+	// An empty pattern allows all values of the header's Type.
+	Pattern string
+
+	// Aliases are legacy spellings of the header, such as typos.
+	// Custom tags using an alias or a different case than Name are renamed by the header migration.
+	Aliases []string
+}
@@ -0,0 +1,17 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+// A Playlist is a named, ordered collection of songs, such as a set for a party.
+// The same song can appear multiple times in a playlist.
+type Playlist struct {
+	Model
+
+	Name        string
+	Description string
+
+	// Songs are the UUIDs of the songs in the playlist, in order.
+	Songs []uuid.UUID
+}
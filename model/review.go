@@ -0,0 +1,110 @@
+package model
+
+import (
+	"time"
+
+	"codello.dev/ultrastar"
+	"github.com/google/uuid"
+)
+
+// ReviewState is the state of a song in the review workflow.
+type ReviewState string
+
+const (
+	// ReviewStateDraft identifies songs that are still being worked on.
+	// Songs without an explicit state are drafts.
+	ReviewStateDraft ReviewState = "draft"
+
+	// ReviewStateNeedsReview identifies songs that are ready to be reviewed.
+	ReviewStateNeedsReview ReviewState = "needs-review"
+
+	// ReviewStateApproved identifies songs that have been approved by a reviewer.
+	ReviewStateApproved ReviewState = "approved"
+
+	// ReviewStateBroken identifies songs with known problems, such as notes that are out of sync.
+	ReviewStateBroken ReviewState = "broken"
+)
+
+// IsValid reports whether s is a known state.
+func (s ReviewState) IsValid() bool {
+	switch s {
+	case ReviewStateDraft, ReviewStateNeedsReview, ReviewStateApproved, ReviewStateBroken:
+		return true
+	default:
+		return false
+	}
+}
+
+// CanTransition reports whether the review state of a song can be changed from s to state.
+// Only songs that need a review can be approved.
+// All other transitions are allowed.
+// Karman does not authenticate users, so transitions are not restricted by the role of a user.
+func (s ReviewState) CanTransition(state ReviewState) bool {
+	return state != ReviewStateApproved || s == ReviewStateNeedsReview || s == ReviewStateApproved
+}
+
+// CommentKind distinguishes remarks of reviewers from problem reports.
+type CommentKind string
+
+const (
+	// CommentKindRemark identifies a regular comment on a song.
+	CommentKindRemark CommentKind = "remark"
+
+	// CommentKindOutOfSync identifies a report that the notes of a song are out of sync with its audio.
+	// Out-of-sync reports can be filed by any user.
+	CommentKindOutOfSync CommentKind = "out-of-sync"
+)
+
+// IsValid reports whether k is a known comment kind.
+func (k CommentKind) IsValid() bool {
+	return k == CommentKindRemark || k == CommentKindOutOfSync
+}
+
+// A SongComment is a comment in the review thread of a song.
+type SongComment struct {
+	Model
+
+	// Author identifies who wrote the comment.
+	// The value is provided by the client and may be empty.
+	Author string
+
+	Kind CommentKind
+	Text string
+
+	// Anchor references the part of the song that the comment is about.
+	// If Anchor is nil, the comment refers to the song as a whole.
+	Anchor *CommentAnchor
+
+	// Resolved indicates that the comment has been addressed.
+	// Unresolved out-of-sync reports put a song into the review queue.
+	Resolved bool
+}
+
+// A CommentAnchor references a position in the notes of a song.
+type CommentAnchor struct {
+	// Player is the index of the voice (0 for P1 and 1 for P2).
+	Player int
+	// Line is the index of the referenced line of Player.
+	// Indices are compatible with the notes of the song (see song.SplitLines).
+	// If the anchor does not reference a line, Line is -1.
+	Line int
+	// Beat is the referenced beat.
+	// For anchors that reference a line, Beat is the start of the first note of the line.
+	Beat ultrastar.Beat
+}
+
+// A ReviewQueueEntry is a song that requires the attention of a reviewer.
+type ReviewQueueEntry struct {
+	// Song is the UUID of the song.
+	Song uuid.UUID
+	// Title and Artists of the song.
+	// These are included so that the queue can be listed without fetching each song.
+	Title   string
+	Artists []string
+	// State is the review state of the song.
+	State ReviewState
+	// Since is the time at which the song entered the queue.
+	Since time.Time
+	// OpenReports is the number of unresolved out-of-sync reports of the song.
+	OpenReports int
+}
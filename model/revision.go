@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"codello.dev/ultrastar"
+	"github.com/google/uuid"
+)
+
+// A SongRevision is a snapshot of the metadata and notes of a Song at a certain point in time.
+// Revisions are recorded whenever a song is changed.
+// File references are not part of a revision.
+type SongRevision struct {
+	// The unique identifier of this revision.
+	UUID uuid.UUID
+
+	// The time at which the revision was recorded.
+	CreatedAt time.Time
+
+	// Author identifies who made the change.
+	// The value is provided by the client and may be empty.
+	Author string
+
+	ultrastar.Song
+	Artists         []string
+	FeaturedArtists []string
+}
+
+// NewSongRevision creates a snapshot of the current state of song.
+// The returned revision does not have a UUID or creation time yet.
+func NewSongRevision(song Song, author string) SongRevision {
+	rev := SongRevision{
+		Author:          author,
+		Song:            song.Song,
+		Artists:         song.Artists,
+		FeaturedArtists: song.FeaturedArtists,
+	}
+	// File names are not versioned.
+	rev.AudioFileName = ""
+	rev.VideoFileName = ""
+	rev.CoverFileName = ""
+	rev.BackgroundFileName = ""
+	return rev
+}
+
+// Apply sets the metadata and notes of song to the values of r.
+// File references of song are not affected.
+func (r SongRevision) Apply(song *Song) {
+	data := r.Song
+	data.AudioFileName = song.AudioFileName
+	data.VideoFileName = song.VideoFileName
+	data.CoverFileName = song.CoverFileName
+	data.BackgroundFileName = song.BackgroundFileName
+	song.Song = data
+	song.Artists = r.Artists
+	song.FeaturedArtists = r.FeaturedArtists
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PlayStats record how often a song has been sung.
+// Play statistics are imported from karaoke games such as UltraStar Deluxe.
+type PlayStats struct {
+	// Song is the UUID of the song.
+	Song uuid.UUID
+
+	// TimesPlayed is the number of times the song has been sung.
+	TimesPlayed int
+	// LastPlayed is the time the song was last sung.
+	// The zero value indicates that the time is unknown.
+	LastPlayed time.Time
+}
+
+// GameDifficulty is the difficulty setting of a karaoke game that a score was achieved at.
+// This is different from the Difficulty of a song.
+type GameDifficulty string
+
+const (
+	GameDifficultyEasy   GameDifficulty = "easy"
+	GameDifficultyMedium GameDifficulty = "medium"
+	GameDifficultyHard   GameDifficulty = "hard"
+)
+
+// A Highscore is a score that a player achieved when singing a song.
+type Highscore struct {
+	// Song is the UUID of the song.
+	Song uuid.UUID
+
+	Player     string
+	Difficulty GameDifficulty
+	Score      int
+
+	// Date is the time the score was achieved.
+	// The zero value indicates that the time is unknown.
+	Date time.Time
+}
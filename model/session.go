@@ -0,0 +1,52 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+// A Session is a party session in which guests can request songs.
+// Guests join a session using its join code and do not need an account.
+type Session struct {
+	Model
+
+	Name string
+
+	// Code is the join code that guests use to access the session.
+	Code string // read only
+
+	// RequestLimit is the maximum number of queued requests per guest.
+	// A value of 0 indicates that there is no limit.
+	RequestLimit int
+
+	// Closed indicates that the session does not accept new requests.
+	Closed bool
+}
+
+// RequestState indicates whether a song request is waiting in the queue.
+type RequestState string
+
+const (
+	// RequestStateQueued indicates that a request is waiting in the queue.
+	RequestStateQueued RequestState = "queued"
+
+	// RequestStateSung indicates that the requested song has been sung.
+	RequestStateSung RequestState = "sung"
+
+	// RequestStateSkipped indicates that a request has been skipped by the operator.
+	RequestStateSkipped RequestState = "skipped"
+)
+
+// A SongRequest is a request of a guest to sing a song in a Session.
+type SongRequest struct {
+	Model
+
+	// Song is the UUID of the requested song.
+	Song uuid.UUID
+
+	// Singer is the name of the guest who requested the song.
+	Singer string
+	// Partner is the name of the duet partner of the singer, if any.
+	Partner string
+
+	State RequestState
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"codello.dev/ultrastar"
+	"github.com/google/uuid"
 )
 
 // Song is the base model of Karman.
@@ -10,7 +11,28 @@ type Song struct {
 	Model
 
 	ultrastar.Song
-	Artists []string
+
+	// Artists are the main artists of the song.
+	// FeaturedArtists are credited as featured ("feat.") artists.
+	Artists         []string
+	FeaturedArtists []string
+
+	// Tags are the names of the user-defined tags of the song, ordered by name.
+	// Tags are not written to the TXT file of the song.
+	Tags []string
+
+	// VariantGroup identifies the group of songs that are variants of each other, such as a solo and a duet chart.
+	// If the song does not belong to a variant group, VariantGroup is uuid.Nil.
+	// VariantRelation describes how the song relates to its group.
+	// Variants are the other library songs in the group.
+	// Variant groups are managed via the variant.Repository.
+	VariantGroup    uuid.UUID       // read only
+	VariantRelation VariantRelation // read only
+	Variants        []SongVariant   // read only
+
+	// ReviewState is the state of the song in the review workflow.
+	// The review state is managed via the review.Repository.
+	ReviewState ReviewState // read only
 
 	// InUpload indicates whether this song belongs to an upload.
 	InUpload bool // read only
@@ -22,4 +44,10 @@ type Song struct {
 	VideoFile      *File  // read only
 	BackgroundFile *File  // read only
 	TxtFileName    string // read only
+	FolderName     string // read only
+	// LyricsFileNames contains the name of the LRC file for each player of the song.
+	LyricsFileNames []string // read only
+
+	// Stats are calculated from the notes whenever the song is saved.
+	Stats SongStats // read only
 }
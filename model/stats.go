@@ -0,0 +1,55 @@
+package model
+
+import (
+	"time"
+
+	"codello.dev/ultrastar"
+)
+
+// TrackStats contains statistics about the notes of a single player of a song.
+type TrackStats struct {
+	// Notes is the number of singable notes of the track.
+	Notes int
+	// Pitched is the number of notes that are evaluated by their pitch.
+	// Freestyle and rap notes are not pitched.
+	Pitched int
+
+	// LowestPitch, HighestPitch and MedianPitch describe the vocal range of the track.
+	// Only pitched notes are considered.
+	// If Pitched is 0, the values are meaningless.
+	LowestPitch  ultrastar.Pitch
+	HighestPitch ultrastar.Pitch
+	MedianPitch  ultrastar.Pitch
+
+	// NoteDensity is the average number of notes per second between the first and the last note of the track.
+	NoteDensity float64
+	// GoldenRatio is the fraction of notes that are golden notes.
+	GoldenRatio float64
+	// FreestyleRatio is the fraction of notes that are freestyle notes.
+	FreestyleRatio float64
+}
+
+// Difficulty is a rating of how hard a song is to sing.
+// Values range from DifficultyEasiest to DifficultyHardest.
+// The zero value indicates that the difficulty is unknown.
+type Difficulty int
+
+const (
+	DifficultyUnknown Difficulty = 0
+	DifficultyEasiest Difficulty = 1
+	DifficultyHardest Difficulty = 5
+)
+
+// SongStats contains statistics about the notes of a song.
+// Stats are derived from the notes and cannot be set directly.
+type SongStats struct {
+	P1 TrackStats
+	// P2 is nil unless the song is a duet.
+	P2 *TrackStats
+
+	// Length is the duration of the song from the start of the audio until the end of the last note.
+	// If the song specifies an end time, that time is used instead.
+	Length time.Duration
+	// Difficulty is the difficulty of the hardest track of the song.
+	Difficulty Difficulty
+}
@@ -0,0 +1,18 @@
+package model
+
+// A Tag is a user-defined label for songs, such as "christmas" or "needs-video".
+// Tags are managed by Karman and are not part of the UltraStar TXT data of a song.
+// Tags are created implicitly when a song is tagged with an unknown name.
+type Tag struct {
+	Model
+
+	// Name is the unique name of the tag.
+	Name string
+
+	// Color is the color of the tag as a hex value of the form #rrggbb.
+	// An empty value indicates that the tag has no color.
+	Color string
+
+	// SongCount is the number of songs in the library that have the tag.
+	SongCount int64 // read only
+}
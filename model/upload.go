@@ -1,5 +1,9 @@
 package model
 
+import (
+	"github.com/google/uuid"
+)
+
 // UploadState indicates in which processing state an upload currently is.
 type UploadState string
 
@@ -44,8 +48,37 @@ type UploadProcessingError struct {
 	File string
 	// The error message.
 	Message string
+	// Duplicate is the UUID of a song in the library if the error reports that the file contains a duplicate of that song.
+	// For other errors Duplicate is uuid.Nil.
+	Duplicate uuid.UUID
+}
+
+// An UploadSong is a song that has been found in an upload.
+type UploadSong struct {
+	// Song is the UUID of the song in the upload.
+	Song uuid.UUID
+	// Match is the UUID of the song in the library that the uploaded song is a version of.
+	// Songs are matched by their artist and title or by their audio file during processing.
+	// If the song does not match any song in the library, Match is uuid.Nil.
+	Match uuid.UUID
 }
 
+// ImportAction determines how a song from an upload is imported into the library.
+type ImportAction string
+
+const (
+	// ImportActionReplace replaces the metadata and notes of the matched library song with those of the uploaded song.
+	ImportActionReplace ImportAction = "replace"
+	// ImportActionKeepBoth imports the uploaded song as a new song, even if it matches a library song.
+	ImportActionKeepBoth ImportAction = "keep-both"
+	// ImportActionMergeMetadata copies the metadata of the uploaded song to the matched library song.
+	// The notes and timing of the library song are kept.
+	ImportActionMergeMetadata ImportAction = "merge-metadata"
+	// ImportActionAddVariant imports the uploaded song as a new song
+	// and adds it to the variant group of the matched library song.
+	ImportActionAddVariant ImportAction = "add-variant"
+)
+
 // Error returns the error message of the error.
 func (err *UploadProcessingError) Error() string {
 	return err.Message
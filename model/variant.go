@@ -0,0 +1,52 @@
+package model
+
+import (
+	"github.com/google/uuid"
+)
+
+// VariantRelation describes how a song relates to the other songs in its variant group.
+type VariantRelation string
+
+const (
+	// VariantRelationOriginal identifies the song that a variant group was created for.
+	VariantRelationOriginal VariantRelation = "original"
+
+	// VariantRelationDuetVersion identifies a duet chart of a song.
+	VariantRelationDuetVersion VariantRelation = "duet-version"
+
+	// VariantRelationAlternativeChart identifies a different chart of the same recording, usually by a different creator.
+	VariantRelationAlternativeChart VariantRelation = "alternative-chart"
+
+	// VariantRelationRemix identifies a chart of a remix.
+	VariantRelationRemix VariantRelation = "remix"
+
+	// VariantRelationCover identifies a chart of a cover version by another artist.
+	VariantRelationCover VariantRelation = "cover"
+
+	// VariantRelationLive identifies a chart of a live recording.
+	VariantRelationLive VariantRelation = "live"
+)
+
+// IsValid reports whether r is a known relation.
+func (r VariantRelation) IsValid() bool {
+	switch r {
+	case VariantRelationOriginal, VariantRelationDuetVersion, VariantRelationAlternativeChart,
+		VariantRelationRemix, VariantRelationCover, VariantRelationLive:
+		return true
+	default:
+		return false
+	}
+}
+
+// A SongVariant references another song in the variant group of a song.
+// Songs in the same variant group are versions of the same song, such as a solo and a duet chart.
+type SongVariant struct {
+	// Song is the UUID of the variant.
+	Song uuid.UUID
+	// Title and Creator of the variant.
+	// These are included so that variants can be listed without fetching each song.
+	Title   string
+	Creator string
+	// Relation describes how the variant relates to the group.
+	Relation VariantRelation
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook is an external HTTP endpoint that gets notified about events in Karman.
+type Webhook struct {
+	Model
+
+	// URL is the endpoint to which events are delivered via POST requests.
+	URL string
+
+	// Secret is used to sign the payloads sent to the webhook.
+	// The receiver can use the signature to verify that a request originated from Karman.
+	Secret string
+
+	// Events is a list of event filters.
+	// A filter is either an event type (such as "song.created") or an event category (such as "song").
+	// If Events is empty, all events are delivered to the webhook.
+	Events []string
+}
+
+// Accepts indicates whether the webhook should receive events of the specified type.
+func (w *Webhook) Accepts(eventType string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	category, _, _ := strings.Cut(eventType, ".")
+	for _, filter := range w.Events {
+		if filter == eventType || filter == category {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery records a single attempt to deliver an event to a Webhook.
+type WebhookDelivery struct {
+	// The unique identifier of this delivery attempt.
+	UUID uuid.UUID
+
+	// The time at which the delivery was attempted.
+	CreatedAt time.Time
+
+	// The ID and type of the delivered event.
+	EventID   uuid.UUID
+	EventType string
+
+	// Attempt is the 1-based number of the delivery attempt for the event.
+	Attempt int
+
+	// StatusCode is the HTTP status code returned by the webhook.
+	// A value of 0 indicates that no response was received.
+	StatusCode int
+
+	// Error describes why a delivery failed.
+	// The value is empty for successful deliveries.
+	Error string
+
+	// Duration is the time it took to deliver the event.
+	Duration time.Duration
+}
+
+// Successful indicates whether the webhook accepted the delivery.
+func (d *WebhookDelivery) Successful() bool {
+	return d.StatusCode >= 200 && d.StatusCode < 300
+}
@@ -0,0 +1,13 @@
+// Package filename generates file names that are valid on all common operating systems.
+//
+// A Template describes how a file name is built from a set of named values.
+// Templates consist of literal text and placeholders in curly braces, such as "{artist} - {title}{ext}".
+// Parts of a template can be enclosed in angle brackets to make them optional:
+// An optional part is omitted if any of its placeholders has an empty value.
+// For example the template "{artist} - {title}< [{kind}]>{ext}" renders as "A - T.txt" if kind is empty
+// and as "A - T [AUDIO].mp3" otherwise.
+// The characters "{", "}", "<" and ">" are reserved and cannot be used literally in a template.
+//
+// Sanitize and Truncate turn arbitrary strings into names that are safe to use on Windows, macOS, and Linux.
+// A Set can be used to resolve collisions between names in the same directory.
+package filename
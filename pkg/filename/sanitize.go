@@ -0,0 +1,88 @@
+package filename
+
+import (
+	"path"
+	"strings"
+	"unicode/utf8"
+)
+
+// replacer replaces characters that are not allowed in file names on at least one common operating system.
+var replacer = strings.NewReplacer(
+	"/", "_",
+	"\\", "_",
+	":", "_",
+	"*", "_",
+	"?", "_",
+	"\"", "'",
+	"<", "_",
+	">", "_",
+	"|", "_",
+)
+
+// reservedNames contains names that cannot be used as file names on Windows, regardless of the extension.
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM0": true, "COM1": true, "COM2": true, "COM3": true, "COM4": true,
+	"COM5": true, "COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT0": true, "LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true,
+	"LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// Sanitize turns name into a file name that is valid on Windows, macOS, and Linux.
+//
+// Path separators and other reserved characters are replaced, control characters are removed.
+// Leading and trailing whitespace as well as trailing dots are removed.
+// A leading dot is replaced so that the file is not hidden.
+// Names reserved by Windows (such as "CON" or "NUL.txt") are prefixed with an underscore.
+// If the resulting name is empty, "_" is returned.
+func Sanitize(name string) string {
+	name = strings.ToValidUTF8(name, "_")
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	name = replacer.Replace(name)
+	name = trim(name)
+	if strings.HasPrefix(name, ".") {
+		name = "_" + name[1:]
+	}
+	stem, _, _ := strings.Cut(name, ".")
+	if reservedNames[strings.ToUpper(strings.TrimSpace(stem))] {
+		name = "_" + name
+	}
+	if name == "" {
+		return "_"
+	}
+	return name
+}
+
+// trim removes leading whitespace as well as trailing whitespace and dots from name.
+func trim(name string) string {
+	return strings.TrimRight(strings.TrimSpace(name), ". ")
+}
+
+// Truncate shortens name to at most maxLen bytes.
+// The extension of name is preserved if possible.
+// Truncation never splits a UTF-8 character.
+// If name is truncated, trailing whitespace and dots are removed from the remaining name.
+func Truncate(name string, maxLen int) string {
+	if len(name) <= maxLen {
+		return name
+	}
+	ext := path.Ext(name)
+	if len(ext) >= maxLen || strings.ContainsRune(ext, ' ') {
+		ext = ""
+	}
+	stem := name[:len(name)-len(ext)]
+	n := maxLen - len(ext)
+	for n > 0 && !utf8.RuneStart(stem[n]) {
+		n--
+	}
+	stem = trim(stem[:n])
+	if stem == "" {
+		stem = "_"
+	}
+	return stem + ext
+}
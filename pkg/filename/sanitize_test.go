@@ -0,0 +1,69 @@
+package filename
+
+import (
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	cases := map[string]struct {
+		v        string
+		expected string
+	}{
+		"valid":              {"Queen - Bohemian Rhapsody.txt", "Queen - Bohemian Rhapsody.txt"},
+		"path separators":    {"AC/DC - Back in Black", "AC_DC - Back in Black"},
+		"reserved chars":     {`What? "Now": <1>*|\`, `What_ 'Now'_ _1____`},
+		"control chars":      {"Tab\tNew\nLine", "TabNewLine"},
+		"trailing dots":      {"Song Title... ", "Song Title"},
+		"leading dot":        {".hidden", "_hidden"},
+		"relative path":      {"..", "_"},
+		"reserved name":      {"con", "_con"},
+		"reserved with ext":  {"NUL.txt", "_NUL.txt"},
+		"reserved substring": {"Console.txt", "Console.txt"},
+		"empty":              {"   ", "_"},
+		"invalid utf-8":      {"a\xffb", "a_b"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			if actual := Sanitize(c.v); actual != c.expected {
+				t.Errorf("Sanitize(%q) = %q, expected %q", c.v, actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	cases := map[string]struct {
+		v        string
+		maxLen   int
+		expected string
+	}{
+		"short":             {"Song.txt", 20, "Song.txt"},
+		"keep extension":    {"A very long song title.txt", 12, "A very l.txt"},
+		"trailing space":    {"A very long song title.txt", 11, "A very.txt"},
+		"multi-byte":        {"Ümläüte.txt", 10, "Ümlä.txt"},
+		"no extension":      {"Mr. Blue Sky", 4, "Mr"},
+		"extension too big": {"a.verylongext", 5, "a.ver"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual := Truncate(c.v, c.maxLen)
+			if actual != c.expected {
+				t.Errorf("Truncate(%q, %d) = %q, expected %q", c.v, c.maxLen, actual, c.expected)
+			}
+			if len(actual) > c.maxLen {
+				t.Errorf("Truncate(%q, %d) returned %d bytes, expected at most %d", c.v, c.maxLen, len(actual), c.maxLen)
+			}
+		})
+	}
+}
+
+func TestSet_Add(t *testing.T) {
+	var s Set
+	names := []string{"Song.txt", "song.TXT", "Song.txt", "Song (2).txt", "Mr. Blue Sky"}
+	expected := []string{"Song.txt", "song (2).TXT", "Song (3).txt", "Song (2) (2).txt", "Mr. Blue Sky"}
+	for i, name := range names {
+		if actual := s.Add(name); actual != expected[i] {
+			t.Errorf("Add(%q) = %q, expected %q", name, actual, expected[i])
+		}
+	}
+}
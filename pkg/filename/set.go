@@ -0,0 +1,34 @@
+package filename
+
+import (
+	"path"
+	"strconv"
+	"strings"
+)
+
+// A Set keeps track of the names in a single directory.
+// Names are compared ignoring case because Windows and macOS use case-insensitive file systems by default.
+// The zero value is an empty set.
+type Set struct {
+	names map[string]bool
+}
+
+// Add adds name to s and returns it.
+// If s already contains name, a number is inserted before the extension (e.g. "Name (2).txt").
+// The first unused number (starting with 2) is used.
+// Adding the same names in the same order always produces the same results.
+func (s *Set) Add(name string) string {
+	if s.names == nil {
+		s.names = make(map[string]bool)
+	}
+	unique := name
+	ext := path.Ext(name)
+	if strings.ContainsRune(ext, ' ') {
+		ext = ""
+	}
+	for i := 2; s.names[strings.ToLower(unique)]; i++ {
+		unique = name[:len(name)-len(ext)] + " (" + strconv.Itoa(i) + ")" + ext
+	}
+	s.names[strings.ToLower(unique)] = true
+	return unique
+}
@@ -0,0 +1,172 @@
+package filename
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Template is a parsed file name template.
+// See the package documentation for the template syntax.
+// The zero value is an empty template that renders as the empty string.
+type Template struct {
+	text  string
+	parts []part
+}
+
+// part is a single component of a Template.
+// Exactly one of the fields is set.
+type part struct {
+	literal  string
+	field    string
+	optional []part
+}
+
+// Parse parses text into a Template.
+func Parse(text string) (*Template, error) {
+	parts, rest, err := parseParts(text, false)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, errors.New("filename: unexpected '>'")
+	}
+	return &Template{text, parts}, nil
+}
+
+// MustParse is like Parse but panics if text cannot be parsed.
+func MustParse(text string) *Template {
+	t, err := Parse(text)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// parseParts parses text until the end of the input or until an unmatched '>'.
+// If nested is true, text is the content of an optional part and must be terminated by '>'.
+// The remaining input starting at the terminating '>' is returned.
+func parseParts(text string, nested bool) ([]part, string, error) {
+	parts := make([]part, 0)
+	for text != "" {
+		i := strings.IndexAny(text, "{}<>")
+		if i < 0 {
+			parts = append(parts, part{literal: text})
+			text = ""
+			break
+		}
+		if i > 0 {
+			parts = append(parts, part{literal: text[:i]})
+		}
+		switch text[i] {
+		case '{':
+			end := strings.IndexByte(text[i:], '}')
+			if end < 0 {
+				return nil, "", errors.New("filename: unterminated placeholder")
+			}
+			name := text[i+1 : i+end]
+			if !validField(name) {
+				return nil, "", fmt.Errorf("filename: invalid placeholder %q", name)
+			}
+			parts = append(parts, part{field: name})
+			text = text[i+end+1:]
+		case '}':
+			return nil, "", errors.New("filename: unexpected '}'")
+		case '<':
+			optional, rest, err := parseParts(text[i+1:], true)
+			if err != nil {
+				return nil, "", err
+			}
+			parts = append(parts, part{optional: optional})
+			text = rest[1:]
+		case '>':
+			return parts, text[i:], nil
+		}
+	}
+	if nested {
+		return nil, "", errors.New("filename: unterminated optional part")
+	}
+	return parts, text, nil
+}
+
+// validField reports whether name is a valid placeholder name.
+// Placeholder names consist of lowercase ASCII letters, digits and hyphens.
+func validField(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// Fields returns the names of all placeholders in t, in order of their first occurrence.
+func (t *Template) Fields() []string {
+	fields := make([]string, 0)
+	var collect func([]part)
+	collect = func(parts []part) {
+		for _, p := range parts {
+			if p.field != "" && !slices.Contains(fields, p.field) {
+				fields = append(fields, p.field)
+			}
+			collect(p.optional)
+		}
+	}
+	collect(t.parts)
+	return fields
+}
+
+// Execute renders t using the specified values.
+// Placeholders without a value render as the empty string.
+// The result is not sanitized.
+func (t *Template) Execute(values map[string]string) string {
+	var b strings.Builder
+	execute(&b, t.parts, values)
+	return b.String()
+}
+
+// execute writes parts to b.
+// The return value indicates whether all placeholders in parts had non-empty values.
+func execute(b *strings.Builder, parts []part, values map[string]string) bool {
+	complete := true
+	for _, p := range parts {
+		switch {
+		case p.field != "":
+			v := values[p.field]
+			complete = complete && v != ""
+			b.WriteString(v)
+		case p.optional != nil:
+			var sub strings.Builder
+			if execute(&sub, p.optional, values) {
+				b.WriteString(sub.String())
+			}
+		default:
+			b.WriteString(p.literal)
+		}
+	}
+	return complete
+}
+
+// String returns the source text of t.
+func (t *Template) String() string {
+	return t.text
+}
+
+// MarshalText encodes t as its source text.
+func (t *Template) MarshalText() ([]byte, error) {
+	return []byte(t.text), nil
+}
+
+// UnmarshalText parses text into t.
+func (t *Template) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*t = *parsed
+	return nil
+}
@@ -0,0 +1,66 @@
+package filename
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := map[string]struct {
+		v       string
+		wantErr bool
+	}{
+		"literal":                 {"song.txt", false},
+		"placeholders":            {"{artist} - {title}{ext}", false},
+		"optional":                {"{title}< [{kind}]>{ext}", false},
+		"nested optional":         {"{title}<< ({year})> [{kind}]>", false},
+		"empty placeholder":       {"{}", true},
+		"invalid placeholder":     {"{Artist}", true},
+		"unterminated":            {"{artist", true},
+		"unexpected brace":        {"artist}", true},
+		"unterminated optional":   {"<{kind}", true},
+		"unexpected angle":        {"{kind}>", true},
+		"placeholder in brackets": {"[{kind}]", false},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(c.v)
+			if err != nil && !c.wantErr {
+				t.Errorf("Parse(%q) returned an unexpected error: %s", c.v, err)
+			} else if err == nil && c.wantErr {
+				t.Errorf("Parse(%q) did not return an error, but an error was expected", c.v)
+			}
+		})
+	}
+}
+
+func TestTemplate_Execute(t *testing.T) {
+	values := map[string]string{"artist": "Queen", "title": "Bohemian Rhapsody", "ext": ".txt"}
+	cases := map[string]struct {
+		template string
+		expected string
+	}{
+		"placeholders":     {"{artist} - {title}{ext}", "Queen - Bohemian Rhapsody.txt"},
+		"missing value":    {"{artist} - {title} [{kind}]{ext}", "Queen - Bohemian Rhapsody [].txt"},
+		"omitted optional": {"{artist} - {title}< [{kind}]>{ext}", "Queen - Bohemian Rhapsody.txt"},
+		"present optional": {"{artist}< - {title}>{ext}", "Queen - Bohemian Rhapsody.txt"},
+		"nested optional":  {"{title}<<, {year}> by {artist}>", "Bohemian Rhapsody by Queen"},
+		"unknown field":    {"{title}{genre}", "Bohemian Rhapsody"},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual := MustParse(c.template).Execute(values)
+			if actual != c.expected {
+				t.Errorf("Execute(values) for template %q = %q, expected %q", c.template, actual, c.expected)
+			}
+		})
+	}
+}
+
+func TestTemplate_Fields(t *testing.T) {
+	tmpl := MustParse("{artist} - {title}< [{kind}]>< ({artist})>{ext}")
+	expected := []string{"artist", "title", "kind", "ext"}
+	if actual := tmpl.Fields(); !slices.Equal(actual, expected) {
+		t.Errorf("Fields() = %q, expected %q", actual, expected)
+	}
+}
@@ -0,0 +1,52 @@
+// Package csv implements a request decoder and a response encoder for CSV data.
+// When this package is imported the encoder and decoder are automatically registered.
+package csv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/Karaoke-Manager/karman/pkg/mediatype"
+	"github.com/Karaoke-Manager/karman/pkg/render"
+)
+
+func init() {
+	render.RegisterDecoder(Decode, "text/csv")
+	render.RegisterEncoder(Encode, "text/csv")
+}
+
+// Decode reads CSV records from r into v.
+// v must be a *[][]string.
+// Records may have a varying number of fields.
+func Decode(r io.Reader, _ mediatype.MediaType, v any) (err error) {
+	defer func() {
+		_, cErr := io.Copy(io.Discard, r)
+		if err == nil {
+			err = cErr
+		}
+	}()
+	records, ok := v.(*[][]string)
+	if !ok {
+		panic(fmt.Sprintf("unsupported type %T", v))
+	}
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	*records, err = reader.ReadAll()
+	return err
+}
+
+// Encode writes v as CSV records.
+// v must either be a [][]string or implement a CSV() method returning the records.
+func Encode(w io.Writer, v any) error {
+	var records [][]string
+	switch x := v.(type) {
+	case [][]string:
+		records = x
+	case interface{ CSV() [][]string }:
+		records = x.CSV()
+	default:
+		panic(fmt.Sprintf("cannot encode value of type %T", v))
+	}
+	return csv.NewWriter(w).WriteAll(records)
+}
@@ -0,0 +1,10 @@
+// Package sqlite implements a minimal reader for SQLite 3 database files.
+//
+// The reader is intended for importing data from other applications that store their data in SQLite,
+// such as UltraStar Deluxe.
+// It can read the schema of a database and all rows of its tables.
+// There is no support for SQL queries, indexes, writing, or rollback journals and write-ahead logs.
+// Changes that have not been checkpointed into the main database file are not visible.
+//
+// See https://www.sqlite.org/fileformat.html for a description of the file format.
+package sqlite
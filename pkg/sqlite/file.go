@@ -0,0 +1,335 @@
+package sqlite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"unicode/utf16"
+)
+
+// These errors are returned when a database file cannot be read.
+var (
+	ErrInvalidHeader = errors.New("not an SQLite 3 database file")
+	ErrCorrupt       = errors.New("database file is corrupt")
+	ErrNoSuchTable   = errors.New("no such table")
+)
+
+// headerMagic is the first 16 bytes of every SQLite 3 database file.
+const headerMagic = "SQLite format 3\x00"
+
+// B-tree page types.
+const (
+	pageInteriorTable = 0x05
+	pageLeafTable     = 0x0D
+)
+
+// Text encodings of a database.
+const (
+	encodingUTF8    = 1
+	encodingUTF16LE = 2
+	encodingUTF16BE = 3
+)
+
+// maxDepth is the maximum depth of a table b-tree.
+// The limit bounds the recursion for corrupt files.
+const maxDepth = 64
+
+// maxPayloadSize is the maximum size of a single row.
+// The limit protects against excessive allocations for corrupt files.
+const maxPayloadSize = 64 << 20
+
+// A DB is an SQLite database file opened for reading.
+type DB struct {
+	r        io.ReaderAt
+	pageSize int
+	usable   int // usable size of each page
+	pages    int // number of pages in the database
+	encoding int
+}
+
+// Open reads the header of the database file in r.
+// r must remain valid as long as the DB is used.
+func Open(r io.ReaderAt) (*DB, error) {
+	header := make([]byte, 100)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, ErrInvalidHeader
+	}
+	if string(header[:16]) != headerMagic {
+		return nil, ErrInvalidHeader
+	}
+	db := &DB{r: r}
+	db.pageSize = int(binary.BigEndian.Uint16(header[16:18]))
+	if db.pageSize == 1 {
+		db.pageSize = 65536
+	}
+	if db.pageSize < 512 || db.pageSize&(db.pageSize-1) != 0 {
+		return nil, ErrInvalidHeader
+	}
+	db.usable = db.pageSize - int(header[20])
+	if db.usable < 480 {
+		return nil, ErrInvalidHeader
+	}
+	db.pages = int(binary.BigEndian.Uint32(header[28:32]))
+	db.encoding = int(binary.BigEndian.Uint32(header[56:60]))
+	switch db.encoding {
+	case 0:
+		// empty databases do not specify an encoding
+		db.encoding = encodingUTF8
+	case encodingUTF8, encodingUTF16LE, encodingUTF16BE:
+	default:
+		return nil, ErrInvalidHeader
+	}
+	return db, nil
+}
+
+// page reads the page with the specified number.
+// Pages are numbered starting at 1.
+func (db *DB) page(n int) ([]byte, error) {
+	if n < 1 || (db.pages > 0 && n > db.pages) {
+		return nil, ErrCorrupt
+	}
+	p := make([]byte, db.pageSize)
+	if _, err := db.r.ReadAt(p, int64(n-1)*int64(db.pageSize)); err != nil {
+		return nil, fmt.Errorf("reading page %d: %w", n, ErrCorrupt)
+	}
+	return p, nil
+}
+
+// walkTable calls fn for each row of the table b-tree with the specified root page in rowid order.
+func (db *DB) walkTable(root int, fn func(rowid int64, payload []byte) error) error {
+	return db.walkPage(root, 0, make(map[int]bool), fn)
+}
+
+// walkPage calls fn for each row in the b-tree page n and its children.
+// visited contains the pages that have already been walked.
+// In a valid b-tree every page is reachable by exactly one path,
+// so a page that is visited twice indicates a corrupt file.
+func (db *DB) walkPage(n int, depth int, visited map[int]bool, fn func(rowid int64, payload []byte) error) error {
+	if depth > maxDepth || visited[n] {
+		return ErrCorrupt
+	}
+	visited[n] = true
+	p, err := db.page(n)
+	if err != nil {
+		return err
+	}
+	offset := 0
+	if n == 1 {
+		// page 1 contains the database header
+		offset = 100
+	}
+	if offset+8 > len(p) {
+		return ErrCorrupt
+	}
+	typ := p[offset]
+	cells := int(binary.BigEndian.Uint16(p[offset+3 : offset+5]))
+	headerSize := 8
+	if typ == pageInteriorTable {
+		headerSize = 12
+	} else if typ != pageLeafTable {
+		return fmt.Errorf("page %d is not a table page: %w", n, ErrCorrupt)
+	}
+	pointers := offset + headerSize
+	if pointers+2*cells > len(p) {
+		return ErrCorrupt
+	}
+	for i := 0; i < cells; i++ {
+		cell := int(binary.BigEndian.Uint16(p[pointers+2*i:]))
+		if cell >= db.usable {
+			return ErrCorrupt
+		}
+		if typ == pageInteriorTable {
+			if cell+4 > len(p) {
+				return ErrCorrupt
+			}
+			if err = db.walkPage(int(binary.BigEndian.Uint32(p[cell:])), depth+1, visited, fn); err != nil {
+				return err
+			}
+			continue
+		}
+		rowid, payload, err := db.leafCell(p[:db.usable], cell)
+		if err != nil {
+			return err
+		}
+		if err = fn(rowid, payload); err != nil {
+			return err
+		}
+	}
+	if typ == pageInteriorTable {
+		return db.walkPage(int(binary.BigEndian.Uint32(p[offset+8:])), depth+1, visited, fn)
+	}
+	return nil
+}
+
+// leafCell reads the cell at offset in the table leaf page p.
+// The payload of the cell is read from overflow pages as necessary.
+func (db *DB) leafCell(p []byte, offset int) (int64, []byte, error) {
+	size, n := varint(p[offset:])
+	if n == 0 || size < 0 {
+		return 0, nil, ErrCorrupt
+	}
+	offset += n
+	rowid, n := varint(p[offset:])
+	if n == 0 {
+		return 0, nil, ErrCorrupt
+	}
+	offset += n
+	// The payload cannot be larger than the database itself.
+	if size > maxPayloadSize || (db.pages > 0 && size > int64(db.pages)*int64(db.usable)) {
+		return 0, nil, ErrCorrupt
+	}
+
+	local := db.localSize(int(min(size, math.MaxInt32)))
+	if offset+local > len(p) {
+		return 0, nil, ErrCorrupt
+	}
+	// The payload grows as overflow pages are read.
+	payload := bytes.Clone(p[offset : offset+local])
+	if int64(local) == size {
+		return rowid, payload, nil
+	}
+	if offset+local+4 > len(p) {
+		return 0, nil, ErrCorrupt
+	}
+	next := int(binary.BigEndian.Uint32(p[offset+local:]))
+	for visited := 0; int64(len(payload)) < size; visited++ {
+		if next == 0 || visited > db.pages {
+			return 0, nil, ErrCorrupt
+		}
+		overflow, err := db.page(next)
+		if err != nil {
+			return 0, nil, err
+		}
+		next = int(binary.BigEndian.Uint32(overflow))
+		chunk := overflow[4:db.usable]
+		payload = append(payload, chunk[:min(len(chunk), int(size)-len(payload))]...)
+	}
+	return rowid, payload, nil
+}
+
+// localSize returns the number of bytes of a payload of the specified size that are stored in a table leaf page.
+// The remaining bytes are stored in overflow pages.
+func (db *DB) localSize(size int) int {
+	maxLocal := db.usable - 35
+	if size <= maxLocal {
+		return size
+	}
+	minLocal := (db.usable-12)*32/255 - 23
+	k := minLocal + (size-minLocal)%(db.usable-4)
+	if k <= maxLocal {
+		return k
+	}
+	return minLocal
+}
+
+// varint decodes a variable-length integer from b.
+// The second return value is the number of bytes read or 0 if b is too short.
+func varint(b []byte) (int64, int) {
+	var v uint64
+	for i := 0; i < 9; i++ {
+		if i >= len(b) {
+			return 0, 0
+		}
+		if i == 8 {
+			return int64(v<<8 | uint64(b[i])), 9
+		}
+		v = v<<7 | uint64(b[i]&0x7F)
+		if b[i]&0x80 == 0 {
+			return int64(v), i + 1
+		}
+	}
+	return 0, 0
+}
+
+// record decodes the values of a record in the SQLite record format.
+// Values are decoded into nil, int64, float64, string or []byte.
+func (db *DB) record(payload []byte) ([]any, error) {
+	headerSize, n := varint(payload)
+	if n == 0 || headerSize < int64(n) || headerSize > int64(len(payload)) {
+		return nil, ErrCorrupt
+	}
+	header := payload[n:headerSize]
+	body := payload[headerSize:]
+	values := make([]any, 0, 8)
+	for len(header) > 0 {
+		typ, n := varint(header)
+		if n == 0 {
+			return nil, ErrCorrupt
+		}
+		header = header[n:]
+		size := serialSize(typ)
+		if size < 0 || size > len(body) {
+			return nil, ErrCorrupt
+		}
+		data := body[:size]
+		body = body[size:]
+		switch {
+		case typ == 0:
+			values = append(values, nil)
+		case typ >= 1 && typ <= 6:
+			values = append(values, bigEndianInt(data))
+		case typ == 7:
+			values = append(values, math.Float64frombits(binary.BigEndian.Uint64(data)))
+		case typ == 8:
+			values = append(values, int64(0))
+		case typ == 9:
+			values = append(values, int64(1))
+		case typ >= 12 && typ%2 == 0:
+			values = append(values, bytes.Clone(data))
+		case typ >= 13:
+			values = append(values, db.text(data))
+		default:
+			return nil, ErrCorrupt
+		}
+	}
+	return values, nil
+}
+
+// serialSize returns the size in bytes of a value with the specified serial type or -1 if the type is invalid.
+func serialSize(typ int64) int {
+	switch {
+	case typ < 0 || typ == 10 || typ == 11:
+		return -1
+	case typ <= 4:
+		return int(typ)
+	case typ == 5:
+		return 6
+	case typ == 6 || typ == 7:
+		return 8
+	case typ <= 9:
+		return 0
+	case typ > math.MaxInt32:
+		return -1
+	default:
+		return int((typ - 12) / 2)
+	}
+}
+
+// bigEndianInt decodes a big-endian two's complement integer of 1 to 8 bytes.
+func bigEndianInt(b []byte) int64 {
+	v := int64(int8(b[0]))
+	for _, c := range b[1:] {
+		v = v<<8 | int64(c)
+	}
+	return v
+}
+
+// text decodes a string in the text encoding of db.
+func (db *DB) text(b []byte) string {
+	if db.encoding == encodingUTF8 {
+		return string(b)
+	}
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		if db.encoding == encodingUTF16LE {
+			u[i] = binary.LittleEndian.Uint16(b[2*i:])
+		} else {
+			u[i] = binary.BigEndian.Uint16(b[2*i:])
+		}
+	}
+	return strings.ToValidUTF8(string(utf16.Decode(u)), "�")
+}
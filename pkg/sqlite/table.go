@@ -0,0 +1,313 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// A Table contains all rows of a database table.
+type Table struct {
+	// Name is the name of the table.
+	Name string
+	// Columns contains the names of the table columns in the order they were declared.
+	Columns []string
+	// Rows contains the values of each row in rowid order.
+	// Each row has exactly one value per column.
+	// Values are nil, int64, float64, string or []byte.
+	Rows [][]any
+}
+
+// Column returns the index of the column with the specified name or -1 if the table has no such column.
+// Column names are compared case-insensitively, as in SQLite.
+func (t *Table) Column(name string) int {
+	for i, c := range t.Columns {
+		if strings.EqualFold(c, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// schemaEntry is a row of the sqlite_master table.
+type schemaEntry struct {
+	typ      string
+	name     string
+	rootPage int
+	sql      string
+}
+
+// schema reads the sqlite_master table of db.
+func (db *DB) schema() ([]schemaEntry, error) {
+	var entries []schemaEntry
+	err := db.walkTable(1, func(_ int64, payload []byte) error {
+		values, err := db.record(payload)
+		if err != nil {
+			return err
+		}
+		if len(values) < 5 {
+			return ErrCorrupt
+		}
+		entry := schemaEntry{}
+		entry.typ, _ = values[0].(string)
+		entry.name, _ = values[1].(string)
+		root, _ := values[3].(int64)
+		entry.rootPage = int(root)
+		entry.sql, _ = values[4].(string)
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
+
+// Tables returns the names of all tables in db.
+func (db *DB) Tables() ([]string, error) {
+	entries, err := db.schema()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.typ == "table" && e.rootPage > 0 {
+			names = append(names, e.name)
+		}
+	}
+	return names, nil
+}
+
+// Table reads all rows of the table with the specified name.
+// Table names are compared case-insensitively.
+// If db has no such table, ErrNoSuchTable is returned.
+func (db *DB) Table(name string) (*Table, error) {
+	entries, err := db.schema()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.typ != "table" || !strings.EqualFold(e.name, name) {
+			continue
+		}
+		if e.rootPage <= 0 {
+			// virtual tables do not have any pages
+			return nil, fmt.Errorf("%s: %w", name, ErrNoSuchTable)
+		}
+		return db.readTable(e)
+	}
+	return nil, fmt.Errorf("%s: %w", name, ErrNoSuchTable)
+}
+
+// readTable reads all rows of the table described by e.
+func (db *DB) readTable(e schemaEntry) (*Table, error) {
+	columns, rowid := parseColumns(e.sql)
+	if columns == nil {
+		return nil, fmt.Errorf("%s: unsupported table definition: %w", e.name, ErrCorrupt)
+	}
+	t := &Table{Name: e.name, Columns: columns}
+	err := db.walkTable(e.rootPage, func(id int64, payload []byte) error {
+		values, err := db.record(payload)
+		if err != nil {
+			return err
+		}
+		row := make([]any, len(columns))
+		// columns added by ALTER TABLE are missing in older records and are NULL
+		copy(row, values)
+		if rowid >= 0 {
+			// an INTEGER PRIMARY KEY column is stored as NULL and aliases the rowid
+			row[rowid] = id
+		}
+		t.Rows = append(t.Rows, row)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// parseColumns extracts the column names from a CREATE TABLE statement.
+// The second return value is the index of the column that aliases the rowid or -1 if there is no such column.
+// If the statement cannot be parsed, the returned column slice is nil.
+func parseColumns(sql string) ([]string, int) {
+	start := strings.IndexByte(sql, '(')
+	end := strings.LastIndexByte(sql, ')')
+	if start < 0 || end <= start || hasKeyword(tokenize(sql[end+1:]), "WITHOUT") {
+		// WITHOUT ROWID tables are stored as index b-trees which are not supported
+		return nil, -1
+	}
+	var columns, types []string
+	rowid := -1
+	for _, def := range splitDefinitions(sql[start+1 : end]) {
+		tokens := tokenize(def)
+		if len(tokens) == 0 {
+			continue
+		}
+		switch strings.ToUpper(tokens[0]) {
+		case "CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK", "FOREIGN":
+			// table constraint: PRIMARY KEY (column) on an INTEGER column aliases the rowid
+			if pk := primaryKeyColumns(tokens); len(pk) == 1 {
+				for i, c := range columns {
+					if strings.EqualFold(c, pk[0]) && strings.EqualFold(types[i], "INTEGER") {
+						rowid = i
+					}
+				}
+			}
+			continue
+		}
+		columns = append(columns, unquote(tokens[0]))
+		types = append(types, "")
+		if len(tokens) > 1 && !isConstraintKeyword(tokens[1]) {
+			types[len(types)-1] = tokens[1]
+		}
+		if strings.EqualFold(types[len(types)-1], "INTEGER") && hasKeyword(tokens, "PRIMARY") && !hasKeyword(tokens, "DESC") {
+			rowid = len(columns) - 1
+		}
+	}
+	if len(columns) == 0 {
+		return nil, -1
+	}
+	return columns, rowid
+}
+
+// splitDefinitions splits the body of a CREATE TABLE statement at top-level commas.
+func splitDefinitions(body string) []string {
+	var defs []string
+	depth, start := 0, 0
+	var quote rune
+	for i, c := range body {
+		switch {
+		case quote != 0:
+			if c == quote || (quote == '[' && c == ']') {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			defs = append(defs, body[start:i])
+			start = i + 1
+		}
+	}
+	return append(defs, body[start:])
+}
+
+// tokenize splits a column or constraint definition into identifiers, quoted names and punctuation.
+// Parenthesized expressions are returned as a single token.
+func tokenize(def string) []string {
+	var tokens []string
+	runes := []rune(def)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			j := i + 1
+			for j < len(runes) {
+				if runes[j] == closing {
+					if closing == ']' || j+1 >= len(runes) || runes[j+1] != closing {
+						break
+					}
+					// doubled quotes are escaped quotes
+					j++
+				}
+				j++
+			}
+			tokens = append(tokens, string(runes[i:min(j+1, len(runes))]))
+			i = j + 1
+		case c == '(':
+			depth, j := 0, i
+			for ; j < len(runes); j++ {
+				if runes[j] == '(' {
+					depth++
+				} else if runes[j] == ')' {
+					depth--
+					if depth == 0 {
+						break
+					}
+				}
+			}
+			tokens = append(tokens, string(runes[i:min(j+1, len(runes))]))
+			i = j + 1
+		case unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_' || runes[j] == '$') {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		default:
+			tokens = append(tokens, string(c))
+			i++
+		}
+	}
+	return tokens
+}
+
+// unquote removes SQL identifier quotes from name.
+func unquote(name string) string {
+	if len(name) < 2 {
+		return name
+	}
+	switch name[0] {
+	case '"', '`', '\'':
+		if name[len(name)-1] == name[0] {
+			q := name[:1]
+			return strings.ReplaceAll(name[1:len(name)-1], q+q, q)
+		}
+	case '[':
+		if name[len(name)-1] == ']' {
+			return name[1 : len(name)-1]
+		}
+	}
+	return name
+}
+
+// hasKeyword reports whether tokens contain the specified keyword.
+func hasKeyword(tokens []string, keyword string) bool {
+	for _, t := range tokens {
+		if strings.EqualFold(t, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// isConstraintKeyword reports whether token starts a column constraint.
+func isConstraintKeyword(token string) bool {
+	switch strings.ToUpper(token) {
+	case "CONSTRAINT", "PRIMARY", "NOT", "NULL", "UNIQUE", "CHECK", "DEFAULT", "COLLATE", "REFERENCES", "GENERATED", "AS":
+		return true
+	}
+	return false
+}
+
+// primaryKeyColumns returns the columns of a PRIMARY KEY table constraint.
+// If tokens do not describe a primary key constraint, nil is returned.
+func primaryKeyColumns(tokens []string) []string {
+	for i := 0; i+2 < len(tokens); i++ {
+		if !strings.EqualFold(tokens[i], "PRIMARY") || !strings.EqualFold(tokens[i+1], "KEY") {
+			continue
+		}
+		list := tokens[i+2]
+		if !strings.HasPrefix(list, "(") {
+			return nil
+		}
+		var columns []string
+		for _, def := range splitDefinitions(strings.TrimSuffix(list[1:], ")")) {
+			t := tokenize(def)
+			if len(t) == 0 || hasKeyword(t, "DESC") {
+				return nil
+			}
+			columns = append(columns, unquote(t[0]))
+		}
+		return columns
+	}
+	return nil
+}
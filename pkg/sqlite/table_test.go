@@ -0,0 +1,190 @@
+package sqlite
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// open opens the database file testdata/name.
+func open(t *testing.T, name string) *DB {
+	f, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("os.Open(%q) returned an unexpected error: %s", name, err)
+	}
+	t.Cleanup(func() { _ = f.Close() })
+	db, err := Open(f)
+	if err != nil {
+		t.Fatalf("Open(%q) returned an unexpected error: %s", name, err)
+	}
+	return db
+}
+
+func TestOpen(t *testing.T) {
+	_, err := Open(strings.NewReader("This is not a database file, but it is long enough to contain a full database header. Really."))
+	if !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("Open(invalid) returned error %v, expected %v", err, ErrInvalidHeader)
+	}
+	_, err = Open(strings.NewReader(headerMagic))
+	if !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("Open(short) returned error %v, expected %v", err, ErrInvalidHeader)
+	}
+}
+
+func TestDB_Tables(t *testing.T) {
+	db := open(t, "usdx.db")
+	names, err := db.Tables()
+	if err != nil {
+		t.Fatalf("db.Tables() returned an unexpected error: %s", err)
+	}
+	if strings.Join(names, ",") != "us_songs,us_scores,misc" {
+		t.Errorf("db.Tables() = %v, expected [us_songs us_scores misc]", names)
+	}
+}
+
+func TestDB_Tables_Corrupt(t *testing.T) {
+	// A database with a single page whose only cell claims a payload of about 2^56 bytes.
+	b := make([]byte, 512)
+	copy(b, headerMagic)
+	binary.BigEndian.PutUint16(b[16:], 512)
+	binary.BigEndian.PutUint32(b[28:], 1)
+	b[100] = pageLeafTable
+	binary.BigEndian.PutUint16(b[103:], 1)
+	binary.BigEndian.PutUint16(b[108:], 200)
+	copy(b[200:], []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x7F, 0x01})
+
+	db, err := Open(bytes.NewReader(b))
+	if err != nil {
+		t.Fatalf("Open(corrupt) returned an unexpected error: %s", err)
+	}
+	if _, err = db.Tables(); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("db.Tables() returned error %v, expected %v", err, ErrCorrupt)
+	}
+}
+
+func TestDB_Tables_SharedPages(t *testing.T) {
+	// testdata/dag.db contains a chain of 40 interior pages.
+	// Both cells and the right pointer of each page point to the next page,
+	// so a naive walk would visit the last page 3^40 times.
+	db := open(t, "dag.db")
+	if _, err := db.Tables(); !errors.Is(err, ErrCorrupt) {
+		t.Errorf("db.Tables() returned error %v, expected %v", err, ErrCorrupt)
+	}
+}
+
+func TestDB_Table(t *testing.T) {
+	db := open(t, "usdx.db")
+
+	t.Run("rowid alias", func(t *testing.T) {
+		songs, err := db.Table("US_SONGS")
+		if err != nil {
+			t.Fatalf("db.Table(%q) returned an unexpected error: %s", "US_SONGS", err)
+		}
+		if strings.Join(songs.Columns, ",") != "ID,Artist,Title,TimesPlayed,Rating" {
+			t.Errorf("songs.Columns = %v, expected [ID Artist Title TimesPlayed Rating]", songs.Columns)
+		}
+		if len(songs.Rows) != 200 {
+			t.Fatalf("len(songs.Rows) = %d, expected 200", len(songs.Rows))
+		}
+		row := songs.Rows[41]
+		if row[0] != int64(42) || row[1] != "Artist 42" || row[2] != "Title 42" || row[3] != int64(126) || row[4] != nil {
+			t.Errorf("songs.Rows[41] = %v, expected [42 Artist 42 Title 42 126 <nil>]", row)
+		}
+		if rating := songs.Rows[199][songs.Column("rating")]; rating != int64(5) {
+			t.Errorf("songs.Rows[199][Rating] = %v, expected 5", rating)
+		}
+	})
+
+	t.Run("quoted names", func(t *testing.T) {
+		scores, err := db.Table("us_scores")
+		if err != nil {
+			t.Fatalf("db.Table(%q) returned an unexpected error: %s", "us_scores", err)
+		}
+		if scores.Column("Player") != 2 {
+			t.Errorf("scores.Column(%q) = %d, expected 2", "Player", scores.Column("Player"))
+		}
+		if len(scores.Rows) != 200 {
+			t.Errorf("len(scores.Rows) = %d, expected 200", len(scores.Rows))
+		}
+	})
+
+	t.Run("values", func(t *testing.T) {
+		misc, err := db.Table("misc")
+		if err != nil {
+			t.Fatalf("db.Table(%q) returned an unexpected error: %s", "misc", err)
+		}
+		values := make(map[string]any, len(misc.Rows))
+		for _, row := range misc.Rows {
+			values[row[0].(string)] = row[1]
+		}
+		if b, _ := values["blob"].([]byte); !bytes.Equal(b, bytes.Repeat(makeRange(256), 10)) {
+			t.Errorf("misc[blob] has unexpected content")
+		}
+		if values["text"] != strings.Repeat("Ünïcødé ", 300) {
+			t.Errorf("misc[text] has unexpected content")
+		}
+		if values["real"] != 1.5 {
+			t.Errorf("misc[real] = %v, expected 1.5", values["real"])
+		}
+		if values["neg"] != int64(-1234567890123) {
+			t.Errorf("misc[neg] = %v, expected -1234567890123", values["neg"])
+		}
+		if values["null"] != nil {
+			t.Errorf("misc[null] = %v, expected <nil>", values["null"])
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		_, err := db.Table("foo")
+		if !errors.Is(err, ErrNoSuchTable) {
+			t.Errorf("db.Table(%q) returned error %v, expected %v", "foo", err, ErrNoSuchTable)
+		}
+	})
+
+	t.Run("utf-16", func(t *testing.T) {
+		db := open(t, "utf16.db")
+		table, err := db.Table("t")
+		if err != nil {
+			t.Fatalf("db.Table(%q) returned an unexpected error: %s", "t", err)
+		}
+		if len(table.Rows) != 1 || table.Rows[0][0] != "Bjørk 🎤" {
+			t.Errorf("table.Rows = %v, expected [[Bjørk 🎤]]", table.Rows)
+		}
+	})
+}
+
+func TestParseColumns(t *testing.T) {
+	cases := map[string]struct {
+		sql     string
+		columns string
+		rowid   int
+	}{
+		"simple":        {"CREATE TABLE t (a, b TEXT)", "a,b", -1},
+		"integer key":   {"CREATE TABLE t (a TEXT, id INTEGER PRIMARY KEY)", "a,id", 1},
+		"int key":       {"CREATE TABLE t (id INT PRIMARY KEY, a)", "id,a", -1},
+		"table key":     {"CREATE TABLE t (id INTEGER, a, PRIMARY KEY (id))", "id,a", 0},
+		"composite key": {"CREATE TABLE t (id INTEGER, a, PRIMARY KEY (id, a))", "id,a", -1},
+		"constraints":   {"CREATE TABLE t (a DEFAULT (1, 2), b CHECK (b > 0), CONSTRAINT u UNIQUE (a))", "a,b", -1},
+		"quoted":        {"CREATE TABLE t (\"a \"\"b\"\"\", [c d], `e`)", "a \"b\",c d,e", -1},
+		"without rowid": {"CREATE TABLE t (a PRIMARY KEY) WITHOUT ROWID", "", -1},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			columns, rowid := parseColumns(c.sql)
+			if strings.Join(columns, ",") != c.columns || rowid != c.rowid {
+				t.Errorf("parseColumns(%q) = %v, %d, expected [%s], %d", c.sql, columns, rowid, c.columns, c.rowid)
+			}
+		})
+	}
+}
+
+func makeRange(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	return b
+}
@@ -9,8 +9,11 @@ import (
 
 	"github.com/hibiken/asynq"
 
+	"github.com/Karaoke-Manager/karman/core/batch"
 	"github.com/Karaoke-Manager/karman/core/media"
+	"github.com/Karaoke-Manager/karman/core/song"
 	"github.com/Karaoke-Manager/karman/core/upload"
+	"github.com/Karaoke-Manager/karman/core/webhook"
 	"github.com/Karaoke-Manager/karman/task/middleware"
 )
 
@@ -25,11 +28,14 @@ type Handler struct {
 	logger *slog.Logger
 	mux    *asynq.ServeMux
 
-	mediaRepo     media.Repository
-	mediaService  media.Service
-	uploadService upload.Service
-	uploadRepo    upload.Repository
-	uploadStore   upload.Store
+	mediaRepo      media.Repository
+	mediaService   media.Service
+	songRepo       song.Repository
+	uploadService  upload.Service
+	uploadRepo     upload.Repository
+	uploadStore    upload.Store
+	webhookService webhook.Service
+	batchService   batch.Service
 }
 
 // NewHandler creates a new Handler instance that can process tasks.
@@ -37,9 +43,12 @@ func NewHandler(
 	logger *slog.Logger,
 	mediaRepo media.Repository,
 	mediaService media.Service,
+	songRepo song.Repository,
 	uploadService upload.Service,
 	uploadRepo upload.Repository,
 	uploadStore upload.Store,
+	webhookService webhook.Service,
+	batchService batch.Service,
 ) *Handler {
 	mux := asynq.NewServeMux()
 	h := &Handler{
@@ -47,14 +56,20 @@ func NewHandler(
 		mux,
 		mediaRepo,
 		mediaService,
+		songRepo,
 		uploadService,
 		uploadRepo,
 		uploadStore,
+		webhookService,
+		batchService,
 	}
 	mux.Use(middleware.Logger(h.logger))
 	mux.HandleFunc(TypePruneMedia, h.HandlePruneMediaTask)
+	mux.HandleFunc(TypePruneSongs, h.HandlePruneSongsTask)
 	mux.HandleFunc(TypePruneUploads, h.HandlePruneUploadsTask)
 	mux.HandleFunc(TypeProcessUpload, h.HandleProcessUploadTask)
+	mux.HandleFunc(TypeDeliverWebhook, h.HandleDeliverWebhookTask)
+	mux.HandleFunc(TypeRunSongBatch, h.HandleRunSongBatchTask)
 	return h
 }
 
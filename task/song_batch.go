@@ -0,0 +1,60 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/batch"
+)
+
+// TypeRunSongBatch is the task type for applying a batch operation to its songs.
+// If the task is retried, only songs without a result are processed.
+//
+// The payload of the task is the UUID of the batch in binary form.
+const TypeRunSongBatch = "song:batch"
+
+// NewRunSongBatchTask creates a new [TypeRunSongBatch] task.
+// Only a single task is created for every batch.
+func NewRunSongBatchTask(id uuid.UUID) *asynq.Task {
+	return asynq.NewTask(TypeRunSongBatch, id[:], asynq.TaskID(fmt.Sprintf("%s:%s", TypeRunSongBatch, id)))
+}
+
+// HandleRunSongBatchTask handles [TypeRunSongBatch] tasks.
+func (h *Handler) HandleRunSongBatchTask(ctx context.Context, task *asynq.Task) error {
+	id, err := uuid.FromBytes(task.Payload())
+	if err != nil {
+		h.logger.WarnContext(ctx, "Could not run song batch.", tint.Err(err))
+		return errors.Join(err, ErrInvalidPayload)
+	}
+	err = h.batchService.RunBatch(ctx, id)
+	if errors.Is(err, core.ErrNotFound) {
+		return fmt.Errorf("batch %s: %w", id, asynq.SkipRetry)
+	}
+	return err
+}
+
+// batchQueue is a batch.Queue that enqueues [TypeRunSongBatch] tasks.
+type batchQueue struct {
+	client *asynq.Client
+}
+
+// NewBatchQueue creates a batch.Queue that schedules batches as [TypeRunSongBatch] tasks via client.
+func NewBatchQueue(client *asynq.Client) batch.Queue {
+	return &batchQueue{client}
+}
+
+// EnqueueBatch enqueues a new [TypeRunSongBatch] task.
+func (q *batchQueue) EnqueueBatch(ctx context.Context, id uuid.UUID) error {
+	_, err := q.client.EnqueueContext(ctx, NewRunSongBatchTask(id))
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		// The batch has already been scheduled.
+		return nil
+	}
+	return err
+}
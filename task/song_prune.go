@@ -0,0 +1,43 @@
+package task
+
+import (
+	"context"
+	"encoding/binary"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/lmittmann/tint"
+)
+
+// TypePruneSongs is the task type for the prune songs task.
+// This task permanently deletes songs that have been in the trash for longer than a retention period.
+// Media files of purged songs are not deleted by this task.
+// Instead, they become orphaned and will be deleted by the next [TypePruneMedia] task.
+//
+// The payload of the task is a single int64 in varint encoding specifying the retention period in seconds.
+const TypePruneSongs = "song:prune"
+
+// NewPruneSongsTask creates a new [TypePruneSongs] task.
+// Songs that have been in the trash for longer than retention will be deleted when this task is executed.
+func NewPruneSongsTask(retention time.Duration) *asynq.Task {
+	payload := binary.AppendVarint(nil, int64(retention/time.Second))
+	return asynq.NewTask(TypePruneSongs, payload, asynq.TaskID(TypePruneSongs))
+}
+
+// HandlePruneSongsTask handles [TypePruneSongs] tasks.
+func (h *Handler) HandlePruneSongsTask(ctx context.Context, task *asynq.Task) error {
+	seconds, n := binary.Varint(task.Payload())
+	if n <= 0 || seconds < 0 {
+		return ErrInvalidPayload
+	}
+	retention := time.Duration(seconds) * time.Second
+	count, err := h.songRepo.PurgeSongs(ctx, retention)
+	if err != nil {
+		h.logger.WarnContext(ctx, "Could not purge deleted songs.", "retention", retention, tint.Err(err))
+		return err
+	}
+	if count > 0 {
+		h.logger.InfoContext(ctx, "Purged deleted songs.", "count", count, "retention", retention)
+	}
+	return nil
+}
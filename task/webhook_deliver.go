@@ -0,0 +1,95 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/lmittmann/tint"
+
+	"github.com/Karaoke-Manager/karman/core"
+	"github.com/Karaoke-Manager/karman/core/event"
+	"github.com/Karaoke-Manager/karman/core/webhook"
+)
+
+// TypeDeliverWebhook is the task type for delivering an event to a webhook.
+// Failed deliveries are retried up to WebhookMaxRetry times using WebhookRetryDelay.
+//
+// The payload of the task is a JSON object containing the UUID of the webhook and the event to be delivered.
+const TypeDeliverWebhook = "webhook:deliver"
+
+// WebhookMaxRetry is the maximum number of retries for a [TypeDeliverWebhook] task.
+const WebhookMaxRetry = 8
+
+// deliverWebhookPayload is the payload of a [TypeDeliverWebhook] task.
+type deliverWebhookPayload struct {
+	Webhook uuid.UUID   `json:"webhook"`
+	Event   event.Event `json:"event"`
+}
+
+// NewDeliverWebhookTask creates a new [TypeDeliverWebhook] task.
+// Only a single task is created for every combination of webhook and event.
+func NewDeliverWebhookTask(id uuid.UUID, e event.Event) (*asynq.Task, error) {
+	payload, err := json.Marshal(deliverWebhookPayload{id, e})
+	if err != nil {
+		return nil, err
+	}
+	return asynq.NewTask(TypeDeliverWebhook, payload,
+		asynq.TaskID(fmt.Sprintf("%s:%s:%s", TypeDeliverWebhook, id, e.ID)),
+		asynq.MaxRetry(WebhookMaxRetry),
+	), nil
+}
+
+// HandleDeliverWebhookTask handles [TypeDeliverWebhook] tasks.
+func (h *Handler) HandleDeliverWebhookTask(ctx context.Context, task *asynq.Task) error {
+	var payload deliverWebhookPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		h.logger.WarnContext(ctx, "Could not deliver webhook.", tint.Err(err))
+		return errors.Join(err, ErrInvalidPayload)
+	}
+	retried, _ := asynq.GetRetryCount(ctx)
+	err := h.webhookService.Deliver(ctx, payload.Webhook, payload.Event, retried+1)
+	if errors.Is(err, core.ErrNotFound) {
+		// The webhook has been deleted in the meantime.
+		return fmt.Errorf("webhook %s: %w", payload.Webhook, asynq.SkipRetry)
+	}
+	return err
+}
+
+// WebhookRetryDelay calculates the delay before a failed [TypeDeliverWebhook] task is retried.
+// The delay grows exponentially from 30 seconds up to 6 hours, with some random jitter.
+func WebhookRetryDelay(n int) time.Duration {
+	delay := 30 * time.Second << min(n, 10)
+	delay = min(delay, 6*time.Hour)
+	jitter := time.Duration(rand.Int64N(int64(delay / 10)))
+	return delay + jitter
+}
+
+// webhookQueue is a webhook.Queue that enqueues [TypeDeliverWebhook] tasks.
+type webhookQueue struct {
+	client *asynq.Client
+}
+
+// NewWebhookQueue creates a webhook.Queue that schedules deliveries as [TypeDeliverWebhook] tasks via client.
+func NewWebhookQueue(client *asynq.Client) webhook.Queue {
+	return &webhookQueue{client}
+}
+
+// EnqueueDelivery enqueues a new [TypeDeliverWebhook] task.
+func (q *webhookQueue) EnqueueDelivery(ctx context.Context, id uuid.UUID, e event.Event) error {
+	task, err := NewDeliverWebhookTask(id, e)
+	if err != nil {
+		return err
+	}
+	_, err = q.client.EnqueueContext(ctx, task)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		// The delivery has already been scheduled.
+		return nil
+	}
+	return err
+}
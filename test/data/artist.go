@@ -0,0 +1,35 @@
+//go:build database
+
+package testdata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// Artist inserts a new artist with the specified name and aliases into the database and returns it.
+func Artist(t *testing.T, db pgxutil.DB, name string, aliases ...string) model.Artist {
+	artist := model.Artist{
+		Name:    name,
+		Aliases: aliases,
+	}
+	row, err := pgxutil.InsertRowReturning(context.TODO(), db, "artists", map[string]any{
+		"name": artist.Name,
+	}, "id, uuid, created_at, updated_at", pgx.RowToStructByName[creationResult])
+	if err != nil {
+		t.Fatalf("testdata.Artist() could not insert into the database: %s", err)
+	}
+	if _, err = db.Exec(context.TODO(), `INSERT INTO artist_aliases (artist_id, name)
+	SELECT $1, n FROM UNNEST($2::TEXT[]) AS n`, row.ID, aliases); err != nil {
+		t.Fatalf("testdata.Artist() could not insert aliases into the database: %s", err)
+	}
+	artist.UUID = row.UUID
+	artist.CreatedAt = row.CreatedAt
+	artist.UpdatedAt = row.UpdatedAt
+	return artist
+}
@@ -0,0 +1,37 @@
+//go:build database
+
+package testdata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// CustomHeader inserts a new custom header with the specified name, type, and aliases into the database and returns it.
+func CustomHeader(t *testing.T, db pgxutil.DB, name string, typ model.HeaderType, aliases ...string) model.CustomHeader {
+	if aliases == nil {
+		aliases = make([]string, 0)
+	}
+	header := model.CustomHeader{
+		Name:    name,
+		Type:    typ,
+		Aliases: aliases,
+	}
+	row, err := pgxutil.InsertRowReturning(context.TODO(), db, "custom_headers", map[string]any{
+		"name":    header.Name,
+		"type":    header.Type,
+		"aliases": header.Aliases,
+	}, "id, uuid, created_at, updated_at", pgx.RowToStructByName[creationResult])
+	if err != nil {
+		t.Fatalf("testdata.CustomHeader() could not insert into the database: %s", err)
+	}
+	header.UUID = row.UUID
+	header.CreatedAt = row.CreatedAt
+	header.UpdatedAt = row.UpdatedAt
+	return header
+}
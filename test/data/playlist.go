@@ -0,0 +1,40 @@
+//go:build database
+
+package testdata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// Playlist inserts a new playlist with the specified name and songs into the database and returns it.
+func Playlist(t *testing.T, db pgxutil.DB, name string, songs ...uuid.UUID) model.Playlist {
+	playlist := model.Playlist{
+		Name:  name,
+		Songs: songs,
+	}
+	if playlist.Songs == nil {
+		playlist.Songs = make([]uuid.UUID, 0)
+	}
+	row, err := pgxutil.InsertRowReturning(context.TODO(), db, "playlists", map[string]any{
+		"name": playlist.Name,
+	}, "id, uuid, created_at, updated_at", pgx.RowToStructByName[creationResult])
+	if err != nil {
+		t.Fatalf("testdata.Playlist() could not insert into the database: %s", err)
+	}
+	if _, err = db.Exec(context.TODO(), `INSERT INTO playlist_songs (playlist_id, song_id, position)
+	SELECT $1, s.id, o.position FROM UNNEST($2::UUID[]) WITH ORDINALITY AS o(uuid, position)
+	JOIN songs AS s ON s.uuid = o.uuid`, row.ID, songs); err != nil {
+		t.Fatalf("testdata.Playlist() could not insert songs into the database: %s", err)
+	}
+	playlist.UUID = row.UUID
+	playlist.CreatedAt = row.CreatedAt
+	playlist.UpdatedAt = row.UpdatedAt
+	return playlist
+}
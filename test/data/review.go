@@ -0,0 +1,44 @@
+//go:build database
+
+package testdata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// ReviewState sets the review state of the song with UUID song.
+func ReviewState(t *testing.T, db pgxutil.DB, song uuid.UUID, state model.ReviewState) {
+	_, err := db.Exec(context.TODO(), `INSERT INTO song_reviews (song_id, state)
+	SELECT id, $2 FROM songs WHERE uuid = $1
+	ON CONFLICT (song_id) DO UPDATE SET state = excluded.state, changed_at = NOW()`, song, state)
+	if err != nil {
+		t.Fatalf("testdata.ReviewState() could not insert into the database: %s", err)
+	}
+}
+
+// Comment inserts a new comment of the specified kind into the thread of the song with UUID song and returns it.
+// The comment is not anchored.
+func Comment(t *testing.T, db pgxutil.DB, song uuid.UUID, kind model.CommentKind, text string) model.SongComment {
+	comment := model.SongComment{
+		Author: "Tester",
+		Kind:   kind,
+		Text:   text,
+	}
+	row, err := pgxutil.SelectRow(context.TODO(), db, `INSERT INTO song_comments (song_id, author, kind, text)
+	SELECT id, $2, $3, $4 FROM songs WHERE uuid = $1
+	RETURNING id, uuid, created_at, updated_at`, []any{song, comment.Author, comment.Kind, comment.Text}, pgx.RowToStructByName[creationResult])
+	if err != nil {
+		t.Fatalf("testdata.Comment() could not insert into the database: %s", err)
+	}
+	comment.UUID = row.UUID
+	comment.CreatedAt = row.CreatedAt
+	comment.UpdatedAt = row.UpdatedAt
+	return comment
+}
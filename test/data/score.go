@@ -0,0 +1,44 @@
+//go:build database
+
+package testdata
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgxutil"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// PlayStats inserts play statistics for the song with the specified UUID into the database and returns them.
+func PlayStats(t *testing.T, db pgxutil.DB, song uuid.UUID, timesPlayed int) model.PlayStats {
+	stats := model.PlayStats{
+		Song:        song,
+		TimesPlayed: timesPlayed,
+		LastPlayed:  time.Now().UTC().Truncate(time.Second),
+	}
+	if _, err := db.Exec(context.TODO(), `INSERT INTO song_plays (song_id, times_played, last_played)
+	SELECT id, $2, $3 FROM songs WHERE uuid = $1`, song, stats.TimesPlayed, stats.LastPlayed); err != nil {
+		t.Fatalf("testdata.PlayStats() could not insert into the database: %s", err)
+	}
+	return stats
+}
+
+// Highscore inserts a highscore for the song with the specified UUID into the database and returns it.
+func Highscore(t *testing.T, db pgxutil.DB, song uuid.UUID, player string, difficulty model.GameDifficulty, score int) model.Highscore {
+	highscore := model.Highscore{
+		Song:       song,
+		Player:     player,
+		Difficulty: difficulty,
+		Score:      score,
+		Date:       time.Now().UTC().Truncate(time.Second),
+	}
+	if _, err := db.Exec(context.TODO(), `INSERT INTO song_scores (song_id, player, difficulty, score, played_at)
+	SELECT id, $2, $3, $4, $5 FROM songs WHERE uuid = $1`, song, player, string(difficulty), score, highscore.Date); err != nil {
+		t.Fatalf("testdata.Highscore() could not insert into the database: %s", err)
+	}
+	return highscore
+}
@@ -0,0 +1,37 @@
+//go:build database
+
+package testdata
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// Session inserts a new open session with the specified name and request limit into the database and returns it.
+// The session gets a random join code.
+func Session(t *testing.T, db pgxutil.DB, name string, limit int) model.Session {
+	session := model.Session{
+		Name:         name,
+		Code:         strings.ToUpper(uuid.NewString()[:8]),
+		RequestLimit: limit,
+	}
+	row, err := pgxutil.InsertRowReturning(context.TODO(), db, "sessions", map[string]any{
+		"name":          session.Name,
+		"code":          session.Code,
+		"request_limit": session.RequestLimit,
+	}, "id, uuid, created_at, updated_at", pgx.RowToStructByName[creationResult])
+	if err != nil {
+		t.Fatalf("testdata.Session() could not insert into the database: %s", err)
+	}
+	session.UUID = row.UUID
+	session.CreatedAt = row.CreatedAt
+	session.UpdatedAt = row.UpdatedAt
+	return session
+}
@@ -36,7 +36,6 @@ func readSong(t *testing.T, name string) model.Song {
 func insertSong(db pgxutil.DB, song *model.Song, extra map[string]any) error {
 	values := map[string]any{
 		"title":    song.Title,
-		"artists":  song.Artists,
 		"language": song.Language,
 		"genre":    song.Genre,
 		"year":     song.Year,
@@ -52,6 +51,13 @@ func insertSong(db pgxutil.DB, song *model.Song, extra map[string]any) error {
 	if err != nil {
 		return err
 	}
+	_, err = db.Exec(context.TODO(), `INSERT INTO song_artists (song_id, artist_id, role, position)
+	SELECT $1, artist_id(name), 'main', position FROM UNNEST($2::TEXT[]) WITH ORDINALITY AS t(name, position)
+	WHERE name <> ''
+	ON CONFLICT DO NOTHING`, row.ID, song.Artists)
+	if err != nil {
+		return err
+	}
 	song.UUID = row.UUID
 	song.CreatedAt = row.CreatedAt
 	song.UpdatedAt = row.UpdatedAt
@@ -80,6 +86,19 @@ func NSongs(t *testing.T, db pgxutil.DB, n int) {
 	}
 }
 
+// DeletedSong inserts a single song into the database that has been moved to the trash a long time ago.
+// The inserted song is returned.
+func DeletedSong(t *testing.T, db pgxutil.DB) model.Song {
+	song := readSong(t, "simple-song.txt")
+	song.DeletedAt = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	if err := insertSong(db, &song, map[string]any{
+		"deleted_at": song.DeletedAt,
+	}); err != nil {
+		t.Fatalf("testdata.DeletedSong() could not insert into the database: %s", err)
+	}
+	return song
+}
+
 // SongWithUpload inserts an upload into the database that contains a single song.
 // That song is returned.
 //
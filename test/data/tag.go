@@ -0,0 +1,32 @@
+//go:build database
+
+package testdata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// Tag inserts a new tag with the specified name and color into the database and returns it.
+func Tag(t *testing.T, db pgxutil.DB, name string, color string) model.Tag {
+	tag := model.Tag{
+		Name:  name,
+		Color: color,
+	}
+	row, err := pgxutil.InsertRowReturning(context.TODO(), db, "tags", map[string]any{
+		"name":  tag.Name,
+		"color": tag.Color,
+	}, "id, uuid, created_at, updated_at", pgx.RowToStructByName[creationResult])
+	if err != nil {
+		t.Fatalf("testdata.Tag() could not insert into the database: %s", err)
+	}
+	tag.UUID = row.UUID
+	tag.CreatedAt = row.CreatedAt
+	tag.UpdatedAt = row.UpdatedAt
+	return tag
+}
@@ -153,6 +153,36 @@ func DoneUploadWithSongs(t *testing.T, db pgxutil.DB) model.Upload {
 	return upload
 }
 
+// DoneUploadWithMatch inserts a new upload in the done state into the database.
+// The upload contains a single song that matches a song in the library.
+// The upload, the uploaded song and the library song are returned.
+func DoneUploadWithMatch(t *testing.T, db pgxutil.DB) (model.Upload, model.Song, model.Song) {
+	library := SimpleSong(t, db)
+	upload := model.Upload{
+		State:          model.UploadStateDone,
+		SongsTotal:     1,
+		SongsProcessed: 1,
+	}
+	id, err := insertUpload(db, &upload, nil)
+	if err != nil {
+		t.Fatalf("testdata.DoneUploadWithMatch() could not insert upload into the database: %s", err)
+	}
+	song := readSong(t, "simple-song.txt")
+	song.Comment = "Corrected version"
+	if err = insertSong(db, &song, map[string]any{
+		"upload_id": id,
+		"comment":   song.Comment,
+	}); err != nil {
+		t.Fatalf("testdata.DoneUploadWithMatch() could not insert song into the database: %s", err)
+	}
+	song.InUpload = true
+	if _, err = db.Exec(context.TODO(), `UPDATE songs SET upload_match_id = (SELECT id FROM songs WHERE uuid = $2) WHERE uuid = $1`,
+		song.UUID, library.UUID); err != nil {
+		t.Fatalf("testdata.DoneUploadWithMatch() could not match the song: %s", err)
+	}
+	return upload, song, library
+}
+
 func DoneUploadWithFiles(t *testing.T, db pgxutil.DB) model.Upload {
 	upload := model.Upload{
 		State: model.UploadStateDone,
@@ -0,0 +1,38 @@
+//go:build database
+
+package testdata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// VariantGroup inserts a new variant group into the database and returns its UUID.
+// The song original is added as the original of the group, variants maps the other songs of the group to their relation.
+func VariantGroup(t *testing.T, db pgxutil.DB, original uuid.UUID, variants map[uuid.UUID]model.VariantRelation) uuid.UUID {
+	row, err := pgxutil.SelectRow(context.TODO(), db, `INSERT INTO song_groups DEFAULT VALUES RETURNING id, uuid`, nil, pgx.RowToStructByName[struct {
+		ID   int
+		UUID uuid.UUID
+	}])
+	if err != nil {
+		t.Fatalf("testdata.VariantGroup() could not insert into the database: %s", err)
+	}
+	songs := map[uuid.UUID]model.VariantRelation{original: model.VariantRelationOriginal}
+	for id, relation := range variants {
+		songs[id] = relation
+	}
+	for id, relation := range songs {
+		_, err = db.Exec(context.TODO(), `INSERT INTO song_variants (song_id, group_id, relation)
+		SELECT id, $2, $3 FROM songs WHERE uuid = $1`, id, row.ID, relation)
+		if err != nil {
+			t.Fatalf("testdata.VariantGroup() could not insert into the database: %s", err)
+		}
+	}
+	return row.UUID
+}
@@ -0,0 +1,35 @@
+//go:build database
+
+package testdata
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgxutil"
+
+	"github.com/Karaoke-Manager/karman/model"
+)
+
+// Webhook inserts a new webhook into the database and returns it.
+// The webhook accepts all events.
+func Webhook(t *testing.T, db pgxutil.DB) model.Webhook {
+	hook := model.Webhook{
+		URL:    "https://example.com/hook",
+		Secret: "secret",
+		Events: []string{},
+	}
+	row, err := pgxutil.InsertRowReturning(context.TODO(), db, "webhooks", map[string]any{
+		"url":    hook.URL,
+		"secret": hook.Secret,
+		"events": hook.Events,
+	}, "id, uuid, created_at, updated_at", pgx.RowToStructByName[creationResult])
+	if err != nil {
+		t.Fatalf("testdata.Webhook() could not insert into the database: %s", err)
+	}
+	hook.UUID = row.UUID
+	hook.CreatedAt = row.CreatedAt
+	hook.UpdatedAt = row.UpdatedAt
+	return hook
+}